@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/mm"
@@ -107,7 +108,16 @@ type CoreConfig struct {
 	NoAutoDBBackup     bool `long:"no-db-backup" description:"Disable creation of a database backup on shutdown."`
 	UnlockCoinsOnLogin bool `long:"release-wallet-coins" description:"On login or wallet creation, instruct the wallet to release any coins that it may have locked."`
 
+	DBSnapshotInterval  time.Duration `long:"db-snapshot-interval" description:"How often to save a checksummed snapshot of the database, e.g. 6h. Snapshots are disabled by default."`
+	DBSnapshotsRetained int           `long:"db-snapshots" description:"The number of database snapshots to keep. Default is 5."`
+	VerifyDBOnStart     bool          `long:"verify-db" description:"Check the database for corruption on startup."`
+
 	ExtensionModeFile string `long:"extension-mode-file" description:"path to a file that specifies options for running core as an extension."`
+
+	Amnesia bool `long:"amnesia" description:"Keep all data in memory and never write the database or logs to disk. Logs are written to stdout. Only view-only operation on testnet or simnet is permitted. Everything, including the app seed, is lost on shutdown."`
+
+	Webhooks      []string `long:"webhook" description:"An http or https URL to POST JSON events to, e.g. filled orders, redeemed and failed swaps, and server penalties. May be specified multiple times."`
+	WebhookSecret string   `long:"webhooksecret" description:"The secret key used to sign webhook requests with HMAC-SHA256. The hex-encoded signature is in the X-Dcrdex-Signature header. Required with webhook."`
 }
 
 // WebConfig encapsulates the configuration needed for the web server.
@@ -204,18 +214,24 @@ func (cfg *Config) Web(c *core.Core, mm *mm.MarketMaker, log dex.Logger, utc boo
 // by both core and rpcserver.
 func (cfg *Config) Core(log dex.Logger) *core.Config {
 	return &core.Config{
-		DBPath:             cfg.DBPath,
-		Net:                cfg.Net,
-		Logger:             log,
-		Onion:              cfg.Onion,
-		TorProxy:           cfg.TorProxy,
-		TorIsolation:       cfg.TorIsolation,
-		Language:           cfg.Language,
-		UnlockCoinsOnLogin: cfg.UnlockCoinsOnLogin,
-		NoAutoWalletLock:   cfg.NoAutoWalletLock,
-		NoAutoDBBackup:     cfg.NoAutoDBBackup,
-		ExtensionModeFile:  cfg.ExtensionModeFile,
-		TheOneHost:         cfg.TheOneHost,
+		DBPath:              cfg.DBPath,
+		Net:                 cfg.Net,
+		Logger:              log,
+		Onion:               cfg.Onion,
+		TorProxy:            cfg.TorProxy,
+		TorIsolation:        cfg.TorIsolation,
+		Language:            cfg.Language,
+		UnlockCoinsOnLogin:  cfg.UnlockCoinsOnLogin,
+		NoAutoWalletLock:    cfg.NoAutoWalletLock,
+		NoAutoDBBackup:      cfg.NoAutoDBBackup,
+		DBSnapshotInterval:  cfg.DBSnapshotInterval,
+		DBSnapshotsRetained: cfg.DBSnapshotsRetained,
+		VerifyDBOnStart:     cfg.VerifyDBOnStart,
+		ExtensionModeFile:   cfg.ExtensionModeFile,
+		Amnesia:             cfg.Amnesia,
+		Webhooks:            cfg.Webhooks,
+		WebhookSecret:       cfg.WebhookSecret,
+		TheOneHost:          cfg.TheOneHost,
 	}
 }
 
@@ -337,7 +353,13 @@ func ResolveConfig(appData string, cfg *Config) error {
 		cfg.DBPath = defaultDBPath
 	}
 
-	if cfg.LogPath == "" {
+	// Nothing is written to disk in amnesia mode, so logs are only written to
+	// stdout.
+	if cfg.Amnesia {
+		if cfg.LogPath != "" {
+			return fmt.Errorf("logpath cannot be used with amnesia")
+		}
+	} else if cfg.LogPath == "" {
 		cfg.LogPath = defaultLogPath
 	}
 
@@ -40,9 +40,19 @@ func (w logWriter) Write(p []byte) (n int, err error) {
 }
 
 // initLogging initializes the logging rotater to write logs to logFile and
-// create roll files in the same directory. initLogging must be called before
-// the package-global log rotator variables are used.
+// create roll files in the same directory. If logFile is empty, logs are only
+// written to stdout. initLogging must be called before the package-global log
+// rotator variables are used.
 func InitLogging(logFilename, lvl string, stdout bool, utc bool) (lm *dex.LoggerMaker, closeFn func()) {
+	if logFilename == "" {
+		lm, err := dex.NewLoggerMaker(os.Stdout, lvl, utc)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create custom logger: %v\n", err)
+			os.Exit(1)
+		}
+		lm.SetLevelsFromMap(defaultLogLevelMap)
+		return lm, func() {}
+	}
 	logDirectory := filepath.Dir(logFilename)
 	err := os.MkdirAll(logDirectory, 0700)
 	if err != nil {
@@ -184,7 +184,7 @@ func (d *Driver) Create(params *asset.CreateWalletParams) error {
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, false)
+	return dexbtc.MinLotSizeForDust(&dexbch.DustPolicy, maxFeeRate, false)
 }
 
 // NewWallet is the exported constructor by which the DEX will import the
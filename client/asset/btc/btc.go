@@ -184,7 +184,7 @@ var (
 		Tab:               "External",
 		Description:       "Connect to bitcoind",
 		DefaultConfigPath: dexbtc.SystemConfigPath("bitcoin"),
-		ConfigOpts:        append(RPCConfigOpts("Bitcoin", "8332"), CommonConfigOpts("BTC", false)...),
+		ConfigOpts:        append(append(RPCConfigOpts("Bitcoin", "8332"), CommonConfigOpts("BTC", false)...), ExternalSignerConfigOpts...),
 		MultiFundingOpts:  MultiFundingOpts,
 	}
 	spvWalletDefinition = &asset.WalletDefinition{
@@ -366,6 +366,10 @@ type BTCCloneCFG struct {
 	AddressStringer dexbtc.AddressStringer // btcutil.Address => string, may be an override or just the String method
 	// BlockDeserializer can be used in place of (*wire.MsgBlock).Deserialize.
 	BlockDeserializer func([]byte) (*wire.MsgBlock, error)
+	// BlockPowHasher computes the proof-of-work hash of a block header. The
+	// Electrum wallet uses it to check the work of the headers provided by its
+	// server. If nil, only the linkage of the headers is checked.
+	BlockPowHasher func(*wire.BlockHeader) chainhash.Hash
 	// ArglessChangeAddrRPC can be true if the getrawchangeaddress takes no
 	// address-type argument.
 	ArglessChangeAddrRPC bool
@@ -439,8 +443,9 @@ type RPCConfig struct {
 // RPCWalletConfig is a combination of RPCConfig and WalletConfig. Used for a
 // wallet based on a bitcoind-like RPC API.
 type RPCWalletConfig struct {
-	RPCConfig    `ini:",extends"`
-	WalletConfig `ini:",extends"`
+	RPCConfig            `ini:",extends"`
+	WalletConfig         `ini:",extends"`
+	ExternalSignerConfig `ini:",extends"`
 }
 
 // WalletConfig are wallet-level configuration settings.
@@ -1093,6 +1098,7 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, net dex.Network) (ass
 		// FeeEstimator must default to rpcFeeRate if not set, but set a
 		// specific external estimator:
 		ExternalFeeEstimator: externalFeeRate,
+		BlockPowHasher:       (*wire.BlockHeader).BlockHash,
 		AssetID:              BipID,
 	}
 
@@ -1212,7 +1218,38 @@ func newRPCWallet(requester RawRequester, cfg *BTCCloneCFG, parsedCfg *RPCWallet
 	}
 	core.requesterV.Store(requester)
 	node := newRPCClient(core)
-	btc.setNode(node)
+	signerCfg := &parsedCfg.ExternalSignerConfig
+	switch {
+	case !signerCfg.enabled():
+		btc.setNode(node)
+	case !cfg.Segwit:
+		// The external signer only signs segwit inputs.
+		return nil, fmt.Errorf("an external signer is not supported for %s", cfg.Symbol)
+	case signerCfg.SignerURL != "" && signerCfg.HWIPath != "":
+		return nil, errors.New("only one of signerurl and hwipath may be set")
+	case signerCfg.HWIPath != "":
+		hwi, err := newHWISigner(signerCfg, cfg.Network, cfg.Logger.SubLogger("HWI"))
+		if err != nil {
+			return nil, err
+		}
+		btc.setNode(&hwiWallet{
+			signerWallet: &signerWallet{
+				rpcClient: node,
+				signerCfg: *signerCfg,
+			},
+			hwi: hwi,
+		})
+	default:
+		signer, err := newExternalSigner(signerCfg)
+		if err != nil {
+			return nil, err
+		}
+		btc.setNode(&signerWallet{
+			rpcClient: node,
+			signerCfg: *signerCfg,
+			signer:    signer,
+		})
+	}
 	w := &intermediaryWallet{
 		baseWallet:     btc,
 		txFeeEstimator: node,
@@ -4071,6 +4108,13 @@ func (btc *baseWallet) SignMessage(coin asset.Coin, msg dex.Bytes) (pubkeys, sig
 	if utxo == nil {
 		return nil, nil, fmt.Errorf("no utxo found for %s", op)
 	}
+	if signer, is := btc.node.(inputSigner); is {
+		sig, pk, err := signer.signMessage(utxo.Address, msg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []dex.Bytes{pk}, []dex.Bytes{sig}, nil
+	}
 	privKey, err := btc.node.privKeyForAddress(utxo.Address)
 	if err != nil {
 		return nil, nil, err
@@ -4130,23 +4174,56 @@ func (btc *baseWallet) AuditContract(coinID, contract, txData dex.Bytes, rebroad
 		txOut = tx.TxOut[vout]
 	}
 
+	if err := btc.checkContractOutput(txOut.PkScript, contract); err != nil {
+		return nil, err
+	}
+
+	// Broadcast the transaction, but do not block because this is not required
+	// and does not affect the audit result.
+	if rebroadcast && tx != nil {
+		go func() {
+			if hashSent, err := btc.node.sendRawTransaction(tx); err != nil {
+				btc.log.Debugf("Rebroadcasting counterparty contract %v (THIS MAY BE NORMAL): %v", txHash, err)
+			} else if !hashSent.IsEqual(txHash) {
+				btc.log.Errorf("Counterparty contract %v was rebroadcast as %v!", txHash, hashSent)
+			}
+		}()
+	}
+
+	addrStr, err := btc.stringAddr(receiver, btc.chainParams)
+	if err != nil {
+		btc.log.Errorf("Failed to stringify receiver address %v (default): %v", receiver, err)
+		addrStr = receiver.String() // potentially misleading AuditInfo.Recipient
+	}
+
+	return &asset.AuditInfo{
+		Coin:       NewOutput(txHash, vout, uint64(txOut.Value)),
+		Recipient:  addrStr,
+		Contract:   contract,
+		SecretHash: secretHash,
+		Expiration: time.Unix(int64(stamp), 0).UTC(),
+	}, nil
+}
+
+// checkContractOutput checks that the pubkey script pays to the swap contract.
+func (btc *baseWallet) checkContractOutput(pkScript, contract []byte) error {
 	// Check for standard P2SH. NOTE: btc.scriptHashScript(contract) should
 	// equal txOut.PkScript. All we really get from the TxOut is the *value*.
-	scriptClass, addrs, numReq, err := txscript.ExtractPkScriptAddrs(txOut.PkScript, btc.chainParams)
+	scriptClass, addrs, numReq, err := txscript.ExtractPkScriptAddrs(pkScript, btc.chainParams)
 	if err != nil {
-		return nil, fmt.Errorf("error extracting script addresses from '%x': %w", txOut.PkScript, err)
+		return fmt.Errorf("error extracting script addresses from '%x': %w", pkScript, err)
 	}
 	var contractHash []byte
 	if btc.segwit {
 		if scriptClass != txscript.WitnessV0ScriptHashTy {
-			return nil, fmt.Errorf("unexpected script class. expected %s, got %s",
+			return fmt.Errorf("unexpected script class. expected %s, got %s",
 				txscript.WitnessV0ScriptHashTy, scriptClass)
 		}
 		h := sha256.Sum256(contract)
 		contractHash = h[:]
 	} else {
 		if scriptClass != txscript.ScriptHashTy {
-			return nil, fmt.Errorf("unexpected script class. expected %s, got %s",
+			return fmt.Errorf("unexpected script class. expected %s, got %s",
 				txscript.ScriptHashTy, scriptClass)
 		}
 		// Compare the contract hash to the P2SH address.
@@ -4154,43 +4231,18 @@ func (btc *baseWallet) AuditContract(coinID, contract, txData dex.Bytes, rebroad
 	}
 	// These last two checks are probably overkill.
 	if numReq != 1 {
-		return nil, fmt.Errorf("unexpected number of signatures expected for P2SH script: %d", numReq)
+		return fmt.Errorf("unexpected number of signatures expected for P2SH script: %d", numReq)
 	}
 	if len(addrs) != 1 {
-		return nil, fmt.Errorf("unexpected number of addresses for P2SH script: %d", len(addrs))
+		return fmt.Errorf("unexpected number of addresses for P2SH script: %d", len(addrs))
 	}
 
 	addr := addrs[0]
 	if !bytes.Equal(contractHash, addr.ScriptAddress()) {
-		return nil, fmt.Errorf("contract hash doesn't match script address. %x != %x",
+		return fmt.Errorf("contract hash doesn't match script address. %x != %x",
 			contractHash, addr.ScriptAddress())
 	}
-
-	// Broadcast the transaction, but do not block because this is not required
-	// and does not affect the audit result.
-	if rebroadcast && tx != nil {
-		go func() {
-			if hashSent, err := btc.node.sendRawTransaction(tx); err != nil {
-				btc.log.Debugf("Rebroadcasting counterparty contract %v (THIS MAY BE NORMAL): %v", txHash, err)
-			} else if !hashSent.IsEqual(txHash) {
-				btc.log.Errorf("Counterparty contract %v was rebroadcast as %v!", txHash, hashSent)
-			}
-		}()
-	}
-
-	addrStr, err := btc.stringAddr(receiver, btc.chainParams)
-	if err != nil {
-		btc.log.Errorf("Failed to stringify receiver address %v (default): %v", receiver, err)
-		addrStr = receiver.String() // potentially misleading AuditInfo.Recipient
-	}
-
-	return &asset.AuditInfo{
-		Coin:       NewOutput(txHash, vout, uint64(txOut.Value)),
-		Recipient:  addrStr,
-		Contract:   contract,
-		SecretHash: secretHash,
-		Expiration: time.Unix(int64(stamp), 0).UTC(),
-	}, nil
+	return nil
 }
 
 // LockTimeExpired returns true if the specified locktime has expired, making it
@@ -4381,6 +4433,15 @@ func (btc *baseWallet) DepositAddress() (string, error) {
 		return addrStr, nil
 	}
 
+	// The keys for a watching-only wallet are held by the signer, which must
+	// have the key for the address.
+	if signer, is := btc.node.(inputSigner); is {
+		if _, err := signer.pubKey(addrStr); err != nil {
+			return "", fmt.Errorf("signer does not have the key for address %v: %w", addrStr, err)
+		}
+		return addrStr, nil
+	}
+
 	// If the wallet is unlocked, be extra cautious and ensure the wallet gave
 	// us an address for which we can retrieve the private keys, regardless of
 	// what ownsAddress would say.
@@ -4904,6 +4965,10 @@ func (btc *baseWallet) createSig(tx *wire.MsgTx, idx int, pkScript []byte, addr
 		return nil, nil, err
 	}
 
+	if _, is := btc.node.(inputSigner); is {
+		return nil, nil, errors.New("the external signer does not sign non-segwit inputs")
+	}
+
 	privKey, err := btc.node.privKeyForAddress(addrStr)
 	if err != nil {
 		return nil, nil, err
@@ -4926,6 +4991,9 @@ func (btc *baseWallet) createWitnessSig(tx *wire.MsgTx, idx int, pkScript []byte
 	if err != nil {
 		return nil, nil, err
 	}
+	if signer, is := btc.node.(inputSigner); is {
+		return signer.signWitnessInput(tx, idx, pkScript, val, addrStr)
+	}
 	privKey, err := btc.node.privKeyForAddress(addrStr)
 	if err != nil {
 		return nil, nil, err
@@ -25,6 +25,7 @@ import (
 	"decred.org/dcrdex/dex/calc"
 	"decred.org/dcrdex/dex/encode"
 	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/btcjson"
@@ -3466,6 +3467,7 @@ func testAuditContract(t *testing.T, segwit bool, walletType string) {
 	if err == nil {
 		t.Fatalf("no error for wrong contract")
 	}
+
 }
 
 type tReceipt struct {
@@ -6513,3 +6515,98 @@ func TestFeeRateCache(t *testing.T) {
 		t.Fatal("counter not incremented for recovered rate")
 	}
 }
+
+func TestMerkleRootFromBranch(t *testing.T) {
+	hashPair := func(a, b chainhash.Hash) chainhash.Hash {
+		return chainhash.DoubleHashH(append(a[:], b[:]...))
+	}
+	// A block with three transactions. The last is paired with itself.
+	a, b, c := chainhash.Hash{0x0a}, chainhash.Hash{0x0b}, chainhash.Hash{0x0c}
+	ab, cc := hashPair(a, b), hashPair(c, c)
+	root := hashPair(ab, cc)
+
+	tests := []struct {
+		name    string
+		txHash  chainhash.Hash
+		branch  []chainhash.Hash
+		pos     uint32
+		wantErr bool
+		match   bool
+	}{
+		{name: "first", txHash: a, branch: []chainhash.Hash{b, cc}, pos: 0, match: true},
+		{name: "second", txHash: b, branch: []chainhash.Hash{a, cc}, pos: 1, match: true},
+		{name: "last", txHash: c, branch: []chainhash.Hash{c, ab}, pos: 2, match: true},
+		{name: "wrong pos", txHash: b, branch: []chainhash.Hash{a, cc}, pos: 0},
+		{name: "wrong tx", txHash: chainhash.Hash{0x0d}, branch: []chainhash.Hash{c, ab}, pos: 2},
+		{name: "pos out of range", txHash: c, branch: []chainhash.Hash{c, ab}, pos: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		r, err := merkleRootFromBranch(&tt.txHash, tt.branch, tt.pos)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wantErr = %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if (*r == root) != tt.match {
+			t.Fatalf("%s: wanted match = %t, got root %s", tt.name, tt.match, r)
+		}
+	}
+}
+
+func TestCheckHeaderChain(t *testing.T) {
+	powLimit := chaincfg.RegressionNetParams.PowLimit
+	bits := chaincfg.RegressionNetParams.PowLimitBits
+	powHash := (*wire.BlockHeader).BlockHash
+	meetsTarget := func(hdr *wire.BlockHeader) bool {
+		h := hdr.BlockHash()
+		return blockchain.HashToBig(&h).Cmp(blockchain.CompactToBig(hdr.Bits)) <= 0
+	}
+	// mine finds a nonce for which the header does or does not meet its
+	// target.
+	mine := func(hdr *wire.BlockHeader, valid bool) {
+		for hdr.Nonce = 0; meetsTarget(hdr) != valid; hdr.Nonce++ {
+		}
+	}
+	newChain := func() []*wire.BlockHeader {
+		hdrs := make([]*wire.BlockHeader, 3)
+		var prev chainhash.Hash
+		for i := range hdrs {
+			hdrs[i] = &wire.BlockHeader{PrevBlock: prev, Bits: bits, Timestamp: time.Unix(int64(i), 0)}
+			mine(hdrs[i], true)
+			prev = hdrs[i].BlockHash()
+		}
+		return hdrs
+	}
+
+	if err := checkHeaderChain(newChain(), powHash, powLimit); err != nil {
+		t.Fatalf("valid chain rejected: %v", err)
+	}
+
+	// A header that does not build on the previous one.
+	hdrs := newChain()
+	hdrs[2].PrevBlock = hdrs[0].BlockHash()
+	mine(hdrs[2], true)
+	if err := checkHeaderChain(hdrs, powHash, powLimit); err == nil {
+		t.Fatalf("no error for broken chain")
+	}
+	// Without a proof-of-work hasher, the linkage is still checked.
+	if err := checkHeaderChain(hdrs, nil, powLimit); err == nil {
+		t.Fatalf("no error for broken chain without a pow hasher")
+	}
+
+	// The last header does not meet its target.
+	hdrs = newChain()
+	mine(hdrs[2], false)
+	if err := checkHeaderChain(hdrs, powHash, powLimit); err == nil {
+		t.Fatalf("no error for insufficient work")
+	}
+	if err := checkHeaderChain(hdrs, nil, powLimit); err != nil {
+		t.Fatalf("work checked without a pow hasher: %v", err)
+	}
+
+	// The target is above the pow limit.
+	if err := checkHeaderChain(newChain(), powHash, chaincfg.MainNetParams.PowLimit); err == nil {
+		t.Fatalf("no error for target above the pow limit")
+	}
+}
@@ -66,6 +66,7 @@ func ElectrumWallet(cfg *BTCCloneCFG) (*ExchangeWalletElectrum, error) {
 		addrStringer: cfg.AddressStringer,
 		segwit:       cfg.Segwit,
 		rpcCfg:       rpcCfg,
+		powHash:      cfg.BlockPowHasher,
 	})
 	btc.setNode(ew)
 
@@ -604,6 +604,28 @@ func (sc *ServerConn) GetTransaction(ctx context.Context, txid string) (*GetTran
 	return &resp, nil
 }
 
+// GetMerkleResult is the merkle branch proving the inclusion of a transaction
+// in a block.
+type GetMerkleResult struct {
+	BlockHeight int64 `json:"block_height"`
+	// Merkle is the branch of hashes, in the order they are hashed with the
+	// transaction hash, from the bottom of the tree up.
+	Merkle []string `json:"merkle"`
+	// Pos is the transaction's index in the block.
+	Pos uint32 `json:"pos"`
+}
+
+// GetMerkle requests the merkle branch of a confirmed transaction mined in the
+// block at the given height.
+func (sc *ServerConn) GetMerkle(ctx context.Context, txid string, height int64) (*GetMerkleResult, error) {
+	var resp GetMerkleResult
+	err := sc.Request(ctx, "blockchain.transaction.get_merkle", positional{txid, height}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // BlockHeader requests the block header at the given height, returning
 // hexadecimal encoded serialized header.
 func (sc *ServerConn) BlockHeader(ctx context.Context, height uint32) (string, error) {
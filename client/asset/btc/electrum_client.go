@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"net"
 	"sort"
@@ -26,6 +27,7 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/config"
 	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/psbt"
@@ -66,6 +68,7 @@ type electrumNetworkClient interface {
 	Shutdown()
 	Features(ctx context.Context) (*electrum.ServerFeatures, error)
 	GetTransaction(ctx context.Context, txid string) (*electrum.GetTransactionResult, error)
+	GetMerkle(ctx context.Context, txid string, height int64) (*electrum.GetMerkleResult, error)
 	BlockHeader(ctx context.Context, height uint32) (string, error)
 	BlockHeaders(ctx context.Context, startHeight, count uint32) (*electrum.GetBlockHeadersResult, error)
 }
@@ -79,6 +82,7 @@ type electrumWallet struct {
 	wallet      electrumWalletClient
 	chainV      atomic.Value // electrumNetworkClient
 	segwit      bool
+	powHash     func(*wire.BlockHeader) chainhash.Hash
 
 	// ctx is set on connect, and used in asset.Wallet and btc.Wallet interface
 	// method implementations that have no ctx arg yet (refactoring TODO).
@@ -108,6 +112,9 @@ type electrumWalletConfig struct {
 	addrStringer dexbtc.AddressStringer
 	segwit       bool // indicates if segwit addresses are expected from requests
 	rpcCfg       *RPCConfig
+	// powHash computes a header's proof-of-work hash. If nil, the proof of
+	// work of the server's headers is not checked.
+	powHash func(*wire.BlockHeader) chainhash.Hash
 }
 
 func newElectrumWallet(ew electrumWalletClient, cfg *electrumWalletConfig) *electrumWallet {
@@ -130,6 +137,7 @@ func newElectrumWallet(ew electrumWalletClient, cfg *electrumWalletConfig) *elec
 		stringAddr:  addrStringer,
 		wallet:      ew,
 		segwit:      cfg.segwit,
+		powHash:     cfg.powHash,
 		// TODO: remove this when all interface methods are given a Context. In
 		// the meantime, init with a valid sentry context until connect().
 		ctx: context.TODO(),
@@ -440,7 +448,8 @@ func (ew *electrumWallet) getTxOutput(ctx context.Context, txHash *chainhash.Has
 	// then fall back to the more expensive server request.
 	txid := txHash.String()
 	txRaw, confs, err := ew.checkWalletTx(txid)
-	if err != nil {
+	fromServer := err != nil
+	if fromServer {
 		txRes, err := ew.chain().GetTransaction(ctx, txid)
 		if err != nil {
 			return nil, 0, err
@@ -450,12 +459,23 @@ func (ew *electrumWallet) getTxOutput(ctx context.Context, txHash *chainhash.Has
 		if err != nil {
 			return nil, 0, err
 		}
+		// The server is not trusted with transactions that are not ours, e.g.
+		// the counterparty's swap contract, so check that the transaction is
+		// actually mined.
+		if confs > 0 {
+			if err = ew.verifyTxInclusion(ctx, txHash, confs); err != nil {
+				return nil, 0, fmt.Errorf("unable to verify the inclusion of tx %s in a block: %w", txid, err)
+			}
+		}
 	}
 
 	msgTx, err := msgTxFromBytes(txRaw)
 	if err != nil {
 		return nil, 0, err
 	}
+	if fromServer && msgTx.TxHash() != *txHash {
+		return nil, 0, fmt.Errorf("requested tx %s, got tx %s", txid, msgTx.TxHash())
+	}
 	if vout >= uint32(len(msgTx.TxOut)) {
 		return nil, 0, fmt.Errorf("output %d of tx %v does not exists", vout, txid)
 	}
@@ -475,6 +495,113 @@ func (ew *electrumWallet) getTxOutput(ctx context.Context, txHash *chainhash.Has
 	return wire.NewTxOut(amt, pkScript), confs, nil
 }
 
+// maxProofHeaders is the most block headers checked when verifying that a
+// transaction is mined. Swaps never require more confirmations than this.
+const maxProofHeaders = 144
+
+// verifyTxInclusion checks the server's merkle proof that the transaction is
+// mined in the block that the server reports at the height implied by confs.
+// The block's header and the headers that follow it, up to the tip or
+// maxProofHeaders, must form a chain, and each must meet its proof-of-work
+// target. This is SPV-style verification, so a server would have to mine
+// blocks to misreport a transaction as mined, but difficulty adjustments are
+// not checked.
+func (ew *electrumWallet) verifyTxInclusion(ctx context.Context, txHash *chainhash.Hash, confs uint32) error {
+	tip, err := ew.getBestBlockHeight()
+	if err != nil {
+		return err
+	}
+	height := int64(tip) - int64(confs) + 1
+	if height < 0 {
+		return fmt.Errorf("%d confirmations reported with best block %d", confs, tip)
+	}
+	proof, err := ew.chain().GetMerkle(ctx, txHash.String(), height)
+	if err != nil {
+		return fmt.Errorf("error getting merkle proof: %w", err)
+	}
+	branch := make([]chainhash.Hash, 0, len(proof.Merkle))
+	for _, s := range proof.Merkle {
+		h, err := chainhash.NewHashFromStr(s)
+		if err != nil {
+			return fmt.Errorf("invalid merkle branch hash %q: %w", s, err)
+		}
+		branch = append(branch, *h)
+	}
+	root, err := merkleRootFromBranch(txHash, branch, proof.Pos)
+	if err != nil {
+		return err
+	}
+	// A block may have been mined since the tip was requested, in which case
+	// the server reports the proof for the actual height.
+	count := min(max(int64(tip)-proof.BlockHeight+1, 1), maxProofHeaders)
+	hdrsRes, err := ew.chain().BlockHeaders(ctx, uint32(proof.BlockHeight), uint32(count))
+	if err != nil {
+		return fmt.Errorf("error getting block headers from height %d: %w", proof.BlockHeight, err)
+	}
+	if int64(hdrsRes.Count) < count {
+		return fmt.Errorf("requested %d block headers from height %d, got %d", count, proof.BlockHeight, hdrsRes.Count)
+	}
+	hdrReader := hex.NewDecoder(strings.NewReader(hdrsRes.HexConcat))
+	hdrs := make([]*wire.BlockHeader, count)
+	for i := range hdrs {
+		hdrs[i] = &wire.BlockHeader{}
+		if err = hdrs[i].Deserialize(hdrReader); err != nil {
+			return fmt.Errorf("error decoding block header at height %d: %w", proof.BlockHeight+int64(i), err)
+		}
+	}
+	if hdrs[0].MerkleRoot != *root {
+		return fmt.Errorf("merkle root mismatch for block %d: header has %s, proof has %s",
+			proof.BlockHeight, hdrs[0].MerkleRoot, root)
+	}
+	return checkHeaderChain(hdrs, ew.powHash, ew.chainParams.PowLimit)
+}
+
+// checkHeaderChain checks that each header links to the one before it. If
+// powHash is not nil, each header's proof-of-work hash must also meet the
+// target encoded in its bits, and the target may not exceed powLimit, if set.
+func checkHeaderChain(hdrs []*wire.BlockHeader, powHash func(*wire.BlockHeader) chainhash.Hash, powLimit *big.Int) error {
+	for i, hdr := range hdrs {
+		if i > 0 && hdr.PrevBlock != hdrs[i-1].BlockHash() {
+			return fmt.Errorf("block %s does not build on block %s", hdr.BlockHash(), hdrs[i-1].BlockHash())
+		}
+		if powHash == nil {
+			continue
+		}
+		target := blockchain.CompactToBig(hdr.Bits)
+		if target.Sign() <= 0 || (powLimit != nil && target.Cmp(powLimit) > 0) {
+			return fmt.Errorf("block %s has an invalid target %08x", hdr.BlockHash(), hdr.Bits)
+		}
+		h := powHash(hdr)
+		if blockchain.HashToBig(&h).Cmp(target) > 0 {
+			return fmt.Errorf("block %s does not meet its proof-of-work target %08x", hdr.BlockHash(), hdr.Bits)
+		}
+	}
+	return nil
+}
+
+// merkleRootFromBranch computes the merkle root of a block from the hash of a
+// transaction, its index in the block, and the merkle branch of sibling hashes
+// from the bottom of the tree up.
+func merkleRootFromBranch(txHash *chainhash.Hash, branch []chainhash.Hash, pos uint32) (*chainhash.Hash, error) {
+	if pos>>len(branch) != 0 {
+		return nil, fmt.Errorf("tx position %d out of range for merkle branch of length %d", pos, len(branch))
+	}
+	h := *txHash
+	var buf [chainhash.HashSize * 2]byte
+	for _, sibling := range branch {
+		if pos&1 == 0 {
+			copy(buf[:chainhash.HashSize], h[:])
+			copy(buf[chainhash.HashSize:], sibling[:])
+		} else {
+			copy(buf[:chainhash.HashSize], sibling[:])
+			copy(buf[chainhash.HashSize:], h[:])
+		}
+		h = chainhash.DoubleHashH(buf[:])
+		pos >>= 1
+	}
+	return &h, nil
+}
+
 func (ew *electrumWallet) getBlockHeaderByHeight(ctx context.Context, height int64) (*wire.BlockHeader, error) {
 	hdrStr, err := ew.chain().BlockHeader(ctx, uint32(height))
 	if err != nil {
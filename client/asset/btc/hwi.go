@@ -0,0 +1,193 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"decred.org/dcrdex/dex"
+	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const methodWalletProcessPSBT = "walletprocesspsbt"
+
+// errHWISigner is returned for signing requests that a hardware wallet cannot
+// fulfill. HWI signs PSBTs for the device's own scripts, but will not sign a
+// swap contract input or an arbitrary message hash.
+var errHWISigner = errors.New("a hardware wallet can only sign transactions spending the wallet's own outputs")
+
+// hwiSigner signs PSBTs with a hardware wallet using the HWI command line tool.
+// See https://github.com/bitcoin-core/HWI.
+type hwiSigner struct {
+	fingerprint string
+	chain       string
+	log         dex.Logger
+	run         func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+func newHWISigner(cfg *ExternalSignerConfig, net dex.Network, log dex.Logger) (*hwiSigner, error) {
+	if cfg.HWIFingerprint == "" {
+		return nil, errors.New("the fingerprint of the hardware wallet is required")
+	}
+	if fp, err := hex.DecodeString(cfg.HWIFingerprint); err != nil || len(fp) != 4 {
+		return nil, fmt.Errorf("invalid hardware wallet fingerprint %q", cfg.HWIFingerprint)
+	}
+	var chain string
+	switch net {
+	case dex.Mainnet:
+		chain = "main"
+	case dex.Testnet:
+		chain = "test"
+	case dex.Regtest:
+		chain = "regtest"
+	default:
+		return nil, fmt.Errorf("unknown network %v", net)
+	}
+	hwiPath := cfg.HWIPath
+	return &hwiSigner{
+		fingerprint: strings.ToLower(cfg.HWIFingerprint),
+		chain:       chain,
+		log:         log,
+		run: func(ctx context.Context, args ...string) ([]byte, error) {
+			return exec.CommandContext(ctx, hwiPath, args...).Output()
+		},
+	}, nil
+}
+
+type hwiSignTxResult struct {
+	PSBT   string `json:"psbt"`
+	Signed bool   `json:"signed"`
+	Error  string `json:"error"`
+	Code   int    `json:"code"`
+}
+
+// signPSBT has the device sign the base64-encoded PSBT. The user must confirm
+// the transaction on the device.
+func (s *hwiSigner) signPSBT(b64 string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), signerTimeout)
+	defer cancel()
+	s.log.Infof("Confirm the transaction on hardware wallet %s", s.fingerprint)
+	out, runErr := s.run(ctx, "--fingerprint", s.fingerprint, "--chain", s.chain, "signtx", b64)
+	// HWI reports errors as JSON on stdout, and may also exit with an error.
+	var res hwiSignTxResult
+	if err := json.Unmarshal(out, &res); err != nil {
+		if runErr != nil {
+			return "", fmt.Errorf("hwi signtx error: %w", runErr)
+		}
+		return "", fmt.Errorf("error decoding hwi signtx output: %w", err)
+	}
+	if res.Error != "" {
+		return "", fmt.Errorf("hwi signtx error %d: %s", res.Code, res.Error)
+	}
+	if runErr != nil {
+		return "", fmt.Errorf("hwi signtx error: %w", runErr)
+	}
+	if !res.Signed {
+		return "", errors.New("hardware wallet did not sign the transaction")
+	}
+	return res.PSBT, nil
+}
+
+type walletProcessPSBTResult struct {
+	PSBT     string `json:"psbt"`
+	Complete bool   `json:"complete"`
+}
+
+// hwiWallet is a signerWallet for a watching-only wallet whose keys are held
+// by a hardware wallet. The watching-only wallet must have been created from
+// the device's descriptors, e.g. with hwi getdescriptors, so that the wallet
+// can add the key origins that the device needs to each PSBT.
+type hwiWallet struct {
+	*signerWallet
+	hwi *hwiSigner
+}
+
+var _ Wallet = (*hwiWallet)(nil)
+var _ inputSigner = (*hwiWallet)(nil)
+
+// signTx has the hardware wallet sign the transaction's inputs. The wallet
+// fills in the PSBT's inputs, the device signs them, and the inputs are
+// finalized here.
+func (w *hwiWallet) signTx(inTx *wire.MsgTx) (*wire.MsgTx, error) {
+	packet, err := psbt.NewFromUnsignedTx(inTx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating PSBT: %w", err)
+	}
+	b64, err := packet.B64Encode()
+	if err != nil {
+		return nil, fmt.Errorf("error encoding PSBT: %w", err)
+	}
+	var res walletProcessPSBTResult
+	if err = w.call(methodWalletProcessPSBT, anylist{b64, false, "ALL", true}, &res); err != nil {
+		return nil, fmt.Errorf("walletprocesspsbt error: %w", err)
+	}
+	signed, err := w.hwi.signPSBT(res.PSBT)
+	if err != nil {
+		return nil, err
+	}
+	packet, err = psbt.NewFromRawBytes(strings.NewReader(signed), true)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding signed PSBT: %w", err)
+	}
+	if err = psbt.MaybeFinalizeAll(packet); err != nil {
+		return nil, fmt.Errorf("error finalizing PSBT: %w", err)
+	}
+	outTx, err := psbt.Extract(packet)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting signed transaction: %w", err)
+	}
+	if outTx.TxHash() != inTx.TxHash() {
+		return nil, errors.New("hardware wallet modified the transaction")
+	}
+	return outTx, nil
+}
+
+// signWitnessInput always errors. A hardware wallet cannot sign a swap
+// contract redemption or refund.
+func (w *hwiWallet) signWitnessInput(*wire.MsgTx, int, []byte, int64, string) (sig, pubkey []byte, err error) {
+	return nil, nil, errHWISigner
+}
+
+// signMessage always errors. A hardware wallet will not sign the raw message
+// hash that the server expects for a coin proof.
+func (w *hwiWallet) signMessage(string, []byte) (sig, pubkey []byte, err error) {
+	return nil, nil, errHWISigner
+}
+
+// pubKey returns the public key for the address from the wallet's descriptor
+// for the address, and checks that the key was derived from the hardware
+// wallet's master key.
+func (w *hwiWallet) pubKey(addr string) ([]byte, error) {
+	ai := new(GetAddressInfoResult)
+	if err := w.call(methodGetAddressInfo, anylist{addr}, ai); err != nil {
+		return nil, fmt.Errorf("getaddressinfo RPC failure: %w", err)
+	}
+	desc, err := dexbtc.ParseDescriptor(ai.Descriptor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor %q: %w", ai.Descriptor, err)
+	}
+	if desc.KeyOrigin == nil || !strings.EqualFold(desc.KeyOrigin.Fingerprint, w.hwi.fingerprint) {
+		return nil, fmt.Errorf("address %s is not from hardware wallet %s", addr, w.hwi.fingerprint)
+	}
+	if desc.KeyFmt != dexbtc.KeyHexPub {
+		return nil, fmt.Errorf("not a hexadecimal pubkey: %v", desc.Key)
+	}
+	pk, err := hex.DecodeString(desc.Key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = btcec.ParsePubKey(pk); err != nil {
+		return nil, fmt.Errorf("invalid pubkey: %w", err)
+	}
+	return pk, nil
+}
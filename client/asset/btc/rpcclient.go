@@ -213,6 +213,9 @@ func (wc *rpcClient) reconfigure(cfg *asset.WalletConfig, currentAddress string)
 	if err = config.Unmapify(cfg.Settings, parsedCfg); err != nil {
 		return
 	}
+	if parsedCfg.ExternalSignerConfig.enabled() {
+		return true, nil // switching to an external signer
+	}
 
 	// Check the RPC configuration.
 	newCfg := &parsedCfg.RPCConfig
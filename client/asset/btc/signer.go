@@ -0,0 +1,413 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/config"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// The external signer protocol is JSON-RPC 2.0 over HTTP POST. The signer
+// holds the private keys for a watching-only wallet, and provides signatures
+// for transactions constructed by the wallet. See the wiki for a description
+// of each method.
+const (
+	signerMethodGetPubKey   = "getpubkey"
+	signerMethodSignTx      = "signtx"
+	signerMethodSignInput   = "signinput"
+	signerMethodSignMessage = "signmessage"
+
+	// signerTimeout is how long to wait for a response from the signer. The
+	// signer may require approval from an operator, but swap transactions are
+	// time sensitive, so the approval must be prompt.
+	signerTimeout = time.Minute
+)
+
+var errExternalSigner = errors.New("private keys are held by the external signer")
+
+// ExternalSignerConfig is the configuration for an external signer for a
+// watching-only wallet. The signer is either a signer service at SignerURL or
+// a hardware wallet accessed with the HWI tool at HWIPath.
+type ExternalSignerConfig struct {
+	SignerURL      string `ini:"signerurl"`
+	SignerToken    string `ini:"signertoken"`
+	HWIPath        string `ini:"hwipath"`
+	HWIFingerprint string `ini:"hwifingerprint"`
+}
+
+// enabled is true if an external signer is configured.
+func (cfg *ExternalSignerConfig) enabled() bool {
+	return cfg.SignerURL != "" || cfg.HWIPath != ""
+}
+
+// ExternalSignerConfigOpts are the settings for an RPC wallet that delegates
+// signing to an external signer.
+var ExternalSignerConfigOpts = []*asset.ConfigOption{
+	{
+		Key:         "signerurl",
+		DisplayName: "External signer URL",
+		Description: "The URL of an external signer that holds the private keys for a " +
+			"watching-only wallet, e.g. https://10.0.0.2:7233. If set, the wallet " +
+			"is only used to monitor the chain and construct transactions.",
+	},
+	{
+		Key:         "signertoken",
+		DisplayName: "External signer token",
+		Description: "The bearer token with which to authenticate to the external signer.",
+		NoEcho:      true,
+	},
+	{
+		Key:         "hwipath",
+		DisplayName: "HWI path",
+		Description: "The path to the HWI executable, to sign transactions with a " +
+			"hardware wallet instead of an external signer URL. The wallet must be " +
+			"a watching-only wallet created from the device's descriptors. A " +
+			"hardware wallet can send and withdraw, but cannot sign swap " +
+			"redemptions, refunds, or order funding proofs.",
+	},
+	{
+		Key:         "hwifingerprint",
+		DisplayName: "Hardware wallet fingerprint",
+		Description: "The master key fingerprint of the hardware wallet, as listed by hwi enumerate.",
+	},
+}
+
+// signerPrevOut is the output spent by a transaction input, which is required
+// to compute the signature hash of a segwit input.
+type signerPrevOut struct {
+	Value    int64     `json:"value"`
+	PkScript dex.Bytes `json:"pkscript"`
+}
+
+// SignerSignTxParams are the parameters of the signtx method. The signer signs
+// every input for which it has the key.
+type SignerSignTxParams struct {
+	Tx       dex.Bytes        `json:"tx"`
+	PrevOuts []*signerPrevOut `json:"prevouts"`
+}
+
+// SignerSignTxResult is the result of the signtx method.
+type SignerSignTxResult struct {
+	Tx dex.Bytes `json:"tx"`
+}
+
+// SignerSignInputParams are the parameters of the signinput method, which
+// signs a single segwit input with SIGHASH_ALL, e.g. a swap contract
+// redemption or refund. Script is the script for the signature hash, such as
+// the contract, and Address is the address for the key.
+type SignerSignInputParams struct {
+	Tx      dex.Bytes `json:"tx"`
+	Index   int       `json:"index"`
+	Value   int64     `json:"value"`
+	Script  dex.Bytes `json:"script"`
+	Address string    `json:"address"`
+}
+
+// SignerSignMessageParams are the parameters of the signmessage method, which
+// signs the SHA-256 hash of the message.
+type SignerSignMessageParams struct {
+	Address string    `json:"address"`
+	Message dex.Bytes `json:"message"`
+}
+
+// SignerSigResult is the result of the signinput and signmessage methods. The
+// signature is DER-encoded, and for signinput, is followed by the sighash
+// type byte.
+type SignerSigResult struct {
+	Sig    dex.Bytes `json:"sig"`
+	PubKey dex.Bytes `json:"pubkey"`
+}
+
+// SignerPubKeyParams are the parameters of the getpubkey method.
+type SignerPubKeyParams struct {
+	Address string `json:"address"`
+}
+
+// SignerPubKeyResult is the result of the getpubkey method.
+type SignerPubKeyResult struct {
+	PubKey dex.Bytes `json:"pubkey"`
+}
+
+type signerRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type signerError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type signerResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *signerError    `json:"error"`
+}
+
+// externalSigner is a client for the external signer protocol.
+type externalSigner struct {
+	url    string
+	token  string
+	client *http.Client
+	reqID  uint64
+}
+
+func newExternalSigner(cfg *ExternalSignerConfig) (*externalSigner, error) {
+	u, err := url.Parse(cfg.SignerURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signer URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("signer URL scheme must be http or https, got %q", u.Scheme)
+	}
+	return &externalSigner{
+		url:    u.String(),
+		token:  cfg.SignerToken,
+		client: &http.Client{Timeout: signerTimeout},
+	}, nil
+}
+
+// call sends a request to the signer and decodes the result into thing.
+func (s *externalSigner) call(method string, params, thing any) error {
+	reqID := atomic.AddUint64(&s.reqID, 1)
+	b, err := json.Marshal(&signerRequest{
+		JSONRPC: "2.0",
+		ID:      reqID,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), signerTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("signer %s request error: %w", method, err)
+	}
+	defer resp.Body.Close()
+	b, err = io.ReadAll(io.LimitReader(resp.Body, 1<<22))
+	if err != nil {
+		return fmt.Errorf("error reading signer %s response: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("signer %s request failed: %s: %s", method, resp.Status, string(b))
+	}
+
+	var res signerResponse
+	if err = json.Unmarshal(b, &res); err != nil {
+		return fmt.Errorf("error decoding signer %s response: %w", method, err)
+	}
+	if res.Error != nil {
+		return fmt.Errorf("signer %s error %d: %s", method, res.Error.Code, res.Error.Message)
+	}
+	if res.ID != reqID {
+		return fmt.Errorf("signer %s response ID %d, expected %d", method, res.ID, reqID)
+	}
+	return json.Unmarshal(res.Result, thing)
+}
+
+// inputSigner is satisfied by a Wallet that does not have the private keys,
+// but can have inputs and messages signed.
+type inputSigner interface {
+	signWitnessInput(tx *wire.MsgTx, idx int, script []byte, val int64, addr string) (sig, pubkey []byte, err error)
+	signMessage(addr string, msg []byte) (sig, pubkey []byte, err error)
+	pubKey(addr string) ([]byte, error)
+}
+
+// signerWallet is an rpcClient for a watching-only wallet. Transactions are
+// constructed by the wallet and signed by the external signer.
+type signerWallet struct {
+	*rpcClient
+	signerCfg ExternalSignerConfig
+	signer    *externalSigner
+}
+
+var _ Wallet = (*signerWallet)(nil)
+var _ inputSigner = (*signerWallet)(nil)
+
+// connect connects to the wallet, and warns if the wallet has private keys,
+// since it should be watching-only.
+func (w *signerWallet) connect(ctx context.Context, wg *sync.WaitGroup) error {
+	if err := w.rpcClient.connect(ctx, wg); err != nil {
+		return err
+	}
+	walletInfo, err := w.GetWalletInfo()
+	if err != nil {
+		return fmt.Errorf("GetWalletInfo error: %w", err)
+	}
+	if walletInfo.PriveyKeysEnabled {
+		w.log.Warnf("Wallet %q has private keys, but transactions will be signed by the external signer. "+
+			"Use a watching-only wallet to keep the keys off of this machine.", walletInfo.WalletName)
+	}
+	return nil
+}
+
+// listUnspent lists the wallet's unspent outputs. The outputs of a watching-only
+// wallet may not be marked spendable, but an output is spendable by the signer
+// if the wallet knows how to solve its script.
+func (w *signerWallet) listUnspent() ([]*ListUnspentResult, error) {
+	unspents, err := w.rpcClient.listUnspent()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range unspents {
+		if u.Solvable {
+			u.Spendable = true
+		}
+	}
+	return unspents, nil
+}
+
+// signTx has the signer sign the transaction's inputs.
+func (w *signerWallet) signTx(inTx *wire.MsgTx) (*wire.MsgTx, error) {
+	txB, err := w.serializeTx(inTx)
+	if err != nil {
+		return nil, fmt.Errorf("tx serialization error: %w", err)
+	}
+	prevOuts := make([]*signerPrevOut, 0, len(inTx.TxIn))
+	for _, txIn := range inTx.TxIn {
+		op := txIn.PreviousOutPoint
+		txOut, _, err := w.getTxOut(&op.Hash, op.Index, nil, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		if txOut == nil {
+			return nil, fmt.Errorf("input %s is spent or unknown", op)
+		}
+		prevOuts = append(prevOuts, &signerPrevOut{
+			Value:    txOut.Value,
+			PkScript: txOut.PkScript,
+		})
+	}
+	var res SignerSignTxResult
+	if err = w.signer.call(signerMethodSignTx, &SignerSignTxParams{Tx: txB, PrevOuts: prevOuts}, &res); err != nil {
+		return nil, err
+	}
+	outTx, err := w.deserializeTx(res.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing signed transaction: %w", err)
+	}
+	if outTx.TxHash() != inTx.TxHash() {
+		return nil, errors.New("signer modified the transaction")
+	}
+	return outTx, nil
+}
+
+// signWitnessInput has the signer sign the segwit input.
+func (w *signerWallet) signWitnessInput(tx *wire.MsgTx, idx int, script []byte, val int64, addr string) (sig, pubkey []byte, err error) {
+	txB, err := w.serializeTx(tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tx serialization error: %w", err)
+	}
+	var res SignerSigResult
+	err = w.signer.call(signerMethodSignInput, &SignerSignInputParams{
+		Tx:      txB,
+		Index:   idx,
+		Value:   val,
+		Script:  script,
+		Address: addr,
+	}, &res)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Sig, res.PubKey, nil
+}
+
+// signMessage has the signer sign the message with the key for the address.
+func (w *signerWallet) signMessage(addr string, msg []byte) (sig, pubkey []byte, err error) {
+	var res SignerSigResult
+	if err = w.signer.call(signerMethodSignMessage, &SignerSignMessageParams{Address: addr, Message: msg}, &res); err != nil {
+		return nil, nil, err
+	}
+	return res.Sig, res.PubKey, nil
+}
+
+// pubKey retrieves the public key for the address from the signer, which
+// verifies that the signer has the private key.
+func (w *signerWallet) pubKey(addr string) ([]byte, error) {
+	var res SignerPubKeyResult
+	if err := w.signer.call(signerMethodGetPubKey, &SignerPubKeyParams{Address: addr}, &res); err != nil {
+		return nil, err
+	}
+	if _, err := btcec.ParsePubKey(res.PubKey); err != nil {
+		return nil, fmt.Errorf("signer returned an invalid pubkey: %w", err)
+	}
+	return res.PubKey, nil
+}
+
+// reconfigure requires a restart if the signer configuration has changed.
+func (w *signerWallet) reconfigure(cfg *asset.WalletConfig, currentAddress string) (restartRequired bool, err error) {
+	if cfg.Type != w.cloneParams.WalletCFG.Type {
+		return true, nil
+	}
+	parsedCfg := new(RPCWalletConfig)
+	if err = config.Unmapify(cfg.Settings, parsedCfg); err != nil {
+		return false, err
+	}
+	if parsedCfg.ExternalSignerConfig != w.signerCfg {
+		return true, nil
+	}
+	// Clear the signer settings so the rpcClient does not think an external
+	// signer is being added.
+	settings := make(map[string]string, len(cfg.Settings))
+	for k, v := range cfg.Settings {
+		settings[k] = v
+	}
+	delete(settings, "signerurl")
+	delete(settings, "signertoken")
+	delete(settings, "hwipath")
+	delete(settings, "hwifingerprint")
+	cfgCopy := *cfg
+	cfgCopy.Settings = settings
+	return w.rpcClient.reconfigure(&cfgCopy, currentAddress)
+}
+
+// privKeyForAddress always errors because the private keys are held by the
+// signer.
+func (w *signerWallet) privKeyForAddress(string) (*btcec.PrivateKey, error) {
+	return nil, errExternalSigner
+}
+
+// walletUnlock is a no-op. A watching-only wallet has nothing to unlock, and
+// the signer controls access to the keys.
+func (w *signerWallet) walletUnlock([]byte) error {
+	return nil
+}
+
+// walletLock is a no-op.
+func (w *signerWallet) walletLock() error {
+	return nil
+}
+
+// locked is always false.
+func (w *signerWallet) locked() bool {
+	return false
+}
@@ -0,0 +1,327 @@
+//go:build !spvlive && !harness
+
+package btc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"decred.org/dcrdex/dex"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// tSigner is an external signer with a single key.
+type tSigner struct {
+	t     *testing.T
+	priv  *btcec.PrivateKey
+	addr  string
+	token string
+}
+
+func (s *tSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     uint64          `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+s.token {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+		return
+	}
+	var result any
+	var rpcErr *signerError
+	pubKey := s.priv.PubKey().SerializeCompressed()
+	switch req.Method {
+	case signerMethodGetPubKey:
+		var params SignerPubKeyParams
+		json.Unmarshal(req.Params, &params)
+		if params.Address != s.addr {
+			rpcErr = &signerError{Code: 1, Message: "unknown address"}
+			break
+		}
+		result = &SignerPubKeyResult{PubKey: pubKey}
+	case signerMethodSignMessage:
+		var params SignerSignMessageParams
+		json.Unmarshal(req.Params, &params)
+		sig := ecdsa.Sign(s.priv, chainhash.HashB(params.Message))
+		result = &SignerSigResult{Sig: sig.Serialize(), PubKey: pubKey}
+	case signerMethodSignInput:
+		var params SignerSignInputParams
+		json.Unmarshal(req.Params, &params)
+		tx, err := msgTxFromBytes(params.Tx)
+		if err != nil {
+			s.t.Errorf("signer error decoding tx: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		prevOuts := txscript.NewCannedPrevOutputFetcher(params.Script, params.Value)
+		sigHashes := txscript.NewTxSigHashes(tx, prevOuts)
+		sig, err := txscript.RawTxInWitnessSignature(tx, sigHashes, params.Index, params.Value,
+			params.Script, txscript.SigHashAll, s.priv)
+		if err != nil {
+			s.t.Errorf("signer error signing input: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		result = &SignerSigResult{Sig: sig, PubKey: pubKey}
+	default:
+		rpcErr = &signerError{Code: -32601, Message: "method not found"}
+	}
+	resp := map[string]any{"id": req.ID}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestExternalSigner(t *testing.T) {
+	priv, _ := btcec.NewPrivateKey()
+	pubKey := priv.PubKey().SerializeCompressed()
+	addr, _ := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubKey), &chaincfg.MainNetParams)
+	s := &tSigner{t: t, priv: priv, addr: addr.String(), token: "abc"}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	newWallet := func(token string) *signerWallet {
+		signer, err := newExternalSigner(&ExternalSignerConfig{SignerURL: srv.URL, SignerToken: token})
+		if err != nil {
+			t.Fatalf("newExternalSigner error: %v", err)
+		}
+		return &signerWallet{
+			rpcClient: newRPCClient(&rpcCore{serializeTx: serializeMsgTx}),
+			signer:    signer,
+		}
+	}
+	w := newWallet(s.token)
+
+	if _, err := newExternalSigner(&ExternalSignerConfig{SignerURL: "ftp://localhost"}); err == nil {
+		t.Fatalf("no error for bad signer URL scheme")
+	}
+
+	// pubKey
+	pk, err := w.pubKey(s.addr)
+	if err != nil {
+		t.Fatalf("pubKey error: %v", err)
+	}
+	if !priv.PubKey().IsEqual(mustParsePubKey(t, pk)) {
+		t.Fatalf("wrong pubkey")
+	}
+	if _, err = w.pubKey("unknown"); err == nil {
+		t.Fatalf("no error for unknown address")
+	}
+
+	// signMessage
+	msg := []byte("message")
+	sigB, pk, err := w.signMessage(s.addr, msg)
+	if err != nil {
+		t.Fatalf("signMessage error: %v", err)
+	}
+	sig, err := ecdsa.ParseDERSignature(sigB)
+	if err != nil {
+		t.Fatalf("error parsing signature: %v", err)
+	}
+	if !sig.Verify(chainhash.HashB(msg), mustParsePubKey(t, pk)) {
+		t.Fatalf("message signature not valid")
+	}
+
+	// signWitnessInput, spending a P2WPKH output.
+	pkScript, _ := txscript.PayToAddrScript(addr)
+	const val = 1e8
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(val-1000, pkScript))
+	sigB, pk, err = w.signWitnessInput(tx, 0, pkScript, val, s.addr)
+	if err != nil {
+		t.Fatalf("signWitnessInput error: %v", err)
+	}
+	tx.TxIn[0].Witness = wire.TxWitness{sigB, pk}
+	prevOuts := txscript.NewCannedPrevOutputFetcher(pkScript, val)
+	vm, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(tx, prevOuts), val, prevOuts)
+	if err != nil {
+		t.Fatalf("NewEngine error: %v", err)
+	}
+	if err = vm.Execute(); err != nil {
+		t.Fatalf("input signature not valid: %v", err)
+	}
+
+	// Private keys are not available.
+	if _, err = w.privKeyForAddress(s.addr); err == nil {
+		t.Fatalf("no error for privKeyForAddress")
+	}
+
+	// Bad token.
+	if _, err = newWallet("xyz").pubKey(s.addr); err == nil {
+		t.Fatalf("no error for bad token")
+	}
+}
+
+// tHWIRequester is a watching-only wallet for a hardware wallet with a single
+// address.
+type tHWIRequester struct {
+	desc    string
+	prevOut *wire.TxOut
+}
+
+func (r *tHWIRequester) RawRequest(_ context.Context, method string, params []json.RawMessage) (json.RawMessage, error) {
+	switch method {
+	case methodGetAddressInfo:
+		return json.Marshal(&GetAddressInfoResult{IsMine: true, Descriptor: r.desc})
+	case methodWalletProcessPSBT:
+		var b64 string
+		if err := json.Unmarshal(params[0], &b64); err != nil {
+			return nil, err
+		}
+		packet, err := psbt.NewFromRawBytes(strings.NewReader(b64), true)
+		if err != nil {
+			return nil, err
+		}
+		u, _ := psbt.NewUpdater(packet)
+		if err = u.AddInWitnessUtxo(r.prevOut, 0); err != nil {
+			return nil, err
+		}
+		b64, _ = packet.B64Encode()
+		return json.Marshal(&walletProcessPSBTResult{PSBT: b64})
+	}
+	return nil, fmt.Errorf("unknown method %s", method)
+}
+
+func TestHWISigner(t *testing.T) {
+	priv, _ := btcec.NewPrivateKey()
+	pubKey := priv.PubKey().SerializeCompressed()
+	addr, _ := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubKey), &chaincfg.MainNetParams)
+	pkScript, _ := txscript.PayToAddrScript(addr)
+	const val = 1e8
+	const fingerprint = "b940190e"
+
+	cfg := &ExternalSignerConfig{HWIPath: "hwi", HWIFingerprint: fingerprint}
+	hwi, err := newHWISigner(cfg, dex.Mainnet, tLogger)
+	if err != nil {
+		t.Fatalf("newHWISigner error: %v", err)
+	}
+	if _, err = newHWISigner(&ExternalSignerConfig{HWIPath: "hwi"}, dex.Mainnet, tLogger); err == nil {
+		t.Fatalf("no error for missing fingerprint")
+	}
+	if _, err = newHWISigner(&ExternalSignerConfig{HWIPath: "hwi", HWIFingerprint: "abc"}, dex.Mainnet, tLogger); err == nil {
+		t.Fatalf("no error for bad fingerprint")
+	}
+
+	// The device signs the PSBT's only input.
+	var runErr error
+	var runOut []byte
+	hwi.run = func(_ context.Context, args ...string) ([]byte, error) {
+		if runErr != nil || runOut != nil {
+			return runOut, runErr
+		}
+		wantArgs := []string{"--fingerprint", fingerprint, "--chain", "main", "signtx"}
+		for i, arg := range wantArgs {
+			if args[i] != arg {
+				t.Fatalf("wrong hwi arg %d: %q != %q", i, args[i], arg)
+			}
+		}
+		packet, err := psbt.NewFromRawBytes(strings.NewReader(args[len(args)-1]), true)
+		if err != nil {
+			t.Fatalf("device error decoding PSBT: %v", err)
+		}
+		tx := packet.UnsignedTx
+		prevOuts := txscript.NewCannedPrevOutputFetcher(pkScript, val)
+		sig, err := txscript.RawTxInWitnessSignature(tx, txscript.NewTxSigHashes(tx, prevOuts), 0, val,
+			pkScript, txscript.SigHashAll, priv)
+		if err != nil {
+			t.Fatalf("device error signing input: %v", err)
+		}
+		u, _ := psbt.NewUpdater(packet)
+		if _, err = u.Sign(0, sig, pubKey, nil, nil); err != nil {
+			t.Fatalf("device error adding signature: %v", err)
+		}
+		b64, _ := packet.B64Encode()
+		return json.Marshal(&hwiSignTxResult{PSBT: b64, Signed: true})
+	}
+
+	node := newRPCClient(&rpcCore{serializeTx: serializeMsgTx})
+	node.requesterV.Store(&tHWIRequester{
+		desc:    fmt.Sprintf("wpkh([%s/84'/0'/0'/0/0]%s)", fingerprint, hex.EncodeToString(pubKey)),
+		prevOut: wire.NewTxOut(val, pkScript),
+	})
+	w := &hwiWallet{
+		signerWallet: &signerWallet{rpcClient: node, signerCfg: *cfg},
+		hwi:          hwi,
+	}
+
+	// pubKey
+	pk, err := w.pubKey(addr.String())
+	if err != nil {
+		t.Fatalf("pubKey error: %v", err)
+	}
+	if !priv.PubKey().IsEqual(mustParsePubKey(t, pk)) {
+		t.Fatalf("wrong pubkey")
+	}
+	hwi.fingerprint = "00000000"
+	if _, err = w.pubKey(addr.String()); err == nil {
+		t.Fatalf("no error for address from another device")
+	}
+	hwi.fingerprint = fingerprint
+
+	// signTx, spending a P2WPKH output.
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(val-1000, pkScript))
+	signedTx, err := w.signTx(tx)
+	if err != nil {
+		t.Fatalf("signTx error: %v", err)
+	}
+	prevOuts := txscript.NewCannedPrevOutputFetcher(pkScript, val)
+	vm, err := txscript.NewEngine(pkScript, signedTx, 0, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(signedTx, prevOuts), val, prevOuts)
+	if err != nil {
+		t.Fatalf("NewEngine error: %v", err)
+	}
+	if err = vm.Execute(); err != nil {
+		t.Fatalf("input signature not valid: %v", err)
+	}
+
+	// Device error.
+	runOut = []byte(`{"error": "Could not find device", "code": -3}`)
+	runErr = errors.New("exit status 1")
+	if _, err = w.signTx(tx); err == nil || !strings.Contains(err.Error(), "Could not find device") {
+		t.Fatalf("wrong error for device error: %v", err)
+	}
+	runOut, runErr = nil, nil
+
+	// Swap contract inputs and messages can't be signed.
+	if _, _, err = w.signWitnessInput(tx, 0, pkScript, val, addr.String()); !errors.Is(err, errHWISigner) {
+		t.Fatalf("wrong signWitnessInput error: %v", err)
+	}
+	if _, _, err = w.signMessage(addr.String(), []byte("message")); !errors.Is(err, errHWISigner) {
+		t.Fatalf("wrong signMessage error: %v", err)
+	}
+}
+
+func mustParsePubKey(t *testing.T, b []byte) *btcec.PublicKey {
+	t.Helper()
+	pk, err := btcec.ParsePubKey(b)
+	if err != nil {
+		t.Fatalf("error parsing pubkey: %v", err)
+	}
+	return pk
+}
@@ -104,7 +104,7 @@ func (d *Driver) Info() *asset.WalletInfo {
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, false)
+	return dexbtc.MinLotSizeForDust(&dexdash.DustPolicy, maxFeeRate, false)
 }
 
 // newWallet constructs a new client wallet for Dash based on the WalletDefinition.Type
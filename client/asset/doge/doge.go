@@ -21,8 +21,6 @@ const (
 	version = 0
 	BipID   = 3
 
-	dustLimit = 1_000_000 // sats => 0.01 DOGE, the "soft" limit (DEFAULT_DUST_LIMIT)
-
 	minNetworkVersion = 1140700 // v1.14.7.0-a6d122013
 	walletTypeRPC     = "dogecoindRPC"
 	feeConfs          = 10
@@ -129,7 +127,7 @@ func (d *Driver) Info() *asset.WalletInfo {
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dustLimit + dexbtc.RedeemSwapTxSize(false)*maxFeeRate
+	return dexbtc.MinLotSizeForDust(&dexdoge.DustPolicy, maxFeeRate, false)
 }
 
 // NewWallet is the exported constructor by which the DEX will import the
@@ -177,7 +175,7 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 		BooleanGetBlockRPC:       true,
 		SingularWallet:           true,
 		UnlockSpends:             true,
-		ConstantDustLimit:        dustLimit,
+		ConstantDustLimit:        dexdoge.DustPolicy.MinOutput,
 		FeeEstimator:             estimateFee,
 		ExternalFeeEstimator:     externalFeeRate,
 		BlockDeserializer:        dexdoge.DeserializeBlock,
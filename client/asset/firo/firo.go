@@ -115,7 +115,7 @@ func (d *Driver) Info() *asset.WalletInfo {
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, false)
+	return dexbtc.MinLotSizeForDust(&dexfiro.DustPolicy, maxFeeRate, false)
 }
 
 // NewWallet is the exported constructor by which the DEX will import the
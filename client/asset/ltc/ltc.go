@@ -113,7 +113,7 @@ func (d *Driver) Info() *asset.WalletInfo {
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, true)
+	return dexbtc.MinLotSizeForDust(&dexltc.DustPolicy, maxFeeRate, true)
 }
 
 // Exists checks the existence of the wallet. Part of the Creator interface, so
@@ -225,6 +225,7 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 		InitTxSize:           dexbtc.InitTxSizeSegwit,
 		InitTxSizeBase:       dexbtc.InitTxSizeBaseSegwit,
 		BlockDeserializer:    dexltc.DeserializeBlockBytes,
+		BlockPowHasher:       dexltc.PowHash,
 		ExternalFeeEstimator: externalFeeRate,
 		AssetID:              BipID,
 	}
@@ -335,8 +335,12 @@ func mainCore() error {
 		}
 	}()
 
+	var logDir string
+	if cfg.LogPath != "" {
+		logDir = filepath.Dir(cfg.LogPath)
+	}
 	systray.Run(func() {
-		systrayOnReady(appCtx, filepath.Dir(cfg.LogPath), openC, killChan, activeState)
+		systrayOnReady(appCtx, logDir, openC, killChan, activeState)
 	}, nil)
 
 	closeAllWindows()
@@ -496,18 +500,21 @@ func systrayOnReady(ctx context.Context, logDirectory string, openC chan<- struc
 
 	systray.AddSeparator()
 
-	if logDirURL, err := app.FilePathToURL(logDirectory); err != nil {
-		log.Errorf("error constructing log directory URL: %v", err)
-	} else {
-		mLogs := systray.AddMenuItem("Open logs folder", "Open the folder with your DEX logs.")
-		go func() {
-			for range mLogs.ClickedCh {
-				if err := browser.OpenURL(logDirURL); err != nil {
-					fmt.Fprintln(os.Stderr, err) // you're actually looking for the log file, so info on stdout is warranted
-					log.Errorf("Unable to open log file directory: %v", err)
+	// There is no log directory in amnesia mode.
+	if logDirectory != "" {
+		if logDirURL, err := app.FilePathToURL(logDirectory); err != nil {
+			log.Errorf("error constructing log directory URL: %v", err)
+		} else {
+			mLogs := systray.AddMenuItem("Open logs folder", "Open the folder with your DEX logs.")
+			go func() {
+				for range mLogs.ClickedCh {
+					if err := browser.OpenURL(logDirURL); err != nil {
+						fmt.Fprintln(os.Stderr, err) // you're actually looking for the log file, so info on stdout is warranted
+						log.Errorf("Unable to open log file directory: %v", err)
+					}
 				}
-			}
-		}()
+			}()
+		}
 	}
 
 	systray.AddSeparator()
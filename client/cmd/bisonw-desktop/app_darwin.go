@@ -334,7 +334,10 @@ func mainCore() error {
 		return fmt.Errorf("url.ParseRequestURI error: %w", err)
 	}
 
-	logDir := filepath.Dir(cfg.LogPath)
+	var logDir string // no log file in amnesia mode
+	if cfg.LogPath != "" {
+		logDir = filepath.Dir(cfg.LogPath)
+	}
 	classWrapper := initCocoaDefaultDelegateClassWrapper(logDir)
 
 	// MacOS will always execute this method when bisonw-desktop is about to exit
@@ -490,6 +493,10 @@ func initCocoaDefaultDelegateClassWrapper(logDir string) *cocoaDefaultDelegateCl
 	// different menus. App delegates methods should be added before NSApp is
 	// initialized.
 	ad.AddMethod(selOpenLogs, func(_ objc.Object) {
+		if logDir == "" {
+			log.Infof("There is no log file in amnesia mode")
+			return
+		}
 		logDirURL, err := app.FilePathToURL(logDir)
 		if err != nil {
 			log.Errorf("error constructing log directory URL: %v", err)
@@ -68,18 +68,21 @@ func onReady() {
 
 	systray.AddSeparator()
 
-	if logDirURL, err := app.FilePathToURL(filepath.Dir(cfg.LogPath)); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-	} else {
-		mLogs := systray.AddMenuItem("Open logs folder", "Open the folder with your DEX logs.")
-		go func() {
-			for range mLogs.ClickedCh {
-				err := browser.OpenURL(logDirURL)
-				if err != nil {
-					fmt.Fprintln(os.Stderr, err)
+	// There is no log file in amnesia mode.
+	if cfg.LogPath != "" {
+		if logDirURL, err := app.FilePathToURL(filepath.Dir(cfg.LogPath)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		} else {
+			mLogs := systray.AddMenuItem("Open logs folder", "Open the folder with your DEX logs.")
+			go func() {
+				for range mLogs.ClickedCh {
+					err := browser.OpenURL(logDirURL)
+					if err != nil {
+						fmt.Fprintln(os.Stderr, err)
+					}
 				}
-			}
-		}()
+			}()
+		}
 	}
 
 	if cfgPathURL, err := app.FilePathToURL(cfg.ConfigPath); err != nil {
@@ -52,6 +52,8 @@ var promptPasswords = map[string][]string{
 	"login":             {"App password:"},
 	"newwallet":         {"App password:", "Wallet password:"},
 	"openwallet":        {"App password:"},
+	"changeapppass":     {"App password:", "New app password:"},
+	"setwalletpass":     {"App password:", "New wallet password:"},
 	"register":          {"App password:"},
 	"postbond":          {"App password:"},
 	"trade":             {"App password:"},
@@ -63,6 +65,7 @@ var promptPasswords = map[string][]string{
 	"purchasetickets":   {"App password:"},
 	"startmmbot":        {"App password:"},
 	"withdrawbchspv":    {"App password"},
+	"restoredbsnapshot": {"App password:"},
 }
 
 // optionalTextFiles is a map of routes to arg index for routes that should read
@@ -0,0 +1,127 @@
+//go:build harness && !nolgpl
+
+// dextrader trades between two clients on the simnet harnesses for hours at a
+// time, with randomized orders, cancels, and missed swaps, checking that funds
+// are not left locked, that the book is consistent, and that the server scores
+// the clients correctly. It is meant to be run as a nightly soak test.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	dexpolygon "decred.org/dcrdex/dex/networks/polygon"
+)
+
+func parseWalletType(t string) (core.SimWalletType, error) {
+	switch t {
+	case "core":
+		return core.WTCoreClone, nil
+	case "spv":
+		return core.WTSPVNative, nil
+	case "electrum":
+		return core.WTElectrum, nil
+	default:
+		return 0, errors.New("invalid wallet type")
+	}
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("SUCCESS!")
+	os.Exit(0)
+}
+
+func run() error {
+	var baseSymbol, quoteSymbol, base1Node, quote1Node, base2Node, quote2Node, regAsset string
+	var base1Type, quote1Type, base2Type, quote2Type string
+	var cancelRate, missRate float64
+	var duration time.Duration
+	var debug, trace bool
+	flag.StringVar(&baseSymbol, "base", "dcr", "the market's base asset")
+	flag.StringVar(&quoteSymbol, "quote", "btc", "the market's quote asset")
+	flag.StringVar(&base1Node, "base1node", "beta", "the harness node to connect to for the first client's base asset. only RPC wallets")
+	flag.StringVar(&quote1Node, "quote1node", "beta", "the harness node to connect to for the first client's quote asset. only RPC wallets")
+	flag.StringVar(&base2Node, "base2node", "gamma", "the harness node to connect to for the second client's base asset. only RPC wallets")
+	flag.StringVar(&quote2Node, "quote2node", "gamma", "the harness node to connect to for the second client's quote asset. only RPC wallets")
+	flag.StringVar(&regAsset, "regasset", "", "the asset to use for registration. default is base asset")
+	flag.StringVar(&base1Type, "base1type", "core", "the wallet type for the first client's base asset (core, spv, electrum). ignored for eth")
+	flag.StringVar(&quote1Type, "quote1type", "core", "the wallet type for the first client's quote asset (core, spv, electrum). ignored for eth")
+	flag.StringVar(&base2Type, "base2type", "core", "the wallet type for the second client's base asset (core, spv, electrum). ignored for eth")
+	flag.StringVar(&quote2Type, "quote2type", "core", "the wallet type for the second client's quote asset (core, spv, electrum). ignored for eth")
+	flag.DurationVar(&duration, "duration", time.Hour*6, "how long to trade, e.g. 4h. zero trades until there is an error")
+	flag.Float64Var(&cancelRate, "cancelrate", 0.2, "the probability that a client books and cancels an order before a trade")
+	flag.Float64Var(&missRate, "missrate", 0.1, "the probability that the maker or taker of a trade does not send their swap")
+	flag.BoolVar(&debug, "debug", false, "log at logging level debug")
+	flag.BoolVar(&trace, "trace", false, "log at logging level trace")
+	flag.Parse()
+
+	if cancelRate < 0 || cancelRate > 1 || missRate < 0 || missRate > 1 {
+		return errors.New("cancelrate and missrate must be between 0 and 1")
+	}
+
+	logLevel := dex.LevelInfo
+	switch {
+	case trace:
+		logLevel = dex.LevelTrace
+	case debug:
+		logLevel = dex.LevelDebug
+	}
+
+	if regAsset == "" {
+		regAsset = baseSymbol
+	}
+
+	b1wt, err := parseWalletType(base1Type)
+	if err != nil {
+		return fmt.Errorf("invalid base1 wallet type %q", base1Type)
+	}
+	q1wt, err := parseWalletType(quote1Type)
+	if err != nil {
+		return fmt.Errorf("invalid quote1 wallet type %q", quote1Type)
+	}
+	b2wt, err := parseWalletType(base2Type)
+	if err != nil {
+		return fmt.Errorf("invalid base2 wallet type %q", base2Type)
+	}
+	q2wt, err := parseWalletType(quote2Type)
+	if err != nil {
+		return fmt.Errorf("invalid quote2 wallet type %q", quote2Type)
+	}
+
+	return core.RunSoak(&core.SoakConfig{
+		BaseSymbol:        baseSymbol,
+		QuoteSymbol:       quoteSymbol,
+		RegistrationAsset: regAsset,
+		Client1: &core.SimClient{
+			BaseWalletType:  b1wt,
+			QuoteWalletType: q1wt,
+			BaseNode:        base1Node,
+			QuoteNode:       quote1Node,
+		},
+		Client2: &core.SimClient{
+			BaseWalletType:  b2wt,
+			QuoteWalletType: q2wt,
+			BaseNode:        base2Node,
+			QuoteNode:       quote2Node,
+		},
+		Duration:   duration,
+		CancelRate: cancelRate,
+		MissRate:   missRate,
+		Logger:     dex.StdOutLogger("SOAK", logLevel),
+	})
+}
+
+func init() {
+	dexeth.MaybeReadSimnetAddrs()
+	dexpolygon.MaybeReadSimnetAddrs()
+}
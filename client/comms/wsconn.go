@@ -4,10 +4,12 @@
 package comms
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
@@ -76,9 +78,14 @@ func isErrorInvalidCert(err error) bool {
 	var unknownCertAuthErr x509.UnknownAuthorityError
 	var hostNameErr x509.HostnameError
 	return errors.As(err, &invalidCertErr) || errors.As(err, &hostNameErr) ||
-		errors.As(err, &unknownCertAuthErr) || invalidCertRegexp.MatchString(err.Error())
+		errors.As(err, &unknownCertAuthErr) || errors.Is(err, errCertMismatch) ||
+		invalidCertRegexp.MatchString(err.Error())
 }
 
+// errCertMismatch is the TLS handshake error when the server's certificate is
+// not the pinned certificate.
+var errCertMismatch = errors.New("server certificate does not match the pinned certificate")
+
 // ErrInvalidCert is the error returned when attempting to use an invalid cert
 // to set up a ws connection.
 var ErrInvalidCert = fmt.Errorf("invalid certificate")
@@ -122,6 +129,11 @@ type WsCfg struct {
 	// The server's certificate.
 	Cert []byte
 
+	// PinCert requires the server's certificate to be exactly Cert. The
+	// certificate chain and host name are not verified, so the certificate may
+	// be self-signed for a different name, as is likely for a .onion host.
+	PinCert bool
+
 	// ReconnectSync runs the needed reconnection synchronization after
 	// a reconnect.
 	ReconnectSync func()
@@ -147,6 +159,10 @@ type WsCfg struct {
 
 	ConnectHeaders http.Header
 
+	// ConnectHeadersFunc, if set, supplies additional headers for each
+	// connection attempt. They are merged with ConnectHeaders.
+	ConnectHeadersFunc func() http.Header
+
 	// EchoPingData will echo any data from pings as the pong data.
 	EchoPingData bool
 }
@@ -204,6 +220,16 @@ func NewWsConn(cfg *WsCfg) (WsConn, error) {
 		MinVersion: tls.VersionTLS12,
 		ServerName: uri.Hostname(),
 	}
+	if cfg.PinCert {
+		verifyPinned, err := pinnedCertVerifier(cfg.Cert)
+		if err != nil {
+			return nil, err
+		}
+		// Skip the default verification, which includes the host name check,
+		// and check only that the server presents the pinned certificate.
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyPinned
+	}
 
 	conn := &wsConn{
 		cfg:          cfg,
@@ -218,6 +244,24 @@ func NewWsConn(cfg *WsCfg) (WsConn, error) {
 	return conn, nil
 }
 
+// pinnedCertVerifier creates a tls.Config.VerifyPeerCertificate function that
+// accepts only the PEM-encoded certificate.
+func pinnedCertVerifier(certPEM []byte) (func([][]byte, [][]*x509.Certificate) error, error) {
+	if len(certPEM) == 0 {
+		return nil, ErrCertRequired
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, ErrInvalidCert
+	}
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], block.Bytes) {
+			return errCertMismatch
+		}
+		return nil
+	}, nil
+}
+
 func (conn *wsConn) UpdateURL(uri string) {
 	conn.urlV.Store(uri)
 }
@@ -241,6 +285,46 @@ func (conn *wsConn) setConnectionStatus(status ConnectionStatus) {
 	}
 }
 
+// connectHeaders returns the headers for a connection attempt.
+func (conn *wsConn) connectHeaders() http.Header {
+	if conn.cfg.ConnectHeadersFunc == nil {
+		return conn.cfg.ConnectHeaders
+	}
+	headers := conn.cfg.ConnectHeaders.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	for k, v := range conn.cfg.ConnectHeadersFunc() {
+		headers[k] = append(headers[k], v...)
+	}
+	return headers
+}
+
+// drainRedirect checks a failed handshake response for a draining server's
+// msgjson.DrainResponse and returns its RetryAddr if it is usable. The
+// redirect is only followed if it has the same scheme as the current URL, and
+// the TLS configuration, including the expected server name, is unchanged.
+func (conn *wsConn) drainRedirect(resp *http.Response) string {
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable || resp.Body == nil {
+		return ""
+	}
+	var drainResp msgjson.DrainResponse
+	if err := json.NewDecoder(resp.Body).Decode(&drainResp); err != nil || drainResp.RetryAddr == "" {
+		return ""
+	}
+	retryURL, err := url.Parse(drainResp.RetryAddr)
+	if err != nil || retryURL.Host == "" {
+		conn.log.Warnf("Ignoring invalid drain redirect %q", drainResp.RetryAddr)
+		return ""
+	}
+	origURL, err := url.Parse(conn.url())
+	if err != nil || retryURL.Scheme != origURL.Scheme {
+		conn.log.Warnf("Ignoring drain redirect %q with a different scheme", drainResp.RetryAddr)
+		return ""
+	}
+	return retryURL.String()
+}
+
 // connect attempts to establish a websocket connection.
 func (conn *wsConn) connect(ctx context.Context) error {
 	dialer := &websocket.Dialer{
@@ -253,7 +337,14 @@ func (conn *wsConn) connect(ctx context.Context) error {
 		dialer.Proxy = http.ProxyFromEnvironment
 	}
 
-	ws, _, err := dialer.DialContext(ctx, conn.url(), conn.cfg.ConnectHeaders)
+	headers := conn.connectHeaders()
+	ws, resp, err := dialer.DialContext(ctx, conn.url(), headers)
+	if err != nil {
+		if retryAddr := conn.drainRedirect(resp); retryAddr != "" {
+			conn.log.Infof("Server at %s is draining connections. Retrying at %s.", conn.url(), retryAddr)
+			ws, _, err = dialer.DialContext(ctx, retryAddr, headers)
+		}
+	}
 	if err != nil {
 		if isErrorInvalidCert(err) {
 			conn.setConnectionStatus(InvalidCert)
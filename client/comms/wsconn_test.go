@@ -5,12 +5,17 @@ import (
 	"context"
 	"crypto/elliptic"
 	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -486,3 +491,129 @@ func TestWsConn(t *testing.T) {
 		t.Error("read source should have been closed")
 	}
 }
+
+func TestPinnedCert(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "rpc.cert"), filepath.Join(dir, "rpc.key")
+	if err := genCertPair(certFile, keyFile, []string{"localhost"}); err != nil {
+		t.Fatal(err)
+	}
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(certPEM)
+
+	newConn := func(cert []byte) (*wsConn, error) {
+		wsc, err := NewWsConn(&WsCfg{
+			URL:     "wss://somedex13254214214.onion:7232/ws",
+			Cert:    cert,
+			PinCert: true,
+			Logger:  tLogger,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return wsc.(*wsConn), nil
+	}
+
+	if _, err := newConn(nil); !errors.Is(err, ErrCertRequired) {
+		t.Fatalf("expected ErrCertRequired without a cert, got %v", err)
+	}
+	if _, err := newConn([]byte("not a cert")); !errors.Is(err, ErrInvalidCert) {
+		t.Fatalf("expected ErrInvalidCert for a bad cert, got %v", err)
+	}
+
+	conn, err := newConn(certPEM)
+	if err != nil {
+		t.Fatalf("NewWsConn error: %v", err)
+	}
+	if !conn.tlsCfg.InsecureSkipVerify || conn.tlsCfg.VerifyPeerCertificate == nil {
+		t.Fatalf("pinned cert verification not configured")
+	}
+	verify := conn.tlsCfg.VerifyPeerCertificate
+	if err := verify([][]byte{block.Bytes}, nil); err != nil {
+		t.Fatalf("pinned cert rejected: %v", err)
+	}
+	otherCert := append([]byte{}, block.Bytes...)
+	otherCert[len(otherCert)-1]++
+	err = verify([][]byte{otherCert}, nil)
+	if !errors.Is(err, errCertMismatch) || !isErrorInvalidCert(err) {
+		t.Fatalf("expected cert mismatch error, got %v", err)
+	}
+	if err := verify(nil, nil); err == nil {
+		t.Fatalf("no error without a server cert")
+	}
+}
+
+func TestDrainRedirect(t *testing.T) {
+	acctID := "0123"
+	var gotAcct atomic.Value
+	upgrader := websocket.Upgrader{}
+	retrySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAcct.Store(r.Header.Get(msgjson.AccountIDHeader))
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		ws.ReadMessage() // block until the client closes
+		ws.Close()
+	}))
+	defer retrySrv.Close()
+	retryAddr := "ws" + strings.TrimPrefix(retrySrv.URL, "http") + "/ws"
+
+	var redirect atomic.Value
+	drainSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(&msgjson.DrainResponse{
+			Error:     "server is draining connections",
+			RetryAddr: redirect.Load().(string),
+		})
+	}))
+	defer drainSrv.Close()
+
+	connect := func() error {
+		wsc, err := NewWsConn(&WsCfg{
+			URL:                  "ws" + strings.TrimPrefix(drainSrv.URL, "http") + "/ws",
+			PingWait:             time.Minute,
+			Logger:               tLogger,
+			DisableAutoReconnect: true,
+			ConnectHeadersFunc: func() http.Header {
+				return http.Header{msgjson.AccountIDHeader: {acctID}}
+			},
+		})
+		if err != nil {
+			t.Fatalf("NewWsConn error: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		wg, err := wsc.Connect(ctx)
+		if err != nil {
+			return err
+		}
+		cancel()
+		wg.Wait()
+		return nil
+	}
+
+	redirect.Store(retryAddr)
+	if err := connect(); err != nil {
+		t.Fatalf("drain redirect not followed: %v", err)
+	}
+	if acct, _ := gotAcct.Load().(string); acct != acctID {
+		t.Fatalf("wrong account ID header at the redirect server. wanted %q, got %q", acctID, acct)
+	}
+
+	// A redirect that changes the scheme is refused.
+	redirect.Store("wss" + strings.TrimPrefix(retryAddr, "ws"))
+	if err := connect(); err == nil {
+		t.Fatalf("drain redirect to a different scheme was followed")
+	}
+
+	// No redirect.
+	redirect.Store("")
+	if err := connect(); err == nil {
+		t.Fatalf("no error from a draining server without a redirect")
+	}
+}
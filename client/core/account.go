@@ -10,6 +10,7 @@ import (
 	"decred.org/dcrdex/client/comms"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/server/account"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
@@ -140,8 +141,26 @@ func (c *Core) AccountExport(pw []byte, host string) (*Account, []*db.Bond, erro
 	return acct, acctInf.Bonds, nil
 }
 
+// AccountReputation retrieves the breakdown of the account's reputation from
+// the server, including the recent swap and order outcomes that determine the
+// score and the thresholds at which the account would be penalized.
+func (c *Core) AccountReputation(host string) (*msgjson.ReputationReport, error) {
+	dc, err := c.registeredDEX(host)
+	if err != nil {
+		return nil, err
+	}
+	report := new(msgjson.ReputationReport)
+	if err := sendRequest(dc.WsConn, msgjson.ReputationRoute, nil, report, DefaultResponseTimeout); err != nil {
+		return nil, fmt.Errorf("error retrieving reputation from %s: %w", dc.acct.host, err)
+	}
+	return report, nil
+}
+
 // AccountImport is used import an existing account into the db.
 func (c *Core) AccountImport(pw []byte, acct *Account, bonds []*db.Bond) error {
+	if err := c.checkAmnesia("import an account"); err != nil {
+		return err
+	}
 	crypter, err := c.encryptionKey(pw)
 	if err != nil {
 		return codedError(passwordErr, err)
@@ -0,0 +1,188 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/encrypt"
+)
+
+// AddressBook decrypts and returns the address book for the specified asset.
+func (c *Core) AddressBook(pw []byte, assetID uint32) (*AddressBook, error) {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return nil, codedError(passwordErr, err)
+	}
+	defer crypter.Close()
+
+	dbBook, entries, err := c.addressBook(crypter, assetID)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressBook{
+		AssetID:    assetID,
+		Entries:    entries,
+		Restricted: dbBook.Restricted,
+	}, nil
+}
+
+// AddAddressBookEntry adds an address to the asset's address book. If the
+// address is already in the book, its label is updated.
+func (c *Core) AddAddressBookEntry(pw []byte, assetID uint32, addr, label string) error {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return codedError(passwordErr, err)
+	}
+	defer crypter.Close()
+
+	wallet, found := c.wallet(assetID)
+	if !found {
+		return newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+	}
+	if !wallet.Wallet.ValidateAddress(addr) {
+		return newError(addressParseErr, "invalid %s address %q", unbip(assetID), addr)
+	}
+
+	c.addrBookMtx.Lock()
+	defer c.addrBookMtx.Unlock()
+	dbBook, entries, err := c.addressBook(crypter, assetID)
+	if err != nil {
+		return err
+	}
+	var updated bool
+	for _, entry := range entries {
+		if entry.Address == addr {
+			entry.Label = label
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		entries = append(entries, &AddressBookEntry{Address: addr, Label: label})
+	}
+	return c.saveAddressBook(crypter, dbBook, entries)
+}
+
+// RemoveAddressBookEntry removes an address from the asset's address book.
+func (c *Core) RemoveAddressBookEntry(pw []byte, assetID uint32, addr string) error {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return codedError(passwordErr, err)
+	}
+	defer crypter.Close()
+
+	c.addrBookMtx.Lock()
+	defer c.addrBookMtx.Unlock()
+	dbBook, entries, err := c.addressBook(crypter, assetID)
+	if err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		if entry.Address == addr {
+			entries = append(entries[:i], entries[i+1:]...)
+			return c.saveAddressBook(crypter, dbBook, entries)
+		}
+	}
+	return newError(addressBookErr, "address %q is not in the %s address book", addr, unbip(assetID))
+}
+
+// RestrictWithdrawals sets whether sends and withdraws of the asset are
+// restricted to addresses in the asset's address book. The address book must
+// have at least one entry to enable the restriction.
+func (c *Core) RestrictWithdrawals(pw []byte, assetID uint32, restrict bool) error {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return codedError(passwordErr, err)
+	}
+	defer crypter.Close()
+
+	c.addrBookMtx.Lock()
+	defer c.addrBookMtx.Unlock()
+	dbBook, entries, err := c.addressBook(crypter, assetID)
+	if err != nil {
+		return err
+	}
+	if restrict && len(entries) == 0 {
+		return newError(addressBookErr, "cannot restrict %s withdrawals to an empty address book", unbip(assetID))
+	}
+	dbBook.Restricted = restrict
+	return c.saveAddressBook(crypter, dbBook, entries)
+}
+
+// checkWithdrawalAddress checks that the address is in the asset's address
+// book if withdrawals are restricted. The crypter is required to decrypt the
+// address book, so a restricted asset cannot be sent without the password.
+func (c *Core) checkWithdrawalAddress(crypter encrypt.Crypter, assetID uint32, addr string) error {
+	dbBook, err := c.db.AddressBook(assetID)
+	if err != nil {
+		return codedError(dbErr, fmt.Errorf("error retrieving %s address book: %w", unbip(assetID), err))
+	}
+	if !dbBook.Restricted {
+		return nil
+	}
+	if crypter == nil {
+		return newError(restrictedAddrErr, "%s withdrawals are restricted to the address book, "+
+			"and the app password is required", unbip(assetID))
+	}
+	entries, err := decryptAddressBook(crypter, dbBook)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Address == addr {
+			return nil
+		}
+	}
+	return newError(restrictedAddrErr, "%s withdrawals are restricted to the address book, "+
+		"and %q is not in the address book", unbip(assetID), addr)
+}
+
+// addressBook retrieves the asset's address book from the DB and decrypts the
+// entries.
+func (c *Core) addressBook(crypter encrypt.Crypter, assetID uint32) (*db.AddressBook, []*AddressBookEntry, error) {
+	dbBook, err := c.db.AddressBook(assetID)
+	if err != nil {
+		return nil, nil, codedError(dbErr, fmt.Errorf("error retrieving %s address book: %w", unbip(assetID), err))
+	}
+	entries, err := decryptAddressBook(crypter, dbBook)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dbBook, entries, nil
+}
+
+// saveAddressBook encrypts the entries and stores the address book.
+func (c *Core) saveAddressBook(crypter encrypt.Crypter, dbBook *db.AddressBook, entries []*AddressBookEntry) error {
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("error encoding address book: %w", err)
+	}
+	dbBook.EncEntries, err = crypter.Encrypt(b)
+	if err != nil {
+		return codedError(encryptionErr, fmt.Errorf("error encrypting address book: %w", err))
+	}
+	if err := c.db.SetAddressBook(dbBook); err != nil {
+		return codedError(dbErr, fmt.Errorf("error storing %s address book: %w", unbip(dbBook.AssetID), err))
+	}
+	return nil
+}
+
+// decryptAddressBook decrypts the address book entries.
+func decryptAddressBook(crypter encrypt.Crypter, dbBook *db.AddressBook) ([]*AddressBookEntry, error) {
+	entries := make([]*AddressBookEntry, 0)
+	if len(dbBook.EncEntries) == 0 {
+		return entries, nil
+	}
+	b, err := crypter.Decrypt(dbBook.EncEntries)
+	if err != nil {
+		return nil, codedError(encryptionErr, fmt.Errorf("error decrypting address book: %w", err))
+	}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, codedError(decodeErr, fmt.Errorf("error decoding address book: %w", err))
+	}
+	return entries, nil
+}
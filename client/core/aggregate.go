@@ -0,0 +1,243 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"decred.org/dcrdex/client/comms"
+	"decred.org/dcrdex/dex/calc"
+	serverdex "decred.org/dcrdex/server/dex"
+)
+
+// marketServers returns the connected servers that list the market, sorted by
+// host.
+func (c *Core) marketServers(base, quote uint32) []*dexConnection {
+	mktID := marketName(base, quote)
+	dcs := make([]*dexConnection, 0)
+	for _, dc := range c.dexConnections() {
+		if dc.status() != comms.Connected || dc.marketConfig(mktID) == nil {
+			continue
+		}
+		dcs = append(dcs, dc)
+	}
+	sort.Slice(dcs, func(i, j int) bool {
+		return dcs[i].acct.host < dcs[j].acct.host
+	})
+	return dcs
+}
+
+// AggregateBook combines the order books of a market on every connected server
+// that lists it. Book orders at the same rate are combined into one level, and
+// the top of book and depth of each server are summarized. Rates are
+// comparable between servers, so the aggregated book shows where the best
+// price is. Servers whose book could not be retrieved are summarized with the
+// error.
+func (c *Core) AggregateBook(base, quote uint32) (*AggregateBook, error) {
+	dcs := c.marketServers(base, quote)
+	if len(dcs) == 0 {
+		return nil, fmt.Errorf("no connected server lists the %s market", marketName(base, quote))
+	}
+	books := make(map[string]*OrderBook, len(dcs))
+	failed := make(map[string]error)
+	for _, dc := range dcs {
+		host := dc.acct.host
+		book, err := c.Book(host, base, quote)
+		if err != nil {
+			c.log.Errorf("Error retrieving %s book from %s: %v", marketName(base, quote), host, err)
+			failed[host] = err
+			continue
+		}
+		books[host] = book
+	}
+	agg := aggregateBooks(base, quote, books)
+	for host, err := range failed {
+		agg.Servers = append(agg.Servers, &ServerBookSummary{Host: host, Error: err.Error()})
+	}
+	sort.Slice(agg.Servers, func(i, j int) bool {
+		return agg.Servers[i].Host < agg.Servers[j].Host
+	})
+	return agg, nil
+}
+
+// aggregateBooks combines the books, keyed by host.
+func aggregateBooks(base, quote uint32, books map[string]*OrderBook) *AggregateBook {
+	agg := &AggregateBook{
+		Base:    base,
+		Quote:   quote,
+		Servers: make([]*ServerBookSummary, 0, len(books)),
+	}
+	buys := make(map[string][]*MiniOrder, len(books))
+	sells := make(map[string][]*MiniOrder, len(books))
+	for host, book := range books {
+		summary := &ServerBookSummary{Host: host}
+		if len(book.Buys) > 0 {
+			summary.BestBuy = book.Buys[0].MsgRate
+		}
+		if len(book.Sells) > 0 {
+			summary.BestSell = book.Sells[0].MsgRate
+		}
+		for _, ord := range book.Buys {
+			summary.BuyDepth += ord.QtyAtomic
+		}
+		for _, ord := range book.Sells {
+			summary.SellDepth += ord.QtyAtomic
+		}
+		agg.Servers = append(agg.Servers, summary)
+		buys[host] = book.Buys
+		sells[host] = book.Sells
+	}
+	agg.Buys = aggregateBookSide(buys, false)
+	agg.Sells = aggregateBookSide(sells, true)
+	return agg
+}
+
+// aggregateBookSide combines the orders on one side of the books into levels,
+// sorted best first.
+func aggregateBookSide(sides map[string][]*MiniOrder, sell bool) []*AggregateBookLevel {
+	levels := make(map[uint64]*AggregateBookLevel)
+	for host, ords := range sides {
+		for _, ord := range ords {
+			lvl := levels[ord.MsgRate]
+			if lvl == nil {
+				lvl = &AggregateBookLevel{
+					MsgRate: ord.MsgRate,
+					Rate:    ord.Rate,
+					Hosts:   make(map[string]uint64),
+				}
+				levels[ord.MsgRate] = lvl
+			}
+			lvl.Qty += ord.Qty
+			lvl.QtyAtomic += ord.QtyAtomic
+			lvl.Hosts[host] += ord.QtyAtomic
+		}
+	}
+	side := make([]*AggregateBookLevel, 0, len(levels))
+	for _, lvl := range levels {
+		side = append(side, lvl)
+	}
+	sort.Slice(side, func(i, j int) bool {
+		if sell {
+			return side[i].MsgRate < side[j].MsgRate
+		}
+		return side[i].MsgRate > side[j].MsgRate
+	})
+	return side
+}
+
+// fillEstimate is the result of matching an order against a server's book.
+type fillEstimate struct {
+	host string
+	// base and quote are the quantities that would be exchanged.
+	base  uint64
+	quote uint64
+	// bestRate is the rate of the best order that the order could match, or
+	// zero if that side of the book is empty.
+	bestRate uint64
+}
+
+// better is true if the fill is better for the order than the other fill: more
+// is filled, or the same is filled at a better price. Fills that are otherwise
+// equal are ranked by the best rate on the book, and then by host.
+func (f *fillEstimate) better(o *fillEstimate, sell bool) bool {
+	if f.base != o.base {
+		return f.base > o.base
+	}
+	if f.quote != o.quote {
+		return f.quote > o.quote == sell
+	}
+	if f.bestRate != o.bestRate {
+		switch {
+		case f.bestRate == 0:
+			return false
+		case o.bestRate == 0:
+			return true
+		}
+		return f.bestRate > o.bestRate == sell
+	}
+	return f.host < o.host
+}
+
+// estimateFill matches the order against the book without regard to the lot
+// size. A limit order, or a market order with a WorstRate, only matches book
+// orders at its rate or better.
+func estimateFill(host string, book *OrderBook, form *TradeForm) *fillEstimate {
+	f := &fillEstimate{host: host}
+	ords := book.Sells
+	if form.Sell {
+		ords = book.Buys
+	}
+	if len(ords) > 0 {
+		f.bestRate = ords[0].MsgRate
+	}
+	limitRate := form.WorstRate
+	if form.IsLimit {
+		limitRate = form.Rate
+	}
+	marketBuy := !form.IsLimit && !form.Sell
+	remain := form.Qty
+	for _, ord := range ords {
+		if remain == 0 {
+			break
+		}
+		if limitRate > 0 && (form.Sell && ord.MsgRate < limitRate || !form.Sell && ord.MsgRate > limitRate) {
+			break
+		}
+		if marketBuy {
+			// The quantity of a market buy is in units of the quote asset.
+			if q := calc.BaseToQuote(ord.MsgRate, ord.QtyAtomic); q < remain {
+				f.base += ord.QtyAtomic
+				f.quote += q
+				remain -= q
+				continue
+			}
+			f.base += calc.QuoteToBase(ord.MsgRate, remain)
+			f.quote += remain
+			break
+		}
+		qty := min(ord.QtyAtomic, remain)
+		f.base += qty
+		f.quote += calc.BaseToQuote(ord.MsgRate, qty)
+		remain -= qty
+	}
+	return f
+}
+
+// RouteOrder picks the server to place the order on. Of the servers where the
+// account can trade on the market and the order's quantity and rate are valid,
+// the one where the most of the order would be filled immediately, at the best
+// price, is chosen. The form's Host is ignored.
+func (c *Core) RouteOrder(form *TradeForm) (string, error) {
+	mktID := marketName(form.Base, form.Quote)
+	var best *fillEstimate
+	for _, dc := range c.marketServers(form.Base, form.Quote) {
+		host := dc.acct.host
+		if _, err := c.registeredDEX(host); err != nil || dc.acct.suspended() || !dc.running(mktID) {
+			continue
+		}
+		mktConf := dc.marketConfig(mktID)
+		if (form.IsLimit || form.Sell) && form.Qty%mktConf.LotSize != 0 {
+			continue
+		}
+		if form.IsLimit && form.Rate%mktConf.RateStep != 0 || form.WorstRate%mktConf.RateStep != 0 {
+			continue
+		}
+		if !form.IsLimit && form.WorstRate > 0 && dc.apiVersion() < serverdex.WorstRateAPIVersion {
+			continue
+		}
+		book, err := c.Book(host, form.Base, form.Quote)
+		if err != nil {
+			c.log.Errorf("Error retrieving %s book from %s for order routing: %v", mktID, host, err)
+			continue
+		}
+		if f := estimateFill(host, book, form); best == nil || f.better(best, form.Sell) {
+			best = f
+		}
+	}
+	if best == nil {
+		return "", newError(marketErr, "no server can accept the order on the %s market", mktID)
+	}
+	return best.host, nil
+}
@@ -944,6 +944,9 @@ func (c *Core) monitorBondConfs(dc *dexConnection, bond *asset.Bond, reqConfs ui
 
 // RedeemPrepaidBond redeems a pre-paid bond for a dcrdex host server.
 func (c *Core) RedeemPrepaidBond(appPW []byte, code []byte, host string, certI any) (tier uint64, err error) {
+	if err := c.checkAmnesia("redeem a prepaid bond"); err != nil {
+		return 0, err
+	}
 	// Make sure the app has been initialized.
 	if !c.IsInitialized() {
 		return 0, fmt.Errorf("app not initialized")
@@ -1125,6 +1128,9 @@ func (c *Core) nextBondKey(assetID uint32) (*secp256k1.PrivateKey, uint32, error
 // the target trading tier, the preferred asset to use for bonds, and the
 // maximum amount allowable to be locked in bonds.
 func (c *Core) UpdateBondOptions(form *BondOptionsForm) error {
+	if err := c.checkAmnesia("update bond options"); err != nil {
+		return err
+	}
 	dc, _, err := c.dex(form.Host)
 	if err != nil {
 		return err
@@ -1357,6 +1363,9 @@ func (c *Core) BondsFeeBuffer(assetID uint32) (uint64, error) {
 // to ensure that the wallet reserves the amount reported by a preceding call to
 // BondsFeeBuffer, such as during initial wallet funding.
 func (c *Core) PostBond(form *PostBondForm) (*PostBondResult, error) {
+	if err := c.checkAmnesia("post a bond"); err != nil {
+		return nil, err
+	}
 	// Make sure the app has been initialized.
 	if !c.IsInitialized() {
 		return nil, fmt.Errorf("app not initialized")
@@ -35,6 +35,7 @@ type BookFeed interface {
 	Next() <-chan *BookUpdate
 	Close()
 	Candles(dur string) error
+	Depth(binWidth uint64) (*orderbook.Depth, error)
 }
 
 // bookFeed implements BookFeed.
@@ -62,6 +63,12 @@ func (f *bookFeed) Candles(durStr string) error {
 	return f.bookie.candles(durStr, f.id)
 }
 
+// Depth is the book's depth chart data, with booked quantities binned by rate
+// in bins of width binWidth. See orderbook.(*OrderBook).Depth.
+func (f *bookFeed) Depth(binWidth uint64) (*orderbook.Depth, error) {
+	return f.bookie.Depth(binWidth)
+}
+
 // candleCache adds synchronization and an on/off switch to *candles.Cache.
 type candleCache struct {
 	*candles.Cache
@@ -113,6 +120,13 @@ type bookie struct {
 
 	base, quote           uint32
 	baseUnits, quoteUnits dex.UnitInfo
+
+	// resyncMtx guards the state of a book resync. While missed book updates
+	// are requested with a book_resync request, new book updates are queued in
+	// resyncQueue, to be applied after the missed updates.
+	resyncMtx   sync.Mutex
+	resyncing   bool
+	resyncQueue []*msgjson.Message
 }
 
 func defaultUnitInfo(symbol string) dex.UnitInfo {
@@ -609,8 +623,168 @@ func (b *bookie) translateBookSide(ins []*orderbook.Order) (outs []*MiniOrder) {
 	return
 }
 
+// checkSeq compares the sequence number of a book update notification with the
+// last one applied to the book. If the update was already applied, or if it
+// is queued to be applied after a resync, skip will be true. If there is a
+// gap, the missed updates are requested from the server via the book_resync
+// route in a new goroutine, and the update is queued. Updates received while
+// the resync is in progress are also queued.
+func (b *bookie) checkSeq(c *Core, msg *msgjson.Message, seq uint64) (skip bool) {
+	if seq == 0 {
+		return false
+	}
+	b.resyncMtx.Lock()
+	defer b.resyncMtx.Unlock()
+	if b.resyncing {
+		b.resyncQueue = append(b.resyncQueue, msg)
+		return true
+	}
+	last := b.Seq()
+	switch {
+	case last > 0 && seq <= last:
+		b.log.Debugf("Ignoring stale book update with seq %d, last applied seq is %d", seq, last)
+		return true
+	case seq == last+1:
+		return false
+	}
+
+	b.log.Warnf("Missed book updates %d through %d. Requesting a resync.", last+1, seq-1)
+	b.resyncing = true
+	b.resyncQueue = []*msgjson.Message{msg}
+	go b.resync(c, last)
+	return true
+}
+
+// resync requests the book updates that followed seq from the server and
+// replays them, or resets the book with the snapshot if the server no longer
+// has all of the missed updates. The updates queued during the resync are then
+// applied, skipping any that were covered by the resync. If the resync fails,
+// the error is logged and the queued updates are applied anyway, as they were
+// before gap recovery.
+func (b *bookie) resync(c *Core, seq uint64) {
+	res, err := b.requestResync(seq)
+	b.resyncMtx.Lock()
+	defer b.resyncMtx.Unlock()
+	queue := b.resyncQueue
+	b.resyncing, b.resyncQueue = false, nil
+	if err == nil {
+		err = b.applyResync(c, res)
+	}
+	if err != nil {
+		b.log.Errorf("Failed to resync order book: %v", err)
+	}
+	for _, msg := range queue {
+		var note msgjson.OrderNote
+		if err := msg.Unmarshal(&note); err != nil {
+			b.log.Errorf("Error decoding queued %s book update: %v", msg.Route, err)
+			continue
+		}
+		last := b.Seq()
+		if note.Seq <= last {
+			continue // covered by the resync
+		}
+		if note.Seq > last+1 {
+			b.log.Warnf("Book updates %d through %d are still missing after the resync.", last+1, note.Seq-1)
+		}
+		if err := b.applyUpdate(c, msg); err != nil {
+			b.log.Errorf("Error applying queued %s book update: %v", msg.Route, err)
+		}
+	}
+}
+
+// requestResync sends a book_resync request for the book updates that followed
+// seq.
+func (b *bookie) requestResync(seq uint64) (*msgjson.BookResyncResult, error) {
+	dc := b.dc
+	mktID := marketName(b.base, b.quote)
+	req, err := msgjson.NewRequest(dc.NextID(), msgjson.BookResyncRoute, &msgjson.BookResync{
+		MarketID: mktID,
+		Seq:      seq,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding 'book_resync' request: %w", err)
+	}
+	errChan := make(chan error, 1)
+	res := new(msgjson.BookResyncResult)
+	err = dc.RequestWithTimeout(req, func(msg *msgjson.Message) {
+		errChan <- msg.UnmarshalResult(res)
+	}, DefaultResponseTimeout, func() {
+		errChan <- fmt.Errorf("timed out waiting for '%s' response", msgjson.BookResyncRoute)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error requesting %s book resync: %w", mktID, err)
+	}
+	if err = <-errChan; err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// applyResync resets the book with the resync's snapshot, or replays its
+// updates. resyncMtx must be locked.
+func (b *bookie) applyResync(c *Core, res *msgjson.BookResyncResult) error {
+	dc := b.dc
+	mktID := marketName(b.base, b.quote)
+	if res.Snapshot != nil {
+		if err := b.Reset(res.Snapshot); err != nil {
+			return fmt.Errorf("error resetting %s book: %w", mktID, err)
+		}
+		b.send(&BookUpdate{
+			Action:   FreshBookAction,
+			Host:     dc.acct.host,
+			MarketID: mktID,
+			Payload: &MarketOrderBook{
+				Base:  b.base,
+				Quote: b.quote,
+				Book:  b.book(),
+			},
+		})
+		return nil
+	}
+	for _, msg := range res.Updates {
+		if err := b.applyUpdate(c, msg); err != nil {
+			return fmt.Errorf("error replaying %s book update: %w", msg.Route, err)
+		}
+	}
+	return nil
+}
+
+// applyUpdate applies a sequenced book update notification without checking
+// its sequence number.
+func (b *bookie) applyUpdate(c *Core, msg *msgjson.Message) error {
+	switch msg.Route {
+	case msgjson.BookOrderRoute:
+		note := new(msgjson.BookOrderNote)
+		if err := msg.Unmarshal(note); err != nil {
+			return fmt.Errorf("book order note unmarshal error: %w", err)
+		}
+		return b.applyBookOrder(note)
+	case msgjson.UnbookOrderRoute:
+		note := new(msgjson.UnbookOrderNote)
+		if err := msg.Unmarshal(note); err != nil {
+			return fmt.Errorf("unbook order note unmarshal error: %w", err)
+		}
+		return b.applyUnbookOrder(note)
+	case msgjson.UpdateRemainingRoute:
+		note := new(msgjson.UpdateRemainingNote)
+		if err := msg.Unmarshal(note); err != nil {
+			return fmt.Errorf("update remaining note unmarshal error: %w", err)
+		}
+		return b.applyUpdateRemaining(note)
+	case msgjson.EpochOrderRoute:
+		note := new(msgjson.EpochOrderNote)
+		if err := msg.Unmarshal(note); err != nil {
+			return fmt.Errorf("epoch order note unmarshal error: %w", err)
+		}
+		return b.applyEpochOrder(note)
+	case msgjson.SuspensionRoute:
+		return handleTradeSuspensionMsg(c, b.dc, msg)
+	}
+	return fmt.Errorf("unexpected route %q", msg.Route)
+}
+
 // handleBookOrderMsg is called when a book_order notification is received.
-func handleBookOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error {
+func handleBookOrderMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	note := new(msgjson.BookOrderNote)
 	err := msg.Unmarshal(note)
 	if err != nil {
@@ -622,15 +796,22 @@ func handleBookOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error
 		return fmt.Errorf("no order book found with market id '%v'",
 			note.MarketID)
 	}
-	err = book.Book(note)
-	if err != nil {
+	if book.checkSeq(c, msg, note.Seq) {
+		return nil
+	}
+	return book.applyBookOrder(note)
+}
+
+// applyBookOrder adds the order to the book.
+func (b *bookie) applyBookOrder(note *msgjson.BookOrderNote) error {
+	if err := b.Book(note); err != nil {
 		return err
 	}
-	book.send(&BookUpdate{
+	b.send(&BookUpdate{
 		Action:   BookOrderAction,
-		Host:     dc.acct.host,
+		Host:     b.dc.acct.host,
 		MarketID: note.MarketID,
-		Payload:  book.minifyOrder(note.OrderID, &note.TradeNote, 0),
+		Payload:  b.minifyOrder(note.OrderID, &note.TradeNote, 0),
 	})
 	return nil
 }
@@ -824,8 +1005,24 @@ func (dc *dexConnection) apiVersion() int32 {
 	return atomic.LoadInt32(&dc.apiVer)
 }
 
+// negotiateAPIVersion picks the newest of our supportedAPIVers within the
+// server's advertised range of API versions.
+func negotiateAPIVersion(minVer, maxVer uint16) (int32, error) {
+	for i := len(supportedAPIVers) - 1; i >= 0; i-- {
+		if ver := supportedAPIVers[i]; ver >= int32(minVer) && ver <= int32(maxVer) {
+			return ver, nil
+		}
+	}
+	if maxSupported := supportedAPIVers[len(supportedAPIVers)-1]; int32(minVer) > maxSupported {
+		return 0, fmt.Errorf("server requires API version %d or later, but this client supports up to %d: %w",
+			minVer, maxSupported, outdatedClientErr)
+	}
+	return 0, fmt.Errorf("unsupported server API versions %d to %d", minVer, maxVer)
+}
+
 // refreshServerConfig fetches and replaces server configuration data. It also
-// initially checks that a server's API version is one of serverAPIVers.
+// negotiates the API version from the server's supported range and our
+// supportedAPIVers.
 func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
 	// Fetch the updated DEX configuration.
 	cfg := new(msgjson.ConfigResult)
@@ -834,24 +1031,14 @@ func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
 		return nil, fmt.Errorf("unable to fetch server config: %w", err)
 	}
 
-	apiVer := int32(cfg.APIVersion)
-	dc.log.Infof("Server %v supports API version %v.", dc.acct.host, cfg.APIVersion)
-	atomic.StoreInt32(&dc.apiVer, apiVer)
-
 	// Check that we are able to communicate with this DEX.
-	var supported bool
-	for _, ver := range supportedAPIVers {
-		if apiVer == ver {
-			supported = true
-		}
-	}
-	if !supported {
-		err := fmt.Errorf("unsupported server API version %v", apiVer)
-		if apiVer > supportedAPIVers[len(supportedAPIVers)-1] {
-			err = fmt.Errorf("%v: %w", err, outdatedClientErr)
-		}
+	apiVer, err := negotiateAPIVersion(cfg.MinAPIVersion, cfg.APIVersion)
+	if err != nil {
 		return nil, err
 	}
+	dc.log.Infof("Server %v supports API versions %d to %d. Using version %d.",
+		dc.acct.host, cfg.MinAPIVersion, cfg.APIVersion, apiVer)
+	atomic.StoreInt32(&dc.apiVer, apiVer)
 
 	bTimeout := time.Millisecond * time.Duration(cfg.BroadcastTimeout)
 	tickInterval := bTimeout / tickCheckDivisions
@@ -943,7 +1130,7 @@ func handlePriceUpdateNote(c *Core, dc *dexConnection, msg *msgjson.Message) err
 
 // handleUnbookOrderMsg is called when an unbook_order notification is
 // received.
-func handleUnbookOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error {
+func handleUnbookOrderMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	note := new(msgjson.UnbookOrderNote)
 	err := msg.Unmarshal(note)
 	if err != nil {
@@ -955,23 +1142,29 @@ func handleUnbookOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) erro
 		return fmt.Errorf("no order book found with market id %q",
 			note.MarketID)
 	}
-	err = book.Unbook(note)
-	if err != nil {
+	if book.checkSeq(c, msg, note.Seq) {
+		return nil
+	}
+	return book.applyUnbookOrder(note)
+}
+
+// applyUnbookOrder removes the order from the book.
+func (b *bookie) applyUnbookOrder(note *msgjson.UnbookOrderNote) error {
+	if err := b.Unbook(note); err != nil {
 		return err
 	}
-	book.send(&BookUpdate{
+	b.send(&BookUpdate{
 		Action:   UnbookOrderAction,
-		Host:     dc.acct.host,
+		Host:     b.dc.acct.host,
 		MarketID: note.MarketID,
 		Payload:  &MiniOrder{Token: token(note.OrderID)},
 	})
-
 	return nil
 }
 
 // handleUpdateRemainingMsg is called when an update_remaining notification is
 // received.
-func handleUpdateRemainingMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error {
+func handleUpdateRemainingMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	note := new(msgjson.UpdateRemainingNote)
 	err := msg.Unmarshal(note)
 	if err != nil {
@@ -983,17 +1176,24 @@ func handleUpdateRemainingMsg(_ *Core, dc *dexConnection, msg *msgjson.Message)
 		return fmt.Errorf("no order book found with market id '%v'",
 			note.MarketID)
 	}
-	err = book.UpdateRemaining(note)
-	if err != nil {
+	if book.checkSeq(c, msg, note.Seq) {
+		return nil
+	}
+	return book.applyUpdateRemaining(note)
+}
+
+// applyUpdateRemaining updates the remaining quantity of a booked order.
+func (b *bookie) applyUpdateRemaining(note *msgjson.UpdateRemainingNote) error {
+	if err := b.UpdateRemaining(note); err != nil {
 		return err
 	}
-	book.send(&BookUpdate{
+	b.send(&BookUpdate{
 		Action:   UpdateRemainingAction,
-		Host:     dc.acct.host,
+		Host:     b.dc.acct.host,
 		MarketID: note.MarketID,
 		Payload: &RemainderUpdate{
 			Token:     token(note.OrderID),
-			Qty:       float64(note.Remaining) / float64(book.baseUnits.Conventional.ConversionFactor),
+			Qty:       float64(note.Remaining) / float64(b.baseUnits.Conventional.ConversionFactor),
 			QtyAtomic: note.Remaining,
 		},
 	})
@@ -1022,7 +1222,7 @@ func handleEpochReportMsg(c *Core, dc *dexConnection, msg *msgjson.Message) erro
 
 // handleEpochOrderMsg is called when an epoch_order notification is
 // received.
-func handleEpochOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error {
+func handleEpochOrderMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	note := new(msgjson.EpochOrderNote)
 	err := msg.Unmarshal(note)
 	if err != nil {
@@ -1034,19 +1234,24 @@ func handleEpochOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error
 		return fmt.Errorf("no order book found with market id %q",
 			note.MarketID)
 	}
+	if book.checkSeq(c, msg, note.Seq) {
+		return nil
+	}
+	return book.applyEpochOrder(note)
+}
 
-	err = book.Enqueue(note)
-	if err != nil {
+// applyEpochOrder adds the order to the epoch queue.
+func (b *bookie) applyEpochOrder(note *msgjson.EpochOrderNote) error {
+	if err := b.Enqueue(note); err != nil {
 		return fmt.Errorf("failed to Enqueue epoch order: %w", err)
 	}
 
 	// Send a MiniOrder for book updates.
-	book.send(&BookUpdate{
+	b.send(&BookUpdate{
 		Action:   EpochOrderAction,
-		Host:     dc.acct.host,
+		Host:     b.dc.acct.host,
 		MarketID: note.MarketID,
-		Payload:  book.minifyOrder(note.OrderID, &note.TradeNote, note.Epoch),
+		Payload:  b.minifyOrder(note.OrderID, &note.TradeNote, note.Epoch),
 	})
-
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -31,6 +32,8 @@ import (
 	"decred.org/dcrdex/client/comms"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/db/bolt"
+	"decred.org/dcrdex/client/db/memory"
+	"decred.org/dcrdex/client/intl"
 	"decred.org/dcrdex/client/mnemonic"
 	"decred.org/dcrdex/client/orderbook"
 	"decred.org/dcrdex/dex"
@@ -41,6 +44,7 @@ import (
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/dex/wait"
+	"decred.org/dcrdex/dex/webhook"
 	"decred.org/dcrdex/server/account"
 	serverdex "decred.org/dcrdex/server/dex"
 	"github.com/decred/dcrd/crypto/blake256"
@@ -108,7 +112,7 @@ var (
 	// NOTE: API version may change at any time. Keep this in mind when
 	// updating the API. Long-running operations may start and end with
 	// differing versions.
-	supportedAPIVers = []int32{serverdex.V1APIVersion}
+	supportedAPIVers = []int32{serverdex.V1APIVersion, serverdex.WorstRateAPIVersion, serverdex.CancelTargetAPIVersion}
 	// ActiveOrdersLogoutErr is returned from logout when there are active
 	// orders.
 	ActiveOrdersLogoutErr = errors.New("cannot log out with active orders")
@@ -290,6 +294,21 @@ func (dc *dexConnection) assetConfig(assetID uint32) *dex.Asset {
 	return dc.assets[assetID]
 }
 
+// swapConf is the number of confirmations required for swaps of the asset on
+// the market. The market config may override the asset's SwapConf. Zero is
+// returned if the server's asset config is unknown. mktConf may be nil.
+func (dc *dexConnection) swapConf(mktConf *msgjson.Market, assetID uint32) uint32 {
+	if mktConf != nil {
+		if swapConf := mktConf.SwapConf(assetID); swapConf > 0 {
+			return swapConf
+		}
+	}
+	if asset := dc.assetConfig(assetID); asset != nil {
+		return asset.SwapConf
+	}
+	return 0
+}
+
 // marketMap creates a map of this DEX's *Market keyed by name/ID,
 // [base]_[quote].
 func (dc *dexConnection) marketMap() map[string]*Market {
@@ -368,6 +387,7 @@ func coreMarketFromMsgMarket(dc *dexConnection, msgMkt *msgjson.Market) *Market
 		MarketBuyBuffer: msgMkt.MarketBuyBuffer,
 		AtomToConv:      float64(bconv) / float64(qconv),
 		MinimumRate:     dc.minimumMarketRate(quote, msgMkt.LotSize),
+		DisplayBand:     msgMkt.DisplayBand * msgMkt.LotSize,
 	}
 
 	trades, inFlight := dc.marketTrades(mkt.marketName())
@@ -655,7 +675,7 @@ func (c *Core) sendCancelOrder(dc *dexConnection, oid order.OrderID, base, quote
 		c.sentCommitsMtx.Lock()
 		delete(c.sentCommits, co.Commit)
 		c.sentCommitsMtx.Unlock()
-		return preImg, nil, nil, nil, fmt.Errorf("failed to submit cancel order targeting trade %v: %w", oid, err)
+		return preImg, nil, nil, nil, c.translateServerError(fmt.Errorf("failed to submit cancel order targeting trade %v: %w", oid, err))
 	}
 	err = validateOrderResponse(dc, result, co, msgOrder)
 	if err != nil {
@@ -1434,7 +1454,9 @@ type Config struct {
 	Logger dex.Logger
 	// Onion is the address (host:port) of a Tor proxy for use with DEX hosts
 	// with a .onion address. To use Tor with regular DEX addresses as well, set
-	// TorProxy.
+	// TorProxy. Connections to .onion hosts are plain ws, unless the host was
+	// added with the wss:// scheme, in which case TLS is used with the host's
+	// certificate pinned.
 	Onion string
 	// TorProxy specifies the address of a Tor proxy server.
 	TorProxy string
@@ -1453,6 +1475,16 @@ type Config struct {
 	// on shutdown. This is useful if the consumer is using the BackupDB method,
 	// or simply creating manual backups of the DB file after shutdown.
 	NoAutoDBBackup bool // zero value is legacy behavior
+	// DBSnapshotInterval is how often a checksummed snapshot of the DB is
+	// saved. Snapshots can be listed with DBSnapshots and restored with
+	// RestoreDBSnapshot. Zero disables snapshots.
+	DBSnapshotInterval time.Duration
+	// DBSnapshotsRetained is the number of DB snapshots kept. Zero means the
+	// DB's default.
+	DBSnapshotsRetained int
+	// VerifyDBOnStart runs a consistency check of the DB file on startup.
+	// NewCore fails if the DB is corrupt.
+	VerifyDBOnStart bool
 	// UnlockCoinsOnLogin indicates that on wallet connect during login, or on
 	// creation of a new wallet, all coins with the wallet should be unlocked.
 	UnlockCoinsOnLogin bool
@@ -1460,6 +1492,18 @@ type Config struct {
 	// for running core in extension mode, which gives the caller options for
 	// e.g. limiting the ability to configure wallets.
 	ExtensionModeFile string
+	// Amnesia instructs Core to keep all state in memory instead of in the
+	// database at DBPath. Nothing, including the app seed, survives a
+	// restart. Amnesia mode is only permitted on testnet and simnet, and is
+	// limited to view-only operation, so it is suitable for demos and kiosk
+	// displays on shared machines.
+	Amnesia bool
+	// Webhooks are http or https URLs to which events such as filled orders
+	// and failed swaps are POSTed. See the Webhook* event types.
+	Webhooks []string
+	// WebhookSecret is the key for the HMAC-SHA256 signature of webhook
+	// requests. It is required if Webhooks are set.
+	WebhookSecret string
 
 	TheOneHost string
 }
@@ -1468,6 +1512,7 @@ type Config struct {
 type locale struct {
 	lang    language.Tag
 	m       map[Topic]*translation
+	errs    map[int]*intl.Translation // server error messages
 	printer *message.Printer
 }
 
@@ -1535,6 +1580,13 @@ type Core struct {
 
 	requestedActionMtx sync.RWMutex
 	requestedActions   map[string]*asset.ActionRequiredNote
+
+	// addrBookMtx serializes address book modifications.
+	addrBookMtx sync.Mutex
+
+	// webhooks delivers events to the configured webhook URLs. nil if none
+	// are configured.
+	webhooks *webhook.Notifier
 }
 
 // New is the constructor for a new Core.
@@ -1542,12 +1594,28 @@ func New(cfg *Config) (*Core, error) {
 	if cfg.Logger == nil {
 		return nil, fmt.Errorf("Core.Config must specify a Logger")
 	}
-	dbOpts := bolt.Opts{
-		BackupOnShutdown: !cfg.NoAutoDBBackup,
-	}
-	boltDB, err := bolt.NewDB(cfg.DBPath, cfg.Logger.SubLogger("DB"), dbOpts)
+	webhooks, err := newWebhookNotifier(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("database initialization error: %w", err)
+		return nil, fmt.Errorf("webhook configuration error: %w", err)
+	}
+	var clientDB db.DB
+	if cfg.Amnesia {
+		if cfg.Net == dex.Mainnet {
+			return nil, errors.New("amnesia mode is not permitted on mainnet")
+		}
+		cfg.Logger.Infof("Amnesia mode: all data will be kept in memory and discarded on shutdown")
+		clientDB = memory.NewDB(cfg.Logger.SubLogger("DB"))
+	} else {
+		dbOpts := bolt.Opts{
+			BackupOnShutdown:  !cfg.NoAutoDBBackup,
+			SnapshotInterval:  cfg.DBSnapshotInterval,
+			SnapshotsRetained: cfg.DBSnapshotsRetained,
+			VerifyOnStart:     cfg.VerifyDBOnStart,
+		}
+		clientDB, err = bolt.NewDB(cfg.DBPath, cfg.Logger.SubLogger("DB"), dbOpts)
+		if err != nil {
+			return nil, fmt.Errorf("database initialization error: %w", err)
+		}
 	}
 	if cfg.TorProxy != "" {
 		if _, _, err = net.SplitHostPort(cfg.TorProxy); err != nil {
@@ -1591,7 +1659,7 @@ func New(cfg *Config) (*Core, error) {
 	lang := language.Und
 
 	// Check if the user has set a language with SetLanguage.
-	if langStr, err := boltDB.Language(); err != nil {
+	if langStr, err := clientDB.Language(); err != nil {
 		cfg.Logger.Errorf("Error loading language from database: %v", err)
 	} else if len(langStr) > 0 {
 		if lang, err = parseLanguage(langStr); err != nil {
@@ -1621,12 +1689,12 @@ func New(cfg *Config) (*Core, error) {
 
 	// Try to get the primary credentials, but ignore no-credentials error here
 	// because the client may not be initialized.
-	creds, err := boltDB.PrimaryCredentials()
+	creds, err := clientDB.PrimaryCredentials()
 	if err != nil && !errors.Is(err, db.ErrNoCredentials) {
 		return nil, err
 	}
 
-	seedGenerationTime, err := boltDB.SeedGenerationTime()
+	seedGenerationTime, err := clientDB.SeedGenerationTime()
 	if err != nil && !errors.Is(err, db.ErrNoSeedGenTime) {
 		return nil, err
 	}
@@ -1648,7 +1716,7 @@ func New(cfg *Config) (*Core, error) {
 		ready:         make(chan struct{}),
 		rotate:        make(chan struct{}, 1),
 		log:           cfg.Logger,
-		db:            boltDB,
+		db:            clientDB,
 		conns:         make(map[string]*dexConnection),
 		wallets:       make(map[uint32]*xcWallet),
 		net:           cfg.Net,
@@ -1673,11 +1741,13 @@ func New(cfg *Config) (*Core, error) {
 
 		notes:            make(chan asset.WalletNotification, 128),
 		requestedActions: make(map[string]*asset.ActionRequiredNote),
+		webhooks:         webhooks,
 	}
 
 	c.intl.Store(&locale{
 		lang:    lang,
 		m:       translations,
+		errs:    serverErrorLocales[lang.String()],
 		printer: message.NewPrinter(lang),
 	})
 
@@ -1715,6 +1785,14 @@ func (c *Core) Run(ctx context.Context) {
 		c.latencyQ.Run(ctx)
 	}()
 
+	if c.webhooks != nil {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.webhooks.Run(ctx)
+		}()
+	}
+
 	// Retrieve disabled fiat rate sources from database.
 	disabledSources, err := c.db.DisabledRateSources()
 	if err != nil {
@@ -1865,6 +1943,7 @@ func (c *Core) SetLanguage(lang string) error {
 	}
 	c.intl.Store(&locale{
 		m:       translations,
+		errs:    serverErrorLocales[lang],
 		printer: message.NewPrinter(tag),
 	})
 	return nil
@@ -1881,11 +1960,47 @@ func (c *Core) BackupDB(dst string, overwrite, compact bool) error {
 	return c.db.BackupTo(dst, overwrite, compact)
 }
 
+// DBSnapshots lists the saved DB snapshots, newest first. The Valid field of
+// each snapshot indicates whether the snapshot file matches its checksum.
+func (c *Core) DBSnapshots() ([]*db.Snapshot, error) {
+	return c.db.Snapshots()
+}
+
+// RestoreDBSnapshot stages the named DB snapshot to replace the database on
+// the next startup. The app password is required. A snapshot cannot be
+// restored while there are active orders, since the snapshot would not have
+// the latest state of their swaps, which is needed to redeem or refund.
+func (c *Core) RestoreDBSnapshot(pw []byte, name string) error {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return codedError(passwordErr, err)
+	}
+	crypter.Close()
+	if c.Active() {
+		return newError(activeOrdersErr, "cannot restore a DB snapshot with active orders")
+	}
+	return c.db.RestoreSnapshot(name)
+}
+
 const defaultDEXPort = "7232"
 
-// addrHost returns the host or url:port pair for an address.
+// onionTLSScheme is the prefix of a .onion host that is served with TLS. By
+// default, connections to .onion hosts are plain ws, since Tor encrypts and
+// authenticates them.
+const onionTLSScheme = "wss://"
+
+// addrHost returns the host or url:port pair for an address. The wss:// scheme
+// of a .onion address is retained, since it indicates that the hidden service
+// uses TLS.
 func addrHost(addr string) (string, error) {
 	addr = strings.TrimSpace(addr)
+	if strings.HasPrefix(addr, onionTLSScheme) {
+		host, err := addrHost(strings.TrimPrefix(addr, onionTLSScheme))
+		if err != nil || !isOnionHost(host) {
+			return host, err
+		}
+		return onionTLSScheme + host, nil
+	}
 	const defaultHost = "localhost"
 	const missingPort = "missing port in address"
 	// Empty addresses are localhost.
@@ -2552,6 +2667,7 @@ func (c *Core) User() *User {
 		FiatRates:          c.fiatConversions(),
 		Net:                c.net,
 		ExtensionConfig:    c.extensionModeConfig,
+		Amnesia:            c.cfg.Amnesia,
 		Actions:            c.requestedActionsList(),
 	}
 }
@@ -2568,6 +2684,9 @@ func (c *Core) requestedActionsList() []*asset.ActionRequiredNote {
 
 // CreateWallet creates a new exchange wallet.
 func (c *Core) CreateWallet(appPW, walletPW []byte, form *WalletForm) error {
+	if err := c.checkAmnesia("create a wallet"); err != nil {
+		return err
+	}
 	assetID := form.AssetID
 	symbol := unbip(assetID)
 	_, exists := c.wallet(assetID)
@@ -3207,7 +3326,9 @@ func (c *Core) startWalletSyncMonitor(wallet *xcWallet) {
 // this will check for active orders involving this asset before initiating a
 // rescan. WARNING: It is ill-advised to initiate a wallet rescan with active
 // orders unless as a last ditch effort to get the wallet to recognize a
-// transaction needed to complete a swap.
+// transaction needed to complete a swap. When the rescan is complete, trades
+// funded by the wallet whose funding coins could not be loaded are checked
+// again.
 func (c *Core) RescanWallet(assetID uint32, force bool) error {
 	if !force && c.walletIsActive(assetID) {
 		return newError(activeOrdersErr, "active orders or registration fee payments for %v", unbip(assetID))
@@ -3237,12 +3358,13 @@ func (c *Core) RescanWallet(assetID uint32, force bool) error {
 		return err
 	}
 
-	if c.walletCheckAndNotify(wallet) {
-		return nil // sync done, Rescan may have by synchronous or a no-op
-	}
+	subject, details := c.formatDetails(TopicWalletRescanStarted, unbip(assetID))
+	c.notify(newWalletConfigNote(TopicWalletRescanStarted, subject, details, db.Poke, wallet.state()))
 
-	// Synchronization still running. Launch a status update goroutine.
-	c.startWalletSyncMonitor(wallet)
+	// Emit sync notifications until the rescan is done, which may be right
+	// away if it was synchronous or a no-op, and then look again for funding
+	// coins that were missing.
+	c.monitorRescan(wallet)
 
 	return nil
 }
@@ -3542,6 +3664,9 @@ func (c *Core) ResetAppPass(newPass []byte, seedStr string) (err error) {
 // the password if newWalletPW is non-nil. Do not make concurrent calls to
 // ReconfigureWallet for the same asset.
 func (c *Core) ReconfigureWallet(appPW, newWalletPW []byte, form *WalletForm) error {
+	if err := c.checkAmnesia("reconfigure a wallet"); err != nil {
+		return err
+	}
 	crypter, err := c.encryptionKey(appPW)
 	if err != nil {
 		return newError(authErr, "ReconfigureWallet password error: %w", err)
@@ -3877,6 +4002,9 @@ func (c *Core) updateAssetWalletRefs(newWallet *xcWallet) {
 // passwordErr if provided newPW is nil. The wallet will be connected if it is
 // not already.
 func (c *Core) SetWalletPassword(appPW []byte, assetID uint32, newPW []byte) error {
+	if err := c.checkAmnesia("set a wallet password"); err != nil {
+		return err
+	}
 	// Ensure newPW isn't nil.
 	if newPW == nil {
 		return newError(passwordErr, "SetWalletPassword password can't be nil")
@@ -3927,6 +4055,7 @@ func (c *Core) setWalletPassword(wallet *xcWallet, newPW []byte, crypter encrypt
 
 	wasUnlocked := wallet.unlocked()
 	newPasswordSet := len(newPW) > 0 // excludes empty but non-nil
+	oldEncPW := wallet.encPW()
 
 	// Check that the new password works.
 	if newPasswordSet {
@@ -3961,6 +4090,14 @@ func (c *Core) setWalletPassword(wallet *xcWallet, newPW []byte, crypter encrypt
 
 	err = c.db.SetWalletPassword(wallet.dbID, wallet.encPW())
 	if err != nil {
+		// Restore the stored password so that the wallet's state matches the
+		// DB.
+		wallet.setEncPW(oldEncPW)
+		if !wasUnlocked && newPasswordSet {
+			if err := wallet.Lock(2 * time.Second); err != nil {
+				c.log.Warnf("Unable to relock %s wallet: %v", unbip(wallet.AssetID), err)
+			}
+		}
 		return codedError(dbErr, err)
 	}
 
@@ -4082,6 +4219,9 @@ func (c *Core) GetDEXConfig(dexAddr string, certI any) (*Exchange, error) {
 // with the DEX. DiscoverAccount, PostBond may be used to set up a trading
 // account for this DEX if required.
 func (c *Core) AddDEX(appPW []byte, dexAddr string, certI any) error {
+	if err := c.checkAmnesia("add a DEX"); err != nil {
+		return err
+	}
 	if !c.IsInitialized() { // TODO: Allow adding view-only DEX without init.
 		return fmt.Errorf("cannot register DEX because app has not been initialized")
 	}
@@ -4310,6 +4450,9 @@ func (c *Core) upgradeConnection(dc *dexConnection) {
 // necessary to PostBond (i.e. Tier == 0 && !BondsPending) before trading. The
 // Connected field should be consulted first.
 func (c *Core) DiscoverAccount(dexAddr string, appPW []byte, certI any) (*Exchange, bool, error) {
+	if err := c.checkAmnesia("discover an account"); err != nil {
+		return nil, false, err
+	}
 	if !c.IsInitialized() {
 		return nil, false, fmt.Errorf("cannot register DEX because app has not been initialized")
 	}
@@ -4932,6 +5075,9 @@ func (c *Core) Orders(filter *OrderFilter) ([]*Order, error) {
 		Assets:   filter.Assets,
 		Market:   mkt,
 		Statuses: filter.Statuses,
+		Sell:     filter.Sell,
+		Since:    filter.Since,
+		Until:    filter.Until,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("UserOrders error: %w", err)
@@ -5344,6 +5490,9 @@ func (c *Core) feeSuggestion(dc *dexConnection, assetID uint32) (feeSuggestion u
 // is true, fees are subtracted from the value else fees are taken from the
 // exchange wallet.
 func (c *Core) Send(pw []byte, assetID uint32, value uint64, address string, subtract bool) (asset.Coin, error) {
+	if err := c.checkAmnesia("send funds"); err != nil {
+		return nil, err
+	}
 	var crypter encrypt.Crypter
 	// Empty password can be provided if wallet is already unlocked. Webserver
 	// and RPCServer should not allow empty password, but this is used for
@@ -5360,6 +5509,9 @@ func (c *Core) Send(pw []byte, assetID uint32, value uint64, address string, sub
 	if value == 0 {
 		return nil, fmt.Errorf("cannot send/withdraw zero %s", unbip(assetID))
 	}
+	if err := c.checkWithdrawalAddress(crypter, assetID, address); err != nil {
+		return nil, err
+	}
 	wallet, found := c.wallet(assetID)
 	if !found {
 		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
@@ -5414,6 +5566,9 @@ func (c *Core) ValidateAddress(address string, assetID uint32) (bool, error) {
 // ApproveToken calls a wallet's ApproveToken method. It approves the version
 // of the token used by the dex at the specified address.
 func (c *Core) ApproveToken(appPW []byte, assetID uint32, dexAddr string, onConfirm func()) (string, error) {
+	if err := c.checkAmnesia("approve a token"); err != nil {
+		return "", err
+	}
 	crypter, err := c.encryptionKey(appPW)
 	if err != nil {
 		return "", err
@@ -5743,9 +5898,35 @@ func (c *Core) PreOrder(form *TradeForm) (*OrderEstimate, error) {
 		return nil, fmt.Errorf("error getting redemption estimate: %v", err)
 	}
 
+	maxEstimate, err := wallets.fromWallet.MaxOrder(&asset.MaxOrderForm{
+		LotSize:       swapLotSize,
+		FeeSuggestion: swapFeeSuggestion,
+		AssetVersion:  assetConfigs.fromAsset.Version,
+		MaxFeeRate:    assetConfigs.fromAsset.MaxFeeRate,
+		RedeemVersion: assetConfigs.toAsset.Version,
+		RedeemAssetID: assetConfigs.toAsset.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting max order estimate: %w", err)
+	}
+
+	fromID := wallets.fromWallet.AssetID
+	feeID := fromID
+	if token := asset.TokenInfo(fromID); token != nil {
+		feeID = token.ParentID
+	}
+	locked := map[uint32]uint64{fromID: swapEstimate.Estimate.Value}
+	locked[feeID] += swapEstimate.Estimate.MaxFees
+
 	return &OrderEstimate{
-		Swap:   swapEstimate,
-		Redeem: redeemEstimate,
+		Swap:      swapEstimate,
+		Redeem:    redeemEstimate,
+		Liquidity: dc.evaluateLiquidity(form),
+		Lots:      lots,
+		Qty:       lots * lotSize,
+		MaxLots:   maxEstimate.Lots,
+		MaxQty:    maxEstimate.Lots * lotSize,
+		Locked:    locked,
 	}, nil
 }
 
@@ -5895,6 +6076,10 @@ func (c *Core) prepareForTradeRequestPrep(pw []byte, base, quote uint32, host st
 		return nil, nil, nil, nil, err
 	}
 
+	if err := c.checkAmnesia("trade"); err != nil {
+		return fail(err)
+	}
+
 	// Check the user password. A Trade can be attempted with an empty password,
 	// which should work if both wallets are unlocked. We use this feature for
 	// bots.
@@ -6030,6 +6215,7 @@ func (c *Core) createTradeRequest(wallets *walletSet, coins asset.Coins, redeemS
 				Quantity: form.Qty,
 				Address:  redeemAddr,
 			},
+			WorstRate: form.WorstRate,
 		}
 	}
 
@@ -6115,8 +6301,8 @@ func (c *Core) createTradeRequest(wallets *walletSet, coins asset.Coins, redeemS
 		MetaData: &db.OrderMetaData{
 			Host:               dc.acct.host,
 			EpochDur:           mktConf.EpochLen, // epochIndex := result.ServerTime / mktConf.EpochLen
-			FromSwapConf:       assetConfigs.fromAsset.SwapConf,
-			ToSwapConf:         assetConfigs.toAsset.SwapConf,
+			FromSwapConf:       dc.swapConf(mktConf, assetConfigs.fromAsset.ID),
+			ToSwapConf:         dc.swapConf(mktConf, assetConfigs.toAsset.ID),
 			MaxFeeRate:         assetConfigs.fromAsset.MaxFeeRate,
 			RedeemMaxFeeRate:   assetConfigs.toAsset.MaxFeeRate,
 			FromVersion:        assetConfigs.fromAsset.Version,
@@ -6126,9 +6312,20 @@ func (c *Core) createTradeRequest(wallets *walletSet, coins asset.Coins, redeemS
 			RefundReserves:     refundReserves,
 			ChangeCoin:         changeID,
 			FundingFeesPaid:    fundingFees,
+			TTL:                form.TTL,
+			MaxDrift:           form.MaxDrift,
 		},
 		Order: ord,
 	}
+	if fundingFees > 0 && len(coinIDs) > 0 {
+		// The funding coins are outputs of the split tx.
+		dbOrder.MetaData.FeeTxs = []*db.OrderFeeTx{{
+			Type:   db.FeeTxFunding,
+			CoinID: coinIDs[0],
+			Fees:   fundingFees,
+			Stamp:  uint64(time.Now().UnixMilli()),
+		}}
+	}
 
 	return &tradeRequest{
 		mktID:        marketName(form.Base, form.Quote),
@@ -6148,6 +6345,16 @@ func (c *Core) createTradeRequest(wallets *walletSet, coins asset.Coins, redeemS
 
 // prepareTradeRequest prepares a trade request.
 func (c *Core) prepareTradeRequest(pw []byte, form *TradeForm) (*tradeRequest, error) {
+	if form.Route {
+		host, err := c.RouteOrder(form)
+		if err != nil {
+			return nil, err
+		}
+		routed := *form
+		routed.Host = host
+		form = &routed
+	}
+
 	wallets, assetConfigs, dc, mktConf, err := c.prepareForTradeRequestPrep(pw, form.Base, form.Quote, form.Host, form.Sell)
 	if err != nil {
 		return nil, err
@@ -6158,12 +6365,20 @@ func (c *Core) prepareTradeRequest(pw []byte, form *TradeForm) (*tradeRequest, e
 
 	rate, qty := form.Rate, form.Qty
 	if form.IsLimit {
-		if rate == 0 {
-			return nil, newError(orderParamsErr, "zero-rate order not allowed")
-		}
-		if minRate := dc.minimumMarketRate(assetConfigs.quoteAsset, mktConf.LotSize); rate < minRate {
-			return nil, newError(orderParamsErr, "order's rate is lower than market's minimum rate. %d < %d", rate, minRate)
+		if err := checkLimitRate(dc, assetConfigs.quoteAsset, mktConf, rate); err != nil {
+			return nil, err
 		}
+	} else if err := checkWorstRate(dc, mktConf, form.WorstRate); err != nil {
+		return nil, err
+	}
+	if err := checkOrderQty(form, mktConf.LotSize); err != nil {
+		return nil, err
+	}
+	if err := checkAutoCancel(form); err != nil {
+		return nil, err
+	}
+	if err := checkLiquidity(dc, form); err != nil {
+		return nil, err
 	}
 
 	// Get an address for the swap contract.
@@ -6203,28 +6418,16 @@ func (c *Core) prepareTradeRequest(pw []byte, form *TradeForm) (*tradeRequest, e
 			// An error is only returned when there are no orders on the book.
 			// In that case, fall back to the 1 lot estimate for now.
 			if err == nil {
-				baseQty := calc.QuoteToBase(midGap, fundQty)
-				lots = baseQty / lotSize
-				redemptionRefundLots = lots * marketBuyRedemptionSlippageBuffer
-				if lots == 0 {
-					err = newError(orderParamsErr,
-						"order quantity is too low for current market rates. "+
-							"qty = %d %s, mid-gap = %d, base-qty = %d %s, lot size = %d",
-						qty, assetConfigs.quoteAsset.Symbol, midGap, baseQty,
-						assetConfigs.baseAsset.Symbol, lotSize)
+				if lots, err = marketBuyLots(fundQty, midGap, lotSize); err != nil {
 					return nil, err
 				}
+				redemptionRefundLots = lots * marketBuyRedemptionSlippageBuffer
 			} else if isAccountRedemption {
 				return nil, newError(orderParamsErr, "cannot estimate redemption count")
 			}
 		}
 	}
 
-	if lots == 0 {
-		return nil, newError(orderParamsErr, "order quantity < 1 lot. qty = %d %s, rate = %d, lot size = %d",
-			qty, assetConfigs.baseAsset.Symbol, rate, mktConf.LotSize)
-	}
-
 	coins, redeemScripts, fundingFees, err := fromWallet.FundOrder(&asset.Order{
 		Version:       assetConfigs.fromAsset.Version,
 		Value:         fundQty,
@@ -6403,7 +6606,7 @@ func (c *Core) sendTradeRequest(tr *tradeRequest) (*Order, error) {
 		// and created the trade order, but we lost the connection before
 		// receiving the response with the trade's order ID. Any preimage
 		// request will be unrecognized. This order is ABANDONED.
-		return nil, fmt.Errorf("new order request with DEX server %v market %v failed: %w", dc.acct.host, mktID, err)
+		return nil, c.translateServerError(fmt.Errorf("new order request with DEX server %v market %v failed: %w", dc.acct.host, mktID, err))
 	}
 
 	ord := dbOrder.Order
@@ -6753,7 +6956,7 @@ func (c *Core) authDEX(dc *dexConnection) error {
 	acctID := dc.acct.ID()
 	payload := &msgjson.Connect{
 		AccountID:  acctID[:],
-		APIVersion: 0,
+		APIVersion: uint16(dc.apiVersion()),
 		Time:       uint64(time.Now().UnixMilli()),
 	}
 	sigMsg := payload.Serialize()
@@ -6795,6 +6998,12 @@ func (c *Core) authDEX(dc *dexConnection) error {
 			c.monitorBondConfs(dc, assetBond(dbBond), bondAsset.Confs)
 		}
 	}
+	// OutdatedClientError indicates that the server has retired our API
+	// version, and the user needs to upgrade.
+	if errors.As(err, &mErr) && mErr.Code == msgjson.OutdatedClientError {
+		sendOutdatedClientNotification(c, dc)
+		return fmt.Errorf("'connect' error: %w: %w", outdatedClientErr, err)
+	}
 	if err != nil {
 		return fmt.Errorf("'connect' error: %w", err)
 	}
@@ -6805,6 +7014,16 @@ func (c *Core) authDEX(dc *dexConnection) error {
 		return newError(signatureErr, "DEX signature validation error: %w", err)
 	}
 
+	// The server confirms the API version for this connection. Servers that
+	// predate negotiation don't, but they only speak the version we requested.
+	if result.APIVersion != 0 && int32(result.APIVersion) != dc.apiVersion() {
+		if _, err := negotiateAPIVersion(result.APIVersion, result.APIVersion); err != nil {
+			return fmt.Errorf("server negotiated API version %d: %w", result.APIVersion, err)
+		}
+		c.log.Warnf("Server %s negotiated API version %d instead of %d", dc.acct.host, result.APIVersion, dc.apiVersion())
+		atomic.StoreInt32(&dc.apiVer, int32(result.APIVersion))
+	}
+
 	// Check active and pending bonds, comparing against result.ActiveBonds. For
 	// pendingBonds, rebroadcast and start waiter to postBond. For
 	// (locally-confirmed) bonds that are not in connectResp.Bonds, postBond.
@@ -7302,15 +7521,6 @@ func (c *Core) dbTrackers(dc *dexConnection) (map[order.OrderID]*trackedTrade, e
 		}
 	}
 
-	// For older orders, we'll attempt to get the SwapConf from the server's
-	// asset config. Newer orders will have it stored in the DB.
-	assetSwapConf := func(assetID uint32) uint32 {
-		if asset := dc.assetConfig(assetID); asset != nil {
-			return asset.SwapConf
-		}
-		return 0 // server may be gone
-	}
-
 	trackers := make(map[order.OrderID]*trackedTrade, len(dbOrders))
 	excludeCancelMatches := true
 	for _, dbOrder := range dbOrders {
@@ -7323,7 +7533,8 @@ func (c *Core) dbTrackers(dc *dexConnection) (map[order.OrderID]*trackedTrade, e
 		}
 
 		mktID := marketName(ord.Base(), ord.Quote())
-		if mktConf := dc.marketConfig(mktID); mktConf == nil {
+		mktConf := dc.marketConfig(mktID)
+		if mktConf == nil {
 			c.log.Warnf("Active %s order retrieved for unknown market %s at %v (server status: %v). Loading it anyway.",
 				oid, mktID, dc.acct.host, dc.status())
 		} else {
@@ -7331,18 +7542,21 @@ func (c *Core) dbTrackers(dc *dexConnection) (map[order.OrderID]*trackedTrade, e
 				dbOrder.MetaData.EpochDur = mktConf.EpochLen
 			}
 		}
+		// For older orders, we'll attempt to get the SwapConf from the server's
+		// market and asset configs. Newer orders will have it stored in the DB.
+		// The server may be gone, in which case it remains zero.
 		if dbOrder.MetaData.ToSwapConf == 0 { // upgraded with active order :/
 			if dbOrder.Order.Trade().Sell {
-				dbOrder.MetaData.ToSwapConf = assetSwapConf(ord.Quote())
+				dbOrder.MetaData.ToSwapConf = dc.swapConf(mktConf, ord.Quote())
 			} else {
-				dbOrder.MetaData.ToSwapConf = assetSwapConf(ord.Base())
+				dbOrder.MetaData.ToSwapConf = dc.swapConf(mktConf, ord.Base())
 			}
 		}
 		if dbOrder.MetaData.FromSwapConf == 0 {
 			if dbOrder.Order.Trade().Sell {
-				dbOrder.MetaData.FromSwapConf = assetSwapConf(ord.Base())
+				dbOrder.MetaData.FromSwapConf = dc.swapConf(mktConf, ord.Base())
 			} else {
-				dbOrder.MetaData.FromSwapConf = assetSwapConf(ord.Quote())
+				dbOrder.MetaData.FromSwapConf = dc.swapConf(mktConf, ord.Quote())
 			}
 		}
 
@@ -7485,10 +7699,10 @@ func (c *Core) loadDBTrades(dc *dexConnection) error {
 			}
 		}
 		if tracker.metaData.FromSwapConf == 0 && assetConfigs.fromAsset != nil {
-			tracker.metaData.FromSwapConf = assetConfigs.fromAsset.SwapConf
+			tracker.metaData.FromSwapConf = dc.swapConf(mktConf, assetConfigs.fromAsset.ID)
 		}
 		if tracker.metaData.ToSwapConf == 0 && assetConfigs.toAsset != nil {
-			tracker.metaData.ToSwapConf = assetConfigs.toAsset.SwapConf
+			tracker.metaData.ToSwapConf = dc.swapConf(mktConf, assetConfigs.toAsset.ID)
 		}
 
 		c.notify(newOrderNote(TopicOrderLoaded, "", "", db.Data, tracker.coreOrder()))
@@ -7537,7 +7751,7 @@ func (c *Core) resumeTrade(tracker *trackedTrade, crypter encrypt.Crypter, faile
 		trade.coinsLocked = false
 		// Block swap txn attempts on matches needing funds.
 		for _, match := range matches {
-			match.swapErr = errors.New("no funding coins for swap")
+			match.swapErr = errNoFundingCoins
 		}
 		// Will not be retired until revoke or cancel of the order and all
 		// matches, which may happen on status resolution after authenticating
@@ -7814,7 +8028,7 @@ func (c *Core) reReserveFunding(w *xcWallet) {
 		trade.changeLocked = false
 		trade.coinsLocked = false
 		for _, match := range matches {
-			match.swapErr = errors.New("no funding coins for swap")
+			match.swapErr = errNoFundingCoins
 		}
 	}
 
@@ -8123,7 +8337,8 @@ func (c *Core) newDEXConnection(acctInfo *db.AccountInfo, flag connectDEXFlag) (
 	if err != nil {
 		return nil, newError(addressParseErr, "error parsing address: %v", err)
 	}
-	wsURL, err := url.Parse("wss://" + host + "/ws")
+	onionTLS := strings.HasPrefix(host, onionTLSScheme)
+	wsURL, err := url.Parse("wss://" + strings.TrimPrefix(host, onionTLSScheme) + "/ws")
 	if err != nil {
 		return nil, newError(addressParseErr, "error parsing ws address from host %s: %w", host, err)
 	}
@@ -8168,8 +8383,16 @@ func (c *Core) newDEXConnection(acctInfo *db.AccountInfo, flag connectDEXFlag) (
 			}
 			proxyAddr = c.cfg.Onion
 
-			wsURL.Scheme = "ws"
-			wsCfg.URL = wsURL.String()
+			// Tor encrypts and authenticates connections to .onion hosts, so
+			// TLS is only used if the host was added with the wss:// scheme.
+			// The certificate is then required and pinned, since the onion
+			// host name can't be verified with it.
+			if onionTLS {
+				wsCfg.PinCert = true
+			} else {
+				wsURL.Scheme = "ws"
+				wsCfg.URL = wsURL.String()
+			}
 		}
 		proxy := &socks.Proxy{
 			Addr:         proxyAddr,
@@ -8184,6 +8407,15 @@ func (c *Core) newDEXConnection(acctInfo *db.AccountInfo, flag connectDEXFlag) (
 	wsCfg.ReconnectSync = func() {
 		go c.handleReconnect(host)
 	}
+	// Identify the account so a draining server will still admit reconnects
+	// while there are swaps in progress.
+	wsCfg.ConnectHeadersFunc = func() http.Header {
+		acctID := dc.acct.ID()
+		if acctID == (account.AccountID{}) {
+			return nil
+		}
+		return http.Header{msgjson.AccountIDHeader: {acctID.String()}}
+	}
 
 	// Create a websocket "connection" to the server. (Don't actually connect.)
 	conn, err := c.wsConstructor(&wsCfg)
@@ -8708,6 +8940,51 @@ func handleScoreChangeMsg(c *Core, dc *dexConnection, msg *msgjson.Message) erro
 	return nil
 }
 
+// handleConfigUpdateMsg is called when the server changes the account conduct
+// settings or market settings reported in its config response.
+func handleConfigUpdateMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error {
+	var update *msgjson.ConfigUpdate
+	err := msg.Unmarshal(&update)
+	if err != nil {
+		return fmt.Errorf("config update note unmarshal error: %w", err)
+	}
+	if update == nil {
+		return errors.New("empty message")
+	}
+
+	dc.cfgMtx.Lock()
+	if dc.cfg != nil {
+		// Consumers of dc.config() may be reading the current config, so
+		// replace it with an updated copy.
+		cfg := *dc.cfg
+		cfg.CancelMax = update.CancelMax
+		cfg.FreeCancels = update.FreeCancels
+		cfg.PenaltyThreshold = update.PenaltyThreshold
+		if len(update.Markets) > 0 {
+			cfg.Markets = make([]*msgjson.Market, len(dc.cfg.Markets))
+			copy(cfg.Markets, dc.cfg.Markets)
+			for _, mkt := range update.Markets {
+				for i, m := range cfg.Markets {
+					if m.Name == mkt.Name {
+						cfg.Markets[i] = mkt
+						break
+					}
+				}
+			}
+		}
+		dc.cfg = &cfg
+	}
+	dc.cfgMtx.Unlock()
+
+	dc.log.Infof("Server %s updated account settings: cancellation rate limit %v (free cancels = %v), penalty threshold %d",
+		dc.acct.host, update.CancelMax, update.FreeCancels, update.PenaltyThreshold)
+	for _, mkt := range update.Markets {
+		dc.log.Infof("Server %s updated market %s: base swapConf %d, quote swapConf %d",
+			dc.acct.host, mkt.Name, mkt.BaseSwapConf, mkt.QuoteSwapConf)
+	}
+	return nil
+}
+
 func handleBondExpiredMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
 	var bondExpired *msgjson.BondExpiredNotification
 	err := msg.Unmarshal(&bondExpired)
@@ -8761,6 +9038,7 @@ var noteHandlers = map[string]routeHandler{
 	msgjson.TierChangeRoute:      handleTierChangeMsg,
 	msgjson.ScoreChangeRoute:     handleScoreChangeMsg,
 	msgjson.BondExpiredRoute:     handleBondExpiredMsg,
+	msgjson.ConfigUpdateRoute:    handleConfigUpdateMsg,
 }
 
 // listen monitors the DEX websocket connection for server requests and
@@ -8867,6 +9145,7 @@ func (c *Core) listen(dc *dexConnection) {
 				c.log.Error(err)
 			}
 			updatedAssets.merge(newUpdates)
+			c.cancelStaleOrder(trade)
 		}
 
 		if len(updatedAssets) > 0 {
@@ -9670,8 +9949,9 @@ func messageOrder(ord order.Order, coins []*msgjson.Coin) (string, msgjson.Stamp
 		return msgjson.LimitRoute, msgOrd, &msgOrd.Trade
 	case *order.MarketOrder:
 		msgOrd := &msgjson.MarketOrder{
-			Prefix: *messagePrefix(prefix),
-			Trade:  *messageTrade(trade, coins),
+			Prefix:    *messagePrefix(prefix),
+			Trade:     *messageTrade(trade, coins),
+			WorstRate: o.WorstRate,
 		}
 		return msgjson.MarketRoute, msgOrd, &msgOrd.Trade
 	case *order.CancelOrder:
@@ -9843,44 +10123,9 @@ func (c *Core) deleteOrderFn(ordersFileStr string) (perOrderFn func(*db.MetaOrde
 	return func(ord *db.MetaOrder) error {
 		cord := coreOrderFromTrade(ord.Order, ord.MetaData)
 
-		baseUnitInfo, err := asset.UnitInfo(cord.BaseID)
-		if err != nil {
-			return fmt.Errorf("unable to get base unit info for %v: %v", cord.BaseSymbol, err)
-		}
-
-		baseFeeAssetSymbol := unbip(cord.BaseID)
-		baseFeeUnitInfo := baseUnitInfo
-		if baseToken := asset.TokenInfo(cord.BaseID); baseToken != nil {
-			baseFeeAssetSymbol = unbip(baseToken.ParentID)
-			baseFeeUnitInfo, err = asset.UnitInfo(baseToken.ParentID)
-			if err != nil {
-				return fmt.Errorf("unable to get base fee unit info for %v: %v", baseToken.ParentID, err)
-			}
-		}
-
-		quoteUnitInfo, err := asset.UnitInfo(cord.QuoteID)
+		ordReader, err := newOrderReader(cord)
 		if err != nil {
-			return fmt.Errorf("unable to get quote unit info for %v: %v", cord.QuoteSymbol, err)
-		}
-
-		quoteFeeAssetSymbol := unbip(cord.QuoteID)
-		quoteFeeUnitInfo := quoteUnitInfo
-		if quoteToken := asset.TokenInfo(cord.QuoteID); quoteToken != nil {
-			quoteFeeAssetSymbol = unbip(quoteToken.ParentID)
-			quoteFeeUnitInfo, err = asset.UnitInfo(quoteToken.ParentID)
-			if err != nil {
-				return fmt.Errorf("unable to get quote fee unit info for %v: %v", quoteToken.ParentID, err)
-			}
-		}
-
-		ordReader := &OrderReader{
-			Order:               cord,
-			BaseUnitInfo:        baseUnitInfo,
-			BaseFeeUnitInfo:     baseFeeUnitInfo,
-			BaseFeeAssetSymbol:  baseFeeAssetSymbol,
-			QuoteUnitInfo:       quoteUnitInfo,
-			QuoteFeeUnitInfo:    quoteFeeUnitInfo,
-			QuoteFeeAssetSymbol: quoteFeeAssetSymbol,
+			return err
 		}
 
 		timestamp := time.UnixMilli(int64(cord.Stamp)).Local().Format(time.RFC3339Nano)
@@ -10383,6 +10628,38 @@ func (c *Core) FiatConversionRates() map[uint32]float64 {
 	return c.fiatConversions()
 }
 
+// PortfolioValue values the balance of every wallet at the current fiat
+// conversion rates. Assets without a current rate are listed with a zero Rate
+// and Value, and are not counted in the Total.
+func (c *Core) PortfolioValue() *PortfolioValue {
+	rates := c.fiatConversions()
+	pv := &PortfolioValue{Assets: make([]*AssetValue, 0)}
+	for _, w := range c.Wallets() {
+		av := &AssetValue{
+			AssetID: w.AssetID,
+			Symbol:  w.Symbol,
+		}
+		if bal := w.Balance; bal != nil && bal.Balance != nil {
+			av.Balance = bal.Available + bal.Immature + bal.Locked + bal.ContractLocked + bal.BondLocked
+		}
+		if rate, found := rates[w.AssetID]; found {
+			ui, err := asset.UnitInfo(w.AssetID)
+			if err != nil {
+				c.log.Errorf("No unit info for %s: %v", unbip(w.AssetID), err)
+			} else {
+				av.Rate = rate
+				av.Value = float64(av.Balance) / float64(ui.Conventional.ConversionFactor) * rate
+				pv.Total += av.Value
+			}
+		}
+		pv.Assets = append(pv.Assets, av)
+	}
+	sort.Slice(pv.Assets, func(i, j int) bool {
+		return pv.Assets[i].AssetID < pv.Assets[j].AssetID
+	})
+	return pv
+}
+
 // fiatConversions returns fiat rate for all supported assets that have a
 // wallet.
 func (c *Core) fiatConversions() map[uint32]float64 {
@@ -10898,6 +11175,15 @@ func (c *Core) ExtensionModeConfig() *ExtensionModeConfig {
 	return c.extensionModeConfig
 }
 
+// checkAmnesia returns an error if Core is running in amnesia mode, which
+// only permits view-only operation.
+func (c *Core) checkAmnesia(action string) error {
+	if c.cfg.Amnesia {
+		return newError(amnesiaErr, "cannot %s in amnesia mode", action)
+	}
+	return nil
+}
+
 // calcParcelLimit computes the users score-scaled user parcel limit.
 func calcParcelLimit(tier int64, score, maxScore int32) uint32 {
 	// Users limit starts at 2 parcels per tier.
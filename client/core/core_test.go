@@ -7,9 +7,11 @@ import (
 	"context"
 	crand "crypto/rand"
 	"crypto/sha256"
+	"encoding/csv"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
 	"os"
@@ -351,6 +353,7 @@ func (conn *TWebsocket) UpdateURL(string) {}
 
 type TDB struct {
 	updateWalletErr          error
+	restoredSnapshot         string
 	acct                     *db.AccountInfo
 	acctErr                  error
 	createAccountErr         error
@@ -358,6 +361,7 @@ type TDB struct {
 	updateOrderErr           error
 	activeDEXOrders          []*db.MetaOrder
 	matchesForOID            []*db.MetaMatch
+	orders                   []*db.MetaOrder
 	matchesForOIDErr         error
 	updateMatchChan          chan order.MatchStatus
 	activeMatchOIDs          []order.OrderID
@@ -386,6 +390,7 @@ type TDB struct {
 	deleteInactiveMatchesErr error
 	archivedMatches          int
 	updateAccountInfoErr     error
+	addressBooks             map[uint32]*db.AddressBook
 }
 
 func (tdb *TDB) Run(context.Context) {}
@@ -462,7 +467,7 @@ func (tdb *TDB) Order(oid order.OrderID) (*db.MetaOrder, error) {
 }
 
 func (tdb *TDB) Orders(*db.OrderFilter) ([]*db.MetaOrder, error) {
-	return nil, nil
+	return tdb.orders, nil
 }
 
 func (tdb *TDB) MarketOrders(dex string, base, quote uint32, n int, since uint64) ([]*db.MetaOrder, error) {
@@ -531,6 +536,8 @@ func (tdb *TDB) Wallet([]byte) (*db.Wallet, error) {
 
 func (tdb *TDB) SaveNotification(*db.Notification) error            { return nil }
 func (tdb *TDB) BackupTo(dst string, overwrite, compact bool) error { return nil }
+func (tdb *TDB) Snapshots() ([]*db.Snapshot, error)                 { return nil, nil }
+func (tdb *TDB) RestoreSnapshot(name string) error                  { tdb.restoredSnapshot = name; return nil }
 func (tdb *TDB) NotificationsN(int) ([]*db.Notification, error)     { return nil, nil }
 func (tdb *TDB) SavePokes([]*db.Notification) error                 { return nil }
 func (tdb *TDB) LoadPokes() ([]*db.Notification, error)             { return nil, nil }
@@ -589,6 +596,21 @@ func (tdb *TDB) Language() (string, error) {
 	return "en-US", nil
 }
 
+func (tdb *TDB) SetAddressBook(book *db.AddressBook) error {
+	if tdb.addressBooks == nil {
+		tdb.addressBooks = make(map[uint32]*db.AddressBook)
+	}
+	tdb.addressBooks[book.AssetID] = book
+	return nil
+}
+
+func (tdb *TDB) AddressBook(assetID uint32) (*db.AddressBook, error) {
+	if book, found := tdb.addressBooks[assetID]; found {
+		return book, nil
+	}
+	return &db.AddressBook{AssetID: assetID}, nil
+}
+
 type tCoin struct {
 	id []byte
 
@@ -683,6 +705,7 @@ type TXCWallet struct {
 	preSwap             *asset.PreSwap
 	preRedeemForm       *asset.PreRedeemForm
 	preRedeem           *asset.PreRedeem
+	maxOrder            *asset.SwapEstimate
 	ownsAddress         bool
 	ownsAddressErr      error
 	pubKeys             []dex.Bytes
@@ -807,7 +830,7 @@ func (w *TXCWallet) FundOrder(ord *asset.Order) (asset.Coins, []dex.Bytes, uint6
 }
 
 func (w *TXCWallet) MaxOrder(*asset.MaxOrderForm) (*asset.SwapEstimate, error) {
-	return nil, nil
+	return w.maxOrder, nil
 }
 
 func (w *TXCWallet) PreSwap(form *asset.PreSwapForm) (*asset.PreSwap, error) {
@@ -1357,6 +1380,7 @@ func newTestRig() *testRig {
 
 	rig.core.intl.Store(&locale{
 		m:       originLocale,
+		errs:    originServerErrors,
 		printer: message.NewPrinter(language.AmericanEnglish),
 	})
 
@@ -2572,6 +2596,13 @@ func TestConnectDEX(t *testing.T) {
 		t.Fatalf("expected error with no onion proxy set")
 	}
 
+	var wsCfg *comms.WsCfg
+	ogConstructor := tCore.wsConstructor
+	tCore.wsConstructor = func(cfg *comms.WsCfg) (comms.WsConn, error) {
+		wsCfg = cfg
+		return ogConstructor(cfg)
+	}
+
 	rig.queueConfig()
 	tCore.cfg.Onion = "127.0.0.1:9050"
 	dc, err := tCore.connectDEX(ai)
@@ -2579,6 +2610,39 @@ func TestConnectDEX(t *testing.T) {
 		t.Fatalf("error connecting to onion host with an onion proxy configured: %v", err)
 	}
 	dc.connMaster.Disconnect()
+	// Tor secures the connection to the onion host.
+	if !strings.HasPrefix(wsCfg.URL, "ws://") || wsCfg.PinCert {
+		t.Fatalf("expected plain ws connection to onion host, got %s (pinned = %t)",
+			wsCfg.URL, wsCfg.PinCert)
+	}
+
+	// A stored cert does not switch an onion host to TLS.
+	rig.queueConfig()
+	ai.Cert = []byte{0x1}
+	dc, err = tCore.connectDEX(ai)
+	if err != nil {
+		t.Fatalf("error connecting to onion host with a cert: %v", err)
+	}
+	dc.connMaster.Disconnect()
+	if !strings.HasPrefix(wsCfg.URL, "ws://") || wsCfg.PinCert {
+		t.Fatalf("expected plain ws connection to onion host with a cert, got %s (pinned = %t)",
+			wsCfg.URL, wsCfg.PinCert)
+	}
+
+	// With the wss:// scheme, TLS is used and the cert is pinned.
+	rig.queueConfig()
+	ai.Host = "wss://somedex13254214214.onion:7232"
+	dc, err = tCore.connectDEX(ai)
+	if err != nil {
+		t.Fatalf("error connecting to wss onion host: %v", err)
+	}
+	dc.connMaster.Disconnect()
+	if wsCfg.URL != "wss://somedex13254214214.onion:7232/ws" || !wsCfg.PinCert {
+		t.Fatalf("expected pinned wss connection to wss onion host, got %s (pinned = %t)",
+			wsCfg.URL, wsCfg.PinCert)
+	}
+	tCore.wsConstructor = ogConstructor
+	ai.Cert = nil
 
 	rig.queueConfig()
 	ai.Host = "somedex.com"
@@ -2598,7 +2662,6 @@ func TestConnectDEX(t *testing.T) {
 	ai.Host = "someotherdex.org"
 
 	// Constructor error.
-	ogConstructor := tCore.wsConstructor
 	tCore.wsConstructor = func(*comms.WsCfg) (comms.WsConn, error) {
 		return nil, tErr
 	}
@@ -2775,6 +2838,104 @@ func TestSend(t *testing.T) {
 	}
 }
 
+func TestAddressBook(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	wallet, tWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = wallet
+	tWallet.sendCoin = &tCoin{id: encode.RandomBytes(36)}
+	const addr1, addr2 = "addr1", "addr2"
+
+	// Invalid address.
+	if err := tCore.AddAddressBookEntry(tPW, tUTXOAssetA.ID, addr1, "savings"); err == nil {
+		t.Fatalf("no error for invalid address")
+	}
+	tWallet.validAddr = true
+
+	// Unknown wallet.
+	if err := tCore.AddAddressBookEntry(tPW, 12345, addr1, "savings"); err == nil {
+		t.Fatalf("no error for unknown wallet")
+	}
+
+	// Can't restrict withdrawals with an empty address book.
+	if err := tCore.RestrictWithdrawals(tPW, tUTXOAssetA.ID, true); err == nil {
+		t.Fatalf("no error for restricting withdrawals with an empty address book")
+	}
+
+	if err := tCore.AddAddressBookEntry(tPW, tUTXOAssetA.ID, addr1, "savings"); err != nil {
+		t.Fatalf("AddAddressBookEntry error: %v", err)
+	}
+	if err := tCore.AddAddressBookEntry(tPW, tUTXOAssetA.ID, addr2, "cold"); err != nil {
+		t.Fatalf("AddAddressBookEntry error: %v", err)
+	}
+	// Relabel.
+	if err := tCore.AddAddressBookEntry(tPW, tUTXOAssetA.ID, addr1, "hot"); err != nil {
+		t.Fatalf("AddAddressBookEntry error: %v", err)
+	}
+	book, err := tCore.AddressBook(tPW, tUTXOAssetA.ID)
+	if err != nil {
+		t.Fatalf("AddressBook error: %v", err)
+	}
+	if len(book.Entries) != 2 {
+		t.Fatalf("expected 2 address book entries, got %d", len(book.Entries))
+	}
+	if book.Entries[0].Address != addr1 || book.Entries[0].Label != "hot" {
+		t.Fatalf("wrong first entry: %+v", book.Entries[0])
+	}
+	if book.Restricted {
+		t.Fatalf("address book should not be restricted")
+	}
+
+	// Unrestricted sends to any address are allowed.
+	if _, err := tCore.Send(tPW, tUTXOAssetA.ID, 1e8, "addr3", false); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if err := tCore.RestrictWithdrawals(tPW, tUTXOAssetA.ID, true); err != nil {
+		t.Fatalf("RestrictWithdrawals error: %v", err)
+	}
+
+	// Restricted, and not in the address book.
+	if _, err := tCore.Send(tPW, tUTXOAssetA.ID, 1e8, "addr3", false); err == nil {
+		t.Fatalf("no error for restricted send to an address not in the book")
+	}
+	// Restricted, and no password to decrypt the address book.
+	if _, err := tCore.Send(nil, tUTXOAssetA.ID, 1e8, addr2, false); err == nil {
+		t.Fatalf("no error for restricted send without a password")
+	}
+	// In the book.
+	if _, err := tCore.Send(tPW, tUTXOAssetA.ID, 1e8, addr2, false); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	// Remove an entry.
+	if err := tCore.RemoveAddressBookEntry(tPW, tUTXOAssetA.ID, addr2); err != nil {
+		t.Fatalf("RemoveAddressBookEntry error: %v", err)
+	}
+	if err := tCore.RemoveAddressBookEntry(tPW, tUTXOAssetA.ID, addr2); err == nil {
+		t.Fatalf("no error for removing an unknown entry")
+	}
+	if _, err := tCore.Send(tPW, tUTXOAssetA.ID, 1e8, addr2, false); err == nil {
+		t.Fatalf("no error for restricted send to a removed address")
+	}
+
+	// Lift the restriction.
+	if err := tCore.RestrictWithdrawals(tPW, tUTXOAssetA.ID, false); err != nil {
+		t.Fatalf("RestrictWithdrawals error: %v", err)
+	}
+	if _, err := tCore.Send(tPW, tUTXOAssetA.ID, 1e8, addr2, false); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	// Bad password.
+	rig.crypter.(*tCrypter).recryptErr = tErr
+	if _, err := tCore.AddressBook(tPW, tUTXOAssetA.ID); !errorHasCode(err, passwordErr) {
+		t.Fatalf("wrong password error: %v", err)
+	}
+	rig.crypter.(*tCrypter).recryptErr = nil
+}
+
 func trade(t *testing.T, async bool) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -3151,9 +3312,16 @@ func trade(t *testing.T, async bool) {
 	}
 	tBtcWallet.fundedSwaps = 0
 
-	// Successful market buy order
+	// Market buy order on a book that is too thin must be confirmed.
 	form.IsLimit = false
 	form.Qty = calc.BaseToQuote(rate, qty)
+	_, err = trade()
+	if !errorHasCode(err, lowLiquidityErr) {
+		t.Fatalf("expected low liquidity error, got %v", err)
+	}
+	form.AcceptLowLiquidity = true
+
+	// Successful market buy order
 	rig.ws.queueResponse(msgjson.MarketRoute, handleMarket)
 	corder, err = trade()
 	if err != nil {
@@ -3243,6 +3411,370 @@ func TestTradeAsync(t *testing.T) {
 	trade(t, true)
 }
 
+func TestMarketSwapConf(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	dc := rig.dc
+
+	mktConf := dc.marketConfig(tDcrBtcMktName)
+	mktConf.QuoteSwapConf = tUTXOAssetB.SwapConf + 3
+
+	if swapConf := dc.swapConf(mktConf, tUTXOAssetA.ID); swapConf != tUTXOAssetA.SwapConf {
+		t.Fatalf("wrong base swapConf. wanted %d, got %d", tUTXOAssetA.SwapConf, swapConf)
+	}
+	if swapConf := dc.swapConf(mktConf, tUTXOAssetB.ID); swapConf != mktConf.QuoteSwapConf {
+		t.Fatalf("wrong quote swapConf. wanted %d, got %d", mktConf.QuoteSwapConf, swapConf)
+	}
+	// Without a market config, the asset's swapConf applies.
+	if swapConf := dc.swapConf(nil, tUTXOAssetB.ID); swapConf != tUTXOAssetB.SwapConf {
+		t.Fatalf("wrong swapConf without market. wanted %d, got %d", tUTXOAssetB.SwapConf, swapConf)
+	}
+	if swapConf := dc.swapConf(nil, 12345); swapConf != 0 {
+		t.Fatalf("expected zero swapConf for unknown asset, got %d", swapConf)
+	}
+}
+
+func TestEvaluateLiquidity(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	dc := rig.dc
+
+	rate := dcrBtcRateStep * 100
+	lotSize := dcrBtcLotSize
+	form := &TradeForm{
+		Host:  tDexHost,
+		Sell:  true,
+		Base:  tUTXOAssetA.ID,
+		Quote: tUTXOAssetB.ID,
+		Qty:   lotSize * 10,
+		Rate:  rate,
+	}
+
+	// No book, nothing to evaluate.
+	if r := dc.evaluateLiquidity(form); len(r.Warnings) > 0 {
+		t.Fatalf("unexpected warnings without a book: %v", r.Warnings)
+	}
+
+	book := newBookie(dc, tUTXOAssetA.ID, tUTXOAssetB.ID, []string{liquidityCandleDur}, tLogger)
+	dc.books[tDcrBtcMktName] = book
+	buyNote := &msgjson.BookOrderNote{
+		OrderNote: msgjson.OrderNote{
+			OrderID: encode.RandomBytes(32),
+		},
+		TradeNote: msgjson.TradeNote{
+			Side:     msgjson.BuyOrderNum,
+			Quantity: lotSize * 5,
+			Time:     uint64(time.Now().Unix()),
+			Rate:     rate,
+		},
+	}
+	err := book.Sync(&msgjson.OrderBook{
+		MarketID: tDcrBtcMktName,
+		Seq:      1,
+		Epoch:    1,
+		Orders:   []*msgjson.BookOrderNote{buyNote},
+	})
+	if err != nil {
+		t.Fatalf("order book sync error: %v", err)
+	}
+
+	// A standing limit order adds liquidity, so it isn't evaluated.
+	if r := dc.evaluateLiquidity(form); len(r.Warnings) > 0 {
+		t.Fatalf("unexpected warnings for standing limit order: %v", r.Warnings)
+	}
+
+	// An immediate order can only fill half.
+	form.TifNow = true
+	r := dc.evaluateLiquidity(form)
+	if len(r.Warnings) != 1 {
+		t.Fatalf("expected 1 warning for thin book, got %v", r.Warnings)
+	}
+	if r.Fillable != lotSize*5 {
+		t.Fatalf("wrong fillable quantity. wanted %d, got %d", lotSize*5, r.Fillable)
+	}
+	form.Qty = lotSize * 5
+	if r := dc.evaluateLiquidity(form); len(r.Warnings) > 0 {
+		t.Fatalf("unexpected warnings for fillable order: %v", r.Warnings)
+	}
+
+	// With candles cached, the recent volume is checked too.
+	nowMs := uint64(time.Now().UnixMilli())
+	cache := book.candleCaches[liquidityCandleDur]
+	cache.init([]*msgjson.Candle{{
+		StartStamp:  nowMs - uint64(time.Hour.Milliseconds()),
+		EndStamp:    nowMs,
+		MatchVolume: lotSize * 2,
+		StartRate:   rate,
+		EndRate:     rate,
+	}})
+	atomic.StoreUint32(&cache.on, 1)
+	r = dc.evaluateLiquidity(form)
+	if len(r.Warnings) != 1 {
+		t.Fatalf("expected 1 warning for low volume, got %v", r.Warnings)
+	}
+	if r.Volume != lotSize*2 {
+		t.Fatalf("wrong volume. wanted %d, got %d", lotSize*2, r.Volume)
+	}
+	form.Qty = lotSize * 2
+	if r := dc.evaluateLiquidity(form); len(r.Warnings) > 0 {
+		t.Fatalf("unexpected warnings for order within volume: %v", r.Warnings)
+	}
+
+	// A standing limit order isn't checked against the volume either.
+	form.TifNow = false
+	form.Qty = lotSize * 10
+	if r := dc.evaluateLiquidity(form); len(r.Warnings) > 0 {
+		t.Fatalf("unexpected warnings for standing limit order: %v", r.Warnings)
+	}
+}
+
+func TestValidateTrade(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, _ := newTWallet(tUTXOAssetB.ID)
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+	tDcrWallet.maxOrder = &asset.SwapEstimate{Lots: 5}
+
+	rate := dcrBtcRateStep * 1000
+	newForm := func() *TradeForm {
+		return &TradeForm{
+			Host:    tDexHost,
+			IsLimit: true,
+			Sell:    true,
+			Base:    tUTXOAssetA.ID,
+			Quote:   tUTXOAssetB.ID,
+			Qty:     dcrBtcLotSize * 2,
+			Rate:    rate,
+		}
+	}
+
+	// checkErrors checks that the validation failed with exactly the expected
+	// checks, keyed to whether the error is transient.
+	checkErrors := func(tag string, form *TradeForm, expErrs map[string]bool) {
+		t.Helper()
+		v := tCore.ValidateTrade(form)
+		if v.Valid != (len(expErrs) == 0) {
+			t.Fatalf("%s: wrong Valid %t, errors = %+v", tag, v.Valid, v.Errors)
+		}
+		if len(v.Errors) != len(expErrs) {
+			t.Fatalf("%s: expected %d errors, got %d: %+v", tag, len(expErrs), len(v.Errors), v.Errors)
+		}
+		for _, e := range v.Errors {
+			transient, found := expErrs[e.Check]
+			if !found {
+				t.Fatalf("%s: unexpected %q error: %s", tag, e.Check, e.Message)
+			}
+			if e.Transient != transient {
+				t.Fatalf("%s: wrong Transient %t for %q error: %s", tag, e.Transient, e.Check, e.Message)
+			}
+		}
+	}
+
+	checkErrors("valid", newForm(), nil)
+
+	// Every configuration error is reported at once.
+	form := newForm()
+	form.Qty++
+	form.Rate++
+	form.TifNow = true
+	form.TTL = 60
+	checkErrors("bad order", form, map[string]bool{
+		ValidateRate:     false,
+		ValidateQuantity: false,
+		ValidateOptions:  false,
+	})
+
+	// A worst rate requires a server that supports it.
+	form = newForm()
+	form.IsLimit = false
+	form.WorstRate = rate
+	form.AcceptLowLiquidity = true // the test book is empty
+	checkErrors("worst rate unsupported", form, map[string]bool{ValidateRate: false})
+	atomic.StoreInt32(&rig.dc.apiVer, serverdex.WorstRateAPIVersion)
+	checkErrors("worst rate", form, nil)
+	atomic.StoreInt32(&rig.dc.apiVer, serverdex.PreAPIVersion)
+
+	// Not enough funds.
+	tDcrWallet.maxOrder = &asset.SwapEstimate{Lots: 1}
+	checkErrors("insufficient funds", newForm(), map[string]bool{ValidateFunding: true})
+	tDcrWallet.maxOrder = &asset.SwapEstimate{Lots: 5}
+
+	// Over the parcel limit of a tier 1 account.
+	form = newForm()
+	form.Qty = dcrBtcLotSize * 3
+	checkErrors("parcel limit", form, map[string]bool{ValidateLimits: true})
+
+	// A disconnected wallet can't be checked for funds.
+	dcrWallet.mtx.Lock()
+	dcrWallet.hookedUp = false
+	dcrWallet.mtx.Unlock()
+	checkErrors("wallet disconnected", newForm(), map[string]bool{ValidateWallet: true})
+	dcrWallet.mtx.Lock()
+	dcrWallet.hookedUp = true
+	dcrWallet.mtx.Unlock()
+
+	// Missing wallet.
+	delete(tCore.wallets, tUTXOAssetB.ID)
+	checkErrors("missing wallet", newForm(), map[string]bool{ValidateWallet: false})
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+
+	// Unknown market.
+	form = newForm()
+	form.Quote = 12345
+	checkErrors("unknown market", form, map[string]bool{ValidateMarket: false})
+
+	// Unknown host.
+	form = newForm()
+	form.Host = "blah"
+	checkErrors("unknown host", form, map[string]bool{ValidateAccount: false})
+}
+
+func TestStaleOrderTopic(t *testing.T) {
+	now := time.Now()
+	lo := &order.LimitOrder{
+		P:    order.Prefix{ServerTime: now.Add(-time.Hour)},
+		Rate: 1e6,
+	}
+	tests := []struct {
+		name      string
+		ttl       uint64
+		maxDrift  float64
+		refRate   uint64
+		wantTopic Topic
+		wantDrift float64
+	}{{
+		name: "no limits",
+	}, {
+		name: "not expired",
+		ttl:  3601,
+	}, {
+		name:      "expired",
+		ttl:       3600,
+		wantTopic: TopicOrderExpired,
+	}, {
+		name:     "within drift",
+		maxDrift: 10,
+		refRate:  1.1e6,
+	}, {
+		name:      "drifted",
+		maxDrift:  10,
+		refRate:   0.8e6,
+		wantTopic: TopicOrderDrifted,
+		wantDrift: 25,
+	}, {
+		name:     "no spot rate",
+		maxDrift: 10,
+	}, {
+		name:      "expiry first",
+		ttl:       60,
+		maxDrift:  10,
+		refRate:   2e6,
+		wantTopic: TopicOrderExpired,
+	}}
+	for _, tt := range tests {
+		topic, drift := staleOrderTopic(lo, tt.ttl, tt.maxDrift, tt.refRate, now)
+		if topic != tt.wantTopic {
+			t.Fatalf("%s: wanted topic %q, got %q", tt.name, tt.wantTopic, topic)
+		}
+		if math.Abs(drift-tt.wantDrift) > 1e-9 {
+			t.Fatalf("%s: wanted drift %f, got %f", tt.name, tt.wantDrift, drift)
+		}
+	}
+}
+
+func TestAggregateBook(t *testing.T) {
+	mo := func(sell bool, rate, qty uint64) *MiniOrder {
+		return &MiniOrder{Sell: sell, MsgRate: rate, QtyAtomic: qty, Qty: float64(qty) / 1e8}
+	}
+	books := map[string]*OrderBook{
+		"a.tld": {
+			Sells: []*MiniOrder{mo(true, 2e7, 1e8), mo(true, 3e7, 2e8)},
+			Buys:  []*MiniOrder{mo(false, 1e7, 1e8)},
+		},
+		"b.tld": {
+			Sells: []*MiniOrder{mo(true, 2e7, 5e7), mo(true, 2.4e7, 1e8)},
+			Buys:  []*MiniOrder{mo(false, 1.5e7, 3e8)},
+		},
+		"c.tld": {},
+	}
+
+	agg := aggregateBooks(tUTXOAssetA.ID, tUTXOAssetB.ID, books)
+	if len(agg.Servers) != 3 {
+		t.Fatalf("expected 3 server summaries, got %d", len(agg.Servers))
+	}
+	for _, s := range agg.Servers {
+		if s.Host == "a.tld" && (s.BestBuy != 1e7 || s.BestSell != 2e7 || s.BuyDepth != 1e8 || s.SellDepth != 3e8) {
+			t.Fatalf("wrong summary %+v", s)
+		}
+		if s.Host == "c.tld" && (s.BestBuy != 0 || s.BestSell != 0) {
+			t.Fatalf("wrong summary for empty book %+v", s)
+		}
+	}
+	if len(agg.Sells) != 3 || len(agg.Buys) != 2 {
+		t.Fatalf("wrong number of levels. %d sells, %d buys", len(agg.Sells), len(agg.Buys))
+	}
+	if lvl := agg.Sells[0]; lvl.MsgRate != 2e7 || lvl.QtyAtomic != 1.5e8 || lvl.Hosts["a.tld"] != 1e8 || lvl.Hosts["b.tld"] != 5e7 {
+		t.Fatalf("wrong best sell level %+v", lvl)
+	}
+	if agg.Sells[1].MsgRate != 2.4e7 || agg.Sells[2].MsgRate != 3e7 {
+		t.Fatalf("sells not sorted")
+	}
+	if agg.Buys[0].MsgRate != 1.5e7 || agg.Buys[1].MsgRate != 1e7 {
+		t.Fatalf("buys not sorted")
+	}
+
+	tests := []struct {
+		name     string
+		form     *TradeForm
+		wantHost string
+	}{{
+		name:     "limit buy, same fill, better price",
+		form:     &TradeForm{IsLimit: true, Qty: 1.5e8, Rate: 3e7},
+		wantHost: "b.tld",
+	}, {
+		name:     "limit buy, more filled",
+		form:     &TradeForm{IsLimit: true, Qty: 1.5e8, Rate: 2e7},
+		wantHost: "a.tld",
+	}, {
+		name:     "market sell, more filled",
+		form:     &TradeForm{Sell: true, Qty: 2e8},
+		wantHost: "b.tld",
+	}, {
+		name:     "market sell, worst rate",
+		form:     &TradeForm{Sell: true, Qty: 2e8, WorstRate: 2e7},
+		wantHost: "b.tld",
+	}, {
+		name:     "market buy, equal fills",
+		form:     &TradeForm{Qty: 1e7},
+		wantHost: "a.tld",
+	}, {
+		name:     "standing limit sell, best bid",
+		form:     &TradeForm{IsLimit: true, Sell: true, Qty: 1e8, Rate: 5e7},
+		wantHost: "b.tld",
+	}}
+	for _, tt := range tests {
+		var best *fillEstimate
+		for _, host := range []string{"a.tld", "b.tld", "c.tld"} {
+			if f := estimateFill(host, books[host], tt.form); best == nil || f.better(best, tt.form.Sell) {
+				best = f
+			}
+		}
+		if best.host != tt.wantHost {
+			t.Fatalf("%s: wanted %s, got %s", tt.name, tt.wantHost, best.host)
+		}
+	}
+
+	// A market buy is limited by the quote quantity.
+	f := estimateFill("a.tld", books["a.tld"], &TradeForm{Qty: 3e7})
+	if f.base != 1e8+calc.QuoteToBase(3e7, 1e7) || f.quote != 3e7 {
+		t.Fatalf("wrong market buy fill %+v", f)
+	}
+}
+
 func TestRefundReserves(t *testing.T) {
 	const reserves = 100_000
 
@@ -4861,6 +5393,17 @@ func TestTradeTracking(t *testing.T) {
 	if tracker.metaData.RedemptionFeesPaid != tRedemptionFeesPaid {
 		t.Fatalf("wrong fees recorded for redemption. expected %d, got %d", tRedemptionFeesPaid, tracker.metaData.SwapFeesPaid)
 	}
+	// The fees are attributed to the swap and redeem txs.
+	feeTxs := tracker.metaData.FeeTxs
+	if len(feeTxs) != 2 {
+		t.Fatalf("expected 2 fee txs, got %d", len(feeTxs))
+	}
+	if feeTxs[0].Type != db.FeeTxSwap || feeTxs[0].Fees != tSwapFeesPaid || len(feeTxs[0].Matches) != 1 || feeTxs[0].Matches[0] != mid {
+		t.Fatalf("wrong swap fee tx %+v", feeTxs[0])
+	}
+	if feeTxs[1].Type != db.FeeTxRedeem || feeTxs[1].Fees != tRedemptionFeesPaid || !bytes.Equal(feeTxs[1].CoinID, redeemCoin) {
+		t.Fatalf("wrong redeem fee tx %+v", feeTxs[1])
+	}
 	rig.db.updateMatchChan = nil
 
 	// TAKER MATCH
@@ -6071,6 +6614,193 @@ func TestReReserveFunding(t *testing.T) {
 
 }
 
+func TestReconcileFunding(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	utxoAsset, acctAsset := tUTXOAssetB, tACCTAsset
+	btcWallet, tBtcWallet := newTWallet(utxoAsset.ID)
+	tCore.wallets[utxoAsset.ID] = btcWallet
+
+	_, lo, dbOrder, match, changeCoin := generateMatch(rig, utxoAsset.ID, acctAsset.ID)
+	mt := &matchTracker{
+		MetaMatch: *match,
+		prefix:    lo.Prefix(),
+		trade:     lo.Trade(),
+		swapErr:   errNoFundingCoins,
+	}
+	tracker := &trackedTrade{
+		Order:       lo,
+		dc:          rig.dc,
+		metaData:    dbOrder.MetaData,
+		fromAssetID: utxoAsset.ID,
+		matches:     map[order.MatchID]*matchTracker{match.MatchID: mt},
+	}
+	rig.dc.trades = map[order.OrderID]*trackedTrade{lo.ID(): tracker}
+
+	// Other wallets' trades are not checked.
+	ethWallet, _ := newTAccountLocker(acctAsset.ID)
+	if restored, missing := tCore.reconcileFunding(ethWallet); restored != 0 || missing != 0 {
+		t.Fatalf("trade of another asset reconciled: restored = %d, missing = %d", restored, missing)
+	}
+
+	// Coins still not found.
+	tBtcWallet.fundingCoinErr = tErr
+	if restored, missing := tCore.reconcileFunding(btcWallet); restored != 0 || missing != 1 {
+		t.Fatalf("wanted 1 missing, got restored = %d, missing = %d", restored, missing)
+	}
+	if tracker.coinsLocked || mt.swapErr != errNoFundingCoins {
+		t.Fatalf("unfunded trade updated")
+	}
+	tBtcWallet.fundingCoinErr = nil
+
+	// Coins found.
+	tBtcWallet.fundingCoins = asset.Coins{changeCoin}
+	if restored, missing := tCore.reconcileFunding(btcWallet); restored != 1 || missing != 0 {
+		t.Fatalf("wanted 1 restored, got restored = %d, missing = %d", restored, missing)
+	}
+	if !tracker.coinsLocked || len(tracker.coins) != 1 {
+		t.Fatalf("funding coins not restored")
+	}
+	if mt.swapErr != nil {
+		t.Fatalf("match still blocked: %v", mt.swapErr)
+	}
+
+	// A funded trade is not checked again.
+	if restored, missing := tCore.reconcileFunding(btcWallet); restored != 0 || missing != 0 {
+		t.Fatalf("funded trade reconciled: restored = %d, missing = %d", restored, missing)
+	}
+}
+
+func TestLockedBalance(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, _ := newTWallet(tUTXOAssetB.ID)
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+
+	const orderLocked, refundLocked = 3e7, 100
+	const bondReserves, otherLocked, unexplained = 5e5, 2e5, 1e6
+	tDcrWallet.bal = &asset.Balance{
+		Available:    1e8,
+		Locked:       orderLocked + refundLocked + bondReserves + otherLocked + unexplained,
+		BondReserves: bondReserves,
+		Other: map[asset.BalanceCategory]asset.CustomBalance{
+			"locked":   {Amount: otherLocked, Locked: true},
+			"unlocked": {Amount: 4e5},
+		},
+	}
+
+	lo := &order.LimitOrder{
+		P: order.Prefix{
+			OrderType:  order.LimitOrderType,
+			BaseAsset:  tUTXOAssetA.ID,
+			QuoteAsset: tUTXOAssetB.ID,
+			ServerTime: time.Now(),
+		},
+		T:     order.Trade{Sell: true, Quantity: dcrBtcLotSize * 10},
+		Rate:  dcrBtcRateStep * 100,
+		Force: order.StandingTiF,
+	}
+	newMatch := func(side order.MatchSide, status order.MatchStatus) *matchTracker {
+		return &matchTracker{
+			MetaMatch: db.MetaMatch{
+				UserMatch: &order.UserMatch{
+					OrderID:  lo.ID(),
+					MatchID:  ordertest.RandomMatchID(),
+					Quantity: dcrBtcLotSize * 2,
+					Rate:     lo.Rate,
+					Side:     side,
+					Status:   status,
+				},
+				MetaData: &db.MatchMetaData{},
+			},
+		}
+	}
+	swapped := newMatch(order.Maker, order.MakerSwapCast)
+	completed := newMatch(order.Taker, order.MatchComplete)
+	tracker := &trackedTrade{
+		Order:       lo,
+		dc:          rig.dc,
+		mktID:       tDcrBtcMktName,
+		metaData:    &db.OrderMetaData{Status: order.OrderStatusBooked},
+		fromAssetID: tUTXOAssetA.ID,
+		wallets: &walletSet{
+			fromWallet: dcrWallet,
+			toWallet:   btcWallet,
+		},
+		coins:        mapifyCoins(asset.Coins{&tCoin{id: encode.RandomBytes(36), val: orderLocked}}),
+		coinsLocked:  true,
+		refundLocked: refundLocked,
+		matches: map[order.MatchID]*matchTracker{
+			swapped.MatchID:   swapped,
+			completed.MatchID: completed,
+		},
+	}
+	rig.dc.trades = map[order.OrderID]*trackedTrade{lo.ID(): tracker}
+
+	lb, err := tCore.LockedBalance(tUTXOAssetA.ID)
+	if err != nil {
+		t.Fatalf("LockedBalance error: %v", err)
+	}
+	if len(lb.Orders) != 1 || lb.Orders[0].Amount != orderLocked {
+		t.Fatalf("wrong order locks %+v", lb.Orders)
+	}
+	if len(lb.FeeReserves) != 1 || lb.FeeReserves[0].Amount != refundLocked {
+		t.Fatalf("wrong fee reserves %+v", lb.FeeReserves)
+	}
+	if lb.BondReserves != bondReserves {
+		t.Fatalf("wrong bond reserves %d", lb.BondReserves)
+	}
+	if len(lb.Other) != 1 || lb.Other["locked"] != otherLocked {
+		t.Fatalf("wrong other locked %v", lb.Other)
+	}
+	if lb.Unexplained != unexplained {
+		t.Fatalf("wrong unexplained amount %d", lb.Unexplained)
+	}
+	if len(lb.Contracts) != 1 || lb.Contracts[0].Amount != swapped.Quantity ||
+		!bytes.Equal(lb.Contracts[0].MatchID, swapped.MatchID[:]) {
+		t.Fatalf("wrong contracts %+v", lb.Contracts)
+	}
+	if len(lb.PendingRedemptions) != 0 {
+		t.Fatalf("unexpected pending redemptions of the from asset %+v", lb.PendingRedemptions)
+	}
+	if bonds, _ := rig.dc.bondTotal(tUTXOAssetA.ID); lb.Bonds != bonds {
+		t.Fatalf("wrong bonds amount. wanted %d, got %d", bonds, lb.Bonds)
+	}
+
+	// The swapped match will pay the to asset.
+	lb, err = tCore.LockedBalance(tUTXOAssetB.ID)
+	if err != nil {
+		t.Fatalf("LockedBalance error: %v", err)
+	}
+	if len(lb.Orders) != 0 || len(lb.Contracts) != 0 || len(lb.FeeReserves) != 0 {
+		t.Fatalf("unexpected locks of the to asset %+v", lb)
+	}
+	expRedeem := calc.BaseToQuote(swapped.Rate, swapped.Quantity)
+	if len(lb.PendingRedemptions) != 1 || lb.PendingRedemptions[0].Amount != expRedeem {
+		t.Fatalf("wrong pending redemptions %+v", lb.PendingRedemptions)
+	}
+
+	// A refunded swap is neither locked nor pending redemption.
+	swapped.MetaData.Proof.RefundCoin = encode.RandomBytes(36)
+	lb, err = tCore.LockedBalance(tUTXOAssetB.ID)
+	if err != nil {
+		t.Fatalf("LockedBalance error: %v", err)
+	}
+	if len(lb.PendingRedemptions) != 0 {
+		t.Fatalf("refunded match pending redemption %+v", lb.PendingRedemptions)
+	}
+
+	if _, err := tCore.LockedBalance(12345); err == nil {
+		t.Fatalf("no error for missing wallet")
+	}
+}
+
 func TestCompareServerMatches(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -6366,6 +7096,135 @@ func TestHandleEpochOrderMsg(t *testing.T) {
 	}
 }
 
+func TestBookResync(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	book := newBookie(rig.dc, tUTXOAssetA.ID, tUTXOAssetB.ID, nil, tLogger)
+	if err := book.Reset(&msgjson.OrderBook{MarketID: tDcrBtcMktName, Seq: 1}); err != nil {
+		t.Fatalf("Reset error: %v", err)
+	}
+	rig.dc.books[tDcrBtcMktName] = book
+
+	epochNote := func(seq uint64) *msgjson.Message {
+		oid := ordertest.RandomOrderID()
+		msg, _ := msgjson.NewNotification(msgjson.EpochOrderRoute, &msgjson.EpochOrderNote{
+			BookOrderNote: msgjson.BookOrderNote{
+				OrderNote: msgjson.OrderNote{
+					Seq:      seq,
+					MarketID: tDcrBtcMktName,
+					OrderID:  oid[:],
+				},
+				TradeNote: msgjson.TradeNote{
+					Side:     msgjson.BuyOrderNum,
+					Rate:     4,
+					Quantity: 10,
+				},
+			},
+			Epoch: 1,
+		})
+		return msg
+	}
+	checkEpochOrders := func(n int) {
+		t.Helper()
+		_, _, epochOrders := book.Orders()
+		if len(epochOrders) != n {
+			t.Fatalf("expected %d epoch orders, got %d", n, len(epochOrders))
+		}
+	}
+	waitResync := func() {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			book.resyncMtx.Lock()
+			resyncing := book.resyncing
+			book.resyncMtx.Unlock()
+			if !resyncing {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("resync did not finish")
+	}
+
+	// Seq 2 is missed. The resync response includes both 2 and 3. Seq 4
+	// arrives during the resync, and is applied after it.
+	note2, note3 := epochNote(2), epochNote(3)
+	rig.ws.queueResponse(msgjson.BookResyncRoute, func(msg *msgjson.Message, f msgFunc) error {
+		req := new(msgjson.BookResync)
+		if err := msg.Unmarshal(req); err != nil {
+			t.Fatalf("error unmarshaling book_resync request: %v", err)
+		}
+		if req.Seq != 1 {
+			t.Fatalf("wrong resync seq. wanted 1, got %d", req.Seq)
+		}
+		if err := handleEpochOrderMsg(rig.core, rig.dc, epochNote(4)); err != nil {
+			t.Fatalf("handleEpochOrderMsg error: %v", err)
+		}
+		resp, _ := msgjson.NewResponse(msg.ID, &msgjson.BookResyncResult{
+			MarketID: tDcrBtcMktName,
+			Updates:  []*msgjson.Message{note2, note3},
+		}, nil)
+		f(resp)
+		return nil
+	})
+	if err := handleEpochOrderMsg(rig.core, rig.dc, note3); err != nil {
+		t.Fatalf("handleEpochOrderMsg error: %v", err)
+	}
+	waitResync()
+	if seq := book.Seq(); seq != 4 {
+		t.Fatalf("wrong seq after resync. wanted 4, got %d", seq)
+	}
+	checkEpochOrders(3)
+
+	// A stale update is ignored.
+	if err := handleEpochOrderMsg(rig.core, rig.dc, epochNote(4)); err != nil {
+		t.Fatalf("handleEpochOrderMsg error: %v", err)
+	}
+	checkEpochOrders(3)
+
+	// Too far behind, so the server sends a snapshot. The snapshot predates
+	// the update that triggered the resync, which is applied after it.
+	oid := ordertest.RandomOrderID()
+	rig.ws.queueResponse(msgjson.BookResyncRoute, func(msg *msgjson.Message, f msgFunc) error {
+		resp, _ := msgjson.NewResponse(msg.ID, &msgjson.BookResyncResult{
+			MarketID: tDcrBtcMktName,
+			Snapshot: &msgjson.OrderBook{
+				MarketID: tDcrBtcMktName,
+				Seq:      9,
+				Orders: []*msgjson.BookOrderNote{{
+					OrderNote: msgjson.OrderNote{OrderID: oid[:]},
+					TradeNote: msgjson.TradeNote{
+						Side:     msgjson.SellOrderNum,
+						Quantity: 10,
+						Rate:     5,
+					},
+				}},
+			},
+		}, nil)
+		f(resp)
+		return nil
+	})
+	if err := handleEpochOrderMsg(rig.core, rig.dc, epochNote(10)); err != nil {
+		t.Fatalf("handleEpochOrderMsg error: %v", err)
+	}
+	waitResync()
+	if seq := book.Seq(); seq != 10 {
+		t.Fatalf("wrong seq after snapshot. wanted 10, got %d", seq)
+	}
+	if _, sells, _ := book.Orders(); len(sells) != 1 {
+		t.Fatalf("expected 1 sell order after snapshot, got %d", len(sells))
+	}
+	checkEpochOrders(1)
+
+	// If the resync fails, the update is applied anyway.
+	if err := handleEpochOrderMsg(rig.core, rig.dc, epochNote(12)); err != nil {
+		t.Fatalf("handleEpochOrderMsg error: %v", err)
+	}
+	waitResync()
+	if seq := book.Seq(); seq != 12 {
+		t.Fatalf("wrong seq after failed resync. wanted 12, got %d", seq)
+	}
+}
+
 func makeMatchProof(preimages []order.Preimage, commitments []order.Commitment) (msgjson.Bytes, msgjson.Bytes, error) {
 	if len(preimages) != len(commitments) {
 		return nil, nil, fmt.Errorf("expected equal number of preimages and commitments")
@@ -6484,6 +7343,40 @@ func Test_marketTrades(t *testing.T) {
 	}
 }
 
+func TestRestoreDBSnapshot(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	ord := &order.LimitOrder{P: order.Prefix{ServerTime: time.Now()}}
+	rig.dc.trades[ord.ID()] = &trackedTrade{
+		Order:  ord,
+		preImg: newPreimage(),
+		dc:     rig.dc,
+		metaData: &db.OrderMetaData{
+			Status: order.OrderStatusBooked,
+		},
+		matches: make(map[order.MatchID]*matchTracker),
+	}
+
+	// Active orders error.
+	err := tCore.RestoreDBSnapshot(tPW, "snap")
+	if !errorHasCode(err, activeOrdersErr) {
+		t.Fatalf("expected active orders error, got %v", err)
+	}
+	if rig.db.restoredSnapshot != "" {
+		t.Fatalf("snapshot restored with active orders")
+	}
+
+	rig.dc.trades = make(map[order.OrderID]*trackedTrade)
+	if err := tCore.RestoreDBSnapshot(tPW, "snap"); err != nil {
+		t.Fatalf("RestoreDBSnapshot error: %v", err)
+	}
+	if rig.db.restoredSnapshot != "snap" {
+		t.Fatalf("snapshot not restored")
+	}
+}
+
 func TestLogout(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -6669,6 +7562,18 @@ func TestAddrHost(t *testing.T) {
 		name: "ipv6 host and port",
 		addr: "[1:2::]:5758",
 		want: "[1:2::]:5758",
+	}, {
+		name: "wss onion host",
+		addr: "wss://somedex.onion",
+		want: "wss://somedex.onion:7232",
+	}, {
+		name: "wss onion host and port",
+		addr: "wss://somedex.onion:7232",
+		want: "wss://somedex.onion:7232",
+	}, {
+		name: "wss host",
+		addr: "wss://thatonedex.com",
+		want: "thatonedex.com:7232",
 	}, {
 		name: "empty address",
 		want: "localhost:7232",
@@ -7561,12 +8466,17 @@ func TestSetWalletPassword(t *testing.T) {
 	tXyzWallet.unlockErr = nil
 
 	// SetWalletPassword db error
+	oldEncPW := xyzWallet.encPW()
 	rig.db.setWalletPwErr = tErr
 	err = tCore.SetWalletPassword(tPW, assetID, newPW)
 	if !errorHasCode(err, dbErr) {
 		t.Fatalf("wrong error for missing wallet: %v", err)
 	}
 	rig.db.setWalletPwErr = nil
+	// The old password is restored.
+	if !bytes.Equal(xyzWallet.encPW(), oldEncPW) {
+		t.Fatalf("xcWallet encPW not restored after db error")
+	}
 
 	// Success
 	err = tCore.SetWalletPassword(tPW, assetID, newPW)
@@ -7581,6 +8491,29 @@ func TestSetWalletPassword(t *testing.T) {
 	}
 }
 
+func TestAmnesiaWalletAndDEXSetup(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	tCore.cfg.Amnesia = true
+
+	for name, f := range map[string]func() error{
+		"ReconfigureWallet": func() error {
+			return tCore.ReconfigureWallet(tPW, nil, &WalletForm{AssetID: tUTXOAssetA.ID})
+		},
+		"SetWalletPassword": func() error {
+			return tCore.SetWalletPassword(tPW, tUTXOAssetA.ID, []byte("def"))
+		},
+		"AddDEX": func() error {
+			return tCore.AddDEX(tPW, tDexHost, nil)
+		},
+	} {
+		if err := f(); !errorHasCode(err, amnesiaErr) {
+			t.Fatalf("%s: wrong error in amnesia mode: %v", name, err)
+		}
+	}
+}
+
 func TestHandlePenaltyMsg(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -7648,6 +8581,76 @@ func TestHandlePenaltyMsg(t *testing.T) {
 	}
 }
 
+func TestHandleConfigUpdateMsg(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	dc := rig.dc
+	origCfg := dc.config()
+
+	update := &msgjson.ConfigUpdate{
+		CancelMax:        0.5,
+		FreeCancels:      true,
+		PenaltyThreshold: origCfg.PenaltyThreshold + 5,
+	}
+	note, _ := msgjson.NewNotification(msgjson.ConfigUpdateRoute, update)
+	if err := handleConfigUpdateMsg(rig.core, dc, note); err != nil {
+		t.Fatalf("handleConfigUpdateMsg error: %v", err)
+	}
+	cfg := dc.config()
+	if cfg.CancelMax != update.CancelMax || !cfg.FreeCancels || cfg.PenaltyThreshold != update.PenaltyThreshold {
+		t.Fatalf("config not updated: %+v", cfg)
+	}
+	if cfg == origCfg || len(cfg.Markets) != len(origCfg.Markets) {
+		t.Fatalf("config not copied")
+	}
+
+	// A market update replaces only the named market.
+	mkt := *origCfg.Markets[0]
+	mkt.BaseSwapConf = 7
+	update.Markets = []*msgjson.Market{&mkt}
+	note, _ = msgjson.NewNotification(msgjson.ConfigUpdateRoute, update)
+	if err := handleConfigUpdateMsg(rig.core, dc, note); err != nil {
+		t.Fatalf("handleConfigUpdateMsg error: %v", err)
+	}
+	cfg = dc.config()
+	if cfg.Markets[0].SwapConf(mkt.Base) != 7 {
+		t.Fatalf("market swapConf not updated")
+	}
+	if origCfg.Markets[0].BaseSwapConf == 7 {
+		t.Fatalf("original market config modified")
+	}
+
+	badNote, _ := msgjson.NewNotification(msgjson.ConfigUpdateRoute, "fake")
+	if err := handleConfigUpdateMsg(rig.core, dc, badNote); err == nil {
+		t.Fatalf("no error for bad note")
+	}
+}
+
+func TestAccountReputation(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+
+	rig.ws.queueResponse(msgjson.ReputationRoute, func(msg *msgjson.Message, f msgFunc) error {
+		resp, _ := msgjson.NewResponse(msg.ID, &msgjson.ReputationReport{
+			Reputation:     &account.Reputation{BondedTier: 1, Score: 5},
+			SwapsCompleted: 5,
+		}, nil)
+		f(resp)
+		return nil
+	})
+	report, err := rig.core.AccountReputation(tDexHost)
+	if err != nil {
+		t.Fatalf("AccountReputation error: %v", err)
+	}
+	if report.SwapsCompleted != 5 || report.Reputation.Score != 5 {
+		t.Fatalf("wrong report %+v", report)
+	}
+
+	if _, err = rig.core.AccountReputation("unknown.tld:7232"); err == nil {
+		t.Fatalf("no error for unknown host")
+	}
+}
+
 func TestPreimageSync(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -9600,12 +10603,16 @@ func TestPreOrder(t *testing.T) {
 		Estimate: &asset.SwapEstimate{
 			MaxFees:            1001,
 			Lots:               5,
+			Value:              quoteConvertedLotSize * 5,
 			RealisticBestCase:  15,
 			RealisticWorstCase: 20,
 		},
 	}
 
 	tBtcWallet.preSwap = preSwap
+	tDcrWallet.preSwap = preSwap
+	tBtcWallet.maxOrder = &asset.SwapEstimate{Lots: 8}
+	tDcrWallet.maxOrder = &asset.SwapEstimate{Lots: 12}
 
 	preRedeem := &asset.PreRedeem{
 		Estimate: &asset.RedeemEstimate{
@@ -9644,6 +10651,14 @@ func TestPreOrder(t *testing.T) {
 	// This is a buy order, so the from asset is the quote asset.
 	compUint64("PreOrder.FeeSuggestion.quote", quoteFeeRate, tBtcWallet.preSwapForm.FeeSuggestion)
 	compUint64("PreOrder.FeeSuggestion.base", baseFeeRate, tDcrWallet.preRedeemForm.FeeSuggestion)
+	compUint64("Lots", 5, preOrder.Lots)
+	compUint64("Qty", dcrBtcLotSize*5, preOrder.Qty)
+	compUint64("MaxLots", 8, preOrder.MaxLots)
+	compUint64("MaxQty", dcrBtcLotSize*8, preOrder.MaxQty)
+	if len(preOrder.Locked) != 1 {
+		t.Fatalf("expected locked funds for 1 asset, got %d", len(preOrder.Locked))
+	}
+	compUint64("Locked", quoteConvertedLotSize*5+1001, preOrder.Locked[tUTXOAssetB.ID])
 
 	// Missing book is an error
 	delete(dc.books, tDcrBtcMktName)
@@ -9655,10 +10670,13 @@ func TestPreOrder(t *testing.T) {
 
 	// Exercise the market sell path too.
 	form.Sell = true
-	_, err = tCore.PreOrder(form)
+	preOrder, err = tCore.PreOrder(form)
 	if err != nil {
 		t.Fatalf("PreOrder market sell error: %v", err)
 	}
+	// A sell is funded by the base asset.
+	compUint64("MaxLots sell", 12, preOrder.MaxLots)
+	compUint64("Locked sell", preSwap.Estimate.Value+preSwap.Estimate.MaxFees, preOrder.Locked[tUTXOAssetA.ID])
 
 	// Market orders have to have a market to make estimates.
 	book.Unbook(&msgjson.UnbookOrderNote{
@@ -9759,9 +10777,10 @@ func TestRefreshServerConfig(t *testing.T) {
 	const newAPIVer = ^uint16(0) - 1
 	supportedAPIVers = append(supportedAPIVers, int32(newAPIVer))
 
-	queueConfig := func(err *msgjson.Error, apiVer uint16) {
+	queueConfig := func(err *msgjson.Error, minAPIVer, apiVer uint16) {
 		rig.ws.queueResponse(msgjson.ConfigRoute, func(msg *msgjson.Message, f msgFunc) error {
 			cfg := *rig.dc.cfg
+			cfg.MinAPIVersion = minAPIVer
 			cfg.APIVersion = apiVer
 			resp, _ := msgjson.NewResponse(msg.ID, cfg, err)
 			f(resp)
@@ -9771,19 +10790,34 @@ func TestRefreshServerConfig(t *testing.T) {
 	tests := []struct {
 		name       string
 		configErr  *msgjson.Error
+		gotMinVer  uint16
 		gotAPIVer  uint16
 		marketBase uint32
+		wantAPIVer int32
 		wantErr    bool
 	}{{
 		name:       "ok",
 		marketBase: tUTXOAssetA.ID,
 		gotAPIVer:  newAPIVer,
+		wantAPIVer: int32(newAPIVer),
+	}, {
+		name:       "server newer than client",
+		marketBase: tUTXOAssetA.ID,
+		gotAPIVer:  ^uint16(0),
+		wantAPIVer: int32(newAPIVer),
+	}, {
+		name:       "older version in server range",
+		marketBase: tUTXOAssetA.ID,
+		gotMinVer:  serverdex.V1APIVersion,
+		gotAPIVer:  serverdex.APIVersion + 1,
+		wantAPIVer: serverdex.APIVersion,
 	}, {
 		name:      "unable to fetch config",
 		configErr: new(msgjson.Error),
 		wantErr:   true,
 	}, {
 		name:       "api not in wanted versions",
+		gotMinVer:  ^uint16(0),
 		gotAPIVer:  ^uint16(0),
 		marketBase: tUTXOAssetA.ID,
 		wantErr:    true,
@@ -9796,7 +10830,7 @@ func TestRefreshServerConfig(t *testing.T) {
 
 	for _, test := range tests {
 		rig.dc.cfg.Markets[0].Base = test.marketBase
-		queueConfig(test.configErr, test.gotAPIVer)
+		queueConfig(test.configErr, test.gotMinVer, test.gotAPIVer)
 		_, err := rig.dc.refreshServerConfig()
 		if test.wantErr {
 			if err == nil {
@@ -9807,6 +10841,9 @@ func TestRefreshServerConfig(t *testing.T) {
 		if err != nil {
 			t.Fatalf("unexpected error for test %q: %v", test.name, err)
 		}
+		if ver := rig.dc.apiVersion(); ver != test.wantAPIVer {
+			t.Fatalf("%s: wanted API version %d, got %d", test.name, test.wantAPIVer, ver)
+		}
 	}
 }
 
@@ -10167,6 +11204,87 @@ func TestDeleteArchivedRecords(t *testing.T) {
 	}
 }
 
+func TestExportTradeHistory(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	_, lo, dbOrder, match, _ := generateMatch(rig, tUTXOAssetA.ID, tUTXOAssetB.ID)
+	const stamp = 1_700_000_000_000
+	match.MetaData.Stamp = stamp
+	match.Status = order.MatchComplete
+	match.MetaData.Proof.TakerRedeem = encode.RandomBytes(36)
+	otherMatchID := ordertest.RandomMatchID()
+	dbOrder.MetaData.FeeTxs = []*db.OrderFeeTx{{
+		Type:    db.FeeTxSwap,
+		CoinID:  encode.RandomBytes(36),
+		Fees:    1000,
+		Matches: []order.MatchID{match.MatchID, otherMatchID},
+	}, {
+		Type:    db.FeeTxRedeem,
+		CoinID:  encode.RandomBytes(36),
+		Fees:    300,
+		Matches: []order.MatchID{match.MatchID},
+	}}
+	rig.db.orders = []*db.MetaOrder{dbOrder}
+	rig.db.matchesForOID = []*db.MetaMatch{match}
+
+	cord := coreOrderFromTrade(lo, dbOrder.MetaData)
+	if swapFees, redeemFees := matchFees(cord, match.MatchID[:]); swapFees != 500 || redeemFees != 300 {
+		t.Fatalf("wrong match fees: swap = %d, redeem = %d", swapFees, redeemFees)
+	}
+
+	export := func(since, until time.Time) (int, [][]string) {
+		t.Helper()
+		var buf strings.Builder
+		n, err := tCore.ExportTradeHistory(csv.NewWriter(&buf), since, until)
+		if err != nil {
+			t.Fatalf("ExportTradeHistory error: %v", err)
+		}
+		rows, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+		if err != nil {
+			t.Fatalf("error reading CSV: %v", err)
+		}
+		return n, rows
+	}
+
+	matchTime := time.UnixMilli(stamp)
+	n, rows := export(matchTime.Add(-time.Hour), matchTime.Add(time.Hour))
+	if n != 1 || len(rows) != 2 {
+		t.Fatalf("wanted 1 match exported, got %d, %d rows", n, len(rows))
+	}
+	if len(rows[1]) != len(tradeHistoryHeader) || rows[1][4] != match.MatchID.String() {
+		t.Fatalf("wrong row %v", rows[1])
+	}
+
+	// Open range.
+	if n, _ = export(time.Time{}, time.Time{}); n != 1 {
+		t.Fatalf("wanted 1 match exported for open range, got %d", n)
+	}
+
+	// Out of range.
+	if n, rows = export(matchTime.Add(time.Hour), time.Time{}); n != 0 || len(rows) != 1 {
+		t.Fatalf("wanted only the header for a later range, got %d matches, %d rows", n, len(rows))
+	}
+
+	// Refunded matches are not exported.
+	match.MetaData.Proof.RefundCoin = encode.RandomBytes(36)
+	if n, _ = export(time.Time{}, time.Time{}); n != 0 {
+		t.Fatalf("refunded match exported")
+	}
+	match.MetaData.Proof.RefundCoin = nil
+
+	// Nor are matches that are not complete.
+	match.Status = order.MakerSwapCast
+	if n, _ = export(time.Time{}, time.Time{}); n != 0 {
+		t.Fatalf("incomplete match exported")
+	}
+
+	if _, err := tCore.ExportTradeHistory(csv.NewWriter(io.Discard), matchTime, matchTime.Add(-time.Hour)); err == nil {
+		t.Fatalf("no error for inverted range")
+	}
+}
+
 func TestLCM(t *testing.T) {
 	tests := []struct {
 		name                                  string
@@ -10306,6 +11424,59 @@ func TestFiatConversions(t *testing.T) {
 	}
 }
 
+func TestPortfolioValue(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	dcrWallet, _ := newTWallet(tUTXOAssetA.ID)
+	dcrWallet.balance = &WalletBalance{
+		Balance: &db.Balance{
+			Balance: asset.Balance{
+				Available: 2e8,
+				Immature:  1e8,
+				Locked:    1e8,
+			},
+		},
+		ContractLocked: 5e7,
+		BondLocked:     5e7,
+	}
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, _ := newTWallet(tUTXOAssetB.ID)
+	btcWallet.balance = &WalletBalance{
+		Balance: &db.Balance{
+			Balance: asset.Balance{Available: 1e7},
+		},
+	}
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+
+	// Without rates, the balances are listed without value.
+	pv := tCore.PortfolioValue()
+	if len(pv.Assets) != 2 || pv.Total != 0 {
+		t.Fatalf("wrong portfolio without rates: %+v", pv)
+	}
+	if pv.Assets[0].AssetID != tUTXOAssetB.ID || pv.Assets[1].Balance != 5e8 || pv.Assets[1].Value != 0 {
+		t.Fatalf("wrong asset values without rates: %+v, %+v", pv.Assets[0], pv.Assets[1])
+	}
+
+	for token := range fiatRateFetchers {
+		tCore.fiatRateSources[token] = newCommonRateSource(tFetcher)
+	}
+	tCore.refreshFiatRates(tCtx)
+
+	pv = tCore.PortfolioValue()
+	btcVal, dcrVal := pv.Assets[0], pv.Assets[1]
+	if dcrVal.Rate != 45 || dcrVal.Value != 5*45 {
+		t.Fatalf("wrong dcr value %+v", dcrVal)
+	}
+	if btcVal.Rate != 32000 || btcVal.Value != 0.1*32000 {
+		t.Fatalf("wrong btc value %+v", btcVal)
+	}
+	if pv.Total != dcrVal.Value+btcVal.Value {
+		t.Fatalf("wrong total %f", pv.Total)
+	}
+}
+
 func TestValidateAddress(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
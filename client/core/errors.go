@@ -6,6 +6,8 @@ package core
 import (
 	"errors"
 	"fmt"
+
+	"decred.org/dcrdex/dex/msgjson"
 )
 
 // Error codes here are used on the frontend.
@@ -52,6 +54,11 @@ const (
 	bondTimeErr
 	bondAssetErr
 	bondPostErr // TODO
+	addressBookErr
+	restrictedAddrErr
+	lowLiquidityErr
+	amnesiaErr
+	orderStepErr
 )
 
 // Error is an error code and a wrapped error.
@@ -107,6 +114,49 @@ func UnwrapErr(err error) error {
 	return UnwrapErr(InnerErr)
 }
 
+// OrderStepError is the error from Trade and TradeAsync for an order with a
+// rate that is not a multiple of the market's rate step, or a quantity that is
+// not a multiple of the lot size. The embedded OrderStepHint has the nearest
+// valid values, which can be offered to the user as corrections. The check is
+// made before the order is submitted, and is repeated for the server's
+// OrderParameterErrors that include a hint.
+type OrderStepError struct {
+	*msgjson.OrderStepHint
+	err error // server error, nil if caught before submission
+}
+
+// Error returns the error string with the suggested corrections. Satisfies the
+// error interface.
+func (e *OrderStepError) Error() string {
+	stepName := "rate step"
+	if e.Field == msgjson.StepFieldQty {
+		stepName = "lot size"
+	}
+	s := fmt.Sprintf("order %s %d is not a multiple of the %s %d. ", e.Field, e.Value, stepName, e.Step)
+	if e.Lower > 0 {
+		s += fmt.Sprintf("The nearest valid values are %d and %d", e.Lower, e.Upper)
+	} else {
+		s += fmt.Sprintf("The smallest valid value is %d", e.Upper)
+	}
+	if e.err != nil {
+		s += fmt.Sprintf(" (%v)", e.err)
+	}
+	return s
+}
+
+// Unwrap returns the server error, if any.
+func (e *OrderStepError) Unwrap() error {
+	return e.err
+}
+
+// newOrderStepError creates an orderStepErr-coded OrderStepError for the
+// value of the field with the given step size.
+func newOrderStepError(field string, value, step uint64) error {
+	return codedError(orderStepErr, &OrderStepError{
+		OrderStepHint: msgjson.NewOrderStepHint(field, value, step),
+	})
+}
+
 var (
 	ErrAccountSuspended = errors.New("may not trade while account is suspended")
 )
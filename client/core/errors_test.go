@@ -3,7 +3,10 @@ package core
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+
+	"decred.org/dcrdex/dex/msgjson"
 )
 
 type testErr string
@@ -84,3 +87,46 @@ func TestUnwrapErr(t *testing.T) {
 		}
 	}
 }
+
+func TestTranslateServerError(t *testing.T) {
+	c := new(Core)
+	c.intl.Store(&locale{errs: originServerErrors})
+
+	// An error with a translated code is prefixed with the message, and the
+	// msgjson.Error is still in the chain.
+	msgErr := msgjson.NewError(msgjson.FundingError, "bad coins")
+	err := c.translateServerError(fmt.Errorf("request failed: %w", msgErr))
+	wantPrefix := originServerErrors[msgjson.FundingError].T
+	if !strings.HasPrefix(err.Error(), wantPrefix) || !strings.Contains(err.Error(), "bad coins") {
+		t.Fatalf("wrong error text %q", err)
+	}
+	var mErr *msgjson.Error
+	if !errors.As(err, &mErr) || mErr.Code != msgjson.FundingError {
+		t.Fatalf("msgjson.Error not in the error chain")
+	}
+
+	// Errors with an order step hint become OrderStepErrors.
+	msgErr = msgjson.NewErrorWithData(msgjson.OrderParameterError,
+		msgjson.NewOrderStepHint(msgjson.StepFieldRate, 1234, 100), "bad rate")
+	err = c.translateServerError(fmt.Errorf("request failed: %w", msgErr))
+	var stepErr *OrderStepError
+	if !errors.As(err, &stepErr) || stepErr.Lower != 1200 || stepErr.Upper != 1300 {
+		t.Fatalf("expected OrderStepError, got %v", err)
+	}
+	if !errorHasCode(err, orderStepErr) || !errors.As(err, &mErr) {
+		t.Fatalf("wrong code or msgjson.Error not in the error chain")
+	}
+	if !strings.Contains(err.Error(), "1200 and 1300") {
+		t.Fatalf("no suggestion in error text %q", err)
+	}
+
+	// Errors without a translated code are unmodified.
+	for _, err := range []error{
+		msgjson.NewError(msgjson.SerializationError, "bad bytes"),
+		errors.New("not a server error"),
+	} {
+		if translated := c.translateServerError(err); translated != err {
+			t.Fatalf("error %q modified to %q", err, translated)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"math"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/order"
+)
+
+// spotRate is the market's spot rate, or the mid-gap rate of the book if there
+// is no spot rate. Zero is returned if neither is known.
+func (dc *dexConnection) spotRate(base, quote uint32) uint64 {
+	dc.spotsMtx.RLock()
+	spot := dc.spots[marketName(base, quote)]
+	dc.spotsMtx.RUnlock()
+	if spot != nil && spot.Rate > 0 {
+		return spot.Rate
+	}
+	if midGap, err := dc.midGap(base, quote); err == nil {
+		return midGap
+	}
+	return 0
+}
+
+// rateDrift is the difference, in percent, between the rate and the reference
+// rate.
+func rateDrift(rate, refRate uint64) float64 {
+	return math.Abs(float64(rate)-float64(refRate)) / float64(refRate) * 100
+}
+
+// staleOrderTopic checks a booked limit order against its TTL and MaxDrift.
+// The topic of the notification for the cancellation is returned, or an empty
+// topic if the order should stay on the book. drift is set for
+// TopicOrderDrifted.
+func staleOrderTopic(lo *order.LimitOrder, ttl uint64, maxDrift float64, refRate uint64, now time.Time) (topic Topic, drift float64) {
+	if ttl > 0 && now.Sub(lo.ServerTime) >= time.Duration(ttl)*time.Second {
+		return TopicOrderExpired, 0
+	}
+	if maxDrift > 0 && refRate > 0 {
+		if drift = rateDrift(lo.Rate, refRate); drift > maxDrift {
+			return TopicOrderDrifted, drift
+		}
+	}
+	return "", 0
+}
+
+// cancelStaleOrder cancels a booked order if its TTL has elapsed or its rate
+// has drifted too far from the market's spot rate. A failed cancel is retried
+// on the next tick. A stale cancel order does not block the retry, since
+// tryCancelTrade deletes it before submitting a new one.
+func (c *Core) cancelStaleOrder(t *trackedTrade) {
+	lo, ok := t.Order.(*order.LimitOrder)
+	if !ok {
+		return
+	}
+	t.mtx.RLock()
+	ttl, maxDrift := t.metaData.TTL, t.metaData.MaxDrift
+	booked := t.metaData.Status == order.OrderStatusBooked
+	cancelling := t.cancel != nil && !t.hasStaleCancelOrder()
+	t.mtx.RUnlock()
+	if !booked || cancelling || (ttl == 0 && maxDrift == 0) {
+		return
+	}
+
+	var refRate uint64
+	if maxDrift > 0 {
+		refRate = t.dc.spotRate(t.Base(), t.Quote())
+	}
+	topic, drift := staleOrderTopic(lo, ttl, maxDrift, refRate, time.Now())
+	if topic == "" {
+		return
+	}
+	if err := c.tryCancelTrade(t.dc, t); err != nil {
+		c.log.Errorf("Error canceling stale order %s: %v", t.ID(), err)
+		return
+	}
+	args := []any{t.token(), t.mktID, t.dc.acct.host}
+	if topic == TopicOrderDrifted {
+		args = append(args, drift)
+	}
+	subject, details := c.formatDetails(topic, args...)
+	c.notify(newOrderNote(topic, subject, details, db.WarningLevel, t.coreOrder()))
+}
@@ -0,0 +1,214 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+)
+
+// tradeHistoryPageSize is the number of orders loaded from the DB at a time
+// for a trade history export.
+const tradeHistoryPageSize = 100
+
+// tradeHistoryHeader is the header row of the trade history CSV.
+var tradeHistoryHeader = []string{
+	"Time",
+	"Host",
+	"Market",
+	"Order ID",
+	"Match ID",
+	"Side",
+	"Role",
+	"Rate",
+	"Base Quantity",
+	"Base Asset",
+	"Quote Quantity",
+	"Quote Asset",
+	"Swap Fees",
+	"Swap Fees Asset",
+	"Redemption Fees",
+	"Redemption Fees Asset",
+	"Swap Coin ID",
+	"Redeem Coin ID",
+	"Counterparty Swap Coin ID",
+}
+
+// newOrderReader creates an OrderReader for the order. The fee asset of a
+// token is its parent asset.
+func newOrderReader(cord *Order) (*OrderReader, error) {
+	feeAsset := func(assetID uint32) (string, dex.UnitInfo, error) {
+		if token := asset.TokenInfo(assetID); token != nil {
+			assetID = token.ParentID
+		}
+		ui, err := asset.UnitInfo(assetID)
+		if err != nil {
+			return "", dex.UnitInfo{}, fmt.Errorf("unable to get fee unit info for %v: %v", unbip(assetID), err)
+		}
+		return unbip(assetID), ui, nil
+	}
+
+	baseUnitInfo, err := asset.UnitInfo(cord.BaseID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get base unit info for %v: %v", cord.BaseSymbol, err)
+	}
+	baseFeeAssetSymbol, baseFeeUnitInfo, err := feeAsset(cord.BaseID)
+	if err != nil {
+		return nil, err
+	}
+	quoteUnitInfo, err := asset.UnitInfo(cord.QuoteID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get quote unit info for %v: %v", cord.QuoteSymbol, err)
+	}
+	quoteFeeAssetSymbol, quoteFeeUnitInfo, err := feeAsset(cord.QuoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderReader{
+		Order:               cord,
+		BaseUnitInfo:        baseUnitInfo,
+		BaseFeeUnitInfo:     baseFeeUnitInfo,
+		BaseFeeAssetSymbol:  baseFeeAssetSymbol,
+		QuoteUnitInfo:       quoteUnitInfo,
+		QuoteFeeUnitInfo:    quoteFeeUnitInfo,
+		QuoteFeeAssetSymbol: quoteFeeAssetSymbol,
+	}, nil
+}
+
+// completedMatch is true for a trade match that was redeemed and not refunded.
+func completedMatch(m *Match) bool {
+	return settledFilter(m) && m.Refund == nil
+}
+
+// matchFees is the match's share of the swap and redemption fees paid by the
+// order. The fees of a transaction that swapped or redeemed several matches
+// are split evenly between them.
+func matchFees(ord *Order, matchID dex.Bytes) (swapFees, redeemFees uint64) {
+	if ord.FeesPaid == nil {
+		return 0, 0
+	}
+	for _, tx := range ord.FeesPaid.Txs {
+		for _, mid := range tx.MatchIDs {
+			if !bytes.Equal(mid, matchID) {
+				continue
+			}
+			share := tx.Fees / uint64(len(tx.MatchIDs))
+			switch tx.Type {
+			case db.FeeTxSwap.String():
+				swapFees += share
+			case db.FeeTxRedeem.String():
+				redeemFees += share
+			}
+			break
+		}
+	}
+	return swapFees, redeemFees
+}
+
+// tradeHistoryRow formats the match as a row of the trade history CSV.
+func tradeHistoryRow(ord *OrderReader, m *Match) []string {
+	coinID := func(c *Coin) string {
+		if c == nil {
+			return ""
+		}
+		return c.StringID
+	}
+	swapFees, redeemFees := matchFees(ord.Order, m.MatchID)
+	swapFeeUnitInfo, redeemFeeUnitInfo := ord.QuoteFeeUnitInfo, ord.BaseFeeUnitInfo
+	if ord.Sell {
+		swapFeeUnitInfo, redeemFeeUnitInfo = redeemFeeUnitInfo, swapFeeUnitInfo
+	}
+	stamp := time.UnixMilli(int64(m.Stamp)).Local().Format(time.RFC3339Nano)
+	quoteQty := calc.BaseToQuote(m.Rate, m.Qty)
+	return []string{
+		stamp,                                    // Time
+		ord.Host,                                 // Host
+		ord.MarketID,                             // Market
+		ord.ID.String(),                          // Order ID
+		m.MatchID.String(),                       // Match ID
+		ord.SideString(),                         // Side
+		m.Side.String(),                          // Role
+		ord.formatRate(m.Rate),                   // Rate
+		formatQty(m.Qty, ord.BaseUnitInfo),       // Base Quantity
+		ord.BaseSymbol,                           // Base Asset
+		formatQty(quoteQty, ord.QuoteUnitInfo),   // Quote Quantity
+		ord.QuoteSymbol,                          // Quote Asset
+		formatQty(swapFees, swapFeeUnitInfo),     // Swap Fees
+		ord.FromFeeSymbol(),                      // Swap Fees Asset
+		formatQty(redeemFees, redeemFeeUnitInfo), // Redemption Fees
+		ord.ToFeeSymbol(),                        // Redemption Fees Asset
+		coinID(m.Swap),                           // Swap Coin ID
+		coinID(m.Redeem),                         // Redeem Coin ID
+		coinID(m.CounterSwap),                    // Counterparty Swap Coin ID
+	}
+}
+
+// ExportTradeHistory writes the completed matches of all orders, including
+// archived orders, to the CSV writer. Only matches made between since and
+// until are written. A zero until leaves the range open. The number of matches
+// written is returned.
+func (c *Core) ExportTradeHistory(w *csv.Writer, since, until time.Time) (int, error) {
+	if !until.IsZero() && until.Before(since) {
+		return 0, fmt.Errorf("end of the time range %s is before the start %s", until, since)
+	}
+	inRange := func(stamp uint64) bool {
+		t := time.UnixMilli(int64(stamp))
+		return !t.Before(since) && (until.IsZero() || !t.After(until))
+	}
+
+	if err := w.Write(tradeHistoryHeader); err != nil {
+		return 0, fmt.Errorf("error writing trade history CSV: %w", err)
+	}
+	var n int
+	// An order is last updated after its matches, so orders last updated
+	// before the range can't have matches in it.
+	filter := &db.OrderFilter{N: tradeHistoryPageSize}
+	if !since.IsZero() {
+		filter.Since = uint64(since.UnixMilli())
+	}
+	for {
+		ords, err := c.db.Orders(filter)
+		if err != nil {
+			return n, fmt.Errorf("error loading orders: %w", err)
+		}
+		for _, mOrd := range ords {
+			cord, err := c.coreOrderFromMetaOrder(mOrd)
+			if err != nil {
+				return n, err
+			}
+			var ordReader *OrderReader
+			for _, m := range cord.Matches {
+				if !completedMatch(m) || !inRange(m.Stamp) {
+					continue
+				}
+				if ordReader == nil {
+					if ordReader, err = newOrderReader(cord); err != nil {
+						return n, err
+					}
+				}
+				if err := w.Write(tradeHistoryRow(ordReader, m)); err != nil {
+					return n, fmt.Errorf("error writing trade history CSV: %w", err)
+				}
+				n++
+			}
+		}
+		if len(ords) < tradeHistoryPageSize {
+			break
+		}
+		filter.Offset = ords[len(ords)-1].Order.ID()
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return n, fmt.Errorf("error writing trade history CSV: %w", err)
+	}
+	return n, nil
+}
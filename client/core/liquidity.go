@@ -0,0 +1,78 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrdex/client/orderbook"
+	"decred.org/dcrdex/dex/calc"
+)
+
+const (
+	// liquidityWindow is how far back match volume is summed when evaluating a
+	// market's liquidity.
+	liquidityWindow = 24 * time.Hour
+	// liquidityCandleDur is the candle bin size used to sum match volume.
+	liquidityCandleDur = "1h"
+)
+
+// evaluateLiquidity checks the market's recent match volume and current book
+// against the size of an order that takes liquidity, i.e. a market order or
+// an immediate limit order. A standing limit order adds liquidity and can wait
+// for a counterparty, so it is not evaluated. Volume is only evaluated if the
+// candles for the market are cached. If the book is not synced, there is
+// nothing to evaluate and the report will have no warnings.
+func (dc *dexConnection) evaluateLiquidity(form *TradeForm) *LiquidityReport {
+	r := new(LiquidityReport)
+	if form.IsLimit && !form.TifNow {
+		return r
+	}
+	mktID := marketName(form.Base, form.Quote)
+	book := dc.bookie(mktID)
+	mktConf := dc.marketConfig(mktID)
+	if book == nil || mktConf == nil {
+		return r
+	}
+
+	baseQty := form.Qty
+	if !form.IsLimit && !form.Sell {
+		// Market buy quantity is in units of the quote asset.
+		midGap, err := book.MidGap()
+		if err != nil {
+			r.Warnings = append(r.Warnings, "the order book is empty")
+			return r
+		}
+		baseQty = calc.QuoteToBase(midGap, form.Qty)
+	}
+
+	if cache := book.candleCaches[liquidityCandleDur]; cache != nil && atomic.LoadUint32(&cache.on) == 1 {
+		cache.candleMtx.RLock()
+		_, vol, _, _ := cache.Delta(time.Now().Add(-liquidityWindow))
+		cache.candleMtx.RUnlock()
+		r.Volume = vol
+		if vol < baseQty {
+			r.Warnings = append(r.Warnings, "the order quantity exceeds the recent match volume")
+		}
+	}
+
+	// The fill for an immediate limit order is estimated without regard to the
+	// order's rate, so the book may be thinner than reported.
+	var fills []*orderbook.Fill
+	var filled bool
+	if form.IsLimit || form.Sell {
+		fills, filled = book.BestFill(form.Sell, form.Qty)
+	} else {
+		fills, filled = book.BestFillMarketBuy(form.Qty, mktConf.LotSize)
+	}
+	for _, fill := range fills {
+		r.Fillable += fill.Quantity
+	}
+	if !filled {
+		r.Warnings = append(r.Warnings, "the order book is too thin to fill the order")
+	}
+
+	return r
+}
@@ -189,6 +189,14 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Order auto-revoked"},
 		template: intl.Translation{T: "Order %s on market %s at %s revoked due to market suspension", Notes: "args: [token, market name, host]"},
 	},
+	TopicOrderExpired: {
+		subject:  intl.Translation{T: "Order expired"},
+		template: intl.Translation{T: "Order %s on market %s at %s is being canceled because its time-to-live elapsed", Notes: "args: [token, market name, host]"},
+	},
+	TopicOrderDrifted: {
+		subject:  intl.Translation{T: "Order drifted"},
+		template: intl.Translation{T: "Order %s on market %s at %s is being canceled because its rate is %.1f%% from the spot rate", Notes: "args: [token, market name, host, percent]"},
+	},
 	TopicMatchRecovered: {
 		subject:  intl.Translation{T: "Match recovered"},
 		template: intl.Translation{T: "Found maker's redemption (%s: %v) and validated secret for match %s", Notes: "args: [ticker, coin ID, match]"},
@@ -238,6 +246,14 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Wallet Password Updated"},
 		template: intl.Translation{T: "Password for %s wallet has been updated.", Notes: "args:  [ticker]"},
 	},
+	TopicWalletRescanStarted: {
+		subject:  intl.Translation{T: "Wallet rescan started"},
+		template: intl.Translation{T: "Rescan of %s wallet has started. Orders with missing funding coins will be checked again when it is complete.", Notes: "args: [ticker]"},
+	},
+	TopicWalletRescanComplete: {
+		subject:  intl.Translation{T: "Wallet rescan complete"},
+		template: intl.Translation{T: "Rescan of %s wallet is complete. Funding coins were found for %d orders, and %d orders are still missing funding coins.", Notes: "args: [ticker, orders restored, orders still missing coins]"},
+	},
 	TopicMarketSuspendScheduled: {
 		subject:  intl.Translation{T: "Market suspend scheduled"},
 		template: intl.Translation{T: "Market %s at %s is now scheduled for suspension at %v", Notes: "args: [market name, host, time]"},
@@ -1805,6 +1821,8 @@ func RegisterTranslations() {
 			r.Register(string(topic)+" template", &t.template)
 		}
 	}
+
+	registerServerErrorTranslations()
 }
 
 // CheckTopicLangs is used to report missing notification translations.
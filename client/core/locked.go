@@ -0,0 +1,135 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"fmt"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+)
+
+// feeAssetID is the ID of the asset that pays the asset's transaction fees,
+// which is the parent asset of a token.
+func feeAssetID(assetID uint32) uint32 {
+	if token := asset.TokenInfo(assetID); token != nil {
+		return token.ParentID
+	}
+	return assetID
+}
+
+// LockedBalance breaks down the wallet's locked funds by the orders, matches,
+// reserves, and bonds that they are locked for. The wallet's balance is
+// refreshed if the wallet is connected.
+func (c *Core) LockedBalance(assetID uint32) (*LockedBalance, error) {
+	w, exists := c.wallet(assetID)
+	if !exists {
+		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+	}
+	bal := w.state().Balance
+	if w.connected() {
+		var err error
+		if bal, err = c.walletBalance(w); err != nil {
+			return nil, fmt.Errorf("error getting %s balance: %w", unbip(assetID), err)
+		}
+	}
+	if bal == nil || bal.Balance == nil {
+		return nil, fmt.Errorf("%s wallet balance not known", unbip(assetID))
+	}
+
+	lb := &LockedBalance{
+		AssetID:            assetID,
+		Symbol:             unbip(assetID),
+		Locked:             bal.Locked,
+		Orders:             make([]*OrderLock, 0),
+		FeeReserves:        make([]*OrderLock, 0),
+		BondReserves:       bal.BondReserves,
+		Other:              make(map[asset.BalanceCategory]uint64),
+		Contracts:          make([]*MatchLock, 0),
+		PendingRedemptions: make([]*MatchLock, 0),
+	}
+	explained := bal.BondReserves
+	for cat, custom := range bal.Other {
+		if custom.Locked {
+			lb.Other[cat] = custom.Amount
+			explained += custom.Amount
+		}
+	}
+
+	for _, dc := range c.dexConnections() {
+		bonds, _ := dc.bondTotal(assetID)
+		lb.Bonds += bonds
+		for _, t := range dc.trackedTrades() {
+			explained += t.addLocks(lb)
+		}
+	}
+
+	if explained < lb.Locked {
+		lb.Unexplained = lb.Locked - explained
+	}
+	return lb, nil
+}
+
+// addLocks adds the trade's locked funds of the LockedBalance's asset to the
+// LockedBalance. The amount added that is part of the wallet's locked balance
+// is returned.
+func (t *trackedTrade) addLocks(lb *LockedBalance) (locked uint64) {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	host, oid := t.dc.acct.host, dex.Bytes(t.ID().Bytes())
+	orderLock := func(amt uint64) *OrderLock {
+		return &OrderLock{
+			Host:     host,
+			MarketID: t.mktID,
+			OrderID:  oid,
+			Amount:   amt,
+		}
+	}
+	matchLock := func(match *matchTracker, amt uint64) *MatchLock {
+		return &MatchLock{
+			Host:     host,
+			MarketID: t.mktID,
+			OrderID:  oid,
+			MatchID:  dex.Bytes(match.MatchID[:]),
+			Amount:   amt,
+		}
+	}
+
+	fromID, toID := t.fromAssetID, t.wallets.toWallet.AssetID
+	if fromID == lb.AssetID {
+		if amt := t.lockedAmount(); amt > 0 {
+			lb.Orders = append(lb.Orders, orderLock(amt))
+			locked += amt
+		}
+		for _, match := range t.matches {
+			if amt := t.unspentContractAmount(match); amt > 0 {
+				lb.Contracts = append(lb.Contracts, matchLock(match, amt))
+			}
+		}
+	}
+	if toID == lb.AssetID {
+		for _, match := range t.matches {
+			if amt := t.pendingRedemptionAmount(match); amt > 0 {
+				lb.PendingRedemptions = append(lb.PendingRedemptions, matchLock(match, amt))
+			}
+		}
+	}
+
+	var feeReserves uint64
+	if feeAssetID(fromID) == lb.AssetID {
+		if fromID != lb.AssetID {
+			feeReserves += t.parentLockedAmt()
+		}
+		feeReserves += t.refundLocked
+	}
+	if feeAssetID(toID) == lb.AssetID {
+		feeReserves += t.redemptionLocked
+	}
+	if feeReserves > 0 {
+		lb.FeeReserves = append(lb.FeeReserves, orderLock(feeReserves))
+		locked += feeReserves
+	}
+	return locked
+}
@@ -89,6 +89,7 @@ func (c *Core) notify(n Notification) {
 	}
 
 	c.logNote(n)
+	c.notifyWebhooks(n)
 
 	c.noteMtx.RLock()
 	for _, ch := range c.noteChans {
@@ -404,6 +405,8 @@ const (
 	TopicAsyncOrderFailure    Topic = "AsyncOrderFailure"
 	TopicAsyncOrderSubmitted  Topic = "AsyncOrderSubmitted"
 	TopicOrderQuantityTooHigh Topic = "OrderQuantityTooHigh"
+	TopicOrderExpired         Topic = "OrderExpired"
+	TopicOrderDrifted         Topic = "OrderDrifted"
 )
 
 func newOrderNote(topic Topic, subject, details string, severity db.Severity, corder *Order) *OrderNote {
@@ -600,6 +603,8 @@ const (
 	TopicWalletTypeDeprecated       Topic = "WalletTypeDeprecated"
 	TopicWalletPeersUpdate          Topic = "WalletPeersUpdate"
 	TopicBondWalletNotConnected     Topic = "BondWalletNotConnected"
+	TopicWalletRescanStarted        Topic = "WalletRescanStarted"
+	TopicWalletRescanComplete       Topic = "WalletRescanComplete"
 )
 
 func newWalletConfigNote(topic Topic, subject, details string, severity db.Severity, walletState *WalletState) *WalletConfigNote {
@@ -0,0 +1,121 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+)
+
+// monitorRescan emits wallet sync notifications until the wallet has finished
+// the rescan, and then looks again for the funding coins of trades that could
+// not load them.
+func (c *Core) monitorRescan(w *xcWallet) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(syncTickerPeriod)
+		defer ticker.Stop()
+		for !c.walletCheckAndNotify(w) {
+			select {
+			case <-ticker.C:
+			case <-w.connector.Done():
+				c.log.Warnf("%v wallet shut down before rescan completed.", w.Info().Name)
+				return
+			case <-c.ctx.Done():
+				return
+			}
+		}
+
+		restored, missing := c.reconcileFunding(w)
+		if restored > 0 {
+			c.updateAssetBalance(w.AssetID)
+		}
+		severity := db.Success
+		if missing > 0 {
+			severity = db.WarningLevel
+		}
+		subject, details := c.formatDetails(TopicWalletRescanComplete, unbip(w.AssetID), restored, missing)
+		c.notify(newWalletConfigNote(TopicWalletRescanComplete, subject, details, severity, w.state()))
+	}()
+}
+
+// needsFundingCoins checks whether the trade is funded by the wallet's asset
+// but its funding coins are not loaded, while it is booked or has matches that
+// were blocked for lack of funding coins. The IDs of the coins to load are
+// returned. This should be called with the mtx at least read locked.
+func (t *trackedTrade) needsFundingCoins() []dex.Bytes {
+	if t.hasFundingCoins() {
+		return nil
+	}
+	needed := t.metaData.Status == order.OrderStatusEpoch || t.metaData.Status == order.OrderStatusBooked
+	for _, match := range t.matches {
+		if match.swapErr == errNoFundingCoins {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil
+	}
+	coinIDs := t.Trade().Coins
+	if len(t.metaData.ChangeCoin) != 0 {
+		coinIDs = []order.CoinID{t.metaData.ChangeCoin}
+	}
+	byteIDs := make([]dex.Bytes, 0, len(coinIDs))
+	for _, cid := range coinIDs {
+		byteIDs = append(byteIDs, []byte(cid))
+	}
+	return byteIDs
+}
+
+// reconcileFunding tries to load the funding coins of trades funded by the
+// wallet that are missing them, e.g. after a rescan has found transactions the
+// wallet did not know about. Matches that were blocked for lack of funding
+// coins are unblocked. The numbers of trades that got their coins back and that
+// are still missing coins are returned.
+func (c *Core) reconcileFunding(w *xcWallet) (restored, missing int) {
+	for _, dc := range c.dexConnections() {
+		for _, t := range dc.trackedTrades() {
+			if t.fromAssetID != w.AssetID {
+				continue
+			}
+			t.mtx.RLock()
+			coinIDs := t.needsFundingCoins()
+			t.mtx.RUnlock()
+			if len(coinIDs) == 0 {
+				continue
+			}
+			coins, err := w.FundingCoins(coinIDs)
+			if err != nil || len(coins) == 0 {
+				c.log.Warnf("Funding coins for order %s still not found after %s wallet rescan: %v",
+					t.ID(), unbip(w.AssetID), err)
+				missing++
+				continue
+			}
+			t.restoreFundingCoins(coins)
+			c.log.Infof("Funding coins for order %s found after %s wallet rescan", t.ID(), unbip(w.AssetID))
+			restored++
+		}
+	}
+	return restored, missing
+}
+
+// restoreFundingCoins sets the trade's funding coins and unblocks matches that
+// were blocked for lack of them.
+func (t *trackedTrade) restoreFundingCoins(coins asset.Coins) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.coins = mapifyCoins(coins)
+	t.coinsLocked = true
+	for _, match := range t.matches {
+		if match.swapErr == errNoFundingCoins {
+			match.swapErr = nil
+		}
+	}
+}
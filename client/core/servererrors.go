@@ -0,0 +1,132 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/client/intl"
+	"decred.org/dcrdex/dex/msgjson"
+)
+
+// originServerErrors are actionable messages for the server error codes that
+// the user can do something about. Errors with other codes are reported with
+// the server's message.
+var originServerErrors = map[int]*intl.Translation{
+	msgjson.FundingError: {
+		T: "The server rejected the funding coins. Make sure your wallet is synced and the coins are unspent, then try again.",
+	},
+	msgjson.CoinAuthError: {
+		T: "The server could not verify that you own the funding coins. Make sure your wallet is unlocked and synced, then try again.",
+	},
+	msgjson.ClockRangeError: {
+		T: "Your system clock is out of sync with the server. Synchronize your clock and try again.",
+	},
+	msgjson.UnknownMarketError: {
+		T: "The market is not offered by the server. Reconnect to refresh the server's configuration.",
+	},
+	msgjson.MarketNotRunningError: {
+		T: "The market is not running. Try again after the market resumes.",
+	},
+	msgjson.OrderQuantityTooHigh: {
+		T: "The order quantity exceeds your trading limit. Increase your bond tier or wait for active orders to settle.",
+	},
+	msgjson.UnknownCancelTargetError: {
+		T: "The order is not active on the server. It may have already been filled or canceled.",
+	},
+	msgjson.TryAgainLaterError: {
+		T: "The server is busy. Try again later.",
+	},
+	msgjson.TooManyRequestsError: {
+		T: "Too many requests were sent to the server. Wait a moment and try again.",
+	},
+	msgjson.RPCInternal: {
+		T: "The server encountered an internal error. Try again later.",
+	},
+	msgjson.AccountNotFoundError: {
+		T: "The server does not have an account for you. Post a bond to create one.",
+	},
+	msgjson.UnpaidAccountError: {
+		T: "Your account is not yet active. Wait for your bond to confirm.",
+	},
+	msgjson.AccountClosedError: {
+		T: "Your account may not trade. Post a bond to restore trading privileges.",
+	},
+	msgjson.BondAlreadyConfirmingError: {
+		T: "A bond is already confirming. Wait for it to confirm before posting another.",
+	},
+	msgjson.OutdatedClientError: {
+		T: "The server no longer supports this version of the software. Upgrade to continue trading.",
+	},
+}
+
+// serverErrorLocales are the server error translations, keyed by language.
+// Languages without a translation for a code fall back to originServerErrors.
+var serverErrorLocales = map[string]map[int]*intl.Translation{
+	originLang: originServerErrors,
+}
+
+// registerServerErrorTranslations registers the server error translations with
+// the intl package for translator worksheet preparation.
+func registerServerErrorTranslations() {
+	const callerID = "server errors"
+
+	for lang, m := range serverErrorLocales {
+		r := intl.NewRegistrar(callerID, lang, len(m))
+		for code, t := range m {
+			info, _ := msgjson.LookupErrorCode(code)
+			r.Register(info.Name, t)
+		}
+	}
+}
+
+// serverError is a msgjson.Error from the server with a localized message.
+type serverError struct {
+	msg string
+	err error
+}
+
+// Error returns the localized message followed by the original error.
+func (e *serverError) Error() string {
+	return fmt.Sprintf("%s (%v)", e.msg, e.err)
+}
+
+// Unwrap returns the original error, which wraps the msgjson.Error.
+func (e *serverError) Unwrap() error {
+	return e.err
+}
+
+// serverErrorMessage returns the localized message for the server error code,
+// if there is one.
+func (c *Core) serverErrorMessage(code int) (string, bool) {
+	if t, found := c.locale().errs[code]; found {
+		return t.T, true
+	}
+	if t, found := originServerErrors[code]; found {
+		return t.T, true
+	}
+	return "", false
+}
+
+// translateServerError prefixes an error wrapping a msgjson.Error with the
+// localized message for the error's code. The error is returned unmodified if
+// it does not wrap a msgjson.Error, or if there is no message for the code.
+// Errors with an order step hint are converted to an OrderStepError.
+// The msgjson.Error remains in the error chain, so callers should continue to
+// check the code rather than the error's text.
+func (c *Core) translateServerError(err error) error {
+	var msgErr *msgjson.Error
+	if !errors.As(err, &msgErr) {
+		return err
+	}
+	if hint, found := msgErr.OrderStepHint(); found {
+		return codedError(orderStepErr, &OrderStepError{OrderStepHint: hint, err: err})
+	}
+	msg, found := c.serverErrorMessage(msgErr.Code)
+	if !found {
+		return err
+	}
+	return &serverError{msg: msg, err: err}
+}
@@ -0,0 +1,364 @@
+//go:build harness && !nolgpl
+
+package core
+
+// The soak test trades between two clients on the simnet harnesses for as long
+// as configured, with the same setup as the simulation tests. See
+// client/cmd/dextrader.
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
+)
+
+// Match outcome scores, mirroring server/auth. The soak test checks that the
+// server's scores agree with the outcomes of the matches it caused.
+const (
+	soakSuccessScore         = 1
+	soakNoSwapAsMakerScore   = -4
+	soakNoSwapAsTakerScore   = -11
+	soakExcessiveCancelScore = -5
+)
+
+// soakOutcome is the outcome of a match for one of the clients.
+type soakOutcome uint8
+
+const (
+	soakSuccess soakOutcome = iota
+	soakNoSwapAsMaker
+	soakNoSwapAsTaker
+)
+
+// SoakConfig is the configuration for RunSoak.
+type SoakConfig struct {
+	BaseSymbol        string
+	QuoteSymbol       string
+	RegistrationAsset string
+	Client1           *SimClient
+	Client2           *SimClient
+	// Duration is how long to trade. Zero trades until there is an error.
+	Duration time.Duration
+	// CancelRate is the probability that a client books and cancels an order
+	// before a trade.
+	CancelRate float64
+	// MissRate is the probability that a trade fails because the maker or
+	// taker does not send their swap.
+	MissRate float64
+	Logger   dex.Logger
+}
+
+type soakTest struct {
+	*simulationTest
+	cfg *SoakConfig
+	// outcomes are the outcomes of each client's recent matches, oldest
+	// first, limited to the server's scoring window.
+	outcomes map[*simulationClient][]soakOutcome
+
+	trades, misses, cancels int
+}
+
+// RunSoak trades between two clients until the configured Duration has passed
+// or an invariant is violated. Each round is a trade of a random size and rate
+// between the clients, with random maker and taker. Some trades fail because
+// one of the clients does not send their swap, and the server must penalize
+// that client. Before a trade, a client may book an order and cancel it. After
+// every round, RunSoak checks that
+//   - the expected balance changes occurred, and any refunds were made,
+//   - no funds are left locked for orders or in swap contracts,
+//   - the book is not crossed, and
+//   - each client's score, as reported by the server, agrees with the
+//     outcomes of the client's recent matches.
+func RunSoak(cfg *SoakConfig) error {
+	s, err := newSimulationTest(cfg.BaseSymbol, cfg.QuoteSymbol, cfg.RegistrationAsset, cfg.Client1, cfg.Client2, cfg.Logger)
+	if err != nil {
+		return err
+	}
+	if err := s.setup(cfg.Client1, cfg.Client2); err != nil {
+		return fmt.Errorf("setup error: %w", err)
+	}
+	defer func() {
+		s.cancel()
+		for _, c := range s.clients {
+			c.wg.Wait()
+		}
+	}()
+
+	t := &soakTest{
+		simulationTest: s,
+		cfg:            cfg,
+		outcomes:       make(map[*simulationClient][]soakOutcome, 2),
+	}
+
+	var deadline <-chan time.Time
+	if cfg.Duration > 0 {
+		deadline = time.After(cfg.Duration)
+	}
+	tStart := time.Now()
+	for round := 1; ; round++ {
+		select {
+		case <-deadline:
+			s.log.Infof("Soak test finished after %s. %d trades, %d missed swaps, %d cancels.",
+				time.Since(tStart).Round(time.Second), t.trades, t.misses, t.cancels)
+			return nil
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		default:
+		}
+		s.log.Infof("Starting soak round %d.", round)
+		if err := t.round(); err != nil {
+			return fmt.Errorf("round %d: %w", round, err)
+		}
+	}
+}
+
+// round runs one trade between the clients, optionally preceded by a cancel,
+// and checks the invariants.
+func (t *soakTest) round() error {
+	if rand.Float64() < t.cfg.CancelRate {
+		if err := t.bookAndCancel(t.clients[rand.Intn(2)]); err != nil {
+			return err
+		}
+	}
+
+	t.client1IsMaker = rand.Intn(2) == 0
+	t.client1.isSeller = rand.Intn(2) == 0
+	t.client2.isSeller = !t.client1.isSeller
+	maker, taker := t.client1, t.client2
+	if !t.client1IsMaker {
+		maker, taker = taker, maker
+	}
+	qty := uint64(1+rand.Intn(3)) * t.lotSize
+	rate := uint64(100+rand.Intn(200)) * t.rateStep
+
+	// simpleTradeTest disables the wallets of the client that should miss
+	// their swap. For a missed maker swap, both clients' wallets are disabled,
+	// but the taker is not at fault.
+	finalStatus := order.MatchConfirmed
+	var atFault *simulationClient
+	var miss soakOutcome
+	if rand.Float64() < t.cfg.MissRate {
+		if rand.Intn(2) == 0 {
+			finalStatus, atFault, miss = order.NewlyMatched, maker, soakNoSwapAsMaker
+		} else {
+			finalStatus, atFault, miss = order.MakerSwapCast, taker, soakNoSwapAsTaker
+		}
+		if !t.canMiss(atFault, miss) {
+			t.log.Infof("Client %s cannot miss another swap without a penalty. Trading normally.", atFault.name)
+			finalStatus, atFault = order.MatchConfirmed, nil
+		}
+	}
+
+	if err := t.simpleTradeTest(qty, rate, finalStatus); err != nil {
+		return err
+	}
+	t.trades++
+	if atFault != nil {
+		t.misses++
+		t.record(atFault, miss)
+	} else {
+		t.record(maker, soakSuccess)
+		t.record(taker, soakSuccess)
+	}
+
+	for _, c := range t.clients {
+		if err := t.checkSettled(c); err != nil {
+			return err
+		}
+		if err := t.checkReputation(c); err != nil {
+			return err
+		}
+	}
+	return t.checkBook()
+}
+
+// record adds an expected match outcome for the client.
+func (t *soakTest) record(c *simulationClient, outcome soakOutcome) {
+	t.outcomes[c] = append(t.outcomes[c], outcome)
+}
+
+// canMiss checks that the client's score would stay above the penalty
+// threshold with another missed swap. A penalized client could not trade.
+func (t *soakTest) canMiss(c *simulationClient, miss soakOutcome) bool {
+	report, err := c.core.AccountReputation(dexHost)
+	if err != nil {
+		t.log.Errorf("Error getting reputation for client %s: %v", c.name, err)
+		return false
+	}
+	score := report.Reputation.Score + soakOutcomeScore(miss)
+	// The miss may push the oldest outcome out of the scoring window.
+	if n := len(t.outcomes[c]); n > 0 && n >= int(report.MaxScore) {
+		score -= soakOutcomeScore(t.outcomes[c][n-int(report.MaxScore)])
+	}
+	return score > -report.PenaltyThreshold
+}
+
+// bookAndCancel books an order that cannot match the trades and cancels it
+// once it is booked.
+func (t *soakTest) bookAndCancel(c *simulationClient) error {
+	isSeller := c.isSeller
+	defer func() { c.isSeller = isSeller }()
+	// The trade rates are 100 to 300 rate steps.
+	c.isSeller = rand.Intn(2) == 0
+	rate := 10 * t.rateStep
+	if c.isSeller {
+		rate = 1000 * t.rateStep
+	}
+	oid, err := t.placeOrder(c, t.lotSize, rate, false)
+	if err != nil {
+		return fmt.Errorf("client %s error placing order to cancel: %w", c.name, err)
+	}
+	tracker, err := c.findOrder(oid)
+	if err != nil {
+		return err
+	}
+	epochDur := time.Duration(tracker.epochLen()) * time.Millisecond
+	if !tryUntil(t.ctx, 3*epochDur, func() bool {
+		ord, err := c.core.Order(tracker.ID().Bytes())
+		return err == nil && ord.Status == order.OrderStatusBooked
+	}) {
+		return fmt.Errorf("client %s order %s not booked", c.name, tracker.token())
+	}
+	if err := c.core.Cancel(tracker.ID().Bytes()); err != nil {
+		return fmt.Errorf("client %s error canceling order %s: %w", c.name, tracker.token(), err)
+	}
+	if !tryUntil(t.ctx, 3*epochDur, func() bool {
+		ord, err := c.core.Order(tracker.ID().Bytes())
+		return err == nil && ord.Status == order.OrderStatusCanceled
+	}) {
+		return fmt.Errorf("client %s order %s not canceled", c.name, tracker.token())
+	}
+	t.cancels++
+	c.log.Infof("Client %s canceled order %s.", c.name, tracker.token())
+	return nil
+}
+
+// checkSettled checks that the client has no active trades and that no funds
+// are locked for orders or in swap contracts.
+func (t *soakTest) checkSettled(c *simulationClient) error {
+	var problem error
+	// Trades are retired shortly after the last match is settled.
+	if tryUntil(t.ctx, time.Minute, func() bool {
+		if n := len(c.dc().trackedTrades()); n > 0 {
+			problem = fmt.Errorf("client %s has %d active trades", c.name, n)
+			return false
+		}
+		for _, a := range []*assetConfig{t.base, t.quote} {
+			bal, err := c.core.AssetBalance(a.id)
+			if err != nil {
+				problem = fmt.Errorf("client %s error getting %s balance: %w", c.name, a.symbol, err)
+				return false
+			}
+			if bal.OrderLocked > 0 || bal.ContractLocked > 0 {
+				problem = fmt.Errorf("client %s has no active trades, but %s %s is locked for orders and %s is locked in contracts",
+					c.name, a.valFmt(bal.OrderLocked), a.symbol, a.valFmt(bal.ContractLocked))
+				return false
+			}
+		}
+		return true
+	}) {
+		return nil
+	}
+	if problem == nil {
+		problem = errors.New("context canceled")
+	}
+	return problem
+}
+
+// checkReputation checks that the client's match outcomes reported by the
+// server are the expected outcomes, and that the client's score agrees with
+// the outcomes. The server records a missed swap when it revokes the match,
+// after the broadcast timeout, so the server's report may trail the trade.
+func (t *soakTest) checkReputation(c *simulationClient) error {
+	timeout := time.Millisecond*time.Duration(c.dc().cfg.BroadcastTimeout) + time.Minute
+	var report *msgjson.ReputationReport
+	var want [3]uint32
+	var err error
+	matched := tryUntil(t.ctx, timeout, func() bool {
+		report, err = c.core.AccountReputation(dexHost)
+		if err != nil {
+			return true
+		}
+		if n := int(report.MaxScore); n > 0 && len(t.outcomes[c]) > n {
+			t.outcomes[c] = t.outcomes[c][len(t.outcomes[c])-n:]
+		}
+		want = [3]uint32{}
+		for _, outcome := range t.outcomes[c] {
+			want[outcome]++
+		}
+		return report.SwapsCompleted == want[soakSuccess] &&
+			report.NoSwapAsMaker == want[soakNoSwapAsMaker] &&
+			report.NoSwapAsTaker == want[soakNoSwapAsTaker]
+	})
+	if err != nil {
+		return fmt.Errorf("client %s error getting reputation: %w", c.name, err)
+	}
+	if !matched {
+		if report == nil {
+			return fmt.Errorf("client %s reputation not checked", c.name)
+		}
+		return fmt.Errorf("client %s outcomes: wanted %d successes, %d missed swaps as maker, %d missed swaps as taker, "+
+			"server reports %d, %d, %d", c.name, want[soakSuccess], want[soakNoSwapAsMaker], want[soakNoSwapAsTaker],
+			report.SwapsCompleted, report.NoSwapAsMaker, report.NoSwapAsTaker)
+	}
+	if report.NoRedeemAsMaker > 0 || report.NoRedeemAsTaker > 0 || report.PreimageMisses > 0 || report.Forgiven > 0 {
+		return fmt.Errorf("client %s has unexpected outcomes: %d missed redeems as maker, %d missed redeems as taker, "+
+			"%d preimage misses, %d forgiven", c.name, report.NoRedeemAsMaker, report.NoRedeemAsTaker,
+			report.PreimageMisses, report.Forgiven)
+	}
+
+	score := int32(report.SwapsCompleted)*soakSuccessScore +
+		int32(report.NoSwapAsMaker)*soakNoSwapAsMakerScore +
+		int32(report.NoSwapAsTaker)*soakNoSwapAsTakerScore
+	if !report.FreeCancels && report.Orders > report.GraceLimit && report.CancelRatio > report.CancelThreshold {
+		score += soakExcessiveCancelScore
+	}
+	if report.Reputation.Score != score {
+		return fmt.Errorf("client %s score is %d, but its outcomes add up to %d", c.name, report.Reputation.Score, score)
+	}
+	if report.Reputation.Penalties > 0 {
+		return fmt.Errorf("client %s has %d penalties with score %d", c.name, report.Reputation.Penalties, score)
+	}
+
+	// The client learns of score changes from notifications.
+	dc := c.dc()
+	var clientScore int32
+	if !tryUntil(t.ctx, time.Second*10, func() bool {
+		dc.acct.authMtx.RLock()
+		clientScore = dc.acct.rep.Score
+		dc.acct.authMtx.RUnlock()
+		return clientScore == score
+	}) {
+		return fmt.Errorf("client %s has score %d, but the server reports %d", c.name, clientScore, score)
+	}
+	c.log.Infof("Client %s score is %d after %d successes, %d missed swaps as maker, and %d missed swaps as taker.",
+		c.name, score, report.SwapsCompleted, report.NoSwapAsMaker, report.NoSwapAsTaker)
+	return nil
+}
+
+// checkBook checks that the market's book is not crossed.
+func (t *soakTest) checkBook() error {
+	book, err := t.client1.core.Book(dexHost, t.base.id, t.quote.id)
+	if err != nil {
+		return fmt.Errorf("error getting book: %w", err)
+	}
+	if len(book.Buys) > 0 && len(book.Sells) > 0 && book.Buys[0].MsgRate >= book.Sells[0].MsgRate {
+		return fmt.Errorf("book is crossed. best buy = %d, best sell = %d", book.Buys[0].MsgRate, book.Sells[0].MsgRate)
+	}
+	return nil
+}
+
+func soakOutcomeScore(outcome soakOutcome) int32 {
+	switch outcome {
+	case soakNoSwapAsMaker:
+		return soakNoSwapAsMakerScore
+	case soakNoSwapAsTaker:
+		return soakNoSwapAsTakerScore
+	}
+	return soakSuccessScore
+}
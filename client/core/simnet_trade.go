@@ -160,43 +160,43 @@ func (s *simulationTest) waitALittleBit() {
 	time.Sleep(sleep * sleepFactor)
 }
 
-// RunSimulationTest runs one or more simulations tests, based on the provided
-// SimulationConfig.
-func RunSimulationTest(cfg *SimulationConfig) error {
-	if cfg.Client1.BaseWalletType == WTCoreClone && cfg.Client2.BaseWalletType == WTCoreClone &&
-		cfg.Client1.BaseNode == cfg.Client2.BaseNode {
-		return fmt.Errorf("the %s RPC wallets for both clients are the same", cfg.BaseSymbol)
+// newSimulationTest validates the client configurations and creates a
+// simulationTest for the market. Use setup to start the clients.
+func newSimulationTest(baseSymbol, quoteSymbol, regAssetSymbol string, cl1, cl2 *SimClient, logger dex.Logger) (*simulationTest, error) {
+	if cl1.BaseWalletType == WTCoreClone && cl2.BaseWalletType == WTCoreClone &&
+		cl1.BaseNode == cl2.BaseNode {
+		return nil, fmt.Errorf("the %s RPC wallets for both clients are the same", baseSymbol)
 	}
 
-	if cfg.Client1.QuoteWalletType == WTCoreClone && cfg.Client2.QuoteWalletType == WTCoreClone &&
-		cfg.Client1.QuoteNode == cfg.Client2.QuoteNode {
-		return fmt.Errorf("the %s RPC wallets for both clients are the same", cfg.QuoteSymbol)
+	if cl1.QuoteWalletType == WTCoreClone && cl2.QuoteWalletType == WTCoreClone &&
+		cl1.QuoteNode == cl2.QuoteNode {
+		return nil, fmt.Errorf("the %s RPC wallets for both clients are the same", quoteSymbol)
 	}
 
 	// No alpha wallets allowed until we smarten up the balance checks, I guess.
-	if cfg.Client1.BaseNode == "alpha" || cfg.Client1.QuoteNode == "alpha" ||
-		cfg.Client2.BaseNode == "alpha" || cfg.Client2.QuoteNode == "alpha" {
-		return fmt.Errorf("no alpha nodes allowed")
+	if cl1.BaseNode == "alpha" || cl1.QuoteNode == "alpha" ||
+		cl2.BaseNode == "alpha" || cl2.QuoteNode == "alpha" {
+		return nil, fmt.Errorf("no alpha nodes allowed")
 	}
 
-	baseID, ok := dex.BipSymbolID(cfg.BaseSymbol)
+	baseID, ok := dex.BipSymbolID(baseSymbol)
 	if !ok {
-		return fmt.Errorf("base asset %q not known", cfg.BaseSymbol)
+		return nil, fmt.Errorf("base asset %q not known", baseSymbol)
 	}
 	baseUnitInfo, err := asset.UnitInfo(baseID)
 	if err != nil {
-		return fmt.Errorf("no unit info for %q", cfg.BaseSymbol)
+		return nil, fmt.Errorf("no unit info for %q", baseSymbol)
 	}
-	quoteID, ok := dex.BipSymbolID(cfg.QuoteSymbol)
+	quoteID, ok := dex.BipSymbolID(quoteSymbol)
 	if !ok {
-		return fmt.Errorf("base asset %q not known", cfg.BaseSymbol)
+		return nil, fmt.Errorf("base asset %q not known", baseSymbol)
 	}
 	quoteUnitInfo, err := asset.UnitInfo(quoteID)
 	if err != nil {
-		return fmt.Errorf("no unit info for %q", cfg.QuoteSymbol)
+		return nil, fmt.Errorf("no unit info for %q", quoteSymbol)
 	}
 	regAsset := baseID
-	if cfg.RegistrationAsset == cfg.QuoteSymbol {
+	if regAssetSymbol == quoteSymbol {
 		regAsset = quoteID
 	}
 	valFormatter := func(valFmt func(uint64) string) func(any) string {
@@ -221,17 +221,17 @@ func RunSimulationTest(cfg *SimulationConfig) error {
 	}
 
 	s := &simulationTest{
-		log: cfg.Logger,
+		log: logger,
 		base: &assetConfig{
 			id:               baseID,
-			symbol:           cfg.BaseSymbol,
+			symbol:           baseSymbol,
 			conversionFactor: baseUnitInfo.Conventional.ConversionFactor,
 			valFmt:           valFormatter(baseUnitInfo.ConventionalString),
 			isToken:          asset.TokenInfo(baseID) != nil,
 		},
 		quote: &assetConfig{
 			id:               quoteID,
-			symbol:           cfg.QuoteSymbol,
+			symbol:           quoteSymbol,
 			conversionFactor: quoteUnitInfo.Conventional.ConversionFactor,
 			valFmt:           valFormatter(quoteUnitInfo.ConventionalString),
 			isToken:          asset.TokenInfo(quoteID) != nil,
@@ -239,6 +239,16 @@ func RunSimulationTest(cfg *SimulationConfig) error {
 		regAsset:   regAsset,
 		marketName: marketName(baseID, quoteID),
 	}
+	return s, nil
+}
+
+// RunSimulationTest runs one or more simulations tests, based on the provided
+// SimulationConfig.
+func RunSimulationTest(cfg *SimulationConfig) error {
+	s, err := newSimulationTest(cfg.BaseSymbol, cfg.QuoteSymbol, cfg.RegistrationAsset, cfg.Client1, cfg.Client2, cfg.Logger)
+	if err != nil {
+		return err
+	}
 
 	if err := s.setup(cfg.Client1, cfg.Client2); err != nil {
 		return fmt.Errorf("setup error: %w", err)
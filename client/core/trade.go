@@ -34,6 +34,11 @@ type ExpirationErr string
 // Error satisfies the error interface for ExpirationErr.
 func (err ExpirationErr) Error() string { return string(err) }
 
+// errNoFundingCoins is the swapErr of matches that were blocked because the
+// order's funding coins could not be loaded. The block is lifted if the coins
+// are found later, e.g. after a wallet rescan.
+var errNoFundingCoins = errors.New("no funding coins for swap")
+
 // Ensure matchTracker satisfies the Stringer interface.
 var _ (fmt.Stringer) = (*matchTracker)(nil)
 
@@ -931,7 +936,7 @@ func (t *trackedTrade) negotiate(msgMatches []*msgjson.Match) error {
 		// Otherwise the server will end up revoking these matches.
 		if !t.hasFundingCoins() {
 			t.dc.log.Errorf("Unable to begin swap negotiation for unfunded order %v", t.ID())
-			match.swapErr = errors.New("no funding coins for swap")
+			match.swapErr = errNoFundingCoins
 		}
 
 		err := t.db.UpdateMatch(&match.MetaMatch)
@@ -1320,25 +1325,50 @@ func (t *trackedTrade) activeMatches() []*matchTracker {
 // BUY order.
 // unspentContractAmounts should be called with the mtx >= RLocked.
 func (t *trackedTrade) unspentContractAmounts() (amount uint64) {
-	swapSentFromQuoteAsset := t.fromAssetID == t.Quote()
 	for _, match := range t.matches {
-		side, status := match.Side, match.Status
-		if status >= order.MakerRedeemed || len(match.MetaData.Proof.RefundCoin) != 0 {
-			// Any redemption or own refund implies our swap is spent.
-			// Even if we're Maker and our swap has not been redeemed
-			// by Taker, we should consider it spent.
-			continue
+		amount += t.unspentContractAmount(match)
+	}
+	return
+}
+
+// unspentContractAmount returns the amount locked in the match's swap if it is
+// unspent, or zero. unspentContractAmount should be called with the mtx >=
+// RLocked.
+func (t *trackedTrade) unspentContractAmount(match *matchTracker) uint64 {
+	side, status := match.Side, match.Status
+	if status >= order.MakerRedeemed || len(match.MetaData.Proof.RefundCoin) != 0 {
+		// Any redemption or own refund implies our swap is spent.
+		// Even if we're Maker and our swap has not been redeemed
+		// by Taker, we should consider it spent.
+		return 0
+	}
+	if (side == order.Maker && status >= order.MakerSwapCast) ||
+		(side == order.Taker && status == order.TakerSwapCast) {
+		if t.fromAssetID == t.Quote() {
+			return calc.BaseToQuote(match.Rate, match.Quantity)
 		}
-		if (side == order.Maker && status >= order.MakerSwapCast) ||
-			(side == order.Taker && status == order.TakerSwapCast) {
-			swapAmount := match.Quantity
-			if swapSentFromQuoteAsset {
-				swapAmount = calc.BaseToQuote(match.Rate, match.Quantity)
-			}
-			amount += swapAmount
+		return match.Quantity
+	}
+	return 0
+}
+
+// pendingRedemptionAmount returns the amount of the to asset that the match
+// will pay once redeemed, if our swap has been sent but our redemption has
+// not, and the swap has not been refunded. Otherwise, zero is returned.
+// pendingRedemptionAmount should be called with the mtx >= RLocked.
+func (t *trackedTrade) pendingRedemptionAmount(match *matchTracker) uint64 {
+	if len(match.MetaData.Proof.RefundCoin) != 0 {
+		return 0
+	}
+	side, status := match.Side, match.Status
+	if (side == order.Maker && status >= order.MakerSwapCast && status < order.MakerRedeemed) ||
+		(side == order.Taker && status >= order.TakerSwapCast && status < order.MatchComplete) {
+		if t.fromAssetID == t.Quote() {
+			return match.Quantity
 		}
+		return calc.BaseToQuote(match.Rate, match.Quantity)
 	}
-	return
+	return 0
 }
 
 // isSwappable will be true if the match is ready for a swap transaction to be
@@ -1566,15 +1596,44 @@ func (t *trackedTrade) updateDynamicSwapOrRedemptionFeesPaid(ctx context.Context
 		stopChecks()
 		return
 	}
+	// The fees are for the whole tx, which may have swapped or redeemed other
+	// matches too.
+	txMatches := make([]*matchTracker, 0, 1)
+	for _, m := range t.matches {
+		for _, secretHash := range secrets {
+			if bytes.Equal(m.MetaData.Proof.SecretHash, secretHash) {
+				txMatches = append(txMatches, m)
+				break
+			}
+		}
+	}
 	if isInit {
 		t.metaData.SwapFeesPaid += actualFees
+		t.addFeeTx(db.FeeTxSwap, coinID, actualFees, txMatches)
 	} else {
 		t.metaData.RedemptionFeesPaid += actualFees
+		t.addFeeTx(db.FeeTxRedeem, coinID, actualFees, txMatches)
 	}
 	stopChecks()
 	t.notify(newOrderNote(TopicOrderStatusUpdate, "", "", db.Data, t.coreOrderInternal()))
 }
 
+// addFeeTx records the fees paid by a transaction for the order's matches.
+// This must be called with the mtx locked for writes.
+func (t *trackedTrade) addFeeTx(txType db.FeeTxType, coinID []byte, fees uint64, matches []*matchTracker) {
+	mids := make([]order.MatchID, 0, len(matches))
+	for _, m := range matches {
+		mids = append(mids, m.MatchID)
+	}
+	t.metaData.FeeTxs = append(t.metaData.FeeTxs, &db.OrderFeeTx{
+		Type:    txType,
+		CoinID:  coinID,
+		Fees:    fees,
+		Matches: mids,
+		Stamp:   uint64(time.Now().UnixMilli()),
+	})
+}
+
 // isRedeemable will be true if the match is ready for our redemption to be
 // broadcast.
 //
@@ -2497,6 +2556,7 @@ func (c *Core) swapMatchGroup(t *trackedTrade, matches []*matchTracker, errs *er
 	t.changeLocked = lockChange
 	if _, dynamic := fromWallet.Wallet.(asset.DynamicSwapper); !dynamic {
 		t.metaData.SwapFeesPaid += fees // dynamic tx wallets don't know the fees paid until mining
+		t.addFeeTx(db.FeeTxSwap, []byte(receipts[0].Coin().ID()), fees, matches)
 	}
 
 	if change == nil {
@@ -2784,6 +2844,7 @@ func (c *Core) redeemMatchGroup(t *trackedTrade, matches []*matchTracker, errs *
 
 	if _, dynamic := t.wallets.toWallet.Wallet.(asset.DynamicSwapper); !dynamic {
 		t.metaData.RedemptionFeesPaid += fees // dynamic tx wallets don't know the fees paid until mining
+		t.addFeeTx(db.FeeTxRedeem, []byte(coinIDs[0]), fees, matches)
 	}
 
 	err = t.db.UpdateOrderMetaData(t.ID(), t.metaData)
@@ -118,6 +118,54 @@ type WalletBalance struct {
 	BondLocked uint64 `json:"bondlocked"`
 }
 
+// OrderLock is an amount of an asset that is locked for an order.
+type OrderLock struct {
+	Host     string    `json:"host"`
+	MarketID string    `json:"marketID"`
+	OrderID  dex.Bytes `json:"orderID"`
+	Amount   uint64    `json:"amount"`
+}
+
+// MatchLock is an amount of an asset that is tied up in a match.
+type MatchLock struct {
+	Host     string    `json:"host"`
+	MarketID string    `json:"marketID"`
+	OrderID  dex.Bytes `json:"orderID"`
+	MatchID  dex.Bytes `json:"matchID"`
+	Amount   uint64    `json:"amount"`
+}
+
+// LockedBalance is a breakdown of an asset's locked funds by the reason they
+// are locked. Orders, FeeReserves, BondReserves, Other, and Unexplained add up
+// to Locked. The other fields are funds that have left the wallet for now and
+// are not part of the wallet balance.
+type LockedBalance struct {
+	AssetID uint32 `json:"assetID"`
+	Symbol  string `json:"symbol"`
+	// Locked is the wallet's locked balance.
+	Locked uint64 `json:"locked"`
+	// Orders are the funds locked to fund the future swaps of orders.
+	Orders []*OrderLock `json:"orders"`
+	// FeeReserves are the funds locked for the swap fees of token orders and
+	// the redemption and refund fees of orders on account-based assets.
+	FeeReserves []*OrderLock `json:"feeReserves"`
+	// BondReserves is the amount locked for future fidelity bonds.
+	BondReserves uint64 `json:"bondReserves"`
+	// Other are the wallet's custom balance categories that are locked.
+	Other map[asset.BalanceCategory]uint64 `json:"other"`
+	// Unexplained is the part of Locked that is not accounted for by the
+	// other fields, e.g. funds locked outside of the DEX client.
+	Unexplained uint64 `json:"unexplained"`
+	// Contracts are the funds in our unspent swap contracts. They are
+	// returned to the wallet by a refund if the swap fails.
+	Contracts []*MatchLock `json:"contracts"`
+	// PendingRedemptions are the funds that matches will pay to the wallet
+	// once redeemed.
+	PendingRedemptions []*MatchLock `json:"pendingRedemptions"`
+	// Bonds is the amount in unspent fidelity bonds.
+	Bonds uint64 `json:"bonds"`
+}
+
 // WalletState is the current status of an exchange wallet.
 type WalletState struct {
 	Symbol       string                          `json:"symbol"`
@@ -187,9 +235,45 @@ type User struct {
 	FiatRates          map[uint32]float64          `json:"fiatRates"`
 	Net                dex.Network                 `json:"net"`
 	ExtensionConfig    *ExtensionModeConfig        `json:"extensionModeConfig,omitempty"`
+	Amnesia            bool                        `json:"amnesia,omitempty"`
 	Actions            []*asset.ActionRequiredNote `json:"actions,omitempty"`
 }
 
+// AddressBookEntry is a labeled withdrawal address.
+type AddressBookEntry struct {
+	Address string `json:"address"`
+	Label   string `json:"label"`
+}
+
+// AddressBook is an asset's list of labeled withdrawal addresses. If
+// Restricted is true, sends and withdraws are only permitted to addresses in
+// the book.
+type AddressBook struct {
+	AssetID    uint32              `json:"assetID"`
+	Entries    []*AddressBookEntry `json:"entries"`
+	Restricted bool                `json:"restricted"`
+}
+
+// AssetValue is the fiat value of the balance of a wallet.
+type AssetValue struct {
+	AssetID uint32 `json:"assetID"`
+	Symbol  string `json:"symbol"`
+	// Balance is the total balance of the wallet in atomic units, including
+	// immature, locked, contract-locked and bond-locked funds.
+	Balance uint64 `json:"balance"`
+	// Rate is the fiat (USD) price of one conventional unit of the asset. Rate
+	// is zero if no enabled rate source has a current rate for the asset.
+	Rate  float64 `json:"rate"`
+	Value float64 `json:"value"`
+}
+
+// PortfolioValue is the fiat value of all wallets.
+type PortfolioValue struct {
+	Assets []*AssetValue `json:"assets"`
+	// Total is the sum of the values of the Assets that have a fiat rate.
+	Total float64 `json:"total"`
+}
+
 // SupportedAsset is data about an asset and possibly the wallet associated
 // with it.
 type SupportedAsset struct {
@@ -424,6 +508,9 @@ type Order struct {
 	TimeInForce       order.TimeInForce `json:"tif"`           // limit only
 	TargetOrderID     dex.Bytes         `json:"targetOrderID"` // cancel only
 	ReadyToTick       bool              `json:"readyToTick"`
+	// TTL and MaxDrift are the TradeForm's automatic cancellation settings.
+	TTL      uint64  `json:"ttl,omitempty"`
+	MaxDrift float64 `json:"maxDrift,omitempty"`
 }
 
 // InFlightOrder is an Order that is not stamped yet, but has a temporary ID
@@ -440,6 +527,24 @@ type FeeBreakdown struct {
 	Funding    uint64 `json:"funding"` // split fees
 	// TODO: Refund is not yet being populated.
 	Refund uint64 `json:"refund"`
+	// Txs are the transactions that the fees were paid in. Orders placed
+	// before the transactions were recorded will have none.
+	Txs []*FeeTx `json:"txs,omitempty"`
+}
+
+// FeeTx is a transaction that an order paid on-chain fees for.
+type FeeTx struct {
+	// Type is "funding", "swap", or "redemption".
+	Type string `json:"type"`
+	// Coin is the split output, swap contract, or redemption coin. Fees are
+	// paid in the fee asset of the Coin's asset, which is the parent asset
+	// for tokens.
+	Coin *Coin  `json:"coin"`
+	Fees uint64 `json:"fees"`
+	// MatchIDs are the order's matches that were swapped or redeemed by the
+	// transaction.
+	MatchIDs []dex.Bytes `json:"matchIDs,omitempty"`
+	Stamp    uint64      `json:"stamp"`
 }
 
 // coreOrderFromTrade constructs an *Order from the supplied limit or market
@@ -497,6 +602,30 @@ func coreOrderFromTrade(ord order.Order, metaData *db.OrderMetaData) *Order {
 		accelerationCoins = append(accelerationCoins, NewCoin(fromID, coinID))
 	}
 
+	toID := baseID
+	if trade.Sell {
+		toID = quoteID
+	}
+
+	feeTxs := make([]*FeeTx, 0, len(metaData.FeeTxs))
+	for _, feeTx := range metaData.FeeTxs {
+		assetID := fromID
+		if feeTx.Type == db.FeeTxRedeem {
+			assetID = toID
+		}
+		var mids []dex.Bytes
+		for _, mid := range feeTx.Matches {
+			mids = append(mids, mid.Bytes())
+		}
+		feeTxs = append(feeTxs, &FeeTx{
+			Type:     feeTx.Type.String(),
+			Coin:     NewCoin(assetID, feeTx.CoinID),
+			Fees:     feeTx.Fees,
+			MatchIDs: mids,
+			Stamp:    feeTx.Stamp,
+		})
+	}
+
 	// For in-flight orders, we'll set the order ID as a zero-hash.
 	var oid dex.Bytes
 	if ord.Time() > 0 {
@@ -527,9 +656,12 @@ func coreOrderFromTrade(ord order.Order, metaData *db.OrderMetaData) *Order {
 			Swap:       metaData.SwapFeesPaid,
 			Redemption: metaData.RedemptionFeesPaid,
 			Funding:    metaData.FundingFeesPaid,
+			Txs:        feeTxs,
 		},
 		FundingCoins:      fundingCoins,
 		AccelerationCoins: accelerationCoins,
+		TTL:               metaData.TTL,
+		MaxDrift:          metaData.MaxDrift,
 	}
 
 	return corder
@@ -561,6 +693,11 @@ type Market struct {
 	// MinimumRate is the minimum rate allowed for the market, which is the
 	// minimum rate at which 1 lot converts to something greater than dust.
 	MinimumRate uint64 `json:"minimumRate"`
+	// DisplayBand is non-zero if the server rounds down the book quantities
+	// of orders larger than DisplayBand to a multiple of DisplayBand, in atoms
+	// of the base asset. A banded book quantity is a lower bound on the
+	// order's true quantity.
+	DisplayBand uint64 `json:"displayBand,omitempty"`
 }
 
 // BaseContractLocked is the amount of base asset locked in un-redeemed
@@ -753,6 +890,43 @@ type OrderBook struct {
 	RecentMatches []*orderbook.MatchSummary `json:"recentMatches"`
 }
 
+// AggregateBookLevel is the combined quantity of the book orders at a rate on
+// all servers.
+type AggregateBookLevel struct {
+	MsgRate   uint64  `json:"msgRate"`
+	Rate      float64 `json:"rate"`
+	Qty       float64 `json:"qty"`
+	QtyAtomic uint64  `json:"qtyAtomic"`
+	// Hosts is the quantity at the rate on each server, in atomic units of the
+	// base asset.
+	Hosts map[string]uint64 `json:"hosts"`
+}
+
+// ServerBookSummary is the top of book and depth of a market on one server.
+type ServerBookSummary struct {
+	Host string `json:"host"`
+	// BestBuy and BestSell are the message-rates of the best orders on each
+	// side of the book, or zero if the side is empty.
+	BestBuy  uint64 `json:"bestBuy"`
+	BestSell uint64 `json:"bestSell"`
+	// BuyDepth and SellDepth are the total quantity on each side of the book,
+	// in atomic units of the base asset.
+	BuyDepth  uint64 `json:"buyDepth"`
+	SellDepth uint64 `json:"sellDepth"`
+	// Error is set if the book could not be retrieved from the server.
+	Error string `json:"error,omitempty"`
+}
+
+// AggregateBook is the combined order book of a market on all connected
+// servers that list it.
+type AggregateBook struct {
+	Base    uint32                `json:"base"`
+	Quote   uint32                `json:"quote"`
+	Servers []*ServerBookSummary  `json:"servers"`
+	Buys    []*AggregateBookLevel `json:"buys"`
+	Sells   []*AggregateBookLevel `json:"sells"`
+}
+
 // MarketOrderBook is used as the BookUpdate's Payload with the FreshBookAction.
 // The subscriber will likely need to translate into a JSON tagged type.
 type MarketOrderBook struct {
@@ -777,6 +951,7 @@ const (
 	CandleUpdateAction    = "candle_update"
 	EpochMatchSummary     = "epoch_match_summary"
 	EpochResolved         = "epoch_resolved"
+	DepthUpdateAction     = "depth_update"
 )
 
 // BookUpdate is an order book update.
@@ -1045,6 +1220,26 @@ type TradeForm struct {
 	Rate    uint64            `json:"rate"`
 	TifNow  bool              `json:"tifnow"`
 	Options map[string]string `json:"options"`
+	// AcceptLowLiquidity confirms that the order should be placed even if the
+	// market's liquidity is low relative to the order size. See
+	// LiquidityReport.
+	AcceptLowLiquidity bool `json:"acceptLowLiquidity"`
+	// WorstRate is the worst rate at which a market order may be filled, the
+	// highest rate for a buy or the lowest for a sell. Matching stops at the
+	// limit and the remainder is left unfilled. Zero means no limit. Ignored
+	// for limit orders.
+	WorstRate uint64 `json:"worstRate,omitempty"`
+	// Route places the order on the server with the best price and liquidity
+	// for the order, instead of on Host. See Core.RouteOrder.
+	Route bool `json:"route,omitempty"`
+	// TTL is the number of seconds that a standing limit order may stay on
+	// the book. Core cancels the order when the TTL elapses. Zero means the
+	// order does not expire.
+	TTL uint64 `json:"ttl,omitempty"`
+	// MaxDrift is the difference, in percent, between a standing limit
+	// order's rate and the market's spot rate at which core cancels the order.
+	// Zero means there is no limit.
+	MaxDrift float64 `json:"maxDrift,omitempty"`
 }
 
 // QtyRate specifies the quantity and rate of an order placement.
@@ -1130,6 +1325,11 @@ type OrderFilter struct {
 		Base  uint32 `json:"baseID"`
 		Quote uint32 `json:"quoteID"`
 	} `json:"market"`
+	// Sell, Since, and Until are optional side and update time (milliseconds)
+	// filters. See db.OrderFilter.
+	Sell  *bool  `json:"sell,omitempty"`
+	Since uint64 `json:"since,omitempty"`
+	Until uint64 `json:"until,omitempty"`
 }
 
 // Account holds data returned from AccountExport.
@@ -1166,8 +1366,68 @@ type MaxOrderEstimate struct {
 
 // OrderEstimate is a Core.PreOrder estimate.
 type OrderEstimate struct {
-	Swap   *asset.PreSwap   `json:"swap"`
-	Redeem *asset.PreRedeem `json:"redeem"`
+	Swap      *asset.PreSwap   `json:"swap"`
+	Redeem    *asset.PreRedeem `json:"redeem"`
+	Liquidity *LiquidityReport `json:"liquidity"`
+	// Lots is the number of lots in the order, and Qty is that many lots of
+	// the base asset, i.e. the order quantity rounded down to the lot size.
+	// For a market buy, Lots and Qty are estimated from the book.
+	Lots uint64 `json:"lots"`
+	Qty  uint64 `json:"qty"`
+	// MaxLots is the most lots that the from wallet's available balance can
+	// fund at the order's rate, including fee reserves. MaxQty is that many
+	// lots of the base asset.
+	MaxLots uint64 `json:"maxLots"`
+	MaxQty  uint64 `json:"maxQty"`
+	// Locked is the amount of each asset, keyed by asset ID, that will be
+	// locked to fund the order: the swap value, and reserves for the maximum
+	// swap fees. The fees of a token swap are paid in the parent asset.
+	Locked map[uint32]uint64 `json:"locked"`
+}
+
+// LiquidityReport is an evaluation of a market's liquidity relative to the
+// size of an intended order. Only market orders and immediate limit orders
+// are evaluated. If there are any Warnings, the order must be placed with
+// TradeForm.AcceptLowLiquidity set.
+type LiquidityReport struct {
+	// Volume is the base asset match volume over the last 24 hours. Volume
+	// is only evaluated if the market's candles are cached.
+	Volume uint64 `json:"volume"`
+	// Fillable is the base asset quantity that the order could match on the
+	// current book.
+	Fillable uint64   `json:"fillable"`
+	Warnings []string `json:"warnings"`
+}
+
+// Trade validation checks. Each TradeValidationError is for one of these.
+const (
+	ValidateAccount  = "account"
+	ValidateMarket   = "market"
+	ValidateRate     = "rate"
+	ValidateQuantity = "quantity"
+	ValidateOptions  = "options"
+	ValidateWallet   = "wallet"
+	ValidateFunding  = "funding"
+	ValidateLimits   = "limits"
+)
+
+// TradeValidationError is a reason that an order would be rejected.
+type TradeValidationError struct {
+	// Check is the part of the order that failed validation, e.g.
+	// ValidateRate.
+	Check   string `json:"check"`
+	Message string `json:"message"`
+	// Transient is true if the order itself is fine, but it can't be placed
+	// right now, e.g. because a wallet is locked or not synced, the server is
+	// disconnected, or there are not enough funds. An error that is not
+	// transient will persist until the order is changed.
+	Transient bool `json:"transient"`
+}
+
+// TradeValidation is the result of Core.ValidateTrade.
+type TradeValidation struct {
+	Valid  bool                    `json:"valid"`
+	Errors []*TradeValidationError `json:"errors"`
 }
 
 // PreAccelerate gives information that the user can use to decide on
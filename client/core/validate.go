@@ -0,0 +1,303 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/msgjson"
+	serverdex "decred.org/dcrdex/server/dex"
+)
+
+// add records a validation error.
+func (v *TradeValidation) add(check string, transient bool, format string, args ...any) {
+	v.Errors = append(v.Errors, &TradeValidationError{
+		Check:     check,
+		Message:   fmt.Sprintf(format, args...),
+		Transient: transient,
+	})
+}
+
+// ValidateTrade checks an order the way Trade would, but without placing it,
+// and reports every problem found instead of only the first. Wallets are not
+// connected or unlocked and no funds are locked, so a wallet that Trade would
+// unlock with the app password is reported as a transient error. The form's
+// Host must be set. Route is ignored.
+func (c *Core) ValidateTrade(form *TradeForm) *TradeValidation {
+	v := &TradeValidation{Errors: make([]*TradeValidationError, 0)}
+	c.validateTrade(v, form)
+	v.Valid = len(v.Errors) == 0
+	return v
+}
+
+// validateTrade adds the order's validation errors to the TradeValidation.
+// Checks that depend on a failed check are skipped.
+func (c *Core) validateTrade(v *TradeValidation, form *TradeForm) {
+	if err := c.checkAmnesia("trade"); err != nil {
+		v.add(ValidateAccount, false, "%v", err)
+	}
+
+	dc, connected, err := c.dex(form.Host)
+	if err != nil {
+		v.add(ValidateAccount, false, "%v", err)
+		return
+	}
+	host := dc.acct.host
+	switch {
+	case dc.acct.isViewOnly():
+		v.add(ValidateAccount, false, "not yet registered at %s", host)
+	case dc.acct.locked():
+		v.add(ValidateAccount, true, "account for %s is locked. Are you logged in?", host)
+	case dc.acct.suspended():
+		v.add(ValidateAccount, true, "%v", ErrAccountSuspended)
+	}
+	if !connected {
+		v.add(ValidateAccount, true, "currently disconnected from %s", host)
+	}
+
+	mktID := marketName(form.Base, form.Quote)
+	mktConf := dc.marketConfig(mktID)
+	if mktConf == nil {
+		v.add(ValidateMarket, false, "unknown market %q", mktID)
+		return
+	}
+	if !dc.running(mktID) {
+		v.add(ValidateMarket, true, "%s market trading is suspended", mktID)
+	}
+	lotSize := mktConf.LotSize
+
+	if form.IsLimit {
+		// The minimum rate can't be checked without the quote asset's config.
+		if err := checkLimitRate(dc, dc.assetConfig(form.Quote), mktConf, form.Rate); err != nil {
+			v.add(ValidateRate, false, "%v", err)
+		}
+	} else if err := checkWorstRate(dc, mktConf, form.WorstRate); err != nil {
+		v.add(ValidateRate, false, "%v", err)
+	}
+
+	// rate and lots are what the order would be funded with. A market buy's
+	// quantity is in units of the quote asset, so its lots are estimated from
+	// the mid-gap rate. A market sell is funded without regard to the rate.
+	rate, lots := form.Rate, form.Qty/lotSize
+	if err := checkOrderQty(form, lotSize); err != nil {
+		v.add(ValidateQuantity, false, "%v", err)
+	} else if !form.IsLimit && !form.Sell {
+		midGap, err := dc.midGap(form.Base, form.Quote)
+		if err != nil {
+			v.add(ValidateMarket, true, "cannot estimate a market buy without a synced order book: %v", err)
+			lots = 0
+		} else {
+			rate = midGap
+			if lots, err = marketBuyLots(form.Qty, midGap, lotSize); err != nil {
+				v.add(ValidateQuantity, true, "%v", err)
+			}
+		}
+	}
+
+	if err := checkAutoCancel(form); err != nil {
+		v.add(ValidateOptions, false, "%v", err)
+	}
+
+	if lots > 0 {
+		if err := checkLiquidity(dc, form); err != nil {
+			v.add(ValidateMarket, true, "%v", err)
+		}
+	}
+
+	if lots > 0 && mktConf.ParcelSize > 0 {
+		c.validateParcels(v, form.Host, mktConf, lots)
+	}
+
+	// A buy is funded in the quote asset, so it can't be checked without a rate.
+	wallets, assetConfigs := c.validateWallets(v, dc, form)
+	if wallets != nil && lots > 0 && (form.Sell || rate > 0) {
+		c.validateFunding(v, dc, form, wallets, assetConfigs, lotSize, rate, lots)
+	}
+}
+
+// checkLimitRate checks a limit order's rate against the market's minimum rate
+// and rate step. The minimum rate is not checked if quoteAsset is nil.
+func checkLimitRate(dc *dexConnection, quoteAsset *dex.Asset, mktConf *msgjson.Market, rate uint64) error {
+	if rate == 0 {
+		return newError(orderParamsErr, "zero-rate order not allowed")
+	}
+	if quoteAsset != nil {
+		if minRate := dc.minimumMarketRate(quoteAsset, mktConf.LotSize); rate < minRate {
+			return newError(orderParamsErr, "order's rate is lower than market's minimum rate. %d < %d", rate, minRate)
+		}
+	}
+	if rate%mktConf.RateStep != 0 {
+		return newOrderStepError(msgjson.StepFieldRate, rate, mktConf.RateStep)
+	}
+	return nil
+}
+
+// checkWorstRate checks a market order's optional worst rate.
+func checkWorstRate(dc *dexConnection, mktConf *msgjson.Market, worstRate uint64) error {
+	if worstRate == 0 {
+		return nil
+	}
+	// The worst rate is part of the serialized order, so a server that
+	// doesn't know about it would compute a different order ID.
+	if dc.apiVersion() < serverdex.WorstRateAPIVersion {
+		return newError(orderParamsErr, "%s does not support a worst rate for market orders", dc.acct.host)
+	}
+	if worstRate%mktConf.RateStep != 0 {
+		return newOrderStepError(msgjson.StepFieldRate, worstRate, mktConf.RateStep)
+	}
+	return nil
+}
+
+// checkOrderQty checks the order's quantity against the market's lot size.
+// The quantity of a market buy is in units of the quote asset, and is not
+// subject to the lot size.
+func checkOrderQty(form *TradeForm, lotSize uint64) error {
+	switch {
+	case form.Qty == 0:
+		return newError(orderParamsErr, "zero quantity not allowed")
+	case !form.IsLimit && !form.Sell:
+		return nil
+	case form.Qty%lotSize != 0:
+		return newOrderStepError(msgjson.StepFieldQty, form.Qty, lotSize)
+	case form.Qty < lotSize:
+		return newError(orderParamsErr, "order quantity < 1 lot. qty = %d, lot size = %d", form.Qty, lotSize)
+	}
+	return nil
+}
+
+// marketBuyLots estimates the number of lots in a market buy of qty units of
+// the quote asset at the mid-gap rate.
+func marketBuyLots(qty, midGap, lotSize uint64) (uint64, error) {
+	lots := calc.QuoteToBase(midGap, qty) / lotSize
+	if lots == 0 {
+		return 0, newError(orderParamsErr, "order quantity is too low for current market rates. qty = %d, mid-gap = %d, lot size = %d",
+			qty, midGap, lotSize)
+	}
+	return lots, nil
+}
+
+// checkAutoCancel checks the order's automatic cancellation settings.
+func checkAutoCancel(form *TradeForm) error {
+	if form.TTL == 0 && form.MaxDrift == 0 {
+		return nil
+	}
+	if !form.IsLimit || form.TifNow {
+		return newError(orderParamsErr, "only standing limit orders can be canceled automatically")
+	}
+	if form.MaxDrift < 0 {
+		return newError(orderParamsErr, "negative max drift %f", form.MaxDrift)
+	}
+	return nil
+}
+
+// checkLiquidity checks the order against the market's liquidity, unless the
+// form accepts low liquidity.
+func checkLiquidity(dc *dexConnection, form *TradeForm) error {
+	if form.AcceptLowLiquidity {
+		return nil
+	}
+	if r := dc.evaluateLiquidity(form); len(r.Warnings) > 0 {
+		return newError(lowLiquidityErr, "low liquidity on market %s: %s. confirm to place the order anyway",
+			marketName(form.Base, form.Quote), strings.Join(r.Warnings, ", "))
+	}
+	return nil
+}
+
+// validateWallets checks that the order's wallets exist and are ready to
+// trade. The wallets and asset configurations are returned if the order's
+// funding can be checked, i.e. the from wallet is connected and unlocked and
+// the wallet versions are compatible with the server.
+func (c *Core) validateWallets(v *TradeValidation, dc *dexConnection, form *TradeForm) (*walletSet, *assetSet) {
+	var missing bool
+	for _, assetID := range []uint32{form.Base, form.Quote} {
+		if _, found := c.wallet(assetID); !found {
+			v.add(ValidateWallet, false, "no wallet found for %s", unbip(assetID))
+			missing = true
+		}
+	}
+	if missing {
+		return nil, nil
+	}
+	wallets, assetConfigs, versCompat, err := c.walletSet(dc, form.Base, form.Quote, form.Sell)
+	if err != nil {
+		v.add(ValidateWallet, false, "%v", err)
+		return nil, nil
+	}
+	if !versCompat {
+		v.add(ValidateWallet, false, "client and server asset versions are incompatible for %v", dc.acct.host)
+	}
+
+	ready := func(w *xcWallet) bool {
+		switch {
+		case !w.connected():
+			v.add(ValidateWallet, true, "%s wallet is not connected", unbip(w.AssetID))
+			return false
+		case !w.unlocked():
+			v.add(ValidateWallet, true, "%s wallet is locked", unbip(w.AssetID))
+			return false
+		}
+		w.mtx.RLock()
+		peers, synced, progress := w.peerCount, w.syncStatus.Synced, w.syncStatus.BlockProgress()
+		w.mtx.RUnlock()
+		if peers < 1 {
+			v.add(ValidateWallet, true, "%v", &WalletNoPeersError{w.AssetID})
+		}
+		if !synced {
+			v.add(ValidateWallet, true, "%v", &WalletSyncError{w.AssetID, progress})
+		}
+		return true
+	}
+	fromReady := ready(wallets.fromWallet)
+	ready(wallets.toWallet)
+	if !fromReady || !versCompat {
+		return nil, nil
+	}
+	return wallets, assetConfigs
+}
+
+// validateFunding checks that the from wallet has the funds for the order.
+func (c *Core) validateFunding(v *TradeValidation, dc *dexConnection, form *TradeForm, wallets *walletSet,
+	assetConfigs *assetSet, lotSize, rate, lots uint64) {
+
+	fromAsset, toAsset := assetConfigs.fromAsset, assetConfigs.toAsset
+	swapLotSize := lotSize
+	if !form.Sell {
+		swapLotSize = calc.BaseToQuote(rate, lotSize)
+	}
+	maxOrder, err := wallets.fromWallet.MaxOrder(&asset.MaxOrderForm{
+		LotSize:       swapLotSize,
+		FeeSuggestion: c.feeSuggestion(dc, fromAsset.ID),
+		AssetVersion:  fromAsset.Version,
+		MaxFeeRate:    fromAsset.MaxFeeRate,
+		RedeemVersion: toAsset.Version,
+		RedeemAssetID: toAsset.ID,
+	})
+	if err != nil {
+		v.add(ValidateFunding, true, "error getting max order estimate: %v", err)
+		return
+	}
+	if maxOrder.Lots < lots {
+		v.add(ValidateFunding, true, "insufficient %s balance: the order is %d lots, but only %d lots can be funded",
+			unbip(fromAsset.ID), lots, maxOrder.Lots)
+	}
+}
+
+// validateParcels checks that the order would not put the account over its
+// trading limit at the server.
+func (c *Core) validateParcels(v *TradeValidation, host string, mktConf *msgjson.Market, lots uint64) {
+	userParcels, parcelLimit, err := c.TradingLimits(host)
+	if err != nil {
+		v.add(ValidateLimits, true, "error getting trading limits: %v", err)
+		return
+	}
+	orderParcels := float64(lots) / float64(mktConf.ParcelSize)
+	if float64(userParcels)+orderParcels > float64(parcelLimit) {
+		v.add(ValidateLimits, true, "order of %.2f parcels would exceed the trading limit at %s. %d of %d parcels are in use",
+			orderParcels, host, userParcels, parcelLimit)
+	}
+}
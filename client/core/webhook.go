@@ -0,0 +1,150 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"decred.org/dcrdex/dex/webhook"
+)
+
+// Webhook event types. Events are POSTed to the Config.Webhooks URLs as a
+// webhook.Event, signed with Config.WebhookSecret.
+const (
+	// WebhookOrderFilled is sent when an order is matched, either partially or
+	// completely. The Data is a *WebhookOrder.
+	WebhookOrderFilled = "order_filled"
+	// WebhookSwapRedeemed is sent when the redemption of a match is confirmed.
+	// The Data is a *WebhookMatch.
+	WebhookSwapRedeemed = "swap_redeemed"
+	// WebhookSwapFailed is sent when a swap could not be sent or redeemed, or
+	// when a match is revoked or refunded. The Data is a *WebhookOrder or a
+	// *WebhookMatch.
+	WebhookSwapFailed = "swap_failed"
+	// WebhookPenaltyWarning is sent when a server reports that the account was
+	// penalized. The Data is a *WebhookPenalty.
+	WebhookPenaltyWarning = "penalty_warning"
+)
+
+// WebhookOrder is the Data of order events.
+type WebhookOrder struct {
+	// Topic and Details are from the notification that triggered the event.
+	Topic    Topic  `json:"topic"`
+	Details  string `json:"details"`
+	Host     string `json:"host"`
+	MarketID string `json:"marketID"`
+	OrderID  string `json:"orderID"`
+	Sell     bool   `json:"sell"`
+	Status   string `json:"status"`
+	Qty      uint64 `json:"qty"`
+	Filled   uint64 `json:"filled"`
+}
+
+// WebhookMatch is the Data of match events.
+type WebhookMatch struct {
+	Topic    Topic  `json:"topic"`
+	Details  string `json:"details"`
+	Host     string `json:"host"`
+	MarketID string `json:"marketID"`
+	OrderID  string `json:"orderID"`
+	MatchID  string `json:"matchID"`
+	Status   string `json:"status"`
+	Side     string `json:"side"`
+	Rate     uint64 `json:"rate"`
+	Qty      uint64 `json:"qty"`
+}
+
+// WebhookPenalty is the Data of a WebhookPenaltyWarning event.
+type WebhookPenalty struct {
+	Subject string `json:"subject"`
+	Details string `json:"details"`
+}
+
+// webhookEventTypes maps the topics of notifications that trigger webhook
+// events to the event type.
+var webhookEventTypes = map[Topic]string{
+	TopicBuyMatchesMade:      WebhookOrderFilled,
+	TopicSellMatchesMade:     WebhookOrderFilled,
+	TopicRedemptionConfirmed: WebhookSwapRedeemed,
+	TopicSwapSendError:       WebhookSwapFailed,
+	TopicInitError:           WebhookSwapFailed,
+	TopicRedemptionError:     WebhookSwapFailed,
+	TopicRefundFailure:       WebhookSwapFailed,
+	TopicMatchesRefunded:     WebhookSwapFailed,
+	TopicMatchRevoked:        WebhookSwapFailed,
+	TopicSwapRefunded:        WebhookSwapFailed,
+	TopicPenalized:           WebhookPenaltyWarning,
+}
+
+// webhookEvent converts the notification to a webhook event. ok is false if
+// the notification does not trigger an event.
+func webhookEvent(n Notification) (evtType string, data any, ok bool) {
+	evtType, ok = webhookEventTypes[n.Topic()]
+	if !ok {
+		return "", nil, false
+	}
+	switch note := n.(type) {
+	case *OrderNote:
+		if note.Order == nil {
+			return "", nil, false
+		}
+		ord := note.Order
+		return evtType, &WebhookOrder{
+			Topic:    note.Topic(),
+			Details:  note.Details(),
+			Host:     ord.Host,
+			MarketID: ord.MarketID,
+			OrderID:  ord.ID.String(),
+			Sell:     ord.Sell,
+			Status:   ord.Status.String(),
+			Qty:      ord.Qty,
+			Filled:   ord.Filled,
+		}, true
+	case *MatchNote:
+		if note.Match == nil {
+			return "", nil, false
+		}
+		m := note.Match
+		return evtType, &WebhookMatch{
+			Topic:    note.Topic(),
+			Details:  note.Details(),
+			Host:     note.Host,
+			MarketID: note.MarketID,
+			OrderID:  note.OrderID.String(),
+			MatchID:  m.MatchID.String(),
+			Status:   m.Status.String(),
+			Side:     m.Side.String(),
+			Rate:     m.Rate,
+			Qty:      m.Qty,
+		}, true
+	case *ServerNotifyNote:
+		return evtType, &WebhookPenalty{
+			Subject: note.Subject(),
+			Details: note.Details(),
+		}, true
+	}
+	return "", nil, false
+}
+
+// notifyWebhooks queues a webhook event for the notification, if webhooks are
+// configured and the notification triggers an event.
+func (c *Core) notifyWebhooks(n Notification) {
+	if c.webhooks == nil {
+		return
+	}
+	if evtType, data, ok := webhookEvent(n); ok {
+		c.webhooks.Notify(evtType, data)
+	}
+}
+
+// newWebhookNotifier creates the notifier for the configured webhooks, or nil
+// if none are configured.
+func newWebhookNotifier(cfg *Config) (*webhook.Notifier, error) {
+	if len(cfg.Webhooks) == 0 {
+		return nil, nil
+	}
+	return webhook.NewNotifier(&webhook.Config{
+		URLs:   cfg.Webhooks,
+		Secret: cfg.WebhookSecret,
+		Logger: cfg.Logger.SubLogger("HOOK"),
+	})
+}
@@ -0,0 +1,143 @@
+//go:build !harness && !botlive
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/dex/webhook"
+)
+
+func TestWebhookEvent(t *testing.T) {
+	oid := order.OrderID{0x01}
+	ord := &Order{
+		Host:     "somedex.tld:7232",
+		MarketID: "dcr_btc",
+		ID:       oid[:],
+		Sell:     true,
+		Status:   order.OrderStatusBooked,
+		Qty:      3e8,
+		Filled:   1e8,
+	}
+	mid := order.MatchID{0x02}
+	matchNote := &MatchNote{
+		Notification: db.NewNotification(NoteTypeMatch, TopicRedemptionConfirmed, "", "", db.Success),
+		OrderID:      oid[:],
+		Match:        &Match{MatchID: mid[:], Status: order.MatchConfirmed, Side: order.Taker, Rate: 5e5, Qty: 1e8},
+		Host:         ord.Host,
+		MarketID:     ord.MarketID,
+	}
+
+	tests := []struct {
+		name    string
+		note    Notification
+		wantTyp string
+		wantOK  bool
+	}{{
+		name:    "order matched",
+		note:    newOrderNote(TopicSellMatchesMade, "", "", db.Poke, ord),
+		wantTyp: WebhookOrderFilled,
+		wantOK:  true,
+	}, {
+		name:    "redeemed",
+		note:    matchNote,
+		wantTyp: WebhookSwapRedeemed,
+		wantOK:  true,
+	}, {
+		name:    "refunded",
+		note:    newOrderNote(TopicMatchesRefunded, "", "", db.WarningLevel, ord),
+		wantTyp: WebhookSwapFailed,
+		wantOK:  true,
+	}, {
+		name:    "penalized",
+		note:    newServerNotifyNote(TopicPenalized, "Penalized", "details", db.WarningLevel),
+		wantTyp: WebhookPenaltyWarning,
+		wantOK:  true,
+	}, {
+		name: "order booked",
+		note: newOrderNote(TopicOrderBooked, "", "", db.Data, ord),
+	}, {
+		name: "other server note",
+		note: newServerNotifyNote(TopicMarketSuspended, "", "", db.WarningLevel),
+	}}
+	for _, tt := range tests {
+		evtType, data, ok := webhookEvent(tt.note)
+		if ok != tt.wantOK {
+			t.Fatalf("%s: wanted ok = %t, got %t", tt.name, tt.wantOK, ok)
+		}
+		if evtType != tt.wantTyp {
+			t.Fatalf("%s: wanted event type %q, got %q", tt.name, tt.wantTyp, evtType)
+		}
+		if !ok {
+			continue
+		}
+		switch d := data.(type) {
+		case *WebhookOrder:
+			if d.OrderID != oid.String() || d.Filled != ord.Filled || !d.Sell || d.Status != "booked" {
+				t.Fatalf("%s: wrong order data %+v", tt.name, d)
+			}
+		case *WebhookMatch:
+			if d.MatchID != mid.String() || d.OrderID != oid.String() || d.Side != "Taker" {
+				t.Fatalf("%s: wrong match data %+v", tt.name, d)
+			}
+		case *WebhookPenalty:
+			if d.Details != "details" {
+				t.Fatalf("%s: wrong penalty data %+v", tt.name, d)
+			}
+		default:
+			t.Fatalf("%s: unexpected data type %T", tt.name, data)
+		}
+	}
+}
+
+func TestNotifyWebhooks(t *testing.T) {
+	const secret = "shhh"
+	received := make(chan *webhook.Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(webhook.SignatureHeader) != "sha256="+webhook.Sign([]byte(secret), body) {
+			t.Errorf("bad signature")
+		}
+		evt := new(webhook.Event)
+		if err := json.Unmarshal(body, evt); err != nil {
+			t.Errorf("error decoding event: %v", err)
+		}
+		received <- evt
+	}))
+	defer srv.Close()
+
+	if _, err := newWebhookNotifier(&Config{Webhooks: []string{srv.URL}, Logger: tLogger}); err == nil {
+		t.Fatal("no error for missing webhook secret")
+	}
+	hooks, err := newWebhookNotifier(&Config{Webhooks: []string{srv.URL}, WebhookSecret: secret, Logger: tLogger})
+	if err != nil {
+		t.Fatalf("newWebhookNotifier error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hooks.Run(ctx)
+
+	c := &Core{webhooks: hooks}
+	c.notifyWebhooks(newServerNotifyNote(TopicMarketSuspended, "", "", db.WarningLevel))
+	c.notifyWebhooks(newServerNotifyNote(TopicPenalized, "Penalized", "details", db.WarningLevel))
+
+	select {
+	case evt := <-received:
+		if evt.Type != WebhookPenaltyWarning {
+			t.Fatalf("wrong event type %q", evt.Type)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not received")
+	}
+
+	// A Core without webhooks does nothing.
+	(&Core{}).notifyWebhooks(newServerNotifyNote(TopicPenalized, "", "", db.WarningLevel))
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -68,6 +69,7 @@ var (
 	notesBucket           = []byte("notes")
 	pokesBucket           = []byte("pokes")
 	credentialsBucket     = []byte("credentials")
+	addressBooksBucket    = []byte("addressBooks")
 
 	// value keys
 	versionKey            = []byte("version")
@@ -103,6 +105,9 @@ var (
 	redemptionFeesKey     = []byte("redeemFees")
 	fundingFeesKey        = []byte("fundingFees")
 	accelerationsKey      = []byte("accelerations")
+	feeTxsKey             = []byte("feeTxs")
+	ttlKey                = []byte("ttl")
+	maxDriftKey           = []byte("maxDrift")
 	typeKey               = []byte("type")
 	seedGenTimeKey        = []byte("seedGenTime")
 	encSeedKey            = []byte("encSeed")
@@ -137,6 +142,15 @@ var (
 // Opts is a set of options for the DB.
 type Opts struct {
 	BackupOnShutdown bool // default is true
+	// SnapshotInterval is how often a checksummed snapshot of the DB is
+	// written to the snapshots folder. Zero disables snapshots.
+	SnapshotInterval time.Duration
+	// SnapshotsRetained is the number of snapshots kept. Older snapshots are
+	// deleted. Default is DefaultSnapshotsRetained.
+	SnapshotsRetained int
+	// VerifyOnStart runs a consistency check of the DB file when it is
+	// opened. NewDB fails if the DB is corrupt.
+	VerifyOnStart bool
 }
 
 var defaultOpts = Opts{
@@ -156,6 +170,10 @@ var _ dexdb.DB = (*BoltDB)(nil)
 
 // NewDB is a constructor for a *BoltDB.
 func NewDB(dbPath string, logger dex.Logger, opts ...Opts) (dexdb.DB, error) {
+	if err := applyStagedRestore(dbPath, logger); err != nil {
+		return nil, err
+	}
+
 	_, err := os.Stat(dbPath)
 	isNew := os.IsNotExist(err)
 
@@ -176,13 +194,24 @@ func NewDB(dbPath string, logger dex.Logger, opts ...Opts) (dexdb.DB, error) {
 	if len(opts) > 0 {
 		bdb.opts = opts[0]
 	}
+	if bdb.opts.SnapshotsRetained <= 0 {
+		bdb.opts.SnapshotsRetained = DefaultSnapshotsRetained
+	}
+
+	if bdb.opts.VerifyOnStart && !isNew {
+		if err := bdb.verify(); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("database %s failed verification, consider restoring a snapshot: %w", dbPath, err)
+		}
+		bdb.log.Infof("Database %s passed verification", dbPath)
+	}
 
 	if err = bdb.makeTopLevelBuckets([][]byte{
 		appBucket, accountsBucket, bondIndexesBucket,
 		activeOrdersBucket, archivedOrdersBucket,
 		activeMatchesBucket, archivedMatchesBucket,
 		walletsBucket, notesBucket, credentialsBucket,
-		botProgramsBucket, pokesBucket,
+		botProgramsBucket, pokesBucket, addressBooksBucket,
 	}); err != nil {
 		return nil, err
 	}
@@ -231,9 +260,26 @@ func (db *BoltDB) fileSize(path string) int64 {
 	return stat.Size()
 }
 
-// Run waits for context cancellation and closes the database.
+// Run waits for context cancellation and closes the database. If snapshots are
+// enabled, a snapshot is made every SnapshotInterval until then.
 func (db *BoltDB) Run(ctx context.Context) {
-	<-ctx.Done() // wait for shutdown to backup and compact
+	var snapshotTick <-chan time.Time
+	if db.opts.SnapshotInterval > 0 {
+		ticker := time.NewTicker(db.opts.SnapshotInterval)
+		defer ticker.Stop()
+		snapshotTick = ticker.C
+	}
+out:
+	for {
+		select {
+		case <-snapshotTick:
+			if err := db.snapshot(); err != nil {
+				db.log.Errorf("Unable to snapshot database: %v", err)
+			}
+		case <-ctx.Done(): // shutdown to backup and compact
+			break out
+		}
+	}
 
 	// Create a backup in the backups folder.
 	if db.opts.BackupOnShutdown {
@@ -1127,6 +1173,30 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 		})
 	}
 
+	if orderFilter.Since > 0 || orderFilter.Until > 0 {
+		filters = append(filters, func(_ []byte, oBkt *bbolt.Bucket) bool {
+			stampB := oBkt.Get(updateTimeKey)
+			if len(stampB) != 8 {
+				return false
+			}
+			stamp := intCoder.Uint64(stampB)
+			return stamp >= orderFilter.Since && (orderFilter.Until == 0 || stamp <= orderFilter.Until)
+		})
+	}
+
+	if orderFilter.Sell != nil {
+		sell := *orderFilter.Sell
+		filters = append(filters, func(oidB []byte, oBkt *bbolt.Bucket) bool {
+			ord, err := order.DecodeOrder(oBkt.Get(orderKey))
+			if err != nil {
+				db.log.Errorf("error decoding order %x: %v", oidB, err)
+				return false
+			}
+			trade := ord.Trade()
+			return trade != nil && trade.Sell == sell
+		})
+	}
+
 	if !orderFilter.Offset.IsZero() {
 		offsetOID := orderFilter.Offset
 		var stampB []byte
@@ -1251,6 +1321,31 @@ func decodeOrderBucket(oid []byte, oBkt *bbolt.Bucket) (*dexdb.MetaOrder, error)
 		fundingFeesPaid = intCoder.Uint64(fundingFeesB)
 	}
 
+	var feeTxs []*dexdb.OrderFeeTx
+	if feeTxsB := getCopy(oBkt, feeTxsKey); len(feeTxsB) > 0 {
+		_, pushes, err := encode.DecodeBlob(feeTxsB)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode fee txs: %w", err)
+		}
+		for _, b := range pushes {
+			feeTx, err := dexdb.DecodeOrderFeeTx(b)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode fee tx: %w", err)
+			}
+			feeTxs = append(feeTxs, feeTx)
+		}
+	}
+
+	var ttl uint64
+	if ttlB := oBkt.Get(ttlKey); len(ttlB) == 8 {
+		ttl = intCoder.Uint64(ttlB)
+	}
+
+	var maxDrift float64
+	if maxDriftB := oBkt.Get(maxDriftKey); len(maxDriftB) == 8 {
+		maxDrift = math.Float64frombits(intCoder.Uint64(maxDriftB))
+	}
+
 	return &dexdb.MetaOrder{
 		MetaData: &dexdb.OrderMetaData{
 			Proof:              *proof,
@@ -1272,6 +1367,9 @@ func decodeOrderBucket(oid []byte, oBkt *bbolt.Bucket) (*dexdb.MetaOrder, error)
 			RefundReserves:     refundReserves,
 			AccelerationCoins:  accelerationCoinIDs,
 			FundingFeesPaid:    fundingFeesPaid,
+			FeeTxs:             feeTxs,
+			TTL:                ttl,
+			MaxDrift:           maxDrift,
 		},
 		Order: ord,
 	}, nil
@@ -1347,6 +1445,14 @@ func updateOrderMetaData(bkt *bbolt.Bucket, md *dexdb.OrderMetaData) error {
 		}
 	}
 
+	var feeTxsB encode.BuildyBytes
+	if len(md.FeeTxs) > 0 {
+		feeTxsB = encode.BuildyBytes{0}
+		for _, feeTx := range md.FeeTxs {
+			feeTxsB = feeTxsB.AddData(feeTx.Encode())
+		}
+	}
+
 	return newBucketPutter(bkt).
 		put(statusKey, uint16Bytes(uint16(md.Status))).
 		put(updateTimeKey, uint64Bytes(timeNow())).
@@ -1360,6 +1466,9 @@ func updateOrderMetaData(bkt *bbolt.Bucket, md *dexdb.OrderMetaData) error {
 		put(refundReservesKey, uint64Bytes(md.RefundReserves)).
 		put(accelerationsKey, accelerationsB).
 		put(fundingFeesKey, uint64Bytes(md.FundingFeesPaid)).
+		put(feeTxsKey, feeTxsB).
+		put(ttlKey, uint64Bytes(md.TTL)).
+		put(maxDriftKey, uint64Bytes(math.Float64bits(md.MaxDrift))).
 		err()
 }
 
@@ -2463,6 +2572,37 @@ func (db *BoltDB) Language() (lang string, _ error) {
 	})
 }
 
+// SetAddressBook saves the address book for an asset, overwriting any existing
+// address book for the asset.
+func (db *BoltDB) SetAddressBook(book *dexdb.AddressBook) error {
+	return db.Update(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(addressBooksBucket)
+		if bkt == nil {
+			return fmt.Errorf("address books bucket not found")
+		}
+		return bkt.Put(uint32Bytes(book.AssetID), book.Encode())
+	})
+}
+
+// AddressBook retrieves the address book for an asset. If no address book has
+// been stored, an empty, unrestricted *AddressBook is returned.
+func (db *BoltDB) AddressBook(assetID uint32) (book *dexdb.AddressBook, _ error) {
+	return book, db.View(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(addressBooksBucket)
+		if bkt == nil {
+			return fmt.Errorf("address books bucket not found")
+		}
+		bookB := bkt.Get(uint32Bytes(assetID))
+		if bookB == nil {
+			book = &dexdb.AddressBook{AssetID: assetID}
+			return nil
+		}
+		var err error
+		book, err = dexdb.DecodeAddressBook(bookB)
+		return err
+	})
+}
+
 // timeNow is the current unix timestamp in milliseconds.
 func timeNow() uint64 {
 	return uint64(time.Now().UnixMilli())
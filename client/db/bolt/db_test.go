@@ -115,6 +115,91 @@ func TestBackupTo(t *testing.T) {
 	}
 }
 
+func TestSnapshots(t *testing.T) {
+	db, shutdown := newTestDB(t)
+	defer shutdown()
+	db.opts.SnapshotsRetained = 2
+
+	for i := 0; i < 3; i++ {
+		if err := db.snapshot(); err != nil {
+			t.Fatalf("unable to snapshot database: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond) // names have millisecond resolution
+	}
+
+	// The oldest snapshot is pruned.
+	snaps, err := db.Snapshots()
+	if err != nil {
+		t.Fatalf("Snapshots error: %v", err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snaps))
+	}
+	if snaps[0].Stamp <= snaps[1].Stamp {
+		t.Fatalf("snapshots not sorted newest first")
+	}
+	for _, snap := range snaps {
+		if !snap.Valid || snap.Checksum == "" || snap.Size == 0 {
+			t.Fatalf("bad snapshot %+v", snap)
+		}
+	}
+
+	// A modified snapshot fails verification and can't be restored.
+	corruptPath := filepath.Join(db.snapshotsPath(), snaps[1].Name)
+	f, err := os.OpenFile(corruptPath, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("error opening snapshot: %v", err)
+	}
+	f.WriteAt([]byte{0xff, 0xff}, 100)
+	f.Close()
+	snaps, _ = db.Snapshots()
+	if snaps[1].Valid {
+		t.Fatalf("corrupt snapshot reported as valid")
+	}
+	if err := db.RestoreSnapshot(snaps[1].Name); err == nil {
+		t.Fatalf("no error restoring corrupt snapshot")
+	}
+
+	// Only snapshots in the snapshots folder can be restored.
+	if err := db.RestoreSnapshot("../" + filepath.Base(db.Path())); err == nil {
+		t.Fatalf("no error restoring file outside of snapshots folder")
+	}
+
+	// Stage a valid snapshot and apply it.
+	if err := db.RestoreSnapshot(snaps[0].Name); err != nil {
+		t.Fatalf("RestoreSnapshot error: %v", err)
+	}
+	dbPath := db.Path()
+	if _, err := os.Stat(dbPath + restoreExt); err != nil {
+		t.Fatalf("restore not staged: %v", err)
+	}
+	shutdown()
+	if err := applyStagedRestore(dbPath, tLogger); err != nil {
+		t.Fatalf("applyStagedRestore error: %v", err)
+	}
+	if _, err := os.Stat(dbPath + restoreExt); !os.IsNotExist(err) {
+		t.Fatalf("staged restore file not removed")
+	}
+	if _, err := os.Stat(dbPath + preRestoreExt); err != nil {
+		t.Fatalf("previous database not kept: %v", err)
+	}
+	checksum, err := fileChecksum(dbPath)
+	if err != nil {
+		t.Fatalf("fileChecksum error: %v", err)
+	}
+	if checksum != snaps[0].Checksum {
+		t.Fatalf("database not replaced by snapshot")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	db, shutdown := newTestDB(t)
+	defer shutdown()
+	if err := db.verify(); err != nil {
+		t.Fatalf("verify error: %v", err)
+	}
+}
+
 func TestStorePrimaryCredentials(t *testing.T) {
 	boltdb, shutdown := newTestDB(t)
 	defer shutdown()
@@ -471,6 +556,9 @@ func TestOrders(t *testing.T) {
 				SwapFeesPaid:       rand.Uint64(),
 				RedemptionFeesPaid: rand.Uint64(),
 				MaxFeeRate:         rand.Uint64(),
+				FeeTxs:             []*db.OrderFeeTx{dbtest.RandomOrderFeeTx(), dbtest.RandomOrderFeeTx()},
+				TTL:                rand.Uint64(),
+				MaxDrift:           rand.Float64() * 100,
 			},
 			Order: ord,
 		}
@@ -511,6 +599,13 @@ func TestOrders(t *testing.T) {
 	if firstOrd.MetaData.MaxFeeRate != mord.MetaData.MaxFeeRate {
 		t.Fatalf("wrong MaxFeeRate. wanted %d, got %d", firstOrd.MetaData.MaxFeeRate, mord.MetaData.MaxFeeRate)
 	}
+	dbtest.MustCompareOrderFeeTxs(t, firstOrd.MetaData.FeeTxs, mord.MetaData.FeeTxs)
+	if firstOrd.MetaData.TTL != mord.MetaData.TTL {
+		t.Fatalf("wrong TTL. wanted %d, got %d", firstOrd.MetaData.TTL, mord.MetaData.TTL)
+	}
+	if firstOrd.MetaData.MaxDrift != mord.MetaData.MaxDrift {
+		t.Fatalf("wrong MaxDrift. wanted %f, got %f", firstOrd.MetaData.MaxDrift, mord.MetaData.MaxDrift)
+	}
 
 	// Check the active orders.
 	activeOrders, err := boltdb.ActiveOrders()
@@ -649,7 +744,7 @@ func TestOrderFilters(t *testing.T) {
 	boltdb, shutdown := newTestDB(t)
 	defer shutdown()
 
-	makeOrder := func(host string, base, quote uint32, stamp int64, status order.OrderStatus) *db.MetaOrder {
+	makeOrder := func(host string, base, quote uint32, stamp int64, status order.OrderStatus, sell bool) *db.MetaOrder {
 		mord := &db.MetaOrder{
 			MetaData: &db.OrderMetaData{
 				Status: status,
@@ -662,6 +757,9 @@ func TestOrderFilters(t *testing.T) {
 					QuoteAsset: quote,
 					ServerTime: time.UnixMilli(stamp),
 				},
+				T: order.Trade{
+					Sell: sell,
+				},
 			},
 		}
 		oid := mord.Order.ID()
@@ -694,14 +792,15 @@ func TestOrderFilters(t *testing.T) {
 	var asset2 uint32 = 2
 	var asset3 uint32 = 3
 	orders := []*db.MetaOrder{
-		makeOrder(host1, asset1, asset2, start, order.OrderStatusExecuted),   // 0, oid: 95318d1d4d1d19348d96f260e6d54eca942ce9bf0760f43edc7afa2c0173a401
-		makeOrder(host2, asset2, asset3, start+1, order.OrderStatusRevoked),  // 1, oid: 58148721dd3109647fd912fb1b3e29be7c72e72cf589dcbe5d0697735e1df8bb
-		makeOrder(host1, asset3, asset1, start+2, order.OrderStatusCanceled), // 2, oid: feea1852996c042174a40a88e74175f95009ce72b31d51402f452b28f2618a13
-		makeOrder(host2, asset1, asset3, start+3, order.OrderStatusEpoch),    // 3, oid: 8707caf2e70bc615845673cf30e44c67dec972064ab137a321d9ee98e8c96fe3
-		makeOrder(host1, asset2, asset3, start+4, order.OrderStatusBooked),   // 4, oid: e2fe7b28eae9a4511013ecb35be58e8031e5f7ec9f3a0e2f6411ec58efd4464a
-		makeOrder(host2, asset3, asset1, start+4, order.OrderStatusExecuted), // 5, oid: c76d4dfbc4ea8e0e8065e809f9c3ebfca98a1053a42f464e7632f79126f752d0
+		makeOrder(host1, asset1, asset2, start, order.OrderStatusExecuted, true),    // 0, oid: c81abcd460f413a1e39a5498991cdc287888646b6269be28f4b82dee331eb50c
+		makeOrder(host2, asset2, asset3, start+1, order.OrderStatusRevoked, false),  // 1, oid: 58148721dd3109647fd912fb1b3e29be7c72e72cf589dcbe5d0697735e1df8bb
+		makeOrder(host1, asset3, asset1, start+2, order.OrderStatusCanceled, true),  // 2, oid: 13106ca635475de5e606aa52a0a2c89ed9a0d075db0d56781e0298c549135e16
+		makeOrder(host2, asset1, asset3, start+3, order.OrderStatusEpoch, false),    // 3, oid: 8707caf2e70bc615845673cf30e44c67dec972064ab137a321d9ee98e8c96fe3
+		makeOrder(host1, asset2, asset3, start+4, order.OrderStatusBooked, false),   // 4, oid: e2fe7b28eae9a4511013ecb35be58e8031e5f7ec9f3a0e2f6411ec58efd4464a
+		makeOrder(host2, asset3, asset1, start+4, order.OrderStatusExecuted, false), // 5, oid: c76d4dfbc4ea8e0e8065e809f9c3ebfca98a1053a42f464e7632f79126f752d0
 	}
 	orderCount := len(orders)
+	tSell, tBuy := true, false
 
 	for i, ord := range orders {
 		fmt.Println(i, ord.Order.ID().String())
@@ -770,6 +869,49 @@ func TestOrderFilters(t *testing.T) {
 			},
 			expected: []int{4, 3},
 		},
+		{
+			name: "sell",
+			filter: &db.OrderFilter{
+				N:    orderCount,
+				Sell: &tSell,
+			},
+			expected: []int{2, 0},
+		},
+		{
+			name: "buy + host2",
+			filter: &db.OrderFilter{
+				N:     orderCount,
+				Hosts: []string{host2},
+				Sell:  &tBuy,
+			},
+			expected: []int{5, 3, 1},
+		},
+		{
+			name: "since",
+			filter: &db.OrderFilter{
+				N:     orderCount,
+				Since: uint64(start + 3),
+			},
+			expected: []int{4, 5, 3},
+		},
+		{
+			name: "since + until",
+			filter: &db.OrderFilter{
+				N:     orderCount,
+				Since: uint64(start + 1),
+				Until: uint64(start + 2),
+			},
+			expected: []int{2, 1},
+		},
+		{
+			name: "until + offset",
+			filter: &db.OrderFilter{
+				N:      orderCount,
+				Offset: orders[2].Order.ID(),
+				Until:  uint64(start + 3),
+			},
+			expected: []int{1, 0},
+		},
 	}
 
 	for _, test := range tests {
@@ -1613,3 +1755,42 @@ func TestPokes(t *testing.T) {
 		t.Fatal("Result from second LoadPokes wasn't empty")
 	}
 }
+
+func TestAddressBook(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	// No address book stored yet.
+	book, err := boltdb.AddressBook(42)
+	if err != nil {
+		t.Fatalf("AddressBook error: %v", err)
+	}
+	if book.AssetID != 42 || book.Restricted || len(book.EncEntries) != 0 {
+		t.Fatalf("expected an empty address book, got %+v", book)
+	}
+
+	book = &db.AddressBook{
+		AssetID:    42,
+		EncEntries: []byte{0x01, 0x02, 0x03},
+		Restricted: true,
+	}
+	if err := boltdb.SetAddressBook(book); err != nil {
+		t.Fatalf("SetAddressBook error: %v", err)
+	}
+	reBook, err := boltdb.AddressBook(42)
+	if err != nil {
+		t.Fatalf("AddressBook error: %v", err)
+	}
+	if reBook.AssetID != book.AssetID || !reBook.Restricted || !bytes.Equal(reBook.EncEntries, book.EncEntries) {
+		t.Fatalf("wrong address book. wanted %+v, got %+v", book, reBook)
+	}
+
+	// Other assets are unaffected.
+	otherBook, err := boltdb.AddressBook(0)
+	if err != nil {
+		t.Fatalf("AddressBook error: %v", err)
+	}
+	if otherBook.Restricted {
+		t.Fatalf("address book for another asset is restricted")
+	}
+}
@@ -0,0 +1,284 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bolt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// DefaultSnapshotsRetained is the default number of snapshots kept.
+	DefaultSnapshotsRetained = 5
+
+	snapshotDir    = "snapshots"
+	snapshotPrefix = "snapshot-"
+	snapshotExt    = ".db"
+	checksumExt    = ".sha256"
+	// A snapshot staged by RestoreSnapshot is written next to the DB file
+	// with restoreExt, and is swapped in by NewDB. The replaced DB file is
+	// kept with preRestoreExt.
+	restoreExt    = ".restore"
+	preRestoreExt = ".prerestore"
+
+	// maxCheckErrors is the number of consistency errors reported by verify.
+	maxCheckErrors = 5
+)
+
+// snapshotsPath is the folder holding the DB's snapshots.
+func (db *BoltDB) snapshotsPath() string {
+	return filepath.Join(filepath.Dir(db.Path()), snapshotDir)
+}
+
+// snapshot writes a copy of the DB and its checksum into the snapshots folder,
+// then deletes any snapshots in excess of SnapshotsRetained.
+func (db *BoltDB) snapshot() error {
+	dir := db.snapshotsPath()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("unable to create snapshot directory: %w", err)
+	}
+	name := snapshotPrefix + strconv.FormatInt(time.Now().UnixMilli(), 10) + snapshotExt
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	err = db.View(func(tx *bbolt.Tx) error {
+		_, err := tx.WriteTo(io.MultiWriter(f, h))
+		return err
+	})
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.WriteFile(path+checksumExt, []byte(hex.EncodeToString(h.Sum(nil))+"\n"), 0600)
+	}
+	if err != nil {
+		os.Remove(path)
+		os.Remove(path + checksumExt)
+		return err
+	}
+	db.log.Debugf("Created database snapshot %s", name)
+
+	return db.pruneSnapshots()
+}
+
+// pruneSnapshots deletes the oldest snapshots until no more than
+// SnapshotsRetained remain.
+func (db *BoltDB) pruneSnapshots() error {
+	names, err := db.snapshotNames()
+	if err != nil {
+		return err
+	}
+	if len(names) <= db.opts.SnapshotsRetained {
+		return nil
+	}
+	for _, name := range names[db.opts.SnapshotsRetained:] {
+		path := filepath.Join(db.snapshotsPath(), name)
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("unable to delete snapshot %s: %w", name, err)
+		}
+		os.Remove(path + checksumExt)
+	}
+	return nil
+}
+
+// snapshotStamp parses the creation time in milliseconds from the snapshot
+// file name.
+func snapshotStamp(name string) (uint64, bool) {
+	if !strings.HasPrefix(name, snapshotPrefix) || !strings.HasSuffix(name, snapshotExt) {
+		return 0, false
+	}
+	stamp, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), snapshotExt), 10, 64)
+	return stamp, err == nil
+}
+
+// snapshotNames lists the file names of the snapshots, newest first.
+func (db *BoltDB) snapshotNames() ([]string, error) {
+	entries, err := os.ReadDir(db.snapshotsPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	type stampedName struct {
+		name  string
+		stamp uint64
+	}
+	snaps := make([]*stampedName, 0, len(entries))
+	for _, entry := range entries {
+		if stamp, ok := snapshotStamp(entry.Name()); ok && entry.Type().IsRegular() {
+			snaps = append(snaps, &stampedName{entry.Name(), stamp})
+		}
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].stamp > snaps[j].stamp })
+	names := make([]string, 0, len(snaps))
+	for _, snap := range snaps {
+		names = append(names, snap.name)
+	}
+	return names, nil
+}
+
+// fileChecksum computes the hex-encoded SHA-256 of the file's contents.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadSnapshot describes the named snapshot, comparing the snapshot file with
+// its recorded checksum.
+func (db *BoltDB) loadSnapshot(name string) (*dexdb.Snapshot, error) {
+	stamp, ok := snapshotStamp(name)
+	if !ok || filepath.Base(name) != name {
+		return nil, fmt.Errorf("invalid snapshot name %q", name)
+	}
+	path := filepath.Join(db.snapshotsPath(), name)
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	snap := &dexdb.Snapshot{
+		Name:  name,
+		Stamp: stamp,
+		Size:  fi.Size(),
+	}
+	b, err := os.ReadFile(path + checksumExt)
+	if err != nil {
+		db.log.Warnf("No checksum for snapshot %s: %v", name, err)
+		return snap, nil
+	}
+	snap.Checksum = string(bytes.TrimSpace(b))
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return nil, err
+	}
+	snap.Valid = checksum == snap.Checksum
+	return snap, nil
+}
+
+// Snapshots lists the database snapshots, newest first. The checksum of each
+// snapshot is verified. Snapshots is part of the db.DB interface.
+func (db *BoltDB) Snapshots() ([]*dexdb.Snapshot, error) {
+	names, err := db.snapshotNames()
+	if err != nil {
+		return nil, err
+	}
+	snaps := make([]*dexdb.Snapshot, 0, len(names))
+	for _, name := range names {
+		snap, err := db.loadSnapshot(name)
+		if err != nil {
+			return nil, err
+		}
+		if !snap.Valid {
+			db.log.Warnf("Snapshot %s does not match its checksum", name)
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
+
+// RestoreSnapshot verifies the named snapshot and stages it to replace the
+// database the next time the database is opened. The snapshot itself is left
+// in place. RestoreSnapshot is part of the db.DB interface.
+func (db *BoltDB) RestoreSnapshot(name string) error {
+	snap, err := db.loadSnapshot(name)
+	if err != nil {
+		return err
+	}
+	if !snap.Valid {
+		return fmt.Errorf("snapshot %s does not match its checksum", name)
+	}
+
+	src, err := os.Open(filepath.Join(db.snapshotsPath(), name))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(db.Path()+restoreExt, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	if err == nil {
+		err = dst.Sync()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(db.Path() + restoreExt)
+		return fmt.Errorf("unable to stage snapshot %s: %w", name, err)
+	}
+	db.log.Infof("Snapshot %s will be restored the next time the database is opened", name)
+	return nil
+}
+
+// applyStagedRestore replaces the DB file with a snapshot staged by
+// RestoreSnapshot, if there is one. The replaced DB file is kept with the
+// preRestoreExt extension.
+func applyStagedRestore(dbPath string, logger dex.Logger) error {
+	restorePath := dbPath + restoreExt
+	if _, err := os.Stat(restorePath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := os.Rename(dbPath, dbPath+preRestoreExt); err != nil {
+			return fmt.Errorf("unable to move database aside for restore: %w", err)
+		}
+	}
+	if err := os.Rename(restorePath, dbPath); err != nil {
+		return fmt.Errorf("unable to restore snapshot: %w", err)
+	}
+	logger.Infof("Restored database from snapshot. The previous database file is %s", dbPath+preRestoreExt)
+	return nil
+}
+
+// verify runs a consistency check of the DB file.
+func (db *BoltDB) verify() error {
+	return db.View(func(tx *bbolt.Tx) error {
+		var errs []string
+		var n int
+		for err := range tx.Check() {
+			n++
+			if len(errs) < maxCheckErrors {
+				errs = append(errs, err.Error())
+			}
+		}
+		if n > 0 {
+			return fmt.Errorf("%d consistency errors: %s", n, strings.Join(errs, "; "))
+		}
+		return nil
+	})
+}
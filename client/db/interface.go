@@ -118,6 +118,12 @@ type DB interface {
 	// BackupTo makes a backup of the database at the specified location,
 	// optionally overwriting any existing file and compacting the database.
 	BackupTo(dst string, overwrite, compact bool) error
+	// Snapshots lists the database snapshots, newest first. The checksum of
+	// each snapshot is verified.
+	Snapshots() ([]*Snapshot, error)
+	// RestoreSnapshot verifies the named snapshot and stages it to replace
+	// the database the next time the database is opened.
+	RestoreSnapshot(name string) error
 	// SaveNotification saves the notification.
 	SaveNotification(*Notification) error
 	// NotificationsN reads out the N most recent notifications.
@@ -151,6 +157,12 @@ type DB interface {
 	// SaveDisabledRateSources saves disabled fiat rate sources in the database.
 	// A source name must not contain a comma.
 	SaveDisabledRateSources(disabledSources []string) error
+	// SetAddressBook saves the address book for an asset, overwriting any
+	// existing address book for the asset.
+	SetAddressBook(book *AddressBook) error
+	// AddressBook fetches the address book for an asset. If no address book
+	// has been stored, an empty, unrestricted *AddressBook is returned.
+	AddressBook(assetID uint32) (*AddressBook, error)
 	// SetLanguage stores the user's chosen language.
 	SetLanguage(lang string) error
 	// Language gets the language stored with SetLanguage.
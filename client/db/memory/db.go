@@ -0,0 +1,1109 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package memory provides a dexdb.DB that keeps all data in memory. Nothing
+// is ever written to disk, and everything is lost when the process exits.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encrypt"
+	"decred.org/dcrdex/dex/order"
+)
+
+// Check that MemoryDB satisfies the db.DB interface.
+var _ db.DB = (*MemoryDB)(nil)
+
+// ErrNoBackup is returned by the backup methods, since an in-memory database
+// cannot be backed up to disk.
+const ErrNoBackup = dex.ErrorKind("the in-memory database cannot be backed up")
+
+type account struct {
+	info     []byte // AccountInfo encoding, which excludes bonds and status
+	disabled bool
+	bonds    map[string]*db.Bond
+}
+
+type metaOrder struct {
+	ord   []byte // order.EncodeOrder
+	md    db.OrderMetaData
+	stamp uint64 // last update, unix ms
+}
+
+type metaMatch struct {
+	match  []byte // order.EncodeMatch
+	md     db.MatchMetaData
+	active bool
+}
+
+type note struct {
+	note  []byte // Notification encoding
+	stamp uint64
+	ack   bool
+}
+
+type wallet struct {
+	wallet   []byte // Wallet encoding, which excludes balance and status
+	balance  []byte
+	disabled bool
+}
+
+// MemoryDB is an in-memory implementation of db.DB. Where a type has a binary
+// encoding, values are stored encoded and decoded on retrieval, as with the
+// bolt database.
+type MemoryDB struct {
+	log dex.Logger
+
+	mtx             sync.RWMutex
+	creds           *db.PrimaryCredentials
+	seedGenTime     uint64
+	accounts        map[string]*account
+	bondIndexes     map[uint32]uint32
+	orders          map[order.OrderID]*metaOrder
+	matches         map[string]*metaMatch
+	wallets         map[string]*wallet
+	notes           map[string]*note
+	pokes           []byte
+	disabledSources []string
+	lang            string
+	addressBooks    map[uint32][]byte
+}
+
+// NewDB creates an empty in-memory database.
+func NewDB(logger dex.Logger) *MemoryDB {
+	return &MemoryDB{
+		log:          logger,
+		accounts:     make(map[string]*account),
+		bondIndexes:  make(map[uint32]uint32),
+		orders:       make(map[order.OrderID]*metaOrder),
+		matches:      make(map[string]*metaMatch),
+		wallets:      make(map[string]*wallet),
+		notes:        make(map[string]*note),
+		addressBooks: make(map[uint32][]byte),
+	}
+}
+
+// Run waits for context cancellation. There is nothing to flush or close.
+func (mdb *MemoryDB) Run(ctx context.Context) {
+	<-ctx.Done()
+}
+
+// timeNow is the current unix timestamp in milliseconds.
+func timeNow() uint64 {
+	return uint64(time.Now().UnixMilli())
+}
+
+// validateCreds checks that the PrimaryCredentials fields are properly
+// populated.
+func validateCreds(creds *db.PrimaryCredentials) error {
+	if len(creds.EncSeed) == 0 {
+		return errors.New("EncSeed not set")
+	}
+	if len(creds.EncInnerKey) == 0 {
+		return errors.New("EncInnerKey not set")
+	}
+	if len(creds.InnerKeyParams) == 0 {
+		return errors.New("InnerKeyParams not set")
+	}
+	if len(creds.OuterKeyParams) == 0 {
+		return errors.New("OuterKeyParams not set")
+	}
+	return nil
+}
+
+func copyCreds(creds *db.PrimaryCredentials) *db.PrimaryCredentials {
+	return &db.PrimaryCredentials{
+		EncSeed:        bytes.Clone(creds.EncSeed),
+		EncInnerKey:    bytes.Clone(creds.EncInnerKey),
+		InnerKeyParams: bytes.Clone(creds.InnerKeyParams),
+		OuterKeyParams: bytes.Clone(creds.OuterKeyParams),
+		Birthday:       creds.Birthday,
+		Version:        creds.Version,
+	}
+}
+
+// SetPrimaryCredentials validates and stores the PrimaryCredentials.
+func (mdb *MemoryDB) SetPrimaryCredentials(creds *db.PrimaryCredentials) error {
+	if err := validateCreds(creds); err != nil {
+		return err
+	}
+	mdb.mtx.Lock()
+	mdb.creds = copyCreds(creds)
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// PrimaryCredentials retrieves the *PrimaryCredentials, if they are stored. It
+// is an error if none have been stored.
+func (mdb *MemoryDB) PrimaryCredentials() (*db.PrimaryCredentials, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	if mdb.creds == nil {
+		return nil, db.ErrNoCredentials
+	}
+	return copyCreds(mdb.creds), nil
+}
+
+// Recrypt re-encrypts the wallet passwords and account private keys, and
+// stores the new *PrimaryCredentials.
+func (mdb *MemoryDB) Recrypt(creds *db.PrimaryCredentials, oldCrypter, newCrypter encrypt.Crypter) (walletUpdates map[uint32][]byte, acctUpdates map[string][]byte, err error) {
+	if err := validateCreds(creds); err != nil {
+		return nil, nil, err
+	}
+
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+
+	recrypt := func(b []byte) ([]byte, error) {
+		pt, err := oldCrypter.Decrypt(b)
+		if err != nil {
+			return nil, fmt.Errorf("Decrypt error: %w", err)
+		}
+		ct, err := newCrypter.Encrypt(pt)
+		if err != nil {
+			return nil, fmt.Errorf("Encrypt error: %w", err)
+		}
+		return ct, nil
+	}
+
+	// Encode everything before storing any of it, so a failure leaves the
+	// database unchanged.
+	walletUpdates = make(map[uint32][]byte)
+	walletBs := make(map[string][]byte)
+	for wid, w := range mdb.wallets {
+		dbWallet, err := db.DecodeWallet(w.wallet)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(dbWallet.EncryptedPW) == 0 {
+			continue
+		}
+		if dbWallet.EncryptedPW, err = recrypt(dbWallet.EncryptedPW); err != nil {
+			return nil, nil, fmt.Errorf("wallets update error: %w", err)
+		}
+		walletBs[wid] = dbWallet.Encode()
+		walletUpdates[dbWallet.AssetID] = dbWallet.EncryptedPW
+	}
+
+	acctUpdates = make(map[string][]byte)
+	acctBs := make(map[string][]byte)
+	for host, acct := range mdb.accounts {
+		acctInfo, err := db.DecodeAccountInfo(acct.info)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(acctInfo.LegacyEncKey) != 0 {
+			if acctInfo.LegacyEncKey, err = recrypt(acctInfo.LegacyEncKey); err != nil {
+				return nil, nil, fmt.Errorf("accounts update error: %w", err)
+			}
+			acctUpdates[host] = acctInfo.LegacyEncKey
+		} else if len(acctInfo.EncKeyV2) > 0 {
+			if acctInfo.EncKeyV2, err = recrypt(acctInfo.EncKeyV2); err != nil {
+				return nil, nil, fmt.Errorf("accounts update error: %w", err)
+			}
+			acctUpdates[host] = acctInfo.EncKeyV2
+		}
+		acctBs[host] = acctInfo.Encode()
+	}
+
+	for wid, b := range walletBs {
+		mdb.wallets[wid].wallet = b
+	}
+	for host, b := range acctBs {
+		mdb.accounts[host].info = b
+	}
+	mdb.creds = copyCreds(creds)
+	return walletUpdates, acctUpdates, nil
+}
+
+// SetSeedGenerationTime stores the time the app seed was generated.
+func (mdb *MemoryDB) SetSeedGenerationTime(time uint64) error {
+	mdb.mtx.Lock()
+	mdb.seedGenTime = time
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// SeedGenerationTime returns the time the app seed was generated, if it was
+// stored. It returns db.ErrNoSeedGenTime if it was not stored.
+func (mdb *MemoryDB) SeedGenerationTime() (uint64, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	if mdb.seedGenTime == 0 {
+		return 0, db.ErrNoSeedGenTime
+	}
+	return mdb.seedGenTime, nil
+}
+
+// sortedHosts returns the account hosts in lexicographical order, which is
+// the order of the bolt database's account buckets.
+func (mdb *MemoryDB) sortedHosts() []string {
+	hosts := make([]string, 0, len(mdb.accounts))
+	for host := range mdb.accounts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// ListAccounts returns a list of the hosts of the enabled DEX accounts.
+func (mdb *MemoryDB) ListAccounts() ([]string, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	var hosts []string
+	for _, host := range mdb.sortedHosts() {
+		if !mdb.accounts[host].disabled {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
+
+func (acct *account) accountInfo() (*db.AccountInfo, error) {
+	acctInfo, err := db.DecodeAccountInfo(acct.info)
+	if err != nil {
+		return nil, err
+	}
+	acctInfo.Disabled = acct.disabled
+	uids := make([]string, 0, len(acct.bonds))
+	for uid := range acct.bonds {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	for _, uid := range uids {
+		acctInfo.Bonds = append(acctInfo.Bonds, copyBond(acct.bonds[uid]))
+	}
+	return acctInfo, nil
+}
+
+func copyBond(bond *db.Bond) *db.Bond {
+	b := *bond
+	b.CoinID = bytes.Clone(bond.CoinID)
+	b.UnsignedTx = bytes.Clone(bond.UnsignedTx)
+	b.SignedTx = bytes.Clone(bond.SignedTx)
+	b.Data = bytes.Clone(bond.Data)
+	b.RefundTx = bytes.Clone(bond.RefundTx)
+	return &b
+}
+
+// Accounts returns all of the DEX accounts.
+func (mdb *MemoryDB) Accounts() ([]*db.AccountInfo, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	var accounts []*db.AccountInfo
+	for _, host := range mdb.sortedHosts() {
+		acctInfo, err := mdb.accounts[host].accountInfo()
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, acctInfo)
+	}
+	return accounts, nil
+}
+
+// Account gets the AccountInfo associated with the specified DEX host.
+func (mdb *MemoryDB) Account(host string) (*db.AccountInfo, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	acct, found := mdb.accounts[host]
+	if !found {
+		return nil, db.ErrAcctNotFound
+	}
+	return acct.accountInfo()
+}
+
+// CreateAccount saves the AccountInfo. If an account already exists for this
+// DEX, it will return an error.
+func (mdb *MemoryDB) CreateAccount(ai *db.AccountInfo) error {
+	if ai.Host == "" {
+		return fmt.Errorf("empty host not allowed")
+	}
+	if ai.DEXPubKey == nil {
+		return fmt.Errorf("nil DEXPubKey not allowed")
+	}
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	if _, found := mdb.accounts[ai.Host]; found {
+		return fmt.Errorf("account already exists for %s", ai.Host)
+	}
+	acct := &account{
+		info:  ai.Encode(),
+		bonds: make(map[string]*db.Bond, len(ai.Bonds)),
+	}
+	for _, bond := range ai.Bonds {
+		acct.bonds[string(bond.UniqueID())] = copyBond(bond)
+	}
+	mdb.accounts[ai.Host] = acct
+	return nil
+}
+
+// UpdateAccountInfo updates the account info for an existing account with
+// the same Host as the parameter. If no account exists with this host,
+// an error is returned.
+func (mdb *MemoryDB) UpdateAccountInfo(ai *db.AccountInfo) error {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	acct, found := mdb.accounts[ai.Host]
+	if !found {
+		return fmt.Errorf("account not found for %s", ai.Host)
+	}
+	acct.info = ai.Encode()
+	for _, bond := range ai.Bonds {
+		acct.bonds[string(bond.UniqueID())] = copyBond(bond)
+	}
+	return nil
+}
+
+// ToggleAccountStatus enables or disables the account associated with the
+// given host.
+func (mdb *MemoryDB) ToggleAccountStatus(host string, disable bool) error {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	acct, found := mdb.accounts[host]
+	if !found {
+		return fmt.Errorf("account not found for %s", host)
+	}
+	if acct.disabled == disable {
+		if disable {
+			return errors.New("account is already disabled")
+		}
+		return errors.New("account is already enabled")
+	}
+	acct.disabled = disable
+	return nil
+}
+
+// AddBond saves a new Bond or updates an existing bond for an existing DEX
+// account.
+func (mdb *MemoryDB) AddBond(host string, bond *db.Bond) error {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	acct, found := mdb.accounts[host]
+	if !found {
+		return fmt.Errorf("account not found for %s", host)
+	}
+	acct.bonds[string(bond.UniqueID())] = copyBond(bond)
+	return nil
+}
+
+// NextBondKeyIndex returns the next bond key index and increments the stored
+// value so that subsequent calls will always return a higher index.
+func (mdb *MemoryDB) NextBondKeyIndex(assetID uint32) (uint32, error) {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	idx := mdb.bondIndexes[assetID]
+	mdb.bondIndexes[assetID] = idx + 1
+	return idx, nil
+}
+
+func (mdb *MemoryDB) setBondFlag(host string, assetID uint32, bondCoinID []byte, set func(*db.Bond)) error {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	acct, found := mdb.accounts[host]
+	if !found {
+		return fmt.Errorf("account not found for %s", host)
+	}
+	bondUID := db.BondUID(assetID, bondCoinID)
+	bond, found := acct.bonds[string(bondUID)]
+	if !found {
+		return fmt.Errorf("bond does not exist: %x", bondUID)
+	}
+	set(bond)
+	return nil
+}
+
+// ConfirmBond marks a DEX account bond as confirmed by the DEX.
+func (mdb *MemoryDB) ConfirmBond(host string, assetID uint32, bondCoinID []byte) error {
+	return mdb.setBondFlag(host, assetID, bondCoinID, func(bond *db.Bond) { bond.Confirmed = true })
+}
+
+// BondRefunded marks a DEX account bond as refunded by the client wallet.
+func (mdb *MemoryDB) BondRefunded(host string, assetID uint32, bondCoinID []byte) error {
+	return mdb.setBondFlag(host, assetID, bondCoinID, func(bond *db.Bond) { bond.Refunded = true })
+}
+
+func (mo *metaOrder) metaOrder() (*db.MetaOrder, error) {
+	ord, err := order.DecodeOrder(mo.ord)
+	if err != nil {
+		return nil, err
+	}
+	md := mo.md
+	return &db.MetaOrder{
+		MetaData: &md,
+		Order:    ord,
+	}, nil
+}
+
+// UpdateOrder saves the order information in the database. Any existing order
+// info for the same order ID will be overwritten without indication.
+func (mdb *MemoryDB) UpdateOrder(m *db.MetaOrder) error {
+	ord, md := m.Order, m.MetaData
+	if md.Status == order.OrderStatusUnknown {
+		return fmt.Errorf("cannot set order %s status to unknown", ord.ID())
+	}
+	if md.Host == "" {
+		return fmt.Errorf("empty DEX not allowed")
+	}
+	if len(md.Proof.DEXSig) == 0 {
+		return fmt.Errorf("cannot save order without DEX signature")
+	}
+	mdb.mtx.Lock()
+	mdb.orders[ord.ID()] = &metaOrder{
+		ord:   order.EncodeOrder(ord),
+		md:    *md,
+		stamp: timeNow(),
+	}
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// newestOrders returns the n newest orders that pass the filter, or all of
+// them if n is zero.
+func (mdb *MemoryDB) newestOrders(n int, filter func(order.OrderID, *metaOrder) bool) ([]*db.MetaOrder, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	oids := make([]order.OrderID, 0, len(mdb.orders))
+	for oid, mo := range mdb.orders {
+		if filter(oid, mo) {
+			oids = append(oids, oid)
+		}
+	}
+	sort.Slice(oids, func(i, j int) bool {
+		// Newest first, or by key if the times are equal.
+		t1, t2 := mdb.orders[oids[i]].stamp, mdb.orders[oids[j]].stamp
+		return t1 > t2 || (t1 == t2 && bytes.Compare(oids[i][:], oids[j][:]) == 1)
+	})
+	if n > 0 && len(oids) > n {
+		oids = oids[:n]
+	}
+	orders := make([]*db.MetaOrder, 0, len(oids))
+	for _, oid := range oids {
+		mo, err := mdb.orders[oid].metaOrder()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding order %s: %w", oid, err)
+		}
+		orders = append(orders, mo)
+	}
+	return orders, nil
+}
+
+// ActiveOrders retrieves all orders which appear to be in an active state,
+// which is either in the epoch queue or in the order book.
+func (mdb *MemoryDB) ActiveOrders() ([]*db.MetaOrder, error) {
+	return mdb.newestOrders(0, func(_ order.OrderID, mo *metaOrder) bool {
+		return mo.md.Status.IsActive()
+	})
+}
+
+// AccountOrders retrieves all orders associated with the specified DEX. n = 0
+// applies no limit on number of orders returned. since = 0 is equivalent to
+// disabling the time filter, since no orders were created before 1970.
+func (mdb *MemoryDB) AccountOrders(dex string, n int, since uint64) ([]*db.MetaOrder, error) {
+	return mdb.newestOrders(n, func(_ order.OrderID, mo *metaOrder) bool {
+		return mo.md.Host == dex && mo.stamp >= since
+	})
+}
+
+// MarketOrders retrieves all orders for the specified DEX and market. n = 0
+// applies no limit on number of orders returned. since = 0 is equivalent to
+// disabling the time filter, since no orders were created before 1970.
+func (mdb *MemoryDB) MarketOrders(dex string, base, quote uint32, n int, since uint64) ([]*db.MetaOrder, error) {
+	return mdb.newestOrders(n, func(_ order.OrderID, mo *metaOrder) bool {
+		if mo.md.Host != dex || mo.stamp < since {
+			return false
+		}
+		ord, err := order.DecodeOrder(mo.ord)
+		return err == nil && ord.Base() == base && ord.Quote() == quote
+	})
+}
+
+// ActiveDEXOrders retrieves the active orders for the specified DEX.
+func (mdb *MemoryDB) ActiveDEXOrders(dex string) ([]*db.MetaOrder, error) {
+	return mdb.newestOrders(0, func(_ order.OrderID, mo *metaOrder) bool {
+		return mo.md.Status.IsActive() && mo.md.Host == dex
+	})
+}
+
+// Order fetches a MetaOrder by order ID.
+func (mdb *MemoryDB) Order(oid order.OrderID) (*db.MetaOrder, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	mo, found := mdb.orders[oid]
+	if !found {
+		return nil, fmt.Errorf("order %s not found", oid)
+	}
+	return mo.metaOrder()
+}
+
+// Orders fetches a slice of orders, sorted by descending time, and filtered
+// with the provided OrderFilter. Orders does not return cancel orders.
+func (mdb *MemoryDB) Orders(filter *db.OrderFilter) ([]*db.MetaOrder, error) {
+	hosts := make(map[string]bool, len(filter.Hosts))
+	for _, host := range filter.Hosts {
+		hosts[host] = true
+	}
+	assetIDs := make(map[uint32]bool, len(filter.Assets))
+	for _, assetID := range filter.Assets {
+		assetIDs[assetID] = true
+	}
+	statuses := make(map[order.OrderStatus]bool, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		statuses[status] = true
+	}
+
+	var offsetStamp uint64
+	if !filter.Offset.IsZero() {
+		mdb.mtx.RLock()
+		mo, found := mdb.orders[filter.Offset]
+		if found {
+			offsetStamp = mo.stamp
+		}
+		mdb.mtx.RUnlock()
+		if !found {
+			return nil, fmt.Errorf("order %s not found", filter.Offset)
+		}
+	}
+
+	return mdb.newestOrders(filter.N, func(oid order.OrderID, mo *metaOrder) bool {
+		ord, err := order.DecodeOrder(mo.ord)
+		if err != nil {
+			mdb.log.Errorf("Error decoding order %s: %v", oid, err)
+			return false
+		}
+		if ord.Type() == order.CancelOrderType {
+			return false
+		}
+		if len(hosts) > 0 && !hosts[mo.md.Host] {
+			return false
+		}
+		if len(assetIDs) > 0 && !assetIDs[ord.Base()] && !assetIDs[ord.Quote()] {
+			return false
+		}
+		if len(statuses) > 0 && !statuses[mo.md.Status] {
+			return false
+		}
+		if filter.Market != nil && (filter.Market.Base != ord.Base() || filter.Market.Quote != ord.Quote()) {
+			return false
+		}
+		if !filter.Offset.IsZero() {
+			return mo.stamp < offsetStamp || (mo.stamp == offsetStamp && bytes.Compare(filter.Offset[:], oid[:]) < 0)
+		}
+		return true
+	})
+}
+
+// updateOrder applies the update to the stored order and stamps it.
+func (mdb *MemoryDB) updateOrder(oid order.OrderID, update func(*metaOrder)) error {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	mo, found := mdb.orders[oid]
+	if !found {
+		return fmt.Errorf("order %s not found", oid)
+	}
+	update(mo)
+	return nil
+}
+
+// UpdateOrderMetaData updates the order metadata, not including the Host.
+func (mdb *MemoryDB) UpdateOrderMetaData(oid order.OrderID, md *db.OrderMetaData) error {
+	return mdb.updateOrder(oid, func(mo *metaOrder) {
+		host := mo.md.Host
+		mo.md = *md
+		mo.md.Host = host
+		mo.stamp = timeNow()
+	})
+}
+
+// UpdateOrderStatus sets the order status for an order.
+func (mdb *MemoryDB) UpdateOrderStatus(oid order.OrderID, status order.OrderStatus) error {
+	return mdb.updateOrder(oid, func(mo *metaOrder) {
+		mo.md.Status = status
+	})
+}
+
+// LinkOrder sets the linked order.
+func (mdb *MemoryDB) LinkOrder(oid, linkedID order.OrderID) error {
+	return mdb.updateOrder(oid, func(mo *metaOrder) {
+		mo.md.LinkedOrder = linkedID
+	})
+}
+
+// UpdateMatch updates the match information in the database. Any existing
+// entry for the same match ID will be overwritten without indication.
+func (mdb *MemoryDB) UpdateMatch(m *db.MetaMatch) error {
+	md := m.MetaData
+	if md.Quote == md.Base {
+		return fmt.Errorf("quote and base asset cannot be the same")
+	}
+	if md.DEX == "" {
+		return fmt.Errorf("empty DEX not allowed")
+	}
+	mdb.mtx.Lock()
+	mdb.matches[string(m.MatchOrderUniqueID())] = &metaMatch{
+		match:  order.EncodeMatch(m.UserMatch),
+		md:     *md,
+		active: db.MatchIsActive(m.UserMatch, &md.Proof),
+	}
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// metaMatch decodes the match. A nil match is returned if it is a cancel
+// match and excludeCancels is true.
+func (mm *metaMatch) metaMatch(excludeCancels bool) (*db.MetaMatch, error) {
+	match, _, err := order.DecodeMatch(mm.match)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding match: %w", err)
+	}
+	// A cancel match for a maker (trade) order has an empty address, and a
+	// cancel match for a taker (the cancel) order is complete with no
+	// InitSig.
+	if excludeCancels && (match.Address == "" ||
+		(len(mm.md.Proof.Auth.InitSig) == 0 && match.Status == order.MatchComplete)) {
+		return nil, nil
+	}
+	md := mm.md
+	return &db.MetaMatch{
+		MetaData:  &md,
+		UserMatch: match,
+	}, nil
+}
+
+// filteredMatches gets all matches that pass the provided filter function.
+func (mdb *MemoryDB) filteredMatches(filter func(*metaMatch) bool, excludeCancels bool) ([]*db.MetaMatch, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	var matches []*db.MetaMatch
+	for k, mm := range mdb.matches {
+		if !filter(mm) {
+			continue
+		}
+		match, err := mm.metaMatch(excludeCancels)
+		if err != nil {
+			return nil, fmt.Errorf("loading match %x: %w", k, err)
+		}
+		if match != nil {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+// ActiveMatches retrieves the matches that are in an active state, which is
+// any match that is still active.
+func (mdb *MemoryDB) ActiveMatches() ([]*db.MetaMatch, error) {
+	return mdb.filteredMatches(func(mm *metaMatch) bool {
+		return mm.active
+	}, true)
+}
+
+// DEXOrdersWithActiveMatches retrieves order IDs for any order that has active
+// matches, regardless of whether the order itself is in an active state.
+func (mdb *MemoryDB) DEXOrdersWithActiveMatches(dex string) ([]order.OrderID, error) {
+	matches, err := mdb.filteredMatches(func(mm *metaMatch) bool {
+		return mm.active && mm.md.DEX == dex
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	idMap := make(map[order.OrderID]bool, len(matches))
+	ids := make([]order.OrderID, 0, len(matches))
+	for _, m := range matches {
+		if !idMap[m.OrderID] {
+			idMap[m.OrderID] = true
+			ids = append(ids, m.OrderID)
+		}
+	}
+	return ids, nil
+}
+
+// MatchesForOrder retrieves the matches for the specified order ID.
+func (mdb *MemoryDB) MatchesForOrder(oid order.OrderID, excludeCancels bool) ([]*db.MetaMatch, error) {
+	matches, err := mdb.filteredMatches(func(*metaMatch) bool { return true }, excludeCancels)
+	if err != nil {
+		return nil, err
+	}
+	orderMatches := make([]*db.MetaMatch, 0, len(matches))
+	for _, m := range matches {
+		if m.OrderID == oid {
+			orderMatches = append(orderMatches, m)
+		}
+	}
+	return orderMatches, nil
+}
+
+func (w *wallet) dbWallet() (*db.Wallet, error) {
+	dbWallet, err := db.DecodeWallet(w.wallet)
+	if err != nil {
+		return nil, fmt.Errorf("DecodeWallet error: %w", err)
+	}
+	if w.balance != nil {
+		if dbWallet.Balance, err = db.DecodeBalance(w.balance); err != nil {
+			return nil, fmt.Errorf("DecodeBalance error: %w", err)
+		}
+	}
+	dbWallet.Disabled = w.disabled
+	return dbWallet, nil
+}
+
+// UpdateWallet adds a wallet to the database, or updates the wallet if it
+// already exists.
+func (mdb *MemoryDB) UpdateWallet(dbWallet *db.Wallet) error {
+	if dbWallet.Balance == nil {
+		return fmt.Errorf("cannot UpdateWallet with nil Balance field")
+	}
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	wid := string(dbWallet.ID())
+	w, found := mdb.wallets[wid]
+	if !found {
+		w = new(wallet)
+		mdb.wallets[wid] = w
+	}
+	w.wallet = dbWallet.Encode()
+	w.balance = dbWallet.Balance.Encode()
+	return nil
+}
+
+// updateWallet applies the update to the stored wallet.
+func (mdb *MemoryDB) updateWallet(wid []byte, update func(*wallet) error) error {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	w, found := mdb.wallets[string(wid)]
+	if !found {
+		return fmt.Errorf("wallet with ID %x not known", wid)
+	}
+	return update(w)
+}
+
+// SetWalletPassword sets the encrypted password field for the wallet.
+func (mdb *MemoryDB) SetWalletPassword(wid []byte, newEncPW []byte) error {
+	return mdb.updateWallet(wid, func(w *wallet) error {
+		dbWallet, err := db.DecodeWallet(w.wallet)
+		if err != nil {
+			return err
+		}
+		dbWallet.EncryptedPW = bytes.Clone(newEncPW)
+		w.wallet = dbWallet.Encode()
+		return nil
+	})
+}
+
+// UpdateBalance updates a wallet's balance.
+func (mdb *MemoryDB) UpdateBalance(wid []byte, bal *db.Balance) error {
+	return mdb.updateWallet(wid, func(w *wallet) error {
+		w.balance = bal.Encode()
+		return nil
+	})
+}
+
+// UpdateWalletStatus updates a wallet's status.
+func (mdb *MemoryDB) UpdateWalletStatus(wid []byte, disable bool) error {
+	return mdb.updateWallet(wid, func(w *wallet) error {
+		w.disabled = disable
+		return nil
+	})
+}
+
+// Wallets lists all saved wallets, ordered by wallet ID.
+func (mdb *MemoryDB) Wallets() ([]*db.Wallet, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	wids := make([]string, 0, len(mdb.wallets))
+	for wid := range mdb.wallets {
+		wids = append(wids, wid)
+	}
+	sort.Strings(wids)
+	wallets := make([]*db.Wallet, 0, len(wids))
+	for _, wid := range wids {
+		dbWallet, err := mdb.wallets[wid].dbWallet()
+		if err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, dbWallet)
+	}
+	return wallets, nil
+}
+
+// Wallet fetches the wallet for the specified wallet ID.
+func (mdb *MemoryDB) Wallet(wid []byte) (*db.Wallet, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	w, found := mdb.wallets[string(wid)]
+	if !found {
+		return nil, fmt.Errorf("wallet with ID %x not known", wid)
+	}
+	return w.dbWallet()
+}
+
+// Backup is not supported by the in-memory database.
+func (mdb *MemoryDB) Backup() error {
+	return ErrNoBackup
+}
+
+// BackupTo is not supported by the in-memory database.
+func (mdb *MemoryDB) BackupTo(string, bool, bool) error {
+	return ErrNoBackup
+}
+
+// Snapshots returns no snapshots, since the in-memory database is never
+// written to disk.
+func (mdb *MemoryDB) Snapshots() ([]*db.Snapshot, error) {
+	return nil, nil
+}
+
+// RestoreSnapshot is not supported by the in-memory database.
+func (mdb *MemoryDB) RestoreSnapshot(string) error {
+	return ErrNoBackup
+}
+
+// SaveNotification saves the notification.
+func (mdb *MemoryDB) SaveNotification(n *db.Notification) error {
+	if n.Severeness < db.Success {
+		return fmt.Errorf("storage of notification with severity %s is forbidden", n.Severeness)
+	}
+	mdb.mtx.Lock()
+	mdb.notes[string(n.ID())] = &note{
+		note:  n.Encode(),
+		stamp: n.TimeStamp,
+	}
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// AckNotification sets the acknowledgement for a notification.
+func (mdb *MemoryDB) AckNotification(id []byte) error {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	n, found := mdb.notes[string(id)]
+	if !found {
+		return fmt.Errorf("notification not found")
+	}
+	n.ack = true
+	return nil
+}
+
+// NotificationsN reads out the N most recent notifications.
+func (mdb *MemoryDB) NotificationsN(n int) ([]*db.Notification, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	ids := make([]string, 0, len(mdb.notes))
+	for id := range mdb.notes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		t1, t2 := mdb.notes[ids[i]].stamp, mdb.notes[ids[j]].stamp
+		return t1 > t2 || (t1 == t2 && ids[i] > ids[j])
+	})
+	if n > 0 && len(ids) > n {
+		ids = ids[:n]
+	}
+	notes := make([]*db.Notification, 0, len(ids))
+	for _, id := range ids {
+		stored := mdb.notes[id]
+		dbNote, err := db.DecodeNotification(stored.note)
+		if err != nil {
+			return nil, err
+		}
+		dbNote.Ack = stored.ack
+		dbNote.Id = dbNote.ID()
+		notes = append(notes, dbNote)
+	}
+	return notes, nil
+}
+
+// SavePokes saves a slice of notifications, overwriting any previously saved
+// slice.
+func (mdb *MemoryDB) SavePokes(pokes []*db.Notification) error {
+	b, err := json.Marshal(pokes)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	mdb.mtx.Lock()
+	mdb.pokes = b
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// LoadPokes loads the slice of notifications last saved with SavePokes. The
+// loaded pokes are deleted from the database.
+func (mdb *MemoryDB) LoadPokes() (pokes []*db.Notification, _ error) {
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+	if len(mdb.pokes) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(mdb.pokes, &pokes); err != nil {
+		return nil, err
+	}
+	mdb.pokes = nil
+	return pokes, nil
+}
+
+// olderThanStamp is the unix ms time stamp for the optional olderThan time of
+// the DeleteInactive methods.
+func olderThanStamp(olderThan *time.Time) uint64 {
+	if olderThan != nil && !olderThan.IsZero() {
+		return uint64(olderThan.UnixMilli())
+	}
+	return timeNow()
+}
+
+// DeleteInactiveOrders deletes inactive orders older than the supplied time,
+// or the current time if none is supplied, that have no active matches.
+// Accepts an optional function to perform on deleted orders.
+func (mdb *MemoryDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time,
+	perOrderFn func(ord *db.MetaOrder) error) (int, error) {
+
+	stamp := olderThanStamp(olderThan)
+
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+
+	activeMatchOrders := make(map[order.OrderID]bool)
+	for _, mm := range mdb.matches {
+		if !mm.active {
+			continue
+		}
+		match, _, err := order.DecodeMatch(mm.match)
+		if err != nil {
+			return 0, fmt.Errorf("unable to get active matches: %v", err)
+		}
+		activeMatchOrders[match.OrderID] = true
+	}
+
+	var nDeleted int
+	for oid, mo := range mdb.orders {
+		if err := ctx.Err(); err != nil {
+			return nDeleted, err
+		}
+		if mo.md.Status.IsActive() || activeMatchOrders[oid] || mo.stamp > stamp {
+			continue
+		}
+		if perOrderFn != nil {
+			ord, err := mo.metaOrder()
+			if err != nil {
+				return nDeleted, fmt.Errorf("failed to decode order: %v", err)
+			}
+			if err := perOrderFn(ord); err != nil {
+				return nDeleted, fmt.Errorf("problem performing batch function: %v", err)
+			}
+		}
+		delete(mdb.orders, oid)
+		nDeleted++
+	}
+
+	mdb.log.Infof("Deleted %d archived orders from the database", nDeleted)
+	return nDeleted, nil
+}
+
+// DeleteInactiveMatches deletes inactive matches older than the supplied time,
+// or the current time if none is supplied, that are not for active orders.
+// Accepts an optional function to perform on deleted matches.
+func (mdb *MemoryDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Time,
+	perMatchFn func(match *db.MetaMatch, isSell bool) error) (int, error) {
+
+	stamp := olderThanStamp(olderThan)
+
+	mdb.mtx.Lock()
+	defer mdb.mtx.Unlock()
+
+	var nDeleted int
+	for k, mm := range mdb.matches {
+		if err := ctx.Err(); err != nil {
+			return nDeleted, err
+		}
+		if mm.active || mm.md.Stamp > stamp {
+			continue
+		}
+		m, err := mm.metaMatch(false)
+		if err != nil {
+			return nDeleted, fmt.Errorf("failed to load match: %v", err)
+		}
+		mo, found := mdb.orders[m.OrderID]
+		if found && mo.md.Status.IsActive() {
+			continue
+		}
+		if perMatchFn != nil {
+			if !found {
+				return nDeleted, fmt.Errorf("order %s not found", m.OrderID)
+			}
+			ord, err := order.DecodeOrder(mo.ord)
+			if err != nil {
+				return nDeleted, fmt.Errorf("error decoding order %s: %w", m.OrderID, err)
+			}
+			// Cancel orders have no side.
+			isSell := ord.Type() != order.CancelOrderType && ord.Trade().Sell
+			if err := perMatchFn(m, isSell); err != nil {
+				return nDeleted, fmt.Errorf("problem performing batch function: %v", err)
+			}
+		}
+		delete(mdb.matches, k)
+		nDeleted++
+	}
+
+	mdb.log.Infof("Deleted %d archived matches from the database", nDeleted)
+	return nDeleted, nil
+}
+
+// SaveDisabledRateSources saves the disabled fiat rate sources.
+func (mdb *MemoryDB) SaveDisabledRateSources(disabledSources []string) error {
+	mdb.mtx.Lock()
+	mdb.disabledSources = append([]string(nil), disabledSources...)
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// DisabledRateSources retrieves the disabled fiat rate sources.
+func (mdb *MemoryDB) DisabledRateSources() ([]string, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	return append([]string(nil), mdb.disabledSources...), nil
+}
+
+// SetLanguage stores the language.
+func (mdb *MemoryDB) SetLanguage(lang string) error {
+	mdb.mtx.Lock()
+	mdb.lang = lang
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// Language retrieves the language stored with SetLanguage. If no language
+// has been stored, an empty string is returned without an error.
+func (mdb *MemoryDB) Language() (string, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	return mdb.lang, nil
+}
+
+// SetAddressBook saves the address book for an asset, overwriting any existing
+// address book for the asset.
+func (mdb *MemoryDB) SetAddressBook(book *db.AddressBook) error {
+	mdb.mtx.Lock()
+	mdb.addressBooks[book.AssetID] = book.Encode()
+	mdb.mtx.Unlock()
+	return nil
+}
+
+// AddressBook retrieves the address book for an asset. If no address book has
+// been stored, an empty, unrestricted *AddressBook is returned.
+func (mdb *MemoryDB) AddressBook(assetID uint32) (*db.AddressBook, error) {
+	mdb.mtx.RLock()
+	defer mdb.mtx.RUnlock()
+	bookB, found := mdb.addressBooks[assetID]
+	if !found {
+		return &db.AddressBook{AssetID: assetID}, nil
+	}
+	return db.DecodeAddressBook(bookB)
+}
@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"decred.org/dcrdex/client/db"
+	dbtest "decred.org/dcrdex/client/db/test"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+	ordertest "decred.org/dcrdex/dex/order/test"
+)
+
+var tLogger = dex.StdOutLogger("db_TEST", dex.LevelTrace)
+
+func TestPrimaryCredentials(t *testing.T) {
+	mdb := NewDB(tLogger)
+
+	if _, err := mdb.PrimaryCredentials(); !errors.Is(err, db.ErrNoCredentials) {
+		t.Fatalf("wrong error for missing credentials: %v", err)
+	}
+	if _, err := mdb.SeedGenerationTime(); !errors.Is(err, db.ErrNoSeedGenTime) {
+		t.Fatalf("wrong error for missing seed generation time: %v", err)
+	}
+
+	if err := mdb.SetPrimaryCredentials(&db.PrimaryCredentials{}); err == nil {
+		t.Fatalf("no error for empty credentials")
+	}
+
+	creds := dbtest.RandomPrimaryCredentials()
+	if err := mdb.SetPrimaryCredentials(creds); err != nil {
+		t.Fatalf("SetPrimaryCredentials error: %v", err)
+	}
+	reCreds, err := mdb.PrimaryCredentials()
+	if err != nil {
+		t.Fatalf("PrimaryCredentials error: %v", err)
+	}
+	if string(reCreds.EncSeed) != string(creds.EncSeed) || reCreds.Birthday.Unix() != creds.Birthday.Unix() {
+		t.Fatalf("wrong credentials retrieved")
+	}
+	// The stored credentials must not share memory with the caller's.
+	creds.EncSeed[0]++
+	if reCreds.EncSeed[0] == creds.EncSeed[0] {
+		t.Fatalf("stored credentials modified by caller")
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	mdb := NewDB(tLogger)
+
+	acct := dbtest.RandomAccountInfo()
+	if err := mdb.CreateAccount(acct); err != nil {
+		t.Fatalf("CreateAccount error: %v", err)
+	}
+	if err := mdb.CreateAccount(acct); err == nil {
+		t.Fatalf("no error for duplicate account")
+	}
+	if _, err := mdb.Account("nope"); !errors.Is(err, db.ErrAcctNotFound) {
+		t.Fatalf("wrong error for unknown account: %v", err)
+	}
+	reAcct, err := mdb.Account(acct.Host)
+	if err != nil {
+		t.Fatalf("Account error: %v", err)
+	}
+	dbtest.MustCompareAccountInfo(t, acct, reAcct)
+
+	if err := mdb.ToggleAccountStatus(acct.Host, true); err != nil {
+		t.Fatalf("ToggleAccountStatus error: %v", err)
+	}
+	if err := mdb.ToggleAccountStatus(acct.Host, true); err == nil {
+		t.Fatalf("no error for disabling a disabled account")
+	}
+	hosts, err := mdb.ListAccounts()
+	if err != nil {
+		t.Fatalf("ListAccounts error: %v", err)
+	}
+	if len(hosts) != 0 {
+		t.Fatalf("disabled account listed")
+	}
+	accts, err := mdb.Accounts()
+	if err != nil {
+		t.Fatalf("Accounts error: %v", err)
+	}
+	if len(accts) != 1 || !accts[0].Disabled {
+		t.Fatalf("disabled account not returned by Accounts")
+	}
+}
+
+func TestOrders(t *testing.T) {
+	mdb := NewDB(tLogger)
+
+	acct := dbtest.RandomAccountInfo()
+	newMetaOrder := func(ord order.Order, status order.OrderStatus) *db.MetaOrder {
+		return &db.MetaOrder{
+			MetaData: &db.OrderMetaData{
+				Status: status,
+				Host:   acct.Host,
+				Proof:  db.OrderProof{DEXSig: []byte{0x01}},
+			},
+			Order: ord,
+		}
+	}
+
+	lo, _ := ordertest.RandomLimitOrder()
+	co, _ := ordertest.RandomCancelOrder()
+	co.BaseAsset, co.QuoteAsset = lo.BaseAsset, lo.QuoteAsset
+	booked := newMetaOrder(lo, order.OrderStatusBooked)
+	cancel := newMetaOrder(co, order.OrderStatusExecuted)
+	for _, mo := range []*db.MetaOrder{booked, cancel} {
+		if err := mdb.UpdateOrder(mo); err != nil {
+			t.Fatalf("UpdateOrder error: %v", err)
+		}
+	}
+
+	active, err := mdb.ActiveOrders()
+	if err != nil {
+		t.Fatalf("ActiveOrders error: %v", err)
+	}
+	if len(active) != 1 || active[0].Order.ID() != lo.ID() {
+		t.Fatalf("wrong active orders")
+	}
+
+	mktOrders, err := mdb.MarketOrders(acct.Host, lo.BaseAsset, lo.QuoteAsset, 0, 0)
+	if err != nil {
+		t.Fatalf("MarketOrders error: %v", err)
+	}
+	if len(mktOrders) != 2 {
+		t.Fatalf("expected 2 market orders, got %d", len(mktOrders))
+	}
+
+	// Orders excludes cancel orders.
+	ords, err := mdb.Orders(&db.OrderFilter{})
+	if err != nil {
+		t.Fatalf("Orders error: %v", err)
+	}
+	if len(ords) != 1 || ords[0].Order.ID() != lo.ID() {
+		t.Fatalf("wrong filtered orders")
+	}
+
+	if err := mdb.UpdateOrderStatus(lo.ID(), order.OrderStatusCanceled); err != nil {
+		t.Fatalf("UpdateOrderStatus error: %v", err)
+	}
+	n, err := mdb.DeleteInactiveOrders(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("DeleteInactiveOrders error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 deleted orders, got %d", n)
+	}
+}
+
+func TestWallets(t *testing.T) {
+	mdb := NewDB(tLogger)
+
+	w := dbtest.RandomWallet()
+	if err := mdb.UpdateWallet(w); err != nil {
+		t.Fatalf("UpdateWallet error: %v", err)
+	}
+	if err := mdb.UpdateWalletStatus(w.ID(), true); err != nil {
+		t.Fatalf("UpdateWalletStatus error: %v", err)
+	}
+	reW, err := mdb.Wallet(w.ID())
+	if err != nil {
+		t.Fatalf("Wallet error: %v", err)
+	}
+	if !reW.Disabled {
+		t.Fatalf("wallet not disabled")
+	}
+	w.Disabled = true
+	dbtest.MustCompareWallets(t, w, reW)
+
+	if err := mdb.Backup(); !errors.Is(err, ErrNoBackup) {
+		t.Fatalf("wrong Backup error: %v", err)
+	}
+}
@@ -292,6 +292,53 @@ func MustCompareOrderProof(t testKiller, p1, p2 *db.OrderProof) {
 	}
 }
 
+// RandomOrderFeeTx creates an OrderFeeTx with random values.
+func RandomOrderFeeTx() *db.OrderFeeTx {
+	feeTx := &db.OrderFeeTx{
+		Type:   db.FeeTxType(rand.Intn(3) + 1),
+		CoinID: randBytes(36),
+		Fees:   rand.Uint64(),
+		Stamp:  rand.Uint64(),
+	}
+	if feeTx.Type != db.FeeTxFunding {
+		for i := rand.Intn(3) + 1; i > 0; i-- {
+			feeTx.Matches = append(feeTx.Matches, ordertest.RandomMatchID())
+		}
+	}
+	return feeTx
+}
+
+// MustCompareOrderFeeTxs ensures the two OrderFeeTx slices are identical,
+// calling the Fatalf method of the testKiller if not.
+func MustCompareOrderFeeTxs(t testKiller, txs1, txs2 []*db.OrderFeeTx) {
+	if len(txs1) != len(txs2) {
+		t.Fatalf("OrderFeeTx count mismatch. %d != %d", len(txs1), len(txs2))
+	}
+	for i, f1 := range txs1 {
+		f2 := txs2[i]
+		if f1.Type != f2.Type {
+			t.Fatalf("Type mismatch. %s != %s", f1.Type, f2.Type)
+		}
+		if !bytes.Equal(f1.CoinID, f2.CoinID) {
+			t.Fatalf("CoinID mismatch. %x != %x", f1.CoinID, f2.CoinID)
+		}
+		if f1.Fees != f2.Fees {
+			t.Fatalf("Fees mismatch. %d != %d", f1.Fees, f2.Fees)
+		}
+		if f1.Stamp != f2.Stamp {
+			t.Fatalf("Stamp mismatch. %d != %d", f1.Stamp, f2.Stamp)
+		}
+		if len(f1.Matches) != len(f2.Matches) {
+			t.Fatalf("Matches count mismatch. %d != %d", len(f1.Matches), len(f2.Matches))
+		}
+		for j, mid := range f1.Matches {
+			if mid != f2.Matches[j] {
+				t.Fatalf("Match ID mismatch. %s != %s", mid, f2.Matches[j])
+			}
+		}
+	}
+}
+
 // MustCompareWallets ensures the two Wallet are identical, calling the Fatalf
 // method of the testKiller if not.
 func MustCompareWallets(t testKiller, w1, w2 *db.Wallet) {
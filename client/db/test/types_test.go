@@ -77,6 +77,25 @@ func TestOrderProof(t *testing.T) {
 	t.Logf("encoded, decoded, and compared %d OrderProof in %d ms", spins, time.Since(tStart)/time.Millisecond)
 }
 
+func TestOrderFeeTx(t *testing.T) {
+	spins := 10000
+	if testing.Short() {
+		spins = 1000
+	}
+	feeTxs := make([]*db.OrderFeeTx, 0, spins)
+	nTimes(spins, func(int) { feeTxs = append(feeTxs, RandomOrderFeeTx()) })
+	tStart := time.Now()
+	nTimes(spins, func(i int) {
+		feeTx := feeTxs[i]
+		reFeeTx, err := db.DecodeOrderFeeTx(feeTx.Encode())
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		MustCompareOrderFeeTxs(t, []*db.OrderFeeTx{feeTx}, []*db.OrderFeeTx{reFeeTx})
+	})
+	t.Logf("encoded, decoded, and compared %d OrderFeeTx in %d ms", spins, time.Since(tStart)/time.Millisecond)
+}
+
 func nTimes(n int, f func(int)) {
 	for i := 0; i < n; i++ {
 		f(i)
@@ -479,6 +479,19 @@ type OrderMetaData struct {
 	// AccelerationCoins keeps track of all the change coins generated from doing
 	// accelerations on this order.
 	AccelerationCoins []order.CoinID
+	// FeeTxs are the transactions that the order paid on-chain fees for, with
+	// the fees attributed to each. The fees of each FeeTxType sum to
+	// FundingFeesPaid, SwapFeesPaid, and RedemptionFeesPaid, although orders
+	// stored before FeeTxs was added will have none.
+	FeeTxs []*OrderFeeTx
+	// TTL is the number of seconds after the server stamps a standing limit
+	// order that the order is canceled, if it is still booked. Zero means the
+	// order does not expire.
+	TTL uint64
+	// MaxDrift is the difference, in percent, between a standing limit order's
+	// rate and the market's spot rate at which the order is canceled. Zero
+	// means there is no limit.
+	MaxDrift float64
 }
 
 // MetaMatch is a match and its metadata.
@@ -783,6 +796,95 @@ func decodeOrderProof_v0(pushes [][]byte) (*OrderProof, error) {
 	}, nil
 }
 
+// FeeTxType is the purpose of a transaction that an order paid fees for.
+type FeeTxType uint8
+
+const (
+	FeeTxFunding FeeTxType = iota + 1
+	FeeTxSwap
+	FeeTxRedeem
+)
+
+// String returns the name of the FeeTxType.
+func (t FeeTxType) String() string {
+	switch t {
+	case FeeTxFunding:
+		return "funding"
+	case FeeTxSwap:
+		return "swap"
+	case FeeTxRedeem:
+		return "redemption"
+	}
+	return "unknown"
+}
+
+// OrderFeeTx is a transaction that an order paid on-chain fees for. Funding
+// and swap fees are paid in the fee asset of the order's "from" asset, and
+// redemption fees in that of the "to" asset.
+type OrderFeeTx struct {
+	Type FeeTxType
+	// CoinID identifies the transaction. This is the split output for a
+	// funding tx, the order's swap contract for a swap, and the redemption
+	// coin for a redeem.
+	CoinID order.CoinID
+	Fees   uint64
+	// Matches are the order's matches that were swapped or redeemed by the
+	// transaction. Empty for a funding tx.
+	Matches []order.MatchID
+	// Stamp is when the fees were recorded, in unix ms.
+	Stamp uint64
+}
+
+// Encode serializes the OrderFeeTx.
+func (f *OrderFeeTx) Encode() []byte {
+	matchesB := make([]byte, 0, len(f.Matches)*order.MatchIDSize)
+	for _, mid := range f.Matches {
+		matchesB = append(matchesB, mid[:]...)
+	}
+	return versionedBytes(0).
+		AddData([]byte{byte(f.Type)}).
+		AddData(f.CoinID).
+		AddData(uint64Bytes(f.Fees)).
+		AddData(matchesB).
+		AddData(uint64Bytes(f.Stamp))
+}
+
+// DecodeOrderFeeTx decodes the versioned blob to an *OrderFeeTx.
+func DecodeOrderFeeTx(b []byte) (*OrderFeeTx, error) {
+	ver, pushes, err := encode.DecodeBlob(b)
+	if err != nil {
+		return nil, err
+	}
+	switch ver {
+	case 0:
+		return decodeOrderFeeTx_v0(pushes)
+	}
+	return nil, fmt.Errorf("unknown OrderFeeTx version %d", ver)
+}
+
+func decodeOrderFeeTx_v0(pushes [][]byte) (*OrderFeeTx, error) {
+	if len(pushes) != 5 {
+		return nil, fmt.Errorf("decodeOrderFeeTx_v0: expected 5 pushes, got %d", len(pushes))
+	}
+	typeB, coinID, feesB, matchesB, stampB := pushes[0], pushes[1], pushes[2], pushes[3], pushes[4]
+	if len(typeB) != 1 || len(feesB) != 8 || len(stampB) != 8 || len(matchesB)%order.MatchIDSize != 0 {
+		return nil, fmt.Errorf("decodeOrderFeeTx_v0: invalid data")
+	}
+	matches := make([]order.MatchID, 0, len(matchesB)/order.MatchIDSize)
+	for i := 0; i < len(matchesB); i += order.MatchIDSize {
+		var mid order.MatchID
+		copy(mid[:], matchesB[i:])
+		matches = append(matches, mid)
+	}
+	return &OrderFeeTx{
+		Type:    FeeTxType(typeB[0]),
+		CoinID:  coinID,
+		Fees:    intCoder.Uint64(feesB),
+		Matches: matches,
+		Stamp:   intCoder.Uint64(stampB),
+	}, nil
+}
+
 // encodeAssetBalance serializes an asset.Balance.
 func encodeAssetBalance(bal *asset.Balance) []byte {
 	return versionedBytes(0).
@@ -931,6 +1033,51 @@ func (w *Wallet) SID() string {
 	return strconv.Itoa(int(w.AssetID))
 }
 
+// AddressBook is an asset's list of labeled withdrawal addresses. The entries
+// are encrypted by the caller with the app's encryption key. If Restricted is
+// true, sends and withdraws are only permitted to addresses in the book.
+type AddressBook struct {
+	AssetID    uint32
+	EncEntries []byte
+	Restricted bool
+}
+
+// Encode encodes the AddressBook to a versioned blob.
+func (ab *AddressBook) Encode() []byte {
+	return versionedBytes(0).
+		AddData(uint32Bytes(ab.AssetID)).
+		AddData(ab.EncEntries).
+		AddData(boolByte(ab.Restricted))
+}
+
+// DecodeAddressBook decodes the versioned blob to an *AddressBook.
+func DecodeAddressBook(b []byte) (*AddressBook, error) {
+	ver, pushes, err := encode.DecodeBlob(b)
+	if err != nil {
+		return nil, err
+	}
+	switch ver {
+	case 0:
+		return decodeAddressBook_v0(pushes)
+	}
+	return nil, fmt.Errorf("unknown AddressBook version %d", ver)
+}
+
+func decodeAddressBook_v0(pushes [][]byte) (*AddressBook, error) {
+	if len(pushes) != 3 {
+		return nil, fmt.Errorf("decodeAddressBook_v0: expected 3 pushes, got %d", len(pushes))
+	}
+	idB, entriesB, restrictedB := pushes[0], pushes[1], pushes[2]
+	if len(idB) != 4 {
+		return nil, fmt.Errorf("decodeAddressBook_v0: expected 4 bytes for asset ID, got %d", len(idB))
+	}
+	return &AddressBook{
+		AssetID:    intCoder.Uint32(idB),
+		EncEntries: entriesB,
+		Restricted: bytes.Equal(restrictedB, encode.ByteTrue),
+	}, nil
+}
+
 func versionedBytes(v byte) encode.BuildyBytes {
 	return encode.BuildyBytes{v}
 }
@@ -1218,6 +1365,28 @@ type OrderFilter struct {
 	// Statuses is a list of acceptable statuses. A zero-length Statuses means
 	// all statuses are accepted.
 	Statuses []order.OrderStatus
+	// Sell limits results to sell orders if true, or buy orders if false. A
+	// nil Sell means both sides are accepted.
+	Sell *bool
+	// Since and Until limit results to orders last updated within the time
+	// range, in milliseconds. Zero values leave the range open.
+	Since uint64
+	Until uint64
+}
+
+// Snapshot describes a copy of the database made periodically while the
+// database is running.
+type Snapshot struct {
+	// Name identifies the snapshot for RestoreSnapshot.
+	Name string `json:"name"`
+	// Stamp is the creation time in milliseconds.
+	Stamp uint64 `json:"stamp"`
+	Size  int64  `json:"size"`
+	// Checksum is the hex-encoded SHA-256 of the snapshot file recorded when
+	// the snapshot was created.
+	Checksum string `json:"checksum"`
+	// Valid is true if the snapshot file still matches the Checksum.
+	Valid bool `json:"valid"`
 }
 
 // noteKeySize must be <= 32.
@@ -35,6 +35,9 @@ type tBookFeed struct {
 func (t *tBookFeed) Next() <-chan *core.BookUpdate { return t.c }
 func (t *tBookFeed) Close()                        {}
 func (t *tBookFeed) Candles(dur string) error      { return nil }
+func (t *tBookFeed) Depth(binWidth uint64) (*orderbook.Depth, error) {
+	return &orderbook.Depth{BinWidth: binWidth}, nil
+}
 
 var _ core.BookFeed = (*tBookFeed)(nil)
 
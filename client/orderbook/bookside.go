@@ -29,7 +29,10 @@ type Fill struct {
 
 // bookSide represents a side of the order book.
 type bookSide struct {
-	bins      map[uint64][]*Order
+	bins map[uint64][]*Order
+	// qtys is the total quantity of the orders in each bin, kept up to date
+	// so depth data can be computed without visiting every order.
+	qtys      map[uint64]uint64
 	rateIndex *rateIndex
 	orderPref orderPreference
 	mtx       sync.RWMutex
@@ -39,6 +42,7 @@ type bookSide struct {
 func newBookSide(pref orderPreference) *bookSide {
 	return &bookSide{
 		bins:      make(map[uint64][]*Order),
+		qtys:      make(map[uint64]uint64),
 		rateIndex: newRateIndex(),
 		orderPref: pref,
 	}
@@ -48,6 +52,7 @@ func newBookSide(pref orderPreference) *bookSide {
 func (d *bookSide) reset() {
 	d.mtx.Lock()
 	d.bins = make(map[uint64][]*Order)
+	d.qtys = make(map[uint64]uint64)
 	d.rateIndex = newRateIndex()
 	d.mtx.Unlock()
 }
@@ -77,6 +82,7 @@ func (d *bookSide) Add(order *Order) {
 	copy(bin[i+1:], bin[i:])
 	bin[i] = order
 	d.bins[order.Rate] = bin
+	d.qtys[order.Rate] += order.Quantity
 
 	// Update the sort order if a new order group is created.
 	if !exists {
@@ -97,6 +103,7 @@ func (d *bookSide) Remove(oid order.OrderID, rateBin uint64) error {
 
 	for i := range bin {
 		if oid == bin[i].OrderID {
+			d.qtys[rateBin] -= bin[i].Quantity
 			// Remove the entry and preserve the sort order.
 			if i < len(bin)-1 {
 				copy(bin[i:], bin[i+1:])
@@ -107,6 +114,7 @@ func (d *bookSide) Remove(oid order.OrderID, rateBin uint64) error {
 			// Delete the bin if there are no orders left in it.
 			if len(bin) == 0 {
 				delete(d.bins, rateBin)
+				delete(d.qtys, rateBin)
 				return d.rateIndex.Remove(rateBin)
 			}
 
@@ -130,6 +138,7 @@ func (d *bookSide) UpdateRemaining(oid order.OrderID, rateBin, remaining uint64)
 			newOrder := *ord // deep copy
 			newOrder.Quantity = remaining
 			bin[i] = &newOrder
+			d.qtys[rateBin] = d.qtys[rateBin] - ord.Quantity + remaining
 			return
 		}
 	}
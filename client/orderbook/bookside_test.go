@@ -11,8 +11,15 @@ import (
 // makeBookSideDepth creates a new book side depth from the provided
 // group and sort order.
 func makeBookSide(groups map[uint64][]*Order, rateIndex *rateIndex, orderPref orderPreference) *bookSide {
+	qtys := make(map[uint64]uint64, len(groups))
+	for rate, bin := range groups {
+		for _, ord := range bin {
+			qtys[rate] += ord.Quantity
+		}
+	}
 	return &bookSide{
 		bins:      groups,
+		qtys:      qtys,
 		rateIndex: rateIndex,
 		orderPref: orderPref,
 	}
@@ -0,0 +1,108 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package orderbook
+
+import "fmt"
+
+// DepthBin is the booked quantity in a range of rates.
+type DepthBin struct {
+	// Rate is the edge of the bin furthest from the mid-gap, i.e. the worst
+	// rate of the orders in the bin. A buy bin covers the rates from Rate up to
+	// the next bin, and a sell bin covers the rates from the previous bin up to
+	// Rate.
+	Rate uint64 `json:"rate"`
+	// Qty is the quantity of the orders in the bin, in units of the base asset.
+	Qty uint64 `json:"qty"`
+	// CumQty is the quantity of the orders in the bin and in every bin closer
+	// to the mid-gap.
+	CumQty uint64 `json:"cumQty"`
+}
+
+// Depth is the book binned by rate, for depth charts. Rates and quantities
+// are in atomic units.
+type Depth struct {
+	BinWidth uint64 `json:"binWidth"`
+	// MidGap is zero if the book is empty. If one side of the book is empty,
+	// MidGap is the best rate of the other side, as from OrderBook.MidGap.
+	MidGap uint64 `json:"midGap"`
+	// Spread is the difference between the best sell and buy rates. Spread is
+	// zero if either side of the book is empty.
+	Spread uint64 `json:"spread"`
+	// Buys and Sells are sorted best bin first.
+	Buys  []*DepthBin `json:"buys"`
+	Sells []*DepthBin `json:"sells"`
+}
+
+// depth bins the side's quantities by rate. A binWidth of zero or one puts
+// each rate in its own bin. The best rate on the side is also returned, and is
+// zero if the side is empty.
+func (d *bookSide) depth(binWidth uint64) (bins []*DepthBin, best uint64) {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	binRate := func(rate uint64) uint64 {
+		if binWidth <= 1 {
+			return rate
+		}
+		if d.orderPref == ascending { // sells bin up, away from the mid-gap
+			return (rate + binWidth - 1) / binWidth * binWidth
+		}
+		return rate / binWidth * binWidth
+	}
+
+	bins = make([]*DepthBin, 0)
+	calcIdx := d.idxCalculator()
+	var cumQty uint64
+	for i := range d.rateIndex.Rates {
+		rate := d.rateIndex.Rates[calcIdx(i)]
+		if i == 0 {
+			best = rate
+		}
+		qty := d.qtys[rate]
+		cumQty += qty
+		r := binRate(rate)
+		// Rates are visited best first, so rates in the same bin are adjacent.
+		if n := len(bins); n > 0 && bins[n-1].Rate == r {
+			bins[n-1].Qty += qty
+			bins[n-1].CumQty = cumQty
+			continue
+		}
+		bins = append(bins, &DepthBin{
+			Rate:   r,
+			Qty:    qty,
+			CumQty: cumQty,
+		})
+	}
+	return bins, best
+}
+
+// Depth is the book's cumulative quantity per rate bin of width binWidth, with
+// the mid-gap and spread. The per-rate quantities are updated as book updates
+// arrive, so the cost of Depth scales with the number of rates on the book,
+// not the number of orders.
+func (ob *OrderBook) Depth(binWidth uint64) (*Depth, error) {
+	if !ob.isSynced() {
+		return nil, fmt.Errorf("order book is unsynced")
+	}
+
+	buys, bestBuy := ob.buys.depth(binWidth)
+	sells, bestSell := ob.sells.depth(binWidth)
+	depth := &Depth{
+		BinWidth: binWidth,
+		Buys:     buys,
+		Sells:    sells,
+	}
+	switch {
+	case len(buys) > 0 && len(sells) > 0:
+		depth.MidGap = (bestBuy + bestSell) / 2
+		if bestSell > bestBuy {
+			depth.Spread = bestSell - bestBuy
+		}
+	case len(buys) > 0:
+		depth.MidGap = bestBuy
+	case len(sells) > 0:
+		depth.MidGap = bestSell
+	}
+	return depth, nil
+}
@@ -0,0 +1,106 @@
+package orderbook
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
+)
+
+func TestOrderBookDepth(t *testing.T) {
+	mid := "abc_xyz"
+	book := makeOrderBook(
+		1,
+		mid,
+		[]*Order{
+			makeOrder(order.OrderID{'b', 0x01}, msgjson.BuyOrderNum, 10, 100, 1),
+			makeOrder(order.OrderID{'b', 0x02}, msgjson.BuyOrderNum, 5, 95, 2),
+			makeOrder(order.OrderID{'b', 0x03}, msgjson.BuyOrderNum, 3, 94, 3),
+			makeOrder(order.OrderID{'s', 0x01}, msgjson.SellOrderNum, 4, 110, 4),
+			makeOrder(order.OrderID{'s', 0x02}, msgjson.SellOrderNum, 6, 111, 5),
+			makeOrder(order.OrderID{'s', 0x03}, msgjson.SellOrderNum, 2, 125, 6),
+		},
+		make([]*cachedOrderNote, 0),
+		true,
+	)
+
+	checkBins := func(tag string, bins []*DepthBin, exp [][3]uint64) {
+		t.Helper()
+		if len(bins) != len(exp) {
+			t.Fatalf("%s: expected %d bins, got %d", tag, len(exp), len(bins))
+		}
+		for i, bin := range bins {
+			if bin.Rate != exp[i][0] || bin.Qty != exp[i][1] || bin.CumQty != exp[i][2] {
+				t.Fatalf("%s: bin %d: wanted %v, got %+v", tag, i, exp[i], bin)
+			}
+		}
+	}
+	checkDepth := func(tag string, binWidth, midGap, spread uint64, buys, sells [][3]uint64) {
+		t.Helper()
+		depth, err := book.Depth(binWidth)
+		if err != nil {
+			t.Fatalf("%s: Depth error: %v", tag, err)
+		}
+		if depth.MidGap != midGap {
+			t.Fatalf("%s: wrong mid-gap. wanted %d, got %d", tag, midGap, depth.MidGap)
+		}
+		if depth.Spread != spread {
+			t.Fatalf("%s: wrong spread. wanted %d, got %d", tag, spread, depth.Spread)
+		}
+		checkBins(tag+" buys", depth.Buys, buys)
+		checkBins(tag+" sells", depth.Sells, sells)
+	}
+
+	// Each rate in its own bin.
+	checkDepth("no binning", 0, 105, 10,
+		[][3]uint64{{100, 10, 10}, {95, 5, 15}, {94, 3, 18}},
+		[][3]uint64{{110, 4, 4}, {111, 6, 10}, {125, 2, 12}})
+
+	// Buys bin down and sells bin up.
+	checkDepth("binned", 10, 105, 10,
+		[][3]uint64{{100, 10, 10}, {90, 8, 18}},
+		[][3]uint64{{110, 4, 4}, {120, 6, 10}, {130, 2, 12}})
+
+	// Book updates are reflected.
+	oid := order.OrderID{'b', 0x02}
+	err := book.UpdateRemaining(&msgjson.UpdateRemainingNote{
+		OrderNote: msgjson.OrderNote{
+			Seq:      2,
+			MarketID: mid,
+			OrderID:  oid[:],
+		},
+		Remaining: 1,
+	})
+	if err != nil {
+		t.Fatalf("UpdateRemaining error: %v", err)
+	}
+	checkDepth("update remaining", 10, 105, 10,
+		[][3]uint64{{100, 10, 10}, {90, 4, 14}},
+		[][3]uint64{{110, 4, 4}, {120, 6, 10}, {130, 2, 12}})
+
+	if err := book.Unbook(makeUnbookOrderNote(3, mid, order.OrderID{'b', 0x01})); err != nil {
+		t.Fatalf("Unbook error: %v", err)
+	}
+	checkDepth("unbook", 10, 102, 15,
+		[][3]uint64{{90, 4, 4}},
+		[][3]uint64{{110, 4, 4}, {120, 6, 10}, {130, 2, 12}})
+
+	if err := book.Book(makeBookOrderNote(4, mid, order.OrderID{'s', 0x04}, msgjson.SellOrderNum, 7, 105, 7)); err != nil {
+		t.Fatalf("Book error: %v", err)
+	}
+	checkDepth("book", 10, 100, 10,
+		[][3]uint64{{90, 4, 4}},
+		[][3]uint64{{110, 11, 11}, {120, 6, 17}, {130, 2, 19}})
+
+	// An empty book has no mid-gap or spread.
+	if err := book.Reset(makeOrderBookMsg(5, mid, nil)); err != nil {
+		t.Fatalf("Reset error: %v", err)
+	}
+	checkDepth("empty", 10, 0, 0, nil, nil)
+
+	// Unsynced book.
+	book.setSynced(false)
+	if _, err := book.Depth(10); err == nil {
+		t.Fatalf("no error for unsynced book")
+	}
+}
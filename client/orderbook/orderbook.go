@@ -154,6 +154,14 @@ func (ob *OrderBook) setSeq(seq uint64) {
 	}
 }
 
+// Seq is the sequence number of the last book update applied to the order
+// book.
+func (ob *OrderBook) Seq() uint64 {
+	ob.seqMtx.Lock()
+	defer ob.seqMtx.Unlock()
+	return ob.seq
+}
+
 // cacheOrderNote caches an order note.
 func (ob *OrderBook) cacheOrderNote(route string, entry any) error {
 	note := new(cachedOrderNote)
@@ -13,6 +13,7 @@ import (
 
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
@@ -22,6 +23,9 @@ import (
 // routes
 const (
 	cancelRoute                = "cancel"
+	preAccelerateRoute         = "preaccelerate"
+	accelerationEstimateRoute  = "accelerationestimate"
+	accelerateOrderRoute       = "accelerateorder"
 	closeWalletRoute           = "closewallet"
 	discoverAcctRoute          = "discoveracct"
 	exchangesRoute             = "exchanges"
@@ -30,17 +34,24 @@ const (
 	loginRoute                 = "login"
 	logoutRoute                = "logout"
 	myOrdersRoute              = "myorders"
+	orderHistoryRoute          = "orderhistory"
 	newWalletRoute             = "newwallet"
 	openWalletRoute            = "openwallet"
 	toggleWalletStatusRoute    = "togglewalletstatus"
+	changeAppPassRoute         = "changeapppass"
+	setWalletPassRoute         = "setwalletpass"
 	orderBookRoute             = "orderbook"
 	getDEXConfRoute            = "getdexconfig"
 	bondAssetsRoute            = "bondassets"
 	postBondRoute              = "postbond"
 	bondOptionsRoute           = "bondopts"
 	tradeRoute                 = "trade"
+	preOrderRoute              = "preorder"
+	validateTradeRoute         = "validatetrade"
 	versionRoute               = "version"
 	walletsRoute               = "wallets"
+	portfolioRoute             = "portfolio"
+	lockedBalanceRoute         = "lockedbalance"
 	rescanWalletRoute          = "rescanwallet"
 	withdrawRoute              = "withdraw"
 	sendRoute                  = "send"
@@ -64,6 +75,14 @@ const (
 	txHistoryRoute             = "txhistory"
 	walletTxRoute              = "wallettx"
 	withdrawBchSpvRoute        = "withdrawbchspv"
+	addressBookRoute           = "addressbook"
+	addAddressRoute            = "addaddress"
+	removeAddressRoute         = "removeaddress"
+	restrictWithdrawalsRoute   = "restrictwithdrawals"
+	exportAccountRoute         = "exportaccount"
+	importAccountRoute         = "importaccount"
+	dbSnapshotsRoute           = "dbsnapshots"
+	restoreDBSnapshotRoute     = "restoredbsnapshot"
 )
 
 const (
@@ -74,8 +93,13 @@ const (
 	canceledOrderStr  = "canceled order %s"
 	logoutStr         = "goodbye"
 	walletStatusStr   = "%s wallet has been %s"
+	appPassChangedStr = "app password changed"
+	walletPassSetStr  = "%s wallet password updated"
 	setVotePrefsStr   = "vote preferences set"
 	setVSPStr         = "vsp set to %s"
+	acctExportedStr   = "%s account exported to %s"
+	acctImportedStr   = "%s account imported"
+	snapshotStagedStr = "snapshot %s will be restored on the next restart"
 )
 
 // createResponse creates a msgjson response payload.
@@ -99,6 +123,9 @@ func usage(route string, err error) *msgjson.ResponsePayload {
 // routes maps routes to a handler function.
 var routes = map[string]func(s *RPCServer, params *RawParams) *msgjson.ResponsePayload{
 	cancelRoute:                handleCancel,
+	preAccelerateRoute:         handlePreAccelerate,
+	accelerationEstimateRoute:  handleAccelerationEstimate,
+	accelerateOrderRoute:       handleAccelerateOrder,
 	closeWalletRoute:           handleCloseWallet,
 	discoverAcctRoute:          handleDiscoverAcct,
 	exchangesRoute:             handleExchanges,
@@ -107,17 +134,24 @@ var routes = map[string]func(s *RPCServer, params *RawParams) *msgjson.ResponseP
 	loginRoute:                 handleLogin,
 	logoutRoute:                handleLogout,
 	myOrdersRoute:              handleMyOrders,
+	orderHistoryRoute:          handleOrderHistory,
 	newWalletRoute:             handleNewWallet,
 	openWalletRoute:            handleOpenWallet,
 	toggleWalletStatusRoute:    handleToggleWalletStatus,
+	changeAppPassRoute:         handleChangeAppPass,
+	setWalletPassRoute:         handleSetWalletPass,
 	orderBookRoute:             handleOrderBook,
 	getDEXConfRoute:            handleGetDEXConfig,
 	postBondRoute:              handlePostBond,
 	bondOptionsRoute:           handleBondOptions,
 	bondAssetsRoute:            handleBondAssets,
 	tradeRoute:                 handleTrade,
+	preOrderRoute:              handlePreOrder,
+	validateTradeRoute:         handleValidateTrade,
 	versionRoute:               handleVersion,
 	walletsRoute:               handleWallets,
+	portfolioRoute:             handlePortfolio,
+	lockedBalanceRoute:         handleLockedBalance,
 	rescanWalletRoute:          handleRescanWallet,
 	withdrawRoute:              handleWithdraw,
 	sendRoute:                  handleSend,
@@ -141,6 +175,14 @@ var routes = map[string]func(s *RPCServer, params *RawParams) *msgjson.ResponseP
 	txHistoryRoute:             handleTxHistory,
 	walletTxRoute:              handleWalletTx,
 	withdrawBchSpvRoute:        handleWithdrawBchSpv,
+	addressBookRoute:           handleAddressBook,
+	addAddressRoute:            handleAddAddress,
+	removeAddressRoute:         handleRemoveAddress,
+	restrictWithdrawalsRoute:   handleRestrictWithdrawals,
+	exportAccountRoute:         handleExportAccount,
+	importAccountRoute:         handleImportAccount,
+	dbSnapshotsRoute:           handleDBSnapshots,
+	restoreDBSnapshotRoute:     handleRestoreDBSnapshot,
 }
 
 // handleHelp handles requests for help. Returns general help for all commands
@@ -316,6 +358,69 @@ func handleWallets(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
 	return createResponse(walletsRoute, walletsStates, nil)
 }
 
+// handleChangeAppPass handles requests for changeapppass.
+// *msgjson.ResponsePayload.Error is empty if successful. The app password is
+// changed in a single database update, so the old password is still valid if
+// the change fails.
+func handleChangeAppPass(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseChangeAppPassArgs(params)
+	if err != nil {
+		return usage(changeAppPassRoute, err)
+	}
+	defer form.appPass.Clear()
+	defer form.newAppPass.Clear()
+
+	if err := s.core.ChangeAppPass(form.appPass, form.newAppPass); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCChangeAppPassError, "unable to change app password: %v", err)
+		return createResponse(changeAppPassRoute, nil, resErr)
+	}
+	res := appPassChangedStr
+	return createResponse(changeAppPassRoute, &res, nil)
+}
+
+// handleSetWalletPass handles requests for setwalletpass.
+// *msgjson.ResponsePayload.Error is empty if successful. The new password must
+// already be set on the wallet itself. It is checked by unlocking the wallet
+// before it is saved.
+func handleSetWalletPass(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseSetWalletPassArgs(params)
+	if err != nil {
+		return usage(setWalletPassRoute, err)
+	}
+	defer form.appPass.Clear()
+	defer form.newWalletPass.Clear()
+
+	if err := s.core.SetWalletPassword(form.appPass, form.assetID, form.newWalletPass); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCSetWalletPassError, "unable to set %s wallet password: %v",
+			dex.BipIDSymbol(form.assetID), err)
+		return createResponse(setWalletPassRoute, nil, resErr)
+	}
+	res := fmt.Sprintf(walletPassSetStr, dex.BipIDSymbol(form.assetID))
+	return createResponse(setWalletPassRoute, &res, nil)
+}
+
+// handlePortfolio handles requests for portfolio. Returns the fiat value of
+// each wallet's balance and the total value.
+func handlePortfolio(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
+	return createResponse(portfolioRoute, s.core.PortfolioValue(), nil)
+}
+
+// handleLockedBalance handles requests for lockedbalance. Returns a breakdown
+// of a wallet's locked funds by the orders, matches, reserves, and bonds that
+// they are locked for.
+func handleLockedBalance(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	assetID, err := parseLockedBalanceArgs(params)
+	if err != nil {
+		return usage(lockedBalanceRoute, err)
+	}
+	lb, err := s.core.LockedBalance(assetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCLockedBalanceError, "unable to get locked balance: %v", err)
+		return createResponse(lockedBalanceRoute, nil, resErr)
+	}
+	return createResponse(lockedBalanceRoute, lb, nil)
+}
+
 // handleBondAssets handles requests for bondassets.
 // *msgjson.ResponsePayload.Error is empty if successful. Requires the address
 // of a dex and returns the bond expiry and supported asset bond details.
@@ -518,6 +623,33 @@ func handleTrade(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	return createResponse(tradeRoute, &tradeRes, nil)
 }
 
+// handlePreOrder handles requests for an estimate of a prospective order's
+// size and fees. *msgjson.ResponsePayload.Error is empty if successful.
+func handlePreOrder(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parsePreOrderArgs(params)
+	if err != nil {
+		return usage(preOrderRoute, err)
+	}
+	est, err := s.core.PreOrder(form)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCPreOrderError, "unable to estimate order: %v", err)
+		return createResponse(preOrderRoute, nil, resErr)
+	}
+	return createResponse(preOrderRoute, est, nil)
+}
+
+// handleValidateTrade handles requests to check a prospective order without
+// placing it. A failed validation is not a response error. The reasons are in
+// the result. *msgjson.ResponsePayload.Error is empty if successful.
+func handleValidateTrade(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	// The arguments are the same as preorder's.
+	form, err := parsePreOrderArgs(params)
+	if err != nil {
+		return usage(validateTradeRoute, err)
+	}
+	return createResponse(validateTradeRoute, s.core.ValidateTrade(form), nil)
+}
+
 func handleMultiTrade(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	form, err := parseMultiTradeArgs(params)
 	if err != nil {
@@ -558,6 +690,55 @@ func handleCancel(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	return createResponse(cancelRoute, &res, nil)
 }
 
+// handlePreAccelerate handles requests for the information needed to choose a
+// fee rate for accelerating an order's swaps. *msgjson.ResponsePayload.Error
+// is empty if successful.
+func handlePreAccelerate(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	oidB, err := parsePreAccelerateArgs(params)
+	if err != nil {
+		return usage(preAccelerateRoute, err)
+	}
+	pre, err := s.core.PreAccelerateOrder(oidB)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCPreAccelerateError, "unable to check acceleration of order %s: %v", oidB, err)
+		return createResponse(preAccelerateRoute, nil, resErr)
+	}
+	return createResponse(preAccelerateRoute, pre, nil)
+}
+
+// handleAccelerationEstimate handles requests for the fees required to
+// accelerate an order's swaps to a fee rate. *msgjson.ResponsePayload.Error is
+// empty if successful.
+func handleAccelerationEstimate(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAccelerationEstimateArgs(params)
+	if err != nil {
+		return usage(accelerationEstimateRoute, err)
+	}
+	fee, err := s.core.AccelerationEstimate(form.orderID, form.newFeeRate)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAccelerationEstimateError, "unable to estimate acceleration of order %s: %v", form.orderID, err)
+		return createResponse(accelerationEstimateRoute, nil, resErr)
+	}
+	return createResponse(accelerationEstimateRoute, fee, nil)
+}
+
+// handleAccelerateOrder handles requests to accelerate an order's swaps with a
+// child-pays-for-parent transaction. *msgjson.ResponsePayload.Error is empty
+// if successful.
+func handleAccelerateOrder(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAccelerateOrderArgs(params)
+	if err != nil {
+		return usage(accelerateOrderRoute, err)
+	}
+	defer form.appPass.Clear()
+	txID, err := s.core.AccelerateOrder(form.appPass, form.orderID, form.newFeeRate)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAccelerateOrderError, "unable to accelerate order %s: %v", form.orderID, err)
+		return createResponse(accelerateOrderRoute, nil, resErr)
+	}
+	return createResponse(accelerateOrderRoute, txID, nil)
+}
+
 // handleWithdraw handles requests for withdraw. *msgjson.ResponsePayload.Error
 // is empty if successful.
 func handleWithdraw(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
@@ -759,6 +940,31 @@ func handleMyOrders(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	return createResponse(myOrdersRoute, myOrders, nil)
 }
 
+// handleOrderHistory handles requests for the user's historical orders.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleOrderHistory(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	filter, err := parseOrderHistoryArgs(params)
+	if err != nil {
+		return usage(orderHistoryRoute, err)
+	}
+	ords, err := s.core.Orders(filter)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCOrderHistoryError, "unable to fetch orders: %v", err)
+		return createResponse(orderHistoryRoute, nil, resErr)
+	}
+	res := &orderHistoryResponse{
+		Orders: make(myOrdersResponse, 0, len(ords)),
+	}
+	for _, ord := range ords {
+		res.Orders = append(res.Orders, parseCoreOrder(ord, ord.BaseID, ord.QuoteID))
+	}
+	// A full page may be followed by more orders.
+	if len(ords) > 0 && len(ords) == filter.N {
+		res.Next = ords[len(ords)-1].ID.String()
+	}
+	return createResponse(orderHistoryRoute, res, nil)
+}
+
 // handleAppSeed handles requests for the app seed. *msgjson.ResponsePayload.Error
 // is empty if successful.
 func handleAppSeed(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
@@ -840,6 +1046,185 @@ func handleRemoveWalletPeer(s *RPCServer, params *RawParams) *msgjson.ResponsePa
 	return createResponse(removeWalletPeerRoute, "successfully removed peer", nil)
 }
 
+// handleAddressBook handles requests for an asset's withdrawal address book.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleAddressBook(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAddressBookArgs(params, 1)
+	if err != nil {
+		return usage(addressBookRoute, err)
+	}
+	defer form.appPass.Clear()
+
+	book, err := s.core.AddressBook(form.appPass, form.assetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAddressBookError, "unable to get address book: %v", err)
+		return createResponse(addressBookRoute, nil, resErr)
+	}
+	return createResponse(addressBookRoute, book, nil)
+}
+
+// handleAddAddress handles requests to add or relabel an address book entry.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleAddAddress(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAddressBookArgs(params, 2, 3)
+	if err != nil {
+		return usage(addAddressRoute, err)
+	}
+	defer form.appPass.Clear()
+
+	err = s.core.AddAddressBookEntry(form.appPass, form.assetID, form.address, form.label)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAddressBookError, "unable to add address: %v", err)
+		return createResponse(addAddressRoute, nil, resErr)
+	}
+	return createResponse(addAddressRoute, "successfully added address", nil)
+}
+
+// handleRemoveAddress handles requests to remove an address book entry.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRemoveAddress(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAddressBookArgs(params, 2)
+	if err != nil {
+		return usage(removeAddressRoute, err)
+	}
+	defer form.appPass.Clear()
+
+	err = s.core.RemoveAddressBookEntry(form.appPass, form.assetID, form.address)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAddressBookError, "unable to remove address: %v", err)
+		return createResponse(removeAddressRoute, nil, resErr)
+	}
+	return createResponse(removeAddressRoute, "successfully removed address", nil)
+}
+
+// handleRestrictWithdrawals handles requests to enable or disable the
+// restriction of withdrawals to address book entries.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRestrictWithdrawals(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseRestrictWithdrawalsArgs(params)
+	if err != nil {
+		return usage(restrictWithdrawalsRoute, err)
+	}
+	defer form.appPass.Clear()
+
+	err = s.core.RestrictWithdrawals(form.appPass, form.assetID, form.restrict)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAddressBookError, "unable to update withdrawal restriction: %v", err)
+		return createResponse(restrictWithdrawalsRoute, nil, resErr)
+	}
+	msg := "withdrawals are no longer restricted"
+	if form.restrict {
+		msg = "withdrawals restricted to address book"
+	}
+	return createResponse(restrictWithdrawalsRoute, msg, nil)
+}
+
+// handleExportAccount handles requests for exportaccount. The account's keys
+// and bonds are written to a new file, in the same format as the file exported
+// from the browser. *msgjson.ResponsePayload.Error is empty if successful.
+func handleExportAccount(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseExportAccountArgs(params)
+	if err != nil {
+		return usage(exportAccountRoute, err)
+	}
+	defer form.appPass.Clear()
+
+	acct, bonds, err := s.core.AccountExport(form.appPass, form.host)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCExportAccountError, "unable to export account: %v", err)
+		return createResponse(exportAccountRoute, nil, resErr)
+	}
+	if bonds == nil {
+		bonds = make([]*db.Bond, 0) // marshal to [], not null
+	}
+	b, err := json.MarshalIndent(&accountFile{Account: acct, Bonds: bonds}, "", "  ")
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCExportAccountError, "unable to encode account: %v", err)
+		return createResponse(exportAccountRoute, nil, resErr)
+	}
+	// The file holds the account's private key, so it is only readable by the
+	// user, and an existing file is never overwritten.
+	f, err := os.OpenFile(form.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCExportAccountError, "unable to create account file: %v", err)
+		return createResponse(exportAccountRoute, nil, resErr)
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(form.path)
+		resErr := msgjson.NewError(msgjson.RPCExportAccountError, "unable to write account file: %v", err)
+		return createResponse(exportAccountRoute, nil, resErr)
+	}
+	res := fmt.Sprintf(acctExportedStr, acct.Host, form.path)
+	return createResponse(exportAccountRoute, &res, nil)
+}
+
+// handleDBSnapshots handles requests for dbsnapshots.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleDBSnapshots(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
+	snaps, err := s.core.DBSnapshots()
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCDBSnapshotError, "unable to list snapshots: %v", err)
+		return createResponse(dbSnapshotsRoute, nil, resErr)
+	}
+	if snaps == nil {
+		snaps = make([]*db.Snapshot, 0) // marshal to [], not null
+	}
+	return createResponse(dbSnapshotsRoute, snaps, nil)
+}
+
+// handleRestoreDBSnapshot handles requests for restoredbsnapshot. The snapshot
+// replaces the database when Bison Wallet is next started.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRestoreDBSnapshot(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseRestoreDBSnapshotArgs(params)
+	if err != nil {
+		return usage(restoreDBSnapshotRoute, err)
+	}
+	defer form.appPass.Clear()
+	if err := s.core.RestoreDBSnapshot(form.appPass, form.name); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCDBSnapshotError, "unable to restore snapshot: %v", err)
+		return createResponse(restoreDBSnapshotRoute, nil, resErr)
+	}
+	res := fmt.Sprintf(snapshotStagedStr, form.name)
+	return createResponse(restoreDBSnapshotRoute, &res, nil)
+}
+
+// handleImportAccount handles requests for importaccount. The file may be from
+// exportaccount or the browser. *msgjson.ResponsePayload.Error is empty if
+// successful.
+func handleImportAccount(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseImportAccountArgs(params)
+	if err != nil {
+		return usage(importAccountRoute, err)
+	}
+	defer form.appPass.Clear()
+
+	b, err := os.ReadFile(form.path)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCImportAccountError, "unable to read account file: %v", err)
+		return createResponse(importAccountRoute, nil, resErr)
+	}
+	af := &accountFile{Account: new(core.Account)}
+	if err := json.Unmarshal(b, af); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCImportAccountError, "unable to decode account file: %v", err)
+		return createResponse(importAccountRoute, nil, resErr)
+	}
+	if af.Host == "" || af.PrivKey == "" {
+		resErr := msgjson.NewError(msgjson.RPCImportAccountError, "no account in file %s", form.path)
+		return createResponse(importAccountRoute, nil, resErr)
+	}
+	if err := s.core.AccountImport(form.appPass, af.Account, af.Bonds); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCImportAccountError, "unable to import account: %v", err)
+		return createResponse(importAccountRoute, nil, resErr)
+	}
+	res := fmt.Sprintf(acctImportedStr, af.Host)
+	return createResponse(importAccountRoute, &res, nil)
+}
+
 func handleNotifications(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	numNotes, err := parseNotificationsArgs(params)
 	if err != nil {
@@ -1276,6 +1661,33 @@ var helpMsgs = map[string]helpMsg{
       See https://github.com/satoshilabs/slips/blob/master/slip-0044.md`,
 		returns: `Returns:
     string: The message "` + fmt.Sprintf(walletLockedStr, "[coin symbol]") + `"`,
+	},
+	changeAppPassRoute: {
+		pwArgsShort: `"appPass" "newAppPass"`,
+		cmdSummary: `Change the Bison Wallet password. Account keys and wallet passwords
+  do not need to be re-encrypted, so the change is made in a single update.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The current Bison Wallet password.
+    newAppPass (string): The new Bison Wallet password.`,
+		returns: `Returns:
+    string: The message "` + appPassChangedStr + `"`,
+	},
+	setWalletPassRoute: {
+		pwArgsShort: `"appPass" "newWalletPass"`,
+		argsShort:   `assetID`,
+		cmdSummary: `Update the password stored for a wallet after it was changed in the
+  wallet itself. The wallet is unlocked with the new password before it is
+  saved. If it cannot be saved, the old password is kept. Not available for
+  seeded (native) or token wallets.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.
+    newWalletPass (string): The wallet's new password. Leave the password
+      empty for wallets without a password set.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index. e.g. 42 for DCR.
+      See https://github.com/satoshilabs/slips/blob/master/slip-0044.md`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(walletPassSetStr, "[coin symbol]") + `"`,
 	},
 	toggleWalletStatusRoute: {
 		pwArgsShort: "appPass",
@@ -1290,6 +1702,73 @@ var helpMsgs = map[string]helpMsg{
   disable (bool): The wallet's status. e.g To disable a wallet set to "true", to enable set to "false".`,
 		returns: `Returns:
     string: The message "` + fmt.Sprintf(walletStatusStr, "[coin symbol]", "[wallet status]") + `".`,
+	},
+	portfolioRoute: {
+		cmdSummary: `Show the fiat (USD) value of each wallet's balance and of all
+  wallets, at the current fiat rates. At least one fiat rate source must be
+  enabled.`,
+		returns: `Returns:
+    obj: The portfolio value.
+    {
+      "assets" (array): The value of each wallet.
+      [
+        {
+          "assetID" (int): The asset's BIP-44 registered coin index.
+          "symbol" (string): The coin symbol.
+          "balance" (int): The total balance in atomic units, including
+            immature, locked, and bond-locked funds and funds in unspent
+            swap contracts.
+          "rate" (float): The fiat value of one unit of the asset. Zero if no
+            rate source has a current rate for the asset.
+          "value" (float): The fiat value of the balance.
+        },...
+      ],
+      "total" (float): The total fiat value of the assets that have a rate.
+    }`,
+	},
+	lockedBalanceRoute: {
+		cmdSummary: `Show what a wallet's locked funds are locked for. Funds locked for
+  orders, fee reserves, and bond reserves are part of the wallet's locked
+  balance. Funds in swap contracts, funds pending redemption, and bonds have
+  left the wallet for now.`,
+		argsShort: `assetID`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index.`,
+		returns: `Returns:
+    obj: The locked balance breakdown. Amounts are in atomic units.
+    {
+      "assetID" (int): The asset's BIP-44 registered coin index.
+      "symbol" (string): The coin symbol.
+      "locked" (int): The wallet's locked balance.
+      "orders" (array): Funds locked to fund the future swaps of orders.
+      [
+        {
+          "host" (string): The DEX host.
+          "marketID" (string): The market.
+          "orderID" (string): The order's hex ID.
+          "amount" (int): The amount locked.
+        },...
+      ]
+      "feeReserves" (array): Funds locked for the fees of orders, in the same
+        format as orders.
+      "bondReserves" (int): Funds locked for future bonds.
+      "other" (obj): Locked custom balance categories of the wallet.
+      "unexplained" (int): The part of the locked balance that is not
+        accounted for, e.g. funds locked outside of the DEX client.
+      "contracts" (array): Funds in unspent swap contracts.
+      [
+        {
+          "host" (string): The DEX host.
+          "marketID" (string): The market.
+          "orderID" (string): The order's hex ID.
+          "matchID" (string): The match's hex ID.
+          "amount" (int): The amount in the contract.
+        },...
+      ]
+      "pendingRedemptions" (array): Funds that matches will pay to the wallet
+        once redeemed, in the same format as contracts.
+      "bonds" (int): Funds in unspent bonds.
+    }`,
 	},
 	walletsRoute: {
 		cmdSummary: `List all wallets.`,
@@ -1422,7 +1901,7 @@ var helpMsgs = map[string]helpMsg{
 	},
 	tradeRoute: {
 		pwArgsShort: `"appPass"`,
-		argsShort:   `"host" isLimit sell base quote qty rate immediate`,
+		argsShort:   `"host" isLimit sell base quote qty rate immediate options (worstRate ttl maxDrift)`,
 		cmdSummary:  `Make an order to buy or sell an asset.`,
 		pwArgsLong: `Password Args:
     appPass (string): The Bison Wallet password.`,
@@ -1437,7 +1916,16 @@ var helpMsgs = map[string]helpMsg{
       156000 satoshi/DCR for the DCR(base)_BTC(quote).
     immediate (bool): Require immediate match. Do not book the order.
     options (string): A JSON-encoded string->string mapping of additional
-       trade options.`,
+       trade options.
+    worstRate (int): Optional. For market orders, the worst rate, in the same
+      units as rate, at which the order may be filled. The highest rate for a
+      buy, the lowest for a sell. Any remainder not matched within the limit is
+      left unfilled. Must be a multiple of the rate step. (default is no limit)
+    ttl (int): Optional. For standing limit orders, the number of seconds the
+      order may stay on the book before it is canceled. (default is no limit)
+    maxDrift (float): Optional. For standing limit orders, the difference, in
+      percent, between the order's rate and the market's spot rate at which the
+      order is canceled. (default is no limit)`,
 		returns: `Returns:
     obj: The order details.
     {
@@ -1445,6 +1933,111 @@ var helpMsgs = map[string]helpMsg{
       "sig" (string): The DEX's signature of the order information.
       "stamp" (int): The time the order was signed in milliseconds since 00:00:00
         Jan 1 1970.
+    }`,
+	},
+	preOrderRoute: {
+		argsShort:  `"host" isLimit sell base quote qty rate immediate options (worstRate ttl maxDrift)`,
+		cmdSummary: `Estimate the size, fees, and locked funds of a prospective order, without placing it.`,
+		argsLong: `Args:
+    host (string): The DEX to trade on.
+    isLimit (bool): Whether the order is a limit order.
+    sell (bool): Whether the order is selling.
+    base (int): The BIP-44 coin index for the market's base asset.
+    quote (int): The BIP-44 coin index for the market's quote asset.
+    qty (int): The number of units to buy/sell. For a market buy, the amount of
+      the quote asset to spend.
+    rate (int): The atoms quote asset to pay/accept per unit base asset.
+      Ignored for market orders, which are estimated from the book.
+    immediate (bool): Require immediate match. Do not book the order.
+    options (string): A JSON-encoded string->string mapping of additional
+       trade options.
+    worstRate (int): Optional. The worst rate for a market order. See trade.
+    ttl (int): Optional. The time to live of a standing limit order. See trade.
+    maxDrift (float): Optional. The max rate drift of a standing limit order.
+      See trade.`,
+		returns: `Returns:
+    obj: The order estimate.
+    {
+      "swap" (obj): The from asset's swap estimate.
+      {
+        "estimate" (obj): Swap fee estimates.
+        {
+          "lots" (int): The number of lots in the order.
+          "value" (int): The total value of the order, in the from asset.
+          "maxFees" (int): The maximum possible fees for the order's swaps.
+          "realisticWorstCase" (int): The fees for one swap per lot at the
+            prevailing fee rate.
+          "realisticBestCase" (int): The fees for a single swap at the
+            prevailing fee rate.
+          "feeReservesPerLot" (int): The amount reserved per lot for swap fees.
+        }
+        "options" (array): Order options that the wallet supports.
+      }
+      "redeem" (obj): The to asset's redemption estimate.
+      {
+        "estimate" (obj): Redeem fee estimates.
+        {
+          "realisticWorstCase" (int): The fees for one redemption per lot.
+          "realisticBestCase" (int): The fees for a single redemption.
+        }
+        "options" (array): Order options that the wallet supports.
+      }
+      "liquidity" (obj): The market's liquidity relative to the order.
+      {
+        "volume" (int): The base asset match volume over the last 24 hours.
+        "fillable" (int): The base asset quantity that an immediate order
+          could match on the current book.
+        "warnings" (array): Low liquidity warnings. Orders with warnings are
+          placed by the trade route anyway.
+      }
+      "lots" (int): The number of lots in the order.
+      "qty" (int): The quantity that will trade, in the base asset, after
+        rounding down to the lot size.
+      "maxLots" (int): The most lots that the wallet's balance can fund.
+      "maxQty" (int): maxLots in units of the base asset.
+      "locked" (obj): The amount of each asset, keyed by asset ID, that will
+        be locked to fund the order, including swap fee reserves.
+    }`,
+	},
+	validateTradeRoute: {
+		argsShort: `"host" isLimit sell base quote qty rate immediate options (worstRate ttl maxDrift)`,
+		cmdSummary: `Check whether a prospective order would be accepted, without placing
+    it. Every problem with the order is reported, not just the first. Wallets
+    are not unlocked and no funds are locked.`,
+		argsLong: `Args:
+    host (string): The DEX to trade on.
+    isLimit (bool): Whether the order is a limit order.
+    sell (bool): Whether the order is selling.
+    base (int): The BIP-44 coin index for the market's base asset.
+    quote (int): The BIP-44 coin index for the market's quote asset.
+    qty (int): The number of units to buy/sell. For a market buy, the amount of
+      the quote asset to spend.
+    rate (int): The atoms quote asset to pay/accept per unit base asset.
+      Ignored for market orders.
+    immediate (bool): Require immediate match. Do not book the order.
+    options (string): A JSON-encoded string->string mapping of additional
+       trade options.
+    worstRate (int): Optional. The worst rate for a market order. See trade.
+    ttl (int): Optional. The time to live of a standing limit order. See trade.
+    maxDrift (float): Optional. The max rate drift of a standing limit order.
+      See trade.`,
+		returns: `Returns:
+    obj: The validation result.
+    {
+      "valid" (bool): Whether the order can be placed now.
+      "errors" (array): The reasons the order would be rejected.
+      [
+        {
+          "check" (string): The part of the order that failed. One of
+            "account", "market", "rate", "quantity", "options", "wallet",
+            "funding", or "limits".
+          "message" (string): The error.
+          "transient" (bool): Whether the order itself is fine but can't be
+            placed right now, e.g. because a wallet is locked or not synced or
+            has insufficient funds. Errors that are not transient will not go
+            away until the order is changed.
+        },...
+      ]
     }`,
 	},
 	multiTradeRoute: {
@@ -1487,7 +2080,9 @@ var helpMsgs = map[string]helpMsg{
 		argsShort: `assetID (force)`,
 		cmdSummary: `Initiate a rescan of an asset's wallet. This is only supported for certain
 wallet types. Wallet resynchronization may be asynchronous, and the wallet
-state should be consulted for progress.
+state should be consulted for progress. When the rescan is complete, orders
+whose funding coins could not be found are checked again, and a notification
+reports the result.
 
 WARNING: It is ill-advised to initiate a wallet rescan with active orders
 unless as a last ditch effort to get the wallet to recognize a transaction
@@ -1637,6 +2232,40 @@ needed to complete a swap.`,
       ]
     },...
   ]`,
+	},
+	orderHistoryRoute: {
+		argsShort: `("filter")`,
+		cmdSummary: `Fetch the user's orders, including inactive and archived
+    orders, with their matches. Orders are sorted by the time they were last
+    updated, newest first.`,
+		argsLong: `Args:
+    filter (string): Optional. A JSON-encoded object with any of the fields
+      below. e.g. '{"host":"dex.decred.org:7232","side":"sell","n":20}'
+      {
+        "host" (string): The DEX to show orders from.
+        "base" (int): The BIP-44 coin index for the market's base asset.
+          Requires quote.
+        "quote" (int): The BIP-44 coin index for the market's quote asset.
+          Requires base.
+        "side" (string): "buy" or "sell".
+        "statuses" ([string]): Acceptable order statuses. "epoch", "booked",
+          "executed", "canceled", or "revoked".
+        "since" (int): Only orders last updated at or after this time, in
+          milliseconds since 00:00:00 Jan 1 1970.
+        "until" (int): Only orders last updated at or before this time, in
+          milliseconds.
+        "n" (int): The maximum number of orders to return. Default is 50.
+        "cursor" (string): The "next" value of the previous response, to get
+          the following page of orders.
+      }`,
+		returns: `Returns:
+  obj: The orders.
+  {
+    "orders" (array): An array of orders, in the same format as the myorders
+      command.
+    "next" (string): The cursor for the next page of orders. Omitted if there
+      are no more orders.
+  }`,
 	},
 	appSeedRoute: {
 		pwArgsShort: `"appPass"`,
@@ -1672,6 +2301,165 @@ needed to complete a swap.`,
 		which wallet to add a peer.
 		addr (string): The peer's address (host:port).`,
 	},
+	addressBookRoute: {
+		pwArgsShort: `"appPass"`,
+		cmdSummary:  `Show the withdrawal address book for an asset.`,
+		argsShort:   `(assetID)`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index.`,
+		returns: `Returns:
+    obj: The address book.
+    {
+      "assetID" (int): The asset's BIP-44 registered coin index.
+      "entries" (array): The saved addresses.
+      [
+        {
+          "address" (string): The withdrawal address.
+          "label" (string): A user-defined label for the address.
+        },...
+      ],
+      "restricted" (bool): Whether withdrawals are limited to the saved addresses.
+    }`,
+	},
+	addAddressRoute: {
+		pwArgsShort: `"appPass"`,
+		cmdSummary: `Add an address to an asset's withdrawal address book. If the address
+  is already saved, its label is updated.`,
+		argsShort: `(assetID) "address" ("label")`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index.
+    address (string): The withdrawal address.
+    label (string): Optional. A label for the address.`,
+	},
+	removeAddressRoute: {
+		pwArgsShort: `"appPass"`,
+		cmdSummary:  `Remove an address from an asset's withdrawal address book.`,
+		argsShort:   `(assetID) "address"`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index.
+    address (string): The address to remove.`,
+	},
+	restrictWithdrawalsRoute: {
+		pwArgsShort: `"appPass"`,
+		cmdSummary: `Restrict sends and withdrawals of an asset to addresses in its
+  address book, or lift the restriction. The address book must not be empty
+  to enable the restriction.`,
+		argsShort: `(assetID) (restrict)`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index.
+    restrict (bool): Whether to restrict withdrawals to the address book.`,
+	},
+	preAccelerateRoute: {
+		argsShort: `"orderID"`,
+		cmdSummary: `Get the information needed to choose a fee rate for accelerating
+    the unconfirmed swap transactions of an order. Acceleration is only
+    available for wallets that support child-pays-for-parent transactions.`,
+		argsLong: `Args:
+    orderID (string): The hex ID of the order.`,
+		returns: `Returns:
+    obj: The acceleration details.
+    {
+      "swapRate" (int): The effective fee rate of the order's unconfirmed swap
+        transactions, including any previous accelerations.
+      "suggestedRate" (int): The current fee rate suggestion.
+      "suggestedRange" (obj): A range of fee rates and the total fees each
+        would cost, for choosing a newFeeRate.
+      "earlyAcceleration" (obj): Set if it is early to accelerate again.
+        {
+          "timePast" (int): Seconds since the previous acceleration, or since
+            the oldest unmined swap transaction was sent.
+          "wasAccelerated" (bool): Whether timePast is measured from a
+            previous acceleration.
+        }
+    }`,
+	},
+	accelerationEstimateRoute: {
+		argsShort:  `"orderID" newFeeRate`,
+		cmdSummary: `Estimate the fees to accelerate an order's swap transactions.`,
+		argsLong: `Args:
+    orderID (string): The hex ID of the order.
+    newFeeRate (int): The desired effective fee rate of the swap transactions.`,
+		returns: `Returns:
+    int: The fees, in atomic units of the order's funding asset, that the
+      acceleration transaction would pay.`,
+	},
+	accelerateOrderRoute: {
+		pwArgsShort: `"appPass"`,
+		argsShort:   `"orderID" newFeeRate`,
+		cmdSummary: `Accelerate the unconfirmed swap transactions of an order by
+    broadcasting a child-pays-for-parent transaction that spends the order's
+    change. Use preaccelerate and accelerationestimate to choose a fee rate.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    orderID (string): The hex ID of the order.
+    newFeeRate (int): The desired effective fee rate of the swap transactions.`,
+		returns: `Returns:
+    string: The ID of the acceleration transaction.`,
+	},
+	exportAccountRoute: {
+		pwArgsShort: `"appPass"`,
+		argsShort:   `"host" "path"`,
+		cmdSummary: `Export a DEX account's keys and bonds to a file. The file can be
+  imported with importaccount, or in the browser, to restore the account on
+  another machine without posting new bonds. The file contains the account's
+  private key and must be kept secret.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    host (string): The DEX address of the account.
+    path (string): The path of the file to create. An existing file is not
+      overwritten.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(acctExportedStr, "[host]", "[path]") + `"`,
+	},
+	importAccountRoute: {
+		pwArgsShort: `"appPass"`,
+		argsShort:   `"path"`,
+		cmdSummary: `Import a DEX account from a file created by exportaccount or the
+  browser. If the account is already known, any missing bonds are added.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    path (string): The path of the account file.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(acctImportedStr, "[host]") + `"`,
+	},
+	dbSnapshotsRoute: {
+		cmdSummary: `List the saved database snapshots, newest first.`,
+		returns: `Returns:
+    array: The snapshots.
+    [
+      {
+        "name" (string): The snapshot's name, for restoredbsnapshot.
+        "stamp" (int): The creation time in milliseconds.
+        "size" (int): The size of the snapshot file in bytes.
+        "checksum" (string): The SHA-256 of the snapshot file, recorded when
+          the snapshot was created.
+        "valid" (bool): Whether the snapshot file still matches its checksum.
+      },...
+    ]`,
+	},
+	restoreDBSnapshotRoute: {
+		pwArgsShort: `"appPass"`,
+		argsShort:   `"name"`,
+		cmdSummary: `Restore a database snapshot. The snapshot replaces the database
+  when Bison Wallet is next started. Not allowed while trades are active.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    name (string): The name of the snapshot, from dbsnapshots.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(snapshotStagedStr, "[name]") + `"`,
+	},
 	notificationsRoute: {
 		cmdSummary: `See recent notifications.`,
 		argsShort:  `(num)`,
@@ -8,16 +8,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/websocket"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
 	"github.com/davecgh/go-spew/spew"
 )
 
@@ -436,6 +440,77 @@ func TestHandleOpenWallet(t *testing.T) {
 	}
 }
 
+func TestHandleChangeAppPass(t *testing.T) {
+	params := &RawParams{PWArgs: []encode.PassBytes{encode.PassBytes("abc"), encode.PassBytes("def")}}
+	tests := []struct {
+		name             string
+		params           *RawParams
+		changeAppPassErr error
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:             "core.ChangeAppPass error",
+		params:           params,
+		changeAppPassErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCChangeAppPassError,
+	}, {
+		name:        "empty new password",
+		params:      &RawParams{PWArgs: []encode.PassBytes{encode.PassBytes("abc"), nil}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{changeAppPassErr: test.changeAppPassErr}
+		r := &RPCServer{core: tc}
+		payload := handleChangeAppPass(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestHandleSetWalletPass(t *testing.T) {
+	params := &RawParams{
+		PWArgs: []encode.PassBytes{encode.PassBytes("abc"), encode.PassBytes("def")},
+		Args:   []string{"42"},
+	}
+	tests := []struct {
+		name             string
+		params           *RawParams
+		setWalletPassErr error
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:             "core.SetWalletPassword error",
+		params:           params,
+		setWalletPassErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCSetWalletPassError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{PWArgs: params.PWArgs},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{setWalletPassErr: test.setWalletPassErr}
+		r := &RPCServer{core: tc}
+		payload := handleSetWalletPass(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+	}
+}
+
 func TestHandleCloseWallet(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -512,6 +587,75 @@ func TestHandleWallets(t *testing.T) {
 	}
 }
 
+func TestHandlePortfolio(t *testing.T) {
+	tc := &TCore{portfolio: &core.PortfolioValue{
+		Assets: []*core.AssetValue{{AssetID: 42, Symbol: "dcr", Balance: 2e8, Rate: 20, Value: 40}},
+		Total:  40,
+	}}
+	r := &RPCServer{core: tc}
+	payload := handlePortfolio(r, nil)
+	res := new(core.PortfolioValue)
+	if err := verifyResponse(payload, res, -1); err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 40 || len(res.Assets) != 1 || res.Assets[0].Value != 40 {
+		t.Fatalf("wrong portfolio %+v", res)
+	}
+}
+
+func TestHandleLockedBalance(t *testing.T) {
+	lb := &core.LockedBalance{
+		AssetID: 42,
+		Symbol:  "dcr",
+		Locked:  5e7,
+		Orders: []*core.OrderLock{{
+			Host:     "127.0.0.1:7232",
+			MarketID: "dcr_btc",
+			OrderID:  dex.Bytes{0x01},
+			Amount:   4e7,
+		}},
+		Unexplained: 1e7,
+	}
+	tests := []struct {
+		name             string
+		params           *RawParams
+		lockedBalanceErr error
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      &RawParams{Args: []string{"42"}},
+		wantErrCode: -1,
+	}, {
+		name:             "core.LockedBalance error",
+		params:           &RawParams{Args: []string{"42"}},
+		lockedBalanceErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCLockedBalanceError,
+	}, {
+		name:        "bad asset ID",
+		params:      &RawParams{Args: []string{"dcr"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "no args",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			lockedBalance:    lb,
+			lockedBalanceErr: test.lockedBalanceErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleLockedBalance(r, test.params)
+		res := new(core.LockedBalance)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && (len(res.Orders) != 1 || res.Orders[0].Amount != 4e7 || res.Unexplained != 1e7) {
+			t.Fatalf("%s: wrong locked balance %+v", test.name, res)
+		}
+	}
+}
+
 const exchangeIn = `{
   "https://127.0.0.1:7232": {
     "host": "https://127.0.0.1:7232",
@@ -728,6 +872,372 @@ func TestHandleTrade(t *testing.T) {
 	}
 }
 
+func TestHandlePreOrder(t *testing.T) {
+	params := &RawParams{
+		Args: []string{
+			"1.2.3.4:3000", // 0. DEX
+			"false",        // 1. IsLimit
+			"true",         // 2. Sell
+			"42",           // 3. Base
+			"0",            // 4. Quote
+			"100",          // 5. Qty
+			"0",            // 6. Rate
+			"false",        // 7. TifNow
+			"{}",           // 8. Options
+		}}
+	tests := []struct {
+		name        string
+		params      *RawParams
+		preOrderErr error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:        "core.PreOrder error",
+		params:      params,
+		preOrderErr: errors.New("error"),
+		wantErrCode: msgjson.RPCPreOrderError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			orderEstimate: &core.OrderEstimate{Lots: 1, MaxLots: 5},
+			preOrderErr:   test.preOrderErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handlePreOrder(r, test.params)
+		res := new(core.OrderEstimate)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatal(err)
+		}
+		if test.wantErrCode == -1 && res.MaxLots != 5 {
+			t.Fatalf("%s: wrong max lots %d", test.name, res.MaxLots)
+		}
+	}
+}
+
+func TestHandleValidateTrade(t *testing.T) {
+	params := &RawParams{
+		Args: []string{
+			"1.2.3.4:3000", // 0. DEX
+			"true",         // 1. IsLimit
+			"true",         // 2. Sell
+			"42",           // 3. Base
+			"0",            // 4. Quote
+			"100",          // 5. Qty
+			"1",            // 6. Rate
+			"false",        // 7. TifNow
+			"{}",           // 8. Options
+		}}
+	validation := &core.TradeValidation{
+		Errors: []*core.TradeValidationError{{
+			Check:     core.ValidateFunding,
+			Message:   "insufficient funds",
+			Transient: true,
+		}},
+	}
+	tests := []struct {
+		name        string
+		params      *RawParams
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{tradeValidation: validation}
+		r := &RPCServer{core: tc}
+		payload := handleValidateTrade(r, test.params)
+		res := new(core.TradeValidation)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatal(err)
+		}
+		if test.wantErrCode == -1 && (res.Valid || len(res.Errors) != 1 || !res.Errors[0].Transient) {
+			t.Fatalf("%s: wrong validation result %+v", test.name, res)
+		}
+	}
+}
+
+func TestHandleAccelerate(t *testing.T) {
+	oid := "fb94fe99e4e32200a341f0f1cb33f34a08ac23eedab636e8adb991fa76343e1e"
+	pw := []encode.PassBytes{encode.PassBytes("abc")}
+	tests := []struct {
+		name          string
+		handler       func(*RPCServer, *RawParams) *msgjson.ResponsePayload
+		params        *RawParams
+		accelerateErr error
+		wantErrCode   int
+	}{{
+		name:        "preaccelerate ok",
+		handler:     handlePreAccelerate,
+		params:      &RawParams{Args: []string{oid}},
+		wantErrCode: -1,
+	}, {
+		name:          "preaccelerate core error",
+		handler:       handlePreAccelerate,
+		params:        &RawParams{Args: []string{oid}},
+		accelerateErr: errors.New("not an accelerator"),
+		wantErrCode:   msgjson.RPCPreAccelerateError,
+	}, {
+		name:        "estimate ok",
+		handler:     handleAccelerationEstimate,
+		params:      &RawParams{Args: []string{oid, "50"}},
+		wantErrCode: -1,
+	}, {
+		name:          "estimate core error",
+		handler:       handleAccelerationEstimate,
+		params:        &RawParams{Args: []string{oid, "50"}},
+		accelerateErr: errors.New("not an accelerator"),
+		wantErrCode:   msgjson.RPCAccelerationEstimateError,
+	}, {
+		name:        "accelerate ok",
+		handler:     handleAccelerateOrder,
+		params:      &RawParams{PWArgs: pw, Args: []string{oid, "50"}},
+		wantErrCode: -1,
+	}, {
+		name:          "accelerate core error",
+		handler:       handleAccelerateOrder,
+		params:        &RawParams{PWArgs: pw, Args: []string{oid, "50"}},
+		accelerateErr: errors.New("not an accelerator"),
+		wantErrCode:   msgjson.RPCAccelerateOrderError,
+	}, {
+		name:        "accelerate no password",
+		handler:     handleAccelerateOrder,
+		params:      &RawParams{Args: []string{oid, "50"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			preAccelerate: &core.PreAccelerate{SwapRate: 10, SuggestedRate: 40},
+			accelerateErr: test.accelerateErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := test.handler(r, test.params)
+		var res any
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestHandleOrderHistory(t *testing.T) {
+	var oid1, oid2 order.OrderID
+	oid1[0], oid2[0] = 1, 2
+	ords := []*core.Order{
+		{Host: "host", BaseID: 42, QuoteID: 0, ID: oid1[:], Status: order.OrderStatusExecuted},
+		{Host: "host", BaseID: 42, QuoteID: 0, ID: oid2[:], Status: order.OrderStatusCanceled},
+	}
+	tests := []struct {
+		name        string
+		params      *RawParams
+		ordersErr   error
+		wantErrCode int
+		wantNext    string
+	}{{
+		name:        "ok full page",
+		params:      &RawParams{Args: []string{`{"n":2}`}},
+		wantErrCode: -1,
+		wantNext:    oid2.String(),
+	}, {
+		name:        "ok last page",
+		params:      &RawParams{Args: []string{`{"n":3}`}},
+		wantErrCode: -1,
+	}, {
+		name:        "core.Orders error",
+		params:      &RawParams{},
+		ordersErr:   errors.New("error"),
+		wantErrCode: msgjson.RPCOrderHistoryError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{Args: []string{`{"side":"long"}`}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			orders:    ords,
+			ordersErr: test.ordersErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleOrderHistory(r, test.params)
+		res := new(orderHistoryResponse)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatal(err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		if len(res.Orders) != len(ords) {
+			t.Fatalf("%s: wanted %d orders, got %d", test.name, len(ords), len(res.Orders))
+		}
+		if res.Next != test.wantNext {
+			t.Fatalf("%s: wanted next %q, got %q", test.name, test.wantNext, res.Next)
+		}
+	}
+}
+
+func TestHandleDBSnapshots(t *testing.T) {
+	snaps := []*db.Snapshot{{Name: "snap", Stamp: 1, Size: 2, Checksum: "abcd", Valid: true}}
+	tc := &TCore{snapshots: snaps}
+	r := &RPCServer{core: tc}
+	var res []*db.Snapshot
+	if err := verifyResponse(handleDBSnapshots(r, nil), &res, -1); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || *res[0] != *snaps[0] {
+		t.Fatalf("wrong snapshots %+v", res)
+	}
+	tc.snapshotsErr = errors.New("error")
+	if err := verifyResponse(handleDBSnapshots(r, nil), &res, msgjson.RPCDBSnapshotError); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandleRestoreDBSnapshot(t *testing.T) {
+	pw := []encode.PassBytes{encode.PassBytes("abc")}
+	tests := []struct {
+		name        string
+		params      *RawParams
+		restoreErr  error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &RawParams{PWArgs: pw, Args: []string{"snap"}},
+		wantErrCode: -1,
+	}, {
+		name:        "core.RestoreDBSnapshot error",
+		params:      &RawParams{PWArgs: pw, Args: []string{"snap"}},
+		restoreErr:  errors.New("error"),
+		wantErrCode: msgjson.RPCDBSnapshotError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{PWArgs: pw},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{restoreSnapshotErr: test.restoreErr}
+		r := &RPCServer{core: tc}
+		payload := handleRestoreDBSnapshot(r, test.params)
+		var res string
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && tc.restoredSnapshot != "snap" {
+			t.Fatalf("%s: snapshot not restored", test.name)
+		}
+	}
+}
+
+func TestHandleExportImportAccount(t *testing.T) {
+	dir := t.TempDir()
+	acct := &core.Account{
+		Host:      "127.0.0.1:17273",
+		AccountID: "abcd",
+		PrivKey:   "0123",
+		DEXPubKey: "4567",
+	}
+	bonds := []*db.Bond{{AssetID: 42, CoinID: []byte{1, 2}, Amount: 1e8}}
+	pw := []encode.PassBytes{encode.PassBytes("abc")}
+	acctPath := filepath.Join(dir, "acct.json")
+
+	exportTests := []struct {
+		name        string
+		params      *RawParams
+		exportErr   error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &RawParams{PWArgs: pw, Args: []string{acct.Host, acctPath}},
+		wantErrCode: -1,
+	}, {
+		name:        "file exists",
+		params:      &RawParams{PWArgs: pw, Args: []string{acct.Host, acctPath}},
+		wantErrCode: msgjson.RPCExportAccountError,
+	}, {
+		name:        "core.AccountExport error",
+		params:      &RawParams{PWArgs: pw, Args: []string{acct.Host, filepath.Join(dir, "other.json")}},
+		exportErr:   errors.New("error"),
+		wantErrCode: msgjson.RPCExportAccountError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{Args: []string{acct.Host}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range exportTests {
+		tc := &TCore{account: acct, accountBonds: bonds, accountExportErr: test.exportErr}
+		r := &RPCServer{core: tc}
+		payload := handleExportAccount(r, test.params)
+		var res string
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+	}
+	if fi, err := os.Stat(acctPath); err != nil {
+		t.Fatalf("account file not written: %v", err)
+	} else if fi.Mode().Perm() != 0600 {
+		t.Fatalf("wrong account file permissions %v", fi.Mode().Perm())
+	}
+
+	badPath := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(badPath, []byte(`{"bonds":[]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	importTests := []struct {
+		name        string
+		params      *RawParams
+		importErr   error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &RawParams{PWArgs: pw, Args: []string{acctPath}},
+		wantErrCode: -1,
+	}, {
+		name:        "core.AccountImport error",
+		params:      &RawParams{PWArgs: pw, Args: []string{acctPath}},
+		importErr:   errors.New("error"),
+		wantErrCode: msgjson.RPCImportAccountError,
+	}, {
+		name:        "no file",
+		params:      &RawParams{PWArgs: pw, Args: []string{filepath.Join(dir, "missing.json")}},
+		wantErrCode: msgjson.RPCImportAccountError,
+	}, {
+		name:        "no account in file",
+		params:      &RawParams{PWArgs: pw, Args: []string{badPath}},
+		wantErrCode: msgjson.RPCImportAccountError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{Args: []string{acctPath}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range importTests {
+		tc := &TCore{accountImportErr: test.importErr}
+		r := &RPCServer{core: tc}
+		payload := handleImportAccount(r, test.params)
+		var res string
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		if !reflect.DeepEqual(tc.importedAccount, acct) {
+			t.Fatalf("wrong account imported: %+v", tc.importedAccount)
+		}
+		if len(tc.importedBonds) != 1 || !reflect.DeepEqual(tc.importedBonds[0].CoinID, bonds[0].CoinID) {
+			t.Fatalf("wrong bonds imported: %+v", tc.importedBonds)
+		}
+	}
+}
+
 func TestHandleCancel(t *testing.T) {
 	params := &RawParams{
 		Args: []string{"fb94fe99e4e32200a341f0f1cb33f34a08ac23eedab636e8adb991fa76343e1e"},
@@ -1382,3 +1892,79 @@ func TestHandleSetVotingPreferences(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleAddressBook(t *testing.T) {
+	pw := encode.PassBytes("password123")
+	pwParams := func(args ...string) *RawParams {
+		return &RawParams{PWArgs: []encode.PassBytes{pw}, Args: args}
+	}
+	tests := []struct {
+		name           string
+		handler        func(*RPCServer, *RawParams) *msgjson.ResponsePayload
+		params         *RawParams
+		addressBookErr error
+		wantErrCode    int
+	}{{
+		name:        "ok list",
+		handler:     handleAddressBook,
+		params:      pwParams("42"),
+		wantErrCode: -1,
+	}, {
+		name:           "core.AddressBook error",
+		handler:        handleAddressBook,
+		params:         pwParams("42"),
+		addressBookErr: errors.New("error"),
+		wantErrCode:    msgjson.RPCAddressBookError,
+	}, {
+		name:        "ok add",
+		handler:     handleAddAddress,
+		params:      pwParams("42", "addr", "label"),
+		wantErrCode: -1,
+	}, {
+		name:        "ok add no label",
+		handler:     handleAddAddress,
+		params:      pwParams("42", "addr"),
+		wantErrCode: -1,
+	}, {
+		name:           "core.AddAddressBookEntry error",
+		handler:        handleAddAddress,
+		params:         pwParams("42", "addr"),
+		addressBookErr: errors.New("error"),
+		wantErrCode:    msgjson.RPCAddressBookError,
+	}, {
+		name:        "ok remove",
+		handler:     handleRemoveAddress,
+		params:      pwParams("42", "addr"),
+		wantErrCode: -1,
+	}, {
+		name:        "remove missing address",
+		handler:     handleRemoveAddress,
+		params:      pwParams("42"),
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "ok restrict",
+		handler:     handleRestrictWithdrawals,
+		params:      pwParams("42", "true"),
+		wantErrCode: -1,
+	}, {
+		name:           "core.RestrictWithdrawals error",
+		handler:        handleRestrictWithdrawals,
+		params:         pwParams("42", "false"),
+		addressBookErr: errors.New("error"),
+		wantErrCode:    msgjson.RPCAddressBookError,
+	}, {
+		name:        "bad params",
+		handler:     handleAddressBook,
+		params:      &RawParams{Args: []string{"42"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{addressBookErr: test.addressBookErr}
+		r := &RPCServer{core: tc}
+		payload := test.handler(r, test.params)
+		var res any
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+	}
+}
@@ -2,6 +2,14 @@
 // also available online at https://blueoakcouncil.org/license/1.0.0.
 
 // Package rpcserver provides a JSON RPC to communicate with the client core.
+//
+// Requests are POSTed to the server's root path. The websocket endpoint at /ws
+// provides push notifications. A websocket client sends a 'subscribe' request
+// with payload {"noteTypes": [...]} to receive core notifications of the listed
+// types, e.g. "order", "match", "balance", and "conn", or of all types if the
+// list is empty. Notifications are sent on the 'notify' route until the client
+// sends an 'unsubscribe' request. The 'loadmarket' request subscribes to a
+// market's order book updates.
 package rpcserver
 
 import (
@@ -38,12 +46,16 @@ const (
 	// patch for bug fixes. bwctl requiredRPCSemVer should be kept up to date
 	// with this version.
 	rpcSemverMajor uint32 = 0
-	rpcSemverMinor uint32 = 4
+	rpcSemverMinor uint32 = 5
 	rpcSemverPatch uint32 = 0
 
 	// rpcTimeoutSeconds is the number of seconds a connection to the RPC server
 	// is allowed to stay open without authenticating before it is closed.
 	rpcTimeoutSeconds = 10
+
+	// notifyRoute is the websocket route of the core notifications sent to
+	// clients that subscribed with the 'subscribe' route.
+	notifyRoute = "notify"
 )
 
 var (
@@ -57,23 +69,38 @@ var (
 // clientCore is satisfied by core.Core.
 type clientCore interface {
 	websocket.Core
+	NotificationFeed() *core.NoteFeed
 	AssetBalance(assetID uint32) (*core.WalletBalance, error)
 	Book(host string, base, quote uint32) (orderBook *core.OrderBook, err error)
 	Cancel(orderID dex.Bytes) error
+	PreAccelerateOrder(oidB dex.Bytes) (*core.PreAccelerate, error)
+	AccelerationEstimate(oidB dex.Bytes, newFeeRate uint64) (uint64, error)
+	AccelerateOrder(pw []byte, oidB dex.Bytes, newFeeRate uint64) (string, error)
 	CloseWallet(assetID uint32) error
 	CreateWallet(appPass, walletPass []byte, form *core.WalletForm) error
 	DiscoverAccount(dexAddr string, pass []byte, certI any) (*core.Exchange, bool, error)
 	Exchanges() (exchanges map[string]*core.Exchange)
+	Orders(filter *core.OrderFilter) ([]*core.Order, error)
 	InitializeClient(appPass []byte, seed *string) (string, error)
 	Login(appPass []byte) error
 	Logout() error
 	OpenWallet(assetID uint32, appPass []byte) error
+	ChangeAppPass(appPass, newAppPass []byte) error
+	SetWalletPassword(appPass []byte, assetID uint32, newPW []byte) error
 	ToggleWalletStatus(assetID uint32, disable bool) error
 	GetDEXConfig(dexAddr string, certI any) (*core.Exchange, error)
 	PostBond(form *core.PostBondForm) (*core.PostBondResult, error)
 	UpdateBondOptions(form *core.BondOptionsForm) error
 	Trade(appPass []byte, form *core.TradeForm) (order *core.Order, err error)
+	PreOrder(form *core.TradeForm) (*core.OrderEstimate, error)
+	ValidateTrade(form *core.TradeForm) *core.TradeValidation
+	AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error)
+	AccountImport(pw []byte, acct *core.Account, bonds []*db.Bond) error
+	DBSnapshots() ([]*db.Snapshot, error)
+	RestoreDBSnapshot(pw []byte, name string) error
 	Wallets() (walletsStates []*core.WalletState)
+	PortfolioValue() *core.PortfolioValue
+	LockedBalance(assetID uint32) (*core.LockedBalance, error)
 	WalletState(assetID uint32) *core.WalletState
 	RescanWallet(assetID uint32, force bool) error
 	Send(appPass []byte, assetID uint32, value uint64, addr string, subtract bool) (asset.Coin, error)
@@ -81,6 +108,10 @@ type clientCore interface {
 	DeleteArchivedRecords(olderThan *time.Time, matchesFileStr, ordersFileStr string) (int, error)
 	WalletPeers(assetID uint32) ([]*asset.WalletPeer, error)
 	AddWalletPeer(assetID uint32, host string) error
+	AddressBook(pw []byte, assetID uint32) (*core.AddressBook, error)
+	AddAddressBookEntry(pw []byte, assetID uint32, addr, label string) error
+	RemoveAddressBookEntry(pw []byte, assetID uint32, addr string) error
+	RestrictWithdrawals(pw []byte, assetID uint32, restrict bool) error
 	RemoveWalletPeer(assetID uint32, host string) error
 	Notifications(int) (notes, pokes []*db.Notification, _ error)
 	MultiTrade(pw []byte, form *core.MultiTradeForm) []*core.MultiTradeResult
@@ -299,6 +330,13 @@ func (s *RPCServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 		s.wsServer.HandleConnect(ctx, w, r)
 	})
 
+	// Relay core notifications to subscribed websocket clients.
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.readNotifications(ctx)
+	}()
+
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
@@ -314,6 +352,21 @@ func (s *RPCServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	return &s.wg, nil
 }
 
+// readNotifications reads from the Core notification channel and relays to
+// subscribed websocket clients.
+func (s *RPCServer) readNotifications(ctx context.Context) {
+	feed := s.core.NotificationFeed()
+	defer feed.ReturnFeed()
+	for {
+		select {
+		case n := <-feed.C:
+			s.wsServer.NotifySubscribers(notifyRoute, n)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // handleRequest sends the request to the correct handler function if able.
 func (s *RPCServer) handleRequest(req *msgjson.Message) *msgjson.ResponsePayload {
 	payload := new(msgjson.ResponsePayload)
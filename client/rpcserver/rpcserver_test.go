@@ -42,11 +42,16 @@ type TCore struct {
 	createWalletErr          error
 	newWalletForm            *core.WalletForm
 	openWalletErr            error
+	changeAppPassErr         error
+	setWalletPassErr         error
 	rescanWalletErr          error
 	walletState              *core.WalletState
 	closeWalletErr           error
 	walletStatusErr          error
 	wallets                  []*core.WalletState
+	portfolio                *core.PortfolioValue
+	lockedBalance            *core.LockedBalance
+	lockedBalanceErr         error
 	initializeClientErr      error
 	postBondResult           *core.PostBondResult
 	postBondErr              error
@@ -55,6 +60,24 @@ type TCore struct {
 	loginErr                 error
 	order                    *core.Order
 	tradeErr                 error
+	orderEstimate            *core.OrderEstimate
+	tradeValidation          *core.TradeValidation
+	preOrderErr              error
+	orders                   []*core.Order
+	preAccelerate            *core.PreAccelerate
+	accelerateErr            error
+	ordersErr                error
+	orderFilter              *core.OrderFilter
+	account                  *core.Account
+	accountBonds             []*db.Bond
+	accountExportErr         error
+	accountImportErr         error
+	snapshots                []*db.Snapshot
+	snapshotsErr             error
+	restoredSnapshot         string
+	restoreSnapshotErr       error
+	importedAccount          *core.Account
+	importedBonds            []*db.Bond
 	cancelErr                error
 	coin                     asset.Coin
 	sendErr                  error
@@ -71,6 +94,7 @@ type TCore struct {
 	stakeStatus              *asset.TicketStakingStatus
 	stakeStatusErr           error
 	setVotingPrefErr         error
+	addressBookErr           error
 }
 
 func (c *TCore) Balance(uint32) (uint64, error) {
@@ -117,6 +141,12 @@ func (c *TCore) Logout() error {
 func (c *TCore) OpenWallet(assetID uint32, pw []byte) error {
 	return c.openWalletErr
 }
+func (c *TCore) ChangeAppPass(appPass, newAppPass []byte) error {
+	return c.changeAppPassErr
+}
+func (c *TCore) SetWalletPassword(appPass []byte, assetID uint32, newPW []byte) error {
+	return c.setWalletPassErr
+}
 func (c *TCore) ToggleWalletStatus(assetID uint32, disable bool) error {
 	if c.walletStatusErr != nil {
 		return c.walletStatusErr
@@ -147,6 +177,12 @@ func (c *TCore) Trade(appPass []byte, form *core.TradeForm) (order *core.Order,
 func (c *TCore) Wallets() []*core.WalletState {
 	return c.wallets
 }
+func (c *TCore) PortfolioValue() *core.PortfolioValue {
+	return c.portfolio
+}
+func (c *TCore) LockedBalance(assetID uint32) (*core.LockedBalance, error) {
+	return c.lockedBalance, c.lockedBalanceErr
+}
 func (c *TCore) WalletState(assetID uint32) *core.WalletState {
 	return c.walletState
 }
@@ -174,9 +210,57 @@ func (c *TCore) AddWalletPeer(assetID uint32, address string) error {
 func (c *TCore) RemoveWalletPeer(assetID uint32, address string) error {
 	return nil
 }
+func (c *TCore) AddressBook(pw []byte, assetID uint32) (*core.AddressBook, error) {
+	return &core.AddressBook{AssetID: assetID}, c.addressBookErr
+}
+func (c *TCore) AddAddressBookEntry(pw []byte, assetID uint32, addr, label string) error {
+	return c.addressBookErr
+}
+func (c *TCore) RemoveAddressBookEntry(pw []byte, assetID uint32, addr string) error {
+	return c.addressBookErr
+}
+func (c *TCore) RestrictWithdrawals(pw []byte, assetID uint32, restrict bool) error {
+	return c.addressBookErr
+}
 func (c *TCore) Notifications(n int) (notes, pokes []*db.Notification, _ error) {
 	return nil, nil, nil
 }
+func (c *TCore) PreOrder(form *core.TradeForm) (*core.OrderEstimate, error) {
+	return c.orderEstimate, c.preOrderErr
+}
+func (c *TCore) ValidateTrade(form *core.TradeForm) *core.TradeValidation {
+	return c.tradeValidation
+}
+func (c *TCore) Orders(filter *core.OrderFilter) ([]*core.Order, error) {
+	c.orderFilter = filter
+	return c.orders, c.ordersErr
+}
+func (c *TCore) PreAccelerateOrder(oidB dex.Bytes) (*core.PreAccelerate, error) {
+	return c.preAccelerate, c.accelerateErr
+}
+func (c *TCore) AccelerationEstimate(oidB dex.Bytes, newFeeRate uint64) (uint64, error) {
+	return newFeeRate * 100, c.accelerateErr
+}
+func (c *TCore) AccelerateOrder(pw []byte, oidB dex.Bytes, newFeeRate uint64) (string, error) {
+	return "abcd", c.accelerateErr
+}
+func (c *TCore) NotificationFeed() *core.NoteFeed {
+	return &core.NoteFeed{C: make(chan core.Notification, 1)}
+}
+func (c *TCore) AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error) {
+	return c.account, c.accountBonds, c.accountExportErr
+}
+func (c *TCore) AccountImport(pw []byte, acct *core.Account, bonds []*db.Bond) error {
+	c.importedAccount, c.importedBonds = acct, bonds
+	return c.accountImportErr
+}
+func (c *TCore) DBSnapshots() ([]*db.Snapshot, error) {
+	return c.snapshots, c.snapshotsErr
+}
+func (c *TCore) RestoreDBSnapshot(pw []byte, name string) error {
+	c.restoredSnapshot = name
+	return c.restoreSnapshotErr
+}
 func (c *TCore) MultiTrade(appPass []byte, form *core.MultiTradeForm) []*core.MultiTradeResult {
 	return nil
 }
@@ -211,6 +295,9 @@ func (*tBookFeed) Close() {}
 func (*tBookFeed) Candles(dur string) error {
 	return nil
 }
+func (*tBookFeed) Depth(binWidth uint64) (*orderbook.Depth, error) {
+	return &orderbook.Depth{BinWidth: binWidth}, nil
+}
 
 func newTServer(t *testing.T, start bool, user, pass string) (*RPCServer, func()) {
 	tSrv, fn, err := newTServerWErr(t, start, user, pass)
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/config"
@@ -67,6 +68,14 @@ type tradeResponse struct {
 // myOrdersResponse is used when responding to the myorders route.
 type myOrdersResponse []*myOrder
 
+// orderHistoryResponse is used when responding to the orderhistory route.
+type orderHistoryResponse struct {
+	Orders myOrdersResponse `json:"orders"`
+	// Next is the cursor to request the next page of orders with. Empty if
+	// there are no more orders.
+	Next string `json:"next,omitempty"`
+}
+
 // myOrder represents an order when responding to the myorders route.
 type myOrder struct {
 	Host        string   `json:"host"`
@@ -122,6 +131,19 @@ type openWalletForm struct {
 	appPass encode.PassBytes
 }
 
+// changeAppPassForm is information necessary to change the app password.
+type changeAppPassForm struct {
+	appPass    encode.PassBytes
+	newAppPass encode.PassBytes
+}
+
+// setWalletPassForm is information necessary to update a wallet's password.
+type setWalletPassForm struct {
+	assetID       uint32
+	appPass       encode.PassBytes
+	newWalletPass encode.PassBytes
+}
+
 // walletStatusForm is information necessary to change a wallet's status.
 type walletStatusForm struct {
 	assetID uint32
@@ -161,6 +183,14 @@ type cancelForm struct {
 	orderID dex.Bytes
 }
 
+// accelerateForm is information necessary to estimate or perform the
+// acceleration of an order's swap transactions.
+type accelerateForm struct {
+	appPass    encode.PassBytes
+	orderID    dex.Bytes
+	newFeeRate uint64
+}
+
 // sendOrWithdrawForm is information necessary to send or withdraw funds.
 type sendOrWithdrawForm struct {
 	appPass encode.PassBytes
@@ -184,17 +214,75 @@ type myOrdersForm struct {
 	quote *uint32
 }
 
+// defaultOrderHistoryN is the number of orders returned by the orderhistory
+// route if the filter does not specify n.
+const defaultOrderHistoryN = 50
+
+// orderHistoryFilter is the optional JSON-encoded filter argument of the
+// orderhistory route.
+type orderHistoryFilter struct {
+	Host     string   `json:"host"`
+	Base     *uint32  `json:"base"`
+	Quote    *uint32  `json:"quote"`
+	Side     string   `json:"side"`
+	Statuses []string `json:"statuses"`
+	Since    uint64   `json:"since"`
+	Until    uint64   `json:"until"`
+	N        int      `json:"n"`
+	Cursor   string   `json:"cursor"`
+}
+
 type deleteRecordsForm struct {
 	olderThan                     *time.Time
 	ordersFileStr, matchesFileStr string
 }
 
+// exportAccountForm is the information necessary to export a DEX account to a
+// file.
+type exportAccountForm struct {
+	appPass encode.PassBytes
+	host    string
+	path    string
+}
+
+// importAccountForm is the information necessary to import a DEX account from
+// a file.
+type importAccountForm struct {
+	appPass encode.PassBytes
+	path    string
+}
+
+// restoreDBSnapshotForm is the information necessary to restore a DB
+// snapshot.
+type restoreDBSnapshotForm struct {
+	appPass encode.PassBytes
+	name    string
+}
+
+// accountFile is the format of an exported account file. It matches the file
+// exported from the browser, in which the bonds are listed with the account
+// fields.
+type accountFile struct {
+	*core.Account
+	Bonds []*db.Bond `json:"bonds"`
+}
+
 // addRemovePeerForm is the information necessary to add or remove a wallet peer.
 type addRemovePeerForm struct {
 	assetID uint32
 	address string
 }
 
+// addressBookForm is the information necessary to view or modify a
+// withdrawal address book.
+type addressBookForm struct {
+	appPass  encode.PassBytes
+	assetID  uint32
+	address  string
+	label    string
+	restrict bool
+}
+
 type mmAvailableBalancesForm struct {
 	cfgFilePath string
 	mkt         *mm.MarketWithHost
@@ -280,6 +368,14 @@ func checkUIntArg(arg, name string, bitSize int) (uint64, error) {
 	return i, nil
 }
 
+func checkFloatArg(arg, name string) (float64, error) {
+	f, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return f, fmt.Errorf("%w: cannot parse %s: %v", errArgs, name, err)
+	}
+	return f, nil
+}
+
 func checkBoolArg(arg, name string) (bool, error) {
 	b, err := strconv.ParseBool(arg)
 	if err != nil {
@@ -406,6 +502,36 @@ func parseOpenWalletArgs(params *RawParams) (*openWalletForm, error) {
 	return req, nil
 }
 
+func parseChangeAppPassArgs(params *RawParams) (*changeAppPassForm, error) {
+	if err := checkNArgs(params, []int{2}, []int{0}); err != nil {
+		return nil, err
+	}
+	if len(params.PWArgs[1]) == 0 {
+		return nil, fmt.Errorf("%w: new app password cannot be empty", errArgs)
+	}
+	return &changeAppPassForm{appPass: params.PWArgs[0], newAppPass: params.PWArgs[1]}, nil
+}
+
+func parseSetWalletPassArgs(params *RawParams) (*setWalletPassForm, error) {
+	if err := checkNArgs(params, []int{2}, []int{1}); err != nil {
+		return nil, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return nil, err
+	}
+	newWalletPass := params.PWArgs[1]
+	if newWalletPass == nil {
+		// Core takes a non-nil empty password to mean no password.
+		newWalletPass = encode.PassBytes{}
+	}
+	return &setWalletPassForm{
+		assetID:       uint32(assetID),
+		appPass:       params.PWArgs[0],
+		newWalletPass: newWalletPass,
+	}, nil
+}
+
 func parseCloseWalletArgs(params *RawParams) (uint32, error) {
 	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
 		return 0, err
@@ -558,56 +684,99 @@ func parsePostBondArgs(params *RawParams) (*core.PostBondForm, error) {
 }
 
 func parseTradeArgs(params *RawParams) (*tradeForm, error) {
-	if err := checkNArgs(params, []int{1}, []int{9}); err != nil {
+	if err := checkNArgs(params, []int{1}, []int{9, 12}); err != nil {
+		return nil, err
+	}
+	form, err := parseTradeFormArgs(params.Args)
+	if err != nil {
+		return nil, err
+	}
+	// RPC trades are not interactive, so there is no opportunity to confirm
+	// an order on a low-liquidity market.
+	form.AcceptLowLiquidity = true
+	req := &tradeForm{
+		appPass: params.PWArgs[0],
+		srvForm: form,
+	}
+	return req, nil
+}
+
+func parsePreOrderArgs(params *RawParams) (*core.TradeForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{9, 12}); err != nil {
 		return nil, err
 	}
-	isLimit, err := checkBoolArg(params.Args[1], "isLimit")
+	return parseTradeFormArgs(params.Args)
+}
+
+// parseTradeFormArgs parses the arguments shared by the trade, preorder, and
+// validatetrade routes.
+func parseTradeFormArgs(args []string) (*core.TradeForm, error) {
+	isLimit, err := checkBoolArg(args[1], "isLimit")
 	if err != nil {
 		return nil, err
 	}
-	sell, err := checkBoolArg(params.Args[2], "sell")
+	sell, err := checkBoolArg(args[2], "sell")
 	if err != nil {
 		return nil, err
 	}
-	base, err := checkUIntArg(params.Args[3], "base", 32)
+	base, err := checkUIntArg(args[3], "base", 32)
 	if err != nil {
 		return nil, err
 	}
-	quote, err := checkUIntArg(params.Args[4], "quote", 32)
+	quote, err := checkUIntArg(args[4], "quote", 32)
 	if err != nil {
 		return nil, err
 	}
-	qty, err := checkUIntArg(params.Args[5], "qty", 64)
+	qty, err := checkUIntArg(args[5], "qty", 64)
 	if err != nil {
 		return nil, err
 	}
-	rate, err := checkUIntArg(params.Args[6], "rate", 64)
+	rate, err := checkUIntArg(args[6], "rate", 64)
 	if err != nil {
 		return nil, err
 	}
-	tifnow, err := checkBoolArg(params.Args[7], "immediate")
+	tifnow, err := checkBoolArg(args[7], "immediate")
 	if err != nil {
 		return nil, err
 	}
-	options, err := checkMapArg(params.Args[8], "options")
+	options, err := checkMapArg(args[8], "options")
 	if err != nil {
 		return nil, err
 	}
-	req := &tradeForm{
-		appPass: params.PWArgs[0],
-		srvForm: &core.TradeForm{
-			Host:    params.Args[0],
-			IsLimit: isLimit,
-			Sell:    sell,
-			Base:    uint32(base),
-			Quote:   uint32(quote),
-			Qty:     qty,
-			Rate:    rate,
-			TifNow:  tifnow,
-			Options: options,
-		},
+	var worstRate, ttl uint64
+	var maxDrift float64
+	if len(args) > 9 {
+		worstRate, err = checkUIntArg(args[9], "worstRate", 64)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return req, nil
+	if len(args) > 10 {
+		ttl, err = checkUIntArg(args[10], "ttl", 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(args) > 11 {
+		maxDrift, err = checkFloatArg(args[11], "maxDrift")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &core.TradeForm{
+		Host:      args[0],
+		IsLimit:   isLimit,
+		Sell:      sell,
+		Base:      uint32(base),
+		Quote:     uint32(quote),
+		Qty:       qty,
+		Rate:      rate,
+		TifNow:    tifnow,
+		Options:   options,
+		WorstRate: worstRate,
+		TTL:       ttl,
+		MaxDrift:  maxDrift,
+	}, nil
 }
 
 func parseMultiTradeArgs(params *RawParams) (*multiTradeForm, error) {
@@ -684,6 +853,61 @@ func parseCancelArgs(params *RawParams) (*cancelForm, error) {
 	return &cancelForm{orderID: oidB}, nil
 }
 
+// checkOrderIDArg decodes a hex order ID.
+func checkOrderIDArg(id string) (dex.Bytes, error) {
+	if len(id) != orderIdLen {
+		return nil, fmt.Errorf("%w: orderID has incorrect length", errArgs)
+	}
+	oidB, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid order id hex", errArgs)
+	}
+	return oidB, nil
+}
+
+func parsePreAccelerateArgs(params *RawParams) (dex.Bytes, error) {
+	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
+		return nil, err
+	}
+	return checkOrderIDArg(params.Args[0])
+}
+
+func parseAccelerationEstimateArgs(params *RawParams) (*accelerateForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{2}); err != nil {
+		return nil, err
+	}
+	return parseAccelerateFormArgs(params.Args)
+}
+
+func parseAccelerateOrderArgs(params *RawParams) (*accelerateForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{2}); err != nil {
+		return nil, err
+	}
+	form, err := parseAccelerateFormArgs(params.Args)
+	if err != nil {
+		return nil, err
+	}
+	form.appPass = params.PWArgs[0]
+	return form, nil
+}
+
+// parseAccelerateFormArgs parses the orderID and newFeeRate arguments shared by
+// the accelerationestimate and accelerateorder routes.
+func parseAccelerateFormArgs(args []string) (*accelerateForm, error) {
+	oidB, err := checkOrderIDArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	newFeeRate, err := checkUIntArg(args[1], "newFeeRate", 64)
+	if err != nil {
+		return nil, err
+	}
+	if newFeeRate == 0 {
+		return nil, fmt.Errorf("%w: newFeeRate must be positive", errArgs)
+	}
+	return &accelerateForm{orderID: oidB, newFeeRate: newFeeRate}, nil
+}
+
 func parseSendOrWithdrawArgs(params *RawParams) (*sendOrWithdrawForm, error) {
 	if err := checkNArgs(params, []int{1}, []int{3}); err != nil {
 		return nil, err
@@ -790,6 +1014,77 @@ func parseMyOrdersArgs(params *RawParams) (*myOrdersForm, error) {
 	return req, nil
 }
 
+func parseOrderHistoryArgs(params *RawParams) (*core.OrderFilter, error) {
+	if err := checkNArgs(params, []int{0}, []int{0, 1}); err != nil {
+		return nil, err
+	}
+	var f orderHistoryFilter
+	if len(params.Args) > 0 {
+		if err := json.Unmarshal([]byte(params.Args[0]), &f); err != nil {
+			return nil, fmt.Errorf("%w: invalid filter: %v", errArgs, err)
+		}
+	}
+	if f.N < 0 {
+		return nil, fmt.Errorf("%w: n cannot be negative", errArgs)
+	}
+	if f.Until > 0 && f.Until < f.Since {
+		return nil, fmt.Errorf("%w: until is before since", errArgs)
+	}
+	filter := &core.OrderFilter{
+		N:     f.N,
+		Since: f.Since,
+		Until: f.Until,
+	}
+	if filter.N == 0 {
+		filter.N = defaultOrderHistoryN
+	}
+	if f.Host != "" {
+		filter.Hosts = []string{f.Host}
+	}
+	if (f.Base == nil) != (f.Quote == nil) {
+		return nil, fmt.Errorf("%w: base and quote must be specified together", errArgs)
+	}
+	if f.Base != nil {
+		filter.Market = &struct {
+			Base  uint32 `json:"baseID"`
+			Quote uint32 `json:"quoteID"`
+		}{*f.Base, *f.Quote}
+	}
+	switch f.Side {
+	case "":
+	case "buy", "sell":
+		sell := f.Side == "sell"
+		filter.Sell = &sell
+	default:
+		return nil, fmt.Errorf("%w: unknown side %q", errArgs, f.Side)
+	}
+	for _, s := range f.Statuses {
+		status, err := parseOrderStatus(s)
+		if err != nil {
+			return nil, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	if f.Cursor != "" {
+		oid, err := hex.DecodeString(f.Cursor)
+		if err != nil || len(oid) != order.OrderIDSize {
+			return nil, fmt.Errorf("%w: invalid cursor %q", errArgs, f.Cursor)
+		}
+		filter.Offset = oid
+	}
+	return filter, nil
+}
+
+// parseOrderStatus parses an order status from its string representation.
+func parseOrderStatus(s string) (order.OrderStatus, error) {
+	for status := order.OrderStatusEpoch; status <= order.OrderStatusRevoked; status++ {
+		if status.String() == s {
+			return status, nil
+		}
+	}
+	return order.OrderStatusUnknown, fmt.Errorf("%w: unknown order status %q", errArgs, s)
+}
+
 func parseAppSeedArgs(params *RawParams) (encode.PassBytes, error) {
 	if err := checkNArgs(params, []int{1}, []int{0}); err != nil {
 		return nil, err
@@ -835,6 +1130,17 @@ func parseWalletPeersArgs(params *RawParams) (uint32, error) {
 	return uint32(assetID), nil
 }
 
+func parseLockedBalanceArgs(params *RawParams) (uint32, error) {
+	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
+		return 0, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(assetID), nil
+}
+
 func parseAddRemoveWalletPeerArgs(params *RawParams) (form *addRemovePeerForm, err error) {
 	if err = checkNArgs(params, []int{0}, []int{2}); err != nil {
 		return nil, err
@@ -849,6 +1155,49 @@ func parseAddRemoveWalletPeerArgs(params *RawParams) (form *addRemovePeerForm, e
 	return form, nil
 }
 
+// parseAddressBookArgs parses the password and the assetID, address and label
+// arguments shared by the address book routes. nArgs are the acceptable
+// numbers of non-password arguments.
+func parseAddressBookArgs(params *RawParams, nArgs ...int) (*addressBookForm, error) {
+	if err := checkNArgs(params, []int{1}, nArgs); err != nil {
+		return nil, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return nil, err
+	}
+	form := &addressBookForm{
+		appPass: params.PWArgs[0],
+		assetID: uint32(assetID),
+	}
+	if len(params.Args) > 1 {
+		form.address = params.Args[1]
+	}
+	if len(params.Args) > 2 {
+		form.label = params.Args[2]
+	}
+	return form, nil
+}
+
+func parseRestrictWithdrawalsArgs(params *RawParams) (*addressBookForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{2}); err != nil {
+		return nil, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return nil, err
+	}
+	restrict, err := checkBoolArg(params.Args[1], "restrict")
+	if err != nil {
+		return nil, err
+	}
+	return &addressBookForm{
+		appPass:  params.PWArgs[0],
+		assetID:  uint32(assetID),
+		restrict: restrict,
+	}, nil
+}
+
 func parseNotificationsArgs(params *RawParams) (int, error) {
 	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
 		return 0, err
@@ -1168,3 +1517,34 @@ func parseWalletTxArgs(params *RawParams) (*walletTxForm, error) {
 		txID:    params.Args[1],
 	}, nil
 }
+
+func parseExportAccountArgs(params *RawParams) (*exportAccountForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{2}); err != nil {
+		return nil, err
+	}
+	return &exportAccountForm{
+		appPass: params.PWArgs[0],
+		host:    params.Args[0],
+		path:    dex.CleanAndExpandPath(params.Args[1]),
+	}, nil
+}
+
+func parseRestoreDBSnapshotArgs(params *RawParams) (*restoreDBSnapshotForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{1}); err != nil {
+		return nil, err
+	}
+	return &restoreDBSnapshotForm{
+		appPass: params.PWArgs[0],
+		name:    params.Args[0],
+	}, nil
+}
+
+func parseImportAccountArgs(params *RawParams) (*importAccountForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{1}); err != nil {
+		return nil, err
+	}
+	return &importAccountForm{
+		appPass: params.PWArgs[0],
+		path:    dex.CleanAndExpandPath(params.Args[0]),
+	}, nil
+}
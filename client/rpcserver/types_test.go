@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/order"
 )
 
 func TestCheckNArgs(t *testing.T) {
@@ -164,6 +165,25 @@ func TestParseOpenWalletArgs(t *testing.T) {
 	}
 }
 
+func TestParseSetWalletPassArgs(t *testing.T) {
+	appPW := encode.PassBytes("password123")
+	form, err := parseSetWalletPassArgs(&RawParams{PWArgs: []encode.PassBytes{appPW, nil}, Args: []string{"42"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if form.assetID != 42 || !bytes.Equal(form.appPass, appPW) {
+		t.Fatalf("wrong form %+v", form)
+	}
+	// A missing new password means no password, which core expects to be
+	// empty but not nil.
+	if form.newWalletPass == nil || len(form.newWalletPass) != 0 {
+		t.Fatalf("wanted empty new password, got %v", form.newWalletPass)
+	}
+	if _, err := parseSetWalletPassArgs(&RawParams{PWArgs: []encode.PassBytes{appPW, nil}, Args: []string{"x"}}); !errors.Is(err, errArgs) {
+		t.Fatalf("wrong error for bad asset ID: %v", err)
+	}
+}
+
 func TestCheckUIntArg(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -393,6 +413,46 @@ func TestTradeArgs(t *testing.T) {
 		name:    "options not map[string]string",
 		params:  paramsWith(8, "blue"),
 		wantErr: errArgs,
+	}, {
+		name: "worst rate",
+		params: &RawParams{
+			PWArgs: goodParams.PWArgs,
+			Args:   append(append([]string{}, goodParams.Args...), "5000"),
+		},
+	}, {
+		name: "worst rate not uint64",
+		params: &RawParams{
+			PWArgs: goodParams.PWArgs,
+			Args:   append(append([]string{}, goodParams.Args...), "-1"),
+		},
+		wantErr: errArgs,
+	}, {
+		name: "ttl and max drift",
+		params: &RawParams{
+			PWArgs: goodParams.PWArgs,
+			Args:   append(append([]string{}, goodParams.Args...), "0", "3600", "2.5"),
+		},
+	}, {
+		name: "ttl not uint64",
+		params: &RawParams{
+			PWArgs: goodParams.PWArgs,
+			Args:   append(append([]string{}, goodParams.Args...), "0", "-1"),
+		},
+		wantErr: errArgs,
+	}, {
+		name: "max drift not float",
+		params: &RawParams{
+			PWArgs: goodParams.PWArgs,
+			Args:   append(append([]string{}, goodParams.Args...), "0", "3600", "blue"),
+		},
+		wantErr: errArgs,
+	}, {
+		name: "too many args",
+		params: &RawParams{
+			PWArgs: goodParams.PWArgs,
+			Args:   append(append([]string{}, goodParams.Args...), "0", "3600", "2.5", "1"),
+		},
+		wantErr: errArgs,
 	}}
 	for _, test := range tests {
 		reg, err := parseTradeArgs(test.params)
@@ -442,6 +502,15 @@ func TestTradeArgs(t *testing.T) {
 		if wantOptions != test.params.Args[8] {
 			t.Fatalf("Options doesn't match")
 		}
+		if len(test.params.Args) > 9 && fmt.Sprint(reg.srvForm.WorstRate) != test.params.Args[9] {
+			t.Fatalf("WorstRate doesn't match")
+		}
+		if len(test.params.Args) > 10 && fmt.Sprint(reg.srvForm.TTL) != test.params.Args[10] {
+			t.Fatalf("TTL doesn't match")
+		}
+		if len(test.params.Args) > 11 && fmt.Sprint(reg.srvForm.MaxDrift) != test.params.Args[11] {
+			t.Fatalf("MaxDrift doesn't match")
+		}
 	}
 }
 
@@ -482,6 +551,61 @@ func TestParseCancelArgs(t *testing.T) {
 	}
 }
 
+func TestParseAccelerateArgs(t *testing.T) {
+	oid := "fb94fe99e4e32200a341f0f1cb33f34a08ac23eedab636e8adb991fa76343e1e"
+	pw := []encode.PassBytes{encode.PassBytes("abc")}
+	tests := []struct {
+		name    string
+		params  *RawParams
+		wantErr error
+	}{{
+		name:   "ok",
+		params: &RawParams{PWArgs: pw, Args: []string{oid, "50"}},
+	}, {
+		name:    "order ID too short",
+		params:  &RawParams{PWArgs: pw, Args: []string{oid[2:], "50"}},
+		wantErr: errArgs,
+	}, {
+		name:    "order ID not hex",
+		params:  &RawParams{PWArgs: pw, Args: []string{"zz" + oid[2:], "50"}},
+		wantErr: errArgs,
+	}, {
+		name:    "fee rate not a number",
+		params:  &RawParams{PWArgs: pw, Args: []string{oid, "fast"}},
+		wantErr: errArgs,
+	}, {
+		name:    "zero fee rate",
+		params:  &RawParams{PWArgs: pw, Args: []string{oid, "0"}},
+		wantErr: errArgs,
+	}, {
+		name:    "no fee rate",
+		params:  &RawParams{PWArgs: pw, Args: []string{oid}},
+		wantErr: errArgs,
+	}}
+	for _, test := range tests {
+		form, err := parseAccelerateOrderArgs(test.params)
+		if test.wantErr != nil {
+			if errors.Is(err, test.wantErr) {
+				continue
+			}
+			t.Fatalf("expected error for test %v", test.name)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error %v for test %s", err, test.name)
+		}
+		if form.orderID.String() != oid || form.newFeeRate != 50 || !bytes.Equal(form.appPass, pw[0]) {
+			t.Fatalf("%s: wrong form %+v", test.name, form)
+		}
+		// The estimate takes the same args without a password.
+		if _, err := parseAccelerationEstimateArgs(&RawParams{Args: test.params.Args}); err != nil {
+			t.Fatalf("%s: unexpected estimate error %v", test.name, err)
+		}
+		if _, err := parsePreAccelerateArgs(&RawParams{Args: test.params.Args[:1]}); err != nil {
+			t.Fatalf("%s: unexpected preaccelerate error %v", test.name, err)
+		}
+	}
+}
+
 func TestParseSendOrWithdrawArgs(t *testing.T) {
 	paramsWithArgs := func(id, value string) *RawParams {
 		pw := encode.PassBytes("password123")
@@ -650,6 +774,91 @@ func TestMyOrdersArgs(t *testing.T) {
 	}
 }
 
+func TestOrderHistoryArgs(t *testing.T) {
+	paramsWithArgs := func(ss ...string) *RawParams {
+		args := []string{}
+		args = append(args, ss...)
+		return &RawParams{Args: args}
+	}
+	cursor := "e2fe7b28eae9a4511013ecb35be58e8031e5f7ec9f3a0e2f6411ec58efd4464a"
+	tests := []struct {
+		name    string
+		params  *RawParams
+		wantErr error
+	}{{
+		name:   "ok no params",
+		params: paramsWithArgs(),
+	}, {
+		name: "ok all fields",
+		params: paramsWithArgs(`{"host":"host","base":42,"quote":0,"side":"sell","statuses":["executed","canceled"],` +
+			`"since":1000,"until":2000,"n":10,"cursor":"` + cursor + `"}`),
+	}, {
+		name:    "not json",
+		params:  paramsWithArgs("host"),
+		wantErr: errArgs,
+	}, {
+		name:    "base but no quote",
+		params:  paramsWithArgs(`{"base":42}`),
+		wantErr: errArgs,
+	}, {
+		name:    "bad side",
+		params:  paramsWithArgs(`{"side":"long"}`),
+		wantErr: errArgs,
+	}, {
+		name:    "bad status",
+		params:  paramsWithArgs(`{"statuses":["matched"]}`),
+		wantErr: errArgs,
+	}, {
+		name:    "until before since",
+		params:  paramsWithArgs(`{"since":2000,"until":1000}`),
+		wantErr: errArgs,
+	}, {
+		name:    "negative n",
+		params:  paramsWithArgs(`{"n":-1}`),
+		wantErr: errArgs,
+	}, {
+		name:    "bad cursor",
+		params:  paramsWithArgs(`{"cursor":"abcd"}`),
+		wantErr: errArgs,
+	}}
+	for _, test := range tests {
+		res, err := parseOrderHistoryArgs(test.params)
+		if test.wantErr != nil {
+			if errors.Is(err, test.wantErr) {
+				continue
+			}
+			t.Fatalf("expected error for test %v", test.name)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error %v for test %s", err, test.name)
+		}
+		if len(test.params.Args) == 0 {
+			if res.N != defaultOrderHistoryN || res.Sell != nil || res.Market != nil || len(res.Offset) != 0 {
+				t.Fatalf("%s: unexpected filter %+v", test.name, res)
+			}
+			continue
+		}
+		if res.N != 10 || len(res.Hosts) != 1 || res.Hosts[0] != "host" {
+			t.Fatalf("%s: wrong n or hosts", test.name)
+		}
+		if res.Market == nil || res.Market.Base != 42 || res.Market.Quote != 0 {
+			t.Fatalf("%s: wrong market", test.name)
+		}
+		if res.Sell == nil || !*res.Sell {
+			t.Fatalf("%s: wrong side", test.name)
+		}
+		if len(res.Statuses) != 2 || res.Statuses[0] != order.OrderStatusExecuted || res.Statuses[1] != order.OrderStatusCanceled {
+			t.Fatalf("%s: wrong statuses %v", test.name, res.Statuses)
+		}
+		if res.Since != 1000 || res.Until != 2000 {
+			t.Fatalf("%s: wrong time range", test.name)
+		}
+		if res.Offset.String() != cursor {
+			t.Fatalf("%s: wrong offset %s", test.name, res.Offset)
+		}
+	}
+}
+
 func TestParseAppSeedArgs(t *testing.T) {
 	pw := encode.PassBytes("password123")
 	pwArgs := []encode.PassBytes{pw}
@@ -151,6 +151,28 @@ func (s *WebServer) apiGetWalletPeers(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, resp)
 }
 
+// apiLockedBalance is the handler for the '/lockedbalance' API request.
+func (s *WebServer) apiLockedBalance(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		AssetID uint32 `json:"assetID"`
+	}
+	if !readPost(w, r, &form) {
+		return
+	}
+	lb, err := s.core.LockedBalance(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK            bool                `json:"ok"`
+		LockedBalance *core.LockedBalance `json:"lockedBalance"`
+	}{
+		OK:            true,
+		LockedBalance: lb,
+	})
+}
+
 // apiAddWalletPeer is the handler for the '/addwalletpeer' API request.
 func (s *WebServer) apiAddWalletPeer(w http.ResponseWriter, r *http.Request) {
 	var form struct {
@@ -185,6 +207,113 @@ func (s *WebServer) apiRemoveWalletPeer(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, simpleAck())
 }
 
+// apiAddressBook is the handler for the '/addressbook' API request.
+func (s *WebServer) apiAddressBook(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32           `json:"assetID"`
+		Pass    encode.PassBytes `json:"pw"`
+	}{}
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+	book, err := s.core.AddressBook(pass, form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error retrieving address book: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool              `json:"ok"`
+		Book *core.AddressBook `json:"book"`
+	}{
+		OK:   true,
+		Book: book,
+	})
+}
+
+// apiAddAddress is the handler for the '/addaddress' API request.
+func (s *WebServer) apiAddAddress(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32           `json:"assetID"`
+		Address string           `json:"address"`
+		Label   string           `json:"label"`
+		Pass    encode.PassBytes `json:"pw"`
+	}{}
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+	err = s.core.AddAddressBookEntry(pass, form.AssetID, form.Address, form.Label)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiRemoveAddress is the handler for the '/removeaddress' API request.
+func (s *WebServer) apiRemoveAddress(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32           `json:"assetID"`
+		Address string           `json:"address"`
+		Pass    encode.PassBytes `json:"pw"`
+	}{}
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+	err = s.core.RemoveAddressBookEntry(pass, form.AssetID, form.Address)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiRestrictWithdrawals is the handler for the '/restrictwithdrawals' API
+// request.
+func (s *WebServer) apiRestrictWithdrawals(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID  uint32           `json:"assetID"`
+		Restrict bool             `json:"restrict"`
+		Pass     encode.PassBytes `json:"pw"`
+	}{}
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+	err = s.core.RestrictWithdrawals(pass, form.AssetID, form.Restrict)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 func (s *WebServer) apiApproveTokenFee(w http.ResponseWriter, r *http.Request) {
 	var form struct {
 		AssetID  uint32 `json:"assetID"`
@@ -738,6 +867,46 @@ func (s *WebServer) apiExportSeed(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// apiDBSnapshots is the handler for the '/dbsnapshots' API request.
+func (s *WebServer) apiDBSnapshots(w http.ResponseWriter, r *http.Request) {
+	snaps, err := s.core.DBSnapshots()
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error listing DB snapshots: %w", err))
+		return
+	}
+	if snaps == nil {
+		snaps = make([]*db.Snapshot, 0) // marshal to [], not null
+	}
+	writeJSON(w, &struct {
+		OK        bool           `json:"ok"`
+		Snapshots []*db.Snapshot `json:"snapshots"`
+	}{
+		OK:        true,
+		Snapshots: snaps,
+	})
+}
+
+// apiRestoreDBSnapshot is the handler for the '/restoredbsnapshot' API
+// request. The snapshot replaces the DB on the next startup.
+func (s *WebServer) apiRestoreDBSnapshot(w http.ResponseWriter, r *http.Request) {
+	form := new(restoreDBSnapshotForm)
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+	if err := s.core.RestoreDBSnapshot(pass, form.Name); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error restoring DB snapshot: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 // apiAccountImport is the handler for the '/importaccount' API request.
 func (s *WebServer) apiAccountImport(w http.ResponseWriter, r *http.Request) {
 	form := new(accountImportForm)
@@ -1357,6 +1526,37 @@ func (s *WebServer) apiResetAppPassword(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, simpleAck())
 }
 
+// apiSetWalletPass updates the password stored for a wallet, without changing
+// the wallet's configuration. The new password must already be set on the
+// wallet. An empty password is for a wallet without a password.
+func (s *WebServer) apiSetWalletPass(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID     uint32           `json:"assetID"`
+		AppPW       encode.PassBytes `json:"appPW"`
+		NewWalletPW encode.PassBytes `json:"newWalletPW"`
+	}{}
+	defer form.AppPW.Clear()
+	defer form.NewWalletPW.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	pass, err := s.resolvePass(form.AppPW, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+	newPW := form.NewWalletPW
+	if newPW == nil {
+		newPW = encode.PassBytes{}
+	}
+	if err := s.core.SetWalletPassword(pass, form.AssetID, newPW); err != nil {
+		s.writeAPIError(w, fmt.Errorf("set wallet password error: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 // apiReconfig sets new configuration details for the wallet.
 func (s *WebServer) apiReconfig(w http.ResponseWriter, r *http.Request) {
 	form := &struct {
@@ -1500,6 +1700,22 @@ func (s *WebServer) apiPreOrder(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, resp)
 }
 
+// apiValidateTrade handles the 'validatetrade' API request. The order is
+// checked but not placed. The response is OK even if the order is invalid.
+func (s *WebServer) apiValidateTrade(w http.ResponseWriter, r *http.Request) {
+	form := new(core.TradeForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	writeJSON(w, &struct {
+		OK         bool                  `json:"ok"`
+		Validation *core.TradeValidation `json:"validation"`
+	}{
+		OK:         true,
+		Validation: s.core.ValidateTrade(form),
+	})
+}
+
 // apiActuallyLogin logs the user in. login form private data is expected to be
 // cleared by the caller.
 func (s *WebServer) actuallyLogin(w http.ResponseWriter, r *http.Request, login *loginForm) error {
@@ -1575,6 +1791,17 @@ func (s *WebServer) apiToggleRateSource(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, simpleAck())
 }
 
+// apiPortfolio handles the /portfolio API request.
+func (s *WebServer) apiPortfolio(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		OK        bool                 `json:"ok"`
+		Portfolio *core.PortfolioValue `json:"portfolio"`
+	}{
+		OK:        true,
+		Portfolio: s.core.PortfolioValue(),
+	})
+}
+
 // apiDeleteArchiveRecords handles the '/deletearchivedrecords' API request.
 func (s *WebServer) apiDeleteArchivedRecords(w http.ResponseWriter, r *http.Request) {
 	form := new(deleteRecordsForm)
@@ -2090,6 +2317,10 @@ func (s *WebServer) writeAPIError(w http.ResponseWriter, err error) {
 		Msg:  innerErr.Error(),
 		Code: code,
 	}
+	var stepErr *core.OrderStepError
+	if errors.As(err, &stepErr) {
+		resp.StepHint = stepErr.OrderStepHint
+	}
 	log.Error(err.Error())
 	writeJSON(w, resp)
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"sort"
 	"strconv"
@@ -22,20 +23,21 @@ import (
 )
 
 const (
-	homeRoute        = "/"
-	registerRoute    = "/register"
-	initRoute        = "/init"
-	loginRoute       = "/login"
-	marketsRoute     = "/markets"
-	walletsRoute     = "/wallets"
-	walletLogRoute   = "/wallets/logfile"
-	settingsRoute    = "/settings"
-	ordersRoute      = "/orders"
-	exportOrderRoute = "/orders/export"
-	marketMakerRoute = "/mm"
-	mmSettingsRoute  = "/mmsettings"
-	mmArchivesRoute  = "/mmarchives"
-	mmLogsRoute      = "/mmlogs"
+	homeRoute         = "/"
+	registerRoute     = "/register"
+	initRoute         = "/init"
+	loginRoute        = "/login"
+	marketsRoute      = "/markets"
+	walletsRoute      = "/wallets"
+	walletLogRoute    = "/wallets/logfile"
+	settingsRoute     = "/settings"
+	ordersRoute       = "/orders"
+	exportOrderRoute  = "/orders/export"
+	exportTradesRoute = "/trades/export"
+	marketMakerRoute  = "/mm"
+	mmSettingsRoute   = "/mmsettings"
+	mmArchivesRoute   = "/mmarchives"
+	mmLogsRoute       = "/mmlogs"
 )
 
 // sendTemplate processes the template and sends the result.
@@ -463,6 +465,52 @@ func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// tradeHistoryRange parses the time range of a trade history export from the
+// since and until query parameters, which are optional unix times in
+// milliseconds.
+func tradeHistoryRange(q url.Values) (since, until time.Time, err error) {
+	parse := func(k string) (time.Time, error) {
+		v := q.Get(k)
+		if v == "" {
+			return time.Time{}, nil
+		}
+		ms, err := strconv.ParseUint(v, 10, 63)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s time %q", k, v)
+		}
+		return time.UnixMilli(int64(ms)), nil
+	}
+	if since, err = parse("since"); err != nil {
+		return
+	}
+	if until, err = parse("until"); err != nil {
+		return
+	}
+	if !until.IsZero() && until.Before(since) {
+		err = fmt.Errorf("until time is before since time")
+	}
+	return
+}
+
+// handleExportTradeHistory is the handler for the /trades/export page request.
+// The completed matches of all orders are downloaded as a CSV file.
+func (s *WebServer) handleExportTradeHistory(w http.ResponseWriter, r *http.Request) {
+	since, until, err := tradeHistoryRange(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", "attachment; filename=trade_history.csv")
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+	csvWriter := csv.NewWriter(w)
+	csvWriter.UseCRLF = strings.Contains(r.UserAgent(), "Windows")
+	if _, err := s.core.ExportTradeHistory(csvWriter, since, until); err != nil {
+		log.Errorf("error exporting trade history: %v", err)
+	}
+}
+
 type orderTmplData struct {
 	CommonArguments
 	Order *core.OrderReader
@@ -219,6 +219,8 @@ const (
 	idCausesSelfMatch                = "CAUSES_SELF_MATCH"
 	idCexNotConnected                = "CEX_NOT_CONNECTED"
 	idDeleteBot                      = "DELETE_BOT"
+	idBandedQty                      = "BANDED_QTY"
+	idUseStepValue                   = "USE_STEP_VALUE"
 )
 
 var enUS = map[string]*intl.Translation{
@@ -437,6 +439,8 @@ var enUS = map[string]*intl.Translation{
 	idCausesSelfMatch:                {T: "This order would cause a self-match"},
 	idCexNotConnected:                {T: "{{ cexName }} not connected"},
 	idDeleteBot:                      {T: "Are you sure you want to delete this bot for the {{ baseTicker }}-{{ quoteTicker }} market on {{ host }}?"},
+	idBandedQty:                      {T: "The server rounds large orders down to a multiple of {{ band }}. The true quantity may be larger."},
+	idUseStepValue:                   {T: "Use {{ value }}"},
 }
 
 var ptBR = map[string]*intl.Translation{
@@ -9,6 +9,7 @@ package webserver
 import (
 	"context"
 	"encoding/binary"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -443,6 +444,10 @@ func (t *tBookFeed) Candles(dur string) error {
 	return nil
 }
 
+func (t *tBookFeed) Depth(binWidth uint64) (*orderbook.Depth, error) {
+	return &orderbook.Depth{BinWidth: binWidth}, nil
+}
+
 type TCore struct {
 	inited    bool
 	mtx       sync.RWMutex
@@ -766,6 +771,10 @@ func (c *TCore) PreOrder(*core.TradeForm) (*core.OrderEstimate, error) {
 	}, nil
 }
 
+func (c *TCore) ValidateTrade(*core.TradeForm) *core.TradeValidation {
+	return &core.TradeValidation{Valid: true, Errors: []*core.TradeValidationError{}}
+}
+
 func (c *TCore) AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error) {
 	return nil, nil, nil
 }
@@ -1900,6 +1909,12 @@ func (c *TCore) runRandomNotes() {
 	}
 }
 
+func (c *TCore) DBSnapshots() ([]*db.Snapshot, error) {
+	return nil, nil
+}
+func (c *TCore) RestoreDBSnapshot(pw []byte, name string) error {
+	return nil
+}
 func (c *TCore) ExportSeed(pw []byte) (string, error) {
 	return "copper life simple hello fit manage dune curve argue gadget erosion fork theme chase broccoli", nil
 }
@@ -1925,18 +1940,39 @@ func (c *TCore) ToggleRateSourceStatus(src string, disable bool) error {
 func (c *TCore) FiatRateSources() map[string]bool {
 	return c.fiatSources
 }
+func (c *TCore) PortfolioValue() *core.PortfolioValue {
+	return &core.PortfolioValue{}
+}
 func (c *TCore) DeleteArchivedRecordsWithBackup(olderThan *time.Time, saveMatchesToFile, saveOrdersToFile bool) (string, int, error) {
 	return "/path/to/records", 10, nil
 }
+func (c *TCore) ExportTradeHistory(w *csv.Writer, since, until time.Time) (int, error) {
+	return 0, nil
+}
 func (c *TCore) WalletPeers(assetID uint32) ([]*asset.WalletPeer, error) {
 	return nil, nil
 }
+func (c *TCore) LockedBalance(assetID uint32) (*core.LockedBalance, error) {
+	return &core.LockedBalance{AssetID: assetID, Symbol: dex.BipIDSymbol(assetID)}, nil
+}
 func (c *TCore) AddWalletPeer(assetID uint32, address string) error {
 	return nil
 }
 func (c *TCore) RemoveWalletPeer(assetID uint32, address string) error {
 	return nil
 }
+func (c *TCore) AddressBook(pw []byte, assetID uint32) (*core.AddressBook, error) {
+	return &core.AddressBook{AssetID: assetID}, nil
+}
+func (c *TCore) AddAddressBookEntry(pw []byte, assetID uint32, addr, label string) error {
+	return nil
+}
+func (c *TCore) RemoveAddressBookEntry(pw []byte, assetID uint32, addr string) error {
+	return nil
+}
+func (c *TCore) RestrictWithdrawals(pw []byte, assetID uint32, restrict bool) error {
+	return nil
+}
 func (c *TCore) ApproveToken(appPW []byte, assetID uint32, dexAddr string, onConfirm func()) (string, error) {
 	return "", nil
 }
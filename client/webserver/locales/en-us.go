@@ -93,6 +93,13 @@ var EnUS = map[string]*intl.Translation{
 	"min trade is about":             {T: "min trade is about"},
 	"immediate_explanation":          {T: "If the order doesn't fully match during the next match cycle, any unmatched quantity will not be booked or matched again. Taker-only order."},
 	"Immediate or cancel":            {T: "Immediate or cancel"},
+	"Worst price":                    {T: "Worst price"},
+	"worst_price_explanation":        {T: "Optional. The order will not be matched beyond this price. Any quantity that can't be matched within the limit is left unfilled."},
+	"Cancel after":                   {T: "Cancel after"},
+	"cancel_after_explanation":       {T: "Optional. The order will be canceled if it is still on the book after this many minutes."},
+	"minutes":                        {T: "minutes"},
+	"Max drift":                      {T: "Max drift"},
+	"max_drift_explanation":          {T: "Optional. The order will be canceled if the mid-market price moves more than this percent away from the order's price."},
 	"Balances":                       {T: "Balances"},
 	"outdated_tooltip":               {T: "Balance may be outdated. Connect to the wallet to refresh."},
 	"available":                      {T: "available"},
@@ -312,6 +319,9 @@ var EnUS = map[string]*intl.Translation{
 	"estimated_balance":           {T: "Estimated Balance After"},
 	"max_estimated_send":          {T: "Max Estimated Send"},
 	"max_estimated_send_fee":      {T: "Max Estimated Send Fee"},
+	"address_book":                {T: "Address Book"},
+	"place_order_anyway":          {T: "Place Order Anyway"},
+	"address_book_restricted":     {T: "Sends are restricted to addresses in the address book."},
 	"sending":                     {T: "Sending"},
 	"transfer":                    {T: "Transfer"},
 	"max_estimated_send_tooltip":  {T: "This is the estimated amount that will be received if you withdraw your current balance with 'Subtract fees from amount sent' checked. If there is no subtract fee checkbox, this is the maximum estimated amount you can send."},
@@ -338,6 +348,10 @@ var EnUS = map[string]*intl.Translation{
 	"save_orders_to_file":         {T: "Save orders to CSV file"},
 	"save_orders_to_file_msg":     {T: "Optional: Whether to save deleted orders to CSV file on bisonw data directory. Default is false."},
 	"save_matches_to_file_msg":    {T: "Optional: Whether to save deleted matches to CSV file on bisonw data directory. Default is false."},
+	"export_trade_history":        {T: "Export Trade History"},
+	"export_trade_history_msg":    {T: "Download the completed matches of all orders, with the fees paid and transaction IDs, as a CSV file. Leave a date empty to leave the range open."},
+	"start_date":                  {T: "Start date"},
+	"end_date":                    {T: "End date"},
 	// Market maker bot
 	"Market Making":          {T: "Market Making"},
 	"Off":                    {T: "Off"},
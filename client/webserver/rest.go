@@ -0,0 +1,310 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/go-chi/chi/v5"
+)
+
+// The v1 REST API, mounted on /api/v1, is for applications other than the
+// browser frontend, such as mobile apps and dashboards. It does not use
+// cookies. An API token is obtained by logging in on /api/v1/login, and must be
+// sent with every other request in an "Authorization: Bearer <token>" header.
+// The app password is not cached for API tokens, so requests that place orders
+// or spend funds must include it. Responses use the same JSON format as the
+// rest of /api.
+
+const bearerPrefix = "Bearer "
+
+// restAPIRoutes registers the v1 REST API handlers.
+func (s *WebServer) restAPIRoutes(r chi.Router) {
+	r.Use(s.rejectUninited)
+	r.Post("/login", s.apiV1Login)
+
+	r.Group(func(apiAuth chi.Router) {
+		apiAuth.Use(s.rejectBadAPIToken)
+		apiAuth.Post("/logout", s.apiV1Logout)
+		apiAuth.Get("/wallets", s.apiV1Wallets)
+		apiAuth.Get("/balances", s.apiV1Balances)
+		apiAuth.Post("/openwallet", s.apiOpenWallet)
+		apiAuth.Post("/closewallet", s.apiCloseWallet)
+		apiAuth.Post("/depositaddress", s.apiNewDepositAddress)
+		apiAuth.Post("/send", s.apiSend)
+		apiAuth.Get("/markets", s.apiV1Markets)
+		apiAuth.Get("/orders", s.apiV1Orders)
+		apiAuth.Post("/orders", s.apiTrade)
+		apiAuth.Post("/validatetrade", s.apiValidateTrade)
+		apiAuth.With(orderIDCtx).Get("/orders/{oid}", s.apiV1Order)
+		apiAuth.With(orderIDCtx).Delete("/orders/{oid}", s.apiV1Cancel)
+	})
+}
+
+// authorizeAPI creates and stores a new API token.
+func (s *WebServer) authorizeAPI() string {
+	b := make([]byte, 32)
+	crand.Read(b)
+	token := hex.EncodeToString(b)
+	zero(b)
+	s.authMtx.Lock()
+	s.apiTokens[token] = true
+	s.authMtx.Unlock()
+	return token
+}
+
+// getAPIToken gets the bearer token from the request's Authorization header.
+// An empty string is returned if there is no bearer token.
+func getAPIToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(auth[len(bearerPrefix):])
+}
+
+// rejectBadAPIToken responds with an error if the request does not have a
+// valid API token.
+func (s *WebServer) rejectBadAPIToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := getAPIToken(r)
+		s.authMtx.RLock()
+		valid := token != "" && s.apiTokens[token]
+		s.authMtx.RUnlock()
+		if !valid {
+			http.Error(w, "not authorized - invalid API token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiV1Login handles the /api/v1/login request. The app is logged in if it is
+// not already, and a new API token is returned.
+func (s *WebServer) apiV1Login(w http.ResponseWriter, r *http.Request) {
+	login := new(loginForm)
+	defer login.Pass.Clear()
+	if !readPost(w, r, login) {
+		return
+	}
+	if err := s.core.Login(login.Pass); err != nil {
+		s.writeAPIError(w, fmt.Errorf("login error: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool   `json:"ok"`
+		Token string `json:"token"`
+	}{
+		OK:    true,
+		Token: s.authorizeAPI(),
+	})
+}
+
+// apiV1Logout handles the /api/v1/logout request. Only the request's API token
+// is revoked. The app stays logged in.
+func (s *WebServer) apiV1Logout(w http.ResponseWriter, r *http.Request) {
+	token := getAPIToken(r)
+	s.authMtx.Lock()
+	delete(s.apiTokens, token)
+	s.authMtx.Unlock()
+	writeJSON(w, simpleAck())
+}
+
+// apiV1Wallets handles the /api/v1/wallets request.
+func (s *WebServer) apiV1Wallets(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		OK      bool                `json:"ok"`
+		Wallets []*core.WalletState `json:"wallets"`
+	}{
+		OK:      true,
+		Wallets: s.core.Wallets(),
+	})
+}
+
+// assetBalance is the balance of one wallet in the /api/v1/balances response.
+type assetBalance struct {
+	AssetID uint32              `json:"assetID"`
+	Symbol  string              `json:"symbol"`
+	Balance *core.WalletBalance `json:"balance"`
+}
+
+// apiV1Balances handles the /api/v1/balances request.
+func (s *WebServer) apiV1Balances(w http.ResponseWriter, r *http.Request) {
+	wallets := s.core.Wallets()
+	bals := make([]*assetBalance, 0, len(wallets))
+	for _, ws := range wallets {
+		bals = append(bals, &assetBalance{
+			AssetID: ws.AssetID,
+			Symbol:  ws.Symbol,
+			Balance: ws.Balance,
+		})
+	}
+	writeJSON(w, &struct {
+		OK       bool            `json:"ok"`
+		Balances []*assetBalance `json:"balances"`
+	}{
+		OK:       true,
+		Balances: bals,
+	})
+}
+
+// marketInfo is a market in the /api/v1/markets response.
+type marketInfo struct {
+	Host        string        `json:"host"`
+	Name        string        `json:"name"`
+	BaseID      uint32        `json:"baseID"`
+	BaseSymbol  string        `json:"baseSymbol"`
+	QuoteID     uint32        `json:"quoteID"`
+	QuoteSymbol string        `json:"quoteSymbol"`
+	LotSize     uint64        `json:"lotSize"`
+	RateStep    uint64        `json:"rateStep"`
+	EpochLen    uint64        `json:"epochLen"`
+	MinimumRate uint64        `json:"minimumRate"`
+	SpotPrice   *msgjson.Spot `json:"spot,omitempty"`
+}
+
+// apiV1Markets handles the /api/v1/markets request. The markets of all known
+// servers are listed, sorted by host and market name.
+func (s *WebServer) apiV1Markets(w http.ResponseWriter, r *http.Request) {
+	mkts := make([]*marketInfo, 0)
+	for host, xc := range s.core.Exchanges() {
+		for _, mkt := range xc.Markets {
+			mkts = append(mkts, &marketInfo{
+				Host:        host,
+				Name:        mkt.Name,
+				BaseID:      mkt.BaseID,
+				BaseSymbol:  mkt.BaseSymbol,
+				QuoteID:     mkt.QuoteID,
+				QuoteSymbol: mkt.QuoteSymbol,
+				LotSize:     mkt.LotSize,
+				RateStep:    mkt.RateStep,
+				EpochLen:    mkt.EpochLen,
+				MinimumRate: mkt.MinimumRate,
+				SpotPrice:   mkt.SpotPrice,
+			})
+		}
+	}
+	sort.Slice(mkts, func(i, j int) bool {
+		if mkts[i].Host != mkts[j].Host {
+			return mkts[i].Host < mkts[j].Host
+		}
+		return mkts[i].Name < mkts[j].Name
+	})
+	writeJSON(w, &struct {
+		OK      bool          `json:"ok"`
+		Markets []*marketInfo `json:"markets"`
+	}{
+		OK:      true,
+		Markets: mkts,
+	})
+}
+
+// orderFilterFromQuery parses the order filter from the /api/v1/orders query
+// parameters: host, base and quote (both or neither), n, and offset, the ID of
+// the last order of the previous page.
+func orderFilterFromQuery(q url.Values) (*core.OrderFilter, error) {
+	filter := &core.OrderFilter{N: 50}
+	if host := q.Get("host"); host != "" {
+		filter.Hosts = []string{host}
+	}
+	if n := q.Get("n"); n != "" {
+		v, err := strconv.Atoi(n)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("invalid n %q", n)
+		}
+		filter.N = v
+	}
+	if offset := q.Get("offset"); offset != "" {
+		oid, err := hex.DecodeString(offset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset %q", offset)
+		}
+		filter.Offset = oid
+	}
+	base, quote := q.Get("base"), q.Get("quote")
+	if base == "" && quote == "" {
+		return filter, nil
+	}
+	baseID, err := strconv.ParseUint(base, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base asset ID %q", base)
+	}
+	quoteID, err := strconv.ParseUint(quote, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote asset ID %q", quote)
+	}
+	filter.Market = &struct {
+		Base  uint32 `json:"baseID"`
+		Quote uint32 `json:"quoteID"`
+	}{
+		Base:  uint32(baseID),
+		Quote: uint32(quoteID),
+	}
+	return filter, nil
+}
+
+// apiV1Orders handles the /api/v1/orders GET request.
+func (s *WebServer) apiV1Orders(w http.ResponseWriter, r *http.Request) {
+	filter, err := orderFilterFromQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ords, err := s.core.Orders(filter)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("Orders error: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK     bool          `json:"ok"`
+		Orders []*core.Order `json:"orders"`
+	}{
+		OK:     true,
+		Orders: ords,
+	})
+}
+
+// apiV1Order handles the /api/v1/orders/{oid} GET request.
+func (s *WebServer) apiV1Order(w http.ResponseWriter, r *http.Request) {
+	oid, err := getOrderIDCtx(r)
+	if err != nil {
+		http.Error(w, "invalid order ID", http.StatusBadRequest)
+		return
+	}
+	ord, err := s.core.Order(oid)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("Order error: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool        `json:"ok"`
+		Order *core.Order `json:"order"`
+	}{
+		OK:    true,
+		Order: ord,
+	})
+}
+
+// apiV1Cancel handles the /api/v1/orders/{oid} DELETE request.
+func (s *WebServer) apiV1Cancel(w http.ResponseWriter, r *http.Request) {
+	oid, err := getOrderIDCtx(r)
+	if err != nil {
+		http.Error(w, "invalid order ID", http.StatusBadRequest)
+		return
+	}
+	if err := s.core.Cancel(oid); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error cancelling order %s: %w", oid, err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
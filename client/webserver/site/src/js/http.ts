@@ -35,9 +35,11 @@ export async function getJSON (addr: string): Promise<any> {
   return requestJSON('GET', addr)
 }
 
+// Errors are the client/core error codes.
 export enum Errors {
   walletErr,
   walletAuthErr,
+  noAuthError,
   walletBalanceErr,
   dupeDEXErr,
   assetSupportErr,
@@ -67,10 +69,19 @@ export enum Errors {
   fileReadErr,
   unknownDEXErr,
   accountRetrieveErr,
-  accountDisableErr,
+  accountStatusUpdateErr,
   suspendedAcctErr,
   existenceCheckErr,
   createWalletErr,
   activeOrdersErr,
   newAddrErr,
+  bondAmtErr,
+  bondTimeErr,
+  bondAssetErr,
+  bondPostErr,
+  addressBookErr,
+  restrictedAddrErr,
+  lowLiquidityErr,
+  amnesiaErr,
+  orderStepErr,
 }
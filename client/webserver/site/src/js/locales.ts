@@ -219,6 +219,8 @@ export const ID_CEX_BALANCES = 'CEX_BALANCES'
 export const ID_CAUSES_SELF_MATCH = 'CAUSES_SELF_MATCH'
 export const ID_CEX_NOT_CONNECTED = 'CEX_NOT_CONNECTED'
 export const ID_DELETE_BOT = 'DELETE_BOT'
+export const ID_BANDED_QTY = 'BANDED_QTY'
+export const ID_USE_STEP_VALUE = 'USE_STEP_VALUE'
 
 let locale: Locale
 
@@ -13,7 +13,7 @@ import {
   DepthMarker,
   Wave
 } from './charts'
-import { postJSON } from './http'
+import { postJSON, Errors } from './http'
 import {
   NewWalletForm,
   AccelerateOrderForm,
@@ -39,6 +39,7 @@ import {
   Candle,
   CandlesPayload,
   TradeForm,
+  OrderStepHint,
   BookUpdate,
   MaxSell,
   MaxBuy,
@@ -192,6 +193,7 @@ export default class MarketsPage extends BasePage {
   loadingAnimations: { candles?: Wave, depth?: Wave }
   mmRunning: boolean | undefined
   forms: Forms
+  stepHint: OrderStepHint | null
   constructor (main: HTMLElement, pageParams: MarketsPageParams) {
     super()
 
@@ -353,7 +355,7 @@ export default class MarketsPage extends BasePage {
       this.lotChanged()
     })
 
-    Doc.disableMouseWheel(page.rateField, page.lotField, page.qtyField, page.mktBuyField)
+    Doc.disableMouseWheel(page.rateField, page.lotField, page.qtyField, page.mktBuyField, page.worstRateField, page.ttlField, page.maxDriftField)
 
     // Handle the full orderbook sent on the 'book' route.
     ws.registerRoute(bookRoute, (data: BookUpdate) => { this.handleBookRoute(data) })
@@ -378,6 +380,19 @@ export default class MarketsPage extends BasePage {
     bindForm(page.orderForm, page.submitBttn, async () => { this.stepSubmit() })
     // Order verification form.
     bindForm(page.verifyForm, page.vSubmit, async () => { this.submitOrder() })
+    // Resubmit an order on a low-liquidity market after confirmation.
+    Doc.bind(page.vAcceptLowLiquidity, 'click', async () => {
+      this.currentOrder.acceptLowLiquidity = true
+      this.submitOrder()
+    })
+    // Replace a rate or quantity rejected for the market's step size with one
+    // of the suggested values.
+    Doc.bind(page.vStepHintLower, 'click', () => {
+      if (this.stepHint?.lower) this.applyStepHint(this.stepHint.field, this.stepHint.lower, this.stepHint.step)
+    })
+    Doc.bind(page.vStepHintUpper, 'click', () => {
+      if (this.stepHint) this.applyStepHint(this.stepHint.field, this.stepHint.upper, this.stepHint.step)
+    })
     // Cancel order form.
     bindForm(page.cancelForm, page.cancelSubmit, async () => { this.submitCancel() })
     // Order detail view.
@@ -765,11 +780,12 @@ export default class MarketsPage extends BasePage {
   setOrderVisibility () {
     const page = this.page
     if (this.isLimit()) {
-      Doc.show(page.priceBox, page.tifBox, page.qtyBox, page.maxBox)
-      Doc.hide(page.mktBuyBox)
+      Doc.show(page.priceBox, page.tifBox, page.qtyBox, page.maxBox, page.autoCancelBox)
+      Doc.hide(page.mktBuyBox, page.worstRateBox)
       this.previewQuoteAmt(true)
     } else {
-      Doc.hide(page.tifBox, page.maxBox, page.priceBox)
+      Doc.hide(page.tifBox, page.maxBox, page.priceBox, page.autoCancelBox)
+      Doc.show(page.worstRateBox)
       if (this.isSell()) {
         Doc.hide(page.mktBuyBox)
         Doc.show(page.qtyBox)
@@ -1106,6 +1122,9 @@ export default class MarketsPage extends BasePage {
     page.lotField.value = ''
     page.qtyField.value = ''
     page.rateField.value = ''
+    page.worstRateField.value = ''
+    page.ttlField.value = ''
+    page.maxDriftField.value = ''
 
     // clear depth chart and orderbook.
     this.depthChart.clear()
@@ -1319,6 +1338,8 @@ export default class MarketsPage extends BasePage {
       qtyField = page.mktBuyField
       qtyConv = market.quoteUnitInfo.conventional.conversionFactor
     }
+    // Automatic cancellation only applies to standing limit orders.
+    const standing = limit && !page.tifNow.checked
     return {
       host: market.dex.host,
       isLimit: limit,
@@ -1328,7 +1349,10 @@ export default class MarketsPage extends BasePage {
       qty: convertToAtoms(qtyField.value || '', qtyConv),
       rate: convertToAtoms(page.rateField.value || '', market.rateConversionFactor), // message-rate
       tifnow: page.tifNow.checked || false,
-      options: {}
+      options: {},
+      worstRate: limit ? 0 : this.adjustedWorstRate(sell),
+      ttl: standing ? Math.max(Math.round(Number(page.ttlField.value || 0) * 60), 0) : 0,
+      maxDrift: standing ? Math.max(Number(page.maxDriftField.value || 0), 0) : 0
     }
   }
 
@@ -2137,7 +2161,7 @@ export default class MarketsPage extends BasePage {
   /* showVerifyForm displays form to verify an order */
   async showVerifyForm () {
     const page = this.page
-    Doc.hide(page.vErr)
+    Doc.hide(page.vErr, page.vLowLiquidity, page.vStepHint)
     this.forms.show(page.verifyForm)
   }
 
@@ -2698,7 +2722,7 @@ export default class MarketsPage extends BasePage {
    */
   async submitOrder () {
     const page = this.page
-    Doc.hide(page.orderErr, page.vErr)
+    Doc.hide(page.orderErr, page.vErr, page.vLowLiquidity, page.vStepHint)
     const order = this.currentOrder
     const req = { order: wireOrder(order) }
     if (!this.validateOrder(order)) return
@@ -2709,6 +2733,18 @@ export default class MarketsPage extends BasePage {
     // Hide loader and show submit button.
     page.vSubmit.classList.remove('d-hide')
     page.vLoader.classList.add('d-hide')
+    // An order on a low-liquidity market must be confirmed.
+    if (!res.ok && res.code === Errors.lowLiquidityErr) {
+      page.vLowLiquidityMsg.textContent = res.msg
+      Doc.show(page.vLowLiquidity)
+      return
+    }
+    // A rate or quantity that is not a multiple of the step size can be
+    // corrected to one of the nearest valid values.
+    if (!res.ok && res.code === Errors.orderStepErr && res.stepHint) {
+      this.showStepHint(res.msg, res.stepHint)
+      return
+    }
     // If error, display error on confirmation modal.
     if (!app().checkResponse(res)) {
       page.vErr.textContent = res.msg
@@ -2720,6 +2756,41 @@ export default class MarketsPage extends BasePage {
     this.refreshActiveOrders()
   }
 
+  /*
+   * showStepHint displays the error for an order rate or quantity that is not
+   * a multiple of the market's rate step or lot size, with buttons to use the
+   * nearest valid values instead.
+   */
+  showStepHint (msg: string, hint: OrderStepHint) {
+    const { page, market } = this
+    this.stepHint = hint
+    const conv = hint.field === 'rate' ? market.rateConversionFactor : market.baseUnitInfo.conventional.conversionFactor
+    page.vStepHintMsg.textContent = msg
+    Doc.setVis(hint.lower, page.vStepHintLower)
+    if (hint.lower) page.vStepHintLower.textContent = intl.prep(intl.ID_USE_STEP_VALUE, { value: String(hint.lower / conv) })
+    page.vStepHintUpper.textContent = intl.prep(intl.ID_USE_STEP_VALUE, { value: String(hint.upper / conv) })
+    Doc.show(page.vStepHint)
+  }
+
+  /*
+   * applyStepHint sets the order form's rate or quantity to a valid value from
+   * a step hint and shows the updated order for verification. The value is
+   * not adjusted to the market's step sizes again, since the server's step
+   * sizes are the ones that apply.
+   */
+  applyStepHint (field: string, v: number, step: number) {
+    const { page, market } = this
+    this.stepHint = null
+    if (field === 'rate') {
+      page.rateField.value = String(v / market.rateConversionFactor)
+    } else {
+      page.lotField.value = String(v / step)
+      page.qtyField.value = String(v / market.baseUnitInfo.conventional.conversionFactor)
+    }
+    this.previewQuoteAmt(true)
+    this.showVerify()
+  }
+
   /*
    * createWallet is attached to successful submission of the wallet creation
    * form. createWallet is only called once the form is submitted and a success
@@ -2852,6 +2923,20 @@ export default class MarketsPage extends BasePage {
     return rate - (rate % rateStep)
   }
 
+  /*
+   * adjustedWorstRate is the market order's worst rate, from the worst price
+   * field, rounded to a rate step within the user's limit. 0 means no limit.
+   */
+  adjustedWorstRate (sell: boolean): number {
+    const v = this.page.worstRateField.value
+    if (!v) return 0
+    const rate = convertToAtoms(v, this.market.rateConversionFactor)
+    const rateStep = this.market.cfg.ratestep
+    const rem = rate % rateStep
+    if (rem === 0) return rate
+    return sell ? rate - rem + rateStep : rate - rem
+  }
+
   /* loadTable reloads the table from the current order book information. */
   loadTable () {
     this.loadTableSide(true)
@@ -2976,8 +3061,8 @@ export default class MarketsPage extends BasePage {
    */
   orderTableRow (orderBin: MiniOrder[]): OrderRow {
     const tr = this.page.orderRowTmpl.cloneNode(true) as OrderRow
-    const { baseUnitInfo, quoteUnitInfo, rateConversionFactor, cfg: { ratestep: rateStep } } = this.market
-    const manager = new OrderTableRowManager(tr, orderBin, baseUnitInfo, quoteUnitInfo, rateStep)
+    const { baseUnitInfo, quoteUnitInfo, rateConversionFactor, cfg: { ratestep: rateStep, displayBand } } = this.market
+    const manager = new OrderTableRowManager(tr, orderBin, baseUnitInfo, quoteUnitInfo, rateStep, displayBand ?? 0)
     tr.manager = manager
     bind(tr, 'click', () => {
       this.reportDepthClick(tr.manager.getRate() / rateConversionFactor)
@@ -3459,8 +3544,9 @@ class OrderTableRowManager {
   msgRate: number
   epoch: boolean
   baseUnitInfo: UnitInfo
+  displayBand: number
 
-  constructor (tableRow: HTMLElement, orderBin: MiniOrder[], baseUnitInfo: UnitInfo, quoteUnitInfo: UnitInfo, rateStep: number) {
+  constructor (tableRow: HTMLElement, orderBin: MiniOrder[], baseUnitInfo: UnitInfo, quoteUnitInfo: UnitInfo, rateStep: number, displayBand: number) {
     this.tableRow = tableRow
     const page = this.page = Doc.parseTemplate(tableRow)
     this.orderBin = orderBin
@@ -3468,6 +3554,7 @@ class OrderTableRowManager {
     this.msgRate = orderBin[0].msgRate
     this.epoch = !!orderBin[0].epoch
     this.baseUnitInfo = baseUnitInfo
+    this.displayBand = displayBand
     const rateText = Doc.formatRateFullPrecision(this.msgRate, baseUnitInfo, quoteUnitInfo, rateStep)
     Doc.setVis(this.isEpoch(), this.page.epoch)
     if (this.msgRate === 0) {
@@ -3482,12 +3569,20 @@ class OrderTableRowManager {
 
   // updateQtyNumOrdersEl populates the quantity element in the row, and also
   // displays the number of orders if there is more than one order in the order
-  // bin.
+  // bin. If the server bands the quantities of large orders, and any order in
+  // the bin is that large, the quantity is shown as a lower bound.
   updateQtyNumOrdersEl () {
-    const { page, orderBin } = this
+    const { page, orderBin, displayBand } = this
     const qty = orderBin.reduce((total, curr) => total + curr.qtyAtomic, 0)
     const numOrders = orderBin.length
-    page.qty.innerText = Doc.formatFullPrecision(qty, this.baseUnitInfo)
+    const qtyText = Doc.formatFullPrecision(qty, this.baseUnitInfo)
+    if (displayBand > 0 && orderBin.some(ord => ord.qtyAtomic >= displayBand)) {
+      page.qty.innerText = `≥ ${qtyText}`
+      page.qty.title = intl.prep(intl.ID_BANDED_QTY, { band: Doc.formatFullPrecision(displayBand, this.baseUnitInfo) })
+    } else {
+      page.qty.innerText = qtyText
+      page.qty.removeAttribute('title')
+    }
     if (numOrders > 1) {
       page.numOrders.removeAttribute('hidden')
       page.numOrders.innerText = String(numOrders)
@@ -107,6 +107,15 @@ export default class OrdersPage extends BasePage {
       this.exportOrders()
     })
 
+    Doc.bind(page.exportTradeHistory, 'click', () => {
+      Doc.hide(page.exportTradeHistoryErr)
+      this.showForm(page.exportTradeHistoryForm)
+    })
+
+    Doc.bind(page.exportTradeHistorySubmit, 'click', () => {
+      this.exportTradeHistory()
+    })
+
     page.showArchivedDateField.addEventListener('change', () => {
       if (page.showArchivedDateField.checked) Doc.show(page.archivedDateField)
       else Doc.hide(page.archivedDateField, page.deleteArchivedRecordsErr)
@@ -259,6 +268,33 @@ export default class OrdersPage extends BasePage {
     window.open(url.toString())
   }
 
+  /*
+   * exportTradeHistory downloads a csv of the user's completed matches in the
+   * date range of the export form. The end date is inclusive.
+   */
+  exportTradeHistory () {
+    const page = this.page
+    const search = new URLSearchParams('')
+    const dayMs = 86400000
+    const setDate = (k: string, input: PageElement, endOfDay: boolean) => {
+      if (!input.value) return true
+      // Parse as local time, like the date input.
+      const ms = new Date(input.value + 'T00:00').getTime()
+      if (isNaN(ms)) return false
+      search.set(k, String(endOfDay ? ms + dayMs - 1 : ms))
+      return true
+    }
+    if (!setDate('since', page.tradeHistorySince, false) || !setDate('until', page.tradeHistoryUntil, true)) {
+      Doc.showFormError(page.exportTradeHistoryErr, intl.prep(intl.ID_INVALID_DATE_ERR_MSG))
+      return
+    }
+    const url = new URL(window.location.href)
+    url.search = search.toString()
+    url.pathname = '/trades/export'
+    window.open(url.toString())
+    Doc.hide(page.forms)
+  }
+
   /* deleteArchivedRecords removes the user's archived orders and matches
    * created before user specified date time in millisecond. Deleted archived
    * records are saved to a CSV file if the user specify so.
@@ -101,6 +101,7 @@ export interface Market {
   atomToConv: number
   inflight: InFlightOrder[]
   minimumRate: number
+  displayBand?: number
 }
 
 export interface InFlightOrder extends Order {
@@ -135,6 +136,8 @@ export interface Order {
   tif: number // limit only
   targetOrderID: string // cancel only
   readyToTick: boolean
+  ttl?: number
+  maxDrift?: number
 }
 
 export interface Match {
@@ -196,6 +199,29 @@ export interface PendingBondState {
 export interface FeeBreakdown {
   swap: number
   redemption: number
+  funding: number
+  txs?: FeeTx[]
+}
+
+export interface FeeTx {
+  type: string // 'funding', 'swap', or 'redemption'
+  coin: Coin
+  fees: number
+  matchIDs?: string[]
+  stamp: number
+}
+
+export interface AssetValue {
+  assetID: number
+  symbol: string
+  balance: number
+  rate: number
+  value: number
+}
+
+export interface PortfolioValue {
+  assets: AssetValue[]
+  total: number
 }
 
 export interface SupportedAsset {
@@ -283,6 +309,31 @@ export interface WalletBalance {
   other: Record<string, CustomBalance>
 }
 
+export interface OrderLock {
+  host: string
+  marketID: string
+  orderID: string
+  amount: number
+}
+
+export interface MatchLock extends OrderLock {
+  matchID: string
+}
+
+export interface LockedBalance {
+  assetID: number
+  symbol: string
+  locked: number
+  orders: OrderLock[]
+  feeReserves: OrderLock[]
+  bondReserves: number
+  other: Record<string, number>
+  unexplained: number
+  contracts: MatchLock[]
+  pendingRedemptions: MatchLock[]
+  bonds: number
+}
+
 export interface CustomBalance {
   amt: number
   locked: boolean
@@ -673,6 +724,22 @@ export interface PreRedeem {
 export interface OrderEstimate {
   swap: PreSwap
   redeem: PreRedeem
+  lots: number
+  qty: number
+  maxLots: number
+  maxQty: number
+  locked: Record<number, number>
+}
+
+export interface TradeValidationError {
+  check: string
+  message: string
+  transient: boolean
+}
+
+export interface TradeValidation {
+  valid: boolean
+  errors: TradeValidationError[]
 }
 
 export interface MaxOrderEstimate {
@@ -688,6 +755,16 @@ export interface MaxBuy {
   maxBuy: MaxOrderEstimate
 }
 
+// OrderStepHint is the nearest valid values for an order rate or quantity
+// that is not a multiple of the market's rate step or lot size.
+export interface OrderStepHint {
+  field: string // 'rate' or 'qty'
+  value: number
+  step: number
+  lower?: number
+  upper: number
+}
+
 export interface TradeForm {
   host: string
   isLimit: boolean
@@ -698,6 +775,11 @@ export interface TradeForm {
   rate: number
   tifnow: boolean
   options: Record<string, any>
+  worstRate?: number
+  acceptLowLiquidity?: boolean
+  route?: boolean
+  ttl?: number
+  maxDrift?: number
 }
 
 export interface BookUpdate {
@@ -731,6 +813,20 @@ export interface MarketOrderBook {
   book: CoreOrderBook
 }
 
+export interface DepthBin {
+  rate: number
+  qty: number
+  cumQty: number
+}
+
+export interface Depth {
+  binWidth: number
+  midGap: number
+  spread: number
+  buys: DepthBin[]
+  sells: DepthBin[]
+}
+
 export interface RemainderUpdate {
   token: string
   qty: number
@@ -749,6 +845,9 @@ export interface OrderFilter {
   assets?: number[]
   market?: OrderFilterMarket
   statuses?: number[]
+  sell?: boolean
+  since?: number
+  until?: number
 }
 
 export interface OrderPlacement {
@@ -1158,6 +1257,17 @@ export interface WalletPeer {
   connected: boolean
 }
 
+export interface AddressBookEntry {
+  address: string
+  label: string
+}
+
+export interface AddressBook {
+  assetID: number
+  entries: AddressBookEntry[]
+  restricted: boolean
+}
+
 export interface TicketTransaction {
   hash: string
   ticketPrice: number
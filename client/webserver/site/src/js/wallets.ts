@@ -41,7 +41,8 @@ import {
   TxHistoryResult,
   TransactionNote,
   WalletTransaction,
-  FeeState
+  FeeState,
+  AddressBook
 } from './registry'
 import { CoinExplorers } from './coinexplorers'
 
@@ -390,6 +391,13 @@ export default class WalletsPage extends BasePage {
       else page.sendAddr.classList.add('border-danger')
     })
 
+    // Choosing an address book entry fills in the send address.
+    Doc.bind(page.sendAddrBook, 'change', () => {
+      if (!page.sendAddrBook.value) return
+      page.sendAddr.value = page.sendAddrBook.value
+      page.sendAddr.dispatchEvent(new Event('input'))
+    })
+
     // A link on the wallet reconfiguration form to show/hide the password field.
     Doc.bind(page.showChangePW, 'click', () => {
       this.changeWalletPW = !this.changeWalletPW
@@ -2162,6 +2170,7 @@ export default class WalletsPage extends BasePage {
     Doc.hide(page.sendErr, page.maxSendDisplay, page.sendTokenMsgBox)
     page.sendAddr.classList.remove('border-danger', 'border-success')
     page.sendAddr.value = ''
+    await this.loadAddressBook(assetID)
     page.sendAmt.value = ''
     const xcRate = app().fiatRatesMap[assetID]
     Doc.showFiatValue(page.sendValue, 0, xcRate, ui)
@@ -2216,6 +2225,39 @@ export default class WalletsPage extends BasePage {
     this.showForm(box)
   }
 
+  /*
+   * loadAddressBook fills the send form's address book selector with the
+   * asset's address book entries. The selector is hidden if the book is empty
+   * or can't be loaded, e.g. because the app password is not cached. If sends
+   * are restricted to the book, the address can only be chosen from the book.
+   */
+  async loadAddressBook (assetID: number) {
+    const page = this.page
+    Doc.hide(page.sendAddrBookBox, page.sendAddrBookRestricted)
+    Doc.empty(page.sendAddrBook)
+    const addrInput = page.sendAddr as HTMLInputElement
+    addrInput.readOnly = false
+    const res = await postJSON('/api/addressbook', { assetID })
+    if (!res.ok || !res.book) return
+    const book = res.book as AddressBook
+    if (book.restricted) {
+      addrInput.readOnly = true
+      Doc.show(page.sendAddrBookRestricted)
+    }
+    if (book.entries.length === 0 && !book.restricted) return
+    const blank = document.createElement('option') as HTMLOptionElement
+    blank.value = ''
+    blank.textContent = '-'
+    page.sendAddrBook.appendChild(blank)
+    for (const entry of book.entries) {
+      const option = document.createElement('option') as HTMLOptionElement
+      option.value = entry.address
+      option.textContent = entry.label ? `${entry.label} (${entry.address})` : entry.address
+      page.sendAddrBook.appendChild(option)
+    }
+    Doc.show(page.sendAddrBookBox)
+  }
+
   /* doConnect connects to a wallet via the connectwallet API route. */
   async doConnect (assetID: number) {
     const loaded = app().loading(this.body)
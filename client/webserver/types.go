@@ -8,6 +8,7 @@ import (
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/msgjson"
 )
 
 // standardResponse is a basic API response when no data needs to be returned.
@@ -15,6 +16,9 @@ type standardResponse struct {
 	OK   bool   `json:"ok"`
 	Msg  string `json:"msg,omitempty"`
 	Code *int   `json:"code,omitempty"`
+	// StepHint has the nearest valid values for an order rate or quantity
+	// that violates the market's rate step or lot size.
+	StepHint *msgjson.OrderStepHint `json:"stepHint,omitempty"`
 }
 
 // simpleAck is a plain standardResponse with "ok" = true.
@@ -127,6 +131,11 @@ type accountExportForm struct {
 	Host string           `json:"host"`
 }
 
+type restoreDBSnapshotForm struct {
+	Pass encode.PassBytes `json:"pw"`
+	Name string           `json:"name"`
+}
+
 type accountImportForm struct {
 	Pass    encode.PassBytes `json:"pw"`
 	Account *core.Account    `json:"account"`
@@ -9,6 +9,7 @@ import (
 	crand "crypto/rand"
 	"crypto/tls"
 	"embed"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -113,6 +114,7 @@ type clientCore interface {
 	ReconfigureWallet([]byte, []byte, *core.WalletForm) error
 	ToggleWalletStatus(assetID uint32, disable bool) error
 	ChangeAppPass([]byte, []byte) error
+	SetWalletPassword(appPW []byte, assetID uint32, newPW []byte) error
 	ResetAppPass(newPass []byte, seed string) error
 	NewDepositAddress(assetID uint32) (string, error)
 	AutoWalletConfig(assetID uint32, walletType string) (map[string]string, error)
@@ -136,7 +138,10 @@ type clientCore interface {
 	ToggleAccountStatus(pw []byte, host string, disable bool) error
 	IsInitialized() bool
 	ExportSeed(pw []byte) (string, error)
+	DBSnapshots() ([]*db.Snapshot, error)
+	RestoreDBSnapshot(pw []byte, name string) error
 	PreOrder(*core.TradeForm) (*core.OrderEstimate, error)
+	ValidateTrade(*core.TradeForm) *core.TradeValidation
 	WalletLogFilePath(assetID uint32) (string, error)
 	BondsFeeBuffer(assetID uint32) (uint64, error)
 	PreAccelerateOrder(oidB dex.Bytes) (*core.PreAccelerate, error)
@@ -147,12 +152,19 @@ type clientCore interface {
 	WalletRestorationInfo(pw []byte, assetID uint32) ([]*asset.WalletRestoration, error)
 	ToggleRateSourceStatus(src string, disable bool) error
 	FiatRateSources() map[string]bool
+	PortfolioValue() *core.PortfolioValue
 	EstimateSendTxFee(address string, assetID uint32, value uint64, subtract, maxWithdraw bool) (fee uint64, isValidAddress bool, err error)
 	ValidateAddress(address string, assetID uint32) (bool, error)
 	DeleteArchivedRecordsWithBackup(olderThan *time.Time, saveMatchesToFile, saveOrdersToFile bool) (string, int, error)
+	ExportTradeHistory(w *csv.Writer, since, until time.Time) (int, error)
 	WalletPeers(assetID uint32) ([]*asset.WalletPeer, error)
+	LockedBalance(assetID uint32) (*core.LockedBalance, error)
 	AddWalletPeer(assetID uint32, addr string) error
 	RemoveWalletPeer(assetID uint32, addr string) error
+	AddressBook(pw []byte, assetID uint32) (*core.AddressBook, error)
+	AddAddressBookEntry(pw []byte, assetID uint32, addr, label string) error
+	RemoveAddressBookEntry(pw []byte, assetID uint32, addr string) error
+	RestrictWithdrawals(pw []byte, assetID uint32, restrict bool) error
 	Notifications(n int) (notes, pokes []*db.Notification, _ error)
 	ApproveToken(appPW []byte, assetID uint32, dexAddr string, onConrim func()) (string, error)
 	UnapproveToken(appPW []byte, assetID uint32, version uint32) (string, error)
@@ -266,6 +278,7 @@ type WebServer struct {
 	authMtx         sync.RWMutex
 	authTokens      map[string]bool
 	cachedPasswords map[string]*cachedPassword // cached passwords keyed by auth token
+	apiTokens       map[string]bool            // v1 REST API tokens
 
 	bondBufMtx sync.Mutex
 	bondBuf    map[uint32]valStamp
@@ -390,6 +403,7 @@ func New(cfg *Config) (*WebServer, error) {
 		wsServer:        websocket.New(cfg.Core, log.SubLogger("WS")),
 		authTokens:      make(map[string]bool),
 		cachedPasswords: make(map[string]*cachedPassword),
+		apiTokens:       make(map[string]bool),
 		bondBuf:         map[uint32]valStamp{},
 		useDEXBranding:  useDEXBranding,
 	}
@@ -473,6 +487,7 @@ func New(cfg *Config) (*WebServer, error) {
 				webDC.With(orderIDCtx).Get("/order/{oid}", s.handleOrder)
 				webDC.Get(ordersRoute, s.handleOrders)
 				webDC.Get(exportOrderRoute, s.handleExportOrders)
+				webDC.Get(exportTradesRoute, s.handleExportTradeHistory)
 				webDC.Get(marketsRoute, s.handleMarkets)
 				webDC.Get(mmSettingsRoute, s.handleMMSettings)
 				webDC.Get(mmArchivesRoute, s.handleMMArchives)
@@ -493,6 +508,7 @@ func New(cfg *Config) (*WebServer, error) {
 		r.Get("/user", s.apiUser)
 		r.Post("/locale", s.apiLocale)
 		r.Post("/setlocale", s.apiSetLocale)
+		r.Route("/v1", s.restAPIRoutes)
 
 		r.Group(func(apiInit chi.Router) {
 			apiInit.Use(s.rejectUninited)
@@ -525,6 +541,7 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/parseconfig", s.apiParseConfig)
 			apiAuth.Post("/reconfigurewallet", s.apiReconfig)
 			apiAuth.Post("/changeapppass", s.apiChangeAppPass)
+			apiAuth.Post("/setwalletpass", s.apiSetWalletPass)
 			apiAuth.Post("/walletsettings", s.apiWalletSettings)
 			apiAuth.Post("/togglewalletstatus", s.apiToggleWalletStatus)
 			apiAuth.Post("/orders", s.apiOrders)
@@ -533,8 +550,11 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/maxbuy", s.apiMaxBuy)
 			apiAuth.Post("/maxsell", s.apiMaxSell)
 			apiAuth.Post("/preorder", s.apiPreOrder)
+			apiAuth.Post("/validatetrade", s.apiValidateTrade)
 			apiAuth.Post("/exportaccount", s.apiAccountExport)
 			apiAuth.Post("/exportseed", s.apiExportSeed)
+			apiAuth.Get("/dbsnapshots", s.apiDBSnapshots)
+			apiAuth.Post("/restoredbsnapshot", s.apiRestoreDBSnapshot)
 			apiAuth.Post("/importaccount", s.apiAccountImport)
 			apiAuth.Post("/toggleaccountstatus", s.apiToggleAccountStatus)
 			apiAuth.Post("/accelerateorder", s.apiAccelerateOrder)
@@ -544,12 +564,18 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/updatedexhost", s.apiUpdateDEXHost)
 			apiAuth.Post("/restorewalletinfo", s.apiRestoreWalletInfo)
 			apiAuth.Post("/toggleratesource", s.apiToggleRateSource)
+			apiAuth.Get("/portfolio", s.apiPortfolio)
 			apiAuth.Post("/validateaddress", s.apiValidateAddress)
 			apiAuth.Post("/txfee", s.apiEstimateSendTxFee)
 			apiAuth.Post("/deletearchivedrecords", s.apiDeleteArchivedRecords)
 			apiAuth.Post("/getwalletpeers", s.apiGetWalletPeers)
+			apiAuth.Post("/lockedbalance", s.apiLockedBalance)
 			apiAuth.Post("/addwalletpeer", s.apiAddWalletPeer)
 			apiAuth.Post("/removewalletpeer", s.apiRemoveWalletPeer)
+			apiAuth.Post("/addressbook", s.apiAddressBook)
+			apiAuth.Post("/addaddress", s.apiAddAddress)
+			apiAuth.Post("/removeaddress", s.apiRemoveAddress)
+			apiAuth.Post("/restrictwithdrawals", s.apiRestrictWithdrawals)
 			apiAuth.Post("/approvetoken", s.apiApproveToken)
 			apiAuth.Post("/unapprovetoken", s.apiUnapproveToken)
 			apiAuth.Post("/approvetokenfee", s.apiApproveTokenFee)
@@ -757,12 +783,13 @@ func (s *WebServer) authorize() string {
 	return token
 }
 
-// deauth invalidates all current auth tokens. All existing sessions will need
-// to login again.
+// deauth invalidates all current auth tokens and API tokens. All existing
+// sessions will need to login again.
 func (s *WebServer) deauth() {
 	s.authMtx.Lock()
 	s.authTokens = make(map[string]bool)
 	s.cachedPasswords = make(map[string]*cachedPassword)
+	s.apiTokens = make(map[string]bool)
 	s.authMtx.Unlock()
 }
 
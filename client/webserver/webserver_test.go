@@ -5,6 +5,7 @@ package webserver
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -13,6 +14,8 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -90,6 +93,9 @@ type TCore struct {
 	tradeErr         error
 	notes            []*db.Notification
 	notesErr         error
+	snapshots        []*db.Snapshot
+	restoredSnapshot string
+	restoreErr       error
 }
 
 func (c *TCore) Network() dex.Network                         { return dex.Mainnet }
@@ -122,6 +128,9 @@ func (c *TCore) ToggleRateSourceStatus(src string, disable bool) error {
 func (c *TCore) FiatRateSources() map[string]bool {
 	return nil
 }
+func (c *TCore) PortfolioValue() *core.PortfolioValue {
+	return &core.PortfolioValue{}
+}
 
 func (c *TCore) InitializeClient(pw []byte, seed *string) (string, error) {
 	var mnemonicSeed string
@@ -240,6 +249,9 @@ func (c *TCore) MaxSell(host string, base, quote uint32) (*core.MaxOrderEstimate
 func (c *TCore) PreOrder(*core.TradeForm) (*core.OrderEstimate, error) {
 	return nil, nil
 }
+func (c *TCore) ValidateTrade(*core.TradeForm) *core.TradeValidation {
+	return &core.TradeValidation{Valid: true, Errors: []*core.TradeValidationError{}}
+}
 func (c *TCore) AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error) {
 	return nil, nil, nil
 }
@@ -251,6 +263,13 @@ func (c *TCore) ToggleAccountStatus(pw []byte, host string, disable bool) error
 func (c *TCore) ExportSeed(pw []byte) (string, error) {
 	return "seed words here", nil
 }
+func (c *TCore) DBSnapshots() ([]*db.Snapshot, error) {
+	return c.snapshots, nil
+}
+func (c *TCore) RestoreDBSnapshot(pw []byte, name string) error {
+	c.restoredSnapshot = name
+	return c.restoreErr
+}
 func (c *TCore) WalletLogFilePath(uint32) (string, error) {
 	return "", nil
 }
@@ -278,15 +297,33 @@ func (c *TCore) WalletRestorationInfo(pw []byte, assetID uint32) ([]*asset.Walle
 func (c *TCore) DeleteArchivedRecordsWithBackup(endDateTime *time.Time, saveMatchesToFile, saveOrdersToFile bool) (string, int, error) {
 	return "/path/to/records", c.deletedRecords, c.deleteRecordsErr
 }
+func (c *TCore) ExportTradeHistory(w *csv.Writer, since, until time.Time) (int, error) {
+	return 0, nil
+}
 func (c *TCore) WalletPeers(assetID uint32) ([]*asset.WalletPeer, error) {
 	return nil, nil
 }
+func (c *TCore) LockedBalance(assetID uint32) (*core.LockedBalance, error) {
+	return &core.LockedBalance{AssetID: assetID, Symbol: dex.BipIDSymbol(assetID)}, nil
+}
 func (c *TCore) AddWalletPeer(assetID uint32, address string) error {
 	return nil
 }
 func (c *TCore) RemoveWalletPeer(assetID uint32, address string) error {
 	return nil
 }
+func (c *TCore) AddressBook(pw []byte, assetID uint32) (*core.AddressBook, error) {
+	return &core.AddressBook{AssetID: assetID}, nil
+}
+func (c *TCore) AddAddressBookEntry(pw []byte, assetID uint32, addr, label string) error {
+	return nil
+}
+func (c *TCore) RemoveAddressBookEntry(pw []byte, assetID uint32, addr string) error {
+	return nil
+}
+func (c *TCore) RestrictWithdrawals(pw []byte, assetID uint32, restrict bool) error {
+	return nil
+}
 func (c *TCore) Notifications(n int) (notes, pokes []*db.Notification, _ error) {
 	return c.notes, []*db.Notification{}, c.notesErr
 }
@@ -968,6 +1005,54 @@ func TestAPIToggleWalletStatus(t *testing.T) {
 	}
 }
 
+func TestTradeHistoryRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantSince int64
+		wantUntil int64
+		wantErr   bool
+	}{{
+		name: "open range",
+	}, {
+		name:      "since and until",
+		query:     "since=1000&until=2000",
+		wantSince: 1000,
+		wantUntil: 2000,
+	}, {
+		name:      "since only",
+		query:     "since=1000",
+		wantSince: 1000,
+	}, {
+		name:    "bad since",
+		query:   "since=yesterday",
+		wantErr: true,
+	}, {
+		name:    "until before since",
+		query:   "since=2000&until=1000",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		since, until, err := tradeHistoryRange(q)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wanted error = %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if tt.wantErr {
+			continue
+		}
+		ms := func(tm time.Time) int64 {
+			if tm.IsZero() {
+				return 0
+			}
+			return tm.UnixMilli()
+		}
+		if ms(since) != tt.wantSince || ms(until) != tt.wantUntil {
+			t.Fatalf("%s: wanted range %d - %d, got %d - %d", tt.name, tt.wantSince, tt.wantUntil, ms(since), ms(until))
+		}
+	}
+}
+
 func TestAPIDeleteArchivedRecords(t *testing.T) {
 	s, tCore, shutdown := newTServer(t, false)
 	defer shutdown()
@@ -990,6 +1075,29 @@ func TestAPIDeleteArchivedRecords(t *testing.T) {
 	ensure(`{"ok":false,"msg":"expected dummy error"}`)
 }
 
+func TestAPIRestoreDBSnapshot(t *testing.T) {
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+	writer := new(TWriter)
+	reader := new(TReader)
+
+	body := &restoreDBSnapshotForm{
+		Pass: encode.PassBytes("abc"),
+		Name: "snap",
+	}
+	ensure := func(want string) {
+		ensureResponse(t, s.apiRestoreDBSnapshot, want, reader, writer, body, nil)
+	}
+
+	ensure(`{"ok":true}`)
+	if tCore.restoredSnapshot != "snap" {
+		t.Fatalf("snapshot not restored")
+	}
+
+	tCore.restoreErr = tErr
+	ensure(`{"ok":false,"msg":"expected dummy error"}`)
+}
+
 func TestAPITrade(t *testing.T) {
 	testTrade(t, false)
 }
@@ -1077,3 +1185,119 @@ func Test_prepareAddr(t *testing.T) {
 		}
 	}
 }
+
+func TestRESTAPIAuth(t *testing.T) {
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+	tCore.isInited = true
+
+	do := func(method, path, token string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		var b []byte
+		if body != nil {
+			b, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(b))
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("GET", "/api/v1/balances", "", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized without a token, got %d", rec.Code)
+	}
+	if rec := do("GET", "/api/v1/balances", "abcd", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized with a bad token, got %d", rec.Code)
+	}
+
+	// Login error
+	tCore.loginErr = tErr
+	rec := do("POST", "/api/v1/login", "", &loginForm{Pass: encode.PassBytes("def")})
+	if !strings.Contains(rec.Body.String(), `"ok":false`) {
+		t.Fatalf("expected login error, got %s", rec.Body.String())
+	}
+	tCore.loginErr = nil
+
+	rec = do("POST", "/api/v1/login", "", &loginForm{Pass: encode.PassBytes("def")})
+	var loginResp struct {
+		OK    bool   `json:"ok"`
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &loginResp); err != nil {
+		t.Fatalf("error decoding login response: %v", err)
+	}
+	if !loginResp.OK || loginResp.Token == "" {
+		t.Fatalf("no token in login response %s", rec.Body.String())
+	}
+	token := loginResp.Token
+
+	if rec := do("GET", "/api/v1/balances", token, nil); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"ok":true`) {
+		t.Fatalf("balances request failed: %d %s", rec.Code, rec.Body.String())
+	}
+	// Cookie sessions are not authorized by the API token.
+	if rec := do("GET", "/api/notes", token, nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected cookie route to reject API token, got %d", rec.Code)
+	}
+
+	if rec := do("POST", "/api/v1/logout", token, nil); rec.Code != http.StatusOK {
+		t.Fatalf("logout failed: %d", rec.Code)
+	}
+	if rec := do("GET", "/api/v1/balances", token, nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized after logout, got %d", rec.Code)
+	}
+
+	// Logging out of the app revokes all API tokens.
+	token = s.authorizeAPI()
+	s.deauth()
+	if rec := do("GET", "/api/v1/balances", token, nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized after deauth, got %d", rec.Code)
+	}
+}
+
+func TestOrderFilterFromQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+		check   func(*core.OrderFilter) bool
+	}{{
+		name:  "default",
+		query: "",
+		check: func(f *core.OrderFilter) bool { return f.N == 50 && f.Hosts == nil && f.Market == nil },
+	}, {
+		name:  "all",
+		query: "host=dex.tld&n=10&offset=0102&base=42&quote=0",
+		check: func(f *core.OrderFilter) bool {
+			return f.N == 10 && f.Hosts[0] == "dex.tld" && bytes.Equal(f.Offset, []byte{1, 2}) &&
+				f.Market.Base == 42 && f.Market.Quote == 0
+		},
+	}, {
+		name:    "bad n",
+		query:   "n=-1",
+		wantErr: true,
+	}, {
+		name:    "bad offset",
+		query:   "offset=xyz",
+		wantErr: true,
+	}, {
+		name:    "base without quote",
+		query:   "base=42",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		filter, err := orderFilterFromQuery(q)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wanted error %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if err == nil && !tt.check(filter) {
+			t.Fatalf("%s: wrong filter %+v", tt.name, filter)
+		}
+	}
+}
@@ -45,6 +45,12 @@ type wsClient struct {
 
 	feedMtx sync.RWMutex
 	feed    *bookFeed
+
+	noteMtx sync.RWMutex
+	// noteTypes are the core notification types that the client subscribed to
+	// with the 'subscribe' route. nil means not subscribed, and an empty map
+	// means all types.
+	noteTypes map[string]bool
 }
 
 func newWSClient(addr string, conn ws.Connection, hndlr func(msg *msgjson.Message) *msgjson.Error, logger dex.Logger) *wsClient {
@@ -54,6 +60,14 @@ func newWSClient(addr string, conn ws.Connection, hndlr func(msg *msgjson.Messag
 	}
 }
 
+// subscribed checks whether the client has subscribed to notifications of the
+// type.
+func (cl *wsClient) subscribed(noteType string) bool {
+	cl.noteMtx.RLock()
+	defer cl.noteMtx.RUnlock()
+	return cl.noteTypes != nil && (len(cl.noteTypes) == 0 || cl.noteTypes[noteType])
+}
+
 func (cl *wsClient) shutDownFeed() {
 	if cl.feed != nil {
 		cl.feed.loop.Stop()
@@ -189,6 +203,30 @@ func (s *Server) Notify(route string, payload any) {
 	}
 }
 
+// NotifySubscribers sends a core notification to the clients that have
+// subscribed to its type with the 'subscribe' route.
+func (s *Server) NotifySubscribers(route string, n core.Notification) {
+	var msg *msgjson.Message
+	s.clientsMtx.RLock()
+	defer s.clientsMtx.RUnlock()
+	for _, cl := range s.clients {
+		if !cl.subscribed(n.Type()) {
+			continue
+		}
+		if msg == nil {
+			var err error
+			if msg, err = msgjson.NewNotification(route, n); err != nil {
+				s.log.Errorf("%q notification encoding error: %v", route, err)
+				return
+			}
+		}
+		if err := cl.Send(msg); err != nil {
+			s.log.Warnf("Failed to send %v notification to client %v at %v: %v",
+				msg.Route, cl.cid, cl.Addr(), err)
+		}
+	}
+}
+
 // handleMessage handles the websocket message, calling the right handler for
 // the route.
 func (s *Server) handleMessage(conn *wsClient, msg *msgjson.Message) *msgjson.Error {
@@ -215,14 +253,18 @@ var wsHandlers = map[string]wsHandler{
 	"loadcandles": wsLoadCandles,
 	"unmarket":    wsUnmarket,
 	"acknotes":    wsAckNotes,
+	"subscribe":   wsSubscribe,
+	"unsubscribe": wsUnsubscribe,
 }
 
 // marketLoad is sent by websocket clients to subscribe to a market and request
-// the order book.
+// the order book. If DepthBinWidth is set, the client is also sent binned depth
+// data whenever the book changes. A DepthBinWidth of 1 bins by rate.
 type marketLoad struct {
-	Host  string `json:"host"`
-	Base  uint32 `json:"base"`
-	Quote uint32 `json:"quote"`
+	Host          string `json:"host"`
+	Base          uint32 `json:"base"`
+	Quote         uint32 `json:"quote"`
+	DepthBinWidth uint64 `json:"depthBinWidth,omitempty"`
 }
 
 type candlesLoad struct {
@@ -237,20 +279,63 @@ type marketSyncer struct {
 	log  dex.Logger
 	feed core.BookFeed
 	cl   *wsClient
+	// depthBinWidth is the bin width of the depth updates sent after book
+	// changes. Zero means no depth updates.
+	depthBinWidth uint64
 }
 
 // newMarketSyncer is the constructor for a marketSyncer, returned as a running
 // *dex.StartStopWaiter.
-func newMarketSyncer(cl *wsClient, feed core.BookFeed, log dex.Logger) *dex.StartStopWaiter {
+func newMarketSyncer(cl *wsClient, feed core.BookFeed, depthBinWidth uint64, log dex.Logger) *dex.StartStopWaiter {
 	ssWaiter := dex.NewStartStopWaiter(&marketSyncer{
-		feed: feed,
-		cl:   cl,
-		log:  log,
+		feed:          feed,
+		cl:            cl,
+		log:           log,
+		depthBinWidth: depthBinWidth,
 	})
 	ssWaiter.Start(context.Background()) // wrapping Run with a cancel bound to Stop
 	return ssWaiter
 }
 
+// send relays the BookUpdate to the websocket client as a notification.
+func (m *marketSyncer) send(update *core.BookUpdate) error {
+	note, err := msgjson.NewNotification(update.Action, update)
+	if err != nil {
+		m.log.Errorf("error encoding notification message: %v", err)
+		return err
+	}
+	if err = m.cl.Send(note); err != nil {
+		m.log.Debugf("send error. ending market feed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// sendDepth sends the client a depth update if it asked for them and the
+// BookUpdate changed the booked orders.
+func (m *marketSyncer) sendDepth(update *core.BookUpdate) error {
+	if m.depthBinWidth == 0 {
+		return nil
+	}
+	switch update.Action {
+	case core.FreshBookAction, core.BookOrderAction, core.UnbookOrderAction, core.UpdateRemainingAction:
+	default:
+		return nil
+	}
+	depth, err := m.feed.Depth(m.depthBinWidth)
+	if err != nil {
+		// The book may not be synced yet.
+		m.log.Debugf("error getting book depth: %v", err)
+		return nil
+	}
+	return m.send(&core.BookUpdate{
+		Action:   core.DepthUpdateAction,
+		Host:     update.Host,
+		MarketID: update.MarketID,
+		Payload:  depth,
+	})
+}
+
 // Run starts the marketSyncer listening for BookUpdates, which it relays to the
 // websocket client as notifications.
 func (m *marketSyncer) Run(ctx context.Context) {
@@ -262,14 +347,7 @@ out:
 				// We are skipping m.feed.Close if the feed were closed (external sig).
 				return
 			}
-			note, err := msgjson.NewNotification(update.Action, update)
-			if err != nil {
-				m.log.Errorf("error encoding notification message: %v", err)
-				break out
-			}
-			err = m.cl.Send(note)
-			if err != nil {
-				m.log.Debugf("send error. ending market feed: %v", err)
+			if m.send(update) != nil || m.sendDepth(update) != nil {
 				break out
 			}
 		case <-ctx.Done():
@@ -307,7 +385,7 @@ func loadMarket(s *Server, cl *wsClient, req *marketLoad) (*bookFeed, *msgjson.E
 	cl.shutDownFeed()
 	cl.feed = &bookFeed{
 		BookFeed: feed,
-		loop:     newMarketSyncer(cl, feed, s.log.SubLogger(name)),
+		loop:     newMarketSyncer(cl, feed, req.DepthBinWidth, s.log.SubLogger(name)),
 		host:     req.Host,
 		base:     req.Base,
 		quote:    req.Quote,
@@ -369,3 +447,41 @@ func wsAckNotes(s *Server, _ *wsClient, msg *msgjson.Message) *msgjson.Error {
 	s.core.AckNotes(ids)
 	return nil
 }
+
+// noteSubscription is sent by websocket clients with the 'subscribe' route to
+// receive core notifications, e.g. of order and match progress, balance
+// changes, and server connectivity.
+type noteSubscription struct {
+	// NoteTypes are the core.Notification types to send, e.g. "order",
+	// "match", "balance", and "conn". If empty, all types are sent.
+	NoteTypes []string `json:"noteTypes"`
+}
+
+// wsSubscribe is the handler for the 'subscribe' websocket route. The client
+// is sent notifications of the requested types until it unsubscribes or
+// disconnects. A new subscription replaces any previous one.
+func wsSubscribe(_ *Server, cl *wsClient, msg *msgjson.Message) *msgjson.Error {
+	req := new(noteSubscription)
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, req); err != nil {
+			return msgjson.NewError(msgjson.RPCParseError, "error unmarshalling subscribe payload: %v", err)
+		}
+	}
+	noteTypes := make(map[string]bool, len(req.NoteTypes))
+	for _, noteType := range req.NoteTypes {
+		noteTypes[noteType] = true
+	}
+	cl.noteMtx.Lock()
+	cl.noteTypes = noteTypes
+	cl.noteMtx.Unlock()
+	return nil
+}
+
+// wsUnsubscribe is the handler for the 'unsubscribe' websocket route. It ends
+// the client's notification subscription.
+func wsUnsubscribe(_ *Server, cl *wsClient, _ *msgjson.Message) *msgjson.Error {
+	cl.noteMtx.Lock()
+	cl.noteTypes = nil
+	cl.noteMtx.Unlock()
+	return nil
+}
@@ -131,15 +131,23 @@ func newTServer() (*Server, *TCore) {
 	return New(c, dex.StdOutLogger("TEST", dex.LevelTrace)), c
 }
 
-type tBookFeed struct{}
+type tBookFeed struct {
+	updates chan *core.BookUpdate
+}
 
-func (*tBookFeed) Next() <-chan *core.BookUpdate {
+func (f *tBookFeed) Next() <-chan *core.BookUpdate {
+	if f.updates != nil {
+		return f.updates
+	}
 	return make(chan *core.BookUpdate, 1)
 }
 func (*tBookFeed) Close() {}
 func (*tBookFeed) Candles(dur string) error {
 	return nil
 }
+func (*tBookFeed) Depth(binWidth uint64) (*orderbook.Depth, error) {
+	return &orderbook.Depth{BinWidth: binWidth}, nil
+}
 
 func TestMain(m *testing.M) {
 	var shutdown func()
@@ -220,6 +228,61 @@ func TestLoadMarket(t *testing.T) {
 	ensureGood()
 }
 
+func TestMarketSyncerDepth(t *testing.T) {
+	link := newLink()
+	link.conn.respReady = make(chan []byte, 4)
+	linkWg, err := link.cl.Connect(tCtx)
+	if err != nil {
+		t.Fatalf("WSLink Start: %v", err)
+	}
+	defer func() {
+		link.cl.Disconnect()
+		linkWg.Wait()
+	}()
+
+	feed := &tBookFeed{updates: make(chan *core.BookUpdate, 1)}
+	syncer := newMarketSyncer(link.cl, feed, 10, dex.StdOutLogger("TEST", dex.LevelTrace))
+	defer func() {
+		syncer.Stop()
+		syncer.WaitForShutdown()
+	}()
+
+	nextRoute := func() string {
+		t.Helper()
+		select {
+		case b := <-link.conn.respReady:
+			msg, err := msgjson.DecodeMessage(b)
+			if err != nil {
+				t.Fatalf("error decoding message: %v", err)
+			}
+			return msg.Route
+		case <-time.After(time.Second):
+			t.Fatalf("no message received")
+		}
+		return ""
+	}
+
+	// A book change is followed by a depth update.
+	feed.updates <- &core.BookUpdate{Action: core.BookOrderAction, Host: "abc", MarketID: "dcr_btc"}
+	if route := nextRoute(); route != core.BookOrderAction {
+		t.Fatalf("expected %s, got %s", core.BookOrderAction, route)
+	}
+	if route := nextRoute(); route != core.DepthUpdateAction {
+		t.Fatalf("expected %s, got %s", core.DepthUpdateAction, route)
+	}
+
+	// Other updates are only relayed.
+	feed.updates <- &core.BookUpdate{Action: core.EpochMatchSummary, Host: "abc", MarketID: "dcr_btc"}
+	if route := nextRoute(); route != core.EpochMatchSummary {
+		t.Fatalf("expected %s, got %s", core.EpochMatchSummary, route)
+	}
+	select {
+	case <-link.conn.respReady:
+		t.Fatalf("unexpected depth update")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 func TestHandleMessage(t *testing.T) {
 	link := newLink()
 	srv, _ := newTServer()
@@ -302,3 +365,80 @@ func TestClientMap(t *testing.T) {
 		t.Fatal("connection not closed on server shutdown")
 	}
 }
+
+type tNote struct {
+	core.Notification
+	noteType string
+}
+
+func (n *tNote) Type() string { return n.noteType }
+
+func TestNotifySubscribers(t *testing.T) {
+	srv, _ := newTServer()
+
+	link := newLink()
+	linkWg, err := link.cl.Connect(tCtx)
+	if err != nil {
+		t.Fatalf("WSLink Start: %v", err)
+	}
+	defer func() {
+		link.cl.Disconnect()
+		linkWg.Wait()
+	}()
+	srv.clients[link.cl.cid] = link.cl
+
+	orderNote := &tNote{noteType: core.NoteTypeOrder}
+	balanceNote := &tNote{noteType: core.NoteTypeBalance}
+
+	// sent checks whether the notification is sent to the client.
+	sent := func(n core.Notification) bool {
+		t.Helper()
+		srv.NotifySubscribers("notify", n)
+		select {
+		case b := <-link.conn.respReady:
+			msg, err := msgjson.DecodeMessage(b)
+			if err != nil {
+				t.Fatalf("error decoding notification: %v", err)
+			}
+			if msg.Route != "notify" {
+				t.Fatalf("wrong route %q", msg.Route)
+			}
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}
+
+	// Not subscribed.
+	if sent(orderNote) {
+		t.Fatalf("notification sent without subscription")
+	}
+
+	handle := func(route string, payload any) {
+		t.Helper()
+		msg, _ := msgjson.NewRequest(1, route, payload)
+		if msgErr := srv.handleMessage(link.cl, msg); msgErr != nil {
+			t.Fatalf("%q error: %d: %s", route, msgErr.Code, msgErr.Message)
+		}
+	}
+
+	// Subscribe to order notes only.
+	handle("subscribe", &noteSubscription{NoteTypes: []string{core.NoteTypeOrder}})
+	if !sent(orderNote) {
+		t.Fatalf("subscribed notification not sent")
+	}
+	if sent(balanceNote) {
+		t.Fatalf("unsubscribed notification type sent")
+	}
+
+	// Subscribe to all.
+	handle("subscribe", nil)
+	if !sent(orderNote) || !sent(balanceNote) {
+		t.Fatalf("notification not sent with subscription to all types")
+	}
+
+	handle("unsubscribe", nil)
+	if sent(orderNote) {
+		t.Fatalf("notification sent after unsubscribe")
+	}
+}
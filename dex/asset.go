@@ -139,6 +139,37 @@ type Asset struct {
 	UnitInfo   UnitInfo `json:"unitInfo"`
 }
 
+// DustPolicy describes when a UTXO-based asset's relay policy considers an
+// output to be dust. Transactions with outputs below the threshold are not
+// relayed, so swap contracts, change, and bonds must be at least this large.
+// Each UTXO-based asset's package in dex/networks defines its policy as
+// DustPolicy. The server validates swap outputs against it and reports it in
+// the asset's config, and it sets the minimum lot and bond sizes on both the
+// client and the server.
+type DustPolicy struct {
+	// FeeMultiplier is the multiple of the fee to create and spend an output,
+	// at the relay fee rate, below which the output is dust. Bitcoin Core
+	// uses 3. Zero disables the fee-based threshold.
+	FeeMultiplier uint64 `json:"feeMultiplier"`
+	// MinOutput is a fixed value in atoms below which an output is dust, e.g.
+	// Dogecoin's soft dust limit. Zero means no fixed threshold.
+	MinOutput uint64 `json:"minOutput,omitempty"`
+}
+
+// DefaultDustPolicy is Bitcoin Core's dust policy, which is shared by most
+// UTXO-based assets.
+var DefaultDustPolicy = DustPolicy{FeeMultiplier: 3}
+
+// Threshold is the smallest output value that is not dust, where size is the
+// serialized size of the output plus the input that spends it.
+func (p *DustPolicy) Threshold(size, feeRate uint64) uint64 {
+	threshold := p.FeeMultiplier * size * feeRate
+	if p.MinOutput > threshold {
+		return p.MinOutput
+	}
+	return threshold
+}
+
 // Denomination is a unit and its conversion factor.
 type Denomination struct {
 	Unit             string `json:"unit"`
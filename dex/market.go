@@ -39,6 +39,10 @@ const (
 	// tier*PerTierBaseParcelLimit*ParcelLimitScoreMultiplier. By default,
 	// singly-tiered users have max lot limits limited to the range (8,24).
 	ParcelLimitScoreMultiplier = 3
+
+	// MaxTradingFeeRate is the largest maker or taker trading fee rate that
+	// may be configured for a market, in parts per million (1%).
+	MaxTradingFeeRate = 10_000
 )
 
 // MarketInfo specifies a market that the Archiver must support.
@@ -52,6 +56,20 @@ type MarketInfo struct {
 	EpochDuration          uint64 // msec
 	MarketBuyBuffer        float64
 	MaxUserCancelsPerEpoch uint32
+	// MakerFeeRate and TakerFeeRate are the trading fees accrued by each side
+	// of a match, in parts per million of the amount they receive. Accrued
+	// fees are recorded when the match completes, but not collected.
+	MakerFeeRate uint64
+	TakerFeeRate uint64
+	// BaseSwapConf and QuoteSwapConf optionally override the SwapConf of the
+	// base and quote assets for swaps on this market. Zero means the asset's
+	// SwapConf applies.
+	BaseSwapConf  uint32
+	QuoteSwapConf uint32
+	// DisplayBand is the size, in lots, of the bands into which the displayed
+	// quantities of large booked orders are rounded down. Orders are matched
+	// on their true quantity. Zero disables banding.
+	DisplayBand uint64
 }
 
 func marketName(base, quote string) string {
@@ -143,3 +161,14 @@ func NewMarketInfoFromSymbols(base, quote string, lotSize, rateStep, epochDurati
 func (mi *MarketInfo) String() string {
 	return mi.Name
 }
+
+// TradingFee is the fee accrued by the maker or taker on a match in which they
+// receive qty units of an asset.
+func (mi *MarketInfo) TradingFee(qty uint64, maker bool) uint64 {
+	feeRate := mi.TakerFeeRate
+	if maker {
+		feeRate = mi.MakerFeeRate
+	}
+	// Split qty to avoid overflowing qty * feeRate.
+	return qty/1e6*feeRate + qty%1e6*feeRate/1e6
+}
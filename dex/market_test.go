@@ -4,6 +4,7 @@
 package dex
 
 import (
+	"math"
 	"os"
 	"testing"
 )
@@ -89,3 +90,23 @@ func TestNewMarketInfoFromSymbols(t *testing.T) {
 		t.Errorf("NewMarketInfoFromSymbols succeeded for non-existent quote asset")
 	}
 }
+
+func TestTradingFee(t *testing.T) {
+	mi := &MarketInfo{MakerFeeRate: 1_000, TakerFeeRate: 2_500}
+	tests := []struct {
+		qty     uint64
+		maker   bool
+		wantFee uint64
+	}{
+		{qty: 1e8, maker: true, wantFee: 1e5},
+		{qty: 1e8, maker: false, wantFee: 2.5e5},
+		{qty: 999, maker: true, wantFee: 0},
+		{qty: 1_234_567, maker: false, wantFee: 3_086},
+		{qty: math.MaxUint64, maker: true, wantFee: math.MaxUint64 / 1_000},
+	}
+	for _, tt := range tests {
+		if fee := mi.TradingFee(tt.qty, tt.maker); fee != tt.wantFee {
+			t.Fatalf("TradingFee(%d, %t): wanted %d, got %d", tt.qty, tt.maker, tt.wantFee, fee)
+		}
+	}
+}
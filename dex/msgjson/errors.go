@@ -0,0 +1,151 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package msgjson
+
+import "encoding/json"
+
+// ErrorCategory is a broad classification of an error code. Consumers should
+// use the code or its category to decide how to react to an Error rather than
+// matching on the Message, which is for humans and may change.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryUnknown is the category of codes not in the catalog.
+	ErrorCategoryUnknown ErrorCategory = ""
+	// ErrorCategoryFunding is for errors with the coins or transactions a
+	// user provides to fund orders, bonds, and swaps.
+	ErrorCategoryFunding ErrorCategory = "funding"
+	// ErrorCategoryValidation is for malformed, invalid, or out-of-sequence
+	// requests and their parameters.
+	ErrorCategoryValidation ErrorCategory = "validation"
+	// ErrorCategoryAuth is for errors with signatures, accounts, and their
+	// standing with the server.
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// ErrorCategoryBackend is for errors where the server or one of its
+	// asset backends is unable to process an otherwise acceptable request.
+	ErrorCategoryBackend ErrorCategory = "backend"
+)
+
+// ErrorCodeInfo describes a cataloged error code.
+type ErrorCodeInfo struct {
+	Name     string
+	Category ErrorCategory
+}
+
+// errorCatalog is the registry of the error codes that may be sent to clients
+// by the server. The codes are part of the protocol and must never be
+// renumbered.
+var errorCatalog = map[int]ErrorCodeInfo{
+	RPCParseError:              {"RPCParseError", ErrorCategoryValidation},
+	RPCUnknownRoute:            {"RPCUnknownRoute", ErrorCategoryValidation},
+	RPCInternal:                {"RPCInternal", ErrorCategoryBackend},
+	RPCQuarantineClient:        {"RPCQuarantineClient", ErrorCategoryAuth},
+	RPCVersionUnsupported:      {"RPCVersionUnsupported", ErrorCategoryValidation},
+	RPCUnknownMatch:            {"RPCUnknownMatch", ErrorCategoryValidation},
+	SignatureError:             {"SignatureError", ErrorCategoryAuth},
+	SerializationError:         {"SerializationError", ErrorCategoryValidation},
+	TransactionUndiscovered:    {"TransactionUndiscovered", ErrorCategoryBackend},
+	ContractError:              {"ContractError", ErrorCategoryFunding},
+	SettlementSequenceError:    {"SettlementSequenceError", ErrorCategoryValidation},
+	ResultLengthError:          {"ResultLengthError", ErrorCategoryValidation},
+	IDMismatchError:            {"IDMismatchError", ErrorCategoryValidation},
+	RedemptionError:            {"RedemptionError", ErrorCategoryFunding},
+	IDTypeError:                {"IDTypeError", ErrorCategoryValidation},
+	AckCountError:              {"AckCountError", ErrorCategoryValidation},
+	UnknownResponseID:          {"UnknownResponseID", ErrorCategoryValidation},
+	OrderParameterError:        {"OrderParameterError", ErrorCategoryValidation},
+	UnknownMarketError:         {"UnknownMarketError", ErrorCategoryValidation},
+	ClockRangeError:            {"ClockRangeError", ErrorCategoryValidation},
+	FundingError:               {"FundingError", ErrorCategoryFunding},
+	CoinAuthError:              {"CoinAuthError", ErrorCategoryFunding},
+	UnknownMarket:              {"UnknownMarket", ErrorCategoryValidation},
+	NotSubscribedError:         {"NotSubscribedError", ErrorCategoryValidation},
+	UnauthorizedConnection:     {"UnauthorizedConnection", ErrorCategoryAuth},
+	AuthenticationError:        {"AuthenticationError", ErrorCategoryAuth},
+	PubKeyParseError:           {"PubKeyParseError", ErrorCategoryAuth},
+	FeeError:                   {"FeeError", ErrorCategoryFunding},
+	InvalidPreimage:            {"InvalidPreimage", ErrorCategoryValidation},
+	PreimageCommitmentMismatch: {"PreimageCommitmentMismatch", ErrorCategoryValidation},
+	UnknownMessageType:         {"UnknownMessageType", ErrorCategoryValidation},
+	AccountClosedError:         {"AccountClosedError", ErrorCategoryAuth},
+	MarketNotRunningError:      {"MarketNotRunningError", ErrorCategoryBackend},
+	TryAgainLaterError:         {"TryAgainLaterError", ErrorCategoryBackend},
+	AccountNotFoundError:       {"AccountNotFoundError", ErrorCategoryAuth},
+	UnpaidAccountError:         {"UnpaidAccountError", ErrorCategoryAuth},
+	InvalidRequestError:        {"InvalidRequestError", ErrorCategoryValidation},
+	OrderQuantityTooHigh:       {"OrderQuantityTooHigh", ErrorCategoryValidation},
+	HTTPRouteError:             {"HTTPRouteError", ErrorCategoryBackend},
+	RouteUnavailableError:      {"RouteUnavailableError", ErrorCategoryBackend},
+	AccountExistsError:         {"AccountExistsError", ErrorCategoryAuth},
+	AccountSuspendedError:      {"AccountSuspendedError", ErrorCategoryAuth},
+	TooManyRequestsError:       {"TooManyRequestsError", ErrorCategoryBackend},
+	DuplicateRequestError:      {"DuplicateRequestError", ErrorCategoryValidation},
+	BondError:                  {"BondError", ErrorCategoryFunding},
+	BondAlreadyConfirmingError: {"BondAlreadyConfirmingError", ErrorCategoryFunding},
+	OutdatedClientError:        {"OutdatedClientError", ErrorCategoryValidation},
+	UnknownCancelTargetError:   {"UnknownCancelTargetError", ErrorCategoryValidation},
+}
+
+// LookupErrorCode returns the catalog entry for the error code. The second
+// return value is false if the code is not in the catalog.
+func LookupErrorCode(code int) (ErrorCodeInfo, bool) {
+	info, found := errorCatalog[code]
+	return info, found
+}
+
+// ErrorCodeCategory is the category of the error code, or ErrorCategoryUnknown
+// if the code is not in the catalog.
+func ErrorCodeCategory(code int) ErrorCategory {
+	return errorCatalog[code].Category
+}
+
+// Category is the category of the Error's code.
+func (e *Error) Category() ErrorCategory {
+	return ErrorCodeCategory(e.Code)
+}
+
+// Order fields that are subject to a market's step sizes.
+const (
+	StepFieldRate = "rate"
+	StepFieldQty  = "qty"
+)
+
+// OrderStepHint is the Data of an OrderParameterError for an order with a rate
+// that is not a multiple of the market's rate step, or a quantity that is not a
+// multiple of the lot size. Lower and Upper are the nearest valid values on
+// either side of the invalid Value. Lower is omitted if there is no valid value
+// below Value.
+type OrderStepHint struct {
+	Field string `json:"field"`
+	Value uint64 `json:"value"`
+	Step  uint64 `json:"step"`
+	Lower uint64 `json:"lower,omitempty"`
+	Upper uint64 `json:"upper"`
+}
+
+// NewOrderStepHint creates an OrderStepHint for the value of the field with
+// the given step size.
+func NewOrderStepHint(field string, value, step uint64) *OrderStepHint {
+	lower := value - value%step
+	return &OrderStepHint{
+		Field: field,
+		Value: value,
+		Step:  step,
+		Lower: lower,
+		Upper: lower + step,
+	}
+}
+
+// OrderStepHint decodes the Error's Data as an *OrderStepHint. The bool is
+// false if the Error is not an OrderParameterError with a step hint.
+func (e *Error) OrderStepHint() (*OrderStepHint, bool) {
+	if e.Code != OrderParameterError || len(e.Data) == 0 {
+		return nil, false
+	}
+	hint := new(OrderStepHint)
+	if err := json.Unmarshal(e.Data, hint); err != nil || hint.Step == 0 {
+		return nil, false
+	}
+	return hint, true
+}
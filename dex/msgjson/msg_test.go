@@ -705,6 +705,22 @@ func TestMarket(t *testing.T) {
 
 	comparePrefix(t, &marketBack.Prefix, &market.Prefix)
 	compareTrade(t, &marketBack.Trade, &market.Trade)
+
+	// A worst rate is appended to the serialization.
+	noLimit := market.Serialize()
+	market.WorstRate = 123_456
+	b = market.Serialize()
+	if !bytes.Equal(b[:len(noLimit)], noLimit) || !bytes.Equal(b[len(noLimit):], uint64Bytes(123_456)) {
+		t.Fatalf("worst rate not serialized")
+	}
+	marketB, _ = json.Marshal(market)
+	marketBack = MarketOrder{}
+	if err = json.Unmarshal(marketB, &marketBack); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if marketBack.WorstRate != market.WorstRate {
+		t.Fatalf("wrong worst rate %d", marketBack.WorstRate)
+	}
 }
 
 func TestCancel(t *testing.T) {
@@ -1122,6 +1138,65 @@ func comparePrefix(t *testing.T, p1, p2 *Prefix) {
 	}
 }
 
+func TestErrorCatalog(t *testing.T) {
+	names := make(map[string]bool, len(errorCatalog))
+	for code, info := range errorCatalog {
+		if info.Name == "" || names[info.Name] {
+			t.Fatalf("code %d has an empty or duplicate name %q", code, info.Name)
+		}
+		names[info.Name] = true
+		switch info.Category {
+		case ErrorCategoryFunding, ErrorCategoryValidation, ErrorCategoryAuth, ErrorCategoryBackend:
+		default:
+			t.Fatalf("code %d (%s) has invalid category %q", code, info.Name, info.Category)
+		}
+	}
+
+	var err error = NewError(FundingError, "bad coins")
+	var msgErr *Error
+	if !errors.As(err, &msgErr) || msgErr.Category() != ErrorCategoryFunding {
+		t.Fatalf("wrong category for funding error")
+	}
+	if cat := ErrorCodeCategory(RPCLoginError); cat != ErrorCategoryUnknown {
+		t.Fatalf("client-only code has category %q", cat)
+	}
+	if _, found := LookupErrorCode(RPCLoginError); found {
+		t.Fatalf("client-only code found in catalog")
+	}
+}
+
+func TestOrderStepHint(t *testing.T) {
+	hint := NewOrderStepHint(StepFieldRate, 1234, 100)
+	if hint.Lower != 1200 || hint.Upper != 1300 {
+		t.Fatalf("wrong bounds %d, %d", hint.Lower, hint.Upper)
+	}
+	if hint = NewOrderStepHint(StepFieldQty, 50, 100); hint.Lower != 0 || hint.Upper != 100 {
+		t.Fatalf("wrong bounds below step %d, %d", hint.Lower, hint.Upper)
+	}
+
+	// Round trip through a response.
+	msgErr := NewErrorWithData(OrderParameterError, NewOrderStepHint(StepFieldQty, 250, 100), "bad qty")
+	resp, _ := NewResponse(1, nil, msgErr)
+	b, _ := json.Marshal(resp)
+	msg, err := DecodeMessage(b)
+	if err != nil {
+		t.Fatalf("DecodeMessage error: %v", err)
+	}
+	var reErr *Error
+	if err = msg.UnmarshalResult(nil); !errors.As(err, &reErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	hint, found := reErr.OrderStepHint()
+	if !found || hint.Field != StepFieldQty || hint.Lower != 200 || hint.Upper != 300 {
+		t.Fatalf("wrong decoded hint %+v", hint)
+	}
+
+	// Other codes have no hint.
+	if _, found = NewError(FundingError, "").OrderStepHint(); found {
+		t.Fatalf("found hint for error without data")
+	}
+}
+
 func compareTrade(t *testing.T, t1, t2 *Trade) {
 	if t1.Side != t2.Side {
 		t.Fatal(t1.Side, t2.Side)
@@ -98,8 +98,27 @@ const (
 	RPCUpdateRunningBotCfgError          // 80
 	RPCUpdateRunningBotInvError          // 81
 	RPCMMStatusError                     // 82
+	OutdatedClientError                  // 83
+	RPCAddressBookError                  // 84
+	UnknownCancelTargetError             // 85
+	RPCPreOrderError                     // 86
+	RPCExportAccountError                // 87
+	RPCImportAccountError                // 88
+	RPCOrderHistoryError                 // 89
+	RPCPreAccelerateError                // 90
+	RPCAccelerationEstimateError         // 91
+	RPCAccelerateOrderError              // 92
+	RPCChangeAppPassError                // 93
+	RPCSetWalletPassError                // 94
+	RPCLockedBalanceError                // 95
+	RPCDBSnapshotError                   // 96
 )
 
+// UnknownCancelTargetAPIVersion is the API version from which a cancel order
+// for an unknown target order is rejected with UnknownCancelTargetError. It
+// is rejected with OrderParameterError for clients with an older version.
+const UnknownCancelTargetAPIVersion = 4
+
 // Routes are destinations for a "payload" of data. The type of data being
 // delivered, and what kind of action is expected from the receiving party, is
 // completely dependent on the route. The route designation is a string sent as
@@ -117,6 +136,11 @@ const (
 	// OrderStatusRoute is the route of a client-originating request-type
 	// message to retrieve order data from the DEX.
 	OrderStatusRoute = "order_status"
+	// ReputationRoute is the route of a client-originating request-type
+	// message to retrieve the breakdown of the user's reputation, i.e. the
+	// outcomes from which their score is computed and the thresholds at which
+	// they are penalized.
+	ReputationRoute = "reputation"
 	// InitRoute is the route of a client-originating request-type message
 	// notifying the DEX, and subsequently the match counter-party, of the details
 	// of a swap contract.
@@ -153,6 +177,11 @@ const (
 	// UnsubOrderBookRoute is client-originating request-type message cancelling
 	// an order book subscription.
 	UnsubOrderBookRoute = "unsub_orderbook"
+	// BookResyncRoute is a client-originating request-type message requesting
+	// the sequenced book updates that followed a given sequence number. It is
+	// used to recover from a gap in the book update feed without
+	// re-subscribing.
+	BookResyncRoute = "book_resync"
 	// BookOrderRoute is the DEX-originating notification-type message informing
 	// the client to add the order to the order book.
 	BookOrderRoute = "book_order"
@@ -215,6 +244,10 @@ const (
 	// PenaltyRoute is the DEX-originating notification-type message
 	// informing of a broken rule and the resulting penalty.
 	PenaltyRoute = "penalty"
+	// ConfigUpdateRoute is the DEX-originating notification-type message
+	// informing clients of changes to the account conduct settings in the
+	// 'config' response.
+	ConfigUpdateRoute = "config_update"
 	// SpotsRoute is the client-originating HTTP or WebSocket request to get the
 	// spot price and volume for the DEX's markets.
 	SpotsRoute = "spots"
@@ -230,6 +263,14 @@ const (
 	// CandlesRoute is the HTTP request to get the set of candlesticks
 	// representing market activity history.
 	CandlesRoute = "candles"
+	// CandleHistoryRoute is the HTTP request to get a page of stored
+	// candlesticks in a time range. Unlike CandlesRoute, which serves only the
+	// recent candles, this is suitable for backfilling a market's full history.
+	CandleHistoryRoute = "candle_history"
+	// EpochProofRoute is the HTTP request to get the match proof of a past
+	// epoch, with which the commitment checksum, shuffle seed, and matching
+	// order of the epoch queue can be verified.
+	EpochProofRoute = "epoch_proof"
 )
 
 const errNullRespPayload = dex.ErrorKind("null response payload")
@@ -278,6 +319,9 @@ type Acknowledgement struct {
 type Error struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
+	// Data is optional structured detail about the error. Its type depends
+	// on the Code, e.g. an *OrderStepHint for some OrderParameterErrors.
+	Data json.RawMessage `json:"data,omitempty"`
 }
 
 // Error returns the error message. Satisfies the error interface.
@@ -298,6 +342,16 @@ func NewError(code int, format string, a ...any) *Error {
 	}
 }
 
+// NewErrorWithData is a constructor for an Error with Data. If data cannot be
+// encoded, the Error has no Data.
+func NewErrorWithData(code int, data any, format string, a ...any) *Error {
+	msgErr := NewError(code, format, a...)
+	if b, err := json.Marshal(data); err == nil {
+		msgErr.Data = b
+	}
+	return msgErr
+}
+
 // ResponsePayload is the payload for a Response-type Message.
 type ResponsePayload struct {
 	// Result is the payload, if successful, else nil.
@@ -774,13 +828,21 @@ func (l *LimitOrder) Serialize() []byte {
 type MarketOrder struct {
 	Prefix
 	Trade
+	// WorstRate is the optional slippage limit, the highest rate at which a
+	// buy order or the lowest rate at which a sell order may be filled.
+	WorstRate uint64 `json:"worstrate,omitempty"`
 }
 
 // Serialize serializes the MarketOrder data.
 func (m *MarketOrder) Serialize() []byte {
 	// serialization: prefix (89) + trade (varies) + address (35 ish)
+	// + worst rate (8, if set)
 	b := append(m.Prefix.Serialize(), m.Trade.Serialize()...)
-	return append(b, []byte(m.Trade.Address)...)
+	b = append(b, []byte(m.Trade.Address)...)
+	if m.WorstRate > 0 {
+		b = append(b, uint64Bytes(m.WorstRate)...)
+	}
+	return b
 }
 
 // CancelOrder is the payload for the CancelRoute, which places a cancel order.
@@ -822,6 +884,25 @@ type UnsubOrderBook struct {
 	MarketID string `json:"marketid"`
 }
 
+// BookResync is the payload for a client-originating request to the
+// BookResyncRoute. Seq is the last sequence number that the client applied to
+// its book.
+type BookResync struct {
+	MarketID string `json:"marketid"`
+	Seq      uint64 `json:"seq"`
+}
+
+// BookResyncResult is the response to a BookResync request. Updates are the
+// sequenced book update notifications following the requested sequence
+// number, in order. If the server no longer has all of the updates needed to
+// fill the gap, Updates will be empty and Snapshot will contain the full order
+// book instead.
+type BookResyncResult struct {
+	MarketID string     `json:"marketid"`
+	Updates  []*Message `json:"updates,omitempty"`
+	Snapshot *OrderBook `json:"snapshot,omitempty"`
+}
+
 // orderbook subscription notification payloads include: BookOrderNote,
 // UnbookOrderNote, EpochOrderNote, and MatchProofNote.
 
@@ -928,10 +1009,29 @@ type PreimageResponse struct {
 	Preimage Bytes `json:"pimg"`
 }
 
+// AccountIDHeader is the HTTP header in which a client gives its hex-encoded
+// account ID when it opens a websocket connection. The header is not
+// authenticated. A server that is draining connections uses it only to admit
+// the reconnects of clients with active swaps.
+const AccountIDHeader = "X-Dex-Account-Id"
+
+// DrainResponse is the JSON body of the 503 Service Unavailable response to a
+// websocket connection attempt while the server is draining connections.
+type DrainResponse struct {
+	Error string `json:"error"`
+	// RetryAddr, if set, is the websocket URL at which the client should
+	// reconnect, e.g. wss://dex2.example.com:7232/ws. It must have the same
+	// scheme as the refused URL, and the server at RetryAddr must present a
+	// certificate that is valid for the original host.
+	RetryAddr string `json:"retryAddr,omitempty"`
+}
+
 // Connect is the payload for a client-originating ConnectRoute request.
 type Connect struct {
 	Signature
-	AccountID  Bytes  `json:"accountid"`
+	AccountID Bytes `json:"accountid"`
+	// APIVersion is the API version the client will speak, which should be
+	// within the range advertised in the server's ConfigResult.
 	APIVersion uint16 `json:"apiver"`
 	Time       uint64 `json:"timestamp"`
 }
@@ -966,6 +1066,10 @@ type ConnectResult struct {
 	Score               int32               `json:"score"`
 	ActiveBonds         []*Bond             `json:"activeBonds"`
 	Reputation          *account.Reputation `json:"reputation"`
+	// APIVersion is the API version negotiated for this connection, the
+	// lesser of the version in the 'connect' request and the server's
+	// ConfigResult.APIVersion. Servers that predate negotiation omit it.
+	APIVersion uint16 `json:"apiver,omitempty"`
 }
 
 // TierChangedNotification is the dex-originating notification sent when the
@@ -994,6 +1098,39 @@ type ScoreChangedNotification struct {
 	Reputation account.Reputation `json:"reputation"`
 }
 
+// ReputationReport is the result of a ReputationRoute request, and details how
+// the user's score is computed from their recent conduct.
+type ReputationReport struct {
+	Reputation *account.Reputation `json:"reputation"`
+	// PenaltyThreshold is the score deficit for each penalty, i.e. a tier is
+	// revoked for every PenaltyThreshold below zero the score falls.
+	PenaltyThreshold int32 `json:"penaltythreshold"`
+	// MaxScore is the highest attainable score.
+	MaxScore int32 `json:"maxscore"`
+	// Swap outcomes of the user's most recent matches. Matches that ended
+	// without fault by the user are not included.
+	SwapsCompleted  uint32 `json:"swapscompleted"`
+	NoSwapAsMaker   uint32 `json:"noswapasmaker"`
+	NoSwapAsTaker   uint32 `json:"noswapastaker"`
+	NoRedeemAsMaker uint32 `json:"noredeemasmaker"`
+	NoRedeemAsTaker uint32 `json:"noredeemastaker"`
+	Forgiven        uint32 `json:"forgiven"`
+	// PreimageMisses is the number of preimage requests for the user's
+	// recent orders that went unanswered.
+	PreimageMisses uint32 `json:"preimagemisses"`
+	// Orders and Cancels are the number of the user's recently finished
+	// orders, and how many of those were canceled.
+	Orders  uint32 `json:"orders"`
+	Cancels uint32 `json:"cancels"`
+	// CancelRatio is Cancels / Orders. The user is penalized if CancelRatio
+	// exceeds CancelThreshold with more than GraceLimit orders, unless
+	// FreeCancels is set.
+	CancelRatio     float64 `json:"cancelratio"`
+	CancelThreshold float64 `json:"cancelthreshold"`
+	GraceLimit      uint32  `json:"gracelimit"`
+	FreeCancels     bool    `json:"freecancels,omitempty"`
+}
+
 // Serialize serializes the ScoreChangedNotification data.
 func (tc *ScoreChangedNotification) Serialize() []byte {
 	// serialization: bondedTier 8 + penalties 2 + score 4
@@ -1223,7 +1360,27 @@ type Market struct {
 	RateStep        uint64  `json:"ratestep"`
 	MarketBuyBuffer float64 `json:"buybuffer"`
 	ParcelSize      uint32  `json:"parcelSize"`
-	MarketStatus    `json:"status"`
+	// BaseSwapConf and QuoteSwapConf override the assets' SwapConf for this
+	// market if non-zero.
+	BaseSwapConf  uint32 `json:"baseswapconf,omitempty"`
+	QuoteSwapConf uint32 `json:"quoteswapconf,omitempty"`
+	// DisplayBand is the size, in lots, of the bands into which the server
+	// rounds down the quantities of booked limit orders larger than one band.
+	// Zero if the book quantities are exact.
+	DisplayBand  uint64 `json:"displayband,omitempty"`
+	MarketStatus `json:"status"`
+}
+
+// SwapConf is the market's override of the asset's SwapConf, or zero if there
+// is no override and the asset's SwapConf applies.
+func (m *Market) SwapConf(assetID uint32) uint32 {
+	switch assetID {
+	case m.Base:
+		return m.BaseSwapConf
+	case m.Quote:
+		return m.QuoteSwapConf
+	}
+	return 0
 }
 
 // Running indicates if the market should be running given the known StartEpoch,
@@ -1245,6 +1402,9 @@ type Asset struct {
 	MaxFeeRate uint64       `json:"maxfeerate"`
 	SwapConf   uint16       `json:"swapconf"`
 	UnitInfo   dex.UnitInfo `json:"unitinfo"`
+	// Dust is the dust policy the server applies to swap outputs and change.
+	// It is omitted for account-based assets.
+	Dust *dex.DustPolicy `json:"dust,omitempty"`
 }
 
 // BondAsset describes an asset for which fidelity bonds are supported.
@@ -1257,10 +1417,15 @@ type BondAsset struct {
 
 // ConfigResult is the successful result for the ConfigRoute.
 type ConfigResult struct {
-	// APIVersion is the server's communications API version, but we may
-	// consider APIVersions []uint16, with versioned routes e.g. "initV2".
-	// APIVersions []uint16 `json:"apivers"`
-	APIVersion       uint16    `json:"apiver"`
+	// APIVersion is the server's current communications API version, the
+	// newest it supports. Together with MinAPIVersion, it defines the range of
+	// versions a client may request in 'connect'. Clients should use the
+	// newest version in the range that they support.
+	APIVersion uint16 `json:"apiver"`
+	// MinAPIVersion is the oldest client API version that the server will
+	// accept in a 'connect' request. Clients reporting an older APIVersion
+	// are refused with an OutdatedClientError.
+	MinAPIVersion    uint16    `json:"minapiver,omitempty"`
 	DEXPubKey        dex.Bytes `json:"pubkey"`
 	CancelMax        float64   `json:"cancelmax"`
 	BroadcastTimeout uint64    `json:"btimeout"`
@@ -1281,6 +1446,20 @@ type ConfigResult struct {
 
 	PenaltyThreshold uint32 `json:"penaltyThreshold"`
 	MaxScore         uint32 `json:"maxScore"`
+	// FreeCancels indicates that the cancellation rate is not enforced, in
+	// which case CancelMax does not apply.
+	FreeCancels bool `json:"freecancels,omitempty"`
+}
+
+// ConfigUpdate is the payload for the ConfigUpdateRoute notification. The
+// fields replace those of the same name in the ConfigResult. Each of Markets
+// replaces the market of the same name, and markets that are not listed are
+// unchanged.
+type ConfigUpdate struct {
+	CancelMax        float64   `json:"cancelmax"`
+	FreeCancels      bool      `json:"freecancels,omitempty"`
+	PenaltyThreshold uint32    `json:"penaltyThreshold"`
+	Markets          []*Market `json:"markets,omitempty"`
 }
 
 // Spot is a snapshot of a market at the end of a match cycle. A slice of Spot
@@ -1305,6 +1484,87 @@ type CandlesRequest struct {
 	NumCandles int    `json:"numCandles,omitempty"` // default and max defined in apidata.
 }
 
+// CandleHistoryRequest is a data API request for a page of stored candles.
+type CandleHistoryRequest struct {
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+	BinSize string `json:"binSize"`
+	// Start and End are the time range in milliseconds. Candles that start
+	// at or after Start and end at or before End are returned. An End of zero
+	// is the present.
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end,omitempty"`
+	// Count is the maximum number of candles to return. The default and
+	// maximum are defined in apidata.
+	Count int `json:"count,omitempty"`
+}
+
+// CandleHistory is the result of a CandleHistoryRequest. Candles are in
+// chronological order.
+type CandleHistory struct {
+	Candles *WireCandles `json:"candles"`
+	// Next is the Start of the request for the next page. Next is zero if
+	// there are no more candles in the requested range.
+	Next uint64 `json:"next,omitempty"`
+}
+
+// EpochProofRequest is a data API request for the match proof of a past epoch
+// of the market's current epoch duration.
+type EpochProofRequest struct {
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+	Epoch   uint64 `json:"epoch"`
+}
+
+// EpochProofOrder is an order in an EpochProof. Preimage is empty for orders
+// whose preimage was not revealed. OrderType is one of LimitOrderNum,
+// MarketOrderNum, or CancelOrderNum, and is zero if the order is no longer
+// stored, in which case the remaining order details are also zero. Side and
+// Quantity are set for limit and market orders, Rate for limit orders,
+// WorstRate for market orders that committed to one, and TargetID for cancel
+// orders.
+type EpochProofOrder struct {
+	OrderID   Bytes  `json:"oid"`
+	Commit    Bytes  `json:"commit"`
+	Preimage  Bytes  `json:"preimage,omitempty"`
+	OrderType uint8  `json:"ordertype,omitempty"`
+	Side      uint8  `json:"side,omitempty"`
+	Quantity  uint64 `json:"ordersize,omitempty"`
+	Rate      uint64 `json:"rate,omitempty"`
+	WorstRate uint64 `json:"worstrate,omitempty"`
+	TargetID  Bytes  `json:"targetid,omitempty"`
+}
+
+// EpochProofMatch is a trade match made in the epoch of an EpochProof.
+type EpochProofMatch struct {
+	MatchID  Bytes  `json:"matchid"`
+	MakerOID Bytes  `json:"makeroid"`
+	TakerOID Bytes  `json:"takeroid"`
+	Quantity uint64 `json:"qty"`
+	Rate     uint64 `json:"rate"`
+}
+
+// EpochProof is the match proof of a past epoch. CSum is the Blake-256 hash of
+// the commitments of all Queue and Misses orders, sorted lexicographically.
+// Seed is the Blake-256 hash of the Queue preimages, sorted by order ID. Queue
+// is in the order matched, which is the Fisher-Yates shuffle of the ID-sorted
+// queue using an MT19937 source seeded with Seed. Matches are the trades made
+// in the epoch, listed for reference. The proof does not include the state of
+// the book before the epoch, so it does not prove that the queue was matched
+// correctly against the book. It only shows that matched takers were in the
+// queue and that match rates are within their limit and worst rates.
+type EpochProof struct {
+	MarketID  string             `json:"marketid"`
+	Epoch     uint64             `json:"epoch"`
+	EpochDur  uint64             `json:"epochdur"`
+	MatchTime uint64             `json:"matchtime"`
+	CSum      Bytes              `json:"csum"`
+	Seed      Bytes              `json:"seed"`
+	Queue     []*EpochProofOrder `json:"queue"`
+	Misses    []*EpochProofOrder `json:"misses"`
+	Matches   []*EpochProofMatch `json:"matches"`
+}
+
 // Candle is a statistical history of a specified period of market activity.
 type Candle struct {
 	StartStamp  uint64 `json:"startStamp"`
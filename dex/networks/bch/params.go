@@ -37,6 +37,9 @@ var (
 		},
 		FeeRateDenom: "B",
 	}
+	// DustPolicy is the dust policy of Bitcoin Cash nodes, which kept Bitcoin's
+	// fee-based threshold after the fork.
+	DustPolicy = dex.DefaultDustPolicy
 	// MainNetParams are the clone parameters for mainnet.
 	MainNetParams = btc.ReadCloneParams(&btc.CloneParams{
 		Name:             "mainnet",
@@ -4,6 +4,8 @@
 package btc
 
 import (
+	"math/big"
+
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -30,6 +32,7 @@ func ReadCloneParams(cloneParams *CloneParams) *chaincfg.Params {
 		HDPrivateKeyID:   cloneParams.HDPrivateKeyID,
 		HDPublicKeyID:    cloneParams.HDPublicKeyID,
 		GenesisHash:      cloneParams.GenesisHash,
+		PowLimit:         cloneParams.PowLimit,
 	}
 }
 
@@ -61,4 +64,7 @@ type CloneParams struct {
 	// These are not required by the client.
 	HDPrivateKeyID [4]byte
 	HDPublicKeyID  [4]byte
+	// PowLimit is the highest proof-of-work target. It is optional, and is
+	// only used to check block headers from untrusted sources.
+	PowLimit *big.Int
 }
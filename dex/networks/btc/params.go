@@ -23,3 +23,6 @@ var UnitInfo = dex.UnitInfo{
 	},
 	FeeRateDenom: "vB",
 }
+
+// DustPolicy is Bitcoin Core's dust policy.
+var DustPolicy = dex.DefaultDustPolicy
@@ -286,7 +286,7 @@ func RefundBondTxSize(segwit bool) uint64 {
 
 // minHTLCValue calculates the minimum value for the output of a chained
 // P2SH -> P2WPKH transaction pair where the spending tx size is known.
-func minHTLCValue(maxFeeRate, redeemTxSize uint64, segwit bool) uint64 {
+func minHTLCValue(dust *dex.DustPolicy, maxFeeRate, redeemTxSize uint64, segwit bool) uint64 {
 	// Reversing IsDustVal.
 	// totalSize adds some buffer for the spending transaction.
 	var outputSize uint64 = P2PKHOutputSize // larger of p2sh output and p2pkh output.
@@ -299,7 +299,7 @@ func minHTLCValue(maxFeeRate, redeemTxSize uint64, segwit bool) uint64 {
 	} else {
 		totalSize += 107
 	}
-	minInitTxValue := maxFeeRate * totalSize * 3
+	minInitTxValue := dust.Threshold(totalSize, maxFeeRate)
 
 	// The minInitTxValue would get the pssh tx accepted, but when we go to
 	// redemption, we need that output to pass too, so we need to add the fees
@@ -310,17 +310,27 @@ func minHTLCValue(maxFeeRate, redeemTxSize uint64, segwit bool) uint64 {
 }
 
 // MinBondSize is the minimum bond size that avoids dust for a given max network
-// fee rate.
+// fee rate under Bitcoin's DustPolicy.
 func MinBondSize(maxFeeRate uint64, segwit bool) uint64 {
-	refundTxSize := RefundBondTxSize(segwit)
-	return minHTLCValue(maxFeeRate, refundTxSize, segwit)
+	return MinBondSizeForDust(&DustPolicy, maxFeeRate, segwit)
 }
 
 // MinLotSize is the minimum lot size that avoids dust for a given max network
-// fee rate.
+// fee rate under Bitcoin's DustPolicy.
 func MinLotSize(maxFeeRate uint64, segwit bool) uint64 {
+	return MinLotSizeForDust(&DustPolicy, maxFeeRate, segwit)
+}
+
+// MinBondSizeForDust is like MinBondSize, but for a clone's dust policy.
+func MinBondSizeForDust(dust *dex.DustPolicy, maxFeeRate uint64, segwit bool) uint64 {
+	refundTxSize := RefundBondTxSize(segwit)
+	return minHTLCValue(dust, maxFeeRate, refundTxSize, segwit)
+}
+
+// MinLotSizeForDust is like MinLotSize, but for a clone's dust policy.
+func MinLotSizeForDust(dust *dex.DustPolicy, maxFeeRate uint64, segwit bool) uint64 {
 	redeemSize := RedeemSwapTxSize(segwit)
-	return minHTLCValue(maxFeeRate, redeemSize, segwit)
+	return minHTLCValue(dust, maxFeeRate, redeemSize, segwit)
 }
 
 // BTCScriptType holds details about a pubkey script and possibly it's redeem
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"decred.org/dcrdex/dex"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -261,6 +262,27 @@ func TestIsDust(t *testing.T) {
 	}
 }
 
+func TestMinLotSizeForDust(t *testing.T) {
+	const feeRate = 10
+	redeemFees := RedeemSwapTxSize(true) * feeRate
+
+	// The default policy is 3x the fee to create and spend the output.
+	spendSize := uint64(P2WSHOutputSize + 41 + 107/witnessWeight)
+	if got, want := MinLotSize(feeRate, true), 3*spendSize*feeRate+redeemFees; got != want {
+		t.Fatalf("wrong default min lot size. wanted %d, got %d", want, got)
+	}
+
+	// A fixed threshold applies if it is larger.
+	fixed := &dex.DustPolicy{MinOutput: 1e6}
+	if got, want := MinLotSizeForDust(fixed, feeRate, true), 1e6+redeemFees; got != want {
+		t.Fatalf("wrong fixed min lot size. wanted %d, got %d", want, got)
+	}
+	both := &dex.DustPolicy{FeeMultiplier: 3, MinOutput: 1}
+	if MinLotSizeForDust(both, feeRate, true) != MinLotSize(feeRate, true) {
+		t.Fatalf("small fixed threshold should not apply")
+	}
+}
+
 func TestExtractScriptAddrs(t *testing.T) {
 	// Invalid script
 	_, nonStd, _ := ExtractScriptAddrs(invalidScript, tParams)
@@ -42,6 +42,8 @@ var (
 		},
 		FeeRateDenom: "B",
 	}
+	// DustPolicy is Dash Core's dust policy, inherited from Bitcoin Core.
+	DustPolicy = dex.DefaultDustPolicy
 
 	// MainNetParams are the clone parameters for mainnet.
 	MainNetParams = btc.ReadCloneParams(&btc.CloneParams{
@@ -23,3 +23,7 @@ var UnitInfo = dex.UnitInfo{
 	},
 	FeeRateDenom: "B",
 }
+
+// DustPolicy is dcrd's dust policy, which uses the same fee multiplier as
+// Bitcoin Core.
+var DustPolicy = dex.DefaultDustPolicy
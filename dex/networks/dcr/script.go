@@ -157,7 +157,7 @@ func minHTLCValue(maxFeeRate, redeemTxSize uint64) uint64 {
 	// totalSize adds some buffer for the spending transaction.
 	var outputSize uint64 = P2PKHOutputSize // larger of bonds p2sh output and refund's p2pkh output.
 	totalSize := outputSize + 165
-	minInitTxValue := maxFeeRate * totalSize * 3
+	minInitTxValue := DustPolicy.Threshold(totalSize, maxFeeRate)
 
 	// The minInitTxValue would get the bond tx accepted, but when we go to
 	// refund, we need that output to pass too, So let's add the fees for the
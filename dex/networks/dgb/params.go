@@ -53,6 +53,8 @@ var (
 		},
 		FeeRateDenom: "vB",
 	}
+	// DustPolicy is DigiByte Core's dust policy, inherited from Bitcoin Core.
+	DustPolicy = dex.DefaultDustPolicy
 
 	// MainNetParams are the clone parameters for mainnet.
 	MainNetParams = btc.ReadCloneParams(&btc.CloneParams{
@@ -50,6 +50,10 @@ var (
 		},
 		FeeRateDenom: "vB",
 	}
+	// DustPolicy is Dogecoin Core's dust policy. Dogecoin replaced Bitcoin's
+	// fee-based threshold with the fixed "soft" limit of 0.01 DOGE
+	// (DEFAULT_DUST_LIMIT).
+	DustPolicy = dex.DustPolicy{MinOutput: 1_000_000}
 
 	// MainNetParams are the clone parameters for mainnet.
 	MainNetParams = btc.ReadCloneParams(&btc.CloneParams{
@@ -49,6 +49,8 @@ var (
 		},
 		FeeRateDenom: "B",
 	}
+	// DustPolicy is Firo's dust policy, inherited from Bitcoin Core.
+	DustPolicy = dex.DefaultDustPolicy
 
 	// MainNetParams are the clone parameters for mainnet.
 	MainNetParams = btc.ReadCloneParams(&btc.CloneParams{
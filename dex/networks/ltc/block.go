@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	"golang.org/x/crypto/scrypt"
 )
 
 const (
@@ -130,3 +132,16 @@ func DeserializeBlock(blk io.Reader) (*wire.MsgBlock, error) {
 func DeserializeBlockBytes(blk []byte) (*wire.MsgBlock, error) {
 	return DeserializeBlock(bytes.NewReader(blk))
 }
+
+// PowHash computes the scrypt proof-of-work hash of a block header. Unlike
+// Bitcoin, the block hash is not the proof-of-work hash.
+func PowHash(hdr *wire.BlockHeader) chainhash.Hash {
+	var b bytes.Buffer
+	b.Grow(wire.MaxBlockHeaderPayload)
+	hdr.Serialize(&b)
+	var h chainhash.Hash
+	// The parameters cannot produce an error.
+	k, _ := scrypt.Key(b.Bytes(), b.Bytes(), 1024, 1, 1, chainhash.HashSize)
+	copy(h[:], k)
+	return h
+}
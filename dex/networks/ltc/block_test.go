@@ -9,6 +9,7 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 )
@@ -111,6 +112,27 @@ func TestDeserializeBlockBytes(t *testing.T) {
 	}
 }
 
+func TestPowHash(t *testing.T) {
+	for _, blk := range [][]byte{block1821752, block2215584, block2215586, block2321749, block2319633} {
+		var hdr wire.BlockHeader
+		if err := hdr.Deserialize(bytes.NewReader(blk)); err != nil {
+			t.Fatal(err)
+		}
+		target := blockchain.CompactToBig(hdr.Bits)
+		if target.Cmp(TestNet4Params.PowLimit) > 0 {
+			t.Fatalf("block %s target %08x is above the pow limit", hdr.BlockHash(), hdr.Bits)
+		}
+		powHash := PowHash(&hdr)
+		if blockchain.HashToBig(&powHash).Cmp(target) > 0 {
+			t.Fatalf("block %s pow hash %s does not meet target %08x", hdr.BlockHash(), powHash, hdr.Bits)
+		}
+		// The block hash is not the proof-of-work hash.
+		if blkHash := hdr.BlockHash(); blockchain.HashToBig(&blkHash).Cmp(target) <= 0 {
+			t.Fatalf("block hash %s meets the target", blkHash)
+		}
+	}
+}
+
 func TestDecodeTransaction(t *testing.T) {
 	// pegin 84b7ea499d5650cc220afac8b972527cef10ed402da5a5b000f994199044f450
 	// output has witness ver 9
@@ -4,12 +4,20 @@
 package ltc
 
 import (
+	"math/big"
+
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/networks/btc"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 )
 
+// powLimit is the highest proof-of-work target, 0x00000fff...fff for mainnet
+// and testnet and 0x7fff...fff for regtest.
+func powLimit(zeroBits uint) *big.Int {
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256-zeroBits), big.NewInt(1))
+}
+
 func mustHash(hash string) *chainhash.Hash {
 	h, err := chainhash.NewHashFromStr(hash)
 	if err != nil {
@@ -37,6 +45,9 @@ var (
 		},
 		FeeRateDenom: "vB",
 	}
+	// DustPolicy is Litecoin Core's dust policy, which is unchanged from Bitcoin
+	// Core.
+	DustPolicy = dex.DefaultDustPolicy
 	// MainNetParams are the clone parameters for mainnet.
 	MainNetParams = btc.ReadCloneParams(&btc.CloneParams{
 		Name:             "mainnet",
@@ -48,6 +59,7 @@ var (
 		HDPrivateKeyID:   [4]byte{0x04, 0x88, 0xad, 0xe4}, // starts with xprv
 		HDPublicKeyID:    [4]byte{0x04, 0x88, 0xb2, 0x1e}, // starts with xpub
 		GenesisHash:      mustHash("12a765e31ffd4059bada1e25190f6e98c99d9714d334efa41a195a7e7e04bfe2"),
+		PowLimit:         powLimit(20),
 	})
 	// TestNet4Params are the clone parameters for testnet.
 	TestNet4Params = btc.ReadCloneParams(&btc.CloneParams{
@@ -60,6 +72,7 @@ var (
 		HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
 		HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
 		GenesisHash:      mustHash("4966625a4b2851d9fdee139e56211a0d88575f59ed816ff5e6a63deb4e3e29a0"),
+		PowLimit:         powLimit(20),
 	})
 	// RegressionNetParams are the clone parameters for simnet.
 	RegressionNetParams = btc.ReadCloneParams(&btc.CloneParams{
@@ -77,6 +90,7 @@ var (
 		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
 		HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
 		GenesisHash:    mustHash("530827f38f93b43ed12af0b3ad25a288dc02ed74d6d7857862df51fc56c416f9"),
+		PowLimit:       powLimit(1),
 	})
 )
 
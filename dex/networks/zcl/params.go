@@ -28,6 +28,9 @@ var (
 		},
 		FeeRateDenom: "B",
 	}
+	// DustPolicy is Zclassic's dust policy, inherited from Zcash and Bitcoin
+	// Core.
+	DustPolicy = dex.DefaultDustPolicy
 
 	// MainNetParams are the clone parameters for mainnet. Zcash,
 	// like Decred, uses two bytes for their address IDs. We will convert
@@ -575,6 +575,11 @@ func (t *Trade) Serialize() []byte {
 type MarketOrder struct {
 	P
 	T
+	// WorstRate is the least favorable rate at which the order may be filled,
+	// the highest rate for a buy or the lowest rate for a sell. Matching stops
+	// when the book crosses WorstRate, and the remainder is unfilled. Zero
+	// means no limit.
+	WorstRate uint64
 }
 
 // ID computes the order ID.
@@ -601,7 +606,11 @@ func (o *MarketOrder) String() string {
 func (o *MarketOrder) serializeSize() int {
 	// The serialized order includes a byte for coin count, but this is implicit
 	// in coin slice length.
-	return o.P.serializeSize() + o.T.serializeSize()
+	sz := o.P.serializeSize() + o.T.serializeSize()
+	if o.WorstRate > 0 {
+		sz += 8
+	}
+	return sz
 }
 
 // Serialize marshals the LimitOrder into a []byte.
@@ -612,6 +621,13 @@ func (o *MarketOrder) Serialize() []byte {
 	copy(b[:offset], o.P.Serialize())
 	tradeLen := o.T.serializeSize()
 	copy(b[offset:offset+tradeLen], o.T.Serialize())
+	offset += tradeLen
+
+	// The worst rate is omitted when there is no limit, so that the IDs of
+	// orders without a limit are unchanged.
+	if o.WorstRate > 0 {
+		binary.BigEndian.PutUint64(b[offset:offset+8], o.WorstRate)
+	}
 	return b
 }
 
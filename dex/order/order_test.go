@@ -475,6 +475,56 @@ func TestMarketOrder_ID(t *testing.T) {
 	}
 }
 
+func TestMarketOrderWorstRate(t *testing.T) {
+	o := &MarketOrder{
+		P: Prefix{
+			AccountID:  acct0,
+			BaseAsset:  AssetDCR,
+			QuoteAsset: AssetBTC,
+			OrderType:  MarketOrderType,
+			ClientTime: time.Unix(1566497653, 0),
+			ServerTime: time.Unix(1566497656, 0),
+			Commit:     commit0,
+		},
+		T: Trade{
+			Coins:    []CoinID{utxoCoinID("a985d8df97571b130ce30a049a76ffedaa79b6e69b173ff81b1bf9fc07f063c7", 1)},
+			Sell:     true,
+			Quantity: 132413241324,
+			Address:  "DcqXswjTPnUcd4FRCkX4vRJxmVtfgGVa5ui",
+		},
+	}
+	noLimit := o.Serialize()
+	noLimitID := o.ID()
+
+	o.WorstRate = 1234
+	o.id = nil
+	b := o.Serialize()
+	if len(b) != len(noLimit)+8 || len(b) != o.serializeSize() {
+		t.Fatalf("wrong serialized length %d, size %d, without limit %d", len(b), o.serializeSize(), len(noLimit))
+	}
+	if !bytes.Equal(b[:len(noLimit)], noLimit) || binary.BigEndian.Uint64(b[len(noLimit):]) != 1234 {
+		t.Fatalf("worst rate not appended to serialization")
+	}
+	if o.ID() == noLimitID {
+		t.Fatalf("worst rate not committed to by the order ID")
+	}
+
+	for _, worstRate := range []uint64{0, 1234} {
+		o.WorstRate = worstRate
+		ord, err := DecodeOrder(EncodeOrder(o))
+		if err != nil {
+			t.Fatalf("DecodeOrder error: %v", err)
+		}
+		mo, ok := ord.(*MarketOrder)
+		if !ok {
+			t.Fatalf("decoded a %T", ord)
+		}
+		if mo.WorstRate != worstRate {
+			t.Fatalf("decoded worst rate %d, wanted %d", mo.WorstRate, worstRate)
+		}
+	}
+}
+
 func TestLimitOrder_ID(t *testing.T) {
 	orderID0, _ := hex.DecodeString("8490aca39a672a79a1d93d70b531bee2297c56040e970cac6d2be755c932508a")
 	var orderID OrderID
@@ -237,10 +237,14 @@ func EncodeOrder(ord Order) []byte {
 				AddData(tif),
 			)
 	case *MarketOrder:
-		return encode.BuildyBytes{0}.
+		b := encode.BuildyBytes{0}.
 			AddData(orderTypeMarket).
 			AddData(EncodePrefix(&o.P)).
 			AddData(EncodeTrade(&o.T))
+		if o.WorstRate > 0 {
+			b = b.AddData(uint64B(o.WorstRate))
+		}
+		return b
 	case *CancelOrder:
 		return encode.BuildyBytes{0}.
 			AddData(orderTypeCancel).
@@ -307,8 +311,9 @@ func decodeOrder_v0(pushes [][]byte) (Order, error) {
 		}, nil
 
 	case bEqual(oType, orderTypeMarket):
-		if len(pushes) != 2 {
-			return nil, fmt.Errorf("decodeOrder_v0: expected 2 pushes for market order, got %d", len(pushes))
+		// The worst rate is only encoded if set.
+		if len(pushes) != 2 && len(pushes) != 3 {
+			return nil, fmt.Errorf("decodeOrder_v0: expected 2 or 3 pushes for market order, got %d", len(pushes))
 		}
 		prefixB, tradeB := pushes[0], pushes[1]
 		prefix, err := DecodePrefix(prefixB)
@@ -319,9 +324,17 @@ func decodeOrder_v0(pushes [][]byte) (Order, error) {
 		if err != nil {
 			return nil, err
 		}
+		var worstRate uint64
+		if len(pushes) == 3 {
+			if len(pushes[2]) != 8 {
+				return nil, fmt.Errorf("decodeOrder_v0: expected 8 bytes for worst rate, got %d", len(pushes[2]))
+			}
+			worstRate = intCoder.Uint64(pushes[2])
+		}
 		return &MarketOrder{
-			P: *prefix,
-			T: *trade.Copy(),
+			P:         *prefix,
+			T:         *trade.Copy(),
+			WorstRate: worstRate,
 		}, nil
 
 	case bEqual(oType, orderTypeCancel):
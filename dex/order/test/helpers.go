@@ -374,6 +374,9 @@ func MustCompareLimitOrders(t testKiller, l1, l2 *order.LimitOrder) {
 func MustCompareMarketOrders(t testKiller, m1, m2 *order.MarketOrder) {
 	MustComparePrefix(t, &m1.P, &m2.P)
 	MustCompareTrade(t, &m1.T, &m2.T)
+	if m1.WorstRate != m2.WorstRate {
+		t.Fatalf("worst rate mismatch. %d != %d", m1.WorstRate, m2.WorstRate)
+	}
 }
 
 // MustCompareCancelOrders compares the CancelOrders field-by-field and calls
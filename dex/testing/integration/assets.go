@@ -0,0 +1,173 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"decred.org/dcrdex/client/asset/bch"
+	"decred.org/dcrdex/client/asset/btc"
+	"decred.org/dcrdex/client/asset/dcr"
+	"decred.org/dcrdex/client/asset/ltc"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/config"
+	dexsrv "decred.org/dcrdex/server/dex"
+
+	_ "decred.org/dcrdex/server/asset/bch" // register bch backend
+	_ "decred.org/dcrdex/server/asset/btc" // register btc backend
+	_ "decred.org/dcrdex/server/asset/dcr" // register dcr backend
+	_ "decred.org/dcrdex/server/asset/ltc" // register ltc backend
+)
+
+// DefaultEpochDuration is the epoch duration, in milliseconds, of the markets
+// created by SimnetMarket.
+const DefaultEpochDuration = 4000
+
+// walletPass is the passphrase of the harness wallets.
+var walletPass = []byte("abc")
+
+// DextestDir is the root directory of the simnet harnesses started by the
+// dex/testing/<asset>/harness.sh scripts.
+var DextestDir = filepath.Join(os.Getenv("HOME"), "dextest")
+
+// simnetAsset is the server and wallet configuration for an asset's simnet
+// harness. The server settings match dex/testing/dcrdex/genmarkets.sh.
+type simnetAsset struct {
+	id         uint32
+	maxFeeRate uint64
+	swapConf   uint32
+	bondAmt    uint64
+	// nodeConf is the config file of the node used by the server's backend,
+	// relative to the asset's harness directory.
+	nodeConf   string
+	walletType string
+}
+
+var simnetAssets = map[string]*simnetAsset{
+	"dcr": {
+		id:         dcr.BipID,
+		maxFeeRate: 10,
+		swapConf:   1,
+		bondAmt:    50_000_000,
+		nodeConf:   "alpha/dcrd.conf",
+		walletType: "dcrwalletRPC",
+	},
+	"btc": {
+		id:         btc.BipID,
+		maxFeeRate: 100,
+		swapConf:   1,
+		bondAmt:    100_000,
+		nodeConf:   "alpha/alpha.conf",
+		walletType: "bitcoindRPC",
+	},
+	"ltc": {
+		id:         ltc.BipID,
+		maxFeeRate: 20,
+		swapConf:   2,
+		bondAmt:    1_000_000,
+		nodeConf:   "alpha/alpha.conf",
+		walletType: "litecoindRPC",
+	},
+	"bch": {
+		id:         bch.BipID,
+		maxFeeRate: 20,
+		swapConf:   2,
+		bondAmt:    1_000_000,
+		nodeConf:   "alpha/alpha.conf",
+		walletType: "bitcoindRPC",
+	},
+}
+
+func lookupAsset(assetID uint32) (string, *simnetAsset, error) {
+	symbol := dex.BipIDSymbol(assetID)
+	a, found := simnetAssets[symbol]
+	if !found {
+		return "", nil, fmt.Errorf("asset %d (%q) is not supported", assetID, symbol)
+	}
+	return symbol, a, nil
+}
+
+// assetKey is an asset's key in the markets.json assets map.
+func assetKey(symbol string) string {
+	return strings.ToUpper(symbol) + "_simnet"
+}
+
+// SimnetAssets is the server configuration of the assets with the given BIP-44
+// symbols, for the harness nodes. Supported assets are dcr, btc, ltc, and
+// bch. The returned map is keyed as in a markets.json file, e.g. DCR_simnet.
+func SimnetAssets(symbols ...string) (map[string]*dexsrv.Asset, error) {
+	assets := make(map[string]*dexsrv.Asset, len(symbols))
+	for _, symbol := range symbols {
+		a, found := simnetAssets[symbol]
+		if !found {
+			return nil, fmt.Errorf("asset %q is not supported", symbol)
+		}
+		assets[assetKey(symbol)] = &dexsrv.Asset{
+			Symbol:     symbol,
+			Network:    "simnet",
+			MaxFeeRate: a.maxFeeRate,
+			SwapConf:   a.swapConf,
+			ConfigPath: filepath.Join(DextestDir, symbol, a.nodeConf),
+			BondAmt:    a.bondAmt,
+			BondConfs:  1,
+		}
+	}
+	return assets, nil
+}
+
+// SimnetMarket is a market configuration with the given base and quote BIP-44
+// symbols and DefaultEpochDuration.
+func SimnetMarket(base, quote string, lotSize, rateStep uint64) *dexsrv.Market {
+	return &dexsrv.Market{
+		Base:       assetKey(base),
+		Quote:      assetKey(quote),
+		LotSize:    lotSize,
+		ParcelSize: 4,
+		RateStep:   rateStep,
+		Duration:   DefaultEpochDuration,
+		MBBuffer:   1.2,
+	}
+}
+
+// walletForm creates the form for a wallet connected to the named harness
+// wallet node, e.g. "alpha" or "beta".
+func walletForm(assetID uint32, node string) (*core.WalletForm, error) {
+	symbol, a, err := lookupAsset(assetID)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Parse(filepath.Join(DextestDir, symbol, node, node+".conf"))
+	if err != nil {
+		return nil, err
+	}
+	if assetID == dcr.BipID {
+		cfg["account"] = "default"
+	}
+	return &core.WalletForm{
+		AssetID: assetID,
+		Config:  cfg,
+		Type:    a.walletType,
+	}, nil
+}
+
+// Mine mines n blocks on the asset's harness.
+func Mine(ctx context.Context, assetID uint32, n int) error {
+	symbol, _, err := lookupAsset(assetID)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "./mine-alpha", strconv.Itoa(n))
+	cmd.Dir = filepath.Join(DextestDir, symbol, "harness-ctl")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error mining %s blocks: %v, output = %q", symbol, err, string(out))
+	}
+	return nil
+}
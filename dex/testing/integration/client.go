@@ -0,0 +1,270 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+)
+
+const (
+	// mineInterval is how often blocks are mined while waiting for bonds and
+	// swaps to confirm.
+	mineInterval = 3 * time.Second
+	// walletSyncTimeout is how long a new wallet has to sync.
+	walletSyncTimeout = 30 * time.Second
+)
+
+// Client is a client Core connected to the Harness's server. Client embeds
+// *core.Core, so any Core method may be used directly.
+type Client struct {
+	*core.Core
+	Name string
+
+	h       *Harness
+	log     dex.Logger
+	appPass []byte
+	wg      sync.WaitGroup
+
+	notesMtx sync.Mutex
+	notes    []core.Notification
+}
+
+// NewClient creates, initializes, and logs in a client Core. The Client is
+// shut down by Harness.Stop.
+func (h *Harness) NewClient(name string) (*Client, error) {
+	dir := filepath.Join(h.dataDir, "clients", name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	log := h.logMkr.Logger("CORE[" + name + "]")
+	cc, err := core.New(&core.Config{
+		DBPath: filepath.Join(dir, "dex.db"),
+		Net:    dex.Simnet,
+		Logger: log,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		Core:    cc,
+		Name:    name,
+		h:       h,
+		log:     log,
+		appPass: []byte("pass_" + name),
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		cc.Run(h.ctx)
+	}()
+	<-cc.Ready()
+	c.readNotifications(h.ctx)
+
+	if _, err := cc.InitializeClient(c.appPass, nil); err != nil {
+		return nil, fmt.Errorf("error initializing client %s: %w", name, err)
+	}
+	if err := cc.Login(c.appPass); err != nil {
+		return nil, fmt.Errorf("error logging in client %s: %w", name, err)
+	}
+
+	h.clientsMtx.Lock()
+	h.clients = append(h.clients, c)
+	h.clientsMtx.Unlock()
+	return c, nil
+}
+
+// readNotifications collects the Core's notifications so that they may be
+// searched with waitForNote.
+func (c *Client) readNotifications(ctx context.Context) {
+	feed := c.NotificationFeed()
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer feed.ReturnFeed()
+		for {
+			select {
+			case n := <-feed.C:
+				c.notesMtx.Lock()
+				c.notes = append(c.notes, n)
+				c.notesMtx.Unlock()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// waitForNote waits until a notification satisfying check is received. Blocks
+// of the asset are mined while waiting, if mineAsset is not nil. Notifications
+// checked are discarded.
+func (c *Client) waitForNote(timeout time.Duration, mineAsset *uint32, check func(core.Notification) bool) bool {
+	lastMine := time.Now()
+	return tryUntil(c.h.ctx, timeout, 250*time.Millisecond, func() bool {
+		c.notesMtx.Lock()
+		notes := c.notes
+		c.notes = nil
+		c.notesMtx.Unlock()
+		for _, n := range notes {
+			if check(n) {
+				return true
+			}
+		}
+		if mineAsset != nil && time.Since(lastMine) > mineInterval {
+			if err := Mine(c.h.ctx, *mineAsset, 1); err != nil {
+				c.log.Errorf("%v", err)
+			}
+			lastMine = time.Now()
+		}
+		return false
+	})
+}
+
+// ConnectWallet creates a wallet for the asset that uses the named harness
+// wallet node, e.g. "alpha" or "beta", and waits for it to sync. Clients that
+// trade with each other should use different nodes.
+func (c *Client) ConnectWallet(assetID uint32, node string) error {
+	form, err := walletForm(assetID, node)
+	if err != nil {
+		return err
+	}
+	if err := c.CreateWallet(c.appPass, walletPass, form); err != nil {
+		return fmt.Errorf("error creating %s wallet: %w", dex.BipIDSymbol(assetID), err)
+	}
+	synced := tryUntil(c.h.ctx, walletSyncTimeout, time.Second, func() bool {
+		ws := c.WalletState(assetID)
+		return ws != nil && ws.Synced
+	})
+	if !synced {
+		return fmt.Errorf("%s wallet not synced after %s", dex.BipIDSymbol(assetID), walletSyncTimeout)
+	}
+	c.log.Infof("Connected %s wallet on node %s", dex.BipIDSymbol(assetID), node)
+	return nil
+}
+
+// PostBond posts a bond for tier 1 with the asset and waits for the bond to
+// confirm, mining blocks as needed. The asset's wallet must be connected.
+func (c *Client) PostBond(assetID uint32, timeout time.Duration) error {
+	xc, err := c.GetDEXConfig(c.h.addr, c.h.cert)
+	if err != nil {
+		return fmt.Errorf("error getting server config: %w", err)
+	}
+	symbol := dex.BipIDSymbol(assetID)
+	bondAsset := xc.BondAssets[symbol]
+	if bondAsset == nil {
+		return fmt.Errorf("server does not accept %s bonds", symbol)
+	}
+	if _, err = c.Core.PostBond(&core.PostBondForm{
+		Addr:    c.h.addr,
+		Cert:    c.h.cert,
+		AppPass: c.appPass,
+		Asset:   &assetID,
+		Bond:    bondAsset.Amt,
+	}); err != nil {
+		return fmt.Errorf("error posting bond: %w", err)
+	}
+	confirmed := c.waitForNote(timeout, &assetID, func(n core.Notification) bool {
+		return n.Type() == core.NoteTypeBondPost && n.Topic() == core.TopicBondConfirmed
+	})
+	if !confirmed {
+		return fmt.Errorf("%s bond not confirmed after %s", symbol, timeout)
+	}
+	c.log.Infof("Bond confirmed for client %s", c.Name)
+	return nil
+}
+
+// PlaceLimitOrder places a standing limit order on the server's base-quote
+// market.
+func (c *Client) PlaceLimitOrder(base, quote uint32, sell bool, qty, rate uint64) (*core.Order, error) {
+	return c.Trade(c.appPass, &core.TradeForm{
+		Host:    c.h.addr,
+		IsLimit: true,
+		Sell:    sell,
+		Base:    base,
+		Quote:   quote,
+		Qty:     qty,
+		Rate:    rate,
+	})
+}
+
+// WaitForSettlement waits until the order is no longer booked and all of its
+// matches are confirmed, mining blocks of the market's assets as needed. An
+// error is returned if a match is refunded or the timeout elapses. The final
+// state of the order is returned.
+func (c *Client) WaitForSettlement(oid dex.Bytes, timeout time.Duration) (*core.Order, error) {
+	var ord *core.Order
+	var err error
+	lastMine := time.Now()
+	settled := tryUntil(c.h.ctx, timeout, 500*time.Millisecond, func() bool {
+		ord, err = c.Order(oid)
+		if err != nil {
+			return true
+		}
+		var done bool
+		if done, err = orderSettled(ord); done || err != nil {
+			return true
+		}
+		if time.Since(lastMine) > mineInterval {
+			for _, assetID := range []uint32{ord.BaseID, ord.QuoteID} {
+				if err := Mine(c.h.ctx, assetID, 1); err != nil {
+					c.log.Errorf("%v", err)
+				}
+			}
+			lastMine = time.Now()
+		}
+		return false
+	})
+	if err != nil {
+		return ord, err
+	}
+	if !settled {
+		return ord, fmt.Errorf("order %s not settled after %s: %s", oid, timeout, describeOrder(ord))
+	}
+	return ord, nil
+}
+
+// orderSettled checks whether the order is settled. An error is returned if a
+// match was refunded.
+func orderSettled(ord *core.Order) (bool, error) {
+	if ord.Status == order.OrderStatusEpoch || ord.Status == order.OrderStatusBooked {
+		return false, nil
+	}
+	for _, m := range ord.Matches {
+		if m.IsCancel {
+			continue
+		}
+		if m.Refund != nil {
+			return true, fmt.Errorf("match %s of order %s was refunded", m.MatchID, ord.ID)
+		}
+		if m.Status < order.MatchConfirmed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// describeOrder summarizes the order's status and its match statuses for
+// errors.
+func describeOrder(ord *core.Order) string {
+	if ord == nil {
+		return "order not found"
+	}
+	matches := make([]string, 0, len(ord.Matches))
+	for _, m := range ord.Matches {
+		if !m.IsCancel {
+			matches = append(matches, fmt.Sprintf("%s %s", m.MatchID, m.Status))
+		}
+	}
+	return fmt.Sprintf("status %s, filled %d of %d, matches [%s]", ord.Status, ord.Filled, ord.Qty,
+		strings.Join(matches, ", "))
+}
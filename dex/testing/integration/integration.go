@@ -0,0 +1,256 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package integration runs a dcrdex server and any number of client Cores in
+// one process, so that full-stack tests can be written as Go tests. The server
+// uses a SQLite DB in a temporary directory and asset backends connected to the
+// simnet harness nodes, which must be started beforehand with the
+// dex/testing/<asset>/harness.sh scripts. Tests that need the harnesses should
+// use the harness build tag.
+//
+// The swap lock times are set at build time. To test refunds without long
+// waits, build the test with e.g.
+//
+//	go test -tags harness -ldflags "-X 'decred.org/dcrdex/dex.testLockTimeTaker=1m' \
+//	    -X 'decred.org/dcrdex/dex.testLockTimeMaker=2m'"
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/auth"
+	"decred.org/dcrdex/server/book"
+	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/server/db"
+	dexsrv "decred.org/dcrdex/server/dex"
+	"decred.org/dcrdex/server/market"
+	"decred.org/dcrdex/server/matcher"
+	"decred.org/dcrdex/server/swap"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Config is the configuration for a Harness.
+type Config struct {
+	// Markets and Assets are the server's markets and assets, as in a
+	// markets.json file. See SimnetMarket and SimnetAssets.
+	Markets []*dexsrv.Market
+	Assets  map[string]*dexsrv.Asset
+	// DataDir is the directory for the server and client data. If empty, a
+	// temporary directory is created and removed by Stop.
+	DataDir string
+	// LogWriter receives the server and client logs. Default is os.Stdout.
+	LogWriter io.Writer
+	// DebugLevel is the log level, e.g. "debug" or "MKT=trace,info". Default
+	// is "info".
+	DebugLevel string
+}
+
+// Harness is a running dcrdex server and the Clients connected to it. Only one
+// Harness may run at a time, since the server's subsystems log through
+// package-level loggers.
+type Harness struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	dataDir string
+	rmDir   bool
+	logMkr  *dex.LoggerMaker
+	addr    string
+	cert    []byte
+	dex     *dexsrv.DEX
+
+	clientsMtx sync.Mutex
+	clients    []*Client
+}
+
+// New starts a dcrdex server with the configured markets.
+func New(ctx context.Context, cfg *Config) (_ *Harness, err error) {
+	if len(cfg.Markets) == 0 {
+		return nil, errors.New("no markets")
+	}
+	logWriter := cfg.LogWriter
+	if logWriter == nil {
+		logWriter = os.Stdout
+	}
+	debugLevel := cfg.DebugLevel
+	if debugLevel == "" {
+		debugLevel = "info"
+	}
+	logMkr, err := dex.NewLoggerMaker(logWriter, debugLevel)
+	if err != nil {
+		return nil, err
+	}
+	useServerLoggers(logMkr)
+
+	dataDir, rmDir := cfg.DataDir, false
+	if dataDir == "" {
+		if dataDir, err = os.MkdirTemp("", "dexintegration"); err != nil {
+			return nil, err
+		}
+		rmDir = true
+	}
+	defer func() {
+		if err != nil && rmDir {
+			os.RemoveAll(dataDir)
+		}
+	}()
+
+	addr, err := freeAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	// The server only loads markets from a file.
+	marketsPath := filepath.Join(dataDir, "markets.json")
+	b, err := json.MarshalIndent(&dexsrv.Config{Markets: cfg.Markets, Assets: cfg.Assets}, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(marketsPath, b, 0600); err != nil {
+		return nil, err
+	}
+	markets, assets, err := dexsrv.LoadConfig(dex.Simnet, marketsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading market config: %w", err)
+	}
+	for _, mkt := range markets {
+		mkt.MaxUserCancelsPerEpoch = 128
+	}
+
+	privKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	srvDir := filepath.Join(dataDir, "server")
+	certPath := filepath.Join(srvDir, "rpc.cert")
+	if err = os.MkdirAll(srvDir, 0700); err != nil {
+		return nil, err
+	}
+	dm, err := dexsrv.NewDEX(ctx, &dexsrv.DexConf{
+		DataDir:    srvDir,
+		LogBackend: logMkr,
+		Markets:    markets,
+		Assets:     assets,
+		Network:    dex.Simnet,
+		DBConf: &dexsrv.DBConf{
+			Driver:     "sqlite",
+			SQLitePath: filepath.Join(srvDir, "dcrdex.db"),
+		},
+		BroadcastTimeout: time.Minute,
+		TxWaitExpiration: 2 * time.Minute,
+		CancelThreshold:  0.95,
+		PenaltyThreshold: 20,
+		DEXPrivKey:       privKey,
+		CommsCfg: &dexsrv.RPCConfig{
+			RPCCert:     certPath,
+			RPCKey:      filepath.Join(srvDir, "rpc.key"),
+			ListenAddrs: []string{addr},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error starting server: %w", err)
+	}
+	cert, err := os.ReadFile(certPath)
+	if err != nil {
+		dm.Stop()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &Harness{
+		ctx:     ctx,
+		cancel:  cancel,
+		dataDir: dataDir,
+		rmDir:   rmDir,
+		logMkr:  logMkr,
+		addr:    addr,
+		cert:    cert,
+		dex:     dm,
+	}, nil
+}
+
+// Addr is the server's address.
+func (h *Harness) Addr() string {
+	return h.addr
+}
+
+// Cert is the server's TLS certificate.
+func (h *Harness) Cert() []byte {
+	return h.cert
+}
+
+// DEX is the server's DEX manager, for e.g. admin actions.
+func (h *Harness) DEX() *dexsrv.DEX {
+	return h.dex
+}
+
+// Stop shuts down the clients and the server, and removes the temporary data
+// directory.
+func (h *Harness) Stop() {
+	h.cancel()
+	h.clientsMtx.Lock()
+	for _, c := range h.clients {
+		c.wg.Wait()
+	}
+	h.clients = nil
+	h.clientsMtx.Unlock()
+	h.dex.Stop()
+	if h.rmDir {
+		os.RemoveAll(h.dataDir)
+	}
+}
+
+// useServerLoggers sets the loggers of the server's subsystems, as the dcrdex
+// command does.
+func useServerLoggers(lm *dex.LoggerMaker) {
+	dexsrv.UseLogger(lm.Logger("DEX"))
+	db.UseLogger(lm.Logger("DB"))
+	comms.UseLogger(lm.Logger("COMM"))
+	auth.UseLogger(lm.Logger("AUTH"))
+	swap.UseLogger(lm.Logger("SWAP"))
+	market.UseLogger(lm.Logger("MKT"))
+	book.UseLogger(lm.Logger("BOOK"))
+	matcher.UseLogger(lm.Logger("MTCH"))
+}
+
+// freeAddr finds an unused local address for the server to listen on.
+func freeAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
+
+// tryUntil calls tryFn every tick until it returns true, the timeout elapses,
+// or the context is canceled. The return value indicates whether tryFn
+// returned true.
+func tryUntil(ctx context.Context, timeout, tick time.Duration, tryFn func() bool) bool {
+	expire := time.NewTimer(timeout)
+	defer expire.Stop()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		if tryFn() {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-expire.C:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,99 @@
+//go:build harness
+
+// The dcr and btc simnet harnesses should be running before executing
+// these tests.
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset/btc"
+	"decred.org/dcrdex/client/asset/dcr"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex/order"
+	dexsrv "decred.org/dcrdex/server/dex"
+)
+
+const (
+	bondTimeout   = 2 * time.Minute
+	settleTimeout = 5 * time.Minute
+)
+
+func newHarness(t *testing.T, markets ...*dexsrv.Market) *Harness {
+	t.Helper()
+	assets, err := SimnetAssets("dcr", "btc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	h, err := New(ctx, &Config{
+		Markets:    markets,
+		Assets:     assets,
+		DebugLevel: "debug",
+	})
+	if err != nil {
+		t.Fatalf("error starting harness: %v", err)
+	}
+	t.Cleanup(h.Stop)
+	return h
+}
+
+// newTrader creates a client with dcr and btc wallets on the named harness
+// node, and posts a dcr bond.
+func newTrader(t *testing.T, h *Harness, name, node string) *Client {
+	t.Helper()
+	c, err := h.NewClient(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, assetID := range []uint32{dcr.BipID, btc.BipID} {
+		if err := c.ConnectWallet(assetID, node); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	if err := c.PostBond(dcr.BipID, bondTimeout); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return c
+}
+
+func TestTradeSuccess(t *testing.T) {
+	const lotSize, rateStep = 1e9, 100
+	h := newHarness(t, SimnetMarket("dcr", "btc", lotSize, rateStep))
+
+	maker := newTrader(t, h, "maker", "alpha")
+	taker := newTrader(t, h, "taker", "beta")
+
+	const qty, rate = 2 * lotSize, 1e6
+	sell, err := maker.PlaceLimitOrder(dcr.BipID, btc.BipID, true, qty, rate)
+	if err != nil {
+		t.Fatalf("error placing sell order: %v", err)
+	}
+	// Let the sell order book before the buy order is placed, so that the
+	// maker and taker are known.
+	time.Sleep(time.Duration(DefaultEpochDuration) * time.Millisecond * 2)
+	buy, err := taker.PlaceLimitOrder(dcr.BipID, btc.BipID, false, qty, rate)
+	if err != nil {
+		t.Fatalf("error placing buy order: %v", err)
+	}
+
+	for _, o := range []struct {
+		c   *Client
+		ord *core.Order
+	}{{maker, sell}, {taker, buy}} {
+		ord, err := o.c.WaitForSettlement(o.ord.ID, settleTimeout)
+		if err != nil {
+			t.Fatalf("%s: %v", o.c.Name, err)
+		}
+		if ord.Status != order.OrderStatusExecuted {
+			t.Fatalf("%s: expected order status %s, got %s", o.c.Name, order.OrderStatusExecuted, ord.Status)
+		}
+		if ord.Filled != qty {
+			t.Fatalf("%s: expected %d filled, got %d", o.c.Name, uint64(qty), ord.Filled)
+		}
+	}
+}
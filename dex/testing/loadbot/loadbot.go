@@ -65,12 +65,6 @@ const (
 	usdcp            = "usdc.polygon"
 	maxOrderLots     = 5
 	ethFeeRate       = 200 // gwei
-	// missedCancelErrStr is part of an error found in dcrdex/server/market/orderrouter.go
-	// that a cancel order may hit with bad timing but is not a problem.
-	//
-	// TODO: Consider returning a separate msgjson error from server for
-	// this case.
-	missedCancelErrStr = "target order not known:"
 
 	tradingTier = 100 // ~ 50 DCR
 )
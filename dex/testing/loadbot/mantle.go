@@ -13,7 +13,6 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"sync/atomic"
 	"time"
 
@@ -21,6 +20,7 @@ import (
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
 )
 
 // A Trader is a client routine to interact with the server. Each Trader passed
@@ -918,15 +918,21 @@ func newBotWallet(symbol, node, name string, port string, pass []byte, minFunds,
 	}
 }
 
-// isOverLimitError will be true if the error is a ErrQuantityTooHigh,
+// isOverLimitError will be true if the error is a msgjson.OrderQuantityTooHigh,
 // indicating the client has reached its order limit. Ideally, Core would
 // know the limit and we could query it to use in our algorithm, but the order
 // limit change is new and Core doesn't know what to do with it yet.
 func isOverLimitError(err error) bool {
-	if err == nil {
-		return false
-	}
-	return strings.Contains(err.Error(), "order quantity exceeds user limit")
+	var msgErr *msgjson.Error
+	return errors.As(err, &msgErr) && msgErr.Code == msgjson.OrderQuantityTooHigh
+}
+
+// isMissedCancelError will be true if the error is a
+// msgjson.UnknownCancelTargetError, which a cancel order may hit with bad
+// timing but is not a problem.
+func isMissedCancelError(err error) bool {
+	var msgErr *msgjson.Error
+	return errors.As(err, &msgErr) && msgErr.Code == msgjson.UnknownCancelTargetError
 }
 
 func isApprovalPendingError(err error) bool {
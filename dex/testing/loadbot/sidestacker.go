@@ -4,10 +4,8 @@
 package main
 
 import (
-	"errors"
 	"math/rand"
 	"sort"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,7 +13,6 @@ import (
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/calc"
-	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
 )
 
@@ -163,9 +160,7 @@ func (s *sideStacker) stack(m *Mantle, currentEpoch uint64) {
 			err := m.Cancel(o.ID)
 			if err != nil {
 				// Be permissive of cancel misses.
-				var msgErr *msgjson.Error
-				if errors.As(err, &msgErr) && msgErr.Code == msgjson.OrderParameterError &&
-					strings.Contains(err.Error(), missedCancelErrStr) {
+				if isMissedCancelError(err) {
 					continue
 				}
 				m.fatalError("error canceling order for overloaded side: %v", err)
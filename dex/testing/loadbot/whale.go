@@ -4,16 +4,13 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"math/rand"
-	"strings"
 	"sync"
 	"time"
 
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/dex/calc"
-	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
 )
 
@@ -87,9 +84,7 @@ func (*whale) cancelOrders(currentEpoch uint64) {
 			err := m.Cancel(ord.ID)
 			if err != nil {
 				// Be permissive of cancel misses.
-				var msgErr *msgjson.Error
-				if errors.As(err, &msgErr) && msgErr.Code == msgjson.OrderParameterError &&
-					strings.Contains(err.Error(), missedCancelErrStr) {
+				if isMissedCancelError(err) {
 					continue
 				}
 				m.fatalError("error canceling order for overloaded side: %v", err)
@@ -26,8 +26,10 @@ type SourceConfig struct {
 	Period time.Duration
 	// Rank controls which priority group the source is in. Lower Rank is higher
 	// priority. Fee rates from similarly-ranked sources are grouped together
-	// for a composite rate. Lower-ranked groups are not considered at all
-	// until all sources from higher ranked groups are error or expired.
+	// for a composite rate, which is the median rate weighted by age, so that
+	// a single bad source in a group cannot skew the rate. Lower-ranked groups
+	// are not considered at all until all sources from higher ranked groups
+	// are error or expired.
 	Rank uint
 }
 
@@ -44,6 +46,22 @@ type FeeFetcher struct {
 	log     dex.Logger
 	c       chan uint64
 	sources [][]*feeFetchSource
+	// minRate and maxRate are sanity bounds for the rates from the sources.
+	// maxRate is ignored if zero.
+	minRate uint64
+	maxRate uint64
+}
+
+// Option is an optional setting for a FeeFetcher.
+type Option func(*FeeFetcher)
+
+// WithBounds sets sanity bounds for the fee rates from the sources. A rate
+// outside of the bounds is treated as an error from the source. A max of zero
+// means there is no upper bound.
+func WithBounds(min, max uint64) Option {
+	return func(f *FeeFetcher) {
+		f.minRate, f.maxRate = min, max
+	}
 }
 
 func groupedSources(sources []*SourceConfig, log dex.Logger) [][]*feeFetchSource {
@@ -73,12 +91,16 @@ next:
 }
 
 // NewFeeFetcher creates and returns a new FeeFetcher.
-func NewFeeFetcher(sources []*SourceConfig, log dex.Logger) *FeeFetcher {
-	return &FeeFetcher{
+func NewFeeFetcher(sources []*SourceConfig, log dex.Logger, opts ...Option) *FeeFetcher {
+	f := &FeeFetcher{
 		log:     log,
 		sources: groupedSources(sources, log),
 		c:       make(chan uint64, 1),
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 const (
@@ -93,27 +115,55 @@ const (
 	feeExpiration               = feeFetchFullValidityPeriod + feeFetchValidityDecayPeriod
 )
 
+type weightedRate struct {
+	rate   float64
+	weight float64
+}
+
+// weightedMedian is the median of the rates, with each rate counted in
+// proportion to its weight. If the weights are split evenly between two
+// rates, the midpoint is used.
+func weightedMedian(rates []*weightedRate) float64 {
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].rate < rates[j].rate
+	})
+	var total float64
+	for _, r := range rates {
+		total += r.weight
+	}
+	half := total / 2
+	const epsilon = 1e-9
+	var cum float64
+	for i, r := range rates {
+		cum += r.weight
+		switch {
+		case cum > half+epsilon:
+			return r.rate
+		case cum > half-epsilon && i < len(rates)-1:
+			return (r.rate + rates[i+1].rate) / 2
+		}
+	}
+	return rates[len(rates)-1].rate
+}
+
 func prioritizedFeeRate(sources [][]*feeFetchSource) uint64 {
 	for _, group := range sources {
-		var weightedRate float64
-		var weight float64
+		rates := make([]*weightedRate, 0, len(group))
 		for _, src := range group {
 			age := time.Since(src.stamp)
-			if !src.failUntil.IsZero() || age >= feeExpiration {
+			if !src.failUntil.IsZero() || age >= feeExpiration || src.rate == 0 {
 				continue
 			}
 			if age < feeFetchFullValidityPeriod {
-				weight += 1
-				weightedRate += float64(src.rate)
+				rates = append(rates, &weightedRate{rate: float64(src.rate), weight: 1})
 				continue
 			}
 			decayAge := age - feeFetchFullValidityPeriod
 			w := 1 - (float64(decayAge) / float64(feeFetchValidityDecayPeriod))
-			weight += w
-			weightedRate += w * float64(src.rate)
+			rates = append(rates, &weightedRate{rate: float64(src.rate), weight: w})
 		}
-		if weightedRate != 0 {
-			return utils.Max(1, uint64(math.Round(weightedRate/weight)))
+		if len(rates) > 0 {
+			return utils.Max(1, uint64(math.Round(weightedMedian(rates))))
 		}
 	}
 	return 0
@@ -155,27 +205,6 @@ func (f *FeeFetcher) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 		}
 	}
 
-	updateSource := func(src *feeFetchSource) bool {
-		ctx, cancel := context.WithTimeout(ctx, feeFetchTimeout)
-		defer cancel()
-		r, errDelay, err := src.F(ctx)
-		if err != nil {
-			src.log.Meter("fetch-error", time.Minute*30).Errorf("Fetch error: %v", err)
-			src.failUntil = time.Now().Add(utils.Max(minFeeFetchErrorDelay, errDelay))
-			return false
-		}
-		if r == 0 {
-			src.log.Meter("zero-rate", time.Minute*30).Error("Fee rate of zero")
-			src.failUntil = time.Now().Add(minFeeFetchErrorDelay)
-			return false
-		}
-		src.failUntil = time.Time{}
-		src.stamp = time.Now()
-		src.rate = r
-		src.log.Tracef("New rate %d", r)
-		return true
-	}
-
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -183,7 +212,7 @@ func (f *FeeFetcher) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 		var any bool
 		for _, group := range f.sources {
 			for _, src := range group {
-				any = updateSource(src) || any
+				any = f.updateSource(ctx, src) || any
 			}
 		}
 		if any {
@@ -206,7 +235,7 @@ func (f *FeeFetcher) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 			}
 			select {
 			case <-timeout.C:
-				if src == nil || !updateSource(src) {
+				if src == nil || !f.updateSource(ctx, src) {
 					continue
 				}
 				reportCompositeRate()
@@ -219,6 +248,35 @@ func (f *FeeFetcher) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	return &wg, nil
 }
 
+// updateSource fetches a new rate from the source. If the fetch fails or the
+// rate is out of bounds, the source is marked as failed.
+func (f *FeeFetcher) updateSource(ctx context.Context, src *feeFetchSource) bool {
+	ctx, cancel := context.WithTimeout(ctx, feeFetchTimeout)
+	defer cancel()
+	r, errDelay, err := src.F(ctx)
+	if err != nil {
+		src.log.Meter("fetch-error", time.Minute*30).Errorf("Fetch error: %v", err)
+		src.failUntil = time.Now().Add(utils.Max(minFeeFetchErrorDelay, errDelay))
+		return false
+	}
+	if r == 0 {
+		src.log.Meter("zero-rate", time.Minute*30).Error("Fee rate of zero")
+		src.failUntil = time.Now().Add(minFeeFetchErrorDelay)
+		return false
+	}
+	if r < f.minRate || (f.maxRate > 0 && r > f.maxRate) {
+		src.log.Meter("out-of-bounds", time.Minute*30).Errorf("Fee rate %d is outside of the bounds [%d, %d]",
+			r, f.minRate, f.maxRate)
+		src.failUntil = time.Now().Add(minFeeFetchErrorDelay)
+		return false
+	}
+	src.failUntil = time.Time{}
+	src.stamp = time.Now()
+	src.rate = r
+	src.log.Tracef("New rate %d", r)
+	return true
+}
+
 func (f *FeeFetcher) Next() <-chan uint64 {
 	return f.c
 }
@@ -1,6 +1,7 @@
 package txfee
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -101,13 +102,62 @@ func TestPrioritizedFeeRate(t *testing.T) {
 	// second from group 1 half-decayed
 	f1.failUntil = time.Time{}
 	f1.stamp = time.Now().Add(-1 * (feeFetchFullValidityPeriod + (feeFetchValidityDecayPeriod / 2)))
-	checkRate(17) // (10 + (0.5 * 30)) / 1.5 = 16.6666
+	checkRate(10) // 10 has weight 1 of 1.5
 	// first from group 1  decayed by 75%
 	f1.stamp = time.Now()
 	f0.stamp = time.Now().Add(-1 * (feeFetchFullValidityPeriod + (feeFetchValidityDecayPeriod * 3 / 4)))
-	checkRate(26) // (30 + (0.25 * 10)) / 1.25 = 26
+	checkRate(30) // 30 has weight 1 of 1.25
 	// group 1 unusable
 	f0.failUntil = time.Now()
 	f1.failUntil = time.Now()
 	checkRate(250)
+
+	// An outlier does not skew the median.
+	fetchers = feeFetchers([][2]uint{
+		{1, 10}, {1, 12}, {1, 500},
+	})
+	checkRate(12)
+	fetchers = feeFetchers([][2]uint{
+		{1, 10}, {1, 12}, {1, 14}, {1, 500},
+	})
+	checkRate(13)
+}
+
+func TestUpdateSourceBounds(t *testing.T) {
+	log := dex.StdOutLogger("T", dex.LevelTrace)
+	var rate uint64
+	src := &feeFetchSource{
+		SourceConfig: &SourceConfig{
+			F: func(ctx context.Context) (uint64, time.Duration, error) {
+				return rate, 0, nil
+			},
+		},
+		log: log,
+	}
+	f := NewFeeFetcher(nil, log, WithBounds(2, 100))
+	for _, tt := range []struct {
+		rate uint64
+		ok   bool
+	}{
+		{0, false},
+		{1, false},
+		{2, true},
+		{100, true},
+		{101, false},
+	} {
+		rate = tt.rate
+		if ok := f.updateSource(context.Background(), src); ok != tt.ok {
+			t.Fatalf("rate %d: expected ok = %t, got %t", tt.rate, tt.ok, ok)
+		}
+		if tt.ok && src.rate != tt.rate {
+			t.Fatalf("rate %d not stored", tt.rate)
+		}
+	}
+
+	// No upper bound.
+	f = NewFeeFetcher(nil, log, WithBounds(1, 0))
+	rate = 1e6
+	if !f.updateSource(context.Background(), src) {
+		t.Fatalf("rate rejected with no upper bound")
+	}
 }
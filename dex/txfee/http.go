@@ -0,0 +1,145 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package txfee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/dex/dexnet"
+)
+
+const defaultHTTPSourcePeriod = time.Minute * 5
+
+// DefaultHTTPSourceRank is the rank of an HTTP source with no configured
+// rank. It is the lowest priority, so a source added without a rank is only
+// used when no other source has a rate.
+const DefaultHTTPSourceRank uint = math.MaxUint
+
+// HTTPSourceConfig configures a fee rate source that is an HTTP API returning
+// JSON, for operators to add fee rate estimators that are not built in.
+type HTTPSourceConfig struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Path is the location of the fee rate in the JSON response, as a
+	// dot-separated list of object keys and array indices, e.g.
+	// "estimates.30.sat_per_vbyte" or "fees.0".
+	Path string `json:"path"`
+	// Scale converts the number in the response to the asset's fee rate
+	// units, e.g. 0.001 for a rate in atoms per kB. Default 1.
+	Scale float64 `json:"scale"`
+	// Rank is the SourceConfig.Rank. Default DefaultHTTPSourceRank.
+	Rank *uint `json:"rank"`
+	// Period is how often to fetch the fee rate, as a duration string e.g.
+	// "2m". Default 5 minutes.
+	Period string `json:"period"`
+	// Headers are added to the request, e.g. for an API key.
+	Headers map[string]string `json:"headers"`
+}
+
+// NewHTTPSource creates a fee rate source from the HTTPSourceConfig.
+func NewHTTPSource(cfg *HTTPSourceConfig) (*SourceConfig, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL for fee source %q: %w", cfg.Name, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("fee source %q URL scheme must be http or https", cfg.Name)
+	}
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("no path for fee source %q", cfg.Name)
+	}
+	path := strings.Split(cfg.Path, ".")
+	scale := cfg.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	if scale < 0 {
+		return nil, fmt.Errorf("negative scale for fee source %q", cfg.Name)
+	}
+	period := defaultHTTPSourcePeriod
+	if cfg.Period != "" {
+		if period, err = time.ParseDuration(cfg.Period); err != nil {
+			return nil, fmt.Errorf("invalid period for fee source %q: %w", cfg.Name, err)
+		}
+		if period < time.Second {
+			return nil, fmt.Errorf("period for fee source %q is less than one second", cfg.Name)
+		}
+	}
+	rank := DefaultHTTPSourceRank
+	if cfg.Rank != nil {
+		rank = *cfg.Rank
+	}
+	name := cfg.Name
+	if name == "" {
+		name = u.Host
+	}
+	opts := make([]*dexnet.RequestOption, 0, len(cfg.Headers)+1)
+	for k, v := range cfg.Headers {
+		opts = append(opts, dexnet.WithRequestHeader(k, v))
+	}
+
+	return &SourceConfig{
+		Name:   name,
+		Rank:   rank,
+		Period: period,
+		F: func(ctx context.Context) (rate uint64, errDelay time.Duration, err error) {
+			var res any
+			var code int
+			withCode := dexnet.WithStatusFunc(func(respCode int) {
+				code = respCode
+			})
+			if err := dexnet.Get(ctx, cfg.URL, &res, append(opts, withCode)...); err != nil {
+				if code == http.StatusTooManyRequests {
+					return 0, time.Minute * 30, errors.New("exceeded request limit")
+				}
+				return 0, time.Minute * 10, err
+			}
+			v, err := jsonNumberAt(res, path)
+			if err != nil {
+				return 0, time.Minute * 10, err
+			}
+			if v < 0 {
+				return 0, time.Minute * 10, fmt.Errorf("negative fee rate %f", v)
+			}
+			return uint64(math.Round(v * scale)), 0, nil
+		},
+	}, nil
+}
+
+// jsonNumberAt finds the number at the path in the decoded JSON. A number
+// encoded as a string is accepted.
+func jsonNumberAt(v any, path []string) (float64, error) {
+	for _, k := range path {
+		switch vt := v.(type) {
+		case map[string]any:
+			var found bool
+			if v, found = vt[k]; !found {
+				return 0, fmt.Errorf("no %q in response", k)
+			}
+		case []any:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(vt) {
+				return 0, fmt.Errorf("invalid index %q for array of length %d", k, len(vt))
+			}
+			v = vt[i]
+		default:
+			return 0, fmt.Errorf("cannot find %q in a %T", k, v)
+		}
+	}
+	switch vt := v.(type) {
+	case float64:
+		return vt, nil
+	case string:
+		return strconv.ParseFloat(vt, 64)
+	}
+	return 0, fmt.Errorf("fee rate is a %T, not a number", v)
+}
@@ -0,0 +1,85 @@
+package txfee
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSource(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			http.Error(w, "no key", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	newSource := func(path string, scale float64) *SourceConfig {
+		t.Helper()
+		src, err := NewHTTPSource(&HTTPSourceConfig{
+			URL:     srv.URL,
+			Path:    path,
+			Scale:   scale,
+			Headers: map[string]string{"X-Api-Key": "key"},
+		})
+		if err != nil {
+			t.Fatalf("NewHTTPSource error: %v", err)
+		}
+		return src
+	}
+
+	for _, tt := range []struct {
+		name    string
+		body    string
+		path    string
+		scale   float64
+		rate    uint64
+		wantErr bool
+	}{
+		{"object", `{"fees":{"fast":25}}`, "fees.fast", 0, 25, false},
+		{"array", `{"fees":[10, 20]}`, "fees.1", 0, 20, false},
+		{"string number", `{"fee":"12.6"}`, "fee", 0, 13, false},
+		{"per kB", `{"fee":15000}`, "fee", 0.001, 15, false},
+		{"missing key", `{"fees":{"slow":1}}`, "fees.fast", 0, 0, true},
+		{"bad index", `{"fees":[10]}`, "fees.1", 0, 0, true},
+		{"not a number", `{"fee":true}`, "fee", 0, 0, true},
+		{"negative", `{"fee":-1}`, "fee", 0, 0, true},
+	} {
+		body = tt.body
+		rate, _, err := newSource(tt.path, tt.scale).F(context.Background())
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wantErr = %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if rate != tt.rate {
+			t.Fatalf("%s: expected rate %d, got %d", tt.name, tt.rate, rate)
+		}
+	}
+
+	// A source without a rank has the lowest priority.
+	if src := newSource("fee", 0); src.Rank != DefaultHTTPSourceRank {
+		t.Fatalf("expected default rank %d, got %d", DefaultHTTPSourceRank, src.Rank)
+	}
+	rank := uint(0)
+	src, err := NewHTTPSource(&HTTPSourceConfig{URL: srv.URL, Path: "fee", Rank: &rank})
+	if err != nil {
+		t.Fatalf("NewHTTPSource error: %v", err)
+	}
+	if src.Rank != 0 {
+		t.Fatalf("expected rank 0, got %d", src.Rank)
+	}
+
+	for _, cfg := range []*HTTPSourceConfig{
+		{URL: "ftp://example.com", Path: "fee"},
+		{URL: srv.URL},
+		{URL: srv.URL, Path: "fee", Period: "soon"},
+		{URL: srv.URL, Path: "fee", Scale: -1},
+	} {
+		if _, err := NewHTTPSource(cfg); err == nil {
+			t.Fatalf("no error for bad config %+v", cfg)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package txfee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+const (
+	// nodeSourceRank is the rank of the node's fee source with
+	// OracleConfig.NodeFeesFirst, ahead of the external sources.
+	nodeSourceRank = 0
+	// oracleRateExpiry is how long a rate from the external sources is used
+	// before falling back to the node's estimate.
+	oracleRateExpiry = time.Minute * 10
+)
+
+// OracleConfig configures the fee rate sources of an asset backend. Rates are
+// in the asset's fee rate units, e.g. sat/vB for Bitcoin or atoms/B for
+// Decred.
+type OracleConfig struct {
+	// DisableAPIFees disables the external sources, built-in and configured,
+	// so that only the node's estimate is used.
+	DisableAPIFees bool `json:"disableApiFees"`
+	// FeeSources are additional external fee rate estimators.
+	FeeSources []*HTTPSourceConfig `json:"feeSources"`
+	// NodeFeesFirst makes the node's estimate the primary fee rate, with the
+	// external sources used when the node has no estimate. By default, the
+	// node is only used when the external sources have no rate.
+	NodeFeesFirst bool `json:"nodeFeesFirst"`
+	// FeeRateFloor and FeeRateCeiling are sanity bounds for the fee rates
+	// from the fee sources. A rate outside of the bounds is ignored. When the
+	// node's estimate is used as a fallback, it is clamped to the bounds.
+	// FeeRateCeiling is ignored if zero.
+	FeeRateFloor   uint64 `json:"feeRateFloor"`
+	FeeRateCeiling uint64 `json:"feeRateCeiling"`
+}
+
+// Validate checks that the settings are consistent and that the fee sources
+// are valid.
+func (cfg *OracleConfig) Validate() error {
+	if cfg.NodeFeesFirst && cfg.DisableAPIFees {
+		return errors.New("nodeFeesFirst cannot be used with disableApiFees, which leaves only the node's estimate")
+	}
+	if cfg.FeeRateCeiling > 0 && cfg.FeeRateFloor > cfg.FeeRateCeiling {
+		return fmt.Errorf("fee rate floor %d is above the ceiling %d", cfg.FeeRateFloor, cfg.FeeRateCeiling)
+	}
+	for _, srcCfg := range cfg.FeeSources {
+		if _, err := NewHTTPSource(srcCfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NodeFeeFunc gets the fee rate estimate of the asset's node.
+type NodeFeeFunc func(ctx context.Context) (uint64, error)
+
+// Oracle provides the fee rate for an asset backend. The rate is from the
+// external fee sources, if any have a current rate, otherwise it is the node's
+// estimate, clamped to the configured bounds.
+type Oracle struct {
+	log      dex.Logger
+	nodeRate NodeFeeFunc
+	floor    uint64
+	ceiling  uint64
+	fetcher  *FeeFetcher // nil if there are no external sources
+
+	mtx   sync.RWMutex
+	rate  uint64
+	stamp time.Time
+}
+
+// NewOracle creates an Oracle from the configuration and the asset's built-in
+// external fee sources. cfg may be nil.
+func NewOracle(cfg *OracleConfig, builtIn []*SourceConfig, nodeRate NodeFeeFunc, log dex.Logger) (*Oracle, error) {
+	if cfg == nil {
+		cfg = new(OracleConfig)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	o := &Oracle{
+		log:      log,
+		nodeRate: nodeRate,
+		floor:    cfg.FeeRateFloor,
+		ceiling:  cfg.FeeRateCeiling,
+	}
+	if cfg.DisableAPIFees {
+		return o, nil
+	}
+	sources := make([]*SourceConfig, 0, len(builtIn)+len(cfg.FeeSources)+1)
+	sources = append(sources, builtIn...)
+	for _, srcCfg := range cfg.FeeSources {
+		src, err := NewHTTPSource(srcCfg)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, src)
+	}
+	if len(sources) == 0 {
+		return o, nil
+	}
+	if cfg.NodeFeesFirst {
+		sources = append(sources, &SourceConfig{
+			Name:   "node",
+			Rank:   nodeSourceRank,
+			Period: time.Minute,
+			F: func(ctx context.Context) (rate uint64, errDelay time.Duration, err error) {
+				rate, err = nodeRate(ctx)
+				return rate, time.Minute, err
+			},
+		})
+	}
+	o.fetcher = NewFeeFetcher(sources, log, WithBounds(cfg.FeeRateFloor, cfg.FeeRateCeiling))
+	return o, nil
+}
+
+// Connect starts fetching rates from the external sources, if there are any.
+// Connect is part of the dex.Connector interface.
+func (o *Oracle) Connect(ctx context.Context) (*sync.WaitGroup, error) {
+	var wg sync.WaitGroup
+	if o.fetcher == nil {
+		return &wg, nil
+	}
+	cm := dex.NewConnectionMaster(o.fetcher)
+	if err := cm.ConnectOnce(ctx); err != nil {
+		return nil, fmt.Errorf("error starting fee fetcher: %w", err)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cm.Disconnect()
+		for {
+			select {
+			case r := <-o.fetcher.Next():
+				o.log.Tracef("New fee reported: %d", r)
+				o.mtx.Lock()
+				o.stamp = time.Now()
+				o.rate = r
+				o.mtx.Unlock()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &wg, nil
+}
+
+// FeeRate is the rate from the external sources if it is current, otherwise
+// the node's estimate clamped to the bounds.
+func (o *Oracle) FeeRate(ctx context.Context) (uint64, error) {
+	if o.fetcher != nil {
+		o.mtx.RLock()
+		stamp, rate := o.stamp, o.rate
+		o.mtx.RUnlock()
+		if time.Since(stamp) < oracleRateExpiry {
+			return rate, nil
+		}
+		o.log.Warnf("External fee rate is expired. Falling back to the node's estimate.")
+	}
+	rate, err := o.nodeRate(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return o.clamp(rate), nil
+}
+
+// clamp limits the node's fee rate to the floor and ceiling.
+func (o *Oracle) clamp(rate uint64) uint64 {
+	if rate < o.floor {
+		o.log.Warnf("Node fee rate %d is below the floor %d. Using the floor.", rate, o.floor)
+		return o.floor
+	}
+	if o.ceiling > 0 && rate > o.ceiling {
+		o.log.Warnf("Node fee rate %d is above the ceiling %d. Using the ceiling.", rate, o.ceiling)
+		return o.ceiling
+	}
+	return rate
+}
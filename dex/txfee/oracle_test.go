@@ -0,0 +1,103 @@
+package txfee
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+func TestOracleConfigValidate(t *testing.T) {
+	for name, cfg := range map[string]*OracleConfig{
+		"node first with API fees disabled": {NodeFeesFirst: true, DisableAPIFees: true},
+		"floor above ceiling":               {FeeRateFloor: 10, FeeRateCeiling: 5},
+		"bad fee source":                    {FeeSources: []*HTTPSourceConfig{{Name: "x", URL: "ftp://x", Path: "fee"}}},
+	} {
+		if cfg.Validate() == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+	if _, err := NewOracle(&OracleConfig{NodeFeesFirst: true, DisableAPIFees: true}, nil, nil, nil); err == nil {
+		t.Fatalf("no NewOracle error for invalid config")
+	}
+	cfg := &OracleConfig{
+		FeeRateFloor:   1,
+		FeeRateCeiling: 100,
+		FeeSources:     []*HTTPSourceConfig{{Name: "x", URL: "https://x", Path: "fee"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("valid config error: %v", err)
+	}
+}
+
+func TestOracle(t *testing.T) {
+	log := dex.StdOutLogger("T", dex.LevelTrace)
+	var nodeRate uint64
+	nodeFee := func(context.Context) (uint64, error) {
+		return nodeRate, nil
+	}
+	ctx := context.Background()
+
+	// Without external sources, the node's estimate is clamped.
+	o, err := NewOracle(&OracleConfig{FeeRateFloor: 2, FeeRateCeiling: 100}, nil, nodeFee, log)
+	if err != nil {
+		t.Fatalf("NewOracle error: %v", err)
+	}
+	if o.fetcher != nil {
+		t.Fatalf("fee fetcher created without sources")
+	}
+	for _, tt := range []struct {
+		rate, want uint64
+	}{
+		{1, 2},
+		{2, 2},
+		{50, 50},
+		{100, 100},
+		{500, 100},
+	} {
+		nodeRate = tt.rate
+		if r, _ := o.FeeRate(ctx); r != tt.want {
+			t.Fatalf("node rate %d: got %d, want %d", tt.rate, r, tt.want)
+		}
+	}
+	// No ceiling.
+	o.ceiling = 0
+	nodeRate = 500
+	if r, _ := o.FeeRate(ctx); r != 500 {
+		t.Fatalf("rate clamped with no ceiling: %d", r)
+	}
+
+	// A current external rate is used ahead of the node's estimate.
+	builtIn := []*SourceConfig{{Name: "ext", Rank: 1, Period: time.Minute}}
+	o, err = NewOracle(&OracleConfig{}, builtIn, nodeFee, log)
+	if err != nil {
+		t.Fatalf("NewOracle error: %v", err)
+	}
+	if o.fetcher == nil || len(o.fetcher.sources) != 1 {
+		t.Fatalf("wrong fee fetcher sources")
+	}
+	if r, _ := o.FeeRate(ctx); r != 500 {
+		t.Fatalf("wrong rate with no external rate: %d", r)
+	}
+	o.rate, o.stamp = 20, time.Now()
+	if r, _ := o.FeeRate(ctx); r != 20 {
+		t.Fatalf("external rate not used: %d", r)
+	}
+	o.stamp = time.Now().Add(-oracleRateExpiry)
+	if r, _ := o.FeeRate(ctx); r != 500 {
+		t.Fatalf("expired external rate used: %d", r)
+	}
+
+	// NodeFeesFirst adds the node as the top ranked source.
+	o, _ = NewOracle(&OracleConfig{NodeFeesFirst: true}, builtIn, nodeFee, log)
+	if groups := o.fetcher.sources; len(groups) != 2 || groups[0][0].Name != "node" {
+		t.Fatalf("node is not the first source")
+	}
+
+	// Disabling API fees leaves only the node.
+	o, _ = NewOracle(&OracleConfig{DisableAPIFees: true}, builtIn, nodeFee, log)
+	if o.fetcher != nil {
+		t.Fatalf("fee fetcher created with API fees disabled")
+	}
+}
@@ -0,0 +1,253 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package webhook delivers event notifications to configured HTTP endpoints.
+// Each event is POSTed as JSON and signed with an HMAC-SHA256 of the request
+// body keyed with a shared secret, so that receivers can authenticate the
+// payload. Failed deliveries are retried with exponential backoff. The event
+// types and their data are defined by the sender, e.g. the server's account
+// events and client core's trade events.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+const (
+	// SignatureHeader is the HTTP header holding the hex-encoded HMAC-SHA256
+	// of the request body, prefixed with "sha256=".
+	SignatureHeader = "X-Dcrdex-Signature"
+	// EventHeader is the HTTP header holding the event type.
+	EventHeader = "X-Dcrdex-Event"
+	// DeliveryHeader is the HTTP header holding the event ID. Retries of the
+	// same event use the same ID so that receivers may ignore duplicates.
+	DeliveryHeader = "X-Dcrdex-Delivery"
+
+	// DefaultMaxAttempts is the default number of delivery attempts for each
+	// event and endpoint.
+	DefaultMaxAttempts = 5
+
+	// queueSize is the number of undelivered events buffered for each
+	// endpoint. Events are dropped when an endpoint's queue is full.
+	queueSize = 1024
+	// requestTimeout bounds each delivery attempt.
+	requestTimeout = 10 * time.Second
+)
+
+// retryDelay is the wait after the first failed attempt. The delay doubles for
+// each subsequent attempt.
+var retryDelay = 2 * time.Second
+
+// Event is the JSON body of a webhook request.
+type Event struct {
+	// ID is a random identifier for the event.
+	ID string `json:"id"`
+	// Type is the event type, as passed to Notify.
+	Type string `json:"type"`
+	// Time is the time of the event in milliseconds since the unix epoch.
+	Time int64 `json:"time"`
+	// Data is the event type's payload.
+	Data any `json:"data"`
+}
+
+// Config is the configuration for a Notifier.
+type Config struct {
+	// URLs are the http or https endpoints to which every event is sent.
+	URLs []string
+	// Secret is the HMAC key used to sign request bodies.
+	Secret string
+	// MaxAttempts is the number of delivery attempts made for each event and
+	// endpoint before the event is dropped. Default is DefaultMaxAttempts.
+	MaxAttempts int
+	Logger      dex.Logger
+}
+
+type delivery struct {
+	id      string
+	evtType string
+	body    []byte
+}
+
+// endpoint is a webhook URL and its queue of pending deliveries.
+type endpoint struct {
+	url   string
+	queue chan *delivery
+}
+
+// Notifier delivers events to the configured webhook endpoints. Notify may be
+// called before Run, but nothing is delivered until Run is called. Notifier
+// satisfies dex.Runner.
+type Notifier struct {
+	secret      []byte
+	maxAttempts int
+	log         dex.Logger
+	client      *http.Client
+	endpoints   []*endpoint
+}
+
+// NewNotifier is the constructor for a Notifier.
+func NewNotifier(cfg *Config) (*Notifier, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, errors.New("no webhook URLs")
+	}
+	if cfg.Secret == "" {
+		return nil, errors.New("no webhook secret")
+	}
+	if cfg.Logger == nil {
+		return nil, errors.New("no logger")
+	}
+	endpoints := make([]*endpoint, 0, len(cfg.URLs))
+	for _, s := range cfg.URLs {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook URL %q: %w", s, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q: must be an absolute http or https URL", s)
+		}
+		endpoints = append(endpoints, &endpoint{
+			url:   u.String(),
+			queue: make(chan *delivery, queueSize),
+		})
+	}
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return &Notifier{
+		secret:      []byte(cfg.Secret),
+		maxAttempts: maxAttempts,
+		log:         cfg.Logger,
+		client:      &http.Client{Timeout: requestTimeout},
+		endpoints:   endpoints,
+	}, nil
+}
+
+// Notify queues the event for delivery to every endpoint. Notify does not
+// block. If an endpoint's queue is full, the event is dropped for that
+// endpoint.
+func (n *Notifier) Notify(evtType string, data any) {
+	var id [16]byte
+	rand.Read(id[:])
+	evt := &Event{
+		ID:   hex.EncodeToString(id[:]),
+		Type: evtType,
+		Time: time.Now().UnixMilli(),
+		Data: data,
+	}
+	body, err := json.Marshal(evt)
+	if err != nil {
+		n.log.Errorf("Error encoding %s webhook event: %v", evtType, err)
+		return
+	}
+	d := &delivery{id: evt.ID, evtType: evtType, body: body}
+	for _, ep := range n.endpoints {
+		select {
+		case ep.queue <- d:
+		default:
+			n.log.Warnf("Webhook queue for %s is full. Dropping %s event %s", ep.url, evtType, evt.ID)
+		}
+	}
+}
+
+// Run delivers queued events until the context is canceled. Each endpoint is
+// served by its own goroutine, so a slow or failing endpoint does not delay
+// deliveries to the others. Events are delivered to an endpoint in the order
+// they were queued.
+func (n *Notifier) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ep := range n.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			for {
+				select {
+				case d := <-ep.queue:
+					n.deliver(ctx, ep.url, d)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ep)
+	}
+	wg.Wait()
+}
+
+// deliver sends the event to the endpoint URL, retrying with exponential
+// backoff until it is accepted, the receiver rejects it, the attempts are
+// exhausted, or the context is canceled.
+func (n *Notifier) deliver(ctx context.Context, epURL string, d *delivery) {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(ctx, epURL, d)
+		if err == nil {
+			n.log.Debugf("Delivered %s event %s to %s", d.evtType, d.id, epURL)
+			return
+		}
+		if !retry || attempt >= n.maxAttempts {
+			n.log.Errorf("Failed to deliver %s event %s to %s after %d attempt(s): %v",
+				d.evtType, d.id, epURL, attempt, err)
+			return
+		}
+		n.log.Warnf("Error delivering %s event %s to %s (attempt %d of %d), retrying in %v: %v",
+			d.evtType, d.id, epURL, attempt, n.maxAttempts, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay *= 2
+	}
+}
+
+// post makes a single delivery attempt. If the attempt fails, retry indicates
+// whether another attempt may succeed. Client errors other than timeouts and
+// rate limiting are not retried.
+func (n *Notifier) post(ctx context.Context, epURL string, d *delivery) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, epURL, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, d.evtType)
+	req.Header.Set(DeliveryHeader, d.id)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(n.secret, d.body))
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
+	switch code := resp.StatusCode; {
+	case code >= 200 && code < 300:
+		return false, nil
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests, code >= 500:
+		return true, fmt.Errorf("status %s", resp.Status)
+	default:
+		return false, fmt.Errorf("status %s", resp.Status)
+	}
+}
+
+// Sign computes the hex-encoded HMAC-SHA256 of the body with the secret. The
+// SignatureHeader of a webhook request is "sha256=" followed by Sign(secret,
+// body).
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
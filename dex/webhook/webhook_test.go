@@ -0,0 +1,140 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+var tLogger = dex.StdOutLogger("HOOK_TEST", dex.LevelTrace)
+
+const tEvent = "test_event"
+
+type tEventData struct {
+	ID string `json:"id"`
+}
+
+func TestNewNotifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr bool
+	}{
+		{"ok", &Config{URLs: []string{"https://example.com/hook"}, Secret: "s", Logger: tLogger}, false},
+		{"no urls", &Config{Secret: "s", Logger: tLogger}, true},
+		{"no secret", &Config{URLs: []string{"https://example.com/hook"}, Logger: tLogger}, true},
+		{"bad scheme", &Config{URLs: []string{"ftp://example.com/hook"}, Secret: "s", Logger: tLogger}, true},
+		{"relative", &Config{URLs: []string{"/hook"}, Secret: "s", Logger: tLogger}, true},
+	}
+	for _, tt := range tests {
+		_, err := NewNotifier(tt.cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: wantErr = %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestDelivery(t *testing.T) {
+	defer func(d time.Duration) { retryDelay = d }(retryDelay)
+	retryDelay = time.Millisecond
+
+	const secret = "abc"
+
+	// The first attempt fails with a server error, the second succeeds.
+	var attempts uint32
+	received := make(chan *Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddUint32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if sig := r.Header.Get(SignatureHeader); sig != "sha256="+Sign([]byte(secret), body) {
+			t.Errorf("wrong signature %q", sig)
+		}
+		if evtType := r.Header.Get(EventHeader); evtType != tEvent {
+			t.Errorf("wrong event header %q", evtType)
+		}
+		evt := new(Event)
+		if err := json.Unmarshal(body, evt); err != nil {
+			t.Errorf("error decoding event: %v", err)
+		}
+		if r.Header.Get(DeliveryHeader) != evt.ID {
+			t.Errorf("wrong delivery header")
+		}
+		received <- evt
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(&Config{URLs: []string{srv.URL}, Secret: secret, Logger: tLogger})
+	if err != nil {
+		t.Fatalf("NewNotifier error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+
+	n.Notify(tEvent, &tEventData{ID: "01"})
+
+	select {
+	case evt := <-received:
+		if evt.Type != tEvent {
+			t.Fatalf("wrong event type %q", evt.Type)
+		}
+		data, _ := json.Marshal(evt.Data)
+		var d tEventData
+		if err := json.Unmarshal(data, &d); err != nil {
+			t.Fatalf("error decoding data: %v", err)
+		}
+		if d.ID != "01" {
+			t.Fatalf("wrong data ID %s", d.ID)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("event not delivered")
+	}
+	if n := atomic.LoadUint32(&attempts); n != 2 {
+		t.Fatalf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	defer func(d time.Duration) { retryDelay = d }(retryDelay)
+	retryDelay = time.Millisecond
+
+	var attempts uint32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(&Config{URLs: []string{srv.URL}, Secret: "abc", Logger: tLogger})
+	if err != nil {
+		t.Fatalf("NewNotifier error: %v", err)
+	}
+	n.deliver(context.Background(), srv.URL, &delivery{id: "1", evtType: tEvent, body: []byte("{}")})
+	if n := atomic.LoadUint32(&attempts); n != 1 {
+		t.Fatalf("expected 1 attempt, got %d", n)
+	}
+
+	// Server errors are retried up to maxAttempts.
+	atomic.StoreUint32(&attempts, 0)
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	n.deliver(context.Background(), srv.URL, &delivery{id: "2", evtType: tEvent, body: []byte("{}")})
+	if n := atomic.LoadUint32(&attempts); n != DefaultMaxAttempts {
+		t.Fatalf("expected %d attempts, got %d", DefaultMaxAttempts, n)
+	}
+}
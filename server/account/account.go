@@ -113,6 +113,8 @@ const (
 	// LowFees means an account made a transaction that didn't pay fees at the
 	// requisite level.
 	LowFees
+	// Spam means an account repeatedly exceeded the request rate limits.
+	Spam
 	// MaxRule in not an actual rule. It is a placeholder that is used to
 	// determine the total number of rules. It must always be the last
 	// definition in this list.
@@ -146,6 +148,10 @@ var ruleDetails = map[Rule]details{
 		name:        "LowFees",
 		description: "did not pay transaction mining fees at the requisite level",
 	},
+	Spam: {
+		name:        "Spam",
+		description: "repeatedly exceeded the request rate limits",
+	},
 }
 
 // String satisfies the Stringer interface.
@@ -169,6 +175,37 @@ func (r Rule) Punishable() bool {
 	return r > NoRule && r < MaxRule
 }
 
+// Standing is the trading privilege of an account as determined by its ban
+// score. Each Standing is more restrictive than the previous.
+type Standing uint8
+
+const (
+	// StandingGood means the account may trade without restriction.
+	StandingGood Standing = iota
+	// StandingCapped means the account's orders are limited in size.
+	StandingCapped
+	// StandingSuspended means the account may not place orders until its
+	// suspension ends.
+	StandingSuspended
+	// StandingBanned means the account may not place orders.
+	StandingBanned
+)
+
+// String satisfies the Stringer interface.
+func (s Standing) String() string {
+	switch s {
+	case StandingGood:
+		return "good"
+	case StandingCapped:
+		return "capped"
+	case StandingSuspended:
+		return "suspended"
+	case StandingBanned:
+		return "banned"
+	}
+	return "unknown standing"
+}
+
 // Reputation is a part of a number of server-originating messages. It was
 // introduced with the v2 ConnectResult.
 type Reputation struct {
@@ -11,6 +11,8 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -295,29 +297,7 @@ func (s *Server) apiMarketMatches(w http.ResponseWriter, r *http.Request) {
 	enc.SetIndent("", "    ")
 	Nout, err := s.core.MarketMatchesStreaming(status.Base, status.Quote, includeInactive, N,
 		func(match *dexsrv.MatchData) error {
-			md := &MatchData{
-				TakerSell:   match.TakerSell,
-				ID:          match.ID.String(),
-				Maker:       match.Maker.String(),
-				MakerAcct:   match.MakerAcct.String(),
-				MakerSwap:   match.MakerSwap,
-				MakerRedeem: match.MakerRedeem,
-				MakerAddr:   match.MakerAddr,
-				Taker:       match.Taker.String(),
-				TakerAcct:   match.TakerAcct.String(),
-				TakerSwap:   match.TakerSwap,
-				TakerRedeem: match.TakerRedeem,
-				TakerAddr:   match.TakerAddr,
-				EpochIdx:    match.Epoch.Idx,
-				EpochDur:    match.Epoch.Dur,
-				Quantity:    match.Quantity,
-				Rate:        match.Rate,
-				BaseRate:    match.BaseRate,
-				QuoteRate:   match.QuoteRate,
-				Active:      match.Active,
-				Status:      match.Status.String(),
-			}
-			return enc.Encode(md)
+			return enc.Encode(newMatchData(match))
 		})
 	if err != nil {
 		log.Warnf("Failed to write matches response: %v", err)
@@ -327,6 +307,32 @@ func (s *Server) apiMarketMatches(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// newMatchData converts the DEX's match data into the MatchData API type.
+func newMatchData(match *dexsrv.MatchData) *MatchData {
+	return &MatchData{
+		TakerSell:   match.TakerSell,
+		ID:          match.ID.String(),
+		Maker:       match.Maker.String(),
+		MakerAcct:   match.MakerAcct.String(),
+		MakerSwap:   match.MakerSwap,
+		MakerRedeem: match.MakerRedeem,
+		MakerAddr:   match.MakerAddr,
+		Taker:       match.Taker.String(),
+		TakerAcct:   match.TakerAcct.String(),
+		TakerSwap:   match.TakerSwap,
+		TakerRedeem: match.TakerRedeem,
+		TakerAddr:   match.TakerAddr,
+		EpochIdx:    match.Epoch.Idx,
+		EpochDur:    match.Epoch.Dur,
+		Quantity:    match.Quantity,
+		Rate:        match.Rate,
+		BaseRate:    match.BaseRate,
+		QuoteRate:   match.QuoteRate,
+		Active:      match.Active,
+		Status:      match.Status.String(),
+	}
+}
+
 // handler for route '/market/{marketName}/resume?t=UNIXMS'
 func (s *Server) apiResume(w http.ResponseWriter, r *http.Request) {
 	// Ensure the market exists and is not running.
@@ -435,6 +441,42 @@ func (s *Server) apiSuspend(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handler for route '/market/{marketName}/swapconf?asset=SYMBOL&confs=N'
+func (s *Server) apiSetMarketSwapConf(w http.ResponseWriter, r *http.Request) {
+	mkt := strings.ToLower(chi.URLParam(r, marketNameKey))
+	if found, _ := s.core.MarketRunning(mkt); !found {
+		http.Error(w, fmt.Sprintf("unknown market %q", mkt), http.StatusBadRequest)
+		return
+	}
+
+	assetSymbol := strings.ToLower(r.URL.Query().Get("asset"))
+	assetID, found := dex.BipSymbolID(assetSymbol)
+	if !found {
+		http.Error(w, fmt.Sprintf("unknown asset %q", assetSymbol), http.StatusBadRequest)
+		return
+	}
+
+	// A confs of zero removes the market's override.
+	confsStr := r.URL.Query().Get("confs")
+	swapConf, err := strconv.ParseUint(confsStr, 10, 32)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid confs %q: %v", confsStr, err), http.StatusBadRequest)
+		return
+	}
+
+	if err = s.core.SetMarketSwapConf(mkt, assetID, uint32(swapConf)); err != nil {
+		http.Error(w, fmt.Sprintf("failed to set swapConf: %v", err), http.StatusBadRequest)
+		return
+	}
+	log.Infof("Set %s swapConf on market %s to %d", strings.ToUpper(assetSymbol), mkt, swapConf)
+
+	writeJSON(w, &SwapConfResult{
+		Market:   mkt,
+		Asset:    assetSymbol,
+		SwapConf: uint32(swapConf),
+	})
+}
+
 // apiEnableDataAPI is the handler for the `/enabledataapi/{yes}` API request,
 // used to enable or disable the HTTP data API.
 func (s *Server) apiEnableDataAPI(w http.ResponseWriter, r *http.Request) {
@@ -564,6 +606,43 @@ func (s *Server) apiForgiveMatchFail(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, res)
 }
 
+// apiBanScore is the handler for the '/account/{accountID}/banscore' API
+// request.
+func (s *Server) apiBanScore(w http.ResponseWriter, r *http.Request) {
+	acctID, err := decodeAcctID(chi.URLParam(r, accountIDKey))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	info, err := s.core.BanScore(acctID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to retrieve ban score: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, info)
+}
+
+// apiClearBanScore is the handler for the '/account/{accountID}/banscore/clear'
+// API request. The account's ban score is reset, lifting any suspension or ban,
+// and the new ban score is returned.
+func (s *Server) apiClearBanScore(w http.ResponseWriter, r *http.Request) {
+	acctID, err := decodeAcctID(chi.URLParam(r, accountIDKey))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.core.ClearBanScore(acctID); err != nil {
+		http.Error(w, fmt.Sprintf("failed to clear ban score for account %v: %v", acctID, err), http.StatusInternalServerError)
+		return
+	}
+	info, err := s.core.BanScore(acctID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to retrieve ban score: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, info)
+}
+
 func (s *Server) apiMatchOutcomes(w http.ResponseWriter, r *http.Request) {
 	acctIDStr := chi.URLParam(r, accountIDKey)
 	acctID, err := decodeAcctID(acctIDStr)
@@ -587,6 +666,22 @@ func (s *Server) apiMatchOutcomes(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, outcomes)
 }
 
+// apiAccountReputation is the handler for the '/account/{accountID}/reputation'
+// API request.
+func (s *Server) apiAccountReputation(w http.ResponseWriter, r *http.Request) {
+	acctID, err := decodeAcctID(chi.URLParam(r, accountIDKey))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	report, err := s.core.AccountReputation(acctID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to compute reputation: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, report)
+}
+
 func (s *Server) apiMatchFails(w http.ResponseWriter, r *http.Request) {
 	acctIDStr := chi.URLParam(r, accountIDKey)
 	acctID, err := decodeAcctID(acctIDStr)
@@ -610,6 +705,223 @@ func (s *Server) apiMatchFails(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, fails)
 }
 
+// apiMarketTradingFees is the handler for the '/market/{marketName}/fees' API
+// request.
+func (s *Server) apiMarketTradingFees(w http.ResponseWriter, r *http.Request) {
+	mkt := strings.ToLower(chi.URLParam(r, marketNameKey))
+	status := s.core.MarketStatus(mkt)
+	if status == nil {
+		http.Error(w, fmt.Sprintf("unknown market %q", mkt), http.StatusBadRequest)
+		return
+	}
+	totals, err := s.core.MarketTradingFees(status.Base, status.Quote)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to retrieve trading fees: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, tradingFeeTotals(totals))
+}
+
+// apiAccountTradingFees is the handler for the '/account/{accountID}/fees' API
+// request.
+func (s *Server) apiAccountTradingFees(w http.ResponseWriter, r *http.Request) {
+	acctIDStr := chi.URLParam(r, accountIDKey)
+	acctID, err := decodeAcctID(acctIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	totals, err := s.core.AccountTradingFees(acctID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to retrieve trading fees: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, tradingFeeTotals(totals))
+}
+
+// tradingFeeTotals converts per-asset fee totals to a slice sorted by asset ID.
+func tradingFeeTotals(totals map[uint32]uint64) []*TradingFeeTotal {
+	fees := make([]*TradingFeeTotal, 0, len(totals))
+	for assetID, amt := range totals {
+		fees = append(fees, &TradingFeeTotal{
+			AssetID: assetID,
+			Symbol:  dex.BipIDSymbol(assetID),
+			Amount:  amt,
+		})
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i].AssetID < fees[j].AssetID })
+	return fees
+}
+
+// apiDailyReports is the handler for the '/reports' API request. The response
+// lists the dates of the available daily reports.
+func (s *Server) apiDailyReports(w http.ResponseWriter, _ *http.Request) {
+	dates, err := s.core.DailyReportDates()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list daily reports: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, dates)
+}
+
+// apiDailyReport is the handler for the '/report/{date}' API request. The date
+// is a UTC day formatted as YYYY-MM-DD, and the day must have ended.
+func (s *Server) apiDailyReport(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, dateKey)
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", date), http.StatusBadRequest)
+		return
+	}
+	if !day.AddDate(0, 0, 1).Before(time.Now()) {
+		http.Error(w, fmt.Sprintf("day %s has not ended", date), http.StatusBadRequest)
+		return
+	}
+	report, err := s.core.DailyReport(date)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to retrieve daily report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, report)
+}
+
+// apiReloadTLS is the handler for the '/tls/reload' API request. The admin and
+// comms server certificates are reloaded from disk without disconnecting
+// clients.
+func (s *Server) apiReloadTLS(w http.ResponseWriter, _ *http.Request) {
+	if err := s.ReloadTLS(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to reload TLS certificates: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, "TLS certificates reloaded")
+}
+
+// apiDrainStatus is the handler for the '/drain' API request.
+func (s *Server) apiDrainStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, s.core.DrainStatus())
+}
+
+// apiDrain is the handler for the '/drain/start?redirect=ADDR' API request. The
+// DEX stops accepting new websocket connections, telling refused clients to
+// retry at the optional redirect address, a ws or wss URL. Existing connections
+// are unaffected, and clients with active swaps may reconnect.
+func (s *Server) apiDrain(w http.ResponseWriter, r *http.Request) {
+	redirect := r.URL.Query().Get(redirectKey)
+	if redirect != "" {
+		u, err := url.Parse(redirect)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			http.Error(w, fmt.Sprintf("redirect address %q is not a websocket URL", redirect), http.StatusBadRequest)
+			return
+		}
+	}
+	writeJSON(w, s.core.Drain(redirect))
+}
+
+// apiStopDraining is the handler for the '/drain/stop' API request.
+func (s *Server) apiStopDraining(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, s.core.StopDraining())
+}
+
+// apiPrune is the handler for the '/prune?days=N' API request. Archived data
+// older than N days is deleted. If days is not specified, the server's
+// configured retention period is used.
+func (s *Server) apiPrune(w http.ResponseWriter, r *http.Request) {
+	var retention time.Duration
+	if daysStr := r.URL.Query().Get(daysKey); daysStr != "" {
+		days, err := strconv.ParseUint(daysStr, 10, 16)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error parsing days: %v", err), http.StatusBadRequest)
+			return
+		}
+		if days == 0 {
+			http.Error(w, "days parsed to zero", http.StatusBadRequest)
+			return
+		}
+		retention = time.Duration(days) * time.Hour * 24
+	}
+	report, err := s.core.PruneArchive(retention)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to prune archive: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, report)
+}
+
+// apiThresholds is the handler for the '/thresholds' API request.
+func (s *Server) apiThresholds(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, s.core.AuthThresholds())
+}
+
+// apiSetThresholds is the handler for the
+// '/thresholds/set?cancelthresh=F&freecancels=B&penaltythreshold=N&gracelimit=N&suspension=D&banhalflife=D'
+// API request. A gracelimit of "derived" derives the grace limit from the
+// cancellation threshold. suspension and banhalflife are durations, e.g. 24h.
+// Omitted parameters are unchanged.
+func (s *Server) apiSetThresholds(w http.ResponseWriter, r *http.Request) {
+	t := s.core.AuthThresholds()
+	query := r.URL.Query()
+	if str := query.Get(cancelThreshKey); str != "" {
+		thresh, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid cancellation threshold %q: %v", str, err), http.StatusBadRequest)
+			return
+		}
+		t.CancelThreshold = thresh
+	}
+	if str := query.Get(freeCancelsKey); str != "" {
+		free, err := strconv.ParseBool(str)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid free cancels setting %q: %v", str, err), http.StatusBadRequest)
+			return
+		}
+		t.FreeCancels = free
+	}
+	if str := query.Get(penaltyThreshKey); str != "" {
+		thresh, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid penalty threshold %q: %v", str, err), http.StatusBadRequest)
+			return
+		}
+		t.PenaltyThreshold = uint32(thresh)
+	}
+	if str := query.Get(graceLimitKey); str == "derived" {
+		t.GraceLimit = nil
+	} else if str != "" {
+		grace, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid grace limit %q: %v", str, err), http.StatusBadRequest)
+			return
+		}
+		graceLimit := uint32(grace)
+		t.GraceLimit = &graceLimit
+	}
+	if str := query.Get(suspensionKey); str != "" {
+		period, err := time.ParseDuration(str)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid suspension period %q: %v", str, err), http.StatusBadRequest)
+			return
+		}
+		t.SuspensionPeriod = period
+	}
+	if str := query.Get(banHalfLifeKey); str != "" {
+		halfLife, err := time.ParseDuration(str)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid ban score half-life %q: %v", str, err), http.StatusBadRequest)
+			return
+		}
+		t.BanScoreHalfLife = halfLife
+	}
+	if err := t.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.core.SetAuthThresholds(t); err != nil {
+		http.Error(w, fmt.Sprintf("failed to set thresholds: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, s.core.AuthThresholds())
+}
+
 func toNote(r *http.Request) (*msgjson.Message, int, error) {
 	body, err := io.ReadAll(r.Body)
 	r.Body.Close()
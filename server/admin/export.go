@@ -0,0 +1,270 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package admin
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/account"
+	dexsrv "decred.org/dcrdex/server/dex"
+)
+
+const (
+	exportFormatCSV   = "csv"
+	exportFormatJSONL = "jsonl"
+)
+
+var (
+	historyMatchCSVHeader = []string{"market", "time", "id", "takerSell",
+		"makerOrder", "makerAcct", "makerSwap", "makerRedeem", "makerAddr",
+		"takerOrder", "takerAcct", "takerSwap", "takerRedeem", "takerAddr",
+		"epochIdx", "epochDur", "quantity", "rate", "baseFeeRate", "quoteFeeRate",
+		"active", "status"}
+	historyOrderCSVHeader = []string{"market", "id", "type", "account", "sell",
+		"clientTime", "serverTime", "quantity", "rate", "tif", "filled",
+		"address", "targetOrder", "status"}
+)
+
+func (hm *HistoryMatch) csvRecord() []string {
+	return []string{hm.Market, strconv.FormatInt(hm.Time, 10), hm.ID, strconv.FormatBool(hm.TakerSell),
+		hm.Maker, hm.MakerAcct, hm.MakerSwap, hm.MakerRedeem, hm.MakerAddr,
+		hm.Taker, hm.TakerAcct, hm.TakerSwap, hm.TakerRedeem, hm.TakerAddr,
+		strconv.FormatUint(hm.EpochIdx, 10), strconv.FormatUint(hm.EpochDur, 10),
+		strconv.FormatUint(hm.Quantity, 10), strconv.FormatUint(hm.Rate, 10),
+		strconv.FormatUint(hm.BaseRate, 10), strconv.FormatUint(hm.QuoteRate, 10),
+		strconv.FormatBool(hm.Active), hm.Status}
+}
+
+func (ho *HistoryOrder) csvRecord() []string {
+	return []string{ho.Market, ho.ID, ho.Type, ho.Account, strconv.FormatBool(ho.Sell),
+		strconv.FormatInt(ho.ClientTime, 10), strconv.FormatInt(ho.ServerTime, 10),
+		strconv.FormatUint(ho.Quantity, 10), strconv.FormatUint(ho.Rate, 10), ho.TimeInForce,
+		strconv.FormatUint(ho.Filled, 10), ho.Address, ho.TargetOrder, ho.Status}
+}
+
+// newHistoryOrder converts an archived order into the HistoryOrder API type.
+func newHistoryOrder(mkt string, ord order.Order, status order.OrderStatus) *HistoryOrder {
+	prefix := ord.Prefix()
+	ho := &HistoryOrder{
+		Market:     mkt,
+		ID:         ord.ID().String(),
+		Type:       ord.Type().String(),
+		Account:    prefix.AccountID.String(),
+		ClientTime: prefix.ClientTime.UnixMilli(),
+		ServerTime: prefix.ServerTime.UnixMilli(),
+		Status:     status.String(),
+	}
+	if trade := ord.Trade(); trade != nil {
+		ho.Sell = trade.Sell
+		ho.Quantity = trade.Quantity
+		ho.Filled = trade.Filled()
+		ho.Address = trade.Address
+	}
+	switch o := ord.(type) {
+	case *order.LimitOrder:
+		ho.Rate = o.Rate
+		ho.TimeInForce = o.Force.String()
+	case *order.CancelOrder:
+		ho.TargetOrder = o.TargetOrderID.String()
+	}
+	return ho
+}
+
+// historyRequest is a parsed trade history export request, with one filter
+// per market to export.
+type historyRequest struct {
+	markets []string
+	filters []*dexsrv.HistoryFilter
+	csv     bool
+}
+
+// parseHistoryRequest parses the query of an export request. The market is
+// optional if an account is specified, in which case all markets are exported.
+// The time range is [from, to), in unix ms, and defaults to all time up to now.
+func (s *Server) parseHistoryRequest(r *http.Request) (*historyRequest, error) {
+	q := r.URL.Query()
+	req := new(historyRequest)
+	switch format := strings.ToLower(q.Get(formatKey)); format {
+	case exportFormatCSV:
+		req.csv = true
+	case exportFormatJSONL, "":
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	var acct *account.AccountID
+	if acctIDStr := q.Get(accountIDKey); acctIDStr != "" {
+		acctID, err := decodeAcctID(acctIDStr)
+		if err != nil {
+			return nil, err
+		}
+		acct = &acctID
+	}
+
+	parseTime := func(key string, def time.Time) (time.Time, error) {
+		tStr := q.Get(key)
+		if tStr == "" {
+			return def, nil
+		}
+		tMs, err := strconv.ParseInt(tStr, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s time %q: %w", key, tStr, err)
+		}
+		return time.UnixMilli(tMs), nil
+	}
+	start, err := parseTime(fromKey, time.UnixMilli(0))
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseTime(toKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !end.After(start) {
+		return nil, errors.New("to time must be after from time")
+	}
+
+	if mkt := strings.ToLower(q.Get(marketNameKey)); mkt != "" {
+		status := s.core.MarketStatus(mkt)
+		if status == nil {
+			return nil, fmt.Errorf("unknown market %q", mkt)
+		}
+		req.markets = []string{mkt}
+		req.filters = []*dexsrv.HistoryFilter{{
+			Base:    status.Base,
+			Quote:   status.Quote,
+			Account: acct,
+			Start:   start,
+			End:     end,
+		}}
+		return req, nil
+	}
+	if acct == nil {
+		return nil, errors.New("a market or account must be specified")
+	}
+	statuses := s.core.MarketStatuses()
+	for mkt := range statuses {
+		req.markets = append(req.markets, mkt)
+	}
+	sort.Strings(req.markets)
+	for _, mkt := range req.markets {
+		req.filters = append(req.filters, &dexsrv.HistoryFilter{
+			Base:    statuses[mkt].Base,
+			Quote:   statuses[mkt].Quote,
+			Account: acct,
+			Start:   start,
+			End:     end,
+		})
+	}
+	return req, nil
+}
+
+// historyEncoder writes the records of a trade history export as CSV or as
+// JSON lines.
+type historyEncoder struct {
+	csv *csv.Writer
+	enc *json.Encoder
+}
+
+func newHistoryEncoder(w http.ResponseWriter, asCSV bool, csvHeader []string) (*historyEncoder, error) {
+	if !asCSV {
+		w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+		return &historyEncoder{enc: json.NewEncoder(w)}, nil
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	he := &historyEncoder{csv: csv.NewWriter(w)}
+	return he, he.csv.Write(csvHeader)
+}
+
+func (he *historyEncoder) encode(thing interface{ csvRecord() []string }) error {
+	if he.csv != nil {
+		return he.csv.Write(thing.csvRecord())
+	}
+	return he.enc.Encode(thing)
+}
+
+func (he *historyEncoder) flush() error {
+	if he.csv == nil {
+		return nil
+	}
+	he.csv.Flush()
+	return he.csv.Error()
+}
+
+// exportHistory streams the records of each market in the request using the
+// provided history function. An http error is only sent if the failure comes
+// before any records have been written.
+func exportHistory(w http.ResponseWriter, req *historyRequest, csvHeader []string,
+	history func(mkt string, filter *dexsrv.HistoryFilter, he *historyEncoder) (int, error)) {
+	he, err := newHistoryEncoder(w, req.csv, csvHeader)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to write export: %v", err), http.StatusInternalServerError)
+		return
+	}
+	var Nout int
+	for i, filter := range req.filters {
+		n, err := history(req.markets[i], filter, he)
+		Nout += n
+		if err != nil {
+			log.Warnf("Failed to write %s history export: %v", req.markets[i], err)
+			if Nout == 0 {
+				http.Error(w, fmt.Sprintf("failed to retrieve %s history: %v", req.markets[i], err),
+					http.StatusInternalServerError)
+				return
+			} // otherwise too late for an http error code
+			break
+		}
+	}
+	if err = he.flush(); err != nil {
+		log.Warnf("Failed to write history export: %v", err)
+	}
+}
+
+// apiExportMatches is the handler for the
+// '/export/matches?market=NAME&account=ID&from=UNIXMS&to=UNIXMS&format=csv|jsonl'
+// API request. The matches are streamed oldest first, one market at a time.
+func (s *Server) apiExportMatches(w http.ResponseWriter, r *http.Request) {
+	req, err := s.parseHistoryRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	exportHistory(w, req, historyMatchCSVHeader,
+		func(mkt string, filter *dexsrv.HistoryFilter, he *historyEncoder) (int, error) {
+			return s.core.MatchHistory(filter, func(match *dexsrv.MatchData) error {
+				return he.encode(&HistoryMatch{
+					Market:    mkt,
+					Time:      int64(match.Epoch.Idx * match.Epoch.Dur),
+					MatchData: *newMatchData(match),
+				})
+			})
+		})
+}
+
+// apiExportOrders is the handler for the
+// '/export/orders?market=NAME&account=ID&from=UNIXMS&to=UNIXMS&format=csv|jsonl'
+// API request. For each market, the trade orders and then the cancel orders
+// are streamed, oldest first.
+func (s *Server) apiExportOrders(w http.ResponseWriter, r *http.Request) {
+	req, err := s.parseHistoryRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	exportHistory(w, req, historyOrderCSVHeader,
+		func(mkt string, filter *dexsrv.HistoryFilter, he *historyEncoder) (int, error) {
+			return s.core.OrderHistory(filter, func(ord order.Order, status order.OrderStatus) error {
+				return he.encode(newHistoryOrder(mkt, ord, status))
+			})
+		})
+}
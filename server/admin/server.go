@@ -24,7 +24,7 @@ import (
 	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/server/asset"
 	"decred.org/dcrdex/server/auth"
-	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/comms"
 	dexsrv "decred.org/dcrdex/server/dex"
 	"decred.org/dcrdex/server/market"
 	"github.com/decred/slog"
@@ -49,6 +49,17 @@ const (
 	nKey               = "n"
 	daysKey            = "days"
 	strengthKey        = "strength"
+	dateKey            = "date"
+	fromKey            = "from"
+	toKey              = "to"
+	formatKey          = "format"
+	cancelThreshKey    = "cancelthresh"
+	freeCancelsKey     = "freecancels"
+	penaltyThreshKey   = "penaltythreshold"
+	graceLimitKey      = "gracelimit"
+	suspensionKey      = "suspension"
+	banHalfLifeKey     = "banhalflife"
+	redirectKey        = "redirect"
 )
 
 var (
@@ -57,7 +68,7 @@ var (
 
 // SvrCore is satisfied by server/dex.DEX.
 type SvrCore interface {
-	AccountInfo(acctID account.AccountID) (*db.Account, error)
+	AccountInfo(acctID account.AccountID) (*auth.AccountInfo, error)
 	UserMatchFails(aid account.AccountID, n int) ([]*auth.MatchFail, error)
 	Notify(acctID account.AccountID, msg *msgjson.Message)
 	NotifyAll(msg *msgjson.Message)
@@ -70,13 +81,30 @@ type SvrCore interface {
 	MarketStatuses() map[string]*market.Status
 	SuspendMarket(name string, tSusp time.Time, persistBooks bool) (*market.SuspendEpoch, error)
 	ResumeMarket(name string, asSoonAs time.Time) (startEpoch int64, startTime time.Time, err error)
+	SetMarketSwapConf(name string, assetID, swapConf uint32) error
 	ForgiveMatchFail(aid account.AccountID, mid order.MatchID) (forgiven, unbanned bool, err error)
 	AccountMatchOutcomesN(user account.AccountID, n int) ([]*auth.MatchOutcome, error)
+	AccountReputation(aid account.AccountID) (*msgjson.ReputationReport, error)
+	BanScore(aid account.AccountID) (*auth.BanScoreInfo, error)
+	ClearBanScore(aid account.AccountID) error
 	BookOrders(base, quote uint32) (orders []*order.LimitOrder, err error)
 	EpochOrders(base, quote uint32) (orders []order.Order, err error)
 	MarketMatchesStreaming(base, quote uint32, includeInactive bool, N int64, f func(*dexsrv.MatchData) error) (int, error)
 	EnableDataAPI(yes bool)
+	MarketTradingFees(base, quote uint32) (map[uint32]uint64, error)
+	AccountTradingFees(aid account.AccountID) (map[uint32]uint64, error)
 	CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]byte, error)
+	DailyReportDates() ([]string, error)
+	DailyReport(date string) (*dexsrv.DailyReport, error)
+	ReloadTLS() error
+	Drain(redirectAddr string) *comms.DrainStatus
+	StopDraining() *comms.DrainStatus
+	DrainStatus() *comms.DrainStatus
+	PruneArchive(retention time.Duration) (*dexsrv.PruneReport, error)
+	AuthThresholds() *auth.Thresholds
+	SetAuthThresholds(t *auth.Thresholds) error
+	MatchHistory(filter *dexsrv.HistoryFilter, f func(*dexsrv.MatchData) error) (int, error)
+	OrderHistory(filter *dexsrv.HistoryFilter, f func(order.Order, order.OrderStatus) error) (int, error)
 }
 
 // Server is a multi-client https server.
@@ -84,6 +112,7 @@ type Server struct {
 	core      SvrCore
 	addr      string
 	tlsConfig *tls.Config
+	keyPair   *comms.TLSKeyPair // nil if TLS is disabled
 	srv       *http.Server
 	authSHA   [32]byte
 }
@@ -108,18 +137,17 @@ func NewServer(cfg *SrvConfig) (*Server, error) {
 		return nil, fmt.Errorf("missing certificates")
 	}
 
+	var keyPair *comms.TLSKeyPair
 	var tlsConfig *tls.Config
 	if !cfg.NoTLS {
-		keypair, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+		var err error
+		keyPair, err = comms.NewTLSKeyPair(cfg.Cert, cfg.Key)
 		if err != nil {
 			return nil, err
 		}
 
 		// Prepare the TLS configuration.
-		tlsConfig = &tls.Config{
-			Certificates: []tls.Certificate{keypair},
-			MinVersion:   tls.VersionTLS12,
-		}
+		tlsConfig = keyPair.TLSConfig()
 	}
 
 	// Create an HTTP router.
@@ -136,6 +164,7 @@ func NewServer(cfg *SrvConfig) (*Server, error) {
 		srv:       httpServer,
 		addr:      cfg.Addr,
 		tlsConfig: tlsConfig,
+		keyPair:   keyPair,
 		authSHA:   cfg.AuthSHA,
 	}
 
@@ -154,8 +183,12 @@ func NewServer(cfg *SrvConfig) (*Server, error) {
 		r.Route("/account/{"+accountIDKey+"}", func(rm chi.Router) {
 			rm.Get("/", s.apiAccountInfo)
 			rm.Get("/outcomes", s.apiMatchOutcomes)
+			rm.Get("/reputation", s.apiAccountReputation)
 			rm.Get("/fails", s.apiMatchFails)
+			rm.Get("/fees", s.apiAccountTradingFees)
 			rm.Get("/forgive_match/{"+matchIDKey+"}", s.apiForgiveMatchFail)
+			rm.Get("/banscore", s.apiBanScore)
+			rm.Get("/banscore/clear", s.apiClearBanScore)
 			rm.Post("/notify", s.apiNotify)
 		})
 		r.Route("/asset/{"+assetSymbol+"}", func(rm chi.Router) {
@@ -169,10 +202,29 @@ func NewServer(cfg *SrvConfig) (*Server, error) {
 			rm.Get("/orderbook", s.apiMarketOrderBook)
 			rm.Get("/epochorders", s.apiMarketEpochOrders)
 			rm.Get("/matches", s.apiMarketMatches)
+			rm.Get("/fees", s.apiMarketTradingFees)
 			rm.Get("/suspend", s.apiSuspend)
 			rm.Get("/resume", s.apiResume)
+			rm.Get("/swapconf", s.apiSetMarketSwapConf)
 		})
 		r.Get("/prepaybonds", s.prepayBonds)
+		r.Get("/reports", s.apiDailyReports)
+		r.Get("/report/{"+dateKey+"}", s.apiDailyReport)
+		r.Get("/tls/reload", s.apiReloadTLS)
+		r.Get("/prune", s.apiPrune)
+		r.Route("/drain", func(rm chi.Router) {
+			rm.Get("/", s.apiDrainStatus)
+			rm.Get("/start", s.apiDrain)
+			rm.Get("/stop", s.apiStopDraining)
+		})
+		r.Route("/thresholds", func(rm chi.Router) {
+			rm.Get("/", s.apiThresholds)
+			rm.Get("/set", s.apiSetThresholds)
+		})
+		r.Route("/export", func(rm chi.Router) {
+			rm.Get("/matches", s.apiExportMatches)
+			rm.Get("/orders", s.apiExportOrders)
+		})
 	})
 
 	return s, nil
@@ -215,6 +267,25 @@ func (s *Server) Run(ctx context.Context) {
 	log.Infof("admin server off")
 }
 
+// ReloadTLS reloads the TLS certificates of both the admin server and the DEX
+// comms server from disk. Connected clients are not disconnected. An error is
+// returned if either reload fails, in which case that server continues to use
+// its previous certificate.
+func (s *Server) ReloadTLS() error {
+	var errs []error
+	if s.keyPair != nil {
+		if err := s.keyPair.Reload(); err != nil {
+			errs = append(errs, fmt.Errorf("admin server: %w", err))
+		} else {
+			log.Infof("Reloaded admin server TLS certificate")
+		}
+	}
+	if err := s.core.ReloadTLS(); err != nil {
+		errs = append(errs, fmt.Errorf("comms server: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
 // oneTimeConnection sets fields in the header and request that indicate this
 // connection should not be reused.
 func oneTimeConnection(next http.Handler) http.Handler {
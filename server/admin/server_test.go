@@ -14,9 +14,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,6 +31,7 @@ import (
 	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/server/asset"
 	"decred.org/dcrdex/server/auth"
+	"decred.org/dcrdex/server/comms"
 	"decred.org/dcrdex/server/db"
 	dexsrv "decred.org/dcrdex/server/dex"
 	"decred.org/dcrdex/server/market"
@@ -51,13 +54,15 @@ type TMarket struct {
 	resumeEpoch int64
 	resumeTime  time.Time
 	persist     bool
+	swapConfs   map[uint32]uint32
+	base, quote uint32
 }
 
 type TCore struct {
 	markets          map[string]*TMarket
 	accounts         []*db.Account
 	accountsErr      error
-	account          *db.Account
+	account          *auth.AccountInfo
 	accountErr       error
 	penalizeErr      error
 	unbanErr         error
@@ -68,6 +73,26 @@ type TCore struct {
 	marketMatches    []*dexsrv.MatchData
 	marketMatchesErr error
 	dataEnabled      uint32
+	tradingFees      map[uint32]uint64
+	tradingFeesErr   error
+	reports          map[string]*dexsrv.DailyReport
+	reportsErr       error
+	reloadTLSErr     error
+	tlsReloads       int
+	pruneRetention   time.Duration
+	pruneErr         error
+	historyMatches   []*dexsrv.MatchData
+	historyOrders    []order.Order
+	historyErr       error
+	historyFilters   []*dexsrv.HistoryFilter
+	thresholds       auth.Thresholds
+	setThreshErr     error
+	reputation       *msgjson.ReputationReport
+	reputationErr    error
+	banScore         *auth.BanScoreInfo
+	banScoreErr      error
+	clearBanErr      error
+	drain            comms.DrainStatus
 }
 
 func (c *TCore) ConfigMsg() json.RawMessage { return nil }
@@ -85,6 +110,17 @@ func (c *TCore) ResumeMarket(name string, tRes time.Time) (startEpoch int64, sta
 	tMkt.resumeTime = time.UnixMilli(tMkt.resumeEpoch * int64(tMkt.dur))
 	return tMkt.resumeEpoch, tMkt.resumeTime, nil
 }
+func (c *TCore) SetMarketSwapConf(name string, assetID, swapConf uint32) error {
+	tMkt := c.markets[name]
+	if tMkt == nil {
+		return fmt.Errorf("unknown market %s", name)
+	}
+	if tMkt.swapConfs == nil {
+		tMkt.swapConfs = make(map[uint32]uint32)
+	}
+	tMkt.swapConfs[assetID] = swapConf
+	return nil
+}
 func (c *TCore) SuspendMarket(name string, tSusp time.Time, persistBooks bool) (suspEpoch *market.SuspendEpoch, err error) {
 	tMkt := c.markets[name]
 	if tMkt == nil {
@@ -120,6 +156,8 @@ func (c *TCore) MarketStatus(mktName string) *market.Status {
 		StartEpoch:    mkt.startEpoch,
 		SuspendEpoch:  suspendEpoch,
 		PersistBook:   mkt.persist,
+		Base:          mkt.base,
+		Quote:         mkt.quote,
 	}
 }
 
@@ -147,6 +185,92 @@ func (c *TCore) MarketMatchesStreaming(base, quote uint32, includeInactive bool,
 	return len(c.marketMatches), nil
 }
 
+func (c *TCore) MarketTradingFees(base, quote uint32) (map[uint32]uint64, error) {
+	return c.tradingFees, c.tradingFeesErr
+}
+
+func (c *TCore) AccountTradingFees(aid account.AccountID) (map[uint32]uint64, error) {
+	return c.tradingFees, c.tradingFeesErr
+}
+
+func (c *TCore) DailyReportDates() ([]string, error) {
+	if c.reportsErr != nil {
+		return nil, c.reportsErr
+	}
+	dates := make([]string, 0, len(c.reports))
+	for date := range c.reports {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+func (c *TCore) DailyReport(date string) (*dexsrv.DailyReport, error) {
+	if c.reportsErr != nil {
+		return nil, c.reportsErr
+	}
+	report, found := c.reports[date]
+	if !found {
+		return nil, fmt.Errorf("no report for %s", date)
+	}
+	return report, nil
+}
+
+func (c *TCore) ReloadTLS() error {
+	if c.reloadTLSErr != nil {
+		return c.reloadTLSErr
+	}
+	c.tlsReloads++
+	return nil
+}
+
+func (c *TCore) PruneArchive(retention time.Duration) (*dexsrv.PruneReport, error) {
+	if c.pruneErr != nil {
+		return nil, c.pruneErr
+	}
+	c.pruneRetention = retention
+	return &dexsrv.PruneReport{Markets: map[string]*db.PruneResult{"dcr_btc": {Matches: 2}}}, nil
+}
+
+func (c *TCore) AuthThresholds() *auth.Thresholds {
+	t := c.thresholds
+	return &t
+}
+
+func (c *TCore) SetAuthThresholds(t *auth.Thresholds) error {
+	if c.setThreshErr != nil {
+		return c.setThreshErr
+	}
+	c.thresholds = *t
+	return nil
+}
+
+func (c *TCore) MatchHistory(filter *dexsrv.HistoryFilter, f func(*dexsrv.MatchData) error) (int, error) {
+	c.historyFilters = append(c.historyFilters, filter)
+	if c.historyErr != nil {
+		return 0, c.historyErr
+	}
+	for i, md := range c.historyMatches {
+		if err := f(md); err != nil {
+			return i, err
+		}
+	}
+	return len(c.historyMatches), nil
+}
+
+func (c *TCore) OrderHistory(filter *dexsrv.HistoryFilter, f func(order.Order, order.OrderStatus) error) (int, error) {
+	c.historyFilters = append(c.historyFilters, filter)
+	if c.historyErr != nil {
+		return 0, c.historyErr
+	}
+	for i, ord := range c.historyOrders {
+		if err := f(ord, order.OrderStatusExecuted); err != nil {
+			return i, err
+		}
+	}
+	return len(c.historyOrders), nil
+}
+
 func (c *TCore) MarketStatuses() map[string]*market.Status {
 	mktStatuses := make(map[string]*market.Status, len(c.markets))
 	for name, mkt := range c.markets {
@@ -161,6 +285,8 @@ func (c *TCore) MarketStatuses() map[string]*market.Status {
 			StartEpoch:    mkt.startEpoch,
 			SuspendEpoch:  suspendEpoch,
 			PersistBook:   mkt.persist,
+			Base:          mkt.base,
+			Quote:         mkt.quote,
 		}
 	}
 	return mktStatuses
@@ -199,7 +325,7 @@ func (w *tResponseWriter) WriteHeader(statusCode int) {
 }
 
 func (c *TCore) Accounts() ([]*db.Account, error) { return c.accounts, c.accountsErr }
-func (c *TCore) AccountInfo(_ account.AccountID) (*db.Account, error) {
+func (c *TCore) AccountInfo(_ account.AccountID) (*auth.AccountInfo, error) {
 	return c.account, c.accountErr
 }
 func (c *TCore) UserMatchFails(aid account.AccountID, n int) ([]*auth.MatchFail, error) {
@@ -220,8 +346,35 @@ func (c *TCore) CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]b
 func (c *TCore) AccountMatchOutcomesN(user account.AccountID, n int) ([]*auth.MatchOutcome, error) {
 	return nil, nil
 }
+func (c *TCore) AccountReputation(aid account.AccountID) (*msgjson.ReputationReport, error) {
+	return c.reputation, c.reputationErr
+}
+func (c *TCore) BanScore(aid account.AccountID) (*auth.BanScoreInfo, error) {
+	return c.banScore, c.banScoreErr
+}
+func (c *TCore) ClearBanScore(aid account.AccountID) error {
+	if c.clearBanErr != nil {
+		return c.clearBanErr
+	}
+	c.banScore = &auth.BanScoreInfo{Standing: account.StandingGood.String()}
+	return nil
+}
 func (c *TCore) Notify(_ account.AccountID, _ *msgjson.Message) {}
 func (c *TCore) NotifyAll(_ *msgjson.Message)                   {}
+func (c *TCore) Drain(redirectAddr string) *comms.DrainStatus {
+	c.drain.Draining = true
+	c.drain.RedirectAddr = redirectAddr
+	return c.DrainStatus()
+}
+func (c *TCore) StopDraining() *comms.DrainStatus {
+	c.drain.Draining = false
+	c.drain.RedirectAddr = ""
+	return c.DrainStatus()
+}
+func (c *TCore) DrainStatus() *comms.DrainStatus {
+	status := c.drain
+	return &status
+}
 
 // genCertPair generates a key/cert pair to the paths provided.
 func genCertPair(certFile, keyFile string) error {
@@ -742,6 +895,452 @@ func TestMarketMatches(t *testing.T) {
 	}
 }
 
+func TestTradingFees(t *testing.T) {
+	core := new(TCore)
+	core.markets = map[string]*TMarket{"dcr_btc": {}}
+	srv := &Server{
+		core: core,
+	}
+	mux := chi.NewRouter()
+	mux.Get("/market/{"+marketNameKey+"}/fees", srv.apiMarketTradingFees)
+	mux.Get("/account/{"+accountIDKey+"}/fees", srv.apiAccountTradingFees)
+
+	const acctIDStr = "0a9912205b2cbab0c25c2de30bda9074de0ae23b065489a99199bad763f102cc"
+	tests := []struct {
+		name, path     string
+		tradingFeesErr error
+		wantCode       int
+	}{{
+		name:     "ok market",
+		path:     "/market/dcr_btc/fees",
+		wantCode: http.StatusOK,
+	}, {
+		name:     "unknown market",
+		path:     "/market/btc_dcr/fees",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:           "core.MarketTradingFees error",
+		path:           "/market/dcr_btc/fees",
+		tradingFeesErr: errors.New("boom"),
+		wantCode:       http.StatusInternalServerError,
+	}, {
+		name:     "ok account",
+		path:     "/account/" + acctIDStr + "/fees",
+		wantCode: http.StatusOK,
+	}, {
+		name:     "bad account",
+		path:     "/account/abc/fees",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:           "core.AccountTradingFees error",
+		path:           "/account/" + acctIDStr + "/fees",
+		tradingFeesErr: errors.New("boom"),
+		wantCode:       http.StatusInternalServerError,
+	}}
+	for _, test := range tests {
+		core.tradingFees = map[uint32]uint64{42: 1000, 0: 20}
+		core.tradingFeesErr = test.tradingFeesErr
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost"+test.path, nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		var fees []*TradingFeeTotal
+		if err := json.Unmarshal(w.Body.Bytes(), &fees); err != nil {
+			t.Fatalf("%q: unable to decode response: %v", test.name, err)
+		}
+		if len(fees) != 2 || fees[0].AssetID != 0 || fees[1].Symbol != "dcr" || fees[1].Amount != 1000 {
+			t.Fatalf("%q: wrong fees %+v", test.name, fees)
+		}
+	}
+}
+
+func TestDailyReports(t *testing.T) {
+	const date = "2024-03-01"
+	core := &TCore{
+		reports: map[string]*dexsrv.DailyReport{
+			date: {
+				Date:             date,
+				NewRegistrations: 3,
+				Markets: []*dexsrv.MarketReport{{
+					Market:      "dcr_btc",
+					Matches:     4,
+					FailedSwaps: 1,
+				}},
+			},
+		},
+	}
+	srv := &Server{
+		core: core,
+	}
+	mux := chi.NewRouter()
+	mux.Get("/reports", srv.apiDailyReports)
+	mux.Get("/report/{"+dateKey+"}", srv.apiDailyReport)
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	tests := []struct {
+		name, path string
+		reportsErr error
+		wantCode   int
+	}{{
+		name:     "ok list",
+		path:     "/reports",
+		wantCode: http.StatusOK,
+	}, {
+		name:       "core.DailyReportDates error",
+		path:       "/reports",
+		reportsErr: errors.New("boom"),
+		wantCode:   http.StatusInternalServerError,
+	}, {
+		name:     "ok report",
+		path:     "/report/" + date,
+		wantCode: http.StatusOK,
+	}, {
+		name:     "bad date",
+		path:     "/report/03-01-2024",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "day not ended",
+		path:     "/report/" + tomorrow,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:       "core.DailyReport error",
+		path:       "/report/" + date,
+		reportsErr: errors.New("boom"),
+		wantCode:   http.StatusInternalServerError,
+	}}
+	for _, test := range tests {
+		core.reportsErr = test.reportsErr
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost"+test.path, nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		if test.path == "/reports" {
+			var dates []string
+			if err := json.Unmarshal(w.Body.Bytes(), &dates); err != nil {
+				t.Fatalf("%q: unable to decode response: %v", test.name, err)
+			}
+			if len(dates) != 1 || dates[0] != date {
+				t.Fatalf("%q: wrong dates %v", test.name, dates)
+			}
+			continue
+		}
+		report := new(dexsrv.DailyReport)
+		if err := json.Unmarshal(w.Body.Bytes(), report); err != nil {
+			t.Fatalf("%q: unable to decode response: %v", test.name, err)
+		}
+		if report.Date != date || report.NewRegistrations != 3 || len(report.Markets) != 1 || report.Markets[0].FailedSwaps != 1 {
+			t.Fatalf("%q: wrong report %+v", test.name, report)
+		}
+	}
+}
+
+func TestReloadTLS(t *testing.T) {
+	core := new(TCore)
+	srv := &Server{
+		core: core,
+	}
+	mux := chi.NewRouter()
+	mux.Get("/tls/reload", srv.apiReloadTLS)
+
+	tests := []struct {
+		name      string
+		reloadErr error
+		wantCode  int
+	}{{
+		name:     "ok",
+		wantCode: http.StatusOK,
+	}, {
+		name:      "core.ReloadTLS error",
+		reloadErr: errors.New("boom"),
+		wantCode:  http.StatusInternalServerError,
+	}}
+	for _, test := range tests {
+		core.reloadTLSErr = test.reloadErr
+		core.tlsReloads = 0
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost/tls/reload", nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code == http.StatusOK && core.tlsReloads != 1 {
+			t.Fatalf("%q: expected 1 comms reload, got %d", test.name, core.tlsReloads)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	core := new(TCore)
+	srv := &Server{
+		core: core,
+	}
+	mux := chi.NewRouter()
+	mux.Get("/prune", srv.apiPrune)
+
+	tests := []struct {
+		name          string
+		query         string
+		pruneErr      error
+		wantCode      int
+		wantRetention time.Duration
+	}{{
+		name:     "ok configured retention",
+		wantCode: http.StatusOK,
+	}, {
+		name:          "ok days",
+		query:         "?days=90",
+		wantCode:      http.StatusOK,
+		wantRetention: 90 * 24 * time.Hour,
+	}, {
+		name:     "zero days",
+		query:    "?days=0",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad days",
+		query:    "?days=-1",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "core.PruneArchive error",
+		pruneErr: errors.New("boom"),
+		wantCode: http.StatusInternalServerError,
+	}}
+	for _, test := range tests {
+		core.pruneErr = test.pruneErr
+		core.pruneRetention = -1
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost/prune"+test.query, nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		if core.pruneRetention != test.wantRetention {
+			t.Fatalf("%q: wrong retention %v", test.name, core.pruneRetention)
+		}
+		report := new(dexsrv.PruneReport)
+		if err := json.Unmarshal(w.Body.Bytes(), report); err != nil {
+			t.Fatalf("%q: unable to decode response: %v", test.name, err)
+		}
+		if report.Markets["dcr_btc"] == nil || report.Markets["dcr_btc"].Matches != 2 {
+			t.Fatalf("%q: wrong report %+v", test.name, report)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	core := &TCore{
+		markets: map[string]*TMarket{
+			"dcr_btc": {base: 42, quote: 0},
+			"btc_ltc": {base: 0, quote: 2},
+		},
+	}
+	srv := &Server{
+		core: core,
+	}
+	mux := chi.NewRouter()
+	mux.Get("/export/matches", srv.apiExportMatches)
+	mux.Get("/export/orders", srv.apiExportOrders)
+
+	acctID := "0a9912205b2cbab0c25c2de30bda9074de0ae23b065489a99199bad763f102cc"
+	matches := []*dexsrv.MatchData{{
+		MatchData: db.MatchData{
+			Epoch:    order.EpochID{Idx: 1000, Dur: 10_000},
+			Quantity: 1e8,
+			Rate:     2e6,
+			Status:   order.MatchComplete,
+		},
+		MakerSwap: "swap",
+	}, {
+		MatchData: db.MatchData{
+			Epoch:  order.EpochID{Idx: 1001, Dur: 10_000},
+			Active: true,
+		},
+	}}
+	lo := &order.LimitOrder{
+		P: order.Prefix{
+			OrderType:  order.LimitOrderType,
+			ClientTime: time.UnixMilli(1566497653000),
+			ServerTime: time.UnixMilli(1566497656000),
+		},
+		T: order.Trade{
+			Sell:     true,
+			Quantity: 2e8,
+			FillAmt:  1e8,
+		},
+		Rate:  2e6,
+		Force: order.StandingTiF,
+	}
+	co := &order.CancelOrder{
+		P: order.Prefix{
+			OrderType:  order.CancelOrderType,
+			ServerTime: time.UnixMilli(1566497657000),
+		},
+		TargetOrderID: lo.ID(),
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		historyErr error
+		wantCode   int
+		wantRows   int
+		wantCSV    bool
+		wantMkts   []string
+	}{{
+		name:     "ok matches jsonl",
+		path:     "/export/matches?market=dcr_btc&from=1",
+		wantCode: http.StatusOK,
+		wantRows: 2,
+		wantMkts: []string{"dcr_btc"},
+	}, {
+		name:     "ok matches csv",
+		path:     "/export/matches?market=dcr_btc&format=csv",
+		wantCode: http.StatusOK,
+		wantRows: 3,
+		wantCSV:  true,
+		wantMkts: []string{"dcr_btc"},
+	}, {
+		name:     "ok orders csv",
+		path:     "/export/orders?market=DCR_BTC&account=" + acctID + "&format=csv",
+		wantCode: http.StatusOK,
+		wantRows: 3,
+		wantCSV:  true,
+		wantMkts: []string{"dcr_btc"},
+	}, {
+		name:     "ok account all markets",
+		path:     "/export/orders?account=" + acctID,
+		wantCode: http.StatusOK,
+		wantRows: 4,
+		wantMkts: []string{"btc_ltc", "dcr_btc"},
+	}, {
+		name:     "no market or account",
+		path:     "/export/matches",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "unknown market",
+		path:     "/export/matches?market=btc_dcr",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad account",
+		path:     "/export/orders?account=abc",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad format",
+		path:     "/export/matches?market=dcr_btc&format=xml",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad from",
+		path:     "/export/matches?market=dcr_btc&from=yesterday",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "to before from",
+		path:     "/export/matches?market=dcr_btc&from=1000&to=1000",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:       "core.MatchHistory error",
+		path:       "/export/matches?market=dcr_btc",
+		historyErr: errors.New("boom"),
+		wantCode:   http.StatusInternalServerError,
+	}}
+	for _, test := range tests {
+		core.historyMatches = matches
+		core.historyOrders = []order.Order{lo, co}
+		core.historyErr = test.historyErr
+		core.historyFilters = nil
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost"+test.path, nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		if len(core.historyFilters) != len(test.wantMkts) {
+			t.Fatalf("%q: wanted %d markets, got %d", test.name, len(test.wantMkts), len(core.historyFilters))
+		}
+		for i, mkt := range test.wantMkts {
+			filter := core.historyFilters[i]
+			if filter.Base != core.markets[mkt].base || filter.Quote != core.markets[mkt].quote {
+				t.Fatalf("%q: wrong market for filter %d", test.name, i)
+			}
+			if !filter.End.After(filter.Start) {
+				t.Fatalf("%q: bad time range %v - %v", test.name, filter.Start, filter.End)
+			}
+		}
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if len(lines) != test.wantRows {
+			t.Fatalf("%q: wanted %d rows, got %d", test.name, test.wantRows, len(lines))
+		}
+		if test.wantCSV {
+			if !strings.HasPrefix(lines[0], "market,") {
+				t.Fatalf("%q: no csv header: %s", test.name, lines[0])
+			}
+			continue
+		}
+		for _, line := range lines {
+			var rec struct {
+				Market string `json:"market"`
+			}
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatalf("%q: unable to decode line %q: %v", test.name, line, err)
+			}
+			if rec.Market == "" {
+				t.Fatalf("%q: no market in %q", test.name, line)
+			}
+		}
+	}
+
+	// Check the fields of the exported orders.
+	core.historyErr = nil
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "https://localhost/export/orders?market=dcr_btc", nil)
+	r.RemoteAddr = "localhost"
+	mux.ServeHTTP(w, r)
+	dec := json.NewDecoder(w.Body)
+	var hlo, hco HistoryOrder
+	if err := dec.Decode(&hlo); err != nil {
+		t.Fatalf("error decoding limit order: %v", err)
+	}
+	if err := dec.Decode(&hco); err != nil {
+		t.Fatalf("error decoding cancel order: %v", err)
+	}
+	if hlo.ID != lo.ID().String() || hlo.Type != "limit" || !hlo.Sell || hlo.Rate != lo.Rate ||
+		hlo.Filled != 1e8 || hlo.ServerTime != 1566497656000 || hlo.TimeInForce != "standing" {
+		t.Fatalf("wrong limit order %+v", hlo)
+	}
+	if hco.Type != "cancel" || hco.TargetOrder != lo.ID().String() {
+		t.Fatalf("wrong cancel order %+v", hco)
+	}
+}
+
 func TestResume(t *testing.T) {
 	core := &TCore{
 		markets: make(map[string]*TMarket),
@@ -1037,6 +1636,79 @@ func TestSuspend(t *testing.T) {
 	}
 }
 
+func TestSetMarketSwapConf(t *testing.T) {
+	core := &TCore{
+		markets: make(map[string]*TMarket),
+	}
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Get("/market/{"+marketNameKey+"}/swapconf", srv.apiSetMarketSwapConf)
+
+	name := "dcr_btc"
+	tMkt := &TMarket{running: true, dur: 6000}
+
+	tests := []struct {
+		name, query string
+		mkt         *TMarket
+		wantCode    int
+	}{{
+		name:     "unknown market",
+		query:    "asset=btc&confs=3",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "ok",
+		query:    "asset=btc&confs=3",
+		mkt:      tMkt,
+		wantCode: http.StatusOK,
+	}, {
+		name:     "unknown asset",
+		query:    "asset=qwe&confs=3",
+		mkt:      tMkt,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad confs",
+		query:    "asset=btc&confs=-1",
+		mkt:      tMkt,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "missing confs",
+		query:    "asset=btc",
+		mkt:      tMkt,
+		wantCode: http.StatusBadRequest,
+	}}
+	for _, test := range tests {
+		delete(core.markets, name)
+		if test.mkt != nil {
+			core.markets[name] = test.mkt
+		}
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost/market/"+name+"/swapconf?"+test.query, nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: apiSetMarketSwapConf returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		res := new(SwapConfResult)
+		if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+			t.Fatalf("%q: failed to unmarshal result: %v", test.name, err)
+		}
+		if res.Market != name || res.Asset != "btc" || res.SwapConf != 3 {
+			t.Fatalf("%q: wrong result %+v", test.name, res)
+		}
+		if tMkt.swapConfs[0] != 3 {
+			t.Fatalf("%q: swapConf not set", test.name)
+		}
+	}
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	pass := "password123"
 	authSHA := sha256.Sum256([]byte(pass))
@@ -1135,9 +1807,24 @@ func TestAccountInfo(t *testing.T) {
 	}
 
 	// An account.
-	core.account = &db.Account{
+	core.account = &auth.AccountInfo{
 		AccountID: accountID,
 		Pubkey:    dex.Bytes(pubkey),
+		Connected: true,
+		Bonds: []*auth.BondInfo{{
+			AssetID:  42,
+			CoinID:   "e94d1fbd8d1df8d1d2b1a4a7d4c7f2d5c1a8e2b0f6d3c9a5b7e4f1d2c3b4a5e6:0",
+			Amount:   1e8,
+			Strength: 2,
+			LockTime: 1700000000,
+			Expiry:   1699300000,
+		}},
+		Reputation: &account.Reputation{
+			BondedTier: 2,
+			Penalties:  1,
+			Score:      -20,
+		},
+		Tier: 1,
 	}
 
 	w = httptest.NewRecorder()
@@ -1151,8 +1838,26 @@ func TestAccountInfo(t *testing.T) {
 	}
 
 	exp := `{
-    "accountid": "0a9912205b2cbab0c25c2de30bda9074de0ae23b065489a99199bad763f102cc",
-    "pubkey": "0204988a498d5d19514b217e872b4dbd1cf071d365c4879e64ed5919881c97eb19"
+    "accountID": "0a9912205b2cbab0c25c2de30bda9074de0ae23b065489a99199bad763f102cc",
+    "pubkey": "0204988a498d5d19514b217e872b4dbd1cf071d365c4879e64ed5919881c97eb19",
+    "connected": true,
+    "bonds": [
+        {
+            "version": 0,
+            "assetID": 42,
+            "coinID": "e94d1fbd8d1df8d1d2b1a4a7d4c7f2d5c1a8e2b0f6d3c9a5b7e4f1d2c3b4a5e6:0",
+            "amount": 100000000,
+            "strength": 2,
+            "lockTime": 1700000000,
+            "expiry": 1699300000
+        }
+    ],
+    "reputation": {
+        "bondedTier": 2,
+        "penalties": 1,
+        "score": -20
+    },
+    "tier": 1
 }
 `
 	if exp != w.Body.String() {
@@ -1374,3 +2079,271 @@ func TestEnableDataAPI(t *testing.T) {
 	}
 
 }
+
+func TestSetThresholds(t *testing.T) {
+	core := new(TCore)
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Get("/thresholds/set", srv.apiSetThresholds)
+
+	origThresholds := func() auth.Thresholds {
+		grace := uint32(5)
+		return auth.Thresholds{CancelThreshold: 0.9, PenaltyThreshold: 20, GraceLimit: &grace,
+			SuspensionPeriod: 24 * time.Hour, BanScoreHalfLife: 7 * 24 * time.Hour}
+	}
+
+	tests := []struct {
+		name, query string
+		setErr      error
+		wantCode    int
+		want        auth.Thresholds
+		wantGrace   *uint32
+	}{{
+		name:     "ok",
+		query:    "cancelthresh=0.8&freecancels=true&penaltythreshold=30&gracelimit=3&suspension=2h&banhalflife=72h",
+		wantCode: http.StatusOK,
+		want: auth.Thresholds{CancelThreshold: 0.8, FreeCancels: true, PenaltyThreshold: 30,
+			SuspensionPeriod: 2 * time.Hour, BanScoreHalfLife: 72 * time.Hour},
+		wantGrace: func() *uint32 { g := uint32(3); return &g }(),
+	}, {
+		name:     "partial",
+		query:    "cancelthresh=0.7",
+		wantCode: http.StatusOK,
+		want: auth.Thresholds{CancelThreshold: 0.7, PenaltyThreshold: 20,
+			SuspensionPeriod: 24 * time.Hour, BanScoreHalfLife: 7 * 24 * time.Hour},
+		wantGrace: func() *uint32 { g := uint32(5); return &g }(),
+	}, {
+		name:     "derived grace limit",
+		query:    "gracelimit=derived",
+		wantCode: http.StatusOK,
+		want: auth.Thresholds{CancelThreshold: 0.9, PenaltyThreshold: 20,
+			SuspensionPeriod: 24 * time.Hour, BanScoreHalfLife: 7 * 24 * time.Hour},
+	}, {
+		name:     "bad grace limit",
+		query:    "gracelimit=-1",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad suspension period",
+		query:    "suspension=1d",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "zero ban score half-life",
+		query:    "banhalflife=0s",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "cancel threshold too high",
+		query:    "cancelthresh=1",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad free cancels",
+		query:    "freecancels=maybe",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "zero penalty threshold",
+		query:    "penaltythreshold=0",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "core error",
+		query:    "cancelthresh=0.7",
+		setErr:   errors.New(""),
+		wantCode: http.StatusInternalServerError,
+	}}
+	for _, test := range tests {
+		core.thresholds = origThresholds()
+		core.setThreshErr = test.setErr
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost/thresholds/set?"+test.query, nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%q: apiSetThresholds returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		grace := core.thresholds.GraceLimit
+		if (grace == nil) != (test.wantGrace == nil) || (grace != nil && *grace != *test.wantGrace) {
+			t.Fatalf("%q: wrong grace limit %v", test.name, grace)
+		}
+		core.thresholds.GraceLimit = nil
+		if core.thresholds != test.want {
+			t.Fatalf("%q: wrong thresholds %+v", test.name, core.thresholds)
+		}
+	}
+}
+
+func TestAccountReputation(t *testing.T) {
+	core := new(TCore)
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Route("/account/{"+accountIDKey+"}", func(rm chi.Router) {
+		rm.Get("/reputation", srv.apiAccountReputation)
+	})
+
+	acctIDStr := "0a9912205b2cbab0c25c2de30bda9074de0ae23b065489a99199bad763f102cc"
+	core.reputation = &msgjson.ReputationReport{
+		Reputation:       &account.Reputation{BondedTier: 1, Score: 3},
+		PenaltyThreshold: 20,
+		MaxScore:         60,
+		SwapsCompleted:   4,
+		NoRedeemAsTaker:  1,
+		Orders:           4,
+		Cancels:          1,
+		CancelRatio:      0.25,
+		CancelThreshold:  0.95,
+		GraceLimit:       19,
+	}
+
+	tests := []struct {
+		name     string
+		acctID   string
+		coreErr  error
+		wantCode int
+	}{
+		{"ok", acctIDStr, nil, http.StatusOK},
+		{"bad account ID", acctIDStr[2:], nil, http.StatusBadRequest},
+		{"core error", acctIDStr, errors.New(""), http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		core.reputationErr = test.coreErr
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost/account/"+test.acctID+"/reputation", nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%s: apiAccountReputation returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		report := new(msgjson.ReputationReport)
+		if err := json.Unmarshal(w.Body.Bytes(), report); err != nil {
+			t.Fatalf("%s: error decoding response: %v", test.name, err)
+		}
+		if report.SwapsCompleted != 4 || report.CancelRatio != 0.25 || report.Reputation.Score != 3 {
+			t.Fatalf("%s: wrong report %+v", test.name, report)
+		}
+	}
+}
+
+func TestBanScore(t *testing.T) {
+	core := new(TCore)
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Route("/account/{"+accountIDKey+"}", func(rm chi.Router) {
+		rm.Get("/banscore", srv.apiBanScore)
+		rm.Get("/banscore/clear", srv.apiClearBanScore)
+	})
+
+	acctIDStr := "0a9912205b2cbab0c25c2de30bda9074de0ae23b065489a99199bad763f102cc"
+	suspended := &auth.BanScoreInfo{
+		Score:          62.5,
+		Standing:       account.StandingSuspended.String(),
+		SuspendedUntil: 1700086500000,
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		acctID       string
+		banScoreErr  error
+		clearBanErr  error
+		wantCode     int
+		wantStanding account.Standing
+	}{
+		{"ok", "/banscore", acctIDStr, nil, nil, http.StatusOK, account.StandingSuspended},
+		{"bad account ID", "/banscore", acctIDStr[2:], nil, nil, http.StatusBadRequest, 0},
+		{"core error", "/banscore", acctIDStr, errors.New(""), nil, http.StatusInternalServerError, 0},
+		{"clear", "/banscore/clear", acctIDStr, nil, nil, http.StatusOK, account.StandingGood},
+		{"clear bad account ID", "/banscore/clear", acctIDStr[2:], nil, nil, http.StatusBadRequest, 0},
+		{"clear error", "/banscore/clear", acctIDStr, nil, errors.New(""), http.StatusInternalServerError, 0},
+	}
+	for _, test := range tests {
+		core.banScore = suspended
+		core.banScoreErr = test.banScoreErr
+		core.clearBanErr = test.clearBanErr
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost/account/"+test.acctID+test.path, nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%s: returned code %d, expected %d", test.name, w.Code, test.wantCode)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		info := new(auth.BanScoreInfo)
+		if err := json.Unmarshal(w.Body.Bytes(), info); err != nil {
+			t.Fatalf("%s: error decoding response: %v", test.name, err)
+		}
+		if info.Standing != test.wantStanding.String() {
+			t.Fatalf("%s: wrong standing %q", test.name, info.Standing)
+		}
+	}
+}
+
+func TestDrain(t *testing.T) {
+	core := new(TCore)
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Route("/drain", func(rm chi.Router) {
+		rm.Get("/", srv.apiDrainStatus)
+		rm.Get("/start", srv.apiDrain)
+		rm.Get("/stop", srv.apiStopDraining)
+	})
+
+	const redirect = "wss://dex2.example.com:7232/ws"
+	tests := []struct {
+		name         string
+		path         string
+		wantCode     int
+		wantDraining bool
+		wantRedirect string
+	}{
+		{"status", "/drain", http.StatusOK, false, ""},
+		{"start", "/drain/start?redirect=" + url.QueryEscape(redirect), http.StatusOK, true, redirect},
+		{"status while draining", "/drain", http.StatusOK, true, redirect},
+		{"bad redirect", "/drain/start?redirect=dex2.example.com:7232", http.StatusBadRequest, true, redirect},
+		{"start without redirect", "/drain/start", http.StatusOK, true, ""},
+		{"stop", "/drain/stop", http.StatusOK, false, ""},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "https://localhost"+test.path, nil)
+		r.RemoteAddr = "localhost"
+
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%s: returned code %d", test.name, w.Code)
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		status := new(comms.DrainStatus)
+		if err := json.Unmarshal(w.Body.Bytes(), status); err != nil {
+			t.Fatalf("%s: error decoding response: %v", test.name, err)
+		}
+		if status.Draining != test.wantDraining || status.RedirectAddr != test.wantRedirect {
+			t.Fatalf("%s: wrong status %+v", test.name, status)
+		}
+	}
+}
@@ -36,6 +36,13 @@ type MarketStatus struct {
 	PersistBook   *bool  `json:"persistbook,omitempty"`
 }
 
+// TradingFeeTotal is the total of the trading fees accrued in an asset.
+type TradingFeeTotal struct {
+	AssetID uint32 `json:"assetID"`
+	Symbol  string `json:"symbol"`
+	Amount  uint64 `json:"amount"`
+}
+
 // MatchData describes a match.
 type MatchData struct {
 	ID          string `json:"id"`
@@ -82,6 +89,14 @@ type ResumeResult struct {
 	StartTime  APITime `json:"starttime"`
 }
 
+// SwapConfResult is the result of a market swapConf request. A SwapConf of
+// zero indicates that the asset's configured swapConf applies.
+type SwapConfResult struct {
+	Market   string `json:"market"`
+	Asset    string `json:"asset"`
+	SwapConf uint32 `json:"swapconf"`
+}
+
 // RFC3339Milli is the RFC3339 time formatting with millisecond precision.
 const RFC3339Milli = "2006-01-02T15:04:05.999Z07:00"
 
@@ -113,3 +128,29 @@ type ForgiveResult struct {
 	Unbanned    bool    `json:"unbanned"`
 	ForgiveTime APITime `json:"forgivetime"`
 }
+
+// HistoryMatch is a match in a trade history export.
+type HistoryMatch struct {
+	Market string `json:"market"`
+	// Time is the start of the match's epoch, in unix ms.
+	Time int64 `json:"time"`
+	MatchData
+}
+
+// HistoryOrder is an order in a trade history export. Times are in unix ms.
+type HistoryOrder struct {
+	Market      string `json:"market"`
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Account     string `json:"account"`
+	Sell        bool   `json:"sell"`
+	ClientTime  int64  `json:"clientTime"`
+	ServerTime  int64  `json:"serverTime"`
+	Quantity    uint64 `json:"quantity"`
+	Rate        uint64 `json:"rate"`
+	TimeInForce string `json:"tif,omitempty"`
+	Filled      uint64 `json:"filled"`
+	Address     string `json:"address,omitempty"`
+	TargetOrder string `json:"targetOrder,omitempty"`
+	Status      string `json:"status"`
+}
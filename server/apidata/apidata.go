@@ -13,7 +13,9 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/candles"
 	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/server/db"
 	"decred.org/dcrdex/server/matcher"
 )
 
@@ -29,6 +31,10 @@ type DBSource interface {
 	LoadEpochStats(base, quote uint32, caches []*candles.Cache) error
 	LastCandleEndStamp(base, quote uint32, candleDur uint64) (uint64, error)
 	InsertCandles(base, quote uint32, dur uint64, cs []*candles.Candle) error
+	EpochProof(base, quote uint32, epochIdx, epochDur int64) (*db.EpochProof, error)
+	OrderHistory(filter *db.HistoryFilter, f func(order.Order, order.OrderStatus) error) (int, error)
+	MatchHistory(filter *db.HistoryFilter, f func(*db.MatchDataWithCoins) error) (int, error)
+	CandleHistory(base, quote uint32, candleDur, after, through uint64, limit int) ([]*candles.Candle, error)
 }
 
 // MarketSource is a source of market information. Markets are added after
@@ -76,6 +82,8 @@ func NewDataAPI(dbSrc DBSource, registerHTTP func(route string, handler comms.HT
 		registerHTTP(msgjson.SpotsRoute, s.handleSpots)
 		registerHTTP(msgjson.CandlesRoute, s.handleCandles)
 		registerHTTP(msgjson.OrderBookRoute, s.handleOrderBook)
+		registerHTTP(msgjson.EpochProofRoute, s.handleEpochProof)
+		registerHTTP(msgjson.CandleHistoryRoute, s.handleCandleHistory)
 	}
 	return s
 }
@@ -247,6 +255,86 @@ func (s *DataAPI) handleCandles(thing any) (any, error) {
 	return cache.WireCandles(req.NumCandles), nil
 }
 
+// handleCandleHistory implements comms.HTTPHandler for the /candlehistory
+// endpoint. Unlike handleCandles, which serves from the in-memory caches,
+// candles are read from the DB, so the full stored history of a market can be
+// retrieved one page at a time.
+func (s *DataAPI) handleCandleHistory(thing any) (any, error) {
+	req, ok := thing.(*msgjson.CandleHistoryRequest)
+	if !ok {
+		return nil, fmt.Errorf("candle history request unparseable")
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = candles.CacheSize
+	} else if count < 0 || count > candles.CacheSize {
+		return nil, fmt.Errorf("requested count %d out of range, maximum is %d", count, candles.CacheSize)
+	}
+
+	mkt, err := dex.MarketName(req.BaseID, req.QuoteID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing market for %d - %d", req.BaseID, req.QuoteID)
+	}
+	if _, found := s.epochDurations[mkt]; !found {
+		return nil, fmt.Errorf("market %s not known", mkt)
+	}
+
+	binSizeDuration, err := time.ParseDuration(req.BinSize)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing binSize")
+	}
+	binSize := uint64(binSizeDuration / time.Millisecond)
+	var stored bool
+	for _, sz := range binSizes {
+		if sz == binSize {
+			stored = true
+			break
+		}
+	}
+	if !stored {
+		return nil, fmt.Errorf("no history available for binSize %s", req.BinSize)
+	}
+
+	end := req.End
+	if end == 0 {
+		end = uint64(time.Now().UnixMilli())
+	}
+	if end < req.Start {
+		return nil, fmt.Errorf("end %d is before start %d", end, req.Start)
+	}
+
+	// A candle starts at or after req.Start if it ends after
+	// req.Start + binSize - 1. Request one extra candle to learn whether
+	// there is another page.
+	cs, err := s.db.CandleHistory(req.BaseID, req.QuoteID, binSize, req.Start+binSize-1, end, count+1)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving candles for %s", mkt)
+	}
+	var next uint64
+	if len(cs) > count {
+		cs = cs[:count]
+		next = cs[count-1].EndStamp
+	}
+
+	wc := msgjson.NewWireCandles(len(cs))
+	for _, c := range cs {
+		wc.StartStamps = append(wc.StartStamps, c.StartStamp)
+		wc.EndStamps = append(wc.EndStamps, c.EndStamp)
+		wc.MatchVolumes = append(wc.MatchVolumes, c.MatchVolume)
+		wc.QuoteVolumes = append(wc.QuoteVolumes, c.QuoteVolume)
+		wc.HighRates = append(wc.HighRates, c.HighRate)
+		wc.LowRates = append(wc.LowRates, c.LowRate)
+		wc.StartRates = append(wc.StartRates, c.StartRate)
+		wc.EndRates = append(wc.EndRates, c.EndRate)
+	}
+
+	return &msgjson.CandleHistory{
+		Candles: wc,
+		Next:    next,
+	}, nil
+}
+
 // handleOrderBook implements comms.HTTPHandler for the /orderbook endpoints.
 func (s *DataAPI) handleOrderBook(thing any) (any, error) {
 	req, ok := thing.(*msgjson.OrderBookSubscription)
@@ -261,6 +349,127 @@ func (s *DataAPI) handleOrderBook(thing any) (any, error) {
 	return s.bookSource.Book(mkt)
 }
 
+// handleEpochProof implements comms.HTTPHandler for the epoch_proof route,
+// which is served at /epochproof.
+func (s *DataAPI) handleEpochProof(thing any) (any, error) {
+	req, ok := thing.(*msgjson.EpochProofRequest)
+	if !ok {
+		return nil, fmt.Errorf("unparseable epoch proof request")
+	}
+
+	mkt, err := dex.MarketName(req.BaseID, req.QuoteID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing market for %d - %d", req.BaseID, req.QuoteID)
+	}
+	epochDur, found := s.epochDurations[mkt]
+	if !found {
+		return nil, fmt.Errorf("market %s not known", mkt)
+	}
+
+	proof, err := s.db.EpochProof(req.BaseID, req.QuoteID, int64(req.Epoch), int64(epochDur))
+	if err != nil {
+		if db.IsErrEpochUnknown(err) {
+			return nil, fmt.Errorf("no match proof for epoch %d", req.Epoch)
+		}
+		return nil, fmt.Errorf("error retrieving match proof for epoch %d", req.Epoch)
+	}
+	if len(proof.Preimages) != len(proof.OrdersRevealed) || len(proof.MissedCommits) != len(proof.OrdersMissed) {
+		// Epochs processed before the proof material was stored.
+		return nil, fmt.Errorf("match proof for epoch %d is not available", req.Epoch)
+	}
+
+	// The orders of the epoch queue were received during the epoch, and the
+	// matches are stored with the epoch in which they were made.
+	filter := &db.HistoryFilter{
+		Base:  req.BaseID,
+		Quote: req.QuoteID,
+		Start: time.UnixMilli(proof.Idx * proof.Dur),
+		End:   time.UnixMilli((proof.Idx + 1) * proof.Dur),
+	}
+	ords := make(map[order.OrderID]order.Order, len(proof.OrdersRevealed)+len(proof.OrdersMissed))
+	_, err = s.db.OrderHistory(filter, func(ord order.Order, _ order.OrderStatus) error {
+		ords[ord.ID()] = ord
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving orders for epoch %d", req.Epoch)
+	}
+	matches := make([]*msgjson.EpochProofMatch, 0)
+	_, err = s.db.MatchHistory(filter, func(md *db.MatchDataWithCoins) error {
+		matches = append(matches, &msgjson.EpochProofMatch{
+			MatchID:  md.ID[:],
+			MakerOID: md.Maker[:],
+			TakerOID: md.Taker[:],
+			Quantity: md.Quantity,
+			Rate:     md.Rate,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving matches for epoch %d", req.Epoch)
+	}
+
+	queue := make([]*msgjson.EpochProofOrder, 0, len(proof.OrdersRevealed))
+	for i, oid := range proof.OrdersRevealed {
+		pi := proof.Preimages[i]
+		commit := pi.Commit()
+		po := &msgjson.EpochProofOrder{
+			OrderID:  oid.Bytes(),
+			Commit:   commit[:],
+			Preimage: pi[:],
+		}
+		setEpochProofOrderDetails(po, ords[oid])
+		queue = append(queue, po)
+	}
+	misses := make([]*msgjson.EpochProofOrder, 0, len(proof.OrdersMissed))
+	for i, oid := range proof.OrdersMissed {
+		po := &msgjson.EpochProofOrder{
+			OrderID: oid.Bytes(),
+			Commit:  proof.MissedCommits[i][:],
+		}
+		setEpochProofOrderDetails(po, ords[oid])
+		misses = append(misses, po)
+	}
+
+	return &msgjson.EpochProof{
+		MarketID:  mkt,
+		Epoch:     req.Epoch,
+		EpochDur:  epochDur,
+		MatchTime: uint64(proof.MatchTime),
+		CSum:      proof.CSum,
+		Seed:      proof.Seed,
+		Queue:     queue,
+		Misses:    misses,
+		Matches:   matches,
+	}, nil
+}
+
+// setEpochProofOrderDetails sets the type, side, quantity, rate, worst rate,
+// and cancel target of the order. The details are left zero if ord is nil.
+func setEpochProofOrderDetails(po *msgjson.EpochProofOrder, ord order.Order) {
+	side := func(sell bool) uint8 {
+		if sell {
+			return msgjson.SellOrderNum
+		}
+		return msgjson.BuyOrderNum
+	}
+	switch o := ord.(type) {
+	case *order.LimitOrder:
+		po.OrderType = msgjson.LimitOrderNum
+		po.Side = side(o.Sell)
+		po.Quantity = o.Quantity
+		po.Rate = o.Rate
+	case *order.MarketOrder:
+		po.OrderType = msgjson.MarketOrderNum
+		po.Side = side(o.Sell)
+		po.Quantity = o.Quantity
+		po.WorstRate = o.WorstRate
+	case *order.CancelOrder:
+		po.OrderType = msgjson.CancelOrderNum
+		po.TargetID = o.TargetOrderID.Bytes()
+	}
+}
+
 func init() {
 	for _, s := range candles.BinSizes {
 		dur, err := time.ParseDuration(s)
@@ -4,6 +4,7 @@
 package apidata
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -11,7 +12,9 @@ import (
 
 	"decred.org/dcrdex/dex/candles"
 	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/server/db"
 	"decred.org/dcrdex/server/matcher"
 )
 
@@ -26,7 +29,17 @@ func (m *TMarketSource) Base() uint32          { return m.base }
 func (m *TMarketSource) Quote() uint32         { return m.quote }
 
 type TDBSource struct {
-	loadEpochErr error
+	loadEpochErr  error
+	epochProof    *db.EpochProof
+	epochProofErr error
+	orders        []order.Order
+	matches       []*db.MatchDataWithCoins
+	historyFilter *db.HistoryFilter
+
+	candleHistory    []*candles.Candle
+	candleHistoryErr error
+	historyArgs      [3]uint64 // candleDur, after, through
+	historyLimit     int
 }
 
 func (db *TDBSource) LoadEpochStats(base, quote uint32, caches []*candles.Cache) error {
@@ -41,6 +54,39 @@ func (db *TDBSource) InsertCandles(base, quote uint32, dur uint64, cs []*candles
 	return nil
 }
 
+func (tdb *TDBSource) CandleHistory(base, quote uint32, candleDur, after, through uint64, limit int) ([]*candles.Candle, error) {
+	tdb.historyArgs = [3]uint64{candleDur, after, through}
+	tdb.historyLimit = limit
+	cs := tdb.candleHistory
+	if len(cs) > limit {
+		cs = cs[:limit]
+	}
+	return cs, tdb.candleHistoryErr
+}
+
+func (tdb *TDBSource) EpochProof(base, quote uint32, epochIdx, epochDur int64) (*db.EpochProof, error) {
+	return tdb.epochProof, tdb.epochProofErr
+}
+
+func (tdb *TDBSource) OrderHistory(filter *db.HistoryFilter, f func(order.Order, order.OrderStatus) error) (int, error) {
+	tdb.historyFilter = filter
+	for _, ord := range tdb.orders {
+		if err := f(ord, order.OrderStatusExecuted); err != nil {
+			return 0, err
+		}
+	}
+	return len(tdb.orders), nil
+}
+
+func (tdb *TDBSource) MatchHistory(filter *db.HistoryFilter, f func(*db.MatchDataWithCoins) error) (int, error) {
+	for _, md := range tdb.matches {
+		if err := f(md); err != nil {
+			return 0, err
+		}
+	}
+	return len(tdb.matches), nil
+}
+
 type TBookSource struct {
 	book *msgjson.OrderBook
 }
@@ -195,3 +241,178 @@ func TestOrderBook(t *testing.T) {
 		t.Fatalf("where did this book come from?")
 	}
 }
+
+func TestEpochProof(t *testing.T) {
+	rig := newTestRig()
+	if err := rig.api.AddMarketSource(&TMarketSource{42, 0}); err != nil {
+		t.Fatalf("AddMarketSource error: %v", err)
+	}
+
+	var pi order.Preimage
+	pi[0] = 0x01
+	missedCommit := order.Commitment{0x02}
+	lo := &order.LimitOrder{
+		P:    order.Prefix{OrderType: order.LimitOrderType, ServerTime: time.UnixMilli(5500)},
+		T:    order.Trade{Sell: true, Quantity: 7},
+		Rate: 8,
+	}
+	mo := &order.MarketOrder{
+		P:         order.Prefix{OrderType: order.MarketOrderType, ServerTime: time.UnixMilli(5550)},
+		T:         order.Trade{Quantity: 9},
+		WorstRate: 10,
+	}
+	co := &order.CancelOrder{
+		P:             order.Prefix{OrderType: order.CancelOrderType, ServerTime: time.UnixMilli(5600)},
+		TargetOrderID: order.OrderID{0x09},
+	}
+	rig.db.orders = []order.Order{lo, mo, co}
+	rig.db.matches = []*db.MatchDataWithCoins{{MatchData: db.MatchData{
+		ID:       order.MatchID{0x0a},
+		Maker:    order.OrderID{0x09},
+		Taker:    lo.ID(),
+		Quantity: 7,
+		Rate:     8,
+	}}}
+	rig.db.epochProof = &db.EpochProof{
+		Idx:            5,
+		Dur:            1000,
+		MatchTime:      6001,
+		CSum:           []byte{0x03},
+		Seed:           []byte{0x04},
+		OrdersRevealed: []order.OrderID{lo.ID(), {0x05}, mo.ID()},
+		Preimages:      []order.Preimage{pi, pi, pi},
+		OrdersMissed:   []order.OrderID{co.ID()},
+		MissedCommits:  []order.Commitment{missedCommit},
+	}
+
+	req := &msgjson.EpochProofRequest{BaseID: 42, QuoteID: 0, Epoch: 5}
+	proofI, err := rig.api.handleEpochProof(req)
+	if err != nil {
+		t.Fatalf("handleEpochProof error: %v", err)
+	}
+	proof := proofI.(*msgjson.EpochProof)
+	if proof.MarketID != "dcr_btc" || proof.Epoch != 5 || proof.EpochDur != 1000 || proof.MatchTime != 6001 {
+		t.Fatalf("wrong epoch proof: %+v", proof)
+	}
+	if f := rig.db.historyFilter; f.Start.UnixMilli() != 5000 || f.End.UnixMilli() != 6000 {
+		t.Fatalf("wrong history range %v - %v", f.Start, f.End)
+	}
+	commit := pi.Commit()
+	loID := lo.ID()
+	if len(proof.Queue) != 3 || !bytes.Equal(proof.Queue[0].OrderID, loID[:]) ||
+		!bytes.Equal(proof.Queue[0].Commit, commit[:]) || !bytes.Equal(proof.Queue[0].Preimage, pi[:]) {
+		t.Fatalf("wrong queue")
+	}
+	if q := proof.Queue[0]; q.OrderType != msgjson.LimitOrderNum || q.Side != msgjson.SellOrderNum ||
+		q.Quantity != 7 || q.Rate != 8 {
+		t.Fatalf("wrong limit order details: %+v", q)
+	}
+	// An order that is no longer stored has no details.
+	if q := proof.Queue[1]; q.OrderID[0] != 0x05 || q.OrderType != 0 || q.Quantity != 0 {
+		t.Fatalf("wrong unknown order details: %+v", q)
+	}
+	if q := proof.Queue[2]; q.OrderType != msgjson.MarketOrderNum || q.Side != msgjson.BuyOrderNum ||
+		q.Quantity != 9 || q.Rate != 0 || q.WorstRate != 10 {
+		t.Fatalf("wrong market order details: %+v", q)
+	}
+	if len(proof.Misses) != 1 || !bytes.Equal(proof.Misses[0].Commit, missedCommit[:]) ||
+		len(proof.Misses[0].Preimage) != 0 {
+		t.Fatalf("wrong misses")
+	}
+	if m := proof.Misses[0]; m.OrderType != msgjson.CancelOrderNum || len(m.TargetID) == 0 || m.TargetID[0] != 0x09 {
+		t.Fatalf("wrong cancel order details: %+v", m)
+	}
+	if len(proof.Matches) != 1 || proof.Matches[0].MatchID[0] != 0x0a || !bytes.Equal(proof.Matches[0].TakerOID, loID[:]) ||
+		proof.Matches[0].Quantity != 7 || proof.Matches[0].Rate != 8 {
+		t.Fatalf("wrong matches")
+	}
+
+	// Proof material not stored.
+	rig.db.epochProof.Preimages = nil
+	if _, err = rig.api.handleEpochProof(req); err == nil {
+		t.Fatalf("no error for missing preimages")
+	}
+
+	// Unknown epoch.
+	rig.db.epochProofErr = db.ArchiveError{Code: db.ErrUnknownEpoch}
+	if _, err = rig.api.handleEpochProof(req); err == nil {
+		t.Fatalf("no error for unknown epoch")
+	}
+
+	// Unknown market.
+	rig.db.epochProofErr = nil
+	req.QuoteID = 2
+	if _, err = rig.api.handleEpochProof(req); err == nil {
+		t.Fatalf("no error for unknown market")
+	}
+}
+
+func TestCandleHistory(t *testing.T) {
+	rig := newTestRig()
+	if err := rig.api.AddMarketSource(&TMarketSource{42, 0}); err != nil {
+		t.Fatalf("AddMarketSource error: %v", err)
+	}
+
+	const binSize = uint64(time.Hour / time.Millisecond)
+	for i := uint64(1); i <= 5; i++ {
+		rig.db.candleHistory = append(rig.db.candleHistory, &candles.Candle{
+			StartStamp:  (i - 1) * binSize,
+			EndStamp:    i * binSize,
+			MatchVolume: i,
+		})
+	}
+
+	req := &msgjson.CandleHistoryRequest{BaseID: 42, QuoteID: 0, BinSize: "1h", Start: binSize, End: 10 * binSize, Count: 3}
+	resI, err := rig.api.handleCandleHistory(req)
+	if err != nil {
+		t.Fatalf("handleCandleHistory error: %v", err)
+	}
+	res := resI.(*msgjson.CandleHistory)
+	if len(res.Candles.EndStamps) != 3 || res.Candles.MatchVolumes[2] != 3 {
+		t.Fatalf("wrong candles: %+v", res.Candles)
+	}
+	if res.Next != 3*binSize {
+		t.Fatalf("wrong next. wanted %d, got %d", 3*binSize, res.Next)
+	}
+	if rig.db.historyArgs != [3]uint64{binSize, 2*binSize - 1, 10 * binSize} || rig.db.historyLimit != 4 {
+		t.Fatalf("wrong DB args %v, limit %d", rig.db.historyArgs, rig.db.historyLimit)
+	}
+
+	// Last page.
+	req.Count = 5
+	resI, err = rig.api.handleCandleHistory(req)
+	if err != nil {
+		t.Fatalf("handleCandleHistory error: %v", err)
+	}
+	res = resI.(*msgjson.CandleHistory)
+	if len(res.Candles.EndStamps) != 5 || res.Next != 0 {
+		t.Fatalf("wrong last page: %d candles, next %d", len(res.Candles.EndStamps), res.Next)
+	}
+
+	// Default count and end.
+	req.Count, req.End = 0, 0
+	if _, err = rig.api.handleCandleHistory(req); err != nil {
+		t.Fatalf("handleCandleHistory error: %v", err)
+	}
+	if rig.db.historyLimit != candles.CacheSize+1 || rig.db.historyArgs[2] == 0 {
+		t.Fatalf("defaults not applied")
+	}
+
+	for name, badReq := range map[string]*msgjson.CandleHistoryRequest{
+		"count too large":  {BaseID: 42, QuoteID: 0, BinSize: "1h", Count: candles.CacheSize + 1},
+		"negative count":   {BaseID: 42, QuoteID: 0, BinSize: "1h", Count: -1},
+		"unknown market":   {BaseID: 42, QuoteID: 2, BinSize: "1h"},
+		"bad bin size":     {BaseID: 42, QuoteID: 0, BinSize: "1x"},
+		"unstored bin":     {BaseID: 42, QuoteID: 0, BinSize: "1s"},
+		"end before start": {BaseID: 42, QuoteID: 0, BinSize: "1h", Start: 10, End: 5},
+	} {
+		if _, err := rig.api.handleCandleHistory(badReq); err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+
+	rig.db.candleHistoryErr = dummyErr
+	if _, err = rig.api.handleCandleHistory(req); err == nil {
+		t.Fatalf("no error for DB error")
+	}
+}
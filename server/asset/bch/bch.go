@@ -45,14 +45,19 @@ func (d *Driver) UnitInfo() dex.UnitInfo {
 // dust outputs on the bond and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinBondSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinBondSize(maxFeeRate, false)
+	return dexbtc.MinBondSizeForDust(&dexbch.DustPolicy, maxFeeRate, false)
 }
 
 // MinLotSize calculates the minimum bond size for a given fee rate that avoids
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, false)
+	return dexbtc.MinLotSizeForDust(&dexbch.DustPolicy, maxFeeRate, false)
+}
+
+// DustPolicy returns the Bitcoin Cash dust policy.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return &dexbch.DustPolicy
 }
 
 // Name is the asset's name.
@@ -116,6 +121,7 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		MaxFeeBlocks:         maxFeeBlocks,
 		ArglessFeeEstimates:  true,
 		RelayAddr:            cfg.RelayAddr,
+		FeeOracle:            cfg.FeeOracle,
 	})
 	if err != nil {
 		return nil, err
@@ -43,9 +43,18 @@ const defaultNoCompetitionRate = 10
 
 type v1Config struct {
 	ConfigPath     string `json:"configPath"`
-	DisableAPIFees bool   `json:"disableApiFees"`
 	TatumKey       string `json:"tatumKey"`
 	BlockdaemonKey string `json:"blockdaemonKey"`
+	// The fee oracle settings may be set here instead of in the asset's
+	// feeOracle in the markets configuration, but not in both.
+	txfee.OracleConfig
+}
+
+// hasFeeOracleSettings checks whether any of the fee oracle settings are set
+// in the v1 config file.
+func (cfg *v1Config) hasFeeOracleSettings() bool {
+	return cfg.DisableAPIFees || cfg.NodeFeesFirst || len(cfg.FeeSources) > 0 ||
+		cfg.FeeRateFloor > 0 || cfg.FeeRateCeiling > 0
 }
 
 // Driver implements asset.Driver.
@@ -90,6 +99,11 @@ func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
 	return dexbtc.MinLotSize(maxFeeRate, true)
 }
 
+// DustPolicy returns the Bitcoin dust policy.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return &dexbtc.DustPolicy
+}
+
 // Name is the asset's name.
 func (d *Driver) Name() string {
 	return "Bitcoin"
@@ -186,11 +200,9 @@ type Backend struct {
 		hash chainhash.Hash
 	}
 
-	feeRateCache struct {
-		sync.RWMutex
-		feeRate uint64
-		stamp   time.Time
-	}
+	// feeOracle provides the fee rate. nil if not created with NewBTCClone,
+	// in which case the node's estimate is used.
+	feeOracle *txfee.Oracle
 }
 
 // Check that Backend satisfies the Backend interface.
@@ -211,8 +223,9 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		configPath = dexbtc.SystemConfigPath("bitcoin")
 	}
 
-	feeSources := make([]*txfee.SourceConfig, len(freeFeeSources), len(freeFeeSources)+1)
+	feeSources := make([]*txfee.SourceConfig, len(freeFeeSources), len(freeFeeSources)+2)
 	copy(feeSources, freeFeeSources)
+	feeOracle := cfg.FeeOracle
 	b, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("error reading config file: %w", err)
@@ -230,10 +243,12 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		if cfgV1.BlockdaemonKey != "" {
 			feeSources = append(feeSources, blockDaemonFeeFetcher(cfgV1.BlockdaemonKey))
 		}
-	}
-	var feeFetcher *txfee.FeeFetcher
-	if !cfgV1.DisableAPIFees {
-		feeFetcher = txfee.NewFeeFetcher(feeSources, cfg.Logger)
+		if cfgV1.hasFeeOracleSettings() {
+			if feeOracle != nil {
+				return nil, errors.New("fee oracle settings are in both the v1 config file and the markets configuration")
+			}
+			feeOracle = &cfgV1.OracleConfig
+		}
 	}
 	return NewBTCClone(&BackendCloneConfig{
 		Name:        assetName,
@@ -244,7 +259,8 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		ChainParams: params,
 		Ports:       dexbtc.RPCPorts,
 		RelayAddr:   cfg.RelayAddr,
-		FeeFetcher:  feeFetcher,
+		FeeSources:  feeSources,
+		FeeOracle:   feeOracle,
 	})
 }
 
@@ -358,8 +374,12 @@ type BackendCloneConfig struct {
 	// encodes valueBalanceOrchard in their getrawtransaction RPC results.
 	ShieldedIO func(tx *VerboseTxExtended) (in, out uint64, err error)
 	// RelayAddr is an address for a NodeRelay.
-	RelayAddr  string
-	FeeFetcher *txfee.FeeFetcher
+	RelayAddr string
+	// FeeSources are the asset's built-in external fee rate sources, which
+	// are used unless disabled by FeeOracle.
+	FeeSources []*txfee.SourceConfig
+	// FeeOracle configures the fee rate sources. May be nil.
+	FeeOracle *txfee.OracleConfig
 }
 
 // NewBTCClone creates a BTC backend for a set of network parameters and default
@@ -381,7 +401,12 @@ func NewBTCClone(cloneCfg *BackendCloneConfig) (*Backend, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newBTC(cloneCfg, rpcConfig), nil
+	btc := newBTC(cloneCfg, rpcConfig)
+	btc.feeOracle, err = txfee.NewOracle(cloneCfg.FeeOracle, cloneCfg.FeeSources, btc.nodeFeeRate, cloneCfg.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s fee oracle configuration: %w", cloneCfg.Name, err)
+	}
+	return btc, nil
 }
 
 func (btc *Backend) shutdown() {
@@ -458,28 +483,16 @@ func (btc *Backend) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 
 	var wg sync.WaitGroup
 
-	if fetcher := btc.cfg.FeeFetcher; fetcher != nil {
-		cm := dex.NewConnectionMaster(btc.cfg.FeeFetcher)
-		if err := cm.ConnectOnce(ctx); err != nil {
+	if btc.feeOracle != nil {
+		oracleWG, err := btc.feeOracle.Connect(ctx)
+		if err != nil {
 			btc.shutdown()
-			return nil, fmt.Errorf("error starting fee fetcher: %w", err)
+			return nil, err
 		}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			defer cm.Disconnect()
-			for {
-				select {
-				case r := <-fetcher.Next():
-					btc.log.Tracef("New fee reported: %d", r)
-					btc.feeRateCache.Lock()
-					btc.feeRateCache.stamp = time.Now()
-					btc.feeRateCache.feeRate = r
-					btc.feeRateCache.Unlock()
-				case <-ctx.Done():
-					return
-				}
-			}
+			oracleWG.Wait()
 		}()
 	}
 
@@ -1492,23 +1505,21 @@ out:
 }
 
 // estimateFee attempts to get a reasonable tx fee rates (units: atomic/(v)byte)
-// to use for the asset by checking estimate(smart)fee. That call can fail or
-// otherwise be useless on an otherwise perfectly functioning node. In that
-// case, an estimate is calculated from the median fees of the previous
-// block(s).
+// to use for the asset. If the fee oracle has a current rate from an external
+// source, that rate is used. Otherwise, the node is checked with
+// estimate(smart)fee. That call can fail or otherwise be useless on an
+// otherwise perfectly functioning node. In that case, an estimate is calculated
+// from the median fees of the previous block(s).
 func (btc *Backend) estimateFee(ctx context.Context) (satsPerB uint64, err error) {
-	if btc.cfg.FeeFetcher != nil {
-		const feeRateExpiry = time.Minute * 10
-		btc.feeRateCache.RLock()
-		stamp, feeRate := btc.feeRateCache.stamp, btc.feeRateCache.feeRate
-		btc.feeRateCache.RUnlock()
-		if time.Since(stamp) < feeRateExpiry {
-			return feeRate, nil
-		} else {
-			btc.log.Warnf("external btc fee rate is expired. falling back to estimatesmartfee")
-		}
+	if btc.feeOracle == nil {
+		return btc.nodeFeeRate(ctx)
 	}
+	return btc.feeOracle.FeeRate(ctx)
+}
 
+// nodeFeeRate gets the fee rate from the node's estimate(smart)fee, or from
+// the median fees of the previous block(s) if the node has no estimate.
+func (btc *Backend) nodeFeeRate(ctx context.Context) (satsPerB uint64, err error) {
 	if btc.cfg.DumbFeeEstimates {
 		satsPerB, err = btc.node.EstimateFee(btc.feeConfs)
 	} else {
@@ -47,14 +47,19 @@ func (d *Driver) Name() string {
 // dust outputs on the bond and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinBondSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinBondSize(maxFeeRate, false)
+	return dexbtc.MinBondSizeForDust(&dexdash.DustPolicy, maxFeeRate, false)
 }
 
 // MinLotSize calculates the minimum bond size for a given fee rate that avoids
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, false)
+	return dexbtc.MinLotSizeForDust(&dexdash.DustPolicy, maxFeeRate, false)
+}
+
+// DustPolicy returns the Dash dust policy.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return &dexdash.DustPolicy
 }
 
 func init() {
@@ -109,5 +114,6 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		FeeConfs:     2,
 		MaxFeeBlocks: 16,
 		RelayAddr:    cfg.RelayAddr,
+		FeeOracle:    cfg.FeeOracle,
 	})
 }
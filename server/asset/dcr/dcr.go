@@ -20,6 +20,7 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/calc"
 	dexdcr "decred.org/dcrdex/dex/networks/dcr"
+	"decred.org/dcrdex/dex/txfee"
 	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/server/asset"
 	"github.com/decred/dcrd/blockchain/stake/v5"
@@ -86,6 +87,11 @@ func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
 	return dexdcr.MinLotSize(maxFeeRate)
 }
 
+// DustPolicy returns the Decred dust policy.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return &dexdcr.DustPolicy
+}
+
 // Name is the asset's name.
 func (d *Driver) Name() string {
 	return "Decred"
@@ -273,6 +279,9 @@ type Backend struct {
 	log dex.Logger
 	// nodeRelay is the NodeRelay address.
 	nodeRelay string
+	// feeOracle provides the fee rate. nil if not created with NewBackend, in
+	// which case the node's estimate is used.
+	feeOracle *txfee.Oracle
 }
 
 // Check that Backend satisfies the Backend interface.
@@ -301,7 +310,12 @@ func NewBackend(cfg *asset.BackendConfig) (*Backend, error) {
 	if err != nil {
 		return nil, err
 	}
-	return unconnectedDCR(cfg, dcrConfig), nil
+	dcr := unconnectedDCR(cfg, dcrConfig)
+	dcr.feeOracle, err = txfee.NewOracle(cfg.FeeOracle, nil, dcr.nodeFeeRate, cfg.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee oracle configuration: %w", err)
+	}
+	return dcr, nil
 }
 
 func (dcr *Backend) shutdown() {
@@ -394,11 +408,24 @@ func (dcr *Backend) Connect(ctx context.Context) (_ *sync.WaitGroup, err error)
 		}
 	}
 
+	var wg sync.WaitGroup
+	if dcr.feeOracle != nil {
+		oracleWG, err := dcr.feeOracle.Connect(ctx)
+		if err != nil {
+			dcr.shutdown()
+			return nil, err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			oracleWG.Wait()
+		}()
+	}
+
 	if _, err = dcr.FeeRate(ctx); err != nil {
 		dcr.log.Warnf("Decred backend started without fee estimation available: %v", err)
 	}
 
-	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -407,8 +434,18 @@ func (dcr *Backend) Connect(ctx context.Context) (_ *sync.WaitGroup, err error)
 	return &wg, nil
 }
 
-// FeeRate returns the current optimal fee rate in atoms / byte.
+// FeeRate returns the current optimal fee rate in atoms / byte. If the fee
+// oracle has a current rate from an external source, that rate is used.
+// Otherwise, the node's estimate is used.
 func (dcr *Backend) FeeRate(ctx context.Context) (uint64, error) {
+	if dcr.feeOracle == nil {
+		return dcr.nodeFeeRate(ctx)
+	}
+	return dcr.feeOracle.FeeRate(ctx)
+}
+
+// nodeFeeRate gets the fee rate estimate from the node in atoms / byte.
+func (dcr *Backend) nodeFeeRate(ctx context.Context) (uint64, error) {
 	// estimatesmartfee 1 returns extremely high rates on DCR.
 	estimateFeeResult, err := dcr.node.EstimateSmartFee(ctx, 2, chainjson.EstimateSmartFeeConservative)
 	if err != nil {
@@ -45,14 +45,19 @@ func (d *Driver) UnitInfo() dex.UnitInfo {
 // dust outputs on the bond and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinBondSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinBondSize(maxFeeRate, false)
+	return dexbtc.MinBondSizeForDust(&dexdgb.DustPolicy, maxFeeRate, false)
 }
 
 // MinLotSize calculates the minimum bond size for a given fee rate that avoids
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, false)
+	return dexbtc.MinLotSizeForDust(&dexdgb.DustPolicy, maxFeeRate, false)
+}
+
+// DustPolicy returns the DigiByte dust policy.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return &dexdgb.DustPolicy
 }
 
 // Name is the asset's name.
@@ -111,5 +116,6 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		NoCompetitionFeeRate: 210, // 0.0021 DGB/kB
 		MaxFeeBlocks:         maxFeeBlocks,
 		RelayAddr:            cfg.RelayAddr,
+		FeeOracle:            cfg.FeeOracle,
 	})
 }
@@ -14,8 +14,6 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 )
 
-const dustLimit = 1_000_000 // sats => 0.01 DOGE, the "soft" limit (DEFAULT_DUST_LIMIT)
-
 var maxFeeBlocks = 16
 
 // Driver implements asset.Driver.
@@ -47,14 +45,19 @@ func (d *Driver) UnitInfo() dex.UnitInfo {
 // dust outputs on the bond and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinBondSize(maxFeeRate uint64) uint64 {
-	return dustLimit + dexbtc.RefundBondTxSize(false)*maxFeeRate
+	return dexbtc.MinBondSizeForDust(&dexdoge.DustPolicy, maxFeeRate, false)
 }
 
 // MinLotSize calculates the minimum bond size for a given fee rate that avoids
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dustLimit + dexbtc.RedeemSwapTxSize(false)*maxFeeRate
+	return dexbtc.MinLotSizeForDust(&dexdoge.DustPolicy, maxFeeRate, false)
+}
+
+// DustPolicy returns the Dogecoin dust policy.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return &dexdoge.DustPolicy
 }
 
 // Name is the asset's name.
@@ -122,5 +125,6 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		BooleanGetBlockRPC:   true,
 		BlockDeserializer:    dexdoge.DeserializeBlock,
 		RelayAddr:            cfg.RelayAddr,
+		FeeOracle:            cfg.FeeOracle,
 	})
 }
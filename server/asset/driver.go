@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/txfee"
 )
 
 var (
@@ -37,6 +38,19 @@ type minValuer interface {
 	MinLotSize(maxFeeRate uint64) uint64
 }
 
+// minimumser can be implemented by assets whose minimums are retrieved with a
+// request that can fail, such as a backend plugin. It takes precedence over
+// minValuer.
+type minimumser interface {
+	Minimums(maxFeeRate uint64) (minLotSize, minBondSize uint64, err error)
+}
+
+// dustPolicier is implemented by UTXO-based assets, whose relay policies
+// define a dust threshold for outputs.
+type dustPolicier interface {
+	DustPolicy() *dex.DustPolicy
+}
+
 // Driver is the interface required of all base chain assets.
 type Driver interface {
 	driverBase
@@ -94,6 +108,12 @@ func Register(assetID uint32, drv Driver) {
 	drivers[assetID] = drv
 }
 
+// Unregister removes the driver for a base chain asset, e.g. when an asset
+// backend plugin that registered it is unloaded.
+func Unregister(assetID uint32) {
+	delete(drivers, assetID)
+}
+
 // RegisterToken is called to register a token. The parent asset should be
 // registered first.
 func RegisterToken(assetID uint32, drv TokenDriver) {
@@ -128,6 +148,9 @@ type BackendConfig struct {
 	Logger     dex.Logger
 	Net        dex.Network
 	RelayAddr  string
+	// FeeOracle configures the backend's fee rate sources. May be nil.
+	// Backends that only use their node's estimate ignore it.
+	FeeOracle *txfee.OracleConfig
 }
 
 // Setup sets up the named asset. The RPC connection parameters are obtained
@@ -170,20 +193,36 @@ func Tokens(assetID uint32) map[uint32]*dex.Token {
 }
 
 // Minimums returns the minimimum lot size and bond size for a registered asset.
-func Minimums(assetID uint32, maxFeeRate uint64) (minLotSize, minBondSize uint64, found bool) {
+func Minimums(assetID uint32, maxFeeRate uint64) (minLotSize, minBondSize uint64, err error) {
 	baseChainID := assetID
 	if token, is := tokens[assetID]; is {
 		baseChainID = token.TokenInfo().ParentID
 	}
 	drv, found := drivers[baseChainID]
 	if !found {
-		return 0, 0, false
+		return 0, 0, fmt.Errorf("asset: unknown asset driver %d", baseChainID)
+	}
+	if m, is := drv.(minimumser); is {
+		return m.Minimums(maxFeeRate)
 	}
 	m, is := drv.(minValuer)
 	if !is {
-		return 1, 1, true
+		return 1, 1, nil
+	}
+	return m.MinLotSize(maxFeeRate), m.MinBondSize(maxFeeRate), nil
+}
+
+// DustPolicy returns the dust policy for a registered base chain asset, or nil
+// if the asset is a token, is not registered, or does not have a dust policy.
+func DustPolicy(assetID uint32) *dex.DustPolicy {
+	drv, found := drivers[assetID]
+	if !found {
+		return nil
+	}
+	if d, is := drv.(dustPolicier); is {
+		return d.DustPolicy()
 	}
-	return m.MinLotSize(maxFeeRate), m.MinBondSize(maxFeeRate), true
+	return nil
 }
 
 // RegisteredAsset is information about a registered asset.
@@ -50,14 +50,19 @@ func (d *Driver) UnitInfo() dex.UnitInfo {
 // dust outputs on the bond and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinBondSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinBondSize(maxFeeRate, false)
+	return dexbtc.MinBondSizeForDust(&dexfiro.DustPolicy, maxFeeRate, false)
 }
 
 // MinLotSize calculates the minimum bond size for a given fee rate that avoids
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, false)
+	return dexbtc.MinLotSizeForDust(&dexfiro.DustPolicy, maxFeeRate, false)
+}
+
+// DustPolicy returns the Firo dust policy.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return &dexfiro.DustPolicy
 }
 
 // Name is the asset's name.
@@ -134,6 +139,7 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		// but estimatefee works on simnet, and on mainnet v0.14.12.1.
 		DumbFeeEstimates: true,
 		RelayAddr:        cfg.RelayAddr,
+		FeeOracle:        cfg.FeeOracle,
 	})
 }
 
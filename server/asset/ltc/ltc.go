@@ -43,14 +43,19 @@ func (d *Driver) Version() uint32 {
 // dust outputs on the bond and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinBondSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinBondSize(maxFeeRate, true)
+	return dexbtc.MinBondSizeForDust(&dexltc.DustPolicy, maxFeeRate, true)
 }
 
 // MinLotSize calculates the minimum bond size for a given fee rate that avoids
 // dust outputs on the swap and refund txs, assuming the maxFeeRate doesn't
 // change.
 func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
-	return dexbtc.MinLotSize(maxFeeRate, true)
+	return dexbtc.MinLotSizeForDust(&dexltc.DustPolicy, maxFeeRate, true)
+}
+
+// DustPolicy returns the Litecoin dust policy.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return &dexltc.DustPolicy
 }
 
 // Name is the asset's name.
@@ -111,5 +116,6 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		FeeConfs:     2,
 		MaxFeeBlocks: 20,
 		RelayAddr:    cfg.RelayAddr,
+		FeeOracle:    cfg.FeeOracle,
 	})
 }
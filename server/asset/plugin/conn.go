@@ -0,0 +1,194 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"decred.org/dcrdex/server/asset"
+)
+
+// errConnClosed is returned for requests on a closed conn.
+var errConnClosed = errors.New("plugin connection closed")
+
+// conn is one end of a plugin connection. Both the server and the plugin use
+// a conn to send and receive Messages.
+type conn struct {
+	wMtx sync.Mutex
+	enc  *json.Encoder
+	dec  *json.Decoder
+
+	nextID uint64 // atomic
+
+	pendingMtx sync.Mutex
+	pending    map[uint64]chan *Message
+	closed     bool
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		enc:     json.NewEncoder(w),
+		dec:     json.NewDecoder(r),
+		pending: make(map[uint64]chan *Message),
+	}
+}
+
+// run reads messages until the reader is closed or a message cannot be
+// decoded. Responses are delivered to the pending request, and requests and
+// notifications are passed to the handler. Pending and future requests fail
+// after run returns.
+func (c *conn) run(handler func(*Message)) error {
+	defer func() {
+		c.pendingMtx.Lock()
+		c.closed = true
+		for id, ch := range c.pending {
+			close(ch)
+			delete(c.pending, id)
+		}
+		c.pendingMtx.Unlock()
+	}()
+
+	for {
+		msg := new(Message)
+		if err := c.dec.Decode(msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if msg.Method != "" {
+			handler(msg)
+			continue
+		}
+		c.pendingMtx.Lock()
+		ch, found := c.pending[msg.ID]
+		delete(c.pending, msg.ID)
+		c.pendingMtx.Unlock()
+		if found {
+			ch <- msg
+		}
+	}
+}
+
+func (c *conn) send(msg *Message) error {
+	c.wMtx.Lock()
+	defer c.wMtx.Unlock()
+	return c.enc.Encode(msg)
+}
+
+// call sends a request and waits for the response. The result is decoded into
+// result, which may be nil if the result is not needed.
+func (c *conn) call(ctx context.Context, method string, params, result any) error {
+	msg := &Message{
+		ID:     atomic.AddUint64(&c.nextID, 1),
+		Method: method,
+	}
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("error encoding %s params: %w", method, err)
+		}
+		msg.Params = b
+	}
+
+	ch := make(chan *Message, 1)
+	c.pendingMtx.Lock()
+	if c.closed {
+		c.pendingMtx.Unlock()
+		return errConnClosed
+	}
+	c.pending[msg.ID] = ch
+	c.pendingMtx.Unlock()
+	removePending := func() {
+		c.pendingMtx.Lock()
+		delete(c.pending, msg.ID)
+		c.pendingMtx.Unlock()
+	}
+
+	if err := c.send(msg); err != nil {
+		removePending()
+		return fmt.Errorf("error sending %s request: %w", method, err)
+	}
+
+	var resp *Message
+	select {
+	case resp = <-ch:
+		if resp == nil {
+			return errConnClosed
+		}
+	case <-ctx.Done():
+		removePending()
+		return fmt.Errorf("%s request: %w: %v", method, asset.ErrRequestTimeout, ctx.Err())
+	}
+	if resp.Error != nil {
+		return fromError(resp.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("error decoding %s result: %w", method, err)
+	}
+	return nil
+}
+
+// notify sends a notification.
+func (c *conn) notify(method string, params any) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.send(&Message{Method: method, Params: b})
+}
+
+// respond sends the response to a request.
+func (c *conn) respond(id uint64, result any, err error) error {
+	msg := &Message{ID: id}
+	if err != nil {
+		msg.Error = toError(err)
+		return c.send(msg)
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		msg.Error = &Error{Code: ErrCodeUnknown, Message: fmt.Sprintf("error encoding result: %v", err)}
+		return c.send(msg)
+	}
+	msg.Result = b
+	return c.send(msg)
+}
+
+// toError converts an error from a backend to an *Error, preserving the
+// asset package's error kinds.
+func toError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	code := ErrCodeUnknown
+	switch {
+	case errors.Is(err, asset.CoinNotFoundError):
+		code = ErrCodeCoinNotFound
+	case errors.Is(err, asset.ErrRequestTimeout):
+		code = ErrCodeRequestTimeout
+	}
+	return &Error{Code: code, Message: err.Error()}
+}
+
+// fromError converts an *Error from the plugin to an error that wraps the
+// asset package's error kinds.
+func fromError(e *Error) error {
+	switch e.Code {
+	case ErrCodeCoinNotFound:
+		return fmt.Errorf("%w: %s", asset.CoinNotFoundError, e.Message)
+	case ErrCodeRequestTimeout:
+		return fmt.Errorf("%w: %s", asset.ErrRequestTimeout, e.Message)
+	}
+	return e
+}
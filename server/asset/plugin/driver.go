@@ -0,0 +1,684 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/asset"
+)
+
+const (
+	// requestTimeout is the timeout for requests to the plugin for Backend
+	// methods that do not take a Context.
+	requestTimeout = 20 * time.Second
+	// shutdownTimeout is how long to wait for the plugin to exit after the
+	// shutdown request before killing it.
+	shutdownTimeout = 10 * time.Second
+)
+
+// Driver is the asset.Driver for a plugin. The plugin process is started by
+// Load, and runs until the backend is disconnected or the Driver is unloaded.
+type Driver struct {
+	assetID uint32
+	net     dex.Network
+	log     dex.Logger
+	conn    *conn
+	info    *HandshakeResult
+	// kill kills the plugin process. kill is nil if the plugin is not a
+	// process started by Load.
+	kill func() error
+	// done is closed when the plugin closes its end of the connection.
+	done     chan struct{}
+	stopOnce sync.Once
+
+	beMtx sync.RWMutex
+	be    *backend
+}
+
+var _ asset.Driver = (*Driver)(nil)
+
+// Load starts the plugin executable at path with the provided arguments,
+// and registers it as the asset.Driver for the asset. Logs written by the
+// plugin to stderr are logged with the provided logger.
+func Load(assetID uint32, path string, args []string, net dex.Network, log dex.Logger) (*Driver, error) {
+	if _, err := asset.Version(assetID); err == nil {
+		return nil, fmt.Errorf("a backend is already registered for asset %d", assetID)
+	}
+
+	cmd := exec.Command(path, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting plugin %q: %w", path, err)
+	}
+
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Info(scanner.Text())
+		}
+	}()
+
+	kill := func() error {
+		return cmd.Process.Kill()
+	}
+	d := newDriver(assetID, net, log, stdout, stdin, kill)
+	go func() {
+		// Reads from the pipes must complete before Wait.
+		<-d.done
+		<-stderrDone
+		if err := cmd.Wait(); err != nil {
+			log.Errorf("Plugin exited: %v", err)
+		} else {
+			log.Infof("Plugin exited")
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	if err = d.handshake(ctx); err != nil {
+		kill()
+		return nil, err
+	}
+
+	asset.Register(assetID, d)
+	return d, nil
+}
+
+// newDriver creates a Driver for a plugin connected with r and w. kill may be
+// nil.
+func newDriver(assetID uint32, net dex.Network, log dex.Logger, r io.Reader, w io.Writer, kill func() error) *Driver {
+	d := &Driver{
+		assetID: assetID,
+		net:     net,
+		log:     log,
+		conn:    newConn(r, w),
+		kill:    kill,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(d.done)
+		if err := d.conn.run(d.handleMessage); err != nil {
+			log.Errorf("Error reading from plugin: %v", err)
+		}
+		d.beMtx.RLock()
+		be := d.be
+		d.beMtx.RUnlock()
+		if be != nil && be.connected() {
+			be.signal(&asset.BlockUpdate{Err: asset.NewConnectionError("plugin disconnected")})
+		}
+	}()
+	return d
+}
+
+// handshake performs the handshake with the plugin.
+func (d *Driver) handshake(ctx context.Context) error {
+	var res HandshakeResult
+	err := d.conn.call(ctx, MethodHandshake, &HandshakeParams{
+		ProtocolVersion: ProtocolVersion,
+		AssetID:         d.assetID,
+		Network:         d.net.String(),
+	}, &res)
+	if err != nil {
+		return fmt.Errorf("plugin handshake error: %w", err)
+	}
+	if res.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("plugin protocol version %d not supported. expected %d",
+			res.ProtocolVersion, ProtocolVersion)
+	}
+	if res.UnitInfo.Conventional.ConversionFactor == 0 {
+		return errors.New("plugin unit info has a zero conversion factor")
+	}
+	d.info = &res
+	return nil
+}
+
+// handleMessage handles notifications from the plugin.
+func (d *Driver) handleMessage(msg *Message) {
+	if msg.ID != 0 {
+		d.conn.respond(msg.ID, nil, &Error{
+			Code:    ErrCodeUnknownMethod,
+			Message: fmt.Sprintf("unknown method %q", msg.Method),
+		})
+		return
+	}
+	switch msg.Method {
+	case NotifyBlock:
+		var params BlockParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			d.log.Errorf("Error decoding block notification: %v", err)
+			return
+		}
+		d.beMtx.RLock()
+		be := d.be
+		d.beMtx.RUnlock()
+		if be == nil {
+			return
+		}
+		update := &asset.BlockUpdate{Reorg: params.Reorg}
+		if params.Error != "" {
+			update.Err = asset.NewConnectionError("%s", params.Error)
+		}
+		be.signal(update)
+	default:
+		d.log.Warnf("Unknown notification %q from plugin", msg.Method)
+	}
+}
+
+// request sends a request to the plugin with the default timeout.
+func (d *Driver) request(method string, params, result any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	return d.conn.call(ctx, method, params, result)
+}
+
+// stop asks the plugin to shut down, and kills it if it does not exit. Only
+// the first call has any effect.
+func (d *Driver) stop() {
+	d.stopOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := d.conn.call(ctx, MethodShutdown, nil, nil); err != nil {
+			d.log.Errorf("Plugin shutdown error: %v", err)
+		}
+		select {
+		case <-d.done:
+		case <-ctx.Done():
+			if d.kill != nil {
+				d.log.Warnf("Plugin did not exit. Killing it.")
+				if err := d.kill(); err != nil {
+					d.log.Errorf("Error killing plugin: %v", err)
+				}
+			}
+		}
+	})
+}
+
+// Unload stops the plugin and unregisters its asset.Driver. Unload is for a
+// plugin that will not be used, e.g. if the server fails to start after the
+// plugin is loaded. A connected backend also stops the plugin when it is
+// disconnected.
+func (d *Driver) Unload() {
+	d.stop()
+	asset.Unregister(d.assetID)
+}
+
+// DecodeCoinID creates a human-readable representation of a coin ID.
+func (d *Driver) DecodeCoinID(coinID []byte) (string, error) {
+	var s string
+	if err := d.request(MethodDecodeCoinID, &CoinIDParams{CoinID: coinID}, &s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// Version returns the version of the plugin's backend.
+func (d *Driver) Version() uint32 {
+	return d.info.Version
+}
+
+// UnitInfo returns the dex.UnitInfo for the asset.
+func (d *Driver) UnitInfo() dex.UnitInfo {
+	return d.info.UnitInfo
+}
+
+// Name is the name of the asset.
+func (d *Driver) Name() string {
+	return d.info.Name
+}
+
+// Minimums are the minimum lot size and bond size for the asset at the max fee
+// rate, as reported by the plugin.
+func (d *Driver) Minimums(maxFeeRate uint64) (minLotSize, minBondSize uint64, err error) {
+	var res MinimumsResult
+	if err := d.request(MethodMinimums, &MinimumsParams{MaxFeeRate: maxFeeRate}, &res); err != nil {
+		return 0, 0, fmt.Errorf("error retrieving minimums from plugin: %w", err)
+	}
+	if res.MinLotSize == 0 || res.MinBondSize == 0 {
+		return 0, 0, fmt.Errorf("plugin reported a zero minimum lot size (%d) or bond size (%d)",
+			res.MinLotSize, res.MinBondSize)
+	}
+	return res.MinLotSize, res.MinBondSize, nil
+}
+
+// DustPolicy is the asset's dust policy reported by the plugin, if any.
+func (d *Driver) DustPolicy() *dex.DustPolicy {
+	return d.info.Dust
+}
+
+// Setup creates the plugin's backend. The returned Backend is also an
+// asset.OutputTracker or an asset.AccountBalancer, depending on the asset.
+func (d *Driver) Setup(cfg *asset.BackendConfig) (asset.Backend, error) {
+	if cfg.AssetID != d.assetID {
+		return nil, fmt.Errorf("plugin is for asset %d, not %d", d.assetID, cfg.AssetID)
+	}
+	if cfg.Net != d.net {
+		return nil, fmt.Errorf("plugin is for network %s, not %s", d.net, cfg.Net)
+	}
+	d.beMtx.Lock()
+	defer d.beMtx.Unlock()
+	if d.be != nil {
+		return nil, errors.New("plugin backend already created")
+	}
+
+	var res SetupResult
+	err := d.request(MethodSetup, &SetupParams{
+		ConfigPath: cfg.ConfigPath,
+		RelayAddr:  cfg.RelayAddr,
+		FeeOracle:  cfg.FeeOracle,
+	}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("plugin setup error: %w", err)
+	}
+	if res.UTXO == res.Account {
+		return nil, errors.New("plugin backend must be either UTXO-based or account-based")
+	}
+
+	be := &backend{
+		d:          d,
+		log:        cfg.Logger,
+		info:       &asset.BackendInfo{SupportsDynamicTxFee: res.SupportsDynamicTxFee},
+		blockChans: make(map[chan *asset.BlockUpdate]struct{}),
+	}
+	if res.UTXO {
+		d.be = be
+		return &utxoBackend{be}, nil
+	}
+	var sizes TxSizesResult
+	if err = d.request(MethodTxSizes, nil, &sizes); err != nil {
+		return nil, fmt.Errorf("plugin tx sizes error: %w", err)
+	}
+	d.be = be
+	return &accountBackend{backend: be, sizes: &sizes}, nil
+}
+
+// backend is an asset.Backend that forwards requests to the plugin.
+type backend struct {
+	d    *Driver
+	log  dex.Logger
+	info *asset.BackendInfo
+
+	signalMtx   sync.RWMutex
+	blockChans  map[chan *asset.BlockUpdate]struct{}
+	connectMtx  sync.Mutex
+	isConnected bool
+}
+
+var _ asset.Backend = (*backend)(nil)
+
+// Connect connects the plugin's backend. The plugin is stopped when the
+// context is canceled.
+func (be *backend) Connect(ctx context.Context) (*sync.WaitGroup, error) {
+	if err := be.d.conn.call(ctx, MethodConnect, nil, nil); err != nil {
+		return nil, fmt.Errorf("plugin connect error: %w", err)
+	}
+	be.connectMtx.Lock()
+	be.isConnected = true
+	be.connectMtx.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		be.connectMtx.Lock()
+		be.isConnected = false
+		be.connectMtx.Unlock()
+		be.d.stop()
+	}()
+	return &wg, nil
+}
+
+func (be *backend) connected() bool {
+	be.connectMtx.Lock()
+	defer be.connectMtx.Unlock()
+	return be.isConnected
+}
+
+// signal sends the update to every block channel.
+func (be *backend) signal(update *asset.BlockUpdate) {
+	be.signalMtx.RLock()
+	defer be.signalMtx.RUnlock()
+	if update.Err != nil {
+		be.log.Error(update.Err)
+	}
+	for c := range be.blockChans {
+		select {
+		case c <- update:
+		default:
+			be.log.Errorf("failed to send block update on blocking channel")
+		}
+	}
+}
+
+// BlockChannel creates and returns a new channel on which to receive block
+// updates.
+func (be *backend) BlockChannel(size int) <-chan *asset.BlockUpdate {
+	c := make(chan *asset.BlockUpdate, size)
+	be.signalMtx.Lock()
+	defer be.signalMtx.Unlock()
+	be.blockChans[c] = struct{}{}
+	return c
+}
+
+// Contract returns the swap contract in the transaction output.
+func (be *backend) Contract(coinID, contractData []byte) (*asset.Contract, error) {
+	ref := &CoinRef{
+		Kind:         CoinKindContract,
+		CoinID:       coinID,
+		ContractData: contractData,
+	}
+	var res ContractResult
+	if err := be.d.request(MethodContract, ref, &res); err != nil {
+		return nil, err
+	}
+	return &asset.Contract{
+		Coin:         be.newCoin(ref, &res.CoinInfo),
+		SwapAddress:  res.SwapAddress,
+		ContractData: res.ContractData,
+		SecretHash:   res.SecretHash,
+		LockTime:     time.UnixMilli(res.LockTime),
+		TxData:       res.TxData,
+	}, nil
+}
+
+// TxData fetches the raw transaction data for the coin.
+func (be *backend) TxData(coinID []byte) ([]byte, error) {
+	var b dex.Bytes
+	if err := be.d.request(MethodTxData, &CoinIDParams{CoinID: coinID}, &b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ValidateSecret checks that the secret satisfies the contract.
+func (be *backend) ValidateSecret(secret, contractData []byte) bool {
+	var ok bool
+	err := be.d.request(MethodValidateSecret, &ValidateSecretParams{
+		Secret:       secret,
+		ContractData: contractData,
+	}, &ok)
+	if err != nil {
+		be.log.Errorf("validatesecret error: %v", err)
+		return false
+	}
+	return ok
+}
+
+// Redemption returns the redemption of the contract.
+func (be *backend) Redemption(redemptionID, contractID, contractData []byte) (asset.Coin, error) {
+	ref := &CoinRef{
+		Kind:         CoinKindRedemption,
+		CoinID:       redemptionID,
+		ContractID:   contractID,
+		ContractData: contractData,
+	}
+	var res CoinInfo
+	if err := be.d.request(MethodRedemption, ref, &res); err != nil {
+		return nil, err
+	}
+	return be.newCoin(ref, &res), nil
+}
+
+// CheckSwapAddress checks that the address is valid for a swap.
+func (be *backend) CheckSwapAddress(addr string) bool {
+	var ok bool
+	if err := be.d.request(MethodCheckSwapAddress, &AddressParams{Address: addr}, &ok); err != nil {
+		be.log.Errorf("checkswapaddress error: %v", err)
+		return false
+	}
+	return ok
+}
+
+// ValidateCoinID checks that the coin ID can be decoded.
+func (be *backend) ValidateCoinID(coinID []byte) (string, error) {
+	var s string
+	if err := be.d.request(MethodValidateCoinID, &CoinIDParams{CoinID: coinID}, &s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// ValidateContract ensures that the swap contract is constructed properly.
+func (be *backend) ValidateContract(contract []byte) error {
+	return be.d.request(MethodValidateContract, &ContractParams{Contract: contract}, nil)
+}
+
+// FeeRate returns the current optimal fee rate.
+func (be *backend) FeeRate(ctx context.Context) (uint64, error) {
+	var feeRate uint64
+	if err := be.d.conn.call(ctx, MethodFeeRate, nil, &feeRate); err != nil {
+		return 0, err
+	}
+	return feeRate, nil
+}
+
+// Synced is true when the blockchain is synced.
+func (be *backend) Synced() (bool, error) {
+	var synced bool
+	if err := be.d.request(MethodSynced, nil, &synced); err != nil {
+		return false, err
+	}
+	return synced, nil
+}
+
+// Info provides auxiliary information about the backend.
+func (be *backend) Info() *asset.BackendInfo {
+	return be.info
+}
+
+// ValidateFeeRate checks that the fee rate of the coin's transaction is at
+// least the required fee rate. The coin must have been returned by this
+// backend.
+func (be *backend) ValidateFeeRate(c asset.Coin, reqFeeRate uint64) bool {
+	if contract, is := c.(*asset.Contract); is {
+		c = contract.Coin
+	}
+	pc, is := c.(*coin)
+	if !is {
+		be.log.Errorf("ValidateFeeRate: unknown coin type %T", c)
+		return false
+	}
+	var ok bool
+	err := be.d.request(MethodValidateFeeRate, &ValidateFeeRateParams{
+		Coin:       pc.ref,
+		ReqFeeRate: reqFeeRate,
+	}, &ok)
+	if err != nil {
+		be.log.Errorf("validatefeerate error: %v", err)
+		return false
+	}
+	return ok
+}
+
+// coin is an asset.Coin returned by the plugin.
+type coin struct {
+	be   *backend
+	ref  *CoinRef
+	info *CoinInfo
+}
+
+var _ asset.Coin = (*coin)(nil)
+
+func (be *backend) newCoin(ref *CoinRef, info *CoinInfo) *coin {
+	return &coin{be: be, ref: ref, info: info}
+}
+
+// Confirmations returns the number of confirmations of the coin's
+// transaction.
+func (c *coin) Confirmations(ctx context.Context) (int64, error) {
+	var confs int64
+	if err := c.be.d.conn.call(ctx, MethodConfirmations, c.ref, &confs); err != nil {
+		return -1, err
+	}
+	return confs, nil
+}
+
+// ID is the coin ID.
+func (c *coin) ID() []byte {
+	return c.info.ID
+}
+
+// TxID is the coin's transaction ID.
+func (c *coin) TxID() string {
+	return c.info.TxID
+}
+
+// String is a human readable representation of the coin.
+func (c *coin) String() string {
+	return c.info.String
+}
+
+// Value is the coin value.
+func (c *coin) Value() uint64 {
+	return c.info.Value
+}
+
+// FeeRate is the fee rate of the coin's transaction.
+func (c *coin) FeeRate() uint64 {
+	return c.info.FeeRate
+}
+
+// utxoBackend is the backend for a UTXO-based asset.
+type utxoBackend struct {
+	*backend
+}
+
+var _ asset.OutputTracker = (*utxoBackend)(nil)
+
+// VerifyUnspentCoin checks that the coin exists and is unspent.
+func (be *utxoBackend) VerifyUnspentCoin(ctx context.Context, coinID []byte) error {
+	return be.d.conn.call(ctx, MethodVerifyUnspentCoin, &CoinIDParams{CoinID: coinID}, nil)
+}
+
+// FundingCoin returns the unspent coin.
+func (be *utxoBackend) FundingCoin(ctx context.Context, coinID, redeemScript []byte) (asset.FundingCoin, error) {
+	ref := &CoinRef{
+		Kind:         CoinKindFunding,
+		CoinID:       coinID,
+		RedeemScript: redeemScript,
+	}
+	var res FundingCoinResult
+	if err := be.d.conn.call(ctx, MethodFundingCoin, ref, &res); err != nil {
+		return nil, err
+	}
+	return &fundingCoin{
+		coin:      be.newCoin(ref, &res.CoinInfo),
+		spendSize: res.SpendSize,
+	}, nil
+}
+
+// ValidateOrderFunding validates that the funding coins are enough for the
+// order.
+func (be *utxoBackend) ValidateOrderFunding(swapVal, valSum, inputCount, inputsSize, maxSwaps uint64, nfo *dex.Asset) bool {
+	var ok bool
+	err := be.d.request(MethodValidateOrderFunding, &OrderFundingParams{
+		SwapVal:    swapVal,
+		ValSum:     valSum,
+		InputCount: inputCount,
+		InputsSize: inputsSize,
+		MaxSwaps:   maxSwaps,
+		Asset:      nfo,
+	}, &ok)
+	if err != nil {
+		be.log.Errorf("validateorderfunding error: %v", err)
+		return false
+	}
+	return ok
+}
+
+// fundingCoin is an asset.FundingCoin returned by the plugin.
+type fundingCoin struct {
+	*coin
+	spendSize uint32
+}
+
+var _ asset.FundingCoin = (*fundingCoin)(nil)
+
+// Coin returns the coin.
+func (c *fundingCoin) Coin() asset.Coin {
+	return c.coin
+}
+
+// Auth checks that the owner of the pubkeys can spend the coin.
+func (c *fundingCoin) Auth(pubkeys, sigs [][]byte, msg []byte) error {
+	params := &AuthParams{
+		Coin:    c.ref,
+		PubKeys: make([]dex.Bytes, len(pubkeys)),
+		Sigs:    make([]dex.Bytes, len(sigs)),
+		Msg:     msg,
+	}
+	for i, pk := range pubkeys {
+		params.PubKeys[i] = pk
+	}
+	for i, sig := range sigs {
+		params.Sigs[i] = sig
+	}
+	return c.be.d.request(MethodAuth, params, nil)
+}
+
+// SpendSize is the size of the input that spends the coin.
+func (c *fundingCoin) SpendSize() uint32 {
+	return c.spendSize
+}
+
+// accountBackend is the backend for an account-based asset.
+type accountBackend struct {
+	*backend
+	sizes *TxSizesResult
+}
+
+var _ asset.AccountBalancer = (*accountBackend)(nil)
+
+// AccountBalance retrieves the account balance.
+func (be *accountBackend) AccountBalance(addr string) (uint64, error) {
+	var bal uint64
+	if err := be.d.request(MethodAccountBalance, &AddressParams{Address: addr}, &bal); err != nil {
+		return 0, err
+	}
+	return bal, nil
+}
+
+// ValidateSignature checks the signature and that the pubkey is for the
+// address.
+func (be *accountBackend) ValidateSignature(addr string, pubkey, msg, sig []byte) error {
+	return be.d.request(MethodValidateSignature, &SignatureParams{
+		Address: addr,
+		PubKey:  pubkey,
+		Msg:     msg,
+		Sig:     sig,
+	}, nil)
+}
+
+// RedeemSize is the gas used for a single redemption.
+func (be *accountBackend) RedeemSize() uint64 {
+	return be.sizes.RedeemSize
+}
+
+// InitTxSize is the gas used for a single initiation.
+func (be *accountBackend) InitTxSize() uint64 {
+	return be.sizes.InitTxSize
+}
@@ -0,0 +1,419 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/asset"
+)
+
+// helperEnv is set in the environment of the test binary when it is run as a
+// plugin by TestLoad.
+const helperEnv = "DCRDEX_TEST_PLUGIN"
+
+const tAssetID = 42
+
+var (
+	tLogger   = dex.StdOutLogger("TEST", dex.LevelTrace)
+	tUnitInfo = dex.UnitInfo{
+		AtomicUnit: "atoms",
+		Conventional: dex.Denomination{
+			Unit:             "TEST",
+			ConversionFactor: 1e8,
+		},
+	}
+	tCoinID     = []byte{0x01, 0x02}
+	tContract   = []byte{0x03}
+	tRedeemID   = []byte{0x04}
+	tFundingID  = []byte{0x05}
+	tSecret     = []byte{0x06}
+	tMissingID  = []byte{0xff}
+	tLockTime   = time.UnixMilli(time.Now().UnixMilli())
+	tPubKey     = []byte{0x07}
+	tSig        = []byte{0x08}
+	tAuthMsg    = []byte{0x09}
+	tAddress    = "tAddress"
+	tBlockDelay = 10 * time.Millisecond
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) == "1" {
+		log := dex.NewLogger("PLUGIN", dex.LevelTrace, os.Stderr)
+		if err := Serve(os.Stdin, os.Stdout, &tDriver{}, log); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type tDriver struct{}
+
+func (*tDriver) DecodeCoinID(coinID []byte) (string, error) {
+	return fmt.Sprintf("%x", coinID), nil
+}
+func (*tDriver) Version() uint32                      { return 3 }
+func (*tDriver) UnitInfo() dex.UnitInfo               { return tUnitInfo }
+func (*tDriver) Name() string                         { return "Test Coin" }
+func (*tDriver) MinLotSize(maxFeeRate uint64) uint64  { return maxFeeRate * 100 }
+func (*tDriver) MinBondSize(maxFeeRate uint64) uint64 { return maxFeeRate * 200 }
+func (*tDriver) Setup(cfg *asset.BackendConfig) (asset.Backend, error) {
+	if cfg.ConfigPath != "test.conf" {
+		return nil, fmt.Errorf("wrong config path %q", cfg.ConfigPath)
+	}
+	return &tBackend{blockChans: make(map[chan *asset.BlockUpdate]struct{})}, nil
+}
+
+type tCoin struct {
+	id []byte
+}
+
+func (c *tCoin) Confirmations(context.Context) (int64, error) { return 2, nil }
+func (c *tCoin) ID() []byte                                   { return c.id }
+func (c *tCoin) TxID() string                                 { return fmt.Sprintf("%x", c.id) }
+func (c *tCoin) String() string                               { return fmt.Sprintf("%x:0", c.id) }
+func (c *tCoin) Value() uint64                                { return 1e8 }
+func (c *tCoin) FeeRate() uint64                              { return 10 }
+
+type tFundingCoin struct {
+	*tCoin
+}
+
+func (c *tFundingCoin) Coin() asset.Coin { return c.tCoin }
+func (c *tFundingCoin) Auth(pubkeys, sigs [][]byte, msg []byte) error {
+	if len(pubkeys) != 1 || !bytes.Equal(pubkeys[0], tPubKey) || len(sigs) != 1 ||
+		!bytes.Equal(sigs[0], tSig) || !bytes.Equal(msg, tAuthMsg) {
+		return errors.New("auth failed")
+	}
+	return nil
+}
+func (c *tFundingCoin) SpendSize() uint32 { return 150 }
+
+type tBackend struct {
+	mtx        sync.Mutex
+	blockChans map[chan *asset.BlockUpdate]struct{}
+}
+
+func (be *tBackend) Connect(ctx context.Context) (*sync.WaitGroup, error) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Send a block update once connected.
+		select {
+		case <-time.After(tBlockDelay):
+		case <-ctx.Done():
+			return
+		}
+		be.mtx.Lock()
+		for c := range be.blockChans {
+			c <- &asset.BlockUpdate{Reorg: true}
+		}
+		be.mtx.Unlock()
+		<-ctx.Done()
+	}()
+	return &wg, nil
+}
+
+func (be *tBackend) Contract(coinID, contractData []byte) (*asset.Contract, error) {
+	if !bytes.Equal(coinID, tCoinID) {
+		return nil, asset.CoinNotFoundError
+	}
+	return &asset.Contract{
+		Coin:         &tCoin{id: coinID},
+		SwapAddress:  tAddress,
+		ContractData: contractData,
+		SecretHash:   []byte{0x0a},
+		LockTime:     tLockTime,
+	}, nil
+}
+func (be *tBackend) TxData(coinID []byte) ([]byte, error) { return coinID, nil }
+func (be *tBackend) ValidateSecret(secret, contractData []byte) bool {
+	return bytes.Equal(secret, tSecret) && bytes.Equal(contractData, tContract)
+}
+func (be *tBackend) Redemption(redemptionID, contractID, contractData []byte) (asset.Coin, error) {
+	if !bytes.Equal(contractID, tCoinID) {
+		return nil, asset.CoinNotFoundError
+	}
+	return &tCoin{id: redemptionID}, nil
+}
+func (be *tBackend) BlockChannel(size int) <-chan *asset.BlockUpdate {
+	c := make(chan *asset.BlockUpdate, size)
+	be.mtx.Lock()
+	be.blockChans[c] = struct{}{}
+	be.mtx.Unlock()
+	return c
+}
+func (be *tBackend) CheckSwapAddress(addr string) bool { return addr == tAddress }
+func (be *tBackend) ValidateCoinID(coinID []byte) (string, error) {
+	return fmt.Sprintf("%x", coinID), nil
+}
+func (be *tBackend) ValidateContract(contract []byte) error {
+	if !bytes.Equal(contract, tContract) {
+		return errors.New("bad contract")
+	}
+	return nil
+}
+func (be *tBackend) FeeRate(context.Context) (uint64, error) { return 12, nil }
+func (be *tBackend) Synced() (bool, error)                   { return true, nil }
+func (be *tBackend) Info() *asset.BackendInfo {
+	return &asset.BackendInfo{SupportsDynamicTxFee: true}
+}
+func (be *tBackend) ValidateFeeRate(coin asset.Coin, reqFeeRate uint64) bool {
+	return coin.FeeRate() >= reqFeeRate
+}
+func (be *tBackend) VerifyUnspentCoin(ctx context.Context, coinID []byte) error {
+	if !bytes.Equal(coinID, tFundingID) {
+		return asset.CoinNotFoundError
+	}
+	return nil
+}
+func (be *tBackend) FundingCoin(ctx context.Context, coinID, redeemScript []byte) (asset.FundingCoin, error) {
+	if !bytes.Equal(coinID, tFundingID) {
+		return nil, asset.CoinNotFoundError
+	}
+	return &tFundingCoin{&tCoin{id: coinID}}, nil
+}
+func (be *tBackend) ValidateOrderFunding(swapVal, valSum, inputCount, inputsSize, maxSwaps uint64, nfo *dex.Asset) bool {
+	return valSum >= swapVal && nfo.ID == tAssetID
+}
+
+// newTestDriver connects a Driver to a plugin served in-process.
+func newTestDriver(t *testing.T) (*Driver, <-chan error) {
+	t.Helper()
+	srvR, drvW := io.Pipe()
+	drvR, srvW := io.Pipe()
+	served := make(chan error, 1)
+	go func() {
+		err := Serve(srvR, srvW, &tDriver{}, tLogger.SubLogger("PLUGIN"))
+		srvW.Close()
+		served <- err
+	}()
+	d := newDriver(tAssetID, dex.Simnet, tLogger, drvR, drvW, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := d.handshake(ctx); err != nil {
+		t.Fatalf("handshake error: %v", err)
+	}
+	return d, served
+}
+
+func TestPlugin(t *testing.T) {
+	d, served := newTestDriver(t)
+
+	if d.Name() != "Test Coin" || d.Version() != 3 || d.UnitInfo().Conventional.ConversionFactor != 1e8 {
+		t.Fatalf("wrong driver info %+v", d.info)
+	}
+	if s, err := d.DecodeCoinID(tCoinID); err != nil || s != "0102" {
+		t.Fatalf("DecodeCoinID = %q, %v", s, err)
+	}
+	if lot, bond, err := d.Minimums(5); err != nil || lot != 500 || bond != 1000 {
+		t.Fatalf("wrong minimums %d, %d, %v", lot, bond, err)
+	}
+	if _, _, err := d.Minimums(0); err == nil {
+		t.Fatalf("no error for zero minimums")
+	}
+
+	cfg := &asset.BackendConfig{
+		AssetID:    tAssetID,
+		ConfigPath: "test.conf",
+		Logger:     tLogger,
+		Net:        dex.Simnet,
+	}
+	if _, err := d.Setup(&asset.BackendConfig{AssetID: tAssetID, ConfigPath: "bad.conf", Net: dex.Simnet}); err == nil {
+		t.Fatalf("no error for plugin setup error")
+	}
+	be, err := d.Setup(cfg)
+	if err != nil {
+		t.Fatalf("Setup error: %v", err)
+	}
+	if _, err = d.Setup(cfg); err == nil {
+		t.Fatalf("no error for second Setup")
+	}
+	ot, is := be.(asset.OutputTracker)
+	if !is {
+		t.Fatalf("UTXO backend is not an OutputTracker")
+	}
+	if _, is = be.(asset.AccountBalancer); is {
+		t.Fatalf("UTXO backend is an AccountBalancer")
+	}
+	if !be.Info().SupportsDynamicTxFee {
+		t.Fatalf("wrong backend info")
+	}
+
+	blockChan := be.BlockChannel(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wg, err := be.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	select {
+	case u := <-blockChan:
+		if u.Err != nil || !u.Reorg {
+			t.Fatalf("wrong block update %+v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no block update")
+	}
+
+	// Contract
+	contract, err := be.Contract(tCoinID, tContract)
+	if err != nil {
+		t.Fatalf("Contract error: %v", err)
+	}
+	if !bytes.Equal(contract.ID(), tCoinID) || contract.TxID() != "0102" || contract.Value() != 1e8 ||
+		contract.SwapAddress != tAddress || !bytes.Equal(contract.ContractData, tContract) ||
+		!contract.LockTime.Equal(tLockTime) {
+		t.Fatalf("wrong contract %+v", contract)
+	}
+	if confs, err := contract.Confirmations(ctx); err != nil || confs != 2 {
+		t.Fatalf("Confirmations = %d, %v", confs, err)
+	}
+	if !be.ValidateFeeRate(contract.Coin, 10) || be.ValidateFeeRate(contract.Coin, 11) {
+		t.Fatalf("wrong ValidateFeeRate result")
+	}
+	if _, err = be.Contract(tMissingID, tContract); !errors.Is(err, asset.CoinNotFoundError) {
+		t.Fatalf("expected CoinNotFoundError, got %v", err)
+	}
+
+	// Redemption
+	redemption, err := be.Redemption(tRedeemID, tCoinID, tContract)
+	if err != nil {
+		t.Fatalf("Redemption error: %v", err)
+	}
+	if !bytes.Equal(redemption.ID(), tRedeemID) {
+		t.Fatalf("wrong redemption ID")
+	}
+	if confs, err := redemption.Confirmations(ctx); err != nil || confs != 2 {
+		t.Fatalf("redemption Confirmations = %d, %v", confs, err)
+	}
+
+	if b, err := be.TxData(tCoinID); err != nil || !bytes.Equal(b, tCoinID) {
+		t.Fatalf("TxData = %x, %v", b, err)
+	}
+	if !be.ValidateSecret(tSecret, tContract) || be.ValidateSecret(tSecret, nil) {
+		t.Fatalf("wrong ValidateSecret result")
+	}
+	if !be.CheckSwapAddress(tAddress) || be.CheckSwapAddress("x") {
+		t.Fatalf("wrong CheckSwapAddress result")
+	}
+	if err = be.ValidateContract(tContract); err != nil {
+		t.Fatalf("ValidateContract error: %v", err)
+	}
+	if err = be.ValidateContract(nil); err == nil {
+		t.Fatalf("no error for bad contract")
+	}
+	if feeRate, err := be.FeeRate(ctx); err != nil || feeRate != 12 {
+		t.Fatalf("FeeRate = %d, %v", feeRate, err)
+	}
+	if synced, err := be.Synced(); err != nil || !synced {
+		t.Fatalf("Synced = %t, %v", synced, err)
+	}
+
+	// OutputTracker
+	if err = ot.VerifyUnspentCoin(ctx, tFundingID); err != nil {
+		t.Fatalf("VerifyUnspentCoin error: %v", err)
+	}
+	if err = ot.VerifyUnspentCoin(ctx, tMissingID); !errors.Is(err, asset.CoinNotFoundError) {
+		t.Fatalf("expected CoinNotFoundError, got %v", err)
+	}
+	fc, err := ot.FundingCoin(ctx, tFundingID, nil)
+	if err != nil {
+		t.Fatalf("FundingCoin error: %v", err)
+	}
+	if fc.SpendSize() != 150 || !bytes.Equal(fc.Coin().ID(), tFundingID) {
+		t.Fatalf("wrong funding coin")
+	}
+	if err = fc.Auth([][]byte{tPubKey}, [][]byte{tSig}, tAuthMsg); err != nil {
+		t.Fatalf("Auth error: %v", err)
+	}
+	if err = fc.Auth([][]byte{tPubKey}, [][]byte{tPubKey}, tAuthMsg); err == nil {
+		t.Fatalf("no error for bad signature")
+	}
+	if !ot.ValidateOrderFunding(1, 2, 1, 100, 1, &dex.Asset{ID: tAssetID}) {
+		t.Fatalf("wrong ValidateOrderFunding result")
+	}
+
+	cancel()
+	wg.Wait()
+	select {
+	case err = <-served:
+		if err != nil {
+			t.Fatalf("Serve error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Serve did not return")
+	}
+	if _, err = d.DecodeCoinID(tCoinID); !errors.Is(err, errConnClosed) {
+		t.Fatalf("expected errConnClosed after shutdown, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("no executable: %v", err)
+	}
+	t.Setenv(helperEnv, "1")
+	d, err := Load(tAssetID, exe, nil, dex.Simnet, tLogger)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if v, err := asset.Version(tAssetID); err != nil || v != 3 {
+		t.Fatalf("driver not registered: %d, %v", v, err)
+	}
+	if _, err = Load(tAssetID, exe, nil, dex.Simnet, tLogger); err == nil {
+		t.Fatalf("no error for second Load")
+	}
+	be, err := asset.Setup(&asset.BackendConfig{
+		AssetID:    tAssetID,
+		ConfigPath: "test.conf",
+		Logger:     tLogger,
+		Net:        dex.Simnet,
+	})
+	if err != nil {
+		t.Fatalf("Setup error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	wg, err := be.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	if feeRate, err := be.FeeRate(ctx); err != nil || feeRate != 12 {
+		t.Fatalf("FeeRate = %d, %v", feeRate, err)
+	}
+	cancel()
+	wg.Wait()
+	select {
+	case <-d.done:
+	case <-time.After(time.Second):
+		t.Fatalf("plugin did not exit")
+	}
+
+	// Unloading unregisters the driver, so the plugin can be loaded again.
+	d.Unload()
+	if _, err = asset.Version(tAssetID); err == nil {
+		t.Fatalf("driver still registered after Unload")
+	}
+	d, err = Load(tAssetID, exe, nil, dex.Simnet, tLogger)
+	if err != nil {
+		t.Fatalf("Load error after Unload: %v", err)
+	}
+	// An unused plugin is stopped by Unload.
+	d.Unload()
+	select {
+	case <-d.done:
+	case <-time.After(time.Second):
+		t.Fatalf("plugin did not exit after Unload")
+	}
+}
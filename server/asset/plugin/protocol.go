@@ -0,0 +1,297 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package plugin runs asset backends as external processes. The server starts
+// the plugin executable for an asset and communicates with it over the
+// plugin's stdin and stdout with the protocol defined here, so that a backend
+// for a new asset does not need to be compiled into the server. The plugin's
+// stderr is written to the server's log.
+//
+// Messages are newline-delimited JSON objects, using the Message type in both
+// directions. A request has a non-zero ID and a Method, and is answered by a
+// response with the same ID and either a Result or an Error. A notification
+// has a Method but no ID, and is not answered. Only the plugin sends
+// notifications, and only the server sends requests.
+//
+// The server begins with a handshake request, followed by a setup request and
+// a connect request. After connect, the plugin sends a block notification for
+// each new block. The server sends a shutdown request when it stops, and kills
+// the plugin if it does not exit promptly. The plugin should also exit when its
+// stdin is closed.
+//
+// A plugin written in Go can implement the server/asset.Driver interface, and
+// pass it to Serve, which implements the plugin side of the protocol.
+package plugin
+
+import (
+	"encoding/json"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/txfee"
+)
+
+// ProtocolVersion is the version of the plugin protocol. The plugin must
+// respond to the handshake with the same version.
+const ProtocolVersion = 0
+
+// Request methods. The params and result of each method are documented with
+// the method.
+const (
+	// MethodHandshake params: HandshakeParams, result: HandshakeResult.
+	MethodHandshake = "handshake"
+	// MethodSetup creates the backend. params: SetupParams, result:
+	// SetupResult.
+	MethodSetup = "setup"
+	// MethodConnect connects the backend, after which the plugin sends block
+	// notifications. params: null, result: null.
+	MethodConnect = "connect"
+	// MethodShutdown disconnects the backend. The plugin should exit after
+	// responding. params: null, result: null.
+	MethodShutdown = "shutdown"
+
+	// MethodDecodeCoinID params: CoinIDParams, result: string.
+	MethodDecodeCoinID = "decodecoinid"
+	// MethodMinimums params: MinimumsParams, result: MinimumsResult.
+	MethodMinimums = "minimums"
+
+	// MethodContract params: CoinRef (Kind CoinKindContract), result:
+	// ContractResult.
+	MethodContract = "contract"
+	// MethodTxData params: CoinIDParams, result: hex string.
+	MethodTxData = "txdata"
+	// MethodValidateSecret params: ValidateSecretParams, result: bool.
+	MethodValidateSecret = "validatesecret"
+	// MethodRedemption params: CoinRef (Kind CoinKindRedemption), result:
+	// CoinInfo.
+	MethodRedemption = "redemption"
+	// MethodCheckSwapAddress params: AddressParams, result: bool.
+	MethodCheckSwapAddress = "checkswapaddress"
+	// MethodValidateCoinID params: CoinIDParams, result: string.
+	MethodValidateCoinID = "validatecoinid"
+	// MethodValidateContract params: ContractParams, result: null.
+	MethodValidateContract = "validatecontract"
+	// MethodFeeRate params: null, result: uint64.
+	MethodFeeRate = "feerate"
+	// MethodSynced params: null, result: bool.
+	MethodSynced = "synced"
+	// MethodValidateFeeRate params: ValidateFeeRateParams, result: bool.
+	MethodValidateFeeRate = "validatefeerate"
+	// MethodConfirmations params: CoinRef, result: int64.
+	MethodConfirmations = "confirmations"
+
+	// The following methods are for UTXO-based assets.
+
+	// MethodVerifyUnspentCoin params: CoinIDParams, result: null.
+	MethodVerifyUnspentCoin = "verifyunspentcoin"
+	// MethodFundingCoin params: CoinRef (Kind CoinKindFunding), result:
+	// FundingCoinResult.
+	MethodFundingCoin = "fundingcoin"
+	// MethodAuth authenticates ownership of a funding coin. params:
+	// AuthParams, result: null.
+	MethodAuth = "auth"
+	// MethodValidateOrderFunding params: OrderFundingParams, result: bool.
+	MethodValidateOrderFunding = "validateorderfunding"
+
+	// The following methods are for account-based assets.
+
+	// MethodAccountBalance params: AddressParams, result: uint64.
+	MethodAccountBalance = "accountbalance"
+	// MethodValidateSignature params: SignatureParams, result: null.
+	MethodValidateSignature = "validatesignature"
+	// MethodTxSizes params: null, result: TxSizesResult.
+	MethodTxSizes = "txsizes"
+)
+
+// Notification methods.
+const (
+	// NotifyBlock params: BlockParams.
+	NotifyBlock = "block"
+)
+
+// Error codes. Errors from the backend with the asset package's error kinds
+// are sent with these codes so that the server can recognize them.
+const (
+	ErrCodeUnknown        = 1
+	ErrCodeCoinNotFound   = 2
+	ErrCodeRequestTimeout = 3
+	ErrCodeUnknownMethod  = 4
+	ErrCodeBadParams      = 5
+)
+
+// Message is a request, response, or notification.
+type Message struct {
+	ID     uint64          `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// Error is the error of a response.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error satisfies the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// HandshakeParams are the params of the handshake request.
+type HandshakeParams struct {
+	ProtocolVersion uint32 `json:"protocolVersion"`
+	AssetID         uint32 `json:"assetID"`
+	Network         string `json:"network"`
+}
+
+// HandshakeResult describes the plugin's asset.
+type HandshakeResult struct {
+	ProtocolVersion uint32       `json:"protocolVersion"`
+	Name            string       `json:"name"`
+	Version         uint32       `json:"version"`
+	UnitInfo        dex.UnitInfo `json:"unitInfo"`
+	// Dust is the asset's dust policy, if it has one.
+	Dust *dex.DustPolicy `json:"dust,omitempty"`
+}
+
+// SetupParams are the params of the setup request.
+type SetupParams struct {
+	ConfigPath string `json:"configPath"`
+	RelayAddr  string `json:"relayAddr,omitempty"`
+	// FeeOracle is the asset's fee oracle configuration, if any.
+	FeeOracle *txfee.OracleConfig `json:"feeOracle,omitempty"`
+}
+
+// SetupResult describes the backend. Exactly one of UTXO or Account must be
+// true.
+type SetupResult struct {
+	UTXO    bool `json:"utxo"`
+	Account bool `json:"account"`
+	// SupportsDynamicTxFee is the backend's asset.BackendInfo.
+	SupportsDynamicTxFee bool `json:"supportsDynamicTxFee"`
+}
+
+// CoinIDParams identify a coin.
+type CoinIDParams struct {
+	CoinID dex.Bytes `json:"coinID"`
+}
+
+// MinimumsParams are the params of the minimums request.
+type MinimumsParams struct {
+	MaxFeeRate uint64 `json:"maxFeeRate"`
+}
+
+// MinimumsResult is the result of the minimums request.
+type MinimumsResult struct {
+	MinLotSize  uint64 `json:"minLotSize"`
+	MinBondSize uint64 `json:"minBondSize"`
+}
+
+// Coin kinds for a CoinRef.
+const (
+	CoinKindContract   = "contract"
+	CoinKindRedemption = "redemption"
+	CoinKindFunding    = "funding"
+)
+
+// CoinRef is a reference to a coin returned by the contract, redemption, or
+// fundingcoin requests. The server uses a CoinRef to ask about a coin
+// afterwards, e.g. its confirmations, so the plugin does not need to track
+// the coins it has returned. The plugin looks the coin up again, and returns
+// an error if it is no longer valid.
+type CoinRef struct {
+	Kind   string    `json:"kind"`
+	CoinID dex.Bytes `json:"coinID"`
+	// ContractData is set for the contract and redemption kinds.
+	ContractData dex.Bytes `json:"contractData,omitempty"`
+	// ContractID is set for the redemption kind.
+	ContractID dex.Bytes `json:"contractID,omitempty"`
+	// RedeemScript is set for the funding kind.
+	RedeemScript dex.Bytes `json:"redeemScript,omitempty"`
+}
+
+// CoinInfo describes a coin.
+type CoinInfo struct {
+	ID      dex.Bytes `json:"id"`
+	TxID    string    `json:"txid"`
+	String  string    `json:"string"`
+	Value   uint64    `json:"value"`
+	FeeRate uint64    `json:"feeRate"`
+}
+
+// ContractResult is the result of the contract request.
+type ContractResult struct {
+	CoinInfo
+	SwapAddress  string    `json:"swapAddress"`
+	ContractData dex.Bytes `json:"contractData"`
+	SecretHash   dex.Bytes `json:"secretHash"`
+	LockTime     int64     `json:"lockTime"` // unix ms
+	TxData       dex.Bytes `json:"txData"`
+}
+
+// ValidateSecretParams are the params of the validatesecret request.
+type ValidateSecretParams struct {
+	Secret       dex.Bytes `json:"secret"`
+	ContractData dex.Bytes `json:"contractData"`
+}
+
+// AddressParams identify an address.
+type AddressParams struct {
+	Address string `json:"address"`
+}
+
+// ContractParams are the params of the validatecontract request.
+type ContractParams struct {
+	Contract dex.Bytes `json:"contract"`
+}
+
+// ValidateFeeRateParams are the params of the validatefeerate request.
+type ValidateFeeRateParams struct {
+	Coin       *CoinRef `json:"coin"`
+	ReqFeeRate uint64   `json:"reqFeeRate"`
+}
+
+// FundingCoinResult is the result of the fundingcoin request.
+type FundingCoinResult struct {
+	CoinInfo
+	SpendSize uint32 `json:"spendSize"`
+}
+
+// AuthParams are the params of the auth request.
+type AuthParams struct {
+	Coin    *CoinRef    `json:"coin"`
+	PubKeys []dex.Bytes `json:"pubkeys"`
+	Sigs    []dex.Bytes `json:"sigs"`
+	Msg     dex.Bytes   `json:"msg"`
+}
+
+// OrderFundingParams are the params of the validateorderfunding request.
+type OrderFundingParams struct {
+	SwapVal    uint64     `json:"swapVal"`
+	ValSum     uint64     `json:"valSum"`
+	InputCount uint64     `json:"inputCount"`
+	InputsSize uint64     `json:"inputsSize"`
+	MaxSwaps   uint64     `json:"maxSwaps"`
+	Asset      *dex.Asset `json:"asset"`
+}
+
+// SignatureParams are the params of the validatesignature request.
+type SignatureParams struct {
+	Address string    `json:"address"`
+	PubKey  dex.Bytes `json:"pubkey"`
+	Msg     dex.Bytes `json:"msg"`
+	Sig     dex.Bytes `json:"sig"`
+}
+
+// TxSizesResult is the result of the txsizes request.
+type TxSizesResult struct {
+	RedeemSize uint64 `json:"redeemSize"`
+	InitTxSize uint64 `json:"initTxSize"`
+}
+
+// BlockParams are the params of the block notification.
+type BlockParams struct {
+	Error string `json:"error,omitempty"`
+	Reorg bool   `json:"reorg,omitempty"`
+}
@@ -0,0 +1,459 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/asset"
+)
+
+// blockChanSize is the buffer size of the backend's block channel.
+const blockChanSize = 32
+
+// minValuer is implemented by drivers for assets with minimum lot and bond
+// sizes that vary with fee rate.
+type minValuer interface {
+	MinBondSize(maxFeeRate uint64) uint64
+	MinLotSize(maxFeeRate uint64) uint64
+}
+
+// dustPolicier is implemented by drivers for assets with a dust policy.
+type dustPolicier interface {
+	DustPolicy() *dex.DustPolicy
+}
+
+// server is the plugin side of the connection.
+type server struct {
+	drv  asset.Driver
+	log  dex.Logger
+	conn *conn
+
+	mtx     sync.RWMutex
+	assetID uint32
+	net     dex.Network
+	be      asset.Backend
+	cancel  context.CancelFunc
+	wg      *sync.WaitGroup
+
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+// Serve runs the plugin side of the protocol for the backend created by drv,
+// reading requests from r and writing to w. A plugin calls Serve with
+// os.Stdin and os.Stdout, and logs to os.Stderr. Serve returns after the
+// shutdown request, or when r is closed.
+func Serve(r io.Reader, w io.Writer, drv asset.Driver, log dex.Logger) error {
+	s := &server{
+		drv:      drv,
+		log:      log,
+		conn:     newConn(r, w),
+		shutdown: make(chan struct{}),
+	}
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- s.conn.run(func(msg *Message) {
+			go s.handleRequest(msg)
+		})
+	}()
+	var err error
+	select {
+	case err = <-runErr:
+	case <-s.shutdown:
+	}
+	s.disconnect()
+	return err
+}
+
+// disconnect disconnects the backend if it is connected.
+func (s *server) disconnect() {
+	s.mtx.Lock()
+	cancel, wg := s.cancel, s.wg
+	s.cancel, s.wg = nil, nil
+	s.mtx.Unlock()
+	if cancel != nil {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func (s *server) handleRequest(msg *Message) {
+	if msg.ID == 0 {
+		s.log.Warnf("Unexpected notification %q", msg.Method)
+		return
+	}
+	result, err := s.dispatch(msg)
+	if err != nil {
+		s.log.Debugf("%s error: %v", msg.Method, err)
+	}
+	if err := s.conn.respond(msg.ID, result, err); err != nil {
+		s.log.Errorf("Error sending %s response: %v", msg.Method, err)
+	}
+	if msg.Method == MethodShutdown {
+		s.shutdownOnce.Do(func() { close(s.shutdown) })
+	}
+}
+
+// decodeParams decodes the params of the request.
+func decodeParams(msg *Message, params any) error {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &Error{Code: ErrCodeBadParams, Message: fmt.Sprintf("error decoding params: %v", err)}
+	}
+	return nil
+}
+
+// backend is the backend created by the setup request.
+func (s *server) backend() (asset.Backend, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if s.be == nil {
+		return nil, errors.New("backend not set up")
+	}
+	return s.be, nil
+}
+
+func (s *server) dispatch(msg *Message) (any, error) {
+	switch msg.Method {
+	case MethodHandshake:
+		return s.handshake(msg)
+	case MethodSetup:
+		return s.setup(msg)
+	case MethodShutdown:
+		return nil, nil
+	case MethodDecodeCoinID:
+		var params CoinIDParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.drv.DecodeCoinID(params.CoinID)
+	case MethodMinimums:
+		var params MinimumsParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		res := &MinimumsResult{MinLotSize: 1, MinBondSize: 1}
+		if m, is := s.drv.(minValuer); is {
+			res.MinLotSize = m.MinLotSize(params.MaxFeeRate)
+			res.MinBondSize = m.MinBondSize(params.MaxFeeRate)
+		}
+		return res, nil
+	}
+
+	be, err := s.backend()
+	if err != nil {
+		return nil, err
+	}
+	// Requests from the server time out after requestTimeout.
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	switch msg.Method {
+	case MethodConnect:
+		return nil, s.connect(be)
+	case MethodContract:
+		var ref CoinRef
+		if err := decodeParams(msg, &ref); err != nil {
+			return nil, err
+		}
+		contract, err := be.Contract(ref.CoinID, ref.ContractData)
+		if err != nil {
+			return nil, err
+		}
+		return &ContractResult{
+			CoinInfo:     *coinInfo(contract.Coin),
+			SwapAddress:  contract.SwapAddress,
+			ContractData: contract.ContractData,
+			SecretHash:   contract.SecretHash,
+			LockTime:     contract.LockTime.UnixMilli(),
+			TxData:       contract.TxData,
+		}, nil
+	case MethodTxData:
+		var params CoinIDParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		b, err := be.TxData(params.CoinID)
+		if err != nil {
+			return nil, err
+		}
+		return dex.Bytes(b), nil
+	case MethodValidateSecret:
+		var params ValidateSecretParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return be.ValidateSecret(params.Secret, params.ContractData), nil
+	case MethodRedemption:
+		var ref CoinRef
+		if err := decodeParams(msg, &ref); err != nil {
+			return nil, err
+		}
+		c, err := be.Redemption(ref.CoinID, ref.ContractID, ref.ContractData)
+		if err != nil {
+			return nil, err
+		}
+		return coinInfo(c), nil
+	case MethodCheckSwapAddress:
+		var params AddressParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return be.CheckSwapAddress(params.Address), nil
+	case MethodValidateCoinID:
+		var params CoinIDParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return be.ValidateCoinID(params.CoinID)
+	case MethodValidateContract:
+		var params ContractParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, be.ValidateContract(params.Contract)
+	case MethodFeeRate:
+		return be.FeeRate(ctx)
+	case MethodSynced:
+		return be.Synced()
+	case MethodValidateFeeRate:
+		var params ValidateFeeRateParams
+		if err := decodeParams(msg, &params); err != nil {
+			return nil, err
+		}
+		c, _, err := s.coin(ctx, be, params.Coin)
+		if err != nil {
+			return nil, err
+		}
+		return be.ValidateFeeRate(c, params.ReqFeeRate), nil
+	case MethodConfirmations:
+		var ref CoinRef
+		if err := decodeParams(msg, &ref); err != nil {
+			return nil, err
+		}
+		c, _, err := s.coin(ctx, be, &ref)
+		if err != nil {
+			return nil, err
+		}
+		return c.Confirmations(ctx)
+	}
+
+	if ot, is := be.(asset.OutputTracker); is {
+		switch msg.Method {
+		case MethodVerifyUnspentCoin:
+			var params CoinIDParams
+			if err := decodeParams(msg, &params); err != nil {
+				return nil, err
+			}
+			return nil, ot.VerifyUnspentCoin(ctx, params.CoinID)
+		case MethodFundingCoin:
+			var ref CoinRef
+			if err := decodeParams(msg, &ref); err != nil {
+				return nil, err
+			}
+			fc, err := ot.FundingCoin(ctx, ref.CoinID, ref.RedeemScript)
+			if err != nil {
+				return nil, err
+			}
+			return &FundingCoinResult{
+				CoinInfo:  *coinInfo(fc.Coin()),
+				SpendSize: fc.SpendSize(),
+			}, nil
+		case MethodAuth:
+			var params AuthParams
+			if err := decodeParams(msg, &params); err != nil {
+				return nil, err
+			}
+			_, fc, err := s.coin(ctx, be, params.Coin)
+			if err != nil {
+				return nil, err
+			}
+			if fc == nil {
+				return nil, &Error{Code: ErrCodeBadParams, Message: "not a funding coin"}
+			}
+			pubkeys := make([][]byte, len(params.PubKeys))
+			for i, pk := range params.PubKeys {
+				pubkeys[i] = pk
+			}
+			sigs := make([][]byte, len(params.Sigs))
+			for i, sig := range params.Sigs {
+				sigs[i] = sig
+			}
+			return nil, fc.Auth(pubkeys, sigs, params.Msg)
+		case MethodValidateOrderFunding:
+			var p OrderFundingParams
+			if err := decodeParams(msg, &p); err != nil {
+				return nil, err
+			}
+			if p.Asset == nil {
+				return nil, &Error{Code: ErrCodeBadParams, Message: "no asset"}
+			}
+			return ot.ValidateOrderFunding(p.SwapVal, p.ValSum, p.InputCount, p.InputsSize, p.MaxSwaps, p.Asset), nil
+		}
+	}
+
+	if ab, is := be.(asset.AccountBalancer); is {
+		switch msg.Method {
+		case MethodAccountBalance:
+			var params AddressParams
+			if err := decodeParams(msg, &params); err != nil {
+				return nil, err
+			}
+			return ab.AccountBalance(params.Address)
+		case MethodValidateSignature:
+			var params SignatureParams
+			if err := decodeParams(msg, &params); err != nil {
+				return nil, err
+			}
+			return nil, ab.ValidateSignature(params.Address, params.PubKey, params.Msg, params.Sig)
+		case MethodTxSizes:
+			return &TxSizesResult{
+				RedeemSize: ab.RedeemSize(),
+				InitTxSize: ab.InitTxSize(),
+			}, nil
+		}
+	}
+
+	return nil, &Error{Code: ErrCodeUnknownMethod, Message: fmt.Sprintf("unknown method %q", msg.Method)}
+}
+
+func (s *server) handshake(msg *Message) (*HandshakeResult, error) {
+	var params HandshakeParams
+	if err := decodeParams(msg, &params); err != nil {
+		return nil, err
+	}
+	if params.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("protocol version %d not supported. expected %d",
+			params.ProtocolVersion, ProtocolVersion)
+	}
+	net, err := dex.NetFromString(params.Network)
+	if err != nil {
+		return nil, err
+	}
+	s.mtx.Lock()
+	s.assetID, s.net = params.AssetID, net
+	s.mtx.Unlock()
+	res := &HandshakeResult{
+		ProtocolVersion: ProtocolVersion,
+		Name:            s.drv.Name(),
+		Version:         s.drv.Version(),
+		UnitInfo:        s.drv.UnitInfo(),
+	}
+	if d, is := s.drv.(dustPolicier); is {
+		res.Dust = d.DustPolicy()
+	}
+	return res, nil
+}
+
+func (s *server) setup(msg *Message) (*SetupResult, error) {
+	var params SetupParams
+	if err := decodeParams(msg, &params); err != nil {
+		return nil, err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.be != nil {
+		return nil, errors.New("backend already set up")
+	}
+	be, err := s.drv.Setup(&asset.BackendConfig{
+		AssetID:    s.assetID,
+		ConfigPath: params.ConfigPath,
+		Logger:     s.log,
+		Net:        s.net,
+		RelayAddr:  params.RelayAddr,
+		FeeOracle:  params.FeeOracle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &SetupResult{SupportsDynamicTxFee: be.Info().SupportsDynamicTxFee}
+	_, res.UTXO = be.(asset.OutputTracker)
+	_, res.Account = be.(asset.AccountBalancer)
+	s.be = be
+	return res, nil
+}
+
+// connect connects the backend and starts sending block notifications.
+func (s *server) connect(be asset.Backend) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.cancel != nil {
+		return errors.New("already connected")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	beWG, err := be.Connect(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	blockChan := be.BlockChannel(blockChanSize)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case u := <-blockChan:
+				params := &BlockParams{Reorg: u.Reorg}
+				if u.Err != nil {
+					params.Error = u.Err.Error()
+				}
+				if err := s.conn.notify(NotifyBlock, params); err != nil {
+					s.log.Errorf("Error sending block notification: %v", err)
+				}
+			case <-ctx.Done():
+				beWG.Wait()
+				return
+			}
+		}
+	}()
+	s.cancel, s.wg = cancel, &wg
+	return nil
+}
+
+// coin finds the coin for the CoinRef. If the coin is a funding coin, the
+// asset.FundingCoin is also returned.
+func (s *server) coin(ctx context.Context, be asset.Backend, ref *CoinRef) (asset.Coin, asset.FundingCoin, error) {
+	if ref == nil {
+		return nil, nil, &Error{Code: ErrCodeBadParams, Message: "no coin"}
+	}
+	switch ref.Kind {
+	case CoinKindContract:
+		contract, err := be.Contract(ref.CoinID, ref.ContractData)
+		if err != nil {
+			return nil, nil, err
+		}
+		return contract.Coin, nil, nil
+	case CoinKindRedemption:
+		c, err := be.Redemption(ref.CoinID, ref.ContractID, ref.ContractData)
+		return c, nil, err
+	case CoinKindFunding:
+		ot, is := be.(asset.OutputTracker)
+		if !is {
+			return nil, nil, &Error{Code: ErrCodeBadParams, Message: "funding coins are for UTXO-based assets"}
+		}
+		fc, err := ot.FundingCoin(ctx, ref.CoinID, ref.RedeemScript)
+		if err != nil {
+			return nil, nil, err
+		}
+		return fc.Coin(), fc, nil
+	}
+	return nil, nil, &Error{Code: ErrCodeBadParams, Message: fmt.Sprintf("unknown coin kind %q", ref.Kind)}
+}
+
+func coinInfo(c asset.Coin) *CoinInfo {
+	return &CoinInfo{
+		ID:      c.ID(),
+		TxID:    c.TxID(),
+		String:  c.String(),
+		Value:   c.Value(),
+		FeeRate: c.FeeRate(),
+	}
+}
@@ -145,6 +145,7 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 		NumericGetRawRPC:     true,
 		ShieldedIO:           shieldedIO,
 		RelayAddr:            cfg.RelayAddr,
+		FeeOracle:            cfg.FeeOracle,
 	})
 	if err != nil {
 		return nil, err
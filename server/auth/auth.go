@@ -8,6 +8,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"sync"
@@ -69,6 +70,8 @@ type Storage interface {
 	PreimageStats(user account.AccountID, lastN int) ([]*db.PreimageResult, error)
 	AllActiveUserMatches(aid account.AccountID) ([]*db.MatchData, error)
 	MatchStatuses(aid account.AccountID, base, quote uint32, matchIDs []order.MatchID) ([]*db.MatchStatus, error)
+	BanScore(aid account.AccountID) (*db.BanScore, error)
+	SetBanScore(aid account.AccountID, bs *db.BanScore) error
 }
 
 // Signer signs messages. The message must be a 32-byte hash.
@@ -120,6 +123,8 @@ type clientInfo struct {
 	tier         int64
 	score        int32
 	bonds        []*db.Bond // only confirmed and active, not pending
+	// apiVersion is the API version negotiated in the 'connect' request.
+	apiVersion uint16
 }
 
 // not thread-safe
@@ -238,9 +243,14 @@ type AuthManager struct {
 	bondExpiry time.Duration // a bond is expired when time.Until(lockTime) < bondExpiry
 	bondAssets map[uint32]*msgjson.BondAsset
 
+	threshMtx        sync.RWMutex // guards freeCancels, penaltyThreshold, cancelThresh, and graceOverride
 	freeCancels      bool
 	penaltyThreshold int32
 	cancelThresh     float64
+	graceOverride    *uint32 // nil to derive the grace limit from cancelThresh
+	minAPIVersion    uint16
+	maxAPIVersion    uint16
+	events           EventNotifier
 
 	// latencyQ is a queue for fee coin waiters to deal with latency.
 	latencyQ *wait.TickerQueue
@@ -261,6 +271,14 @@ type AuthManager struct {
 	txDataSources map[uint32]TxDataSource
 
 	prepaidBondMtx sync.Mutex
+
+	banMtx    sync.Mutex
+	banCfg    *BanScoreConfig
+	banScores map[account.AccountID]*db.BanScore // cached, removed on disconnect
+	lastSpam  map[account.AccountID]time.Time
+	// banStoreMtx orders ban score DB writes, which are made without the
+	// banMtx.
+	banStoreMtx sync.Mutex
 }
 
 // violation badness
@@ -296,6 +314,7 @@ const (
 	ViolationNoRedeemAsMaker
 	ViolationNoRedeemAsTaker
 	ViolationCancelRate
+	ViolationSpam
 )
 
 var violations = map[Violation]struct {
@@ -310,6 +329,7 @@ var violations = map[Violation]struct {
 	ViolationNoRedeemAsMaker: {noRedeemAsMakerScore, "no redeem as maker"},
 	ViolationNoRedeemAsTaker: {noRedeemAsTakerScore, "no redeem as taker"},
 	ViolationCancelRate:      {excessiveCancels, "excessive cancels"},
+	ViolationSpam:            {0, "spam"}, // ban score only
 	ViolationInvalid:         {0, "invalid violation"},
 }
 
@@ -394,10 +414,36 @@ type Config struct {
 
 	CancelThreshold float64
 	FreeCancels     bool
+	// GraceLimit, if set, is the number of orders a new user may place before
+	// the cancellation rate is enforced. If nil, it is derived from
+	// CancelThreshold.
+	GraceLimit *uint32
 
 	// PenaltyThreshold defines the score deficit at which a user's bond is
 	// revoked.
 	PenaltyThreshold uint32
+
+	// MinAPIVersion is the oldest client API version accepted in a 'connect'
+	// request. Clients reporting an older version are refused with an
+	// OutdatedClientError so that they may prompt the user to upgrade.
+	MinAPIVersion uint16
+	// MaxAPIVersion is the server's current API version. A client reporting a
+	// newer version in its 'connect' request is spoken to with MaxAPIVersion.
+	// If zero, the client's version is not limited.
+	MaxAPIVersion uint16
+
+	// Events, if set, is notified when accounts are registered, post bonds, or
+	// are penalized. See the Event constants for the event types and data.
+	Events EventNotifier
+
+	// BanScore configures the graduated penalties for accumulated violations.
+	// If nil, DefaultBanScoreConfig is used.
+	BanScore *BanScoreConfig
+}
+
+// EventNotifier receives account events, e.g. a *webhook.Notifier.
+type EventNotifier interface {
+	Notify(evtType string, data any)
 }
 
 // NewAuthManager is the constructor for an AuthManager.
@@ -416,6 +462,10 @@ func NewAuthManager(cfg *Config) *AuthManager {
 	for _, asset := range cfg.BondAssets {
 		bondAssets[asset.ID] = asset
 	}
+	banCfg := cfg.BanScore
+	if banCfg == nil {
+		banCfg = DefaultBanScoreConfig()
+	}
 
 	auth := &AuthManager{
 		storage:          cfg.Storage,
@@ -430,6 +480,10 @@ func NewAuthManager(cfg *Config) *AuthManager {
 		freeCancels:      cfg.FreeCancels,
 		penaltyThreshold: penaltyThreshold,
 		cancelThresh:     cfg.CancelThreshold,
+		graceOverride:    cfg.GraceLimit,
+		minAPIVersion:    cfg.MinAPIVersion,
+		maxAPIVersion:    cfg.MaxAPIVersion,
+		events:           cfg.Events,
 		latencyQ:         wait.NewTickerQueue(recheckInterval),
 		users:            make(map[account.AccountID]*clientInfo),
 		conns:            make(map[uint64]*clientInfo),
@@ -439,6 +493,9 @@ func NewAuthManager(cfg *Config) *AuthManager {
 		preimgOutcomes:   make(map[account.AccountID]*latestPreimageOutcomes),
 		orderOutcomes:    make(map[account.AccountID]*latestOrders),
 		txDataSources:    cfg.TxDataSources,
+		banCfg:           banCfg,
+		banScores:        make(map[account.AccountID]*db.BanScore),
+		lastSpam:         make(map[account.AccountID]time.Time),
 	}
 
 	// Unauthenticated
@@ -447,6 +504,7 @@ func NewAuthManager(cfg *Config) *AuthManager {
 	cfg.Route(msgjson.PreValidateBondRoute, auth.handlePreValidateBond)
 	cfg.Route(msgjson.MatchStatusRoute, auth.handleMatchStatus)
 	cfg.Route(msgjson.OrderStatusRoute, auth.handleOrderStatus)
+	cfg.Route(msgjson.ReputationRoute, auth.handleReputation)
 	return auth
 }
 
@@ -476,14 +534,129 @@ func (auth *AuthManager) ExpectUsers(users map[account.AccountID]struct{}, withi
 // GraceLimit returns the number of initial orders allowed for a new user before
 // the cancellation rate threshold is enforced.
 func (auth *AuthManager) GraceLimit() int {
+	_, _, grace := auth.cancelRules()
+	return grace
+}
+
+func graceLimit(cancelThresh float64) int {
 	// Grace period if: total/(1+total) <= thresh OR total <= thresh/(1-thresh).
-	return int(math.Round(1e8*auth.cancelThresh/(1-auth.cancelThresh))) / 1e8
+	return int(math.Round(1e8*cancelThresh/(1-cancelThresh))) / 1e8
+}
+
+// cancelRules returns whether cancels are free, the cancellation rate
+// threshold, and the grace limit.
+func (auth *AuthManager) cancelRules() (freeCancels bool, cancelThresh float64, grace int) {
+	auth.threshMtx.RLock()
+	defer auth.threshMtx.RUnlock()
+	grace = graceLimit(auth.cancelThresh)
+	if auth.graceOverride != nil {
+		grace = int(*auth.graceOverride)
+	}
+	return auth.freeCancels, auth.cancelThresh, grace
+}
+
+// Thresholds are the account conduct settings of the AuthManager that may be
+// changed while it is running.
+type Thresholds struct {
+	// CancelThreshold is the maximum cancellation rate before a user's score
+	// is penalized. It must be greater than zero and less than one.
+	CancelThreshold float64 `json:"cancelThreshold"`
+	// FreeCancels disables enforcement of the cancellation rate.
+	FreeCancels bool `json:"freeCancels"`
+	// PenaltyThreshold is the score deficit per bond tier revoked.
+	PenaltyThreshold uint32 `json:"penaltyThreshold"`
+	// GraceLimit is the number of orders a new user may place before the
+	// cancellation rate is enforced. If nil, it is derived from
+	// CancelThreshold.
+	GraceLimit *uint32 `json:"graceLimit,omitempty"`
+	// SuspensionPeriod is how long an account is suspended when its ban score
+	// reaches the suspension score.
+	SuspensionPeriod time.Duration `json:"suspensionPeriod"`
+	// BanScoreHalfLife is the time for a ban score to decay by half.
+	BanScoreHalfLife time.Duration `json:"banScoreHalfLife"`
+}
+
+// Validate checks that the thresholds are usable.
+func (t *Thresholds) Validate() error {
+	if t.CancelThreshold <= 0 || t.CancelThreshold >= 1 {
+		return fmt.Errorf("cancellation threshold %v is not between 0 and 1", t.CancelThreshold)
+	}
+	if t.PenaltyThreshold == 0 || t.PenaltyThreshold > math.MaxInt32 {
+		return fmt.Errorf("invalid penalty threshold %d", t.PenaltyThreshold)
+	}
+	if t.SuspensionPeriod <= 0 {
+		return errors.New("suspension period must be positive")
+	}
+	if t.BanScoreHalfLife <= 0 {
+		return errors.New("ban score half-life must be positive")
+	}
+	return nil
+}
+
+// Thresholds returns the current account conduct settings.
+func (auth *AuthManager) Thresholds() *Thresholds {
+	auth.banMtx.Lock()
+	suspensionPeriod, halfLife := auth.banCfg.SuspensionPeriod, auth.banCfg.HalfLife
+	auth.banMtx.Unlock()
+	auth.threshMtx.RLock()
+	defer auth.threshMtx.RUnlock()
+	t := &Thresholds{
+		CancelThreshold:  auth.cancelThresh,
+		FreeCancels:      auth.freeCancels,
+		PenaltyThreshold: uint32(-auth.penaltyThreshold),
+		SuspensionPeriod: suspensionPeriod,
+		BanScoreHalfLife: halfLife,
+	}
+	if auth.graceOverride != nil {
+		grace := *auth.graceOverride
+		t.GraceLimit = &grace
+	}
+	return t
+}
+
+// SetThresholds changes the account conduct settings. Users' scores are
+// evaluated against the new thresholds the next time their tier is computed,
+// such as when they connect or an order or match outcome is recorded.
+func (auth *AuthManager) SetThresholds(t *Thresholds) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	var graceOverride *uint32
+	if t.GraceLimit != nil {
+		grace := *t.GraceLimit
+		graceOverride = &grace
+	}
+	auth.threshMtx.Lock()
+	auth.cancelThresh = t.CancelThreshold
+	auth.freeCancels = t.FreeCancels
+	auth.penaltyThreshold = -int32(t.PenaltyThreshold)
+	auth.graceOverride = graceOverride
+	auth.threshMtx.Unlock()
+
+	// The BanScoreConfig is replaced rather than modified, since addBanPoints
+	// uses it after unlocking the banMtx.
+	auth.banMtx.Lock()
+	banCfg := *auth.banCfg
+	banCfg.SuspensionPeriod = t.SuspensionPeriod
+	banCfg.HalfLife = t.BanScoreHalfLife
+	auth.banCfg = &banCfg
+	auth.banMtx.Unlock()
+
+	log.Infof("Account conduct thresholds updated: cancellation rate %v (free cancels = %v), "+
+		"grace limit %d, penalty threshold %d, suspension period %v, ban score half-life %v",
+		t.CancelThreshold, t.FreeCancels, auth.GraceLimit(), t.PenaltyThreshold,
+		t.SuspensionPeriod, t.BanScoreHalfLife)
+	return nil
 }
 
 // RecordCancel records a user's executed cancel order, including the canceled
 // order ID, and the time when the cancel was executed.
 func (auth *AuthManager) RecordCancel(user account.AccountID, oid, target order.OrderID, epochGap int32, t time.Time) {
 	score := auth.recordOrderDone(user, oid, &target, epochGap, t.UnixMilli())
+	if rate, exceeded := auth.cancelRateExceeded(user); exceeded {
+		auth.addBanPoints(user, ViolationCancelRate, account.CancellationRate,
+			fmt.Sprintf("cancellation rate %.3f exceeds the threshold", rate))
+	}
 
 	rep, tierChanged, scoreChanged := auth.computeUserReputation(user, score)
 	effectiveTier := rep.EffectiveTier()
@@ -760,11 +933,12 @@ func (auth *AuthManager) integrateOutcomes(
 		piMissCount = preimgOutcomes.misses()
 		score += ViolationPreimageMiss.Score() * piMissCount
 	}
-	if !auth.freeCancels {
+	freeCancels, cancelThresh, grace := auth.cancelRules()
+	if !freeCancels {
 		totalOrds, cancels := orderOutcomes.counts() // completions := totalOrds - cancels
-		if totalOrds > auth.GraceLimit() {
+		if totalOrds > grace {
 			cancelRate := float64(cancels) / float64(totalOrds)
-			if cancelRate > auth.cancelThresh {
+			if cancelRate > cancelThresh {
 				score += ViolationCancelRate.Score()
 			}
 		}
@@ -821,7 +995,7 @@ func (auth *AuthManager) UserReputation(user account.AccountID) (tier int64, sco
 func (auth *AuthManager) userReputation(bondTier int64, score int32) *account.Reputation {
 	var penalties int32
 	if score < 0 {
-		penalties = score / auth.penaltyThreshold
+		penalties = score / auth.penaltyThresh()
 	}
 	return &account.Reputation{
 		BondedTier: bondTier,
@@ -830,6 +1004,13 @@ func (auth *AuthManager) userReputation(bondTier int64, score int32) *account.Re
 	}
 }
 
+// penaltyThresh returns the (negative) score deficit per penalty.
+func (auth *AuthManager) penaltyThresh() int32 {
+	auth.threshMtx.RLock()
+	defer auth.threshMtx.RUnlock()
+	return auth.penaltyThreshold
+}
+
 // tier computes a user's tier from their conduct score and bond tier.
 func (auth *AuthManager) tier(bondTier int64, score int32) int64 {
 	return auth.userReputation(bondTier, score).EffectiveTier()
@@ -881,6 +1062,57 @@ func (auth *AuthManager) ComputeUserReputation(user account.AccountID) *account.
 	return r
 }
 
+// ReputationReport computes the user's reputation, along with the recent
+// match, preimage, and order outcomes from which their score is derived and
+// the conduct thresholds in effect. The outcomes of connected users are taken
+// from memory, while those of offline users are loaded from the DB.
+func (auth *AuthManager) ReputationReport(user account.AccountID) (*msgjson.ReputationReport, error) {
+	auth.violationMtx.Lock()
+	matchOutcomes, found := auth.matchOutcomes[user]
+	preimgOutcomes, orderOutcomes := auth.preimgOutcomes[user], auth.orderOutcomes[user]
+	auth.violationMtx.Unlock()
+	if !found {
+		var err error
+		matchOutcomes, preimgOutcomes, orderOutcomes, err = auth.loadUserOutcomes(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load order and match outcomes for user %v: %w", user, err)
+		}
+	}
+
+	score, _, piMissCount := auth.integrateOutcomes(matchOutcomes, preimgOutcomes, orderOutcomes)
+	rep, _, _ := auth.computeUserReputation(user, score)
+
+	freeCancels, cancelThresh, grace := auth.cancelRules()
+	penaltyThresh := auth.penaltyThresh()
+
+	report := &msgjson.ReputationReport{
+		Reputation:       rep,
+		PenaltyThreshold: penaltyThresh,
+		MaxScore:         ScoringMatchLimit,
+		PreimageMisses:   uint32(piMissCount),
+		CancelThreshold:  cancelThresh,
+		GraceLimit:       uint32(grace),
+		FreeCancels:      freeCancels,
+	}
+	if matchOutcomes != nil {
+		bins := matchOutcomes.binViolations()
+		report.SwapsCompleted = uint32(bins[ViolationSwapSuccess])
+		report.NoSwapAsMaker = uint32(bins[ViolationNoSwapAsMaker])
+		report.NoSwapAsTaker = uint32(bins[ViolationNoSwapAsTaker])
+		report.NoRedeemAsMaker = uint32(bins[ViolationNoRedeemAsMaker])
+		report.NoRedeemAsTaker = uint32(bins[ViolationNoRedeemAsTaker])
+		report.Forgiven = uint32(bins[ViolationForgiven])
+	}
+	if orderOutcomes != nil {
+		total, cancels := orderOutcomes.counts()
+		report.Orders, report.Cancels = uint32(total), uint32(cancels)
+		if total > 0 {
+			report.CancelRatio = float64(cancels) / float64(total)
+		}
+	}
+	return report, nil
+}
+
 func (auth *AuthManager) registerMatchOutcome(user account.AccountID, misstep NoActionStep, mmid db.MarketMatchID, value uint64, refTime time.Time) (score int32) {
 	violation := misstep.Violation()
 
@@ -945,6 +1177,8 @@ func (auth *AuthManager) Inaction(user account.AccountID, misstep NoActionStep,
 		return
 	}
 	score := auth.registerMatchOutcome(user, misstep, mmid, matchValue, refTime)
+	auth.addBanPoints(user, violation, account.FailureToAct,
+		fmt.Sprintf("swap %v failure (%v) for order %v", mmid.MatchID, misstep, oid))
 
 	// Recompute tier.
 	rep, tierChanged, scoreChanged := auth.computeUserReputation(user, score)
@@ -1002,7 +1236,9 @@ func (auth *AuthManager) PreimageSuccess(user account.AccountID, epochEnd time.T
 // MissedPreimage registers a missed preimage violation by the user.
 func (auth *AuthManager) MissedPreimage(user account.AccountID, epochEnd time.Time, oid order.OrderID) {
 	score := auth.registerPreimageOutcome(user, true, oid, epochEnd)
-	if score < auth.penaltyThreshold {
+	auth.addBanPoints(user, ViolationPreimageMiss, account.PreimageReveal,
+		fmt.Sprintf("preimage for order %v not provided upon request", oid))
+	if score < auth.penaltyThresh() {
 		return
 	}
 
@@ -1035,23 +1271,16 @@ func (auth *AuthManager) Penalize(user account.AccountID, lastRule account.Rule,
 	log.Debugf("User %v account penalized. Last rule broken = %v. Detail: %s", user, lastRule, extraDetails)
 
 	// Notify user of penalty.
-	details := "Ordering has been suspended for this account. Post additional bond to offset violations."
-	details = fmt.Sprintf("%s\nLast Broken Rule Details: %s\n%s", details, lastRule.Description(), extraDetails)
-	penalty := &msgjson.Penalty{
-		Rule:    lastRule,
-		Time:    uint64(time.Now().UnixMilli()),
-		Details: details,
-	}
-	penaltyNote := &msgjson.PenaltyNote{
-		Penalty: penalty,
-	}
-	penaltyNote.Sig = auth.SignMsg(penaltyNote.Serialize())
-	note, err := msgjson.NewNotification(msgjson.PenaltyRoute, penaltyNote)
-	if err != nil {
-		log.Errorf("error creating penalty notification: %w", err)
-		return
+	msg := "Ordering has been suspended for this account. Post additional bond to offset violations."
+	auth.sendPenalty(user, lastRule, msg, extraDetails, "")
+}
+
+// notifyEvent passes the account event to the configured EventNotifier, if
+// any.
+func (auth *AuthManager) notifyEvent(evtType string, data any) {
+	if auth.events != nil {
+		auth.events.Notify(evtType, data)
 	}
-	auth.Notify(user, note)
 }
 
 // AcctStatus indicates if the user is presently connected and their tier.
@@ -1134,6 +1363,17 @@ func (auth *AuthManager) user(user account.AccountID) *clientInfo {
 	return auth.users[user]
 }
 
+// UserAPIVersion is the API version negotiated with the user's current
+// connection. Handlers for routes with version-dependent payloads should use
+// it to pick the format. ok is false if the user is not connected.
+func (auth *AuthManager) UserAPIVersion(user account.AccountID) (ver uint16, ok bool) {
+	client := auth.user(user)
+	if client == nil {
+		return 0, false
+	}
+	return client.apiVersion, true
+}
+
 // conn gets the clientInfo for the specified connection ID.
 func (auth *AuthManager) conn(conn comms.Link) *clientInfo {
 	auth.connMtx.RLock()
@@ -1353,6 +1593,11 @@ func (auth *AuthManager) removeClient(client *clientInfo) {
 	delete(auth.preimgOutcomes, user)
 	delete(auth.orderOutcomes, user)
 	auth.violationMtx.Unlock()
+
+	auth.banMtx.Lock()
+	delete(auth.banScores, user)
+	delete(auth.lastSpam, user)
+	auth.banMtx.Unlock()
 }
 
 func matchStatusToViol(status order.MatchStatus) Violation {
@@ -1479,6 +1724,65 @@ func (auth *AuthManager) UserMatchFails(user account.AccountID, n int) ([]*Match
 	return fails, nil
 }
 
+// BondInfo is a JSON-friendly version of an active db.Bond.
+type BondInfo struct {
+	Version  uint16 `json:"version"`
+	AssetID  uint32 `json:"assetID"`
+	CoinID   string `json:"coinID"`
+	Amount   int64  `json:"amount"`
+	Strength uint32 `json:"strength"`
+	LockTime int64  `json:"lockTime"`
+	// Expiry is when the bond will no longer count toward the user's tier,
+	// which is bondExpiry before LockTime.
+	Expiry int64 `json:"expiry"`
+}
+
+// AccountInfo is an account's identity, active bonds, and reputation.
+type AccountInfo struct {
+	AccountID  account.AccountID   `json:"accountID"`
+	Pubkey     dex.Bytes           `json:"pubkey"`
+	Connected  bool                `json:"connected"`
+	Bonds      []*BondInfo         `json:"bonds"`
+	Reputation *account.Reputation `json:"reputation,omitempty"`
+	Tier       int64               `json:"tier"`
+}
+
+// AccountInfo retrieves the account's active bonds and computes the user's
+// current reputation and trading tier.
+func (auth *AuthManager) AccountInfo(user account.AccountID) (*AccountInfo, error) {
+	acct, err := auth.storage.AccountInfo(user)
+	if err != nil {
+		return nil, err
+	}
+
+	lockTimeThresh := time.Now().Add(auth.bondExpiry)
+	_, dbBonds := auth.storage.Account(user, lockTimeThresh)
+	bonds := make([]*BondInfo, 0, len(dbBonds))
+	for _, bond := range dbBonds {
+		bonds = append(bonds, &BondInfo{
+			Version:  bond.Version,
+			AssetID:  bond.AssetID,
+			CoinID:   coinIDString(bond.AssetID, bond.CoinID),
+			Amount:   bond.Amount,
+			Strength: bond.Strength,
+			LockTime: bond.LockTime,
+			Expiry:   time.Unix(bond.LockTime, 0).Add(-auth.bondExpiry).Unix(),
+		})
+	}
+
+	ai := &AccountInfo{
+		AccountID: acct.AccountID,
+		Pubkey:    acct.Pubkey,
+		Connected: auth.user(user) != nil,
+		Bonds:     bonds,
+	}
+	if r := auth.ComputeUserReputation(user); r != nil {
+		ai.Reputation = r
+		ai.Tier = r.EffectiveTier()
+	}
+	return ai, nil
+}
+
 // loadUserScore computes the user's current score from order and swap data
 // retrieved from the DB. Use this instead of userScore if the user is offline.
 func (auth *AuthManager) loadUserScore(user account.AccountID) (int32, error) {
@@ -1508,6 +1812,19 @@ func (auth *AuthManager) handleConnect(conn comms.Link, msg *msgjson.Message) *m
 			Message: "authentication error. invalid account ID",
 		}
 	}
+	if connect.APIVersion < auth.minAPIVersion {
+		return &msgjson.Error{
+			Code: msgjson.OutdatedClientError,
+			Message: fmt.Sprintf("client API version %d is no longer supported, minimum version is %d. "+
+				"upgrade your client software", connect.APIVersion, auth.minAPIVersion),
+		}
+	}
+	// The client may be newer than the server, in which case it must speak the
+	// server's version, which it learned from the config response.
+	apiVersion := connect.APIVersion
+	if auth.maxAPIVersion > 0 && apiVersion > auth.maxAPIVersion {
+		apiVersion = auth.maxAPIVersion
+	}
 	var user account.AccountID
 	copy(user[:], connect.AccountID[:])
 	lockTimeThresh := time.Now().Add(auth.bondExpiry).Truncate(time.Second)
@@ -1570,6 +1887,7 @@ func (auth *AuthManager) handleConnect(conn comms.Link, msg *msgjson.Message) *m
 		acct:         acctInfo,
 		conn:         conn,
 		respHandlers: respHandlers,
+		apiVersion:   apiVersion,
 	}
 
 	// Get the list of active orders for this user.
@@ -1687,6 +2005,7 @@ func (auth *AuthManager) handleConnect(conn comms.Link, msg *msgjson.Message) *m
 		Score:               score,
 		ActiveBonds:         msgBonds,
 		Reputation:          rep,
+		APIVersion:          apiVersion,
 	}
 	respMsg, err := msgjson.NewResponse(msg.ID, resp, nil)
 	if err != nil {
@@ -1704,8 +2023,8 @@ func (auth *AuthManager) handleConnect(conn comms.Link, msg *msgjson.Message) *m
 	}
 
 	log.Infof("Authenticated account %v from %v with %d active orders, %d active matches, tier = %v, "+
-		"bond tier = %v, score = %v",
-		user, conn.Addr(), len(msgOrderStatuses), len(msgMatches), client.tier, bondTier, score)
+		"bond tier = %v, score = %v, API version = %d",
+		user, conn.Addr(), len(msgOrderStatuses), len(msgMatches), client.tier, bondTier, score, apiVersion)
 	auth.addClient(client)
 
 	return nil
@@ -1921,6 +2240,33 @@ func (auth *AuthManager) handleMatchStatus(conn comms.Link, msg *msgjson.Message
 	return nil
 }
 
+// handleReputation handles requests to the 'reputation' route.
+func (auth *AuthManager) handleReputation(conn comms.Link, msg *msgjson.Message) *msgjson.Error {
+	client := auth.conn(conn)
+	if client == nil {
+		return msgjson.NewError(msgjson.UnauthorizedConnection,
+			"cannot use route 'reputation' on an unauthorized connection")
+	}
+
+	report, err := auth.ReputationReport(client.acct.ID)
+	if err != nil {
+		log.Errorf("ReputationReport error for user %v: %v", client.acct.ID, err)
+		return msgjson.NewError(msgjson.RPCInternalError, "DB error")
+	}
+
+	resp, err := msgjson.NewResponse(msg.ID, report, nil)
+	if err != nil {
+		log.Errorf("NewResponse error: %v", err)
+		return msgjson.NewError(msgjson.RPCInternalError, "Internal error")
+	}
+
+	err = conn.Send(resp)
+	if err != nil {
+		log.Error("error sending reputation response: " + err.Error())
+	}
+	return nil
+}
+
 // marketOrders is an index of order IDs associated with a particular market.
 type marketOrders struct {
 	base     uint32
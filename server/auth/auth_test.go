@@ -10,6 +10,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
 	"sync/atomic"
@@ -64,6 +65,7 @@ type TStorage struct {
 	payErr              error
 	bonds               []*db.Bond
 	ratio               ratioData
+	banScores           map[account.AccountID]*db.BanScore
 }
 
 func (s *TStorage) AccountInfo(account.AccountID) (*db.Account, error) {
@@ -120,6 +122,16 @@ func (s *TStorage) CreateAccount(acct *account.Account, assetID uint32, addr str
 	s.regAsset = assetID
 	return s.acctErr
 }
+func (s *TStorage) BanScore(aid account.AccountID) (*db.BanScore, error) {
+	return s.banScores[aid], nil
+}
+func (s *TStorage) SetBanScore(aid account.AccountID, bs *db.BanScore) error {
+	if s.banScores == nil {
+		s.banScores = make(map[account.AccountID]*db.BanScore)
+	}
+	s.banScores[aid] = bs
+	return nil
+}
 func (s *TStorage) setRatioData(dat *ratioData) {
 	s.ratio = *dat
 }
@@ -1045,6 +1057,308 @@ func TestAccountErrors(t *testing.T) {
 
 }
 
+type tEventNotifier struct {
+	types []string
+	data  []any
+}
+
+func (n *tEventNotifier) Notify(evtType string, data any) {
+	n.types = append(n.types, evtType)
+	n.data = append(n.data, data)
+}
+
+func TestPenalizeEvent(t *testing.T) {
+	events := new(tEventNotifier)
+	rig.mgr.events = events
+	defer func() { rig.mgr.events = nil }()
+
+	user := tNewUser(t)
+	rig.mgr.Penalize(user.acctID, account.FailureToAct, "failed to redeem")
+	if len(events.types) != 1 || events.types[0] != EventAccountPenalized {
+		t.Fatalf("wrong events %v", events.types)
+	}
+	pen, ok := events.data[0].(*AccountPenalized)
+	if !ok {
+		t.Fatalf("wrong event data type %T", events.data[0])
+	}
+	if pen.AccountID != user.acctID.String() || pen.Rule != account.FailureToAct.String() {
+		t.Fatalf("wrong event data %+v", pen)
+	}
+}
+
+func TestBanScore(t *testing.T) {
+	events := new(tEventNotifier)
+	rig.mgr.events = events
+	defer func() { rig.mgr.events = nil }()
+
+	var unbooked int
+	rig.mgr.unbookFun = func(account.AccountID) { unbooked++ }
+	defer func() { rig.mgr.unbookFun = func(account.AccountID) {} }()
+
+	cfg := rig.mgr.banCfg
+	user := tNewUser(t).acctID
+	checkStanding := func(tag string, wantStanding account.Standing, wantUnbooked int) {
+		t.Helper()
+		standing, maxLots := rig.mgr.Standing(user)
+		if standing != wantStanding {
+			t.Fatalf("%s: wanted standing %v, got %v", tag, wantStanding, standing)
+		}
+		if (standing == account.StandingCapped) != (maxLots == cfg.CapLots) {
+			t.Fatalf("%s: wrong max lots %d for standing %v", tag, maxLots, standing)
+		}
+		if unbooked != wantUnbooked {
+			t.Fatalf("%s: wanted %d unbookings, got %d", tag, wantUnbooked, unbooked)
+		}
+	}
+	checkStanding("new user", account.StandingGood, 0)
+
+	// Violations without a weight add no points.
+	rig.mgr.addBanPoints(user, ViolationSwapSuccess, account.NoRule, "")
+	if bs := rig.storage.banScores[user]; bs != nil {
+		t.Fatalf("ban score stored for a success: %+v", bs)
+	}
+
+	// no swap as taker (20) reaches the cap score.
+	rig.mgr.addBanPoints(user, ViolationNoSwapAsTaker, account.FailureToAct, "")
+	checkStanding("capped", account.StandingCapped, 0)
+	if len(events.data) != 1 || events.data[0].(*AccountPenalized).Standing != "capped" {
+		t.Fatalf("wrong events for capped account: %v", events.types)
+	}
+
+	// Two more reach the suspend score.
+	rig.mgr.addBanPoints(user, ViolationNoSwapAsTaker, account.FailureToAct, "")
+	checkStanding("still capped", account.StandingCapped, 0)
+	rig.mgr.addBanPoints(user, ViolationNoSwapAsTaker, account.FailureToAct, "")
+	checkStanding("suspended", account.StandingSuspended, 1)
+	bs := rig.storage.banScores[user]
+	if bs == nil || bs.SuspendedUntil.Before(time.Now().Add(cfg.SuspensionPeriod-time.Minute)) {
+		t.Fatalf("suspension not stored: %+v", bs)
+	}
+
+	// The score decays by half each half-life. After the suspension and a
+	// half-life, 60 points decay to 30, which is still capped.
+	rig.mgr.banMtx.Lock()
+	bs = rig.mgr.banScores[user]
+	bs.Stamp = bs.Stamp.Add(-cfg.HalfLife)
+	bs.SuspendedUntil = time.Now().Add(-time.Second)
+	rig.mgr.banMtx.Unlock()
+	info, err := rig.mgr.BanScoreInfo(user)
+	if err != nil {
+		t.Fatalf("BanScoreInfo error: %v", err)
+	}
+	if math.Abs(info.Score-30) > 0.01 || info.Standing != "capped" || info.MaxLots != cfg.CapLots {
+		t.Fatalf("wrong decayed ban score info %+v", info)
+	}
+
+	// Bans are disabled by default, so more violations only suspend.
+	for i := 0; i < 7; i++ {
+		rig.mgr.addBanPoints(user, ViolationNoSwapAsTaker, account.FailureToAct, "")
+	}
+	checkStanding("suspended, not banned", account.StandingSuspended, 2)
+
+	// With bans enabled, the next violation is a permanent ban.
+	banCfg := *cfg
+	banCfg.BanScore = 150
+	rig.mgr.banMtx.Lock()
+	rig.mgr.banCfg = &banCfg
+	rig.mgr.banMtx.Unlock()
+	defer func() {
+		rig.mgr.banMtx.Lock()
+		rig.mgr.banCfg = cfg
+		rig.mgr.banMtx.Unlock()
+	}()
+	rig.mgr.addBanPoints(user, ViolationNoSwapAsTaker, account.FailureToAct, "")
+	checkStanding("banned", account.StandingBanned, 3)
+	rig.mgr.banMtx.Lock()
+	rig.mgr.banScores[user].Stamp = time.Now().Add(-cfg.HalfLife * 20)
+	rig.mgr.banMtx.Unlock()
+	checkStanding("banned after decay", account.StandingBanned, 3)
+
+	// The ban is persisted, and reloaded when not cached.
+	rig.mgr.banMtx.Lock()
+	delete(rig.mgr.banScores, user)
+	rig.mgr.banMtx.Unlock()
+	checkStanding("reloaded", account.StandingBanned, 3)
+
+	if err := rig.mgr.ClearBanScore(user); err != nil {
+		t.Fatalf("ClearBanScore error: %v", err)
+	}
+	checkStanding("cleared", account.StandingGood, 3)
+}
+
+func TestRateLimited(t *testing.T) {
+	user := tNewUser(t)
+	rig.signer.sig = user.randomSignature()
+	connectUser(t, user)
+
+	// Unauthenticated links are ignored.
+	rig.mgr.RateLimited(tNewUser(t).conn, msgjson.OrderBookRoute)
+
+	spamScore := func() float64 {
+		info, err := rig.mgr.BanScoreInfo(user.acctID)
+		if err != nil {
+			t.Fatalf("BanScoreInfo error: %v", err)
+		}
+		return info.Score
+	}
+	weight := rig.mgr.banCfg.Weights[ViolationSpam]
+	rig.mgr.RateLimited(user.conn, msgjson.OrderBookRoute)
+	if waitFor(func() bool { return spamScore() > weight/2 }, time.Second) {
+		t.Fatalf("no ban points for spam")
+	}
+	// Repeats within spamInterval are not counted.
+	rig.mgr.RateLimited(user.conn, msgjson.OrderBookRoute)
+	time.Sleep(50 * time.Millisecond)
+	if score := spamScore(); score > weight*1.01 {
+		t.Fatalf("spam counted twice, score = %v", score)
+	}
+}
+
+func TestBanScoreConfigValidate(t *testing.T) {
+	if err := DefaultBanScoreConfig().Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	for name, mod := range map[string]func(*BanScoreConfig){
+		"zero half-life":    func(c *BanScoreConfig) { c.HalfLife = 0 },
+		"zero suspension":   func(c *BanScoreConfig) { c.SuspensionPeriod = 0 },
+		"zero cap lots":     func(c *BanScoreConfig) { c.CapLots = 0 },
+		"suspend below cap": func(c *BanScoreConfig) { c.SuspendScore = c.CapScore - 1 },
+		"ban below suspend": func(c *BanScoreConfig) { c.BanScore = c.SuspendScore - 1 },
+		"negative ban":      func(c *BanScoreConfig) { c.BanScore = -1 },
+		"negative weight":   func(c *BanScoreConfig) { c.Weights[ViolationSpam] = -1 },
+		"zero cap score":    func(c *BanScoreConfig) { c.CapScore = 0 },
+	} {
+		cfg := DefaultBanScoreConfig()
+		mod(cfg)
+		if cfg.Validate() == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+}
+
+func TestSetThresholds(t *testing.T) {
+	orig := rig.mgr.Thresholds()
+	defer rig.mgr.SetThresholds(orig)
+
+	newThresholds := func() *Thresholds {
+		return &Thresholds{
+			CancelThreshold:  0.75,
+			FreeCancels:      true,
+			PenaltyThreshold: 30,
+			SuspensionPeriod: time.Hour,
+			BanScoreHalfLife: 48 * time.Hour,
+		}
+	}
+
+	bad := newThresholds()
+	bad.CancelThreshold = 1
+	if err := rig.mgr.SetThresholds(bad); err == nil {
+		t.Fatalf("no error for cancellation threshold of 1")
+	}
+	bad = newThresholds()
+	bad.PenaltyThreshold = 0
+	if err := rig.mgr.SetThresholds(bad); err == nil {
+		t.Fatalf("no error for zero penalty threshold")
+	}
+	bad = newThresholds()
+	bad.SuspensionPeriod = 0
+	if err := rig.mgr.SetThresholds(bad); err == nil {
+		t.Fatalf("no error for zero suspension period")
+	}
+	bad = newThresholds()
+	bad.BanScoreHalfLife = 0
+	if err := rig.mgr.SetThresholds(bad); err == nil {
+		t.Fatalf("no error for zero ban score half-life")
+	}
+
+	// The grace limit is derived from the cancellation threshold.
+	if err := rig.mgr.SetThresholds(newThresholds()); err != nil {
+		t.Fatalf("SetThresholds error: %v", err)
+	}
+	th := rig.mgr.Thresholds()
+	if th.CancelThreshold != 0.75 || !th.FreeCancels || th.PenaltyThreshold != 30 || th.GraceLimit != nil ||
+		th.SuspensionPeriod != time.Hour || th.BanScoreHalfLife != 48*time.Hour {
+		t.Fatalf("wrong thresholds %+v", th)
+	}
+	if rig.mgr.GraceLimit() != 3 {
+		t.Fatalf("wrong derived grace limit %d", rig.mgr.GraceLimit())
+	}
+	if rig.mgr.penaltyThreshold != -30 {
+		t.Fatalf("wrong internal penalty threshold %d", rig.mgr.penaltyThreshold)
+	}
+	if rig.mgr.banCfg.SuspensionPeriod != time.Hour || rig.mgr.banCfg.HalfLife != 48*time.Hour {
+		t.Fatalf("wrong ban score config %+v", rig.mgr.banCfg)
+	}
+
+	// A set grace limit overrides the derived limit.
+	th = newThresholds()
+	grace := uint32(7)
+	th.GraceLimit = &grace
+	if err := rig.mgr.SetThresholds(th); err != nil {
+		t.Fatalf("SetThresholds error: %v", err)
+	}
+	if th = rig.mgr.Thresholds(); th.GraceLimit == nil || *th.GraceLimit != 7 {
+		t.Fatalf("wrong grace limit in %+v", th)
+	}
+	if rig.mgr.GraceLimit() != 7 {
+		t.Fatalf("wrong grace limit %d", rig.mgr.GraceLimit())
+	}
+}
+
+func TestAccountInfo(t *testing.T) {
+	user := tNewUser(t)
+	pubKey := user.privKey.PubKey().SerializeCompressed()
+	rig.storage.acctInfo = &db.Account{
+		AccountID: user.acctID,
+		Pubkey:    pubKey,
+	}
+	lockTime := time.Now().Add(48 * time.Hour).Unix()
+	rig.storage.bonds = []*db.Bond{{
+		AssetID:  42,
+		CoinID:   randBytes(36),
+		Amount:   int64(tRegFee * 20),
+		Strength: 2,
+		LockTime: lockTime,
+	}}
+	defer func() {
+		rig.storage.acctInfo = nil
+		rig.storage.bonds = nil
+		rig.storage.acctInfoErr = nil
+	}()
+
+	ai, err := rig.mgr.AccountInfo(user.acctID)
+	if err != nil {
+		t.Fatalf("AccountInfo error: %v", err)
+	}
+	if ai.AccountID != user.acctID {
+		t.Fatalf("wrong account ID")
+	}
+	if ai.Connected {
+		t.Fatalf("offline user reported as connected")
+	}
+	if len(ai.Bonds) != 1 {
+		t.Fatalf("expected 1 bond, got %d", len(ai.Bonds))
+	}
+	bond := ai.Bonds[0]
+	if bond.Strength != 2 || bond.LockTime != lockTime {
+		t.Fatalf("wrong bond info: %+v", bond)
+	}
+	if expiry := lockTime - int64(rig.mgr.bondExpiry.Seconds()); bond.Expiry != expiry {
+		t.Fatalf("wrong bond expiry. wanted %d, got %d", expiry, bond.Expiry)
+	}
+	if ai.Reputation == nil || ai.Reputation.BondedTier != 2 {
+		t.Fatalf("wrong reputation: %+v", ai.Reputation)
+	}
+	if ai.Tier != 2 {
+		t.Fatalf("wrong tier. wanted 2, got %d", ai.Tier)
+	}
+
+	rig.storage.acctInfoErr = fmt.Errorf("test error")
+	if _, err = rig.mgr.AccountInfo(user.acctID); err == nil {
+		t.Fatalf("no error for storage error")
+	}
+}
+
 func TestRoute(t *testing.T) {
 	user := tNewUser(t)
 	rig.signer.sig = user.randomSignature()
@@ -1216,6 +1530,13 @@ func TestConnectErrors(t *testing.T) {
 	ensureErr(rpcErr, "invalid account ID", msgjson.AuthenticationError)
 	connect.AccountID = user.acctID[:]
 
+	// client API version too old
+	rig.mgr.minAPIVersion = 1
+	encodeMsg()
+	rpcErr = rig.mgr.handleConnect(user.conn, msg)
+	ensureErr(rpcErr, "outdated client", msgjson.OutdatedClientError)
+	rig.mgr.minAPIVersion = 0
+
 	// user unknown to storage
 	encodeMsg()
 	rpcErr = rig.mgr.handleConnect(user.conn, msg)
@@ -1259,6 +1580,33 @@ func TestConnectErrors(t *testing.T) {
 	}
 }
 
+func TestAPIVersionNegotiation(t *testing.T) {
+	defer func() { rig.mgr.maxAPIVersion = 0 }()
+
+	for _, tt := range []struct {
+		maxVer, clientVer, wantVer uint16
+	}{{2, 1, 1}, {2, 2, 2}, {2, 3, 2}, {0, 3, 3}} {
+		rig.mgr.maxAPIVersion = tt.maxVer
+		user := tNewUser(t)
+		rig.signer.sig = user.randomSignature()
+		rig.storage.acct = &account.Account{ID: user.acctID, PubKey: user.privKey.PubKey()}
+		connect := tNewConnect(user)
+		connect.APIVersion = tt.clientVer
+		connect.SetSig(signMsg(user.privKey, connect.Serialize()))
+		msg, _ := msgjson.NewRequest(comms.NextID(), msgjson.ConnectRoute, connect)
+		if rpcErr := rig.mgr.handleConnect(user.conn, msg); rpcErr != nil {
+			t.Fatalf("connect error for client version %d: %s", tt.clientVer, rpcErr.Message)
+		}
+		result := extractConnectResult(t, user.conn.getSend())
+		if result.APIVersion != tt.wantVer {
+			t.Fatalf("client version %d: wanted negotiated version %d, got %d", tt.clientVer, tt.wantVer, result.APIVersion)
+		}
+		if ver, ok := rig.mgr.UserAPIVersion(user.acctID); !ok || ver != tt.wantVer {
+			t.Fatalf("client version %d: wrong UserAPIVersion %d, %v", tt.clientVer, ver, ok)
+		}
+	}
+}
+
 func TestHandleResponse(t *testing.T) {
 	user := tNewUser(t)
 	rig.signer.sig = user.randomSignature()
@@ -1520,6 +1868,53 @@ func TestOrderStatus(t *testing.T) {
 	}
 }
 
+func TestReputationReport(t *testing.T) {
+	user := tNewUser(t)
+	rig.signer.sig = user.randomSignature()
+	connectUser(t, user)
+
+	matchOutcomes := rig.mgr.matchOutcomes[user.acctID]
+	for i, v := range []Violation{ViolationSwapSuccess, ViolationSwapSuccess, ViolationNoRedeemAsTaker} {
+		matchOutcomes.add(&matchOutcome{
+			time:    int64(i + 1),
+			mid:     order.MatchID{byte(i)},
+			outcome: v,
+		})
+	}
+	var oid, coid order.OrderID
+	oid[0], coid[0] = 0x01, 0x02
+	tCompleted := unixMsNow()
+	rig.mgr.RecordCompletedOrder(user.acctID, oid, tCompleted)
+	rig.mgr.RecordCancel(user.acctID, coid, oid, 1, tCompleted.Add(time.Millisecond))
+
+	req, _ := msgjson.NewRequest(1, msgjson.ReputationRoute, nil)
+	if msgErr := rig.mgr.handleReputation(user.conn, req); msgErr != nil {
+		t.Fatalf("handleReputation error: %v", msgErr)
+	}
+	resp := user.conn.getSend()
+	if resp == nil {
+		t.Fatalf("no response sent")
+	}
+	report := new(msgjson.ReputationReport)
+	if err := resp.UnmarshalResult(report); err != nil {
+		t.Fatalf("UnmarshalResult error: %v", err)
+	}
+	if report.SwapsCompleted != 2 || report.NoRedeemAsTaker != 1 {
+		t.Fatalf("wrong swap outcomes: %+v", report)
+	}
+	if report.Orders != 2 || report.Cancels != 1 || report.CancelRatio != 0.5 {
+		t.Fatalf("wrong order outcomes: %+v", report)
+	}
+	wantScore := int32(2*successScore + noRedeemAsTakerScore)
+	if report.Reputation == nil || report.Reputation.Score != wantScore {
+		t.Fatalf("wrong reputation %+v, wanted score %d", report.Reputation, wantScore)
+	}
+	thresh := rig.mgr.Thresholds()
+	if report.PenaltyThreshold != int32(thresh.PenaltyThreshold) || report.CancelThreshold != thresh.CancelThreshold {
+		t.Fatalf("wrong thresholds: %+v", report)
+	}
+}
+
 func Test_checkSigS256(t *testing.T) {
 	sig := []byte{0x30, 0, 0x02, 0x01, 9, 0x2, 0x01, 10}
 	ecdsa.ParseDERSignature(sig) // panic on line 132: sigStr[2] != 0x02 after trimming to sigStr[:(1+2)]
@@ -0,0 +1,374 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/server/db"
+)
+
+// Default ban score settings. See BanScoreConfig. Permanent bans are disabled
+// by default.
+const (
+	DefaultBanScoreHalfLife = 7 * 24 * time.Hour
+	DefaultBanScoreCap      = 20
+	DefaultBanScoreCapLots  = 5
+	DefaultBanScoreSuspend  = 50
+	DefaultSuspensionPeriod = 24 * time.Hour
+
+	// spamInterval limits how often rate limit violations add to a user's ban
+	// score, so that a single burst of requests is only counted once.
+	spamInterval = time.Minute
+)
+
+// DefaultBanScoreWeights are the ban points added for each violation. The
+// weights roughly follow the inconvenience caused to the counterparty, like
+// the reputation score of the violations.
+var DefaultBanScoreWeights = map[Violation]float64{
+	ViolationPreimageMiss:    4,
+	ViolationNoSwapAsMaker:   8,
+	ViolationNoSwapAsTaker:   20,
+	ViolationNoRedeemAsMaker: 14,
+	ViolationNoRedeemAsTaker: 2,
+	ViolationCancelRate:      2,
+	ViolationSpam:            5,
+}
+
+// BanScoreConfig configures the graduated penalties applied to accounts as
+// they accumulate ban points. Points are added for each violation and decay
+// exponentially. The consequences escalate with the decayed score: orders are
+// limited to CapLots at CapScore, the account is suspended for
+// SuspensionPeriod at SuspendScore, and, if the operator enables bans, the
+// account is permanently banned at BanScore. A ban is only lifted by the
+// operator.
+type BanScoreConfig struct {
+	// Weights are the points added for each Violation. Violations without a
+	// weight add no points.
+	Weights map[Violation]float64
+	// HalfLife is the time for the score to decay by half.
+	HalfLife time.Duration
+	// CapScore is the score at which the size of new orders is limited to
+	// CapLots lots.
+	CapScore float64
+	CapLots  uint64
+	// SuspendScore is the score at which the account is suspended for
+	// SuspensionPeriod. Suspension unbooks the account's orders.
+	SuspendScore     float64
+	SuspensionPeriod time.Duration
+	// BanScore is the score at which the account is permanently banned. Zero
+	// disables bans.
+	BanScore float64
+}
+
+// DefaultBanScoreConfig is the BanScoreConfig used when none is configured.
+func DefaultBanScoreConfig() *BanScoreConfig {
+	weights := make(map[Violation]float64, len(DefaultBanScoreWeights))
+	for v, w := range DefaultBanScoreWeights {
+		weights[v] = w
+	}
+	return &BanScoreConfig{
+		Weights:          weights,
+		HalfLife:         DefaultBanScoreHalfLife,
+		CapScore:         DefaultBanScoreCap,
+		CapLots:          DefaultBanScoreCapLots,
+		SuspendScore:     DefaultBanScoreSuspend,
+		SuspensionPeriod: DefaultSuspensionPeriod,
+	}
+}
+
+// Validate checks that the thresholds are positive and escalate. A BanScore of
+// zero is permitted, since it disables bans.
+func (cfg *BanScoreConfig) Validate() error {
+	switch {
+	case cfg.HalfLife <= 0:
+		return errors.New("ban score half-life must be positive")
+	case cfg.SuspensionPeriod <= 0:
+		return errors.New("suspension period must be positive")
+	case cfg.CapLots == 0:
+		return errors.New("capped order size must be at least one lot")
+	case cfg.CapScore <= 0:
+		return errors.New("cap score must be positive")
+	case cfg.SuspendScore < cfg.CapScore:
+		return fmt.Errorf("suspend score %v is less than the cap score %v", cfg.SuspendScore, cfg.CapScore)
+	case cfg.BanScore < 0:
+		return errors.New("ban score must not be negative")
+	case cfg.BanScore > 0 && cfg.BanScore < cfg.SuspendScore:
+		return fmt.Errorf("ban score %v is less than the suspend score %v", cfg.BanScore, cfg.SuspendScore)
+	}
+	for v, w := range cfg.Weights {
+		if w < 0 {
+			return fmt.Errorf("negative weight %v for violation %q", w, v)
+		}
+	}
+	return nil
+}
+
+// decayed is the score of the BanScore at time now.
+func (cfg *BanScoreConfig) decayed(bs *db.BanScore, now time.Time) float64 {
+	elapsed := now.Sub(bs.Stamp)
+	if elapsed <= 0 {
+		return bs.Score
+	}
+	return bs.Score * math.Exp2(-float64(elapsed)/float64(cfg.HalfLife))
+}
+
+// standing is the account.Standing for the BanScore at time now.
+func (cfg *BanScoreConfig) standing(bs *db.BanScore, now time.Time) account.Standing {
+	switch {
+	case bs.Banned:
+		return account.StandingBanned
+	case now.Before(bs.SuspendedUntil):
+		return account.StandingSuspended
+	case cfg.decayed(bs, now) >= cfg.CapScore:
+		return account.StandingCapped
+	}
+	return account.StandingGood
+}
+
+// banScore retrieves the user's ban score from the cache or the DB. A user
+// without a stored score has a zero score. The banMtx MUST be locked.
+func (auth *AuthManager) banScore(user account.AccountID) (*db.BanScore, error) {
+	if bs, found := auth.banScores[user]; found {
+		return bs, nil
+	}
+	bs, err := auth.storage.BanScore(user)
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		bs = new(db.BanScore)
+	}
+	auth.banScores[user] = bs
+	return bs, nil
+}
+
+// addBanPoints adds the violation's weight to the user's ban score and
+// escalates the user's standing if the new score crosses a threshold. A
+// suspended or banned user's orders are unbooked. The rule and details are
+// used in the penalty notification.
+func (auth *AuthManager) addBanPoints(user account.AccountID, v Violation, rule account.Rule, details string) {
+	auth.banMtx.Lock()
+	cfg := auth.banCfg
+	weight := cfg.Weights[v]
+	if weight <= 0 {
+		auth.banMtx.Unlock()
+		return
+	}
+	bs, err := auth.banScore(user)
+	if err != nil {
+		auth.banMtx.Unlock()
+		log.Errorf("Error retrieving ban score for user %v: %v", user, err)
+		return
+	}
+	now := time.Now()
+	prevStanding := cfg.standing(bs, now)
+	newBS := &db.BanScore{
+		Score:          cfg.decayed(bs, now) + weight,
+		Stamp:          now,
+		SuspendedUntil: bs.SuspendedUntil,
+		Banned:         bs.Banned,
+	}
+	if cfg.BanScore > 0 && newBS.Score >= cfg.BanScore {
+		newBS.Banned = true
+	} else if newBS.Score >= cfg.SuspendScore && !now.Before(newBS.SuspendedUntil) {
+		newBS.SuspendedUntil = now.Add(cfg.SuspensionPeriod)
+	}
+	auth.banScores[user] = newBS
+	standing := cfg.standing(newBS, now)
+	auth.banMtx.Unlock()
+
+	auth.storeBanScore(user, newBS)
+
+	log.Infof("Ban score for user %v is now %.2f (+%v for %q), standing %v",
+		user, newBS.Score, weight, v, standing)
+
+	if standing <= prevStanding {
+		return
+	}
+	var msg string
+	switch standing {
+	case account.StandingCapped:
+		msg = fmt.Sprintf("Orders for this account are limited to %d lots.", cfg.CapLots)
+	case account.StandingSuspended:
+		auth.unbookUserOrders(user)
+		msg = fmt.Sprintf("Ordering has been suspended for this account until %s.",
+			newBS.SuspendedUntil.UTC().Format(time.RFC3339))
+	case account.StandingBanned:
+		auth.unbookUserOrders(user)
+		msg = "This account has been banned."
+	}
+	log.Warnf("User %v standing changed from %v to %v. Last rule broken = %v. Detail: %s",
+		user, prevStanding, standing, rule, details)
+	auth.sendPenalty(user, rule, msg, details, standing.String())
+}
+
+// storeBanScore saves the user's ban score to the DB. The banMtx is not held
+// during the write, so a score that has since been replaced in the cache is
+// not written, since the newer score will be.
+func (auth *AuthManager) storeBanScore(user account.AccountID, bs *db.BanScore) {
+	auth.banStoreMtx.Lock()
+	defer auth.banStoreMtx.Unlock()
+	auth.banMtx.Lock()
+	cached, found := auth.banScores[user]
+	auth.banMtx.Unlock()
+	if found && cached != bs {
+		return
+	}
+	if err := auth.storage.SetBanScore(user, bs); err != nil {
+		// The new score is still enforced from the cache.
+		log.Errorf("Error storing ban score for user %v: %v", user, err)
+	}
+}
+
+// sendPenalty notifies the user and the EventNotifier of a penalty. standing
+// is the new account.Standing for a ban score penalty, or empty.
+func (auth *AuthManager) sendPenalty(user account.AccountID, rule account.Rule, msg, extraDetails, standing string) {
+	details := fmt.Sprintf("%s\nLast Broken Rule Details: %s\n%s", msg, rule.Description(), extraDetails)
+	penaltyNote := &msgjson.PenaltyNote{
+		Penalty: &msgjson.Penalty{
+			Rule:    rule,
+			Time:    uint64(time.Now().UnixMilli()),
+			Details: details,
+		},
+	}
+	penaltyNote.Sig = auth.SignMsg(penaltyNote.Serialize())
+	note, err := msgjson.NewNotification(msgjson.PenaltyRoute, penaltyNote)
+	if err != nil {
+		log.Errorf("error creating penalty notification: %v", err)
+		return
+	}
+	auth.Notify(user, note)
+
+	auth.notifyEvent(EventAccountPenalized, &AccountPenalized{
+		AccountID: user.String(),
+		Rule:      rule.String(),
+		Details:   extraDetails,
+		Standing:  standing,
+	})
+}
+
+// cancelRateExceeded checks the connected user's recent cancellation rate
+// against the cancellation threshold. New users within the grace limit, and
+// users that are not connected, are not checked.
+func (auth *AuthManager) cancelRateExceeded(user account.AccountID) (rate float64, exceeded bool) {
+	freeCancels, cancelThresh, grace := auth.cancelRules()
+	if freeCancels {
+		return 0, false
+	}
+	auth.violationMtx.Lock()
+	orderOutcomes := auth.orderOutcomes[user]
+	auth.violationMtx.Unlock()
+	if orderOutcomes == nil {
+		return 0, false
+	}
+	total, cancels := orderOutcomes.counts()
+	if total <= grace {
+		return 0, false
+	}
+	rate = float64(cancels) / float64(total)
+	return rate, rate > cancelThresh
+}
+
+// Standing is the user's trading privilege according to their ban score. For
+// a capped user, maxLots is the largest order permitted, in lots. maxLots is
+// zero for other standings.
+func (auth *AuthManager) Standing(user account.AccountID) (standing account.Standing, maxLots uint64) {
+	auth.banMtx.Lock()
+	defer auth.banMtx.Unlock()
+	bs, err := auth.banScore(user)
+	if err != nil {
+		// Don't lock users out of trading for a DB error.
+		log.Errorf("Error retrieving ban score for user %v: %v", user, err)
+		return account.StandingGood, 0
+	}
+	standing = auth.banCfg.standing(bs, time.Now())
+	if standing == account.StandingCapped {
+		maxLots = auth.banCfg.CapLots
+	}
+	return
+}
+
+// RateLimited records a request from the link that was refused for exceeding
+// the rate limits. Authenticated users are given ban points for spam, at most
+// once every spamInterval. RateLimited is intended for comms.Server's
+// rate-limit callback.
+func (auth *AuthManager) RateLimited(conn comms.Link, route string) {
+	client := auth.conn(conn)
+	if client == nil {
+		return
+	}
+	user := client.acct.ID
+	now := time.Now()
+	auth.banMtx.Lock()
+	if now.Sub(auth.lastSpam[user]) < spamInterval {
+		auth.banMtx.Unlock()
+		return
+	}
+	auth.lastSpam[user] = now
+	auth.banMtx.Unlock()
+
+	// The caller is the link's message handling loop, so don't make it wait on
+	// the DB.
+	go auth.addBanPoints(user, ViolationSpam, account.Spam,
+		fmt.Sprintf("request rate limit exceeded for route %q", route))
+}
+
+// BanScoreInfo is the state of an account's ban score.
+type BanScoreInfo struct {
+	// Score is the current, decayed score.
+	Score    float64 `json:"score"`
+	Standing string  `json:"standing"`
+	// MaxLots is the order size limit of a capped account.
+	MaxLots uint64 `json:"maxLots,omitempty"`
+	// SuspendedUntil is the end of the account's current or last suspension,
+	// in milliseconds since the unix epoch.
+	SuspendedUntil int64 `json:"suspendedUntil,omitempty"`
+	Banned         bool  `json:"banned"`
+}
+
+// BanScoreInfo retrieves the user's ban score and standing.
+func (auth *AuthManager) BanScoreInfo(user account.AccountID) (*BanScoreInfo, error) {
+	auth.banMtx.Lock()
+	defer auth.banMtx.Unlock()
+	bs, err := auth.banScore(user)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	standing := auth.banCfg.standing(bs, now)
+	info := &BanScoreInfo{
+		Score:    auth.banCfg.decayed(bs, now),
+		Standing: standing.String(),
+		Banned:   bs.Banned,
+	}
+	if standing == account.StandingCapped {
+		info.MaxLots = auth.banCfg.CapLots
+	}
+	if !bs.SuspendedUntil.IsZero() {
+		info.SuspendedUntil = bs.SuspendedUntil.UnixMilli()
+	}
+	return info, nil
+}
+
+// ClearBanScore resets the user's ban score, lifting any suspension or ban.
+func (auth *AuthManager) ClearBanScore(user account.AccountID) error {
+	bs := &db.BanScore{Stamp: time.Now()}
+	auth.banStoreMtx.Lock()
+	defer auth.banStoreMtx.Unlock()
+	if err := auth.storage.SetBanScore(user, bs); err != nil {
+		return err
+	}
+	auth.banMtx.Lock()
+	auth.banScores[user] = bs
+	auth.banMtx.Unlock()
+	log.Infof("Ban score cleared for user %v", user)
+	return nil
+}
@@ -0,0 +1,53 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package auth
+
+// Account event types sent to the Config.Events notifier. With a
+// *webhook.Notifier, the data is delivered as the Data of a webhook.Event.
+const (
+	// EventAccountRegistered is sent when a new account is created with its
+	// first bond.
+	EventAccountRegistered = "account_registered"
+	// EventBondPosted is sent when a bond is accepted for a new or existing
+	// account. A new account gets both an EventAccountRegistered and an
+	// EventBondPosted.
+	EventBondPosted = "bond_posted"
+	// EventAccountPenalized is sent when an account is penalized for rule
+	// violations. Its orders are unbooked unless the penalty only limits its
+	// order size.
+	EventAccountPenalized = "account_penalized"
+)
+
+// AccountRegistered is the data of an EventAccountRegistered event.
+type AccountRegistered struct {
+	AccountID string `json:"accountID"`
+	// Addr is the network address of the client that registered.
+	Addr string `json:"addr"`
+}
+
+// BondPosted is the data of an EventBondPosted event.
+type BondPosted struct {
+	AccountID string `json:"accountID"`
+	AssetID   uint32 `json:"assetID"`
+	Symbol    string `json:"symbol"`
+	CoinID    string `json:"coinID"`
+	Amount    int64  `json:"amount"`
+	Strength  uint32 `json:"strength"`
+	LockTime  int64  `json:"lockTime"`
+	Prepaid   bool   `json:"prepaid,omitempty"`
+	// BondedTier and Tier are the account's tiers with the new bond.
+	BondedTier int64 `json:"bondedTier"`
+	Tier       int64 `json:"tier"`
+}
+
+// AccountPenalized is the data of an EventAccountPenalized event.
+type AccountPenalized struct {
+	AccountID string `json:"accountID"`
+	Rule      string `json:"rule"`
+	Details   string `json:"details,omitempty"`
+	// Standing is the account's new standing when the penalty is from the
+	// ban score, e.g. "suspended" or "banned". It is empty for penalties from
+	// the reputation score.
+	Standing string `json:"standing,omitempty"`
+}
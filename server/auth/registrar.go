@@ -341,6 +341,8 @@ func (auth *AuthManager) storeBondAndRespond(conn comms.Link, bond *db.Bond, acc
 	log.Infof("Bond accepted: acct %v from %v locked %d in %v. Bond total %d, tier %d",
 		acctID, conn.Addr(), bond.Amount, coinIDString(bond.AssetID, coinID), rep.BondedTier, rep.EffectiveTier())
 
+	auth.notifyBondEvents(conn, acctID, bond, newAcct, rep)
+
 	// Respond
 	resp, err := msgjson.NewResponse(reqID, postBondRes, nil)
 	if err != nil { // shouldn't be possible
@@ -359,6 +361,41 @@ func (auth *AuthManager) storeBondAndRespond(conn comms.Link, bond *db.Bond, acc
 	}
 }
 
+// notifyBondEvents notifies the EventNotifier of an accepted bond, and of the
+// account's registration if the bond created the account.
+func (auth *AuthManager) notifyBondEvents(conn comms.Link, acctID account.AccountID, bond *db.Bond,
+	newAcct bool, rep *account.Reputation) {
+	if auth.events == nil {
+		return
+	}
+	if newAcct {
+		auth.events.Notify(EventAccountRegistered, &AccountRegistered{
+			AccountID: acctID.String(),
+			Addr:      conn.Addr(),
+		})
+	}
+	prepaid := bond.AssetID == account.PrepaidBondID
+	var coinID, symbol string
+	if prepaid {
+		coinID = dex.Bytes(bond.CoinID).String()
+	} else {
+		coinID = coinIDString(bond.AssetID, bond.CoinID)
+		symbol = dex.BipIDSymbol(bond.AssetID)
+	}
+	auth.events.Notify(EventBondPosted, &BondPosted{
+		AccountID:  acctID.String(),
+		AssetID:    bond.AssetID,
+		Symbol:     symbol,
+		CoinID:     coinID,
+		Amount:     bond.Amount,
+		Strength:   bond.Strength,
+		LockTime:   bond.LockTime,
+		Prepaid:    prepaid,
+		BondedTier: rep.BondedTier,
+		Tier:       rep.EffectiveTier(),
+	})
+}
+
 func (auth *AuthManager) processPrepaidBond(conn comms.Link, msg *msgjson.Message, acct *account.Account, coinID []byte) *msgjson.Error {
 	auth.prepaidBondMtx.Lock()
 	defer auth.prepaidBondMtx.Unlock()
@@ -423,6 +460,8 @@ func (auth *AuthManager) processPrepaidBond(conn comms.Link, msg *msgjson.Messag
 	log.Infof("Pre-paid bond accepted: acct %v from %v. Bonded tier %d, effective tier %d",
 		acct.ID, conn.Addr(), rep.BondedTier, rep.EffectiveTier())
 
+	auth.notifyBondEvents(conn, acct.ID, dbBond, newAcct, rep)
+
 	resp, err := msgjson.NewResponse(msg.ID, postBondRes, nil)
 	if err != nil { // shouldn't be possible
 		return nil
@@ -40,6 +40,9 @@ const (
 	defaultLogDirname          = "logs"
 	defaultMarketsConfFilename = "markets.json"
 	defaultMaxLogZips          = 128
+	defaultDBDriver            = "pg"
+	defaultSQLiteFilename      = "dcrdex.db"
+	defaultACMECacheDirname    = "acme"
 	defaultPGHost              = "127.0.0.1:5432"
 	defaultPGUser              = "dcrdex"
 	defaultPGDBName            = "dcrdex_{netname}"
@@ -70,6 +73,8 @@ type procOpts struct {
 type dexConf struct {
 	DataDir          string
 	Network          dex.Network
+	DBDriver         string
+	SQLitePath       string
 	DBName           string
 	DBUser           string
 	DBPass           string
@@ -81,15 +86,22 @@ type dexConf struct {
 	FreeCancels      bool
 	MaxUserCancels   uint32
 	PenaltyThreshold uint32
+	MinClientAPIVer  uint16
 	DEXPrivKeyPath   string
 	RPCCert          string
 	RPCKey           string
 	NoTLS            bool
 	RPCListen        []string
 	HiddenService    string
+	HiddenServiceTLS bool
 	BroadcastTimeout time.Duration
 	TxWaitExpiration time.Duration
 	AltDNSNames      []string
+	ACMEHosts        []string
+	ACMECacheDir     string
+	ACMEEmail        string
+	ACMEDirectoryURL string
+	ACMEHTTPListen   string
 	LogMaker         *dex.LoggerMaker
 	SigningKeyPW     []byte
 	AdminSrvOn       bool
@@ -100,6 +112,10 @@ type dexConf struct {
 	DisableDataAPI   bool
 	NodeRelayAddr    string
 	ValidateMarkets  bool
+	ArchiveRetention time.Duration
+	WebhookURLs      []string
+	WebhookSecret    string
+	BanScore         *auth.BanScoreConfig
 }
 
 type flagsData struct {
@@ -116,12 +132,19 @@ type flagsData struct {
 	Testnet bool `long:"testnet" description:"Use the test network (default mainnet)."`
 	Simnet  bool `long:"simnet" description:"Use the simulation test network (default mainnet)."`
 
-	RPCCert       string   `long:"rpccert" description:"RPC server TLS certificate file."`
-	RPCKey        string   `long:"rpckey" description:"RPC server TLS private key file."`
-	RPCListen     []string `long:"rpclisten" description:"IP addresses on which the RPC server should listen for incoming connections."`
-	NoTLS         bool     `long:"notls" description:"Run without TLS encryption."`
-	AltDNSNames   []string `long:"altdnsnames" description:"A list of hostnames to include in the RPC certificate (X509v3 Subject Alternative Name)."`
-	HiddenService string   `long:"hiddenservice" description:"A host:port on which the RPC server should listen for incoming hidden service connections. No TLS is used for these connections."`
+	RPCCert          string   `long:"rpccert" description:"RPC server TLS certificate file."`
+	RPCKey           string   `long:"rpckey" description:"RPC server TLS private key file."`
+	RPCListen        []string `long:"rpclisten" description:"IP addresses on which the RPC server should listen for incoming connections."`
+	NoTLS            bool     `long:"notls" description:"Run without TLS encryption."`
+	AltDNSNames      []string `long:"altdnsnames" description:"A list of hostnames to include in the RPC certificate (X509v3 Subject Alternative Name)."`
+	HiddenService    string   `long:"hiddenservice" description:"A host:port on which the RPC server should listen for incoming hidden service connections. No TLS is used for these connections unless hiddenservicetls is set."`
+	HiddenServiceTLS bool     `long:"hiddenservicetls" description:"Use TLS with the RPC certificate on the hidden service listener. Clients must add the .onion host with the wss:// scheme, e.g. wss://{address}.onion:7232, and provide the certificate, which is pinned."`
+
+	ACMEHosts        []string `long:"acmehost" description:"Host name for which to obtain a browser-trusted certificate from an ACME certificate authority (Let's Encrypt by default) and renew it automatically. The certificate authority must reach the server at the host on port 443, or on port 80 with acmehttplisten. Other host names, and any failure to obtain the certificate, fall back to the rpccert key pair. May be specified multiple times."`
+	ACMECacheDir     string   `long:"acmecachedir" description:"Directory to store the ACME account key and certificates. (default: acme in the application home directory)"`
+	ACMEEmail        string   `long:"acmeemail" description:"Contact email address for the ACME account."`
+	ACMEDirectoryURL string   `long:"acmedirectory" description:"ACME directory URL, e.g. a staging environment for testing. (default: Let's Encrypt production)"`
+	ACMEHTTPListen   string   `long:"acmehttplisten" description:"Address on which to answer ACME HTTP challenges, e.g. :80. By default, only TLS-ALPN challenges on the RPC listeners are answered."`
 
 	MarketsConfPath  string        `long:"marketsconfpath" description:"Path to the markets configuration JSON file."`
 	BroadcastTimeout time.Duration `long:"bcasttimeout" description:"The broadcast timeout specifies how long clients have to broadcast an expected transaction when it is their turn to act. Matches without the expected action by this time are revoked and the actor is penalized (default: 12 minutes)."`
@@ -132,10 +155,20 @@ type flagsData struct {
 	FreeCancels      bool    `long:"freecancels" description:"No cancellation rate enforcement (unlimited cancel orders)."`
 	MaxUserCancels   uint32  `long:"maxepochcancels" description:"The maximum number of cancel orders allowed for a user in a given epoch."`
 	PenaltyThreshold uint32  `long:"penaltythreshold" description:"The accumulated penalty score at which when a bond is revoked."`
+	MinClientAPIVer  uint16  `long:"minclientapiver" description:"The minimum client API version permitted to connect. Older clients are refused and told to upgrade. (default: 0, accept all)"`
+
+	BanScoreHalfLife time.Duration `long:"banscorehalflife" description:"The time for an account's ban score to decay by half."`
+	BanScoreCap      float64       `long:"banscorecap" description:"The ban score at which an account's orders are limited to banscorecaplots lots."`
+	BanScoreCapLots  uint64        `long:"banscorecaplots" description:"The largest order, in lots, permitted for an account with a ban score above banscorecap."`
+	BanScoreSuspend  float64       `long:"banscoresuspend" description:"The ban score at which an account's orders are unbooked and it is suspended from trading for suspensionperiod."`
+	SuspensionPeriod time.Duration `long:"suspensionperiod" description:"How long an account is suspended from trading when its ban score reaches banscoresuspend."`
+	BanScoreBan      float64       `long:"banscoreban" description:"The ban score at which an account is permanently banned from trading. Only the operator can lift a ban. (default: 0, bans disabled)"`
 
 	HTTPProfile bool   `long:"httpprof" short:"p" description:"Start HTTP profiler."`
 	CPUProfile  string `long:"cpuprofile" description:"File for CPU profiling."`
 
+	DBDriver           string `long:"dbdriver" description:"The DB backend, pg (PostgreSQL) or sqlite."`
+	SQLitePath         string `long:"sqlitepath" description:"Path to the SQLite DB file for dbdriver=sqlite. A relative path is relative to the network's data directory. (default: dcrdex.db)"`
 	PGDBName           string `long:"pgdbname" description:"PostgreSQL DB name."`
 	PGUser             string `long:"pguser" description:"PostgreSQL DB user."`
 	PGPass             string `long:"pgpass" description:"PostgreSQL DB password."`
@@ -149,6 +182,11 @@ type flagsData struct {
 
 	NoResumeSwaps bool `long:"noresumeswaps" description:"Do not attempt to resume swaps that are active in the DB."`
 
+	ArchiveRetentionDays uint16 `long:"archiveretention" description:"Prune archived matches, cancel orders, and epoch data older than this many days. Active swaps, and the 100 most recent matches and cancel orders of each account, are never pruned. Must be at least 30 if set. (default: 0, keep everything)"`
+
+	WebhookURLs   []string `long:"webhookurl" description:"URL to which account registration, bond, and penalty events are POSTed as JSON. Requires webhooksecret. May be specified multiple times."`
+	WebhookSecret string   `long:"webhooksecret" description:"Secret key with which webhook request bodies are signed. The hex-encoded HMAC-SHA256 of the body is sent in the X-Dcrdex-Signature header, prefixed with sha256=."`
+
 	DisableDataAPI bool `long:"nodata" description:"Disable the HTTP data API."`
 
 	NodeRelayAddr string `long:"noderelayaddr" description:"The public address by which node sources should connect to the node relay"`
@@ -255,6 +293,9 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		RPCCert:          defaultRPCCertFilename,
 		RPCKey:           defaultRPCKeyFilename,
 		DebugLevel:       defaultLogLevel,
+		DBDriver:         defaultDBDriver,
+		SQLitePath:       defaultSQLiteFilename,
+		ACMECacheDir:     defaultACMECacheDirname,
 		PGDBName:         defaultPGDBName,
 		PGUser:           defaultPGUser,
 		PGHost:           defaultPGHost,
@@ -265,6 +306,11 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		CancelThreshold:  defaultCancelThresh,
 		MaxUserCancels:   defaultMaxUserCancels,
 		PenaltyThreshold: defaultPenaltyThresh,
+		BanScoreHalfLife: auth.DefaultBanScoreHalfLife,
+		BanScoreCap:      auth.DefaultBanScoreCap,
+		BanScoreCapLots:  auth.DefaultBanScoreCapLots,
+		BanScoreSuspend:  auth.DefaultBanScoreSuspend,
+		SuspensionPeriod: auth.DefaultSuspensionPeriod,
 	}
 
 	// Pre-parse the command line options to see if an alternative config file
@@ -449,6 +495,31 @@ func loadConfig() (*dexConf, *procOpts, error) {
 	if !filepath.IsAbs(cfg.DEXPrivKeyPath) {
 		cfg.DEXPrivKeyPath = filepath.Join(cfg.AppDataDir, cfg.DEXPrivKeyPath)
 	}
+	if len(cfg.ACMEHosts) > 0 {
+		if cfg.NoTLS {
+			return loadConfigError(fmt.Errorf("acmehost cannot be used with notls"))
+		}
+		cfg.ACMECacheDir = dex.CleanAndExpandPath(cfg.ACMECacheDir)
+		if !filepath.IsAbs(cfg.ACMECacheDir) {
+			cfg.ACMECacheDir = filepath.Join(cfg.AppDataDir, cfg.ACMECacheDir)
+		}
+	}
+	if len(cfg.WebhookURLs) > 0 && cfg.WebhookSecret == "" {
+		return loadConfigError(fmt.Errorf("webhookurl requires webhooksecret"))
+	}
+	switch cfg.DBDriver {
+	case "pg":
+	case "sqlite":
+		if !db.Registered("sqlite") {
+			return loadConfigError(fmt.Errorf("this build does not include the sqlite DB driver, which requires cgo"))
+		}
+		cfg.SQLitePath = dex.CleanAndExpandPath(cfg.SQLitePath)
+		if !filepath.IsAbs(cfg.SQLitePath) {
+			cfg.SQLitePath = filepath.Join(cfg.DataDir, cfg.SQLitePath)
+		}
+	default:
+		return loadConfigError(fmt.Errorf("unknown DB driver %q", cfg.DBDriver))
+	}
 
 	// Validate each RPC listen host:port.
 	var RPCListen []string
@@ -526,12 +597,25 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		adminSrvAddr = cfg.AdminSrvAddr
 	}
 
+	banScore := auth.DefaultBanScoreConfig()
+	banScore.HalfLife = cfg.BanScoreHalfLife
+	banScore.CapScore = cfg.BanScoreCap
+	banScore.CapLots = cfg.BanScoreCapLots
+	banScore.SuspendScore = cfg.BanScoreSuspend
+	banScore.SuspensionPeriod = cfg.SuspensionPeriod
+	banScore.BanScore = cfg.BanScoreBan
+	if err := banScore.Validate(); err != nil {
+		return loadConfigError(fmt.Errorf("invalid ban score settings: %w", err))
+	}
+
 	// If using {netname} then replace it with the network name.
 	cfg.PGDBName = strings.ReplaceAll(cfg.PGDBName, "{netname}", network.String())
 
 	dexCfg := &dexConf{
 		DataDir:          cfg.DataDir,
 		Network:          network,
+		DBDriver:         cfg.DBDriver,
+		SQLitePath:       cfg.SQLitePath,
 		DBName:           cfg.PGDBName,
 		DBHost:           dbHost,
 		DBPort:           dbPort,
@@ -543,15 +627,22 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		MaxUserCancels:   cfg.MaxUserCancels,
 		FreeCancels:      cfg.FreeCancels,
 		PenaltyThreshold: cfg.PenaltyThreshold,
+		MinClientAPIVer:  cfg.MinClientAPIVer,
 		DEXPrivKeyPath:   cfg.DEXPrivKeyPath,
 		RPCCert:          cfg.RPCCert,
 		RPCKey:           cfg.RPCKey,
 		NoTLS:            cfg.NoTLS,
 		RPCListen:        RPCListen,
 		HiddenService:    HiddenService,
+		HiddenServiceTLS: cfg.HiddenServiceTLS,
 		BroadcastTimeout: cfg.BroadcastTimeout,
 		TxWaitExpiration: cfg.TxWaitExpiration,
 		AltDNSNames:      cfg.AltDNSNames,
+		ACMEHosts:        cfg.ACMEHosts,
+		ACMECacheDir:     cfg.ACMECacheDir,
+		ACMEEmail:        cfg.ACMEEmail,
+		ACMEDirectoryURL: cfg.ACMEDirectoryURL,
+		ACMEHTTPListen:   cfg.ACMEHTTPListen,
 		LogMaker:         logMaker,
 		SigningKeyPW:     []byte(cfg.SigningKeyPassword),
 		AdminSrvAddr:     adminSrvAddr,
@@ -562,6 +653,10 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		DisableDataAPI:   cfg.DisableDataAPI,
 		NodeRelayAddr:    cfg.NodeRelayAddr,
 		ValidateMarkets:  cfg.ValidateMarkets,
+		ArchiveRetention: time.Duration(cfg.ArchiveRetentionDays) * 24 * time.Hour,
+		WebhookURLs:      cfg.WebhookURLs,
+		WebhookSecret:    cfg.WebhookSecret,
+		BanScore:         banScore,
 	}
 
 	opts := &procOpts{
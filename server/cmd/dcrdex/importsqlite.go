@@ -0,0 +1,13 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+//
+// The sqlite DB driver uses the cgo-only go-sqlite3 package, so it is only
+// included in builds with cgo enabled.
+
+//go:build cgo
+
+package main
+
+import (
+	_ "decred.org/dcrdex/server/db/driver/sqlite" // register sqlite DB driver
+)
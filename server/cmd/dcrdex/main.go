@@ -123,6 +123,8 @@ func mainCore(ctx context.Context) error {
 		Assets:     assets,
 		Network:    cfg.Network,
 		DBConf: &dexsrv.DBConf{
+			Driver:       cfg.DBDriver,
+			SQLitePath:   cfg.SQLitePath,
 			DBName:       cfg.DBName,
 			Host:         cfg.DBHost,
 			User:         cfg.DBUser,
@@ -130,12 +132,13 @@ func mainCore(ctx context.Context) error {
 			Pass:         cfg.DBPass,
 			ShowPGConfig: cfg.ShowPGConfig,
 		},
-		BroadcastTimeout: cfg.BroadcastTimeout,
-		TxWaitExpiration: cfg.TxWaitExpiration,
-		CancelThreshold:  cfg.CancelThreshold,
-		FreeCancels:      cfg.FreeCancels,
-		PenaltyThreshold: cfg.PenaltyThreshold,
-		DEXPrivKey:       privKey,
+		BroadcastTimeout:    cfg.BroadcastTimeout,
+		TxWaitExpiration:    cfg.TxWaitExpiration,
+		CancelThreshold:     cfg.CancelThreshold,
+		FreeCancels:         cfg.FreeCancels,
+		PenaltyThreshold:    cfg.PenaltyThreshold,
+		MinClientAPIVersion: cfg.MinClientAPIVer,
+		DEXPrivKey:          privKey,
 		CommsCfg: &dexsrv.RPCConfig{
 			RPCCert:           cfg.RPCCert,
 			NoTLS:             cfg.NoTLS,
@@ -144,9 +147,19 @@ func mainCore(ctx context.Context) error {
 			AltDNSNames:       cfg.AltDNSNames,
 			DisableDataAPI:    cfg.DisableDataAPI,
 			HiddenServiceAddr: cfg.HiddenService,
+			HiddenServiceTLS:  cfg.HiddenServiceTLS,
+			ACMEHosts:         cfg.ACMEHosts,
+			ACMECacheDir:      cfg.ACMECacheDir,
+			ACMEEmail:         cfg.ACMEEmail,
+			ACMEDirectoryURL:  cfg.ACMEDirectoryURL,
+			ACMEHTTPListen:    cfg.ACMEHTTPListen,
 		},
-		NoResumeSwaps: cfg.NoResumeSwaps,
-		NodeRelayAddr: cfg.NodeRelayAddr,
+		NoResumeSwaps:    cfg.NoResumeSwaps,
+		NodeRelayAddr:    cfg.NodeRelayAddr,
+		ArchiveRetention: cfg.ArchiveRetention,
+		WebhookURLs:      cfg.WebhookURLs,
+		WebhookSecret:    cfg.WebhookSecret,
+		BanScore:         cfg.BanScore,
 	}
 	dexMan, err := dexsrv.NewDEX(ctx, dexConf) // ctx cancel just aborts setup; Stop does normal shutdown
 	if err != nil {
@@ -154,6 +167,7 @@ func mainCore(ctx context.Context) error {
 	}
 
 	var wg sync.WaitGroup
+	reloadTLS := dexMan.ReloadTLS
 	if cfg.AdminSrvOn {
 		srvCFG := &admin.SrvConfig{
 			Core:    dexMan,
@@ -167,12 +181,16 @@ func mainCore(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("cannot set up admin server: %v", err)
 		}
+		reloadTLS = adminServer.ReloadTLS // admin and comms certs
 		wg.Add(1)
 		go func() {
 			adminServer.Run(ctx)
 			wg.Done()
 		}()
 	}
+	if !cfg.NoTLS || (cfg.AdminSrvOn && !cfg.AdminSrvNoTLS) {
+		go tlsReloadListener(ctx, reloadTLS)
+	}
 
 	log.Info("The DEX is running. Hit CTRL+C to quit...")
 	<-ctx.Done()
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 )
 
 // shutdownRequested checks if the Done channel of the given context has been
@@ -81,3 +82,25 @@ func shutdownListener() {
 		log.Info("Shutdown signaled. Already shutting down...")
 	}
 }
+
+// tlsReloadListener calls reload each time a SIGHUP is received until the
+// context is canceled. This allows renewed TLS certificates to be loaded
+// without restarting. This function is intended to be spawned in a new
+// goroutine.
+func tlsReloadListener(ctx context.Context, reload func() error) {
+	hupChannel := make(chan os.Signal, 1)
+	signal.Notify(hupChannel, syscall.SIGHUP)
+	defer signal.Stop(hupChannel)
+
+	for {
+		select {
+		case <-hupChannel:
+			log.Info("Received SIGHUP. Reloading TLS certificates...")
+			if err := reload(); err != nil {
+				log.Errorf("Failed to reload TLS certificates: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
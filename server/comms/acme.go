@@ -0,0 +1,96 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package comms
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// acmeCertificates provides certificates for the ACME hosts from an ACME
+// certificate authority such as Let's Encrypt, and the fallback key pair's
+// certificate for any other server name or if the ACME certificate cannot be
+// obtained. The autocert.Manager renews certificates before they expire, and
+// new handshakes use the renewed certificate.
+type acmeCertificates struct {
+	mgr      *autocert.Manager
+	hosts    map[string]bool
+	fallback *TLSKeyPair
+}
+
+func newACMECertificates(cfg *RPCConfig, fallback *TLSKeyPair) (*acmeCertificates, error) {
+	if cfg.ACMECacheDir == "" {
+		return nil, fmt.Errorf("no ACME cache directory specified")
+	}
+	hosts := make(map[string]bool, len(cfg.ACMEHosts))
+	for _, host := range cfg.ACMEHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" || net.ParseIP(host) != nil {
+			return nil, fmt.Errorf("invalid ACME host name %q", host)
+		}
+		hosts[host] = true
+	}
+	mgr := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cfg.ACMECacheDir),
+		HostPolicy: autocert.HostWhitelist(cfg.ACMEHosts...),
+		Email:      cfg.ACMEEmail,
+	}
+	if cfg.ACMEDirectoryURL != "" {
+		mgr.Client = &acme.Client{DirectoryURL: cfg.ACMEDirectoryURL}
+	}
+	return &acmeCertificates{
+		mgr:      mgr,
+		hosts:    hosts,
+		fallback: fallback,
+	}, nil
+}
+
+// isACMEChallenge checks if the ClientHello is for a tls-alpn-01 challenge.
+func isACMEChallenge(hello *tls.ClientHelloInfo) bool {
+	return len(hello.SupportedProtos) == 1 && hello.SupportedProtos[0] == acme.ALPNProto
+}
+
+// GetCertificate satisfies the tls.Config.GetCertificate field's signature.
+func (ac *acmeCertificates) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if isACMEChallenge(hello) {
+		return ac.mgr.GetCertificate(hello)
+	}
+	if ac.hosts[strings.ToLower(hello.ServerName)] {
+		cert, err := ac.mgr.GetCertificate(hello)
+		if err == nil {
+			return cert, nil
+		}
+		log.Warnf("Unable to get ACME certificate for %s, using %s: %v",
+			hello.ServerName, ac.fallback.certFile, err)
+	}
+	return ac.fallback.GetCertificate(hello)
+}
+
+// TLSConfig creates a server *tls.Config that answers tls-alpn-01 challenges
+// and uses the ACME certificates.
+func (ac *acmeCertificates) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: ac.GetCertificate,
+		NextProtos:     []string{"http/1.1", acme.ALPNProto},
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// challengeServer creates an HTTP server that answers http-01 challenges and
+// redirects all other requests to https.
+func (ac *acmeCertificates) challengeServer() *http.Server {
+	return &http.Server{
+		Handler:      ac.mgr.HTTPHandler(nil),
+		ReadTimeout:  rpcTimeoutSeconds * time.Second,
+		WriteTimeout: rpcTimeoutSeconds * time.Second,
+	}
+}
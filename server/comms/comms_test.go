@@ -22,6 +22,7 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/ws"
+	"decred.org/dcrdex/server/account"
 	"github.com/gorilla/websocket"
 )
 
@@ -672,6 +673,32 @@ func TestClientResponses(t *testing.T) {
 	client.reqMtx.Unlock()
 }
 
+func TestHiddenServiceTLS(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &RPCConfig{
+		HiddenServiceAddr: "127.0.0.1:0",
+		HiddenServiceTLS:  true,
+		RPCKey:            filepath.Join(tempDir, "rpc.key"),
+		RPCCert:           filepath.Join(tempDir, "rpc.cert"),
+	}
+	server, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("server constructor error: %v", err)
+	}
+	if len(server.listeners) != 1 {
+		t.Fatalf("expected 1 listener, got %d", len(server.listeners))
+	}
+	if _, is := server.listeners[0].(onionListener); !is {
+		t.Fatalf("hidden service listener is a %T", server.listeners[0])
+	}
+	server.listeners[0].Close()
+
+	cfg.NoTLS = true
+	if _, err = NewServer(cfg); err == nil {
+		t.Fatalf("no error for hidden service TLS with TLS disabled")
+	}
+}
+
 func TestOnline(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -896,6 +923,128 @@ func TestParseListeners(t *testing.T) {
 	}
 }
 
+func TestReloadTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "rpc.cert"), filepath.Join(dir, "rpc.key")
+	if err := genCertPair(certFile, keyFile, nil); err != nil {
+		t.Fatalf("genCertPair error: %v", err)
+	}
+
+	s := &Server{}
+	if err := s.ReloadTLS(); err != nil {
+		t.Fatalf("error reloading with TLS disabled: %v", err)
+	}
+
+	keyPair, err := NewTLSKeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewTLSKeyPair error: %v", err)
+	}
+	s.keyPair = keyPair
+	cert0 := keyPair.Certificate()
+
+	// Replace the files with a new pair and reload.
+	os.Remove(certFile)
+	os.Remove(keyFile)
+	if err := genCertPair(certFile, keyFile, nil); err != nil {
+		t.Fatalf("genCertPair error: %v", err)
+	}
+	if err := s.ReloadTLS(); err != nil {
+		t.Fatalf("ReloadTLS error: %v", err)
+	}
+	cert1, _ := keyPair.GetCertificate(nil)
+	if bytes.Equal(cert0.Certificate[0], cert1.Certificate[0]) {
+		t.Fatalf("certificate not reloaded")
+	}
+
+	// A bad pair is rejected and the current certificate kept.
+	if err := os.WriteFile(keyFile, []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ReloadTLS(); err == nil {
+		t.Fatalf("no error reloading a bad key pair")
+	}
+	if keyPair.Certificate() != cert1 {
+		t.Fatalf("certificate replaced by a bad key pair")
+	}
+}
+
+func TestACMECertificates(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "rpc.cert"), filepath.Join(dir, "rpc.key")
+	if err := genCertPair(certFile, keyFile, nil); err != nil {
+		t.Fatalf("genCertPair error: %v", err)
+	}
+	fallback, err := NewTLSKeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewTLSKeyPair error: %v", err)
+	}
+
+	// Put a certificate for one of the hosts in the ACME cache, which is the
+	// key followed by the certificate.
+	const cachedHost, uncachedHost = "dex.example.com", "other.example.com"
+	cacheDir := filepath.Join(dir, "acme")
+	if err = os.Mkdir(cacheDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	acmeCertFile, acmeKeyFile := filepath.Join(dir, "acme.cert"), filepath.Join(dir, "acme.key")
+	if err = genCertPair(acmeCertFile, acmeKeyFile, []string{cachedHost}); err != nil {
+		t.Fatalf("genCertPair error: %v", err)
+	}
+	acmeCert, _ := os.ReadFile(acmeCertFile)
+	acmeKey, _ := os.ReadFile(acmeKeyFile)
+	if err = os.WriteFile(filepath.Join(cacheDir, cachedHost), append(acmeKey, acmeCert...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	acmeLeaf, _ := tls.LoadX509KeyPair(acmeCertFile, acmeKeyFile)
+
+	ac, err := newACMECertificates(&RPCConfig{
+		ACMEHosts:        []string{cachedHost, uncachedHost},
+		ACMECacheDir:     cacheDir,
+		ACMEDirectoryURL: "http://127.0.0.1:1/directory", // nothing listening
+	}, fallback)
+	if err != nil {
+		t.Fatalf("newACMECertificates error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		serverName string
+		wantACME   bool
+	}{{
+		name:       "cached ACME cert",
+		serverName: cachedHost,
+		wantACME:   true,
+	}, {
+		name:       "ACME failure falls back",
+		serverName: uncachedHost,
+	}, {
+		name:       "unknown host",
+		serverName: "dex.example.org",
+	}, {
+		name: "no SNI",
+	}}
+	for _, test := range tests {
+		cert, err := ac.GetCertificate(&tls.ClientHelloInfo{
+			ServerName:   test.serverName,
+			CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+		})
+		if err != nil {
+			t.Fatalf("%s: GetCertificate error: %v", test.name, err)
+		}
+		want := fallback.Certificate().Certificate[0]
+		if test.wantACME {
+			want = acmeLeaf.Certificate[0]
+		}
+		if !bytes.Equal(cert.Certificate[0], want) {
+			t.Fatalf("%s: wrong certificate", test.name)
+		}
+	}
+
+	if _, err = newACMECertificates(&RPCConfig{ACMEHosts: []string{"127.0.0.1"}, ACMECacheDir: cacheDir}, fallback); err == nil {
+		t.Fatalf("no error for an IP address host")
+	}
+}
+
 type tHTTPHandler struct {
 	count uint32
 }
@@ -1039,3 +1188,75 @@ func TestWSRateLimiter(t *testing.T) {
 		}
 	}()
 }
+
+func TestDrain(t *testing.T) {
+	server := newServer()
+	server.clients[1] = &wsLink{}
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+
+	w := httptest.NewRecorder()
+	if server.refuseIfDraining(w, req) {
+		t.Fatalf("connection refused when not draining")
+	}
+
+	const redirect = "wss://dex2.example.com:7232/ws"
+	status := server.Drain(redirect)
+	if !status.Draining || status.RedirectAddr != redirect || status.Since == 0 || status.Connections != 1 {
+		t.Fatalf("wrong drain status %+v", status)
+	}
+
+	for i := 0; i < 2; i++ {
+		w = httptest.NewRecorder()
+		if !server.refuseIfDraining(w, req) {
+			t.Fatalf("connection not refused while draining")
+		}
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("wrong status code %d", w.Code)
+		}
+		var resp msgjson.DrainResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("error decoding drain response: %v", err)
+		}
+		if resp.RetryAddr != redirect || resp.Error == "" {
+			t.Fatalf("wrong drain response %+v", resp)
+		}
+	}
+	if status = server.DrainStatus(); status.Refused != 2 {
+		t.Fatalf("expected 2 refused connections, got %d", status.Refused)
+	}
+
+	// Accounts with active swaps are admitted.
+	swapper := account.AccountID{0x01}
+	server.AdmitWhileDraining(func(user account.AccountID) bool { return user == swapper })
+	for _, acctID := range []string{"", "zz", account.AccountID{0x02}.String()} {
+		req.Header.Set(msgjson.AccountIDHeader, acctID)
+		if !server.refuseIfDraining(httptest.NewRecorder(), req) {
+			t.Fatalf("connection with account ID %q not refused", acctID)
+		}
+	}
+	req.Header.Set(msgjson.AccountIDHeader, swapper.String())
+	if server.refuseIfDraining(httptest.NewRecorder(), req) {
+		t.Fatalf("connection from account with active swaps refused")
+	}
+	if status = server.DrainStatus(); status.Refused != 5 || status.Admitted != 1 {
+		t.Fatalf("wrong refused and admitted counts %+v", status)
+	}
+	req.Header.Del(msgjson.AccountIDHeader)
+
+	// Refused without a redirect.
+	server.Drain("")
+	w = httptest.NewRecorder()
+	server.refuseIfDraining(w, req)
+	if strings.Contains(w.Body.String(), "retryAddr") {
+		t.Fatalf("retry address given without a redirect: %s", w.Body.String())
+	}
+
+	status = server.StopDraining()
+	if status.Draining || status.RedirectAddr != "" {
+		t.Fatalf("still draining: %+v", status)
+	}
+	w = httptest.NewRecorder()
+	if server.refuseIfDraining(w, req) {
+		t.Fatalf("connection refused after draining stopped")
+	}
+}
@@ -0,0 +1,132 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package comms
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/server/account"
+)
+
+// DrainStatus describes the Server's draining mode.
+type DrainStatus struct {
+	Draining bool `json:"draining"`
+	// RedirectAddr is the address at which refused clients are told to retry.
+	RedirectAddr string `json:"redirectAddr,omitempty"`
+	// Since is when draining began, in milliseconds since the unix epoch.
+	Since int64 `json:"since,omitempty"`
+	// Connections is the number of open websocket connections.
+	Connections uint64 `json:"connections"`
+	// Refused is the number of websocket connections refused since draining
+	// began.
+	Refused uint64 `json:"refused"`
+	// Admitted is the number of websocket connections accepted since draining
+	// began from accounts with active swaps.
+	Admitted uint64 `json:"admitted"`
+}
+
+// AdmitWhileDraining registers a function that decides whether to accept a
+// websocket connection from an account while the Server is draining, e.g. so
+// that clients with active swaps can reconnect to finish them. The account is
+// the one given by the client in the msgjson.AccountIDHeader, which is not
+// authenticated, so admission must not grant anything but the connection.
+// AdmitWhileDraining should be called before the Server is started.
+func (s *Server) AdmitWhileDraining(f func(account.AccountID) bool) {
+	s.drainAdmit = f
+}
+
+// Drain puts the Server in draining mode for a rolling restart. Existing
+// websocket connections are unaffected, but new connections are refused unless
+// admitted by the AdmitWhileDraining function. If redirectAddr is not empty,
+// refused clients are told to retry at that websocket URL. Calling Drain while
+// draining updates the redirect address.
+func (s *Server) Drain(redirectAddr string) *DrainStatus {
+	s.drainMtx.Lock()
+	if !s.draining {
+		s.draining = true
+		s.drainStart = time.Now()
+		s.drainRefused = 0
+		s.drainAdmitted = 0
+	}
+	s.drainRedirect = redirectAddr
+	s.drainMtx.Unlock()
+	log.Infof("Draining websocket connections. New connections are refused (redirect address %q).", redirectAddr)
+	return s.DrainStatus()
+}
+
+// StopDraining ends draining mode, and new connections are accepted again.
+func (s *Server) StopDraining() *DrainStatus {
+	s.drainMtx.Lock()
+	s.draining = false
+	s.drainRedirect = ""
+	s.drainMtx.Unlock()
+	log.Infof("Stopped draining. New websocket connections are accepted.")
+	return s.DrainStatus()
+}
+
+// DrainStatus reports the draining mode and the connection counts.
+func (s *Server) DrainStatus() *DrainStatus {
+	s.drainMtx.RLock()
+	status := &DrainStatus{
+		Draining:     s.draining,
+		RedirectAddr: s.drainRedirect,
+		Refused:      s.drainRefused,
+		Admitted:     s.drainAdmitted,
+	}
+	if s.draining {
+		status.Since = s.drainStart.UnixMilli()
+	}
+	s.drainMtx.RUnlock()
+	status.Connections = s.clientCount()
+	return status
+}
+
+// admitWhileDraining checks whether the account in the request's
+// msgjson.AccountIDHeader is admitted while draining.
+func (s *Server) admitWhileDraining(r *http.Request) bool {
+	if s.drainAdmit == nil {
+		return false
+	}
+	b, err := hex.DecodeString(r.Header.Get(msgjson.AccountIDHeader))
+	if err != nil || len(b) != account.HashSize {
+		return false
+	}
+	var acctID account.AccountID
+	copy(acctID[:], b)
+	return s.drainAdmit(acctID)
+}
+
+// refuseIfDraining responds to a websocket connection attempt with a
+// msgjson.DrainResponse if the Server is draining and the client is not
+// admitted. The return value indicates whether the connection was refused.
+func (s *Server) refuseIfDraining(w http.ResponseWriter, r *http.Request) bool {
+	s.drainMtx.Lock()
+	if !s.draining {
+		s.drainMtx.Unlock()
+		return false
+	}
+	if s.admitWhileDraining(r) {
+		s.drainAdmitted++
+		s.drainMtx.Unlock()
+		return false
+	}
+	s.drainRefused++
+	resp := &msgjson.DrainResponse{
+		Error:     "server is draining connections",
+		RetryAddr: s.drainRedirect,
+	}
+	s.drainMtx.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "0")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Debugf("Error writing drain response: %v", err)
+	}
+	return true
+}
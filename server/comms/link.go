@@ -147,6 +147,9 @@ func (s *Server) handleMessage(c *wsLink, msg *msgjson.Message) *msgjson.Error {
 		handler := s.rpcRoutes[msg.Route]
 		if handler != nil {
 			if !c.wsLimiter.allow(msg.Route) {
+				if s.rateLimited != nil {
+					s.rateLimited(c, msg.Route)
+				}
 				return msgjson.NewError(msgjson.TooManyRequestsError, "too many requests to %s", msg.Route)
 			}
 			// Handle the request.
@@ -175,6 +178,10 @@ func (s *Server) handleMessage(c *wsLink, msg *msgjson.Message) *msgjson.Error {
 			thing = new(msgjson.CandlesRequest)
 		case msgjson.OrderBookRoute:
 			thing = new(msgjson.OrderBookSubscription)
+		case msgjson.EpochProofRoute:
+			thing = new(msgjson.EpochProofRequest)
+		case msgjson.CandleHistoryRoute:
+			thing = new(msgjson.CandleHistoryRequest)
 		}
 		if thing != nil {
 			err := msg.Unmarshal(thing)
@@ -206,6 +213,9 @@ func (s *Server) handleMessage(c *wsLink, msg *msgjson.Message) *msgjson.Error {
 		handler := s.rpcRoutes[msg.Route]
 		if handler != nil {
 			if !c.wsLimiter.allow(msg.Route) {
+				if s.rateLimited != nil {
+					s.rateLimited(c, msg.Route)
+				}
 				return msgjson.NewError(msgjson.TooManyRequestsError, "too many requests to %s", msg.Route)
 			}
 			// Handle the request.
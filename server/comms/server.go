@@ -21,6 +21,7 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/ws"
+	"decred.org/dcrdex/server/account"
 	"github.com/decred/dcrd/certgen"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -137,6 +138,14 @@ func (s *Server) Route(route string, handler MsgHandler) {
 	s.rpcRoutes[route] = handler
 }
 
+// OnRateLimited registers a function that is called with the link and route
+// when a websocket request or notification is refused for exceeding the rate
+// limits. The function is called from the link's input loop, so it should not
+// block. OnRateLimited should be called before the Server is started.
+func (s *Server) OnRateLimited(f func(conn Link, route string)) {
+	s.rateLimited = f
+}
+
 func (s *Server) RegisterHTTP(route string, handler HTTPHandler) {
 	if route == "" {
 		panic("RegisterHTTP: route is empty string")
@@ -154,11 +163,16 @@ type RPCConfig struct {
 	// HiddenServiceAddr is the local address to which connections from the
 	// local hidden service will connect, e.g. 127.0.0.1:7252. This is not the
 	// .onion address of the hidden service. The TLS key pairs do not apply to
-	// these connections since TLS is not used on the hidden service's listener.
+	// these connections unless HiddenServiceTLS is set, since Tor already
+	// encrypts and authenticates connections to the hidden service.
 	// This corresponds to the last component of a HiddenServicePort line in a
 	// torrc config file. e.g. HiddenServicePort 7232 127.0.0.1:7252. Clients
 	// would specify the port preceding this address in the above statement.
 	HiddenServiceAddr string
+	// HiddenServiceTLS enables TLS on the hidden service's listener. Clients
+	// must then add the .onion host with the wss:// scheme, and they pin the
+	// server's certificate.
+	HiddenServiceTLS bool
 	// ListenAddrs are the addresses on which the server will listen.
 	ListenAddrs []string
 	// The location of the TLS keypair files. If they are not already at the
@@ -173,6 +187,23 @@ type RPCConfig struct {
 	AltDNSNames []string
 	// DisableDataAPI will disable all traffic to the HTTP data API routes.
 	DisableDataAPI bool
+	// ACMEHosts are host names for which a browser-trusted certificate is
+	// obtained from an ACME certificate authority (Let's Encrypt by default)
+	// and renewed automatically. The RPCCert key pair is still used for other
+	// server names, and if the ACME certificate cannot be obtained. The CA
+	// must be able to reach the server at these hosts on port 443 for the
+	// tls-alpn-01 challenge, or on port 80 at ACMEHTTPListen.
+	ACMEHosts []string
+	// ACMECacheDir is the directory for the ACME account key and certificates.
+	ACMECacheDir string
+	// ACMEEmail is an optional contact address for the ACME account.
+	ACMEEmail string
+	// ACMEDirectoryURL is the ACME directory. The default is the Let's Encrypt
+	// production directory.
+	ACMEDirectoryURL string
+	// ACMEHTTPListen is an optional address on which to answer http-01
+	// challenges, e.g. ":80".
+	ACMEHTTPListen string
 }
 
 // allower is satisfied by rate.Limiter.
@@ -218,6 +249,7 @@ func newRouteLimiter() *routeLimiter {
 			// Status checking of matches and orders
 			msgjson.MatchStatusRoute: statusLimiter,
 			msgjson.OrderStatusRoute: statusLimiter,
+			msgjson.ReputationRoute:  statusLimiter,
 			// Order submission
 			msgjson.LimitRoute:  orderLimiter,
 			msgjson.MarketRoute: orderLimiter,
@@ -230,6 +262,10 @@ func newRouteLimiter() *routeLimiter {
 			msgjson.ConfigRoute:  infoLimiter,
 			msgjson.SpotsRoute:   infoLimiter,
 			msgjson.CandlesRoute: infoLimiter,
+			// Stored candle history
+			msgjson.CandleHistoryRoute: infoLimiter,
+			// Match proofs of past epochs
+			msgjson.EpochProofRoute: infoLimiter,
 		},
 	}
 }
@@ -246,6 +282,13 @@ type ipWsLimiter struct {
 // and an HTTP API.
 type Server struct {
 	mux *chi.Mux
+	// keyPair is the reloadable TLS certificate used by the listeners. It is
+	// nil if TLS is disabled.
+	keyPair *TLSKeyPair
+	// acme and acmeListener are set if ACME certificates are used.
+	// acmeListener is nil if http-01 challenges are not answered.
+	acme         *acmeCertificates
+	acmeListener net.Listener
 	// One listener for each address specified at (RPCConfig).ListenAddrs.
 	listeners []net.Listener
 
@@ -272,6 +315,21 @@ type Server struct {
 	rpcRoutes map[string]MsgHandler
 	// httpRoutes maps HTTP routes to the handlers.
 	httpRoutes map[string]HTTPHandler
+
+	// rateLimited is called when a websocket message is refused for exceeding
+	// the rate limits. See OnRateLimited.
+	rateLimited func(Link, string)
+
+	// While draining, new websocket connections are refused. See Drain.
+	drainMtx      sync.RWMutex
+	draining      bool
+	drainRedirect string
+	drainStart    time.Time
+	drainRefused  uint64
+	drainAdmitted uint64
+	// drainAdmit decides whether to accept a connection from an account while
+	// draining. See AdmitWhileDraining.
+	drainAdmit func(account.AccountID) bool
 }
 
 // NewServer constructs a Server that should be started with Run. The server is
@@ -281,6 +339,7 @@ type Server struct {
 // clients, if necessary.
 func NewServer(cfg *RPCConfig) (*Server, error) {
 
+	var keyPair *TLSKeyPair
 	var tlsConfig *tls.Config
 	if !cfg.NoTLS {
 		// Prepare the TLS configuration.
@@ -295,17 +354,48 @@ func NewServer(cfg *RPCConfig) (*Server, error) {
 				return nil, err
 			}
 		}
-		keypair, err := tls.LoadX509KeyPair(cfg.RPCCert, cfg.RPCKey)
+		var err error
+		keyPair, err = NewTLSKeyPair(cfg.RPCCert, cfg.RPCKey)
 		if err != nil {
 			return nil, err
 		}
-		tlsConfig = &tls.Config{
-			Certificates: []tls.Certificate{keypair}, // TODO: multiple key pairs for virtual hosting
-			MinVersion:   tls.VersionTLS12,
+		tlsConfig = keyPair.TLSConfig() // TODO: multiple key pairs for virtual hosting
+	}
+
+	var acmeCerts *acmeCertificates
+	var acmeListener net.Listener
+	if len(cfg.ACMEHosts) > 0 {
+		if cfg.NoTLS {
+			return nil, fmt.Errorf("ACME certificates requested with TLS disabled")
+		}
+		var err error
+		acmeCerts, err = newACMECertificates(cfg, keyPair)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = acmeCerts.TLSConfig()
+		if cfg.ACMEHTTPListen != "" {
+			acmeListener, err = net.Listen("tcp", cfg.ACMEHTTPListen)
+			if err != nil {
+				return nil, fmt.Errorf("cannot listen on %s for ACME challenges: %w", cfg.ACMEHTTPListen, err)
+			}
 		}
 	}
 
 	// Start with the hidden service listener, if specified.
+	if cfg.HiddenServiceTLS && (cfg.HiddenServiceAddr == "" || cfg.NoTLS) {
+		return nil, fmt.Errorf("hidden service TLS requires a hidden service address and TLS enabled")
+	}
+	onionListen := func(network, addr string) (net.Listener, error) {
+		listener, err := net.Listen(network, addr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot listen on %s: %w", addr, err)
+		}
+		if cfg.HiddenServiceTLS {
+			listener = tls.NewListener(listener, tlsConfig)
+		}
+		return onionListener{listener}, nil
+	}
 	var listeners []net.Listener
 	if cfg.HiddenServiceAddr == "" {
 		listeners = make([]net.Listener, 0, len(cfg.ListenAddrs))
@@ -316,18 +406,18 @@ func NewServer(cfg *RPCConfig) (*Server, error) {
 			return nil, err
 		}
 		for _, addr := range ipv4ListenAddrs {
-			listener, err := net.Listen("tcp4", addr)
+			listener, err := onionListen("tcp4", addr)
 			if err != nil {
-				return nil, fmt.Errorf("cannot listen on %s: %w", addr, err)
+				return nil, err
 			}
-			listeners = append(listeners, onionListener{listener})
+			listeners = append(listeners, listener)
 		}
 		for _, addr := range ipv6ListenAddrs {
-			listener, err := net.Listen("tcp6", addr)
+			listener, err := onionListen("tcp6", addr)
 			if err != nil {
-				return nil, fmt.Errorf("cannot listen on %s: %w", addr, err)
+				return nil, err
 			}
-			listeners = append(listeners, onionListener{listener})
+			listeners = append(listeners, listener)
 		}
 	}
 
@@ -374,20 +464,37 @@ func NewServer(cfg *RPCConfig) (*Server, error) {
 	mux.Use(middleware.Recoverer)
 
 	return &Server{
-		mux:         mux,
-		listeners:   listeners,
-		clients:     make(map[uint64]*wsLink),
-		wsLimiters:  make(map[dex.IPKey]*ipWsLimiter),
-		v6Prefixes:  make(map[dex.IPKey]int),
-		quarantine:  make(map[dex.IPKey]time.Time),
-		dataEnabled: dataEnabled,
-		rpcRoutes:   make(map[string]MsgHandler),
-		httpRoutes:  make(map[string]HTTPHandler),
+		mux:          mux,
+		keyPair:      keyPair,
+		acme:         acmeCerts,
+		acmeListener: acmeListener,
+		listeners:    listeners,
+		clients:      make(map[uint64]*wsLink),
+		wsLimiters:   make(map[dex.IPKey]*ipWsLimiter),
+		v6Prefixes:   make(map[dex.IPKey]int),
+		quarantine:   make(map[dex.IPKey]time.Time),
+		dataEnabled:  dataEnabled,
+		rpcRoutes:    make(map[string]MsgHandler),
+		httpRoutes:   make(map[string]HTTPHandler),
 	}, nil
 }
 
 type onionListener struct{ net.Listener }
 
+// ReloadTLS reloads the TLS certificate and key from disk. Existing client
+// connections are not dropped, and new connections will use the new
+// certificate. ReloadTLS does nothing if TLS is disabled.
+func (s *Server) ReloadTLS() error {
+	if s.keyPair == nil {
+		return nil
+	}
+	if err := s.keyPair.Reload(); err != nil {
+		return err
+	}
+	log.Infof("Reloaded TLS certificate %s", s.keyPair.certFile)
+	return nil
+}
+
 // Run starts the server. Run should be called only after all routes are
 // registered.
 func (s *Server) Run(ctx context.Context) {
@@ -401,6 +508,9 @@ func (s *Server) Run(ctx context.Context) {
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
+		if s.refuseIfDraining(w, r) {
+			return
+		}
 		if s.clientCount() >= rpcMaxClients {
 			http.Error(w, "server at maximum capacity", http.StatusServiceUnavailable)
 			return
@@ -466,6 +576,21 @@ func (s *Server) Run(ctx context.Context) {
 		}(listener)
 	}
 
+	// Answer ACME http-01 challenges.
+	var challengeServer *http.Server
+	if s.acmeListener != nil {
+		challengeServer = s.acme.challengeServer()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Infof("Answering ACME challenges on %s", s.acmeListener.Addr())
+			err := challengeServer.Serve(s.acmeListener)
+			if !errors.Is(err, http.ErrServerClosed) {
+				log.Warnf("unexpected (http.Server).Serve error for ACME challenges: %v", err)
+			}
+		}()
+	}
+
 	// Run a periodic routine to keep the ipHTTPRateLimiter map clean.
 	go func() {
 		ticker := time.NewTicker(time.Minute * 5)
@@ -496,6 +621,11 @@ func (s *Server) Run(ctx context.Context) {
 	if err != nil {
 		log.Warnf("http.Server.Shutdown: %v", err)
 	}
+	if challengeServer != nil {
+		if err := challengeServer.Shutdown(ctxTimeout); err != nil {
+			log.Warnf("http.Server.Shutdown for ACME challenges: %v", err)
+		}
+	}
 
 	// Stop and disconnect websocket clients.
 	s.disconnectClients()
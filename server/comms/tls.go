@@ -0,0 +1,71 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package comms
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// TLSKeyPair is a TLS certificate and key pair loaded from disk that may be
+// reloaded while a server is running. New TLS handshakes use the most recently
+// loaded certificate, while established connections are unaffected.
+type TLSKeyPair struct {
+	certFile, keyFile string
+	cert              atomic.Pointer[tls.Certificate]
+}
+
+// NewTLSKeyPair loads the key pair from the certificate and key files.
+func NewTLSKeyPair(certFile, keyFile string) (*TLSKeyPair, error) {
+	kp := &TLSKeyPair{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if err := kp.Reload(); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+// Reload reads the certificate and key files again. The new pair is only put
+// into use if it loads and the leaf certificate is currently valid, so a
+// partially written or expired certificate does not replace a working one.
+func (kp *TLSKeyPair) Reload() error {
+	cert, err := tls.LoadX509KeyPair(kp.certFile, kp.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading TLS key pair: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("error parsing TLS certificate: %w", err)
+	}
+	if now := time.Now(); now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return fmt.Errorf("TLS certificate is not valid now (valid %v to %v)",
+			leaf.NotBefore, leaf.NotAfter)
+	}
+	cert.Leaf = leaf
+	kp.cert.Store(&cert)
+	return nil
+}
+
+// Certificate returns the currently loaded certificate.
+func (kp *TLSKeyPair) Certificate() *tls.Certificate {
+	return kp.cert.Load()
+}
+
+// GetCertificate satisfies the tls.Config.GetCertificate field's signature.
+func (kp *TLSKeyPair) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return kp.cert.Load(), nil
+}
+
+// TLSConfig creates a server *tls.Config that uses the reloadable key pair.
+func (kp *TLSKeyPair) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: kp.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"decred.org/dcrdex/dex"
 )
@@ -37,6 +38,25 @@ func Register(name string, driver Driver) {
 	drivers[name] = driver
 }
 
+// Registered checks whether a DB driver with the name is registered.
+func Registered(name string) bool {
+	driversMtx.Lock()
+	defer driversMtx.Unlock()
+	_, found := drivers[name]
+	return found
+}
+
+// FileConfig is the configuration for a DB driver that keeps the archive in a
+// single file, such as the sqlite driver. It allows such a driver to be opened
+// by name without importing its package.
+type FileConfig struct {
+	// Path is the path of the database file.
+	Path         string
+	QueryTimeout time.Duration
+	// MarketCfg specifies all of the markets that the archive should prepare.
+	MarketCfg []*dex.MarketInfo
+}
+
 // Open loads the named DB driver with the provided configuration.
 func Open(ctx context.Context, name string, cfg any) (DEXArchivist, error) {
 	driversMtx.Lock()
@@ -203,7 +203,7 @@ func getAccount(dbe sqlQueryer, tableName string, aid account.AccountID) (acct *
 // createAccountForBond creates an entry for the account in the accounts table.
 func createAccountForBond(dbe sqlExecutor, tableName string, acct *account.Account) error {
 	stmt := fmt.Sprintf(internal.CreateAccountForBond, tableName)
-	_, err := dbe.Exec(stmt, acct.ID, acct.PubKey.SerializeCompressed())
+	_, err := dbe.Exec(stmt, acct.ID, acct.PubKey.SerializeCompressed(), time.Now().UnixMilli())
 	return err
 }
 
@@ -0,0 +1,41 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/pg/internal"
+)
+
+// BanScore retrieves the account's ban score, or nil if the account has none.
+func (a *Archiver) BanScore(aid account.AccountID) (*db.BanScore, error) {
+	stmt := fmt.Sprintf(internal.SelectBanScore, banScoresTableName)
+	var bs db.BanScore
+	var suspendedUntil sql.NullTime
+	err := a.db.QueryRowContext(a.ctx, stmt, aid).Scan(&bs.Score, &bs.Stamp, &suspendedUntil, &bs.Banned)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	bs.Stamp = bs.Stamp.UTC()
+	if suspendedUntil.Valid {
+		bs.SuspendedUntil = suspendedUntil.Time.UTC()
+	}
+	return &bs, nil
+}
+
+// SetBanScore stores the account's ban score, replacing any stored score.
+func (a *Archiver) SetBanScore(aid account.AccountID, bs *db.BanScore) error {
+	stmt := fmt.Sprintf(internal.UpsertBanScore, banScoresTableName)
+	suspendedUntil := sql.NullTime{Time: bs.SuspendedUntil, Valid: !bs.SuspendedUntil.IsZero()}
+	_, err := a.db.ExecContext(a.ctx, stmt, aid, bs.Score, bs.Stamp, suspendedUntil, bs.Banned)
+	return err
+}
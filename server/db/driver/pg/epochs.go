@@ -56,6 +56,72 @@ func (oids *orderIDs) Scan(src any) error {
 	return nil
 }
 
+// preimages is a []order.Preimage stored as a BYTEA[].
+type preimages []order.Preimage
+
+// Value implements the sql/driver.Valuer interface.
+func (pis preimages) Value() (driver.Value, error) {
+	if pis == nil {
+		return nil, nil
+	}
+	ba := make(pq.ByteaArray, 0, len(pis))
+	for i := range pis {
+		ba = append(ba, pis[i][:])
+	}
+	return ba.Value()
+}
+
+// Scan implements the sql.Scanner interface. A NULL array scans as a nil
+// slice.
+func (pis *preimages) Scan(src any) error {
+	var ba pq.ByteaArray
+	if err := ba.Scan(src); err != nil {
+		return err
+	}
+	if ba == nil {
+		*pis = nil
+		return nil
+	}
+	*pis = make([]order.Preimage, len(ba))
+	for i := range ba {
+		copy((*pis)[i][:], ba[i])
+	}
+	return nil
+}
+
+// commitments is a []order.Commitment stored as a BYTEA[].
+type commitments []order.Commitment
+
+// Value implements the sql/driver.Valuer interface.
+func (cs commitments) Value() (driver.Value, error) {
+	if cs == nil {
+		return nil, nil
+	}
+	ba := make(pq.ByteaArray, 0, len(cs))
+	for i := range cs {
+		ba = append(ba, cs[i][:])
+	}
+	return ba.Value()
+}
+
+// Scan implements the sql.Scanner interface. A NULL array scans as a nil
+// slice.
+func (cs *commitments) Scan(src any) error {
+	var ba pq.ByteaArray
+	if err := ba.Scan(src); err != nil {
+		return err
+	}
+	if ba == nil {
+		*cs = nil
+		return nil
+	}
+	*cs = make([]order.Commitment, len(ba))
+	for i := range ba {
+		copy((*cs)[i][:], ba[i])
+	}
+	return nil
+}
+
 // InsertEpoch stores the results of a newly-processed epoch. TODO: test.
 func (a *Archiver) InsertEpoch(ed *db.EpochResults) error {
 	marketSchema, err := a.marketSchema(ed.MktBase, ed.MktQuote)
@@ -67,7 +133,8 @@ func (a *Archiver) InsertEpoch(ed *db.EpochResults) error {
 	stmt := fmt.Sprintf(internal.InsertEpoch, epochsTableName)
 
 	_, err = a.db.Exec(stmt, ed.Idx, ed.Dur, ed.MatchTime, ed.CSum, ed.Seed,
-		orderIDs(ed.OrdersRevealed), orderIDs(ed.OrdersMissed))
+		orderIDs(ed.OrdersRevealed), orderIDs(ed.OrdersMissed),
+		preimages(ed.Preimages), commitments(ed.MissedCommits))
 	if err != nil {
 		a.fatalBackendErr(err)
 		return err
@@ -85,6 +152,37 @@ func (a *Archiver) InsertEpoch(ed *db.EpochResults) error {
 	return err
 }
 
+// EpochProof retrieves the match proof for a processed epoch.
+func (a *Archiver) EpochProof(base, quote uint32, epochIdx, epochDur int64) (*db.EpochProof, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	stmt := fmt.Sprintf(internal.SelectEpochProof, fullEpochsTableName(a.dbName, marketSchema))
+	proof := &db.EpochProof{
+		Idx: epochIdx,
+		Dur: epochDur,
+	}
+	var revealed, missed orderIDs
+	var pis preimages
+	var missedCommits commitments
+	err = a.db.QueryRowContext(ctx, stmt, epochIdx, epochDur).Scan(&proof.MatchTime,
+		&proof.CSum, &proof.Seed, &revealed, &pis, &missed, &missedCommits)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, db.ArchiveError{Code: db.ErrUnknownEpoch}
+		}
+		return nil, err
+	}
+	proof.OrdersRevealed, proof.Preimages = revealed, pis
+	proof.OrdersMissed, proof.MissedCommits = missed, missedCommits
+	return proof, nil
+}
+
 // LastEpochRate gets the EndRate of the last EpochResults inserted for the
 // market. If the database is empty, no error and a rate of zero are returned.
 func (a *Archiver) LastEpochRate(base, quote uint32) (rate uint64, err error) {
@@ -232,6 +330,52 @@ func (a *Archiver) InsertCandles(base, quote uint32, candleDur uint64, cs []*can
 	return nil
 }
 
+// CandleHistory retrieves up to limit candles of the specified duration that
+// ended after the after stamp and no later than the through stamp, oldest
+// first.
+func (a *Archiver) CandleHistory(base, quote uint32, candleDur, after, through uint64, limit int) ([]*candles.Candle, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	if through > math.MaxInt64 {
+		through = math.MaxInt64
+	}
+
+	tableName := fullCandlesTableName(a.dbName, marketSchema, candleDur)
+	stmt := fmt.Sprintf(internal.SelectCandleRange, tableName)
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	rows, err := a.db.QueryContext(ctx, stmt, int64(after), int64(through), limit)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	cs := make([]*candles.Candle, 0, limit)
+	var endStamp, matchVol, quoteVol, highRate, lowRate, startRate, endRate fastUint64
+	for rows.Next() {
+		err = rows.Scan(&endStamp, &matchVol, &quoteVol, &highRate, &lowRate, &startRate, &endRate)
+		if err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		cs = append(cs, &candles.Candle{
+			StartStamp:  uint64(endStamp) - candleDur,
+			EndStamp:    uint64(endStamp),
+			MatchVolume: uint64(matchVol),
+			QuoteVolume: uint64(quoteVol),
+			HighRate:    uint64(highRate),
+			LowRate:     uint64(lowRate),
+			StartRate:   uint64(startRate),
+			EndRate:     uint64(endRate),
+		})
+	}
+
+	return cs, rows.Err()
+}
+
 // loadCandles loads the last n candles of a specified duration and market into
 // the provided cache.
 func (a *Archiver) loadCandles(base, quote uint32, cache *candles.Cache, n uint64) error {
@@ -0,0 +1,138 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/pg/internal"
+)
+
+// historyAccount is the account query argument for a HistoryFilter, which is
+// NULL to select all accounts.
+func historyAccount(filter *db.HistoryFilter) any {
+	if filter.Account == nil {
+		return nil
+	}
+	return filter.Account[:]
+}
+
+// MatchHistory streams the trade matches selected by the filter into the
+// provided function, oldest first.
+func (a *Archiver) MatchHistory(filter *db.HistoryFilter, f func(*db.MatchDataWithCoins) error) (int, error) {
+	marketSchema, err := a.marketSchema(filter.Base, filter.Quote)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	stmt := fmt.Sprintf(internal.SelectMatchHistory, fullMatchesTableName(a.dbName, marketSchema))
+	rows, err := a.db.QueryContext(ctx, stmt, filter.Start.UnixMilli(), filter.End.UnixMilli(), historyAccount(filter))
+	if err != nil {
+		return 0, err
+	}
+	return rowsToMatchDataWithCoinsStreaming(rows, true, f)
+}
+
+// OrderHistory streams the orders selected by the filter, active or archived,
+// into the provided function. The trade orders are streamed first, oldest
+// first, followed by the cancel orders.
+func (a *Archiver) OrderHistory(filter *db.HistoryFilter, f func(order.Order, order.OrderStatus) error) (int, error) {
+	marketSchema, err := a.marketSchema(filter.Base, filter.Quote)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	acct := historyAccount(filter)
+	stmt := fmt.Sprintf(internal.SelectOrderHistory, fullOrderTableName(a.dbName, marketSchema, true),
+		fullOrderTableName(a.dbName, marketSchema, false))
+	rows, err := a.db.QueryContext(ctx, stmt, filter.Start, filter.End, acct)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var N int
+	for rows.Next() {
+		var prefix order.Prefix
+		var trade order.Trade
+		var id order.OrderID
+		var tif order.TimeInForce
+		var rate uint64
+		var status pgOrderStatus
+		err = rows.Scan(&id, &prefix.OrderType, &trade.Sell,
+			&prefix.AccountID, &trade.Address, &prefix.ClientTime, &prefix.ServerTime,
+			&prefix.Commit, (*dbCoins)(&trade.Coins),
+			&trade.Quantity, &rate, &tif, &status, &trade.FillAmt)
+		if err != nil {
+			return N, err
+		}
+		prefix.BaseAsset, prefix.QuoteAsset = filter.Base, filter.Quote
+
+		var ord order.Order
+		switch prefix.OrderType {
+		case order.LimitOrderType:
+			ord = &order.LimitOrder{
+				P:     prefix,
+				T:     *trade.Copy(),
+				Rate:  rate,
+				Force: tif,
+			}
+		case order.MarketOrderType:
+			ord = &order.MarketOrder{
+				P:         prefix,
+				T:         *trade.Copy(),
+				WorstRate: rate,
+			}
+		default:
+			log.Errorf("OrderHistory: encountered unexpected order type %v", prefix.OrderType)
+			continue
+		}
+		if err = f(ord, pgToMarketStatus(status)); err != nil {
+			return N, err
+		}
+		N++
+	}
+	if err = rows.Err(); err != nil {
+		return N, err
+	}
+
+	stmt = fmt.Sprintf(internal.SelectCancelOrderHistory, fullCancelOrderTableName(a.dbName, marketSchema, true),
+		fullCancelOrderTableName(a.dbName, marketSchema, false))
+	cancelRows, err := a.db.QueryContext(ctx, stmt, filter.Start, filter.End, acct)
+	if err != nil {
+		return N, err
+	}
+	defer cancelRows.Close()
+
+	for cancelRows.Next() {
+		co := &order.CancelOrder{
+			P: order.Prefix{
+				OrderType:  order.CancelOrderType,
+				BaseAsset:  filter.Base,
+				QuoteAsset: filter.Quote,
+			},
+		}
+		var id order.OrderID
+		var status pgOrderStatus
+		err = cancelRows.Scan(&id, &co.AccountID, &co.ClientTime,
+			&co.ServerTime, &co.Commit, &co.TargetOrderID, &status)
+		if err != nil {
+			return N, err
+		}
+		if err = f(co, pgToMarketStatus(status)); err != nil {
+			return N, err
+		}
+		N++
+	}
+	return N, cancelRows.Err()
+}
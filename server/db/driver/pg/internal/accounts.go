@@ -11,7 +11,8 @@ const (
 	// CreateAccountsTable creates the account table.
 	CreateAccountsTable = `CREATE TABLE IF NOT EXISTS %s (
 		account_id BYTEA PRIMARY KEY,  -- UNIQUE INDEX
-		pubkey BYTEA
+		pubkey BYTEA,
+		created INT8                   -- unix ms, NULL for accounts created before v7
 		);`
 
 	CreateBondsTableV0 = `CREATE TABLE IF NOT EXISTS %s (
@@ -75,7 +76,10 @@ const (
 	SelectAccountInfo = `SELECT account_id, pubkey FROM %s
 		WHERE account_id = $1;`
 
-	CreateAccountForBond = `INSERT INTO %s (account_id, pubkey) VALUES ($1, $2);`
+	CreateAccountForBond = `INSERT INTO %s (account_id, pubkey, created) VALUES ($1, $2, $3);`
+
+	// SelectNewAccountCount counts the accounts created in a time range.
+	SelectNewAccountCount = `SELECT COUNT(*) FROM %s WHERE created >= $1 AND created < $2;`
 
 	CreatePrepaidBondsTable = `CREATE TABLE IF NOT EXISTS %s (
 		coin_id BYTEA PRIMARY KEY,
@@ -9,14 +9,22 @@ const (
 		match_time INT8,      -- time at which matching and book/unbooks began
 		csum BYTEA,           -- commitment checksum
 		seed BYTEA,           -- preimage-derived shuffle seed
-		revealed BYTEA[],     -- order IDs with revealed preimages
+		revealed BYTEA[],     -- order IDs with revealed preimages, in shuffled order
 		missed BYTEA[],       -- IDs of orders with no preimage
+		preimages BYTEA[],    -- preimages of the revealed orders
+		missed_commits BYTEA[], -- commitments of the missed orders
 		PRIMARY KEY(epoch_idx, epoch_dur)  -- epoch idx:dur is unique and the primary key
 	);`
 
 	// InsertEpoch inserts the epoch's match proof data into the epoch table.
-	InsertEpoch = `INSERT INTO %s (epoch_idx, epoch_dur, match_time, csum, seed, revealed, missed)
-		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+	InsertEpoch = `INSERT INTO %s (epoch_idx, epoch_dur, match_time, csum, seed, revealed, missed,
+			preimages, missed_commits)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+
+	// SelectEpochProof retrieves the match proof data for an epoch.
+	SelectEpochProof = `SELECT match_time, csum, seed, revealed, preimages, missed, missed_commits
+		FROM %s
+		WHERE epoch_idx = $1 AND epoch_dur = $2;`
 
 	SelectLastEpochRate = `SELECT end_rate
 		FROM %s
@@ -87,6 +95,15 @@ const (
 	ORDER BY end_stamp
 	LIMIT $1;`
 
+	// SelectCandleRange selects up to a limited number of candles that ended
+	// in a time range, oldest first.
+	SelectCandleRange = `SELECT end_stamp, match_volume, quote_volume,
+		high_rate, low_rate, start_rate, end_rate
+	FROM %s
+	WHERE end_stamp > $1 AND end_stamp <= $2
+	ORDER BY end_stamp
+	LIMIT $3;`
+
 	SelectLastEndStamp = `SELECT (end_stamp)
 		FROM %s
 		ORDER BY end_stamp
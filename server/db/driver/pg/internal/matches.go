@@ -31,6 +31,7 @@ const (
 		baseRate INT8, quoteRate INT8, -- contract tx fee rates, NULL for cancel orders
 		status INT2,           -- also updated during swap negotiation, independent from active for failed swaps
 		forgiven BOOL,
+		aSwapConf INT8, bSwapConf INT8, -- confirmations required for the contracts, NULL for cancel orders
 
 		-- The remaining columns are only set during swap negotiation.
 		sigMatchAckMaker BYTEA,   -- maker's ack of the match
@@ -66,7 +67,8 @@ const (
 		aContractCoinID, aContract, aContractTime, bSigAckOfAContract,
 		bContractCoinID, bContract, bContractTime, aSigAckOfBContract,
 		aRedeemCoinID, aRedeemSecret, aRedeemTime, bSigAckOfARedeem,
-		bRedeemCoinID, bRedeemTime
+		bRedeemCoinID, bRedeemTime,
+		aSwapConf, bSwapConf
 	FROM %s WHERE matchid = $1;`
 
 	InsertMatch = `INSERT INTO %s (matchid, takerSell,
@@ -117,6 +119,18 @@ const (
 	WHERE (takerAccount = $1 OR makerAccount = $1)
 		AND active;`
 
+	// SelectMatchStatsInRange summarizes the trade matches made in a time
+	// range. Failed swaps are inactive matches that did not reach
+	// MatchComplete. Note that the literal status value used in this query
+	// MUST BE UPDATED if the order.MatchStatus enum is changed.
+	SelectMatchStatsInRange = `SELECT COUNT(*),
+		COALESCE(SUM(quantity), 0),
+		COALESCE(SUM(FLOOR(quantity::NUMERIC * rate / 100000000)), 0)::INT8,
+		COUNT(*) FILTER (WHERE NOT active AND status < 4)
+	FROM %s
+	WHERE takerSell IS NOT NULL -- not a cancel order
+		AND epochIdx * epochDur >= $1 AND epochIdx * epochDur < $2;`
+
 	RetrieveMarketMatches = `SELECT matchid, active, takerSell,
 		takerOrder, takerAccount, takerAddress,
 		makerOrder, makerAccount, makerAddress,
@@ -147,7 +161,8 @@ const (
 		aContractCoinID, aContract, aContractTime, bSigAckOfAContract,
 		bContractCoinID, bContract, bContractTime, aSigAckOfBContract,
 		aRedeemCoinID, aRedeemSecret, aRedeemTime, bSigAckOfARedeem,
-		bRedeemCoinID, bRedeemTime
+		bRedeemCoinID, bRedeemTime,
+		aSwapConf, bSwapConf
 	FROM %s
 	WHERE takerSell IS NOT NULL -- not a cancel order
 		AND active
@@ -208,6 +223,8 @@ const (
 	ForgiveMatchFail = `UPDATE %s SET forgiven = TRUE
 		WHERE matchid = $1 AND NOT active;`
 
+	SetSwapConfs = `UPDATE %s SET aSwapConf = $2, bSwapConf = $3 WHERE matchid = $1;`
+
 	SetMakerMatchAckSig = `UPDATE %s SET sigMatchAckMaker = $2 WHERE matchid = $1;`
 	SetTakerMatchAckSig = `UPDATE %s SET sigMatchAckTaker = $2 WHERE matchid = $1;`
 
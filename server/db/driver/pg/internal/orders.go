@@ -17,7 +17,7 @@ const (
 		commit BYTEA UNIQUE,
 		coins BYTEA,
 		quantity INT8,
-		rate INT8,
+		rate INT8,             -- worst rate for market orders, 0 if none
 		force INT2,
 		status INT2,
 		filled INT8,
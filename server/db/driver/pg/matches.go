@@ -88,6 +88,7 @@ func activeSwaps(ctx context.Context, dbe *sql.DB, tableName string) (matches []
 		var takerSell sql.NullBool
 		var takerAddr, makerAddr sql.NullString
 		var contractATime, contractBTime, redeemATime, redeemBTime sql.NullInt64
+		var swapConfA, swapConfB sql.NullInt64
 
 		err = rows.Scan(&m.ID, &takerSell,
 			&m.Taker, &m.TakerAcct, &takerAddr,
@@ -101,7 +102,8 @@ func activeSwaps(ctx context.Context, dbe *sql.DB, tableName string) (matches []
 			&sd.ContractBAckSig,
 			&sd.RedeemACoinID, &sd.RedeemASecret, &redeemATime,
 			&sd.RedeemAAckSig,
-			&sd.RedeemBCoinID, &redeemBTime)
+			&sd.RedeemBCoinID, &redeemBTime,
+			&swapConfA, &swapConfB)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -120,6 +122,8 @@ func activeSwaps(ctx context.Context, dbe *sql.DB, tableName string) (matches []
 		sd.ContractBTime = contractBTime.Int64
 		sd.RedeemATime = redeemATime.Int64
 		sd.RedeemBTime = redeemBTime.Int64
+		sd.SwapConfA = uint32(swapConfA.Int64)
+		sd.SwapConfB = uint32(swapConfB.Int64)
 
 		matches = append(matches, &m)
 		swapData = append(swapData, &sd)
@@ -644,6 +648,7 @@ func (a *Archiver) SwapData(mid db.MarketMatchID) (order.MatchStatus, *db.SwapDa
 	var sd db.SwapData
 	var status uint8
 	var contractATime, contractBTime, redeemATime, redeemBTime sql.NullInt64
+	var swapConfA, swapConfB sql.NullInt64
 	err = a.db.QueryRow(stmt, mid).
 		Scan(&status,
 			&sd.SigMatchAckMaker, &sd.SigMatchAckTaker,
@@ -653,7 +658,8 @@ func (a *Archiver) SwapData(mid db.MarketMatchID) (order.MatchStatus, *db.SwapDa
 			&sd.ContractBAckSig,
 			&sd.RedeemACoinID, &sd.RedeemASecret, &redeemATime,
 			&sd.RedeemAAckSig,
-			&sd.RedeemBCoinID, &redeemBTime)
+			&sd.RedeemBCoinID, &redeemBTime,
+			&swapConfA, &swapConfB)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -662,6 +668,8 @@ func (a *Archiver) SwapData(mid db.MarketMatchID) (order.MatchStatus, *db.SwapDa
 	sd.ContractBTime = contractBTime.Int64
 	sd.RedeemATime = redeemATime.Int64
 	sd.RedeemBTime = redeemBTime.Int64
+	sd.SwapConfA = uint32(swapConfA.Int64)
+	sd.SwapConfB = uint32(swapConfB.Int64)
 
 	return order.MatchStatus(status), &sd, nil
 }
@@ -688,6 +696,13 @@ func (a *Archiver) updateMatchStmt(mid db.MarketMatchID, stmt string, args ...an
 	return nil
 }
 
+// SaveSwapConfs records the number of confirmations required for the swap
+// contracts of party A (the initiator) and party B (the participant).
+func (a *Archiver) SaveSwapConfs(mid db.MarketMatchID, swapConfA, swapConfB uint32) error {
+	return a.updateMatchStmt(mid, internal.SetSwapConfs,
+		mid.MatchID, int64(swapConfA), int64(swapConfB))
+}
+
 // Match acknowledgement message signatures.
 
 // SaveMatchAckSigA records the match data acknowledgement signature from swap
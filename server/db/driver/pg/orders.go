@@ -1418,8 +1418,9 @@ func loadTradeFromTable(dbe *sql.DB, fullTable string, oid order.OrderID) (order
 		}, status, nil
 	case order.MarketOrderType:
 		return &order.MarketOrder{
-			T: *trade.Copy(),
-			P: prefix,
+			T:         *trade.Copy(),
+			P:         prefix,
+			WorstRate: rate,
 		}, status, nil
 
 	}
@@ -1520,8 +1521,9 @@ func ordersByStatusFromTable(ctx context.Context, dbe *sql.DB, fullTable string,
 			}
 		case order.MarketOrderType:
 			ord = &order.MarketOrder{
-				P: prefix,
-				T: *trade.Copy(),
+				P:         prefix,
+				T:         *trade.Copy(),
+				WorstRate: rate,
 			}
 		default:
 			log.Errorf("ordersByStatusFromTable: encountered unexpected order type %v",
@@ -1578,8 +1580,9 @@ func userOrdersFromTable(ctx context.Context, dbe *sql.DB, fullTable string, bas
 			}
 		case order.MarketOrderType:
 			ord = &order.MarketOrder{
-				P: prefix,
-				T: *trade.Copy(),
+				P:         prefix,
+				T:         *trade.Copy(),
+				WorstRate: rate,
 			}
 		default:
 			log.Errorf("userOrdersFromTable: encountered unexpected order type %v",
@@ -1653,7 +1656,7 @@ func storeMarketOrder(dbe sqlExecutor, tableName string, mo *order.MarketOrder,
 	stmt := fmt.Sprintf(internal.InsertOrder, tableName)
 	return sqlExec(dbe, stmt, mo.ID(), mo.Type(), mo.Sell, mo.AccountID,
 		mo.Address, mo.ClientTime, mo.ServerTime, mo.Commit, dbCoins(mo.Coins),
-		mo.Quantity, 0, order.ImmediateTiF, status, mo.Filled(), epochIdx, epochDur)
+		mo.Quantity, mo.WorstRate, order.ImmediateTiF, status, mo.Filled(), epochIdx, epochDur)
 }
 
 func updateOrderStatus(dbe sqlExecutor, tableName string, oid order.OrderID, status pgOrderStatus) error {
@@ -92,6 +92,13 @@ func (a *Archiver) Fatal() <-chan struct{} {
 	return a.fatal
 }
 
+// Ping checks that the database is reachable.
+func (a *Archiver) Ping() error {
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+	return a.db.PingContext(ctx)
+}
+
 func (a *Archiver) fatalBackendErr(err error) {
 	if err == nil {
 		return
@@ -0,0 +1,68 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/pg/internal"
+)
+
+// PruneMarket deletes the market's inactive matches, archived cancel orders,
+// and epoch match proofs from before the given time, and the epoch reports
+// that ended before reportsBefore. Active matches, and the epochs in which they
+// were made, are never deleted, nor are the keep most recent matches and cancel
+// orders of each account, which are used to score the accounts. All deletions
+// are made in one transaction.
+func (a *Archiver) PruneMarket(base, quote uint32, before, reportsBefore time.Time, keep int) (*db.PruneResult, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	matchesTable := fullMatchesTableName(a.dbName, marketSchema)
+	cancelsTable := fullCancelOrderTableName(a.dbName, marketSchema, false)
+	beforeMs := before.UnixMilli()
+
+	dbTx, err := a.db.BeginTx(a.ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil || errors.Is(err, sql.ErrTxDone) {
+			return
+		}
+		if errR := dbTx.Rollback(); errR != nil {
+			log.Errorf("Rollback failed: %v", errR)
+		}
+	}()
+
+	res := new(db.PruneResult)
+	stmt := fmt.Sprintf(internal.DeleteCancelOrdersBefore, cancelsTable)
+	if res.CancelOrders, err = sqlExec(dbTx, stmt, before, keep); err != nil {
+		return nil, fmt.Errorf("error deleting cancel orders: %w", err)
+	}
+	// Delete epochs after cancel orders and before matches so that the epochs
+	// of the remaining cancel orders and of the active matches are retained.
+	stmt = fmt.Sprintf(internal.DeleteEpochsBefore, fullEpochsTableName(a.dbName, marketSchema), matchesTable, cancelsTable)
+	if res.Epochs, err = sqlExec(dbTx, stmt, beforeMs); err != nil {
+		return nil, fmt.Errorf("error deleting epochs: %w", err)
+	}
+	stmt = fmt.Sprintf(internal.DeleteInactiveMatchesBefore, matchesTable)
+	if res.Matches, err = sqlExec(dbTx, stmt, beforeMs, keep); err != nil {
+		return nil, fmt.Errorf("error deleting matches: %w", err)
+	}
+	stmt = fmt.Sprintf(internal.DeleteEpochReportsBefore, fullEpochReportsTableName(a.dbName, marketSchema))
+	if res.EpochReports, err = sqlExec(dbTx, stmt, reportsBefore.UnixMilli()); err != nil {
+		return nil, fmt.Errorf("error deleting epoch reports: %w", err)
+	}
+
+	if err = dbTx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,71 @@
+//go:build pgonline
+
+package pg
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestPruneMarket(t *testing.T) {
+	if err := cleanTables(archie.db); err != nil {
+		t.Fatalf("cleanTables: %v", err)
+	}
+
+	const epochIdx, epochDur = 132412341, 1000
+	maker := newLimitOrder(false, 4500000, 2, order.StandingTiF, 0)
+	base, quote := maker.Base(), maker.Quote()
+	doneMatch := newMatch(maker, newLimitOrder(true, 4490000, 1, order.ImmediateTiF, 10),
+		maker.Quantity/2, order.EpochID{Idx: epochIdx, Dur: epochDur})
+	activeMatch := newMatch(maker, newLimitOrder(true, 4490000, 1, order.ImmediateTiF, 20),
+		maker.Quantity/2, order.EpochID{Idx: epochIdx + 2, Dur: epochDur})
+	for _, match := range []*order.Match{doneMatch, activeMatch} {
+		if err := archie.InsertMatch(match); err != nil {
+			t.Fatalf("InsertMatch error: %v", err)
+		}
+		err := archie.InsertEpoch(&db.EpochResults{
+			MktBase:  base,
+			MktQuote: quote,
+			Idx:      int64(match.Epoch.Idx),
+			Dur:      epochDur,
+		})
+		if err != nil {
+			t.Fatalf("InsertEpoch error: %v", err)
+		}
+	}
+	mid := db.MarketMatchID{MatchID: doneMatch.ID(), Base: base, Quote: quote}
+	if err := archie.SetMatchInactive(mid, false); err != nil {
+		t.Fatalf("SetMatchInactive error: %v", err)
+	}
+
+	co := newCancelOrder(maker.ID(), base, quote, 30)
+	if err := archie.NewEpochOrder(co, epochIdx, epochDur, 1); err != nil {
+		t.Fatalf("NewEpochOrder error: %v", err)
+	}
+	if err := archie.ExecuteOrder(co); err != nil {
+		t.Fatalf("ExecuteOrder error: %v", err)
+	}
+
+	reportsBefore := time.UnixMilli((epochIdx + 2) * epochDur)
+	res, err := archie.PruneMarket(base, quote, time.Now(), reportsBefore, 0)
+	if err != nil {
+		t.Fatalf("PruneMarket error: %v", err)
+	}
+	if *res != (db.PruneResult{Matches: 1, CancelOrders: 1, Epochs: 1, EpochReports: 1}) {
+		t.Fatalf("wrong prune result: %+v", res)
+	}
+	if _, err = archie.MatchByID(activeMatch.ID(), base, quote); err != nil {
+		t.Fatalf("active match pruned: %v", err)
+	}
+
+	// Nothing more to prune while the match is active.
+	if res, err = archie.PruneMarket(base, quote, time.Now(), reportsBefore, 0); err != nil {
+		t.Fatalf("PruneMarket error: %v", err)
+	}
+	if *res != (db.PruneResult{}) {
+		t.Fatalf("wrong prune result: %+v", res)
+	}
+}
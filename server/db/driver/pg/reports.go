@@ -0,0 +1,56 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package pg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/pg/internal"
+)
+
+// MarketStats summarizes the trade matches made on the market in the time
+// range [start, end). The time of a match is the start of its epoch.
+func (a *Archiver) MarketStats(base, quote uint32, start, end time.Time) (*db.MarketStats, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	stats := &db.MarketStats{
+		Fees: make(map[uint32]uint64),
+	}
+	var vol, quoteVol int64
+	stmt := fmt.Sprintf(internal.SelectMatchStatsInRange, fullMatchesTableName(a.dbName, marketSchema))
+	err = a.db.QueryRowContext(ctx, stmt, startMs, endMs).Scan(&stats.Matches, &vol, &quoteVol, &stats.FailedSwaps)
+	if err != nil {
+		return nil, err
+	}
+	stats.Volume, stats.QuoteVolume = uint64(vol), uint64(quoteVol)
+
+	stmt = fmt.Sprintf(internal.SelectTradingFeeTotalsInRange, fullTradingFeesTableName(a.dbName, marketSchema))
+	if err = sumTradingFees(ctx, a.db, stmt, stats.Fees, startMs, endMs); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// NewAccounts counts the accounts created in the time range [start, end).
+// Accounts created before the accounts table recorded creation times are not
+// counted.
+func (a *Archiver) NewAccounts(start, end time.Time) (uint32, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	var n uint32
+	stmt := fmt.Sprintf(internal.SelectNewAccountCount, a.tables.accounts)
+	err := a.db.QueryRowContext(ctx, stmt, start.UnixMilli(), end.UnixMilli()).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,92 @@
+//go:build pgonline
+
+package pg
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestMarketStats(t *testing.T) {
+	if err := cleanTables(archie.db); err != nil {
+		t.Fatalf("cleanTables: %v", err)
+	}
+
+	limitBuyStanding := newLimitOrder(false, 4500000, 1, order.StandingTiF, 0)
+	limitSellImmediate := newLimitOrder(true, 4490000, 1, order.ImmediateTiF, 10)
+	base, quote := limitBuyStanding.Base(), limitBuyStanding.Quote()
+	epochID := order.EpochID{Idx: 132412341, Dur: 1000}
+	match := newMatch(limitBuyStanding, limitSellImmediate, limitSellImmediate.Quantity, epochID)
+	if err := archie.InsertMatch(match); err != nil {
+		t.Fatalf("InsertMatch error: %v", err)
+	}
+	matchTime := time.UnixMilli(int64(epochID.Idx * epochID.Dur)) // epoch start
+	fees := []*db.TradingFee{{
+		MatchID: match.ID(),
+		Account: limitBuyStanding.User(),
+		Maker:   true,
+		AssetID: base,
+		Amount:  1000,
+		Time:    matchTime.UnixMilli(),
+	}}
+	if err := archie.InsertTradingFees(base, quote, fees); err != nil {
+		t.Fatalf("InsertTradingFees error: %v", err)
+	}
+
+	stats, err := archie.MarketStats(base, quote, matchTime, matchTime.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("MarketStats error: %v", err)
+	}
+	if stats.Matches != 1 || stats.Volume != match.Quantity || stats.FailedSwaps != 0 {
+		t.Fatalf("wrong stats %+v", stats)
+	}
+	if stats.Fees[base] != 1000 {
+		t.Fatalf("wrong fees %v", stats.Fees)
+	}
+
+	// Matches and fees outside of the range are excluded.
+	stats, err = archie.MarketStats(base, quote, matchTime.Add(time.Second), matchTime.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("MarketStats error: %v", err)
+	}
+	if stats.Matches != 0 || stats.Volume != 0 || len(stats.Fees) != 0 {
+		t.Fatalf("unexpected stats outside of range %+v", stats)
+	}
+}
+
+func TestNewAccounts(t *testing.T) {
+	if err := cleanTables(archie.db); err != nil {
+		t.Fatalf("cleanTables: %v", err)
+	}
+
+	start := time.Now().Add(-time.Minute)
+	bond := &db.Bond{
+		AssetID:  42,
+		CoinID:   randomBytes(36),
+		Amount:   1e8,
+		Strength: 1,
+		LockTime: time.Now().Add(time.Hour).Unix(),
+	}
+	if err := archie.CreateAccountWithBond(tNewAccount(t), bond); err != nil {
+		t.Fatalf("CreateAccountWithBond error: %v", err)
+	}
+
+	n, err := archie.NewAccounts(start, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("NewAccounts error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 new account, got %d", n)
+	}
+
+	n, err = archie.NewAccounts(start.Add(-time.Hour), start)
+	if err != nil {
+		t.Fatalf("NewAccounts error: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expected no new accounts, got %d", n)
+	}
+}
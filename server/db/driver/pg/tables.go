@@ -20,6 +20,9 @@ const (
 	accountsTableName     = "accounts"
 	bondsTableName        = "bonds"
 	prepaidBondsTableName = "prepaid_bonds"
+	authSettingsTableName = "auth_settings"
+	swapConfsTableName    = "market_swap_confs"
+	banScoresTableName    = "ban_scores"
 
 	indexBondsOnAccountName  = "idx_bonds_on_acct"
 	indexBondsOnLockTimeName = "idx_bonds_on_locktime"
@@ -34,6 +37,7 @@ const (
 	cancelsActiveTableName   = "cancels_active"
 	epochReportsTableName    = "epoch_reports"
 	candlesTableName         = "candles"
+	tradingFeesTableName     = "trading_fees"
 )
 
 type tableStmt struct {
@@ -51,6 +55,9 @@ var createAccountTableStatements = []tableStmt{
 	{accountsTableName, internal.CreateAccountsTable},
 	{bondsTableName, internal.CreateBondsTable},
 	{prepaidBondsTableName, internal.CreatePrepaidBondsTable},
+	{authSettingsTableName, internal.CreateAuthSettingsTable},
+	{swapConfsTableName, internal.CreateMarketSwapConfsTable},
+	{banScoresTableName, internal.CreateBanScoresTable},
 }
 
 type indexStmt struct {
@@ -72,6 +79,7 @@ var createMarketTableStatements = []tableStmt{
 	{matchesTableName, internal.CreateMatchesTable}, // just one matches table per market for now
 	{epochsTableName, internal.CreateEpochsTable},
 	{epochReportsTableName, internal.CreateEpochReportTable},
+	{tradingFeesTableName, internal.CreateTradingFeesTable},
 }
 
 var tableMap = func() map[string]string {
@@ -123,6 +131,10 @@ func fullEpochReportsTableName(dbName, marketSchema string) string {
 	return dbName + "." + marketSchema + "." + epochReportsTableName
 }
 
+func fullTradingFeesTableName(dbName, marketSchema string) string {
+	return dbName + "." + marketSchema + "." + tradingFeesTableName
+}
+
 func fullCandlesTableName(dbName, marketSchema string, candleDur uint64) string {
 	const fiveMin = 5 * 60 * 1000
 	const oneHour = 60 * 60 * 1000
@@ -0,0 +1,101 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/pg/internal"
+)
+
+// InsertTradingFees records the trading fees accrued for matches on the
+// market. Fees that are already recorded are not modified.
+func (a *Archiver) InsertTradingFees(base, quote uint32, fees []*db.TradingFee) error {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return err
+	}
+	stmt := fmt.Sprintf(internal.InsertTradingFee, fullTradingFeesTableName(a.dbName, marketSchema))
+
+	dbTx, err := a.db.BeginTx(a.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil || errors.Is(err, sql.ErrTxDone) {
+			return
+		}
+		if errR := dbTx.Rollback(); errR != nil {
+			log.Errorf("Rollback failed: %v", errR)
+		}
+	}()
+
+	for _, fee := range fees {
+		_, err = dbTx.Exec(stmt, fee.MatchID, fee.Account, fee.Maker,
+			int64(fee.AssetID), int64(fee.Amount), fee.Time)
+		if err != nil {
+			a.fatalBackendErr(err)
+			return err
+		}
+	}
+
+	err = dbTx.Commit() // for the defer
+	return err
+}
+
+// MarketTradingFees sums the trading fees accrued on the market, by asset.
+func (a *Archiver) MarketTradingFees(base, quote uint32) (map[uint32]uint64, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	stmt := fmt.Sprintf(internal.SelectTradingFeeTotals, fullTradingFeesTableName(a.dbName, marketSchema))
+
+	totals := make(map[uint32]uint64)
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+	if err = sumTradingFees(ctx, a.db, stmt, totals); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// AccountTradingFees sums the trading fees accrued by the account on all
+// markets, by asset.
+func (a *Archiver) AccountTradingFees(aid account.AccountID) (map[uint32]uint64, error) {
+	totals := make(map[uint32]uint64)
+	for schema := range a.markets {
+		stmt := fmt.Sprintf(internal.SelectAccountTradingFeeTotals, fullTradingFeesTableName(a.dbName, schema))
+		ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+		err := sumTradingFees(ctx, a.db, stmt, totals, aid)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return totals, nil
+}
+
+// sumTradingFees adds the per-asset sums returned by the query to totals.
+func sumTradingFees(ctx context.Context, dbe *sql.DB, stmt string, totals map[uint32]uint64, args ...any) error {
+	rows, err := dbe.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assetID, amt int64
+		if err = rows.Scan(&assetID, &amt); err != nil {
+			return err
+		}
+		totals[uint32(assetID)] += uint64(amt)
+	}
+	return rows.Err()
+}
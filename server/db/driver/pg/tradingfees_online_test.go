@@ -0,0 +1,68 @@
+//go:build pgonline
+
+package pg
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestTradingFees(t *testing.T) {
+	if err := cleanTables(archie.db); err != nil {
+		t.Fatalf("cleanTables: %v", err)
+	}
+
+	limitBuyStanding := newLimitOrder(false, 4500000, 1, order.StandingTiF, 0)
+	limitSellImmediate := newLimitOrder(true, 4490000, 1, order.ImmediateTiF, 10)
+	base, quote := limitBuyStanding.Base(), limitBuyStanding.Quote()
+	match := newMatch(limitBuyStanding, limitSellImmediate, limitSellImmediate.Quantity, order.EpochID{132412341, 1000})
+	mid := match.ID()
+
+	fees := []*db.TradingFee{{
+		MatchID: mid,
+		Account: limitBuyStanding.User(),
+		Maker:   true,
+		AssetID: base,
+		Amount:  1000,
+		Time:    1,
+	}, {
+		MatchID: mid,
+		Account: limitSellImmediate.User(),
+		AssetID: quote,
+		Amount:  2000,
+		Time:    1,
+	}}
+	if err := archie.InsertTradingFees(base, quote, fees); err != nil {
+		t.Fatalf("InsertTradingFees error: %v", err)
+	}
+	// Inserting again does not double count.
+	if err := archie.InsertTradingFees(base, quote, fees); err != nil {
+		t.Fatalf("InsertTradingFees (repeat) error: %v", err)
+	}
+
+	totals, err := archie.MarketTradingFees(base, quote)
+	if err != nil {
+		t.Fatalf("MarketTradingFees error: %v", err)
+	}
+	if totals[base] != 1000 || totals[quote] != 2000 {
+		t.Fatalf("wrong market totals %v", totals)
+	}
+
+	totals, err = archie.AccountTradingFees(limitSellImmediate.User())
+	if err != nil {
+		t.Fatalf("AccountTradingFees error: %v", err)
+	}
+	if len(totals) != 1 || totals[quote] != 2000 {
+		t.Fatalf("wrong account totals %v", totals)
+	}
+
+	totals, err = archie.AccountTradingFees(randomAccountID())
+	if err != nil {
+		t.Fatalf("AccountTradingFees error: %v", err)
+	}
+	if len(totals) != 0 {
+		t.Fatalf("unexpected totals for unknown account %v", totals)
+	}
+}
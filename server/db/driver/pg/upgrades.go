@@ -17,7 +17,7 @@ import (
 	"decred.org/dcrdex/server/db/driver/pg/internal"
 )
 
-const dbVersion = 6
+const dbVersion = 9
 
 // The number of upgrades defined MUST be equal to dbVersion.
 var upgrades = []func(db *sql.Tx) error{
@@ -48,6 +48,17 @@ var upgrades = []func(db *sql.Tx) error{
 	// old_fee_coin column to the accounts table for when a manual refund is
 	// processed.
 	v6Upgrade,
+
+	// v7 upgrade adds the created column to the accounts table.
+	v7Upgrade,
+
+	// v8 upgrade adds the preimages and missed_commits columns to the epochs
+	// tables.
+	v8Upgrade,
+
+	// v9 upgrade adds the aSwapConf and bSwapConf columns to the matches
+	// tables.
+	v9Upgrade,
 }
 
 // v1Upgrade adds the schema_version column and removes the state_hash column
@@ -357,6 +368,55 @@ func v6Upgrade(tx *sql.Tx) error {
 	return nil
 }
 
+// v7Upgrade adds the created column to the accounts table. The creation time of
+// existing accounts is unknown, so it is left NULL.
+func v7Upgrade(tx *sql.Tx) error {
+	namespacedAccountsTable := publicSchema + "." + accountsTableName
+	_, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS created INT8;", namespacedAccountsTable))
+	return err
+}
+
+// v8Upgrade adds the preimages and missed_commits columns to the epochs table of
+// each market so that the match proof of a past epoch can be served without
+// the orders. The columns are NULL for epochs processed before the upgrade.
+func v8Upgrade(tx *sql.Tx) error {
+	mkts, err := loadMarkets(tx, marketsTableName)
+	if err != nil {
+		return fmt.Errorf("failed to read markets table: %w", err)
+	}
+
+	log.Infof("Adding match proof columns to epochs tables for %d markets", len(mkts))
+
+	for _, mkt := range mkts {
+		_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS preimages BYTEA[], "+
+			"ADD COLUMN IF NOT EXISTS missed_commits BYTEA[];", mkt.Name+"."+epochsTableName))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// v9Upgrade adds the aSwapConf and bSwapConf columns to the matches table of
+// each market. The columns are NULL for matches made before the upgrade.
+func v9Upgrade(tx *sql.Tx) error {
+	mkts, err := loadMarkets(tx, marketsTableName)
+	if err != nil {
+		return fmt.Errorf("failed to read markets table: %w", err)
+	}
+
+	log.Infof("Adding swap confirmation columns to matches tables for %d markets", len(mkts))
+
+	for _, mkt := range mkts {
+		_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS aSwapConf INT8, "+
+			"ADD COLUMN IF NOT EXISTS bSwapConf INT8;", mkt.Name+"."+matchesTableName))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DBVersion retrieves the database version from the meta table.
 func DBVersion(db *sql.DB) (ver uint32, err error) {
 	err = db.QueryRow(internal.SelectDBVersion).Scan(&ver)
@@ -0,0 +1,234 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+// Account retrieves the account pubkey and active bonds. If the account does
+// not exist or there is in an error retrieving any data, a nil
+// *account.Account is returned.
+func (a *Archiver) Account(aid account.AccountID, bondExpiry time.Time) (acct *account.Account, bonds []*db.Bond) {
+	acct, err := getAccount(a.db, a.tables.accounts, aid)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err == nil:
+	default:
+		log.Errorf("getAccount error: %v", err)
+		return nil, nil
+	}
+
+	bonds, err = getBondsForAccount(a.db, a.tables.bonds, aid, bondExpiry.Unix())
+	if err != nil {
+		log.Errorf("getBondsForAccount error: %v", err)
+		return nil, nil
+	}
+
+	return acct, bonds
+}
+
+// AccountInfo returns data for an account.
+func (a *Archiver) AccountInfo(aid account.AccountID) (*db.Account, error) {
+	stmt := fmt.Sprintf(internal.SelectAccountInfo, a.tables.accounts)
+	acct := new(db.Account)
+	if err := a.db.QueryRow(stmt, aid).Scan(&acct.AccountID, &acct.Pubkey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = db.ArchiveError{Code: db.ErrAccountUnknown}
+		}
+		return nil, err
+	}
+	return acct, nil
+}
+
+// CreateAccountWithBond creates a new account with a fidelity bond.
+func (a *Archiver) CreateAccountWithBond(acct *account.Account, bond *db.Bond) error {
+	dbTx, err := a.db.BeginTx(a.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil || errors.Is(err, sql.ErrTxDone) {
+			return
+		}
+		if errR := dbTx.Rollback(); errR != nil {
+			log.Errorf("Rollback failed: %v", errR)
+		}
+	}()
+
+	err = createAccountForBond(dbTx, a.tables.accounts, acct)
+	if err != nil {
+		return err
+	}
+	err = addBond(dbTx, a.tables.bonds, acct.ID, bond)
+	if err != nil {
+		return err
+	}
+
+	err = dbTx.Commit() // for the defer
+	return err
+}
+
+// AddBond stores a new Bond for an existing account.
+func (a *Archiver) AddBond(aid account.AccountID, bond *db.Bond) error {
+	return addBond(a.db, a.tables.bonds, aid, bond)
+}
+
+// DeleteBond deletes a bond, e.g. when it has expired.
+func (a *Archiver) DeleteBond(assetID uint32, coinID []byte) error {
+	stmt := fmt.Sprintf(internal.DeleteBond, a.tables.bonds)
+	_, err := a.db.Exec(stmt, coinID, assetID)
+	return err
+}
+
+// FetchPrepaidBond retrieves the strength and lock time of a prepaid bond.
+func (a *Archiver) FetchPrepaidBond(coinID []byte) (strength uint32, lockTime int64, err error) {
+	stmt := fmt.Sprintf(internal.SelectPrepaidBond, a.tables.prepaidBonds)
+	err = a.db.QueryRow(stmt, coinID).Scan(&strength, &lockTime)
+	return
+}
+
+// DeletePrepaidBond deletes a prepaid bond, e.g. when it has been redeemed.
+func (a *Archiver) DeletePrepaidBond(coinID []byte) (err error) {
+	stmt := fmt.Sprintf(internal.DeletePrepaidBond, a.tables.prepaidBonds)
+	_, err = a.db.ExecContext(a.ctx, stmt, coinID)
+	return
+}
+
+// StorePrepaidBonds stores prepaid bonds with the same strength and lock time.
+func (a *Archiver) StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64) error {
+	stmt := fmt.Sprintf(internal.InsertPrepaidBond, a.tables.prepaidBonds)
+	for i := range coinIDs {
+		if _, err := a.db.ExecContext(a.ctx, stmt, coinIDs[i], strength, lockTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// xpubHash is the fee_keys table key for an xpub. Unlike the pg driver, which
+// uses hash160, this is a plain sha256 hash.
+func xpubHash(xpub string) []byte {
+	h := sha256.Sum256([]byte(xpub))
+	return h[:]
+}
+
+// KeyIndex returns the current child index for the an xpub. If it is not
+// known, this creates a new entry with index zero.
+func (a *Archiver) KeyIndex(xpub string) (uint32, error) {
+	keyHash := xpubHash(xpub)
+
+	var child uint32
+	stmt := fmt.Sprintf(internal.CurrentKeyIndex, a.tables.feeKeys)
+	err := a.db.QueryRow(stmt, keyHash).Scan(&child)
+	switch {
+	case errors.Is(err, sql.ErrNoRows): // continue to create new entry
+	case err == nil:
+		return child, nil
+	default:
+		return 0, err
+	}
+
+	log.Debugf("Inserting key entry for xpub %.40s..., sha256 = %x", xpub, keyHash)
+	stmt = fmt.Sprintf(internal.InsertKeyIfMissing, a.tables.feeKeys)
+	err = a.db.QueryRow(stmt, keyHash).Scan(&child)
+	if err != nil {
+		return 0, err
+	}
+	return child, nil
+}
+
+// SetKeyIndex records the child index for an xpub. An error is returned
+// unless exactly 1 row is updated or created.
+func (a *Archiver) SetKeyIndex(idx uint32, xpub string) error {
+	keyHash := xpubHash(xpub)
+	log.Debugf("Recording new index %d for xpub %.40s... (%x)", idx, xpub, keyHash)
+	stmt := fmt.Sprintf(internal.UpsertKeyIndex, a.tables.feeKeys)
+	N, err := sqlExec(a.db, stmt, idx, keyHash)
+	if err != nil {
+		return err
+	}
+	if N != 1 {
+		return fmt.Errorf("updated %d rows, expected 1", N)
+	}
+	return nil
+}
+
+// createAccountTables creates the accounts and fee_keys tables.
+func createAccountTables(db sqlQueryExecutor) error {
+	for _, c := range createAccountTableStatements {
+		created, err := createTable(db, "", c.name)
+		if err != nil {
+			return err
+		}
+		if created {
+			log.Tracef("Table %s created", c.name)
+		}
+	}
+
+	for _, c := range createBondIndexesStatements {
+		err := createIndexStmt(db, c.stmt, c.idxName, fullTableName("", c.tableName))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getAccount(dbe sqlQueryer, tableName string, aid account.AccountID) (*account.Account, error) {
+	var pubkey []byte
+	stmt := fmt.Sprintf(internal.SelectAccount, tableName)
+	if err := dbe.QueryRow(stmt, aid).Scan(&pubkey); err != nil {
+		return nil, err
+	}
+	return account.NewAccountFromPubKey(pubkey)
+}
+
+// createAccountForBond creates an entry for the account in the accounts table.
+func createAccountForBond(dbe sqlExecutor, tableName string, acct *account.Account) error {
+	stmt := fmt.Sprintf(internal.CreateAccountForBond, tableName)
+	_, err := dbe.Exec(stmt, acct.ID, acct.PubKey.SerializeCompressed(), time.Now().UnixMilli())
+	return err
+}
+
+func addBond(dbe sqlExecutor, tableName string, aid account.AccountID, bond *db.Bond) error {
+	stmt := fmt.Sprintf(internal.AddBond, tableName)
+	_, err := dbe.Exec(stmt, bond.Version, bond.CoinID, bond.AssetID, aid,
+		bond.Amount, bond.Strength, bond.LockTime)
+	return err
+}
+
+func getBondsForAccount(dbe sqlQueryer, tableName string, acct account.AccountID, bondExpiryTime int64) ([]*db.Bond, error) {
+	stmt := fmt.Sprintf(internal.SelectActiveBondsForUser, tableName)
+	rows, err := dbe.Query(stmt, acct, bondExpiryTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bonds []*db.Bond
+	for rows.Next() {
+		var bond db.Bond
+		err = rows.Scan(&bond.Version, &bond.CoinID, &bond.AssetID,
+			&bond.Amount, &bond.Strength, &bond.LockTime)
+		if err != nil {
+			return nil, err
+		}
+		bonds = append(bonds, &bond)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return bonds, nil
+}
@@ -0,0 +1,137 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestAccounts(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	privKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privKey.PubKey()
+	acct := &account.Account{
+		ID:     account.NewID(pubKey.SerializeCompressed()),
+		PubKey: pubKey,
+	}
+
+	lockTime := time.Now().Add(time.Hour).Truncate(time.Second)
+	bond := &db.Bond{
+		AssetID:  AssetDCR,
+		CoinID:   randomBytes(36),
+		Amount:   1e8,
+		Strength: 1,
+		LockTime: lockTime.Unix(),
+	}
+	if err = archie.CreateAccountWithBond(acct, bond); err != nil {
+		t.Fatalf("CreateAccountWithBond: %v", err)
+	}
+
+	acct2, bonds := archie.Account(acct.ID, time.Now())
+	if acct2 == nil {
+		t.Fatalf("account not found")
+	}
+	if !acct2.PubKey.IsEqual(pubKey) {
+		t.Fatalf("wrong pubkey")
+	}
+	if len(bonds) != 1 || !bytes.Equal(bonds[0].CoinID, bond.CoinID) || bonds[0].LockTime != bond.LockTime {
+		t.Fatalf("wrong bonds: %+v", bonds)
+	}
+
+	// Bonds with a lock time before the threshold are not returned.
+	bond2 := &db.Bond{
+		AssetID:  AssetBTC,
+		CoinID:   randomBytes(36),
+		Amount:   1e6,
+		Strength: 2,
+		LockTime: lockTime.Add(time.Hour).Unix(),
+	}
+	if err = archie.AddBond(acct.ID, bond2); err != nil {
+		t.Fatalf("AddBond: %v", err)
+	}
+	if _, bonds = archie.Account(acct.ID, lockTime.Add(time.Minute)); len(bonds) != 1 || bonds[0].AssetID != AssetBTC {
+		t.Fatalf("wrong bonds after threshold: %+v", bonds)
+	}
+	if err = archie.DeleteBond(AssetBTC, bond2.CoinID); err != nil {
+		t.Fatalf("DeleteBond: %v", err)
+	}
+	if _, bonds = archie.Account(acct.ID, time.Now()); len(bonds) != 1 {
+		t.Fatalf("expected 1 bond after deleting, got %d", len(bonds))
+	}
+
+	info, err := archie.AccountInfo(acct.ID)
+	if err != nil {
+		t.Fatalf("AccountInfo: %v", err)
+	}
+	if info.AccountID != acct.ID || !bytes.Equal(info.Pubkey, pubKey.SerializeCompressed()) {
+		t.Fatalf("wrong account info: %+v", info)
+	}
+
+	if acct3, _ := archie.Account(randomAccountID(), time.Now()); acct3 != nil {
+		t.Fatalf("found unknown account")
+	}
+
+	n, err := archie.NewAccounts(time.Now().Add(-time.Minute), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("NewAccounts: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 new account, got %d", n)
+	}
+}
+
+func TestPrepaidBonds(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	coinIDs := [][]byte{randomBytes(32), randomBytes(32)}
+	lockTime := time.Now().Add(time.Hour).Unix()
+	if err := archie.StorePrepaidBonds(coinIDs, 2, lockTime); err != nil {
+		t.Fatalf("StorePrepaidBonds: %v", err)
+	}
+	strength, lt, err := archie.FetchPrepaidBond(coinIDs[0])
+	if err != nil {
+		t.Fatalf("FetchPrepaidBond: %v", err)
+	}
+	if strength != 2 || lt != lockTime {
+		t.Fatalf("wrong prepaid bond strength %d, lock time %d", strength, lt)
+	}
+	if err = archie.DeletePrepaidBond(coinIDs[0]); err != nil {
+		t.Fatalf("DeletePrepaidBond: %v", err)
+	}
+	if _, _, err = archie.FetchPrepaidBond(coinIDs[0]); err == nil {
+		t.Fatalf("no error fetching deleted prepaid bond")
+	}
+}
+
+func TestKeyIndex(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	const xpub = "tpubVpQyRpZW7Au1ayvmo2NyWSXtHGqoXyEq8DEAyaKFpuXwdbcL6yHMGAuDjKTBnTQQzkJXgxdPzHRFNDjQQ2QVxi4XfvrjdSovsLWdfUsdgqk"
+	idx, err := archie.KeyIndex(xpub)
+	if err != nil {
+		t.Fatalf("KeyIndex: %v", err)
+	}
+	if idx != 0 {
+		t.Fatalf("expected index 0 for a new key, got %d", idx)
+	}
+	if err = archie.SetKeyIndex(12, xpub); err != nil {
+		t.Fatalf("SetKeyIndex: %v", err)
+	}
+	if idx, err = archie.KeyIndex(xpub); err != nil {
+		t.Fatalf("KeyIndex: %v", err)
+	}
+	if idx != 12 {
+		t.Fatalf("expected index 12, got %d", idx)
+	}
+}
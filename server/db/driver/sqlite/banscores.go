@@ -0,0 +1,37 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+// BanScore retrieves the account's ban score, or nil if the account has none.
+func (a *Archiver) BanScore(aid account.AccountID) (*db.BanScore, error) {
+	stmt := fmt.Sprintf(internal.SelectBanScore, a.tables.banScores)
+	var bs db.BanScore
+	err := a.db.QueryRowContext(a.ctx, stmt, aid).Scan(&bs.Score, (*msTime)(&bs.Stamp),
+		(*msTime)(&bs.SuspendedUntil), &bs.Banned)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bs, nil
+}
+
+// SetBanScore stores the account's ban score, replacing any stored score.
+func (a *Archiver) SetBanScore(aid account.AccountID, bs *db.BanScore) error {
+	stmt := fmt.Sprintf(internal.UpsertBanScore, a.tables.banScores)
+	_, err := a.db.ExecContext(a.ctx, stmt, aid, bs.Score, msTime(bs.Stamp),
+		msTime(bs.SuspendedUntil), bs.Banned)
+	return err
+}
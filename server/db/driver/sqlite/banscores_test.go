@@ -0,0 +1,51 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestBanScores(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	var aid account.AccountID
+	aid[0] = 0x01
+
+	bs, err := archie.BanScore(aid)
+	if err != nil {
+		t.Fatalf("BanScore: %v", err)
+	}
+	if bs != nil {
+		t.Fatalf("expected no ban score, got %+v", bs)
+	}
+
+	for _, set := range []*db.BanScore{
+		{Score: 12.5, Stamp: time.UnixMilli(1700000000000).UTC()},
+		{Score: 60, Stamp: time.UnixMilli(1700000100000).UTC(), SuspendedUntil: time.UnixMilli(1700086500000).UTC()},
+		{Score: 151.25, Stamp: time.UnixMilli(1700000200000).UTC(), SuspendedUntil: time.UnixMilli(1700086500000).UTC(), Banned: true},
+	} {
+		if err := archie.SetBanScore(aid, set); err != nil {
+			t.Fatalf("SetBanScore: %v", err)
+		}
+		bs, err = archie.BanScore(aid)
+		if err != nil {
+			t.Fatalf("BanScore: %v", err)
+		}
+		if bs.Score != set.Score || bs.Banned != set.Banned || !bs.Stamp.Equal(set.Stamp) ||
+			!bs.SuspendedUntil.Equal(set.SuspendedUntil) || bs.SuspendedUntil.IsZero() != set.SuspendedUntil.IsZero() {
+			t.Fatalf("wrong ban score. wanted %+v, got %+v", set, bs)
+		}
+	}
+
+	// Other accounts are unaffected.
+	aid[0] = 0x02
+	if bs, err = archie.BanScore(aid); err != nil || bs != nil {
+		t.Fatalf("expected no ban score for another account, got %+v, %v", bs, err)
+	}
+}
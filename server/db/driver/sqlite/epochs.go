@@ -0,0 +1,291 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"decred.org/dcrdex/dex/candles"
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+// InsertEpoch stores the results of a newly-processed epoch.
+func (a *Archiver) InsertEpoch(ed *db.EpochResults) error {
+	marketSchema, err := a.marketSchema(ed.MktBase, ed.MktQuote)
+	if err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf(internal.InsertEpoch, fullEpochsTableName(marketSchema))
+	_, err = a.db.Exec(stmt, ed.Idx, ed.Dur, ed.MatchTime, ed.CSum, ed.Seed,
+		orderIDs(ed.OrdersRevealed), orderIDs(ed.OrdersMissed),
+		preimages(ed.Preimages), commitments(ed.MissedCommits))
+	if err != nil {
+		a.fatalBackendErr(err)
+		return err
+	}
+
+	stmt = fmt.Sprintf(internal.InsertEpochReport, fullEpochReportsTableName(marketSchema))
+	epochEnd := (ed.Idx + 1) * ed.Dur
+	_, err = a.db.Exec(stmt, epochEnd, ed.Dur, ed.MatchVolume, ed.QuoteVolume, ed.BookBuys, ed.BookBuys5, ed.BookBuys25,
+		ed.BookSells, ed.BookSells5, ed.BookSells25, ed.HighRate, ed.LowRate, ed.StartRate, ed.EndRate)
+	if err != nil {
+		a.fatalBackendErr(err)
+	}
+
+	return err
+}
+
+// EpochProof retrieves the match proof for a processed epoch.
+func (a *Archiver) EpochProof(base, quote uint32, epochIdx, epochDur int64) (*db.EpochProof, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	stmt := fmt.Sprintf(internal.SelectEpochProof, fullEpochsTableName(marketSchema))
+	proof := &db.EpochProof{
+		Idx: epochIdx,
+		Dur: epochDur,
+	}
+	err = a.db.QueryRowContext(ctx, stmt, epochIdx, epochDur).Scan(&proof.MatchTime,
+		&proof.CSum, &proof.Seed, (*orderIDs)(&proof.OrdersRevealed), (*preimages)(&proof.Preimages),
+		(*orderIDs)(&proof.OrdersMissed), (*commitments)(&proof.MissedCommits))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, db.ArchiveError{Code: db.ErrUnknownEpoch}
+		}
+		return nil, err
+	}
+	return proof, nil
+}
+
+// LastEpochRate gets the EndRate of the last EpochResults inserted for the
+// market. If the database is empty, no error and a rate of zero are returned.
+func (a *Archiver) LastEpochRate(base, quote uint32) (rate uint64, err error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return 0, err
+	}
+
+	stmt := fmt.Sprintf(internal.SelectLastEpochRate, fullEpochReportsTableName(marketSchema))
+	if err = a.db.QueryRowContext(a.ctx, stmt).Scan(&rate); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	return rate, nil
+}
+
+// LoadEpochStats reads all market epoch history from the database, updating the
+// provided caches along the way.
+func (a *Archiver) LoadEpochStats(base, quote uint32, caches []*candles.Cache) error {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	// First load stored candles from the candles tables. Establish a start
+	// stamp for scanning epoch reports for partial candles.
+	var oldestNeeded uint64 = math.MaxUint64
+	sinceCaches := make(map[uint64]*candles.Cache, 0) // maps oldest end stamp
+	now := uint64(time.Now().UnixMilli())
+	for _, cache := range caches {
+		if err = a.loadCandles(marketSchema, cache, candles.CacheSize); err != nil {
+			return fmt.Errorf("loadCandles: %w", err)
+		}
+
+		var since uint64
+		if len(cache.Candles) > 0 {
+			// If we have candles, set our since value to the next expected
+			// epoch stamp.
+			idx := cache.Last().EndStamp / cache.BinSize
+			since = (idx + 1) * cache.BinSize
+		} else {
+			since = now - (cache.BinSize * candles.CacheSize)
+			since = since - since%cache.BinSize // truncate to first end stamp of the epoch
+		}
+		if since < oldestNeeded {
+			oldestNeeded = since
+		}
+		sinceCaches[since] = cache
+	}
+	if oldestNeeded > math.MaxInt64 { // no caches
+		return nil
+	}
+
+	stmt := fmt.Sprintf(internal.SelectEpochCandles, fullEpochReportsTableName(marketSchema))
+	rows, err := a.db.QueryContext(ctx, stmt, oldestNeeded)
+	if err != nil {
+		return fmt.Errorf("SelectEpochCandles: %w", err)
+	}
+	defer rows.Close()
+
+	var endStamp, epochDur, matchVol, quoteVol, highRate, lowRate, startRate, endRate uint64
+	for rows.Next() {
+		err = rows.Scan(&endStamp, &epochDur, &matchVol, &quoteVol, &highRate, &lowRate, &startRate, &endRate)
+		if err != nil {
+			return fmt.Errorf("Scan: %w", err)
+		}
+		candle := &candles.Candle{
+			StartStamp:  endStamp - epochDur,
+			EndStamp:    endStamp,
+			MatchVolume: matchVol,
+			QuoteVolume: quoteVol,
+			HighRate:    highRate,
+			LowRate:     lowRate,
+			StartRate:   startRate,
+			EndRate:     endRate,
+		}
+		for since, cache := range sinceCaches {
+			if endStamp > since {
+				cache.Add(candle)
+			}
+		}
+	}
+
+	return rows.Err()
+}
+
+// LastCandleEndStamp pulls the last stored candles end stamp for a market and
+// candle duration.
+func (a *Archiver) LastCandleEndStamp(base, quote uint32, candleDur uint64) (uint64, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	stmt := fmt.Sprintf(internal.SelectLastEndStamp, fullCandlesTableName(marketSchema, candleDur))
+	var endStamp uint64
+	if err = a.db.QueryRowContext(ctx, stmt).Scan(&endStamp); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return endStamp, nil
+}
+
+// InsertCandles inserts new candles for a market and candle duration.
+func (a *Archiver) InsertCandles(base, quote uint32, candleDur uint64, cs []*candles.Candle) error {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return err
+	}
+	stmt := fmt.Sprintf(internal.InsertCandle, fullCandlesTableName(marketSchema, candleDur))
+
+	insert := func(c *candles.Candle) error {
+		ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+		defer cancel()
+
+		_, err = a.db.ExecContext(ctx, stmt,
+			c.EndStamp, c.MatchVolume, c.QuoteVolume, c.HighRate, c.LowRate, c.StartRate, c.EndRate,
+		)
+		if err != nil {
+			a.fatalBackendErr(err)
+			return err
+		}
+		return nil
+	}
+
+	for _, c := range cs {
+		if err = insert(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CandleHistory retrieves up to limit candles of the specified duration that
+// ended after the after stamp and no later than the through stamp, oldest
+// first.
+func (a *Archiver) CandleHistory(base, quote uint32, candleDur, after, through uint64, limit int) ([]*candles.Candle, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	if through > math.MaxInt64 {
+		through = math.MaxInt64
+	}
+	stmt := fmt.Sprintf(internal.SelectCandleRange, fullCandlesTableName(marketSchema, candleDur))
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	rows, err := a.db.QueryContext(ctx, stmt, int64(after), int64(through), limit)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	cs := make([]*candles.Candle, 0, limit)
+	var endStamp, matchVol, quoteVol, highRate, lowRate, startRate, endRate uint64
+	for rows.Next() {
+		err = rows.Scan(&endStamp, &matchVol, &quoteVol, &highRate, &lowRate, &startRate, &endRate)
+		if err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		cs = append(cs, &candles.Candle{
+			StartStamp:  endStamp - candleDur,
+			EndStamp:    endStamp,
+			MatchVolume: matchVol,
+			QuoteVolume: quoteVol,
+			HighRate:    highRate,
+			LowRate:     lowRate,
+			StartRate:   startRate,
+			EndRate:     endRate,
+		})
+	}
+
+	return cs, rows.Err()
+}
+
+// loadCandles loads the last n candles of a specified duration and market into
+// the provided cache.
+func (a *Archiver) loadCandles(marketSchema string, cache *candles.Cache, n uint64) error {
+	candleDur := cache.BinSize
+	stmt := fmt.Sprintf(internal.SelectCandles, fullCandlesTableName(marketSchema, candleDur))
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	rows, err := a.db.QueryContext(ctx, stmt, n)
+	if err != nil {
+		return fmt.Errorf("QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	var endStamp, matchVol, quoteVol, highRate, lowRate, startRate, endRate uint64
+	for rows.Next() {
+		err = rows.Scan(&endStamp, &matchVol, &quoteVol, &highRate, &lowRate, &startRate, &endRate)
+		if err != nil {
+			return fmt.Errorf("Scan: %w", err)
+		}
+		cache.Add(&candles.Candle{
+			StartStamp:  endStamp - candleDur,
+			EndStamp:    endStamp,
+			MatchVolume: matchVol,
+			QuoteVolume: quoteVol,
+			HighRate:    highRate,
+			LowRate:     lowRate,
+			StartRate:   startRate,
+			EndRate:     endRate,
+		})
+	}
+
+	return rows.Err()
+}
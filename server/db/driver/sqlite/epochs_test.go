@@ -0,0 +1,192 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/candles"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestEpochs(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	rate, err := archie.LastEpochRate(AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("LastEpochRate: %v", err)
+	}
+	if rate != 0 {
+		t.Fatalf("expected zero rate for empty DB, got %d", rate)
+	}
+
+	// Only recent epochs are loaded by LoadEpochStats.
+	dur := int64(EpochDuration)
+	startIdx := time.Now().UnixMilli()/dur - 10
+	for i := int64(0); i < 3; i++ {
+		err = archie.InsertEpoch(&db.EpochResults{
+			MktBase:        AssetDCR,
+			MktQuote:       AssetBTC,
+			Idx:            startIdx + i,
+			Dur:            dur,
+			MatchTime:      (startIdx+i+1)*dur + 1,
+			CSum:           randomBytes(32),
+			Seed:           randomBytes(32),
+			OrdersRevealed: []order.OrderID{newLimitOrder(false, 1, 1, 0).ID()},
+			MatchVolume:    LotSize,
+			QuoteVolume:    LotSize / 2,
+			HighRate:       uint64(5_000_000 + i),
+			LowRate:        4_000_000,
+			StartRate:      4_500_000,
+			EndRate:        uint64(4_600_000 + i),
+		})
+		if err != nil {
+			t.Fatalf("InsertEpoch: %v", err)
+		}
+	}
+
+	if rate, err = archie.LastEpochRate(AssetDCR, AssetBTC); err != nil {
+		t.Fatalf("LastEpochRate: %v", err)
+	}
+	if rate != 4_600_002 {
+		t.Fatalf("wrong last epoch rate %d", rate)
+	}
+
+	cache := candles.NewCache(10, uint64(dur))
+	if err = archie.LoadEpochStats(AssetDCR, AssetBTC, []*candles.Cache{cache}); err != nil {
+		t.Fatalf("LoadEpochStats: %v", err)
+	}
+	if len(cache.Candles) != 3 || cache.Candles[2].EndRate != 4_600_002 || cache.Candles[0].MatchVolume != LotSize {
+		t.Fatalf("wrong epoch candles: %+v", cache.Candles)
+	}
+}
+
+func TestEpochProof(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	dur := int64(EpochDuration)
+	lo1, lo2, lo3 := newLimitOrder(false, 1, 1, 0), newLimitOrder(true, 1, 1, 0), newLimitOrder(false, 2, 1, 0)
+	var pi1, pi2 order.Preimage
+	copy(pi1[:], randomBytes(order.PreimageSize))
+	copy(pi2[:], randomBytes(order.PreimageSize))
+	ed := &db.EpochResults{
+		MktBase:        AssetDCR,
+		MktQuote:       AssetBTC,
+		Idx:            100,
+		Dur:            dur,
+		MatchTime:      101*dur + 1,
+		CSum:           randomBytes(32),
+		Seed:           randomBytes(32),
+		OrdersRevealed: []order.OrderID{lo2.ID(), lo1.ID()},
+		Preimages:      []order.Preimage{pi2, pi1},
+		OrdersMissed:   []order.OrderID{lo3.ID()},
+		MissedCommits:  []order.Commitment{lo3.Commit},
+	}
+	if err := archie.InsertEpoch(ed); err != nil {
+		t.Fatalf("InsertEpoch: %v", err)
+	}
+	// An epoch stored without proof material.
+	if err := archie.InsertEpoch(&db.EpochResults{
+		MktBase:  AssetDCR,
+		MktQuote: AssetBTC,
+		Idx:      101,
+		Dur:      dur,
+	}); err != nil {
+		t.Fatalf("InsertEpoch: %v", err)
+	}
+
+	proof, err := archie.EpochProof(AssetDCR, AssetBTC, 100, dur)
+	if err != nil {
+		t.Fatalf("EpochProof: %v", err)
+	}
+	if proof.MatchTime != ed.MatchTime || !bytes.Equal(proof.CSum, ed.CSum) || !bytes.Equal(proof.Seed, ed.Seed) {
+		t.Fatalf("wrong epoch proof: %+v", proof)
+	}
+	if !reflect.DeepEqual(proof.OrdersRevealed, ed.OrdersRevealed) || !reflect.DeepEqual(proof.Preimages, ed.Preimages) {
+		t.Fatalf("wrong revealed orders or preimages")
+	}
+	if !reflect.DeepEqual(proof.OrdersMissed, ed.OrdersMissed) || !reflect.DeepEqual(proof.MissedCommits, ed.MissedCommits) {
+		t.Fatalf("wrong missed orders or commitments")
+	}
+
+	proof, err = archie.EpochProof(AssetDCR, AssetBTC, 101, dur)
+	if err != nil {
+		t.Fatalf("EpochProof: %v", err)
+	}
+	if proof.Preimages != nil || proof.MissedCommits != nil {
+		t.Fatalf("expected no proof material")
+	}
+
+	if _, err = archie.EpochProof(AssetDCR, AssetBTC, 102, dur); !db.IsErrEpochUnknown(err) {
+		t.Fatalf("expected unknown epoch error, got %v", err)
+	}
+}
+
+func TestCandles(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	const binSize = uint64(60_000)
+	stamp, err := archie.LastCandleEndStamp(AssetDCR, AssetBTC, binSize)
+	if err != nil {
+		t.Fatalf("LastCandleEndStamp: %v", err)
+	}
+	if stamp != 0 {
+		t.Fatalf("expected zero end stamp for empty DB, got %d", stamp)
+	}
+
+	cs := []*candles.Candle{
+		{StartStamp: 0, EndStamp: binSize, MatchVolume: 1, EndRate: 10},
+		{StartStamp: binSize, EndStamp: binSize * 2, MatchVolume: 2, EndRate: 20},
+	}
+	if err = archie.InsertCandles(AssetDCR, AssetBTC, binSize, cs); err != nil {
+		t.Fatalf("InsertCandles: %v", err)
+	}
+	// Inserting an existing candle updates it.
+	cs[1].MatchVolume = 3
+	if err = archie.InsertCandles(AssetDCR, AssetBTC, binSize, cs[1:]); err != nil {
+		t.Fatalf("InsertCandles (update): %v", err)
+	}
+
+	if stamp, err = archie.LastCandleEndStamp(AssetDCR, AssetBTC, binSize); err != nil {
+		t.Fatalf("LastCandleEndStamp: %v", err)
+	}
+	if stamp != binSize*2 {
+		t.Fatalf("wrong last candle end stamp %d", stamp)
+	}
+
+	cache := candles.NewCache(10, binSize)
+	if err = archie.LoadEpochStats(AssetDCR, AssetBTC, []*candles.Cache{cache}); err != nil {
+		t.Fatalf("LoadEpochStats: %v", err)
+	}
+	if len(cache.Candles) != 2 || cache.Candles[1].MatchVolume != 3 {
+		t.Fatalf("wrong candles: %+v", cache.Candles)
+	}
+
+	// Page through the history one candle at a time.
+	page, err := archie.CandleHistory(AssetDCR, AssetBTC, binSize, 0, binSize*10, 1)
+	if err != nil {
+		t.Fatalf("CandleHistory: %v", err)
+	}
+	if len(page) != 1 || page[0].EndStamp != binSize || page[0].StartStamp != 0 {
+		t.Fatalf("wrong first page: %+v", page)
+	}
+	page, err = archie.CandleHistory(AssetDCR, AssetBTC, binSize, page[0].EndStamp, binSize*10, 1)
+	if err != nil {
+		t.Fatalf("CandleHistory: %v", err)
+	}
+	if len(page) != 1 || page[0].EndStamp != binSize*2 || page[0].MatchVolume != 3 {
+		t.Fatalf("wrong second page: %+v", page)
+	}
+	// The through stamp is inclusive.
+	if page, err = archie.CandleHistory(AssetDCR, AssetBTC, binSize, 0, binSize, 10); err != nil {
+		t.Fatalf("CandleHistory: %v", err)
+	}
+	if len(page) != 1 {
+		t.Fatalf("expected 1 candle through the first end stamp, got %d", len(page))
+	}
+}
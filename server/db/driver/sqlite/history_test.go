@@ -0,0 +1,146 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestHistory(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	const epochIdx = 156649765
+	dur := int64(EpochDuration)
+	epochStart := time.UnixMilli(epochIdx * dur)
+
+	// A booked maker matched by two takers in consecutive epochs, and a
+	// canceled order.
+	maker := newLimitOrder(true, 4_900_000, 2, 0)
+	bookOrder(t, archie, maker)
+	taker1 := newLimitOrder(false, 5_000_000, 1, 10)
+	taker2 := newLimitOrder(false, 5_000_000, 1, 20)
+	canceled := newLimitOrder(false, 4_000_000, 1, 30)
+	bookOrder(t, archie, canceled)
+	for _, lo := range []*order.LimitOrder{taker1, taker2} {
+		if err := archie.NewEpochOrder(lo, epochIdx, dur, db.EpochGapNA); err != nil {
+			t.Fatalf("NewEpochOrder: %v", err)
+		}
+	}
+	co := newCancelOrder(canceled.ID(), canceled.User(), 40)
+	if err := archie.NewEpochOrder(co, epochIdx, dur, 1); err != nil {
+		t.Fatalf("NewEpochOrder: %v", err)
+	}
+	if err := archie.ExecuteOrder(co); err != nil {
+		t.Fatalf("ExecuteOrder: %v", err)
+	}
+	if err := archie.CancelOrder(canceled); err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+	match1 := newMatch(maker, taker1, LotSize, order.EpochID{Idx: epochIdx, Dur: EpochDuration})
+	match2 := newMatch(maker, taker2, LotSize, order.EpochID{Idx: epochIdx + 1, Dur: EpochDuration})
+	for _, match := range []*order.Match{match1, match2} {
+		if err := archie.InsertMatch(match); err != nil {
+			t.Fatalf("InsertMatch: %v", err)
+		}
+	}
+
+	matchHistory := func(filter *db.HistoryFilter) []order.MatchID {
+		t.Helper()
+		var mids []order.MatchID
+		n, err := archie.MatchHistory(filter, func(m *db.MatchDataWithCoins) error {
+			mids = append(mids, m.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("MatchHistory: %v", err)
+		}
+		if n != len(mids) {
+			t.Fatalf("MatchHistory returned %d, streamed %d", n, len(mids))
+		}
+		return mids
+	}
+
+	filter := &db.HistoryFilter{
+		Base:  AssetDCR,
+		Quote: AssetBTC,
+		Start: epochStart,
+		End:   epochStart.Add(time.Hour),
+	}
+	if mids := matchHistory(filter); len(mids) != 2 || mids[0] != match1.ID() || mids[1] != match2.ID() {
+		t.Fatalf("wrong matches: %v", mids)
+	}
+	// The end of the range is exclusive.
+	filter.End = time.UnixMilli((epochIdx + 1) * dur)
+	if mids := matchHistory(filter); len(mids) != 1 || mids[0] != match1.ID() {
+		t.Fatalf("wrong matches: %v", mids)
+	}
+	// Both of the maker's matches, but only one of the takers'.
+	filter.End = epochStart.Add(time.Hour)
+	acct := maker.User()
+	filter.Account = &acct
+	if mids := matchHistory(filter); len(mids) != 2 {
+		t.Fatalf("wrong number of maker matches: %d", len(mids))
+	}
+	acct = taker2.User()
+	if mids := matchHistory(filter); len(mids) != 1 || mids[0] != match2.ID() {
+		t.Fatalf("wrong taker matches: %v", mids)
+	}
+
+	orderHistory := func(filter *db.HistoryFilter) ([]order.OrderID, []order.OrderStatus) {
+		t.Helper()
+		var oids []order.OrderID
+		var statuses []order.OrderStatus
+		n, err := archie.OrderHistory(filter, func(ord order.Order, status order.OrderStatus) error {
+			if ord.Base() != AssetDCR || ord.Quote() != AssetBTC {
+				t.Fatalf("wrong market for order %v", ord)
+			}
+			oids = append(oids, ord.ID())
+			statuses = append(statuses, status)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("OrderHistory: %v", err)
+		}
+		if n != len(oids) {
+			t.Fatalf("OrderHistory returned %d, streamed %d", n, len(oids))
+		}
+		return oids, statuses
+	}
+
+	filter = &db.HistoryFilter{
+		Base:  AssetDCR,
+		Quote: AssetBTC,
+		Start: maker.ServerTime,
+		End:   co.ServerTime.Add(time.Millisecond),
+	}
+	oids, statuses := orderHistory(filter)
+	wantOIDs := []order.OrderID{maker.ID(), taker1.ID(), taker2.ID(), canceled.ID(), co.ID()}
+	wantStatuses := []order.OrderStatus{order.OrderStatusBooked, order.OrderStatusEpoch,
+		order.OrderStatusEpoch, order.OrderStatusCanceled, order.OrderStatusExecuted}
+	if len(oids) != len(wantOIDs) {
+		t.Fatalf("wanted %d orders, got %d", len(wantOIDs), len(oids))
+	}
+	for i := range wantOIDs {
+		if oids[i] != wantOIDs[i] || statuses[i] != wantStatuses[i] {
+			t.Fatalf("order %d: wanted %v (%v), got %v (%v)", i, wantOIDs[i], wantStatuses[i], oids[i], statuses[i])
+		}
+	}
+
+	// The canceled order's account has the trade order and the cancel order.
+	acct = canceled.User()
+	filter.Account = &acct
+	if oids, _ = orderHistory(filter); len(oids) != 2 || oids[0] != canceled.ID() || oids[1] != co.ID() {
+		t.Fatalf("wrong account orders: %v", oids)
+	}
+
+	// Excluding the cancel order's server time.
+	filter.End = co.ServerTime
+	if oids, _ = orderHistory(filter); len(oids) != 1 {
+		t.Fatalf("wrong number of account orders: %d", len(oids))
+	}
+}
@@ -0,0 +1,81 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// CreateFeeKeysTable creates the fee_keys table, which is a small table that
+	// is used as a persistent child key counter for master extended public key.
+	CreateFeeKeysTable = `CREATE TABLE IF NOT EXISTS %s (
+		key_hash BLOB PRIMARY KEY,
+		child INTEGER DEFAULT 0
+	);`
+
+	// CreateAccountsTable creates the account table.
+	CreateAccountsTable = `CREATE TABLE IF NOT EXISTS %s (
+		account_id BLOB PRIMARY KEY,
+		pubkey BLOB,
+		created INTEGER               -- unix ms
+	);`
+
+	CreateBondsTable = `CREATE TABLE IF NOT EXISTS %s (
+		version INTEGER,
+		bond_coin_id BLOB,
+		asset_id INTEGER,
+		account_id BLOB,
+		amount INTEGER, -- informative, strength is what matters
+		strength INTEGER,
+		lock_time INTEGER,
+		PRIMARY KEY (bond_coin_id, asset_id)
+	);`
+
+	CreateBondsAcctIndex     = `CREATE INDEX IF NOT EXISTS %s ON %s (account_id);`
+	CreateBondsLockTimeIndex = `CREATE INDEX IF NOT EXISTS %s ON %s (lock_time);`
+
+	AddBond = `INSERT INTO %s (version, bond_coin_id, asset_id, account_id, amount, strength, lock_time)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7);`
+
+	DeleteBond = `DELETE FROM %s WHERE bond_coin_id = ?1 AND asset_id = ?2;`
+
+	SelectActiveBondsForUser = `SELECT version, bond_coin_id, asset_id, amount, strength, lock_time FROM %s
+		WHERE account_id = ?1 AND lock_time >= ?2
+		ORDER BY lock_time;`
+
+	// InsertKeyIfMissing creates an entry for the specified key hash, if it
+	// doesn't already exist.
+	InsertKeyIfMissing = `INSERT INTO %s (key_hash)
+		VALUES (?1)
+		ON CONFLICT (key_hash) DO NOTHING
+		RETURNING child;`
+
+	CurrentKeyIndex = `SELECT child FROM %s WHERE key_hash = ?1;`
+
+	UpsertKeyIndex = `INSERT INTO %s (child, key_hash)
+		VALUES (?1, ?2)
+		ON CONFLICT (key_hash) DO UPDATE
+		SET child = ?1;`
+
+	// SelectAccount gathers account details for the specified account ID.
+	SelectAccount = `SELECT pubkey FROM %s WHERE account_id = ?1;`
+
+	// SelectAccountInfo retrieves all fields for an account.
+	SelectAccountInfo = `SELECT account_id, pubkey FROM %s WHERE account_id = ?1;`
+
+	CreateAccountForBond = `INSERT INTO %s (account_id, pubkey, created) VALUES (?1, ?2, ?3);`
+
+	// SelectNewAccountCount counts the accounts created in a time range.
+	SelectNewAccountCount = `SELECT COUNT(*) FROM %s WHERE created >= ?1 AND created < ?2;`
+
+	CreatePrepaidBondsTable = `CREATE TABLE IF NOT EXISTS %s (
+		coin_id BLOB PRIMARY KEY,
+		version INTEGER DEFAULT 0,
+		strength INTEGER,
+		lock_time INTEGER
+	);`
+
+	SelectPrepaidBond = `SELECT strength, lock_time FROM %s WHERE coin_id = ?1;`
+
+	DeletePrepaidBond = `DELETE FROM %s WHERE coin_id = ?1;`
+
+	InsertPrepaidBond = `INSERT INTO %s (coin_id, strength, lock_time) VALUES (?1, ?2, ?3);`
+)
@@ -0,0 +1,23 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// CreateBanScoresTable creates the ban_scores table, which holds the
+	// decaying violation score and any suspension or ban of each account.
+	CreateBanScoresTable = `CREATE TABLE IF NOT EXISTS %s (
+		account_id BLOB PRIMARY KEY,
+		score REAL,
+		stamp INTEGER,          -- unix ms
+		suspended_until INTEGER, -- unix ms, 0 if never suspended
+		banned INTEGER
+	);`
+
+	SelectBanScore = `SELECT score, stamp, suspended_until, banned FROM %s WHERE account_id = ?1;`
+
+	UpsertBanScore = `INSERT INTO %s (account_id, score, stamp, suspended_until, banned)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		ON CONFLICT (account_id) DO UPDATE
+		SET score = ?2, stamp = ?3, suspended_until = ?4, banned = ?5;`
+)
@@ -0,0 +1,108 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// CreateEpochsTable creates a table specified via the %s printf specifier
+	// for epoch data.
+	CreateEpochsTable = `CREATE TABLE IF NOT EXISTS %s (
+		epoch_idx INTEGER,
+		epoch_dur INTEGER,    -- epoch duration in milliseconds
+		match_time INTEGER,   -- time at which matching and book/unbooks began
+		csum BLOB,            -- commitment checksum
+		seed BLOB,            -- preimage-derived shuffle seed
+		revealed BLOB,        -- concatenated order IDs with revealed preimages, in shuffled order
+		missed BLOB,          -- concatenated IDs of orders with no preimage
+		preimages BLOB,       -- concatenated preimages of the revealed orders
+		missed_commits BLOB,  -- concatenated commitments of the missed orders
+		PRIMARY KEY(epoch_idx, epoch_dur)
+	);`
+
+	// InsertEpoch inserts the epoch's match proof data into the epoch table.
+	InsertEpoch = `INSERT INTO %s (epoch_idx, epoch_dur, match_time, csum, seed, revealed, missed,
+			preimages, missed_commits)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9);`
+
+	// SelectEpochProof retrieves the match proof data for an epoch.
+	SelectEpochProof = `SELECT match_time, csum, seed, revealed, preimages, missed, missed_commits
+		FROM %s
+		WHERE epoch_idx = ?1 AND epoch_dur = ?2;`
+
+	SelectLastEpochRate = `SELECT end_rate
+		FROM %s
+		ORDER BY epoch_end DESC
+		LIMIT 1;`
+
+	// CreateEpochReportTable creates an epoch_reports table that holds
+	// epoch-end reports that can be used to construct market history data sets.
+	CreateEpochReportTable = `CREATE TABLE IF NOT EXISTS %s (
+		epoch_end INTEGER PRIMARY KEY,
+		epoch_dur INTEGER,          -- epoch duration in milliseconds
+		match_volume INTEGER,       -- total matched during epoch's match cycle
+		quote_volume INTEGER,       -- total matched volume in terms of quote asset
+		book_buys INTEGER,          -- booked buy volume
+		book_buys_5 INTEGER,        -- booked buy volume within 5 pct of market
+		book_buys_25 INTEGER,       -- booked buy volume within 25 pct of market
+		book_sells INTEGER,         -- booked sell volume
+		book_sells_5 INTEGER,       -- booked sell volume within 5 pct of market
+		book_sells_25 INTEGER,      -- booked sell volume within 25 pct of market
+		high_rate INTEGER,          -- the highest rate matched
+		low_rate INTEGER,           -- the lowest rate matched
+		start_rate INTEGER,         -- the rate of the first match in the epoch
+		end_rate INTEGER            -- the rate of the last match in the epoch
+	);`
+
+	// InsertEpochReport inserts a row into the epoch_reports table.
+	InsertEpochReport = `INSERT INTO %s (epoch_end, epoch_dur, match_volume, quote_volume,
+			book_buys, book_buys_5, book_buys_25, book_sells, book_sells_5, book_sells_25,
+			high_rate, low_rate, start_rate, end_rate)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14);`
+
+	// SelectEpochCandles selects all rows from the epoch_reports table sorted
+	// by ascending time.
+	SelectEpochCandles = `SELECT epoch_end, epoch_dur, match_volume, quote_volume,
+			high_rate, low_rate, start_rate, end_rate
+		FROM %s
+		WHERE epoch_end >= ?1
+		ORDER BY epoch_end;`
+
+	// CreateCandlesTable creates a candles table that holds binned candle
+	// data.
+	CreateCandlesTable = `CREATE TABLE IF NOT EXISTS %s (
+		end_stamp INTEGER PRIMARY KEY,
+		match_volume INTEGER,
+		quote_volume INTEGER,
+		high_rate INTEGER,
+		low_rate INTEGER,
+		start_rate INTEGER,
+		end_rate INTEGER
+	);`
+
+	InsertCandle = `INSERT INTO %s (
+		end_stamp, match_volume, quote_volume, high_rate, low_rate, start_rate, end_rate
+	)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+	ON CONFLICT (end_stamp) DO UPDATE
+	SET match_volume = ?2, quote_volume = ?3, high_rate = ?4, low_rate = ?5, start_rate = ?6, end_rate = ?7;`
+
+	SelectCandles = `SELECT end_stamp, match_volume, quote_volume,
+		high_rate, low_rate, start_rate, end_rate
+	FROM %s
+	ORDER BY end_stamp
+	LIMIT ?1;`
+
+	// SelectCandleRange selects up to a limited number of candles that ended
+	// in a time range, oldest first.
+	SelectCandleRange = `SELECT end_stamp, match_volume, quote_volume,
+		high_rate, low_rate, start_rate, end_rate
+	FROM %s
+	WHERE end_stamp > ?1 AND end_stamp <= ?2
+	ORDER BY end_stamp
+	LIMIT ?3;`
+
+	SelectLastEndStamp = `SELECT end_stamp
+		FROM %s
+		ORDER BY end_stamp DESC
+		LIMIT 1;`
+)
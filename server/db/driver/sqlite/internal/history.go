@@ -0,0 +1,51 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// SelectMatchHistory retrieves the trade matches from epochs that
+	// started in the time range [?1, ?2), in milliseconds, oldest first. If
+	// ?3 is not NULL, only the matches of that account are selected.
+	SelectMatchHistory = `SELECT matchid, active, takerSell,
+		takerOrder, takerAccount, takerAddress,
+		makerOrder, makerAccount, makerAddress,
+		epochIdx, epochDur, quantity, rate, baseRate, quoteRate, status,
+		aContractCoinID, bContractCoinID, aRedeemCoinID, bRedeemCoinID
+	FROM %s
+	WHERE takerSell IS NOT NULL -- not a cancel order
+		AND epochIdx * epochDur >= ?1 AND epochIdx * epochDur < ?2
+		AND (?3 IS NULL OR takerAccount = ?3 OR makerAccount = ?3)
+	ORDER BY epochIdx * epochDur, matchid;`
+
+	// SelectOrderHistory retrieves the trade orders from both the active and
+	// archived orders tables received in the time range [?1, ?2), oldest
+	// first. If ?3 is not NULL, only the orders of that account are selected.
+	SelectOrderHistory = `SELECT oid, type, sell, account_id, address, client_time, server_time,
+		commitment, coins, quantity, rate, force, status, filled
+	FROM %[1]s
+	WHERE server_time >= ?1 AND server_time < ?2
+		AND (?3 IS NULL OR account_id = ?3)
+	UNION ALL
+	SELECT oid, type, sell, account_id, address, client_time, server_time,
+		commitment, coins, quantity, rate, force, status, filled
+	FROM %[2]s
+	WHERE server_time >= ?1 AND server_time < ?2
+		AND (?3 IS NULL OR account_id = ?3)
+	ORDER BY server_time, oid;`
+
+	// SelectCancelOrderHistory is like SelectOrderHistory, but for the
+	// active and archived cancel orders tables.
+	SelectCancelOrderHistory = `SELECT oid, account_id, client_time, server_time,
+		commitment, target_order, status
+	FROM %[1]s
+	WHERE server_time >= ?1 AND server_time < ?2
+		AND (?3 IS NULL OR account_id = ?3)
+	UNION ALL
+	SELECT oid, account_id, client_time, server_time,
+		commitment, target_order, status
+	FROM %[2]s
+	WHERE server_time >= ?1 AND server_time < ?2
+		AND (?3 IS NULL OR account_id = ?3)
+	ORDER BY server_time, oid;`
+)
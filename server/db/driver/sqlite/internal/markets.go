@@ -0,0 +1,26 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// CreateMarketsTable creates the DEX's "markets" table, which indicates
+	// which markets are currently recognized by the DEX, and their configured
+	// lot sizes.
+	CreateMarketsTable = `CREATE TABLE IF NOT EXISTS %s (
+		name TEXT PRIMARY KEY,
+		base INTEGER,
+		quote INTEGER,
+		lot_size INTEGER
+	);`
+
+	// SelectAllMarkets retrieves the active market information.
+	SelectAllMarkets = `SELECT name, base, quote, lot_size FROM %s;`
+
+	// InsertMarket inserts a new market in to the markets tables
+	InsertMarket = `INSERT INTO %s (name, base, quote, lot_size)
+		VALUES (?1, ?2, ?3, ?4);`
+
+	// UpdateLotSize updates the market's lot size.
+	UpdateLotSize = `UPDATE %s SET lot_size = ?2 WHERE name = ?1;`
+)
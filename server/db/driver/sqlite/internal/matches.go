@@ -0,0 +1,255 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// CreateMatchesTable creates the matches table for storing data related to
+	// a match and the related swap. Cancel order matches are stored with a
+	// NULL takerSell. See the pg driver for a description of the asset of the
+	// address and coin ID columns for each party.
+	CreateMatchesTable = `CREATE TABLE IF NOT EXISTS %s (
+		matchid BLOB PRIMARY KEY,
+		active BOOL DEFAULT TRUE, -- negotiation active, where FALSE includes failure, successful completion, or a taker cancel order
+		takerSell BOOL,           -- to identify asset of address and coinIDs, NULL for cancel orders
+		takerOrder BLOB,
+		takerAccount BLOB,
+		takerAddress TEXT,        -- NULL for cancel orders
+		makerOrder BLOB,
+		makerAccount BLOB,
+		makerAddress TEXT,        -- NULL for cancel orders
+		epochIdx INTEGER,
+		epochDur INTEGER,
+		quantity INTEGER,
+		rate INTEGER,
+		baseRate INTEGER, quoteRate INTEGER, -- contract tx fee rates, NULL for cancel orders
+		status INTEGER,           -- also updated during swap negotiation, independent from active for failed swaps
+		forgiven BOOL,
+		aSwapConf INTEGER, bSwapConf INTEGER, -- confirmations required for the contracts, NULL for cancel orders
+
+		-- The remaining columns are only set during swap negotiation.
+		sigMatchAckMaker BLOB,
+		sigMatchAckTaker BLOB,
+
+		-- initiator/A (maker) CONTRACT data
+		aContractCoinID BLOB,
+		aContract BLOB,
+		aContractTime INTEGER,
+		bSigAckOfAContract BLOB,
+
+		-- participant/B (taker) CONTRACT data
+		bContractCoinID BLOB,
+		bContract BLOB,
+		bContractTime INTEGER,
+		aSigAckOfBContract BLOB,
+
+		-- initiator/A (maker) REDEEM data
+		aRedeemCoinID BLOB,
+		aRedeemSecret BLOB,
+		aRedeemTime INTEGER,
+		bSigAckOfARedeem BLOB,
+
+		-- participant/B (taker) REDEEM data
+		bRedeemCoinID BLOB,
+		bRedeemTime INTEGER
+	);`
+
+	// CreateMatchesTakerIndex and CreateMatchesMakerIndex index a matches
+	// table on the account columns. The index name and table name are the %s
+	// specifiers.
+	CreateMatchesTakerIndex = `CREATE INDEX IF NOT EXISTS %s ON %s (takerAccount);`
+	CreateMatchesMakerIndex = `CREATE INDEX IF NOT EXISTS %s ON %s (makerAccount);`
+
+	RetrieveSwapData = `SELECT status, sigMatchAckMaker, sigMatchAckTaker,
+		aContractCoinID, aContract, aContractTime, bSigAckOfAContract,
+		bContractCoinID, bContract, bContractTime, aSigAckOfBContract,
+		aRedeemCoinID, aRedeemSecret, aRedeemTime, bSigAckOfARedeem,
+		bRedeemCoinID, bRedeemTime,
+		aSwapConf, bSwapConf
+	FROM %s WHERE matchid = ?1;`
+
+	UpsertMatch = `INSERT INTO %s (matchid, takerSell,
+		takerOrder, takerAccount, takerAddress,
+		makerOrder, makerAccount, makerAddress,
+		epochIdx, epochDur,
+		quantity, rate, baseRate, quoteRate, status)
+	VALUES (?1, ?2,
+		?3, ?4, ?5,
+		?6, ?7, ?8,
+		?9, ?10,
+		?11, ?12, ?13, ?14, ?15)
+	ON CONFLICT (matchid) DO
+	UPDATE SET quantity = ?11, status = ?15;`
+
+	UpsertCancelMatch = `INSERT INTO %s (matchid, active, -- omit takerSell
+			takerOrder, takerAccount, -- no taker address for a cancel order
+			makerOrder, makerAccount, -- omit maker's swap address too
+			epochIdx, epochDur,
+			quantity, rate, status) -- omit base and quote fee rates
+		VALUES (?1, FALSE, -- no active swap for a cancel
+			?2, ?3,
+			?4, ?5,
+			?6, ?7,
+			?8, ?9, ?10)
+		ON CONFLICT (matchid) DO NOTHING;`
+
+	RetrieveMatchByID = `SELECT matchid, active, takerSell,
+		takerOrder, takerAccount, takerAddress,
+		makerOrder, makerAccount, makerAddress,
+		epochIdx, epochDur, quantity, rate, baseRate, quoteRate, status
+	FROM %s WHERE matchid = ?1;`
+
+	RetrieveUserMatches = `SELECT matchid, active, takerSell,
+		takerOrder, takerAccount, takerAddress,
+		makerOrder, makerAccount, makerAddress,
+		epochIdx, epochDur, quantity, rate, baseRate, quoteRate, status
+	FROM %s
+	WHERE takerAccount = ?1 OR makerAccount = ?1;`
+
+	RetrieveActiveUserMatches = `SELECT matchid, takerSell,
+		takerOrder, takerAccount, takerAddress,
+		makerOrder, makerAccount, makerAddress,
+		epochIdx, epochDur, quantity, rate, baseRate, quoteRate, status
+	FROM %s
+	WHERE (takerAccount = ?1 OR makerAccount = ?1)
+		AND active;`
+
+	// SelectMatchesInRange retrieves the quantity, rate, and negotiation state
+	// of the trade matches made in a time range. The quote volume is summed by
+	// the caller since the product of quantity and rate may overflow a 64-bit
+	// integer.
+	SelectMatchesInRange = `SELECT quantity, rate, active, status
+	FROM %s
+	WHERE takerSell IS NOT NULL -- not a cancel order
+		AND epochIdx * epochDur >= ?1 AND epochIdx * epochDur < ?2;`
+
+	RetrieveMarketMatches = `SELECT matchid, active, takerSell,
+		takerOrder, takerAccount, takerAddress,
+		makerOrder, makerAccount, makerAddress,
+		epochIdx, epochDur, quantity, rate, baseRate, quoteRate, status,
+		aContractCoinID, bContractCoinID, aRedeemCoinID, bRedeemCoinID
+	FROM %s
+	WHERE takerSell IS NOT NULL -- not a cancel order
+	ORDER BY epochIdx * epochDur DESC
+	LIMIT ?1;`
+
+	RetrieveActiveMarketMatches = `SELECT matchid, takerSell,
+		takerOrder, takerAccount, takerAddress,
+		makerOrder, makerAccount, makerAddress,
+		epochIdx, epochDur, quantity, rate, baseRate, quoteRate, status,
+		aContractCoinID, bContractCoinID, aRedeemCoinID, bRedeemCoinID
+	FROM %s
+	WHERE takerSell IS NOT NULL -- not a cancel order
+		AND active
+	ORDER BY epochIdx * epochDur DESC;`
+
+	// RetrieveActiveMarketMatchesExtended combines RetrieveSwapData with
+	// RetrieveActiveMarketMatches.
+	RetrieveActiveMarketMatchesExtended = `SELECT matchid, takerSell,
+		takerOrder, takerAccount, takerAddress,
+		makerOrder, makerAccount, makerAddress,
+		epochIdx, epochDur, quantity, rate, baseRate, quoteRate, status,
+		sigMatchAckMaker, sigMatchAckTaker,
+		aContractCoinID, aContract, aContractTime, bSigAckOfAContract,
+		bContractCoinID, bContract, bContractTime, aSigAckOfBContract,
+		aRedeemCoinID, aRedeemSecret, aRedeemTime, bSigAckOfARedeem,
+		bRedeemCoinID, bRedeemTime,
+		aSwapConf, bSwapConf
+	FROM %s
+	WHERE takerSell IS NOT NULL -- not a cancel order
+		AND active
+	ORDER BY epochIdx * epochDur DESC;`
+
+	// CompletedOrAtFaultMatchesLastN retrieves inactive matches for a user that
+	// are either successfully completed by the user (MatchComplete or
+	// MakerRedeemed with user as maker), or failed because of this user's
+	// inaction. The multi-argument MAX is NULL if any argument is NULL, unlike
+	// PostgreSQL's GREATEST, hence the COALESCEs. Note that the literal status
+	// values used in this query MUST BE UPDATED if the order.MatchStatus enum
+	// is changed.
+	CompletedOrAtFaultMatchesLastN = `SELECT matchid, status, quantity,
+			(status=4 OR (status=3 AND makerAccount = ?1 AND takerAccount != ?1)) AS success,
+			MAX((epochIdx+1)*epochDur, COALESCE(aContractTime, 0), COALESCE(bContractTime, 0),
+				COALESCE(aRedeemTime, 0), COALESCE(bRedeemTime, 0)) AS lastTime
+		FROM %s
+		WHERE takerSell IS NOT NULL      -- exclude cancel order matches
+			AND (makerAccount = ?1 OR takerAccount = ?1)
+			AND (
+				-- swap success for both
+				status=4
+				OR
+				-- swap success for maker unless maker==taker
+				(status=3 AND makerAccount = ?1 AND takerAccount != ?1)
+				OR
+				( -- at-fault swap failures
+					NOT active -- failure means inactive/revoked
+					AND (forgiven IS NULL OR NOT forgiven)
+					AND (
+						(status=0 AND makerAccount = ?1) OR   -- fault for maker
+						(status=1 AND takerAccount = ?1) OR   -- fault for taker
+						(status=2 AND makerAccount = ?1) OR   -- fault for maker
+						(status=3 AND takerAccount = ?1)      -- fault for taker
+					)
+				)
+			)
+		ORDER BY lastTime DESC
+		LIMIT ?2;`
+
+	UserMatchFails = `SELECT matchid, status
+		FROM %s
+		WHERE takerSell IS NOT NULL      -- exclude cancel order matches
+			AND (makerAccount = ?1 OR takerAccount = ?1)
+			AND NOT active -- failure means inactive/revoked
+			AND (forgiven IS NULL OR NOT forgiven)
+			AND (
+				(status=0 AND makerAccount = ?1) OR   -- fault for maker
+				(status=1 AND takerAccount = ?1) OR   -- fault for taker
+				(status=2 AND makerAccount = ?1) OR   -- fault for maker
+				(status=3 AND takerAccount = ?1)      -- fault for taker
+			)
+		ORDER BY MAX((epochIdx+1)*epochDur, COALESCE(aContractTime, 0), COALESCE(bContractTime, 0),
+			COALESCE(aRedeemTime, 0), COALESCE(bRedeemTime, 0)) DESC
+		LIMIT ?2;`
+
+	ForgiveMatchFail = `UPDATE %s SET forgiven = TRUE
+		WHERE matchid = ?1 AND NOT active;`
+
+	SetSwapConfs = `UPDATE %s SET aSwapConf = ?2, bSwapConf = ?3 WHERE matchid = ?1;`
+
+	SetMakerMatchAckSig = `UPDATE %s SET sigMatchAckMaker = ?2 WHERE matchid = ?1;`
+	SetTakerMatchAckSig = `UPDATE %s SET sigMatchAckTaker = ?2 WHERE matchid = ?1;`
+
+	SetInitiatorSwapData = `UPDATE %s SET status = ?2,
+		aContractCoinID = ?3, aContract = ?4, aContractTime = ?5
+	WHERE matchid = ?1;`
+	SetParticipantSwapData = `UPDATE %s SET status = ?2,
+		bContractCoinID = ?3, bContract = ?4, bContractTime = ?5
+	WHERE matchid = ?1;`
+
+	SetParticipantContractAuditSig = `UPDATE %s SET bSigAckOfAContract = ?2 WHERE matchid = ?1;`
+	SetInitiatorContractAuditSig   = `UPDATE %s SET aSigAckOfBContract = ?2 WHERE matchid = ?1;`
+
+	SetInitiatorRedeemData = `UPDATE %s SET status = ?2,
+		aRedeemCoinID = ?3, aRedeemSecret = ?4, aRedeemTime = ?5
+	WHERE matchid = ?1;`
+	SetParticipantRedeemData = `UPDATE %s SET status = ?2,
+		bRedeemCoinID = ?3, bRedeemTime = ?4, active = FALSE
+	WHERE matchid = ?1;`
+
+	SetParticipantRedeemAckSig = `UPDATE %s SET bSigAckOfARedeem = ?2 WHERE matchid = ?1;`
+
+	SetSwapDone = `UPDATE %s SET active = FALSE  -- leave forgiven NULL
+		WHERE matchid = ?1;`
+
+	SetSwapDoneForgiven = `UPDATE %s SET active = FALSE, forgiven = TRUE
+		WHERE matchid = ?1;`
+
+	// SelectMatchStatuses retrieves the statuses of the matches with the
+	// given IDs. The first %s specifier is the table and the second is the
+	// list of match ID parameters, starting at ?2.
+	SelectMatchStatuses = `SELECT takerSell, (takerAccount = ?1) AS isTaker, (makerAccount = ?1) AS isMaker, matchid, status, aContract, bContract, aContractCoinID,
+		bContractCoinID, aRedeemCoinID, bRedeemCoinID, aRedeemSecret, active
+		FROM %s
+		WHERE matchid IN (%s)
+		AND (takerAccount = ?1 OR makerAccount = ?1);`
+)
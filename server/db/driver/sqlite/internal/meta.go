@@ -0,0 +1,18 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// CreateMetaTable creates a table to hold DEX metadata.
+	CreateMetaTable = `CREATE TABLE IF NOT EXISTS %s (
+		schema_version INTEGER DEFAULT 0
+	);`
+
+	// CreateMetaRow creates the single row of the meta table.
+	CreateMetaRow = "INSERT INTO meta DEFAULT VALUES;"
+
+	SelectDBVersion = `SELECT schema_version FROM meta;`
+
+	SetDBVersion = `UPDATE meta SET schema_version = ?1;`
+)
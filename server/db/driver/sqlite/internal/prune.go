@@ -0,0 +1,63 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// DeleteInactiveMatchesBefore deletes inactive matches from epochs that
+	// started before a time, in milliseconds, except for the ?2 most recent
+	// matches of each maker and taker account, which are used to score the
+	// accounts. The matches are ranked by their last action time, as in
+	// CompletedOrAtFaultMatchesLastN.
+	DeleteInactiveMatchesBefore = `WITH kept AS (
+			SELECT matchid FROM (
+				SELECT matchid,
+					ROW_NUMBER() OVER (PARTITION BY makerAccount ORDER BY lastTime DESC) AS makerRank,
+					ROW_NUMBER() OVER (PARTITION BY takerAccount ORDER BY lastTime DESC) AS takerRank
+				FROM (
+					SELECT matchid, makerAccount, takerAccount,
+						MAX((epochIdx+1)*epochDur, COALESCE(aContractTime, 0), COALESCE(bContractTime, 0),
+							COALESCE(aRedeemTime, 0), COALESCE(bRedeemTime, 0)) AS lastTime
+					FROM %[1]s
+				) AS timed
+			) AS ranked
+			WHERE makerRank <= ?2 OR takerRank <= ?2
+		)
+		DELETE FROM %[1]s
+		WHERE NOT active AND epochIdx * epochDur < ?1
+			AND matchid NOT IN (SELECT matchid FROM kept);`
+
+	// DeleteCancelOrdersBefore deletes cancel orders received before a time,
+	// except for the ?2 most recent cancel orders of each account, which are
+	// used to score the accounts. Use with the archived cancels table.
+	DeleteCancelOrdersBefore = `WITH kept AS (
+			SELECT oid FROM (
+				SELECT oid, ROW_NUMBER() OVER (PARTITION BY account_id ORDER BY server_time DESC) AS n
+				FROM %[1]s
+			) AS ranked
+			WHERE n <= ?2
+		)
+		DELETE FROM %[1]s
+		WHERE server_time < ?1
+			AND oid NOT IN (SELECT oid FROM kept);`
+
+	// DeleteEpochsBefore deletes epochs that started before a time, in
+	// milliseconds, except for the epochs of active matches and of the
+	// remaining cancel orders, whose match times are read from the epochs
+	// table. The second %s is the matches table, and the third is the archived
+	// cancels table.
+	DeleteEpochsBefore = `DELETE FROM %s AS e
+		WHERE epoch_idx * epoch_dur < ?1
+			AND NOT EXISTS (
+				SELECT 1 FROM %s
+				WHERE active AND epochIdx = e.epoch_idx AND epochDur = e.epoch_dur
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM %s AS c
+				WHERE c.epoch_idx = e.epoch_idx AND c.epoch_dur = e.epoch_dur
+			);`
+
+	// DeleteEpochReportsBefore deletes epoch reports for epochs that ended
+	// before a time, in milliseconds.
+	DeleteEpochReportsBefore = `DELETE FROM %s WHERE epoch_end < ?1;`
+)
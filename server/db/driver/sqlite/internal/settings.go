@@ -0,0 +1,47 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// CreateAuthSettingsTable creates the auth_settings table, which holds a
+	// single row of the account conduct settings last set by the operator.
+	CreateAuthSettingsTable = `CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1), -- single row
+		cancel_thresh REAL,
+		free_cancels INTEGER,
+		penalty_thresh INTEGER,
+		grace_limit INTEGER,                            -- NULL to derive from cancel_thresh
+		suspension_period INTEGER,                      -- ms
+		ban_half_life INTEGER,                          -- ms
+		stamp INTEGER                                   -- unix ms
+	);`
+
+	SelectAuthSettings = `SELECT cancel_thresh, free_cancels, penalty_thresh, grace_limit,
+		suspension_period, ban_half_life, stamp FROM %s;`
+
+	UpsertAuthSettings = `INSERT INTO %s (id, cancel_thresh, free_cancels, penalty_thresh, grace_limit,
+			suspension_period, ban_half_life, stamp)
+		VALUES (1, ?1, ?2, ?3, ?4, ?5, ?6, ?7)
+		ON CONFLICT (id) DO UPDATE
+		SET cancel_thresh = ?1, free_cancels = ?2, penalty_thresh = ?3, grace_limit = ?4,
+			suspension_period = ?5, ban_half_life = ?6, stamp = ?7;`
+
+	// CreateMarketSwapConfsTable creates the market_swap_confs table, which
+	// holds the per-market swapConf overrides set by the operator.
+	CreateMarketSwapConfsTable = `CREATE TABLE IF NOT EXISTS %s (
+		market TEXT,
+		asset_id INTEGER,
+		swap_conf INTEGER,
+		PRIMARY KEY (market, asset_id)
+	);`
+
+	SelectMarketSwapConfs = `SELECT market, asset_id, swap_conf FROM %s;`
+
+	UpsertMarketSwapConf = `INSERT INTO %s (market, asset_id, swap_conf)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (market, asset_id) DO UPDATE
+		SET swap_conf = ?3;`
+
+	DeleteMarketSwapConf = `DELETE FROM %s WHERE market = ?1 AND asset_id = ?2;`
+)
@@ -0,0 +1,12 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// TableExists checks if a table with the given name exists.
+	TableExists = `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?1;`
+
+	// RetrieveSQLiteVersion retrieves the version of the SQLite library.
+	RetrieveSQLiteVersion = `SELECT sqlite_version();`
+)
@@ -0,0 +1,40 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package internal
+
+const (
+	// CreateTradingFeesTable creates a table specified via the %s printf
+	// specifier for the trading fees accrued by each party of a match.
+	CreateTradingFeesTable = `CREATE TABLE IF NOT EXISTS %s (
+		matchid BLOB,
+		account BLOB,
+		maker BOOL,
+		asset_id INTEGER, -- the asset received by the account, in which the fee is denominated
+		amount INTEGER,
+		stamp INTEGER,    -- match time, unix ms
+		PRIMARY KEY(matchid, maker)
+	);`
+
+	// InsertTradingFee inserts the fee accrued by one party of a match. The fee
+	// is not modified if it is already recorded.
+	InsertTradingFee = `INSERT INTO %s (matchid, account, maker, asset_id, amount, stamp)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		ON CONFLICT (matchid, maker) DO NOTHING;`
+
+	// SelectTradingFeeTotals sums the accrued fees by asset.
+	SelectTradingFeeTotals = `SELECT asset_id, SUM(amount) FROM %s
+		GROUP BY asset_id;`
+
+	// SelectTradingFeeTotalsInRange sums the fees accrued for matches made in
+	// a time range by asset.
+	SelectTradingFeeTotalsInRange = `SELECT asset_id, SUM(amount) FROM %s
+		WHERE stamp >= ?1 AND stamp < ?2
+		GROUP BY asset_id;`
+
+	// SelectAccountTradingFeeTotals sums the fees accrued by an account by
+	// asset.
+	SelectAccountTradingFeeTotals = `SELECT asset_id, SUM(amount) FROM %s
+		WHERE account = ?1
+		GROUP BY asset_id;`
+)
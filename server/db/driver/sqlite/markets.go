@@ -0,0 +1,97 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/candles"
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+func loadMarkets(db sqlQueryer, marketsTableName string) ([]*dex.MarketInfo, error) {
+	stmt := fmt.Sprintf(internal.SelectAllMarkets, marketsTableName)
+	rows, err := db.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mkts []*dex.MarketInfo
+	for rows.Next() {
+		var name string
+		var base, quote uint32
+		var lotSize uint64
+		err = rows.Scan(&name, &base, &quote, &lotSize)
+		if err != nil {
+			return nil, err
+		}
+		mkts = append(mkts, &dex.MarketInfo{
+			Name:    name,
+			Base:    base,
+			Quote:   quote,
+			LotSize: lotSize,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mkts, nil
+}
+
+func newMarket(db *sql.DB, marketsTableName string, mkt *dex.MarketInfo) error {
+	stmt := fmt.Sprintf(internal.InsertMarket, marketsTableName)
+	N, err := sqlExec(db, stmt, mkt.Name, mkt.Base, mkt.Quote, mkt.LotSize)
+	if err != nil {
+		return err
+	}
+	if N != 1 {
+		return fmt.Errorf("failed to insert market, %d rows affected", N)
+	}
+	return nil
+}
+
+// createMarketTables creates any missing tables and indexes for the market.
+func createMarketTables(db *sql.DB, marketName string, newMarket bool) error {
+	marketUID := marketSchema(marketName)
+
+	for _, c := range createMarketTableStatements {
+		newTable, err := createTable(db, marketUID, c.name)
+		if err != nil {
+			return err
+		}
+		if newTable && !newMarket {
+			log.Warnf(`Created missing table "%s" for existing market %s.`,
+				c.name, marketUID)
+		}
+	}
+
+	for _, c := range createMarketIndexStatements {
+		err := createIndexStmt(db, c.stmt, fullTableName(marketUID, c.idxName),
+			fullTableName(marketUID, c.tableName))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create tables for the candles.
+	for _, binSize := range append(candles.BinSizes, "epoch") {
+		if _, err := createTableStmt(db, internal.CreateCandlesTable, marketUID, candlesTableName+"_"+binSize); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// marketSchema replaces the special token symbol character '.' in the market
+// name, giving the prefix for the market's tables.
+func marketSchema(marketName string) string {
+	return strings.ReplaceAll(marketName, ".", "TKN")
+}
@@ -0,0 +1,217 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestMatchSwap(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	maker := newLimitOrder(true, 4_900_000, 2, 0)
+	taker := newLimitOrder(false, 5_000_000, 1, 10)
+	bookOrder(t, archie, maker)
+	epochID := order.EpochID{Idx: 156649765, Dur: EpochDuration}
+	match := newMatch(maker, taker, LotSize, epochID)
+	if err := archie.InsertMatch(match); err != nil {
+		t.Fatalf("InsertMatch: %v", err)
+	}
+
+	md, err := archie.MatchByID(match.ID(), AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("MatchByID: %v", err)
+	}
+	if md.Maker != maker.ID() || md.Taker != taker.ID() || md.Quantity != LotSize ||
+		md.Rate != maker.Rate || !md.Active || md.Status != order.NewlyMatched || md.TakerSell {
+		t.Fatalf("wrong match data: %+v", md)
+	}
+
+	active, err := archie.AllActiveUserMatches(maker.User())
+	if err != nil {
+		t.Fatalf("AllActiveUserMatches: %v", err)
+	}
+	if len(active) != 1 || active[0].ID != match.ID() {
+		t.Fatalf("wrong active user matches: %v", active)
+	}
+
+	mid := db.MarketMatchID{MatchID: match.ID(), Base: AssetDCR, Quote: AssetBTC}
+	if err = archie.SaveSwapConfs(mid, 2, 3); err != nil {
+		t.Fatalf("SaveSwapConfs: %v", err)
+	}
+	swaps, err := archie.ActiveSwaps()
+	if err != nil {
+		t.Fatalf("ActiveSwaps: %v", err)
+	}
+	if len(swaps) != 1 || swaps[0].SwapConfA != 2 || swaps[0].SwapConfB != 3 {
+		t.Fatalf("wrong active swaps: %+v", swaps)
+	}
+
+	contractA, contractB := randomBytes(80), randomBytes(80)
+	coinA, coinB := randomBytes(36), randomBytes(36)
+	secret := randomBytes(32)
+	steps := []struct {
+		name string
+		f    func() error
+	}{
+		{"SaveMatchAckSigA", func() error { return archie.SaveMatchAckSigA(mid, randomBytes(72)) }},
+		{"SaveMatchAckSigB", func() error { return archie.SaveMatchAckSigB(mid, randomBytes(72)) }},
+		{"SaveContractA", func() error { return archie.SaveContractA(mid, contractA, coinA, 1000) }},
+		{"SaveAuditAckSigB", func() error { return archie.SaveAuditAckSigB(mid, randomBytes(72)) }},
+		{"SaveContractB", func() error { return archie.SaveContractB(mid, contractB, coinB, 2000) }},
+		{"SaveAuditAckSigA", func() error { return archie.SaveAuditAckSigA(mid, randomBytes(72)) }},
+		{"SaveRedeemA", func() error { return archie.SaveRedeemA(mid, randomBytes(36), secret, 3000) }},
+		{"SaveRedeemAckSigB", func() error { return archie.SaveRedeemAckSigB(mid, randomBytes(72)) }},
+		{"SaveRedeemB", func() error { return archie.SaveRedeemB(mid, randomBytes(36), 4000) }},
+	}
+	for _, step := range steps {
+		if err := step.f(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	status, swapData, err := archie.SwapData(mid)
+	if err != nil {
+		t.Fatalf("SwapData: %v", err)
+	}
+	if status != order.MatchComplete {
+		t.Fatalf("expected MatchComplete, got %v", status)
+	}
+	if !bytes.Equal(swapData.ContractA, contractA) || !bytes.Equal(swapData.ContractBCoinID, coinB) ||
+		!bytes.Equal(swapData.RedeemASecret, secret) || swapData.ContractATime != 1000 || swapData.RedeemBTime != 4000 ||
+		swapData.SwapConfA != 2 || swapData.SwapConfB != 3 {
+		t.Fatalf("wrong swap data: %+v", swapData)
+	}
+
+	statuses, err := archie.MatchStatuses(taker.User(), AssetDCR, AssetBTC, []order.MatchID{match.ID()})
+	if err != nil {
+		t.Fatalf("MatchStatuses: %v", err)
+	}
+	if len(statuses) != 1 || !statuses[0].IsTaker || statuses[0].IsMaker || statuses[0].Active ||
+		!bytes.Equal(statuses[0].Secret, secret) {
+		t.Fatalf("wrong match statuses: %+v", statuses)
+	}
+
+	if active, err = archie.AllActiveUserMatches(maker.User()); err != nil {
+		t.Fatalf("AllActiveUserMatches: %v", err)
+	}
+	if len(active) != 0 {
+		t.Fatalf("expected no active matches, got %d", len(active))
+	}
+
+	outcomes, err := archie.CompletedAndAtFaultMatchStats(taker.User(), 10)
+	if err != nil {
+		t.Fatalf("CompletedAndAtFaultMatchStats: %v", err)
+	}
+	if len(outcomes) != 1 || outcomes[0].Fail || outcomes[0].Status != order.MatchComplete {
+		t.Fatalf("wrong match outcomes: %+v", outcomes)
+	}
+
+	if swaps, err = archie.ActiveSwaps(); err != nil {
+		t.Fatalf("ActiveSwaps: %v", err)
+	}
+	if len(swaps) != 0 {
+		t.Fatalf("expected no active swaps, got %d", len(swaps))
+	}
+}
+
+func TestMatchFail(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	maker := newLimitOrder(true, 4_900_000, 1, 0)
+	taker := newLimitOrder(false, 5_000_000, 1, 10)
+	match := newMatch(maker, taker, LotSize, order.EpochID{Idx: 156649765, Dur: EpochDuration})
+	if err := archie.InsertMatch(match); err != nil {
+		t.Fatalf("InsertMatch: %v", err)
+	}
+	mid := db.MarketMatchID{MatchID: match.ID(), Base: AssetDCR, Quote: AssetBTC}
+	if err := archie.SetMatchInactive(mid, false); err != nil {
+		t.Fatalf("SetMatchInactive: %v", err)
+	}
+
+	// The maker failed to send their swap.
+	fails, err := archie.UserMatchFails(maker.User(), 10)
+	if err != nil {
+		t.Fatalf("UserMatchFails: %v", err)
+	}
+	if len(fails) != 1 || fails[0].ID != match.ID() {
+		t.Fatalf("wrong match fails: %+v", fails)
+	}
+	if fails, err = archie.UserMatchFails(taker.User(), 10); err != nil {
+		t.Fatalf("UserMatchFails: %v", err)
+	}
+	if len(fails) != 0 {
+		t.Fatalf("taker should have no match fails, got %d", len(fails))
+	}
+
+	forgiven, err := archie.ForgiveMatchFail(match.ID())
+	if err != nil {
+		t.Fatalf("ForgiveMatchFail: %v", err)
+	}
+	if !forgiven {
+		t.Fatalf("match not forgiven")
+	}
+	if fails, err = archie.UserMatchFails(maker.User(), 10); err != nil {
+		t.Fatalf("UserMatchFails: %v", err)
+	}
+	if len(fails) != 0 {
+		t.Fatalf("expected no match fails after forgiving, got %d", len(fails))
+	}
+
+	var n int
+	_, err = archie.MarketMatchesStreaming(AssetDCR, AssetBTC, true, -1, func(*db.MatchDataWithCoins) error {
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("MarketMatchesStreaming: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 match, got %d", n)
+	}
+
+	// Trading fees and market stats.
+	matchTime := int64(match.Epoch.Idx * match.Epoch.Dur)
+	fees := []*db.TradingFee{
+		{MatchID: match.ID(), Account: maker.User(), Maker: true, AssetID: AssetBTC, Amount: 100, Time: matchTime},
+		{MatchID: match.ID(), Account: taker.User(), AssetID: AssetDCR, Amount: 200, Time: matchTime},
+	}
+	if err = archie.InsertTradingFees(AssetDCR, AssetBTC, fees); err != nil {
+		t.Fatalf("InsertTradingFees: %v", err)
+	}
+	mktFees, err := archie.MarketTradingFees(AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("MarketTradingFees: %v", err)
+	}
+	if mktFees[AssetBTC] != 100 || mktFees[AssetDCR] != 200 {
+		t.Fatalf("wrong market trading fees: %v", mktFees)
+	}
+	acctFees, err := archie.AccountTradingFees(taker.User())
+	if err != nil {
+		t.Fatalf("AccountTradingFees: %v", err)
+	}
+	if len(acctFees) != 1 || acctFees[AssetDCR] != 200 {
+		t.Fatalf("wrong account trading fees: %v", acctFees)
+	}
+
+	stats, err := archie.MarketStats(AssetDCR, AssetBTC, time.UnixMilli(matchTime), time.UnixMilli(matchTime+1))
+	if err != nil {
+		t.Fatalf("MarketStats: %v", err)
+	}
+	if stats.Matches != 1 || stats.Volume != LotSize || stats.QuoteVolume != 490_000_000 ||
+		stats.FailedSwaps != 1 || stats.Fees[AssetDCR] != 200 {
+		t.Fatalf("wrong market stats: %+v", stats)
+	}
+	if stats, err = archie.MarketStats(AssetDCR, AssetBTC, time.UnixMilli(matchTime+1), time.Now()); err != nil {
+		t.Fatalf("MarketStats: %v", err)
+	}
+	if stats.Matches != 0 {
+		t.Fatalf("expected no matches after the match time, got %d", stats.Matches)
+	}
+}
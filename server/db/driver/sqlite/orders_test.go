@@ -0,0 +1,295 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"decred.org/dcrdex/dex/order"
+	ordertest "decred.org/dcrdex/dex/order/test"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestOrderLifecycle(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	lo := newLimitOrder(true, 4_800_000, 2, 0)
+	if err := archie.NewEpochOrder(lo, 100, int64(EpochDuration), db.EpochGapNA); err != nil {
+		t.Fatalf("NewEpochOrder: %v", err)
+	}
+	epochOrds, err := archie.EpochOrders(AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("EpochOrders: %v", err)
+	}
+	if len(epochOrds) != 1 {
+		t.Fatalf("expected 1 epoch order, got %d", len(epochOrds))
+	}
+	ordertest.MustCompareOrders(t, lo, epochOrds[0])
+
+	pi := randomPreimage()
+	if err = archie.StorePreimage(lo, pi); err != nil {
+		t.Fatalf("StorePreimage: %v", err)
+	}
+
+	lo.FillAmt = LotSize
+	if err = archie.BookOrder(lo); err != nil {
+		t.Fatalf("BookOrder: %v", err)
+	}
+	bookOrds, err := archie.BookOrders(AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("BookOrders: %v", err)
+	}
+	if len(bookOrds) != 1 {
+		t.Fatalf("expected 1 book order, got %d", len(bookOrds))
+	}
+	ordertest.MustCompareLimitOrders(t, lo, bookOrds[0])
+
+	baseCoins, quoteCoins, err := archie.ActiveOrderCoins(AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("ActiveOrderCoins: %v", err)
+	}
+	if len(baseCoins[lo.ID()]) != 2 || len(quoteCoins) != 0 {
+		t.Fatalf("wrong active order coins: %d base, %d quote", len(baseCoins[lo.ID()]), len(quoteCoins))
+	}
+
+	found, oid, err := archie.OrderWithCommit(context.Background(), lo.Commit)
+	if err != nil {
+		t.Fatalf("OrderWithCommit: %v", err)
+	}
+	if !found || oid != lo.ID() {
+		t.Fatalf("OrderWithCommit found = %v, oid = %v", found, oid)
+	}
+
+	statuses, err := archie.UserOrderStatuses(lo.User(), AssetDCR, AssetBTC,
+		[]order.OrderID{lo.ID(), ordertest.RandomOrderID()})
+	if err != nil {
+		t.Fatalf("UserOrderStatuses: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Status != order.OrderStatusBooked || statuses[0].ID != lo.ID() {
+		t.Fatalf("wrong user order statuses: %+v", statuses)
+	}
+
+	// Cancel it, moving it to the archived table.
+	co := newCancelOrder(lo.ID(), lo.User(), 10)
+	if err = archie.NewEpochOrder(co, 101, int64(EpochDuration), 1); err != nil {
+		t.Fatalf("NewEpochOrder (cancel): %v", err)
+	}
+	if err = archie.ExecuteOrder(co); err != nil {
+		t.Fatalf("ExecuteOrder (cancel): %v", err)
+	}
+	if err = archie.CancelOrder(lo); err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+	err = archie.InsertEpoch(&db.EpochResults{
+		MktBase:   AssetDCR,
+		MktQuote:  AssetBTC,
+		Idx:       101,
+		Dur:       int64(EpochDuration),
+		MatchTime: 1566497670000,
+		CSum:      randomBytes(32),
+		Seed:      randomBytes(32),
+	})
+	if err != nil {
+		t.Fatalf("InsertEpoch: %v", err)
+	}
+
+	ord, status, err := archie.Order(lo.ID(), AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("Order: %v", err)
+	}
+	if status != order.OrderStatusCanceled {
+		t.Fatalf("expected canceled status, got %v", status)
+	}
+	ordertest.MustCompareOrders(t, lo, ord)
+
+	_, status, err = archie.Order(co.ID(), AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("Order (cancel): %v", err)
+	}
+	if status != order.OrderStatusExecuted {
+		t.Fatalf("expected executed cancel order, got %v", status)
+	}
+
+	cancels, err := archie.ExecutedCancelsForUser(lo.User(), 10)
+	if err != nil {
+		t.Fatalf("ExecutedCancelsForUser: %v", err)
+	}
+	if len(cancels) != 1 || cancels[0].ID != co.ID() || cancels[0].TargetID != lo.ID() {
+		t.Fatalf("wrong executed cancels: %+v", cancels)
+	}
+
+	if bookOrds, err = archie.BookOrders(AssetDCR, AssetBTC); err != nil {
+		t.Fatalf("BookOrders: %v", err)
+	}
+	if len(bookOrds) != 0 {
+		t.Fatalf("expected no book orders, got %d", len(bookOrds))
+	}
+
+	pis, err := archie.PreimageStats(lo.User(), 10)
+	if err != nil {
+		t.Fatalf("PreimageStats: %v", err)
+	}
+	if len(pis) != 2 {
+		t.Fatalf("expected 2 preimage results, got %d", len(pis))
+	}
+}
+
+func TestRevokeOrder(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	lo := newLimitOrder(false, 4_900_000, 1, 0)
+	bookOrder(t, archie, lo)
+	cancelID, revokeTime, err := archie.RevokeOrder(lo)
+	if err != nil {
+		t.Fatalf("RevokeOrder: %v", err)
+	}
+	_, status, err := archie.Order(lo.ID(), AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("Order: %v", err)
+	}
+	if status != order.OrderStatusRevoked {
+		t.Fatalf("expected revoked status, got %v", status)
+	}
+
+	cancels, err := archie.ExecutedCancelsForUser(lo.User(), 10)
+	if err != nil {
+		t.Fatalf("ExecutedCancelsForUser: %v", err)
+	}
+	if len(cancels) != 1 || cancels[0].ID != cancelID || cancels[0].MatchTime != revokeTime.UnixMilli() {
+		t.Fatalf("wrong executed cancels: %+v", cancels)
+	}
+
+	// Uncounted revokes are not returned.
+	lo2 := newLimitOrder(false, 4_900_000, 1, 0)
+	lo2.AccountID = lo.User()
+	bookOrder(t, archie, lo2)
+	if _, _, err = archie.RevokeOrderUncounted(lo2); err != nil {
+		t.Fatalf("RevokeOrderUncounted: %v", err)
+	}
+	if cancels, err = archie.ExecutedCancelsForUser(lo.User(), 10); err != nil {
+		t.Fatalf("ExecutedCancelsForUser: %v", err)
+	}
+	if len(cancels) != 1 {
+		t.Fatalf("expected 1 executed cancel, got %d", len(cancels))
+	}
+}
+
+func TestFlushBook(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	sell := newLimitOrder(true, 5_000_000, 1, 0)
+	buy := newLimitOrder(false, 4_000_000, 1, 0)
+	epochOrd := newLimitOrder(false, 4_000_000, 1, 0)
+	bookOrder(t, archie, sell)
+	bookOrder(t, archie, buy)
+	if err := archie.NewEpochOrder(epochOrd, 101, int64(EpochDuration), db.EpochGapNA); err != nil {
+		t.Fatalf("NewEpochOrder: %v", err)
+	}
+
+	sellsRemoved, buysRemoved, err := archie.FlushBook(AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("FlushBook: %v", err)
+	}
+	if len(sellsRemoved) != 1 || sellsRemoved[0] != sell.ID() {
+		t.Fatalf("wrong sells removed: %v", sellsRemoved)
+	}
+	if len(buysRemoved) != 1 || buysRemoved[0] != buy.ID() {
+		t.Fatalf("wrong buys removed: %v", buysRemoved)
+	}
+
+	for _, lo := range []*order.LimitOrder{sell, buy} {
+		_, status, err := archie.Order(lo.ID(), AssetDCR, AssetBTC)
+		if err != nil {
+			t.Fatalf("Order: %v", err)
+		}
+		if status != order.OrderStatusRevoked {
+			t.Fatalf("expected revoked status, got %v", status)
+		}
+		// Like RevokeOrderUncounted, the flushed orders are not counted
+		// against the user.
+		cancels, err := archie.ExecutedCancelsForUser(lo.User(), 10)
+		if err != nil {
+			t.Fatalf("ExecutedCancelsForUser: %v", err)
+		}
+		if len(cancels) != 0 {
+			t.Fatalf("expected no executed cancels, got %d", len(cancels))
+		}
+	}
+
+	// The epoch order is untouched.
+	_, status, err := archie.Order(epochOrd.ID(), AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("Order: %v", err)
+	}
+	if status != order.OrderStatusEpoch {
+		t.Fatalf("expected epoch status, got %v", status)
+	}
+}
+
+func TestCompletedUserOrders(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	lo := newLimitOrder(false, 4_900_000, 1, 0)
+	lo.FillAmt = lo.Quantity
+	if err := archie.NewEpochOrder(lo, 100, int64(EpochDuration), db.EpochGapNA); err != nil {
+		t.Fatalf("NewEpochOrder: %v", err)
+	}
+	if err := archie.ExecuteOrder(lo); err != nil {
+		t.Fatalf("ExecuteOrder: %v", err)
+	}
+	const compTime = 1566497660000
+	if err := archie.SetOrderCompleteTime(lo, compTime); err != nil {
+		t.Fatalf("SetOrderCompleteTime: %v", err)
+	}
+	oids, compTimes, err := archie.CompletedUserOrders(lo.User(), 10)
+	if err != nil {
+		t.Fatalf("CompletedUserOrders: %v", err)
+	}
+	if len(oids) != 1 || oids[0] != lo.ID() || compTimes[0] != compTime {
+		t.Fatalf("wrong completed orders: %v, %v", oids, compTimes)
+	}
+
+	status, ordType, filled, err := archie.OrderStatus(lo)
+	if err != nil {
+		t.Fatalf("OrderStatus: %v", err)
+	}
+	if status != order.OrderStatusExecuted || ordType != order.LimitOrderType || filled != int64(lo.Quantity) {
+		t.Fatalf("wrong order status %v, type %v, filled %d", status, ordType, filled)
+	}
+
+	ords, statuses, err := archie.UserOrders(context.Background(), lo.User(), AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("UserOrders: %v", err)
+	}
+	if len(ords) != 1 || statuses[0] != order.OrderStatusExecuted {
+		t.Fatalf("wrong user orders: %v, %v", ords, statuses)
+	}
+}
+
+func TestMarketOrderWorstRate(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	lo := newLimitOrder(true, 4_800_000, 2, 0)
+	mo := &order.MarketOrder{
+		P:         lo.P,
+		T:         *lo.T.Copy(),
+		WorstRate: 4_700_000,
+	}
+	mo.OrderType = order.MarketOrderType
+	if err := archie.NewEpochOrder(mo, 100, int64(EpochDuration), db.EpochGapNA); err != nil {
+		t.Fatalf("NewEpochOrder: %v", err)
+	}
+	epochOrds, err := archie.EpochOrders(AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("EpochOrders: %v", err)
+	}
+	if len(epochOrds) != 1 {
+		t.Fatalf("expected 1 epoch order, got %d", len(epochOrds))
+	}
+	ordertest.MustCompareOrders(t, mo, epochOrds[0])
+	if epochOrds[0].ID() != mo.ID() {
+		t.Fatalf("loaded order has a different ID")
+	}
+}
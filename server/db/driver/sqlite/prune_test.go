@@ -0,0 +1,179 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestPruneMarket(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	const epochIdx = 156649765
+	dur := int64(EpochDuration)
+	insertEpoch := func(idx int64) {
+		t.Helper()
+		err := archie.InsertEpoch(&db.EpochResults{
+			MktBase:   AssetDCR,
+			MktQuote:  AssetBTC,
+			Idx:       idx,
+			Dur:       dur,
+			MatchTime: (idx+1)*dur + 1,
+			CSum:      randomBytes(32),
+			Seed:      randomBytes(32),
+			EndRate:   4_900_000,
+		})
+		if err != nil {
+			t.Fatalf("InsertEpoch: %v", err)
+		}
+	}
+
+	// A completed match and an active match, two epochs apart.
+	maker := newLimitOrder(true, 4_900_000, 2, 0)
+	bookOrder(t, archie, maker)
+	doneMatch := newMatch(maker, newLimitOrder(false, 5_000_000, 1, 10), LotSize, order.EpochID{Idx: epochIdx, Dur: EpochDuration})
+	activeMatch := newMatch(maker, newLimitOrder(false, 5_000_000, 1, 20), LotSize, order.EpochID{Idx: epochIdx + 2, Dur: EpochDuration})
+	for _, match := range []*order.Match{doneMatch, activeMatch} {
+		if err := archie.InsertMatch(match); err != nil {
+			t.Fatalf("InsertMatch: %v", err)
+		}
+		insertEpoch(int64(match.Epoch.Idx))
+	}
+	doneMID := db.MarketMatchID{MatchID: doneMatch.ID(), Base: AssetDCR, Quote: AssetBTC}
+	if err := archie.SetMatchInactive(doneMID, false); err != nil {
+		t.Fatalf("SetMatchInactive: %v", err)
+	}
+
+	// An executed cancel order.
+	co := newCancelOrder(maker.ID(), maker.User(), 30)
+	if err := archie.NewEpochOrder(co, epochIdx, dur, 1); err != nil {
+		t.Fatalf("NewEpochOrder: %v", err)
+	}
+	if err := archie.ExecuteOrder(co); err != nil {
+		t.Fatalf("ExecuteOrder: %v", err)
+	}
+
+	// Only the report for the first epoch ended before reportsBefore.
+	reportsBefore := time.UnixMilli((epochIdx + 2) * dur)
+	res, err := archie.PruneMarket(AssetDCR, AssetBTC, time.Now(), reportsBefore, 0)
+	if err != nil {
+		t.Fatalf("PruneMarket: %v", err)
+	}
+	if *res != (db.PruneResult{Matches: 1, CancelOrders: 1, Epochs: 1, EpochReports: 1}) {
+		t.Fatalf("wrong prune result: %+v", res)
+	}
+
+	if _, err = archie.MatchByID(doneMatch.ID(), AssetDCR, AssetBTC); err == nil {
+		t.Fatalf("completed match not pruned")
+	}
+	if _, err = archie.MatchByID(activeMatch.ID(), AssetDCR, AssetBTC); err != nil {
+		t.Fatalf("active match pruned: %v", err)
+	}
+	if _, _, err = archie.Order(co.ID(), AssetDCR, AssetBTC); err == nil {
+		t.Fatalf("cancel order not pruned")
+	}
+
+	// The active match's epoch is retained until the match is inactive.
+	if res, err = archie.PruneMarket(AssetDCR, AssetBTC, time.Now(), reportsBefore, 0); err != nil {
+		t.Fatalf("PruneMarket: %v", err)
+	}
+	if *res != (db.PruneResult{}) {
+		t.Fatalf("wrong prune result: %+v", res)
+	}
+	activeMID := db.MarketMatchID{MatchID: activeMatch.ID(), Base: AssetDCR, Quote: AssetBTC}
+	if err = archie.SetMatchInactive(activeMID, false); err != nil {
+		t.Fatalf("SetMatchInactive: %v", err)
+	}
+	if res, err = archie.PruneMarket(AssetDCR, AssetBTC, time.Now(), time.Now(), 0); err != nil {
+		t.Fatalf("PruneMarket: %v", err)
+	}
+	if *res != (db.PruneResult{Matches: 1, Epochs: 1, EpochReports: 1}) {
+		t.Fatalf("wrong prune result: %+v", res)
+	}
+}
+
+func TestPruneMarketKeep(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	const epochIdx = 156649765
+	dur := int64(EpochDuration)
+
+	// Two completed matches and two executed cancel orders for the same
+	// maker, one epoch apart. The taker is also the same for both matches.
+	maker := newLimitOrder(true, 4_900_000, 2, 0)
+	bookOrder(t, archie, maker)
+	taker1 := newLimitOrder(false, 5_000_000, 1, 10)
+	taker2 := newLimitOrder(false, 5_000_000, 1, 20)
+	taker2.P.AccountID = taker1.P.AccountID
+	var matches []*order.Match
+	var cancels []*order.CancelOrder
+	for i, taker := range []*order.LimitOrder{taker1, taker2} {
+		idx := int64(epochIdx + i)
+		match := newMatch(maker, taker, LotSize, order.EpochID{Idx: uint64(idx), Dur: EpochDuration})
+		if err := archie.InsertMatch(match); err != nil {
+			t.Fatalf("InsertMatch: %v", err)
+		}
+		mid := db.MarketMatchID{MatchID: match.ID(), Base: AssetDCR, Quote: AssetBTC}
+		if err := archie.SetMatchInactive(mid, false); err != nil {
+			t.Fatalf("SetMatchInactive: %v", err)
+		}
+		err := archie.InsertEpoch(&db.EpochResults{
+			MktBase:   AssetDCR,
+			MktQuote:  AssetBTC,
+			Idx:       idx,
+			Dur:       dur,
+			MatchTime: (idx+1)*dur + 1,
+			CSum:      randomBytes(32),
+			Seed:      randomBytes(32),
+			EndRate:   4_900_000,
+		})
+		if err != nil {
+			t.Fatalf("InsertEpoch: %v", err)
+		}
+		co := newCancelOrder(maker.ID(), maker.User(), 30+int64(i))
+		if err := archie.NewEpochOrder(co, idx, dur, 1); err != nil {
+			t.Fatalf("NewEpochOrder: %v", err)
+		}
+		if err := archie.ExecuteOrder(co); err != nil {
+			t.Fatalf("ExecuteOrder: %v", err)
+		}
+		matches = append(matches, match)
+		cancels = append(cancels, co)
+	}
+
+	// Only the most recent match and cancel order of each account are kept,
+	// along with the epoch of the kept cancel order.
+	res, err := archie.PruneMarket(AssetDCR, AssetBTC, time.Now(), time.UnixMilli(0), 1)
+	if err != nil {
+		t.Fatalf("PruneMarket: %v", err)
+	}
+	if *res != (db.PruneResult{Matches: 1, CancelOrders: 1, Epochs: 1}) {
+		t.Fatalf("wrong prune result: %+v", res)
+	}
+	if _, err = archie.MatchByID(matches[0].ID(), AssetDCR, AssetBTC); err == nil {
+		t.Fatalf("old match not pruned")
+	}
+	if _, err = archie.MatchByID(matches[1].ID(), AssetDCR, AssetBTC); err != nil {
+		t.Fatalf("recent match pruned: %v", err)
+	}
+	if _, _, err = archie.Order(cancels[0].ID(), AssetDCR, AssetBTC); err == nil {
+		t.Fatalf("old cancel order not pruned")
+	}
+	if _, _, err = archie.Order(cancels[1].ID(), AssetDCR, AssetBTC); err != nil {
+		t.Fatalf("recent cancel order pruned: %v", err)
+	}
+
+	// The kept cancel order is still counted for the maker.
+	cancelRecords, err := archie.ExecutedCancelsForUser(maker.User(), 10)
+	if err != nil {
+		t.Fatalf("ExecutedCancelsForUser: %v", err)
+	}
+	if len(cancelRecords) != 1 || cancelRecords[0].ID != cancels[1].ID() {
+		t.Fatalf("wrong executed cancels: %+v", cancelRecords)
+	}
+}
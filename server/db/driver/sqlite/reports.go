@@ -0,0 +1,72 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+// MarketStats summarizes the trade matches made on the market in the time
+// range [start, end). The time of a match is the start of its epoch.
+func (a *Archiver) MarketStats(base, quote uint32, start, end time.Time) (*db.MarketStats, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	stats := &db.MarketStats{
+		Fees: make(map[uint32]uint64),
+	}
+	stmt := fmt.Sprintf(internal.SelectMatchesInRange, fullMatchesTableName(marketSchema))
+	rows, err := a.db.QueryContext(ctx, stmt, startMs, endMs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var qty, rate uint64
+		var active bool
+		var status uint8
+		if err = rows.Scan(&qty, &rate, &active, &status); err != nil {
+			return nil, err
+		}
+		stats.Matches++
+		stats.Volume += qty
+		stats.QuoteVolume += calc.BaseToQuote(rate, qty)
+		if !active && order.MatchStatus(status) < order.MatchComplete {
+			stats.FailedSwaps++
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stmt = fmt.Sprintf(internal.SelectTradingFeeTotalsInRange, fullTradingFeesTableName(marketSchema))
+	if err = sumTradingFees(ctx, a.db, stmt, stats.Fees, startMs, endMs); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// NewAccounts counts the accounts created in the time range [start, end).
+func (a *Archiver) NewAccounts(start, end time.Time) (uint32, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	var n uint32
+	stmt := fmt.Sprintf(internal.SelectNewAccountCount, a.tables.accounts)
+	err := a.db.QueryRowContext(ctx, stmt, start.UnixMilli(), end.UnixMilli()).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,91 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/server/db"
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+// AuthSettings retrieves the stored account conduct settings, or nil if the
+// settings were never set.
+func (a *Archiver) AuthSettings() (*db.AuthSettings, error) {
+	stmt := fmt.Sprintf(internal.SelectAuthSettings, a.tables.authSettings)
+	var s db.AuthSettings
+	var suspensionMs, halfLifeMs int64
+	var grace sql.NullInt64
+	err := a.db.QueryRowContext(a.ctx, stmt).Scan(&s.CancelThreshold, &s.FreeCancels,
+		&s.PenaltyThreshold, &grace, &suspensionMs, &halfLifeMs, (*msTime)(&s.Stamp))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if grace.Valid {
+		graceLimit := uint32(grace.Int64)
+		s.GraceLimit = &graceLimit
+	}
+	s.SuspensionPeriod = time.Duration(suspensionMs) * time.Millisecond
+	s.BanScoreHalfLife = time.Duration(halfLifeMs) * time.Millisecond
+	return &s, nil
+}
+
+// SetAuthSettings stores the account conduct settings, replacing any
+// previously stored settings.
+func (a *Archiver) SetAuthSettings(s *db.AuthSettings) error {
+	stmt := fmt.Sprintf(internal.UpsertAuthSettings, a.tables.authSettings)
+	var grace sql.NullInt64
+	if s.GraceLimit != nil {
+		grace = sql.NullInt64{Int64: int64(*s.GraceLimit), Valid: true}
+	}
+	_, err := a.db.ExecContext(a.ctx, stmt, s.CancelThreshold, s.FreeCancels, s.PenaltyThreshold,
+		grace, s.SuspensionPeriod.Milliseconds(), s.BanScoreHalfLife.Milliseconds(), msTime(s.Stamp))
+	return err
+}
+
+// MarketSwapConfs retrieves the stored per-market swapConf overrides, keyed by
+// market name and then asset ID.
+func (a *Archiver) MarketSwapConfs() (map[string]map[uint32]uint32, error) {
+	stmt := fmt.Sprintf(internal.SelectMarketSwapConfs, a.tables.swapConfs)
+	rows, err := a.db.QueryContext(a.ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	swapConfs := make(map[string]map[uint32]uint32)
+	for rows.Next() {
+		var mktName string
+		var assetID, swapConf int64
+		if err = rows.Scan(&mktName, &assetID, &swapConf); err != nil {
+			return nil, err
+		}
+		mktConfs := swapConfs[mktName]
+		if mktConfs == nil {
+			mktConfs = make(map[uint32]uint32, 1)
+			swapConfs[mktName] = mktConfs
+		}
+		mktConfs[uint32(assetID)] = uint32(swapConf)
+	}
+	return swapConfs, rows.Err()
+}
+
+// SetMarketSwapConf stores the swapConf override for the asset on the named
+// market. A swapConf of zero removes the stored override.
+func (a *Archiver) SetMarketSwapConf(mktName string, assetID, swapConf uint32) error {
+	if swapConf == 0 {
+		stmt := fmt.Sprintf(internal.DeleteMarketSwapConf, a.tables.swapConfs)
+		_, err := a.db.ExecContext(a.ctx, stmt, mktName, int64(assetID))
+		return err
+	}
+	stmt := fmt.Sprintf(internal.UpsertMarketSwapConf, a.tables.swapConfs)
+	_, err := a.db.ExecContext(a.ctx, stmt, mktName, int64(assetID), int64(swapConf))
+	return err
+}
@@ -0,0 +1,89 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/server/db"
+)
+
+func TestAuthSettings(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	s, err := archie.AuthSettings()
+	if err != nil {
+		t.Fatalf("AuthSettings: %v", err)
+	}
+	if s != nil {
+		t.Fatalf("expected no stored settings, got %+v", s)
+	}
+
+	grace := uint32(4)
+	for _, set := range []*db.AuthSettings{
+		{CancelThreshold: 0.9, PenaltyThreshold: 20, SuspensionPeriod: time.Hour,
+			BanScoreHalfLife: 24 * time.Hour, Stamp: time.UnixMilli(1700000000000).UTC()},
+		{CancelThreshold: 0.75, FreeCancels: true, PenaltyThreshold: 30, GraceLimit: &grace,
+			SuspensionPeriod: 2 * time.Hour, BanScoreHalfLife: 48 * time.Hour, Stamp: time.UnixMilli(1700000100000).UTC()},
+	} {
+		if err := archie.SetAuthSettings(set); err != nil {
+			t.Fatalf("SetAuthSettings: %v", err)
+		}
+		s, err = archie.AuthSettings()
+		if err != nil {
+			t.Fatalf("AuthSettings: %v", err)
+		}
+		if (s.GraceLimit == nil) != (set.GraceLimit == nil) || (s.GraceLimit != nil && *s.GraceLimit != *set.GraceLimit) {
+			t.Fatalf("wrong grace limit. wanted %v, got %v", set.GraceLimit, s.GraceLimit)
+		}
+		s.GraceLimit = set.GraceLimit
+		if *s != *set {
+			t.Fatalf("wrong settings. wanted %+v, got %+v", set, s)
+		}
+	}
+}
+
+func TestMarketSwapConfs(t *testing.T) {
+	archie := newTestArchiver(t)
+
+	check := func(want map[string]map[uint32]uint32) {
+		t.Helper()
+		swapConfs, err := archie.MarketSwapConfs()
+		if err != nil {
+			t.Fatalf("MarketSwapConfs: %v", err)
+		}
+		if len(swapConfs) != len(want) {
+			t.Fatalf("wanted %d markets, got %d", len(want), len(swapConfs))
+		}
+		for mktName, wantConfs := range want {
+			confs := swapConfs[mktName]
+			if len(confs) != len(wantConfs) {
+				t.Fatalf("wanted %d overrides for %s, got %d", len(wantConfs), mktName, len(confs))
+			}
+			for assetID, swapConf := range wantConfs {
+				if confs[assetID] != swapConf {
+					t.Fatalf("wrong swapConf for asset %d on %s. wanted %d, got %d",
+						assetID, mktName, swapConf, confs[assetID])
+				}
+			}
+		}
+	}
+
+	check(map[string]map[uint32]uint32{})
+
+	set := func(mktName string, assetID, swapConf uint32) {
+		t.Helper()
+		if err := archie.SetMarketSwapConf(mktName, assetID, swapConf); err != nil {
+			t.Fatalf("SetMarketSwapConf: %v", err)
+		}
+	}
+	set("dcr_btc", 42, 4)
+	set("dcr_btc", 0, 2)
+	set("dcr_btc", 42, 6)
+	check(map[string]map[uint32]uint32{"dcr_btc": {42: 6, 0: 2}})
+
+	set("dcr_btc", 0, 0)
+	check(map[string]map[uint32]uint32{"dcr_btc": {42: 6}})
+}
@@ -0,0 +1,266 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package sqlite implements server/db.DEXArchivist with an SQLite database
+// file. It is an alternative to the pg driver for operators of small servers
+// and for tests that should not require a PostgreSQL instance. All tables are
+// kept in one file, with the market tables prefixed by the market name instead
+// of being in a per-market schema.
+//
+// The driver requires cgo. dcrdex only registers it in builds with cgo
+// enabled.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/db"
+	_ "github.com/mattn/go-sqlite3" // the "sqlite3" sql driver
+)
+
+// Driver implements db.Driver.
+type Driver struct{}
+
+// Open creates the DB backend, returning a DEXArchivist.
+func (d *Driver) Open(ctx context.Context, cfg any) (db.DEXArchivist, error) {
+	switch c := cfg.(type) {
+	case *Config:
+		return NewArchiver(ctx, c)
+	case Config:
+		return NewArchiver(ctx, &c)
+	case *db.FileConfig:
+		return NewArchiver(ctx, &Config{
+			Path:         c.Path,
+			QueryTimeout: c.QueryTimeout,
+			MarketCfg:    c.MarketCfg,
+		})
+	default:
+		return nil, fmt.Errorf("invalid config type %T", cfg)
+	}
+}
+
+// UseLogger sets the package-wide logger for the registered DB Driver.
+func (*Driver) UseLogger(logger dex.Logger) {
+	UseLogger(logger)
+}
+
+func init() {
+	db.Register("sqlite", &Driver{})
+}
+
+const (
+	defaultQueryTimeout = 20 * time.Minute
+	// busyTimeout is how long a connection waits for another connection's
+	// write lock before failing with SQLITE_BUSY.
+	busyTimeout = 30 * time.Second
+)
+
+// Config holds the Archiver's configuration.
+type Config struct {
+	// Path is the path of the database file, which is created if it does not
+	// exist.
+	Path         string
+	QueryTimeout time.Duration
+
+	// MarketCfg specifies all of the markets that the Archiver should prepare.
+	MarketCfg []*dex.MarketInfo
+}
+
+// Some frequently used table names.
+type archiverTables struct {
+	feeKeys      string
+	accounts     string
+	bonds        string
+	prepaidBonds string
+	authSettings string
+	swapConfs    string
+	banScores    string
+}
+
+// Archiver must implement server/db.DEXArchivist.
+type Archiver struct {
+	ctx          context.Context
+	queryTimeout time.Duration
+	db           *sql.DB
+	markets      map[string]*dex.MarketInfo
+	tables       archiverTables
+
+	fatalMtx sync.RWMutex
+	fatal    chan struct{}
+	fatalErr error
+}
+
+var _ db.DEXArchivist = (*Archiver)(nil)
+
+// LastErr returns any fatal or unexpected error encountered in a recent query.
+// This may be used to check if the database had an unrecoverable error.
+func (a *Archiver) LastErr() error {
+	a.fatalMtx.RLock()
+	defer a.fatalMtx.RUnlock()
+	return a.fatalErr
+}
+
+// Fatal returns a nil or closed channel for select use. Use LastErr to get the
+// latest fatal error.
+func (a *Archiver) Fatal() <-chan struct{} {
+	a.fatalMtx.RLock()
+	defer a.fatalMtx.RUnlock()
+	return a.fatal
+}
+
+// Ping checks that the database is reachable.
+func (a *Archiver) Ping() error {
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+	return a.db.PingContext(ctx)
+}
+
+func (a *Archiver) fatalBackendErr(err error) {
+	if err == nil {
+		return
+	}
+	a.fatalMtx.Lock()
+	if a.fatalErr == nil {
+		close(a.fatal)
+	}
+	a.fatalErr = err
+	a.fatalMtx.Unlock()
+}
+
+// NewArchiverForRead constructs a new Archiver without creating or modifying
+// any data structures. This should be used for read-only applications. Use
+// Close when done with the Archiver.
+func NewArchiverForRead(ctx context.Context, cfg *Config) (*Archiver, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("no database file path")
+	}
+	db, err := connect(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	ver, err := retrieveSQLiteVersion(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	log.Infof("SQLite %s, database file %s", ver, cfg.Path)
+
+	queryTimeout := cfg.QueryTimeout
+	if queryTimeout <= 0 {
+		queryTimeout = defaultQueryTimeout
+	}
+
+	mktMap := make(map[string]*dex.MarketInfo, len(cfg.MarketCfg))
+	for _, mkt := range cfg.MarketCfg {
+		mktMap[marketSchema(mkt.Name)] = mkt
+	}
+
+	return &Archiver{
+		ctx:          ctx,
+		db:           db,
+		queryTimeout: queryTimeout,
+		markets:      mktMap,
+		tables: archiverTables{
+			feeKeys:      fullTableName("", feeKeysTableName),
+			accounts:     fullTableName("", accountsTableName),
+			bonds:        fullTableName("", bondsTableName),
+			prepaidBonds: fullTableName("", prepaidBondsTableName),
+			authSettings: fullTableName("", authSettingsTableName),
+			swapConfs:    fullTableName("", swapConfsTableName),
+			banScores:    fullTableName("", banScoresTableName),
+		},
+		fatal: make(chan struct{}),
+	}, nil
+}
+
+// NewArchiver constructs a new Archiver. All tables are created, including
+// tables for markets that may have been added since last startup. Use Close
+// when done with the Archiver.
+func NewArchiver(ctx context.Context, cfg *Config) (*Archiver, error) {
+	if dir := filepath.Dir(cfg.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	archiver, err := NewArchiverForRead(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure all tables required by the current market configuration are ready.
+	purgeMarkets, err := prepareTables(ctx, archiver.db, cfg.MarketCfg)
+	if err != nil {
+		archiver.Close()
+		return nil, err
+	}
+	for _, staleMarket := range purgeMarkets {
+		mkt := archiver.markets[staleMarket]
+		if mkt == nil { // shouldn't happen
+			archiver.Close()
+			return nil, fmt.Errorf("unrecognized market %v", staleMarket)
+		}
+		unbookedSells, unbookedBuys, err := archiver.FlushBook(mkt.Base, mkt.Quote)
+		if err != nil {
+			archiver.Close()
+			return nil, fmt.Errorf("failed to flush book for market %v: %w", staleMarket, err)
+		}
+		log.Infof("Flushed %d sell orders and %d buy orders from market %v with a changed lot size.",
+			len(unbookedSells), len(unbookedBuys), staleMarket)
+	}
+
+	return archiver, nil
+}
+
+// connect opens the database file. The database uses write-ahead logging so
+// that reads are not blocked by a writer, and transactions take the write lock
+// when they begin so that concurrent transactions wait on the busy timeout
+// instead of failing when upgrading from a read lock.
+func connect(path string) (*sql.DB, error) {
+	q := url.Values{}
+	q.Set("_journal_mode", "WAL")
+	q.Set("_busy_timeout", fmt.Sprint(busyTimeout.Milliseconds()))
+	q.Set("_txlock", "immediate")
+	dsn := "file:" + path + "?" + q.Encode()
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// Establish a connection and verify the file is a database.
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Close closes the underlying DB connection.
+func (a *Archiver) Close() error {
+	return a.db.Close()
+}
+
+func (a *Archiver) marketSchema(base, quote uint32) (string, error) {
+	marketName, err := dex.MarketName(base, quote)
+	if err != nil {
+		return "", err
+	}
+	schema := marketSchema(marketName)
+	_, found := a.markets[schema]
+	if !found {
+		return "", db.ArchiveError{
+			Code:   db.ErrUnsupportedMarket,
+			Detail: fmt.Sprintf(`archiver does not support the market "%s"`, schema),
+		}
+	}
+	return schema, nil
+}
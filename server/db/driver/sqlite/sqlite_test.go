@@ -0,0 +1,225 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/db"
+	"github.com/decred/slog"
+)
+
+const (
+	LotSize         = uint64(100_0000_0000) // 100
+	RateStep        = uint64(10_0000)       // 0.001
+	EpochDuration   = uint64(10_000)
+	MarketBuyBuffer = 1.1
+)
+
+var (
+	tLogger  = slog.NewBackend(os.Stdout).Logger("SQLITE_DB_TEST")
+	mktInfo  *dex.MarketInfo
+	mktInfo2 *dex.MarketInfo
+	AssetDCR uint32
+	AssetBTC uint32
+	AssetLTC uint32
+)
+
+func TestMain(m *testing.M) {
+	tLogger.SetLevel(slog.LevelInfo)
+	UseLogger(tLogger)
+
+	var err error
+	mktInfo, err = dex.NewMarketInfoFromSymbols("dcr", "btc", LotSize, RateStep, EpochDuration, 0, MarketBuyBuffer)
+	if err != nil {
+		panic(err)
+	}
+	mktInfo2, err = dex.NewMarketInfoFromSymbols("btc", "ltc", LotSize, RateStep, EpochDuration, 0, MarketBuyBuffer)
+	if err != nil {
+		panic(err)
+	}
+	AssetDCR, AssetBTC, AssetLTC = mktInfo.Base, mktInfo.Quote, mktInfo2.Quote
+
+	os.Exit(m.Run())
+}
+
+func testConfig(t *testing.T) *Config {
+	return &Config{
+		Path:      filepath.Join(t.TempDir(), "dcrdex.db"),
+		MarketCfg: []*dex.MarketInfo{mktInfo, mktInfo2},
+	}
+}
+
+func newTestArchiver(t *testing.T) *Archiver {
+	t.Helper()
+	archie, err := NewArchiver(context.Background(), testConfig(t))
+	if err != nil {
+		t.Fatalf("NewArchiver: %v", err)
+	}
+	t.Cleanup(func() { archie.Close() })
+	return archie
+}
+
+func randomBytes(len int) []byte {
+	bytes := make([]byte, len)
+	rand.Read(bytes)
+	return bytes
+}
+
+func randomAccountID() account.AccountID {
+	return account.NewID(randomBytes(account.PubKeySize))
+}
+
+func randomPreimage() (pi order.Preimage) {
+	rand.Read(pi[:])
+	return
+}
+
+func newLimitOrder(sell bool, rate, quantityLots uint64, timeOffset int64) *order.LimitOrder {
+	pi := randomPreimage()
+	return &order.LimitOrder{
+		P: order.Prefix{
+			AccountID:  randomAccountID(),
+			BaseAsset:  AssetDCR,
+			QuoteAsset: AssetBTC,
+			OrderType:  order.LimitOrderType,
+			ClientTime: time.UnixMilli(1566497653000 + timeOffset).UTC(),
+			ServerTime: time.UnixMilli(1566497656000 + timeOffset).UTC(),
+			Commit:     pi.Commit(),
+		},
+		T: order.Trade{
+			Coins:    []order.CoinID{randomBytes(36), randomBytes(36)},
+			Sell:     sell,
+			Quantity: quantityLots * LotSize,
+			Address:  "DcqXswjTPnUcd4FRCkX4vRJxmVtfgGVa5ui",
+		},
+		Rate:  rate,
+		Force: order.StandingTiF,
+	}
+}
+
+// bookOrder stores the order as an epoch order and then books it.
+func bookOrder(t *testing.T, archie *Archiver, lo *order.LimitOrder) {
+	t.Helper()
+	if err := archie.NewEpochOrder(lo, 100, int64(EpochDuration), db.EpochGapNA); err != nil {
+		t.Fatalf("NewEpochOrder: %v", err)
+	}
+	if err := archie.BookOrder(lo); err != nil {
+		t.Fatalf("BookOrder: %v", err)
+	}
+}
+
+func newCancelOrder(target order.OrderID, aid account.AccountID, timeOffset int64) *order.CancelOrder {
+	pi := randomPreimage()
+	return &order.CancelOrder{
+		P: order.Prefix{
+			AccountID:  aid,
+			BaseAsset:  AssetDCR,
+			QuoteAsset: AssetBTC,
+			OrderType:  order.CancelOrderType,
+			ClientTime: time.UnixMilli(1566497653000 + timeOffset).UTC(),
+			ServerTime: time.UnixMilli(1566497656000 + timeOffset).UTC(),
+			Commit:     pi.Commit(),
+		},
+		TargetOrderID: target,
+	}
+}
+
+func newMatch(maker *order.LimitOrder, taker order.Order, quantity uint64, epochID order.EpochID) *order.Match {
+	return &order.Match{
+		Maker:        maker,
+		Taker:        taker,
+		Quantity:     quantity,
+		Rate:         maker.Rate,
+		FeeRateBase:  12,
+		FeeRateQuote: 14,
+		Status:       order.NewlyMatched,
+		Sigs:         order.Signatures{},
+		Epoch:        epochID,
+	}
+}
+
+func TestReopen(t *testing.T) {
+	cfg := testConfig(t)
+	archie, err := NewArchiver(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewArchiver: %v", err)
+	}
+	lo := newLimitOrder(false, 4_900_000, 1, 0)
+	bookOrder(t, archie, lo)
+	if err = archie.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Reopen with a new lot size for the first market.
+	mkt, _ := dex.NewMarketInfoFromSymbols("dcr", "btc", LotSize*2, RateStep, EpochDuration, 0, MarketBuyBuffer)
+	cfg.MarketCfg = []*dex.MarketInfo{mkt, mktInfo2}
+	archie, err = NewArchiver(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewArchiver (reopen): %v", err)
+	}
+	defer archie.Close()
+
+	// The lot size change flushes the book.
+	_, status, err := archie.Order(lo.ID(), AssetDCR, AssetBTC)
+	if err != nil {
+		t.Fatalf("Order: %v", err)
+	}
+	if status != order.OrderStatusRevoked {
+		t.Fatalf("expected revoked order after lot size change, got %v", status)
+	}
+
+	ver, err := DBVersion(archie.db)
+	if err != nil {
+		t.Fatalf("DBVersion: %v", err)
+	}
+	if ver != dbVersion {
+		t.Fatalf("wrong DB version %d, expected %d", ver, dbVersion)
+	}
+
+	// Read-only access to an existing DB.
+	reader, err := NewArchiverForRead(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewArchiverForRead: %v", err)
+	}
+	defer reader.Close()
+	if _, _, err = reader.Order(lo.ID(), AssetDCR, AssetBTC); err != nil {
+		t.Fatalf("Order from reader: %v", err)
+	}
+}
+
+func TestDriver(t *testing.T) {
+	archie, err := db.Open(context.Background(), "sqlite", testConfig(t))
+	if err != nil {
+		t.Fatalf("db.Open: %v", err)
+	}
+	if err = archie.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The driver can be opened without importing this package's Config.
+	cfg := testConfig(t)
+	archie, err = db.Open(context.Background(), "sqlite", &db.FileConfig{
+		Path:      cfg.Path,
+		MarketCfg: cfg.MarketCfg,
+	})
+	if err != nil {
+		t.Fatalf("db.Open with FileConfig: %v", err)
+	}
+	if err = archie.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err = db.Open(context.Background(), "sqlite", "not a config"); err == nil {
+		t.Fatalf("no error for invalid config type")
+	}
+}
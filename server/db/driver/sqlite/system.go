@@ -0,0 +1,118 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+// sqlExecutor is implemented by both sql.DB and sql.Tx.
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+type sqlQueryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+type sqlQueryExecutor interface {
+	sqlQueryer
+	sqlExecutor
+}
+
+// sqlExec executes the SQL statement string with any optional arguments, and
+// returns the number of rows affected.
+func sqlExec(db sqlExecutor, stmt string, args ...any) (int64, error) {
+	res, err := db.Exec(stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	var N int64
+	N, err = res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf(`error in RowsAffected: %w`, err)
+	}
+	return N, err
+}
+
+// tableExists checks if the specified table exists.
+func tableExists(db sqlQueryer, tableName string) (bool, error) {
+	rows, err := db.Query(internal.TableExists, tableName)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	return rows.Next(), rows.Err()
+}
+
+func createIndexStmt(db sqlExecutor, fmtStmt, indexName, fullTableName string) error {
+	stmt := fmt.Sprintf(fmtStmt, indexName, fullTableName)
+	_, err := db.Exec(stmt)
+	return err
+}
+
+// createTableStmt creates a table with the given name using the provided SQL
+// statement, if it does not already exist. The table name is prefixed with the
+// schema, if not empty. See fullTableName.
+func createTableStmt(db sqlQueryExecutor, fmtStmt, schema, tableName string) (bool, error) {
+	name := tableNameInSchema(schema, tableName)
+	exists, err := tableExists(db, name)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	stmt := fmt.Sprintf(fmtStmt, quoteIdent(name))
+	log.Debugf("Creating the %q table.", name)
+	if _, err = db.Exec(stmt); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func retrieveSQLiteVersion(db *sql.DB) (ver string, err error) {
+	err = db.QueryRow(internal.RetrieveSQLiteVersion).Scan(&ver)
+	return
+}
+
+// tableNameInSchema is the name of a table in the schema. SQLite has no
+// schemas within a database file, so a market's tables are distinguished from
+// other markets' tables by a prefix.
+func tableNameInSchema(schema, table string) string {
+	if schema == "" {
+		return table
+	}
+	return schema + "_" + table
+}
+
+// fullTableName is the quoted table name for use in statements. The quotes
+// allow market names that are not valid unquoted identifiers, e.g. those
+// starting with a digit.
+func fullTableName(schema, table string) string {
+	return quoteIdent(tableNameInSchema(schema, table))
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// paramList creates a comma-separated list of n numbered parameters, starting
+// at ?start, for an IN list.
+func paramList(start, n int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = "?" + strconv.Itoa(start+i)
+	}
+	return strings.Join(params, ", ")
+}
@@ -0,0 +1,269 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+const (
+	marketsTableName      = "markets"
+	metaTableName         = "meta"
+	feeKeysTableName      = "fee_keys"
+	accountsTableName     = "accounts"
+	bondsTableName        = "bonds"
+	prepaidBondsTableName = "prepaid_bonds"
+	authSettingsTableName = "auth_settings"
+	swapConfsTableName    = "market_swap_confs"
+	banScoresTableName    = "ban_scores"
+
+	indexBondsOnAccountName  = "idx_bonds_on_acct"
+	indexBondsOnLockTimeName = "idx_bonds_on_locktime"
+
+	// market tables, prefixed with the market schema
+	matchesTableName         = "matches"
+	epochsTableName          = "epochs"
+	ordersArchivedTableName  = "orders_archived"
+	ordersActiveTableName    = "orders_active"
+	cancelsArchivedTableName = "cancels_archived"
+	cancelsActiveTableName   = "cancels_active"
+	epochReportsTableName    = "epoch_reports"
+	candlesTableName         = "candles"
+	tradingFeesTableName     = "trading_fees"
+
+	// market indexes, prefixed with the market schema
+	indexOrdersArchivedOnAccountName  = "idx_orders_archived_on_acct"
+	indexCancelsArchivedOnAccountName = "idx_cancels_archived_on_acct"
+	indexMatchesOnTakerName           = "idx_matches_on_taker"
+	indexMatchesOnMakerName           = "idx_matches_on_maker"
+)
+
+type tableStmt struct {
+	name string
+	stmt string
+}
+
+var createDEXTableStatements = []tableStmt{
+	{marketsTableName, internal.CreateMarketsTable},
+	{metaTableName, internal.CreateMetaTable},
+}
+
+var createAccountTableStatements = []tableStmt{
+	{feeKeysTableName, internal.CreateFeeKeysTable},
+	{accountsTableName, internal.CreateAccountsTable},
+	{bondsTableName, internal.CreateBondsTable},
+	{prepaidBondsTableName, internal.CreatePrepaidBondsTable},
+	{authSettingsTableName, internal.CreateAuthSettingsTable},
+	{swapConfsTableName, internal.CreateMarketSwapConfsTable},
+	{banScoresTableName, internal.CreateBanScoresTable},
+}
+
+type indexStmt struct {
+	idxName   string
+	tableName string
+	stmt      string
+}
+
+var createBondIndexesStatements = []indexStmt{
+	{indexBondsOnAccountName, bondsTableName, internal.CreateBondsAcctIndex},
+	{indexBondsOnLockTimeName, bondsTableName, internal.CreateBondsLockTimeIndex},
+}
+
+var createMarketTableStatements = []tableStmt{
+	{ordersArchivedTableName, internal.CreateOrdersTable},
+	{ordersActiveTableName, internal.CreateOrdersTable},
+	{cancelsArchivedTableName, internal.CreateCancelOrdersTable},
+	{cancelsActiveTableName, internal.CreateCancelOrdersTable},
+	{matchesTableName, internal.CreateMatchesTable},
+	{epochsTableName, internal.CreateEpochsTable},
+	{epochReportsTableName, internal.CreateEpochReportTable},
+	{tradingFeesTableName, internal.CreateTradingFeesTable},
+}
+
+// The archived tables grow without bound and are queried by account, so they
+// are indexed. The pg driver relies on the primary keys alone, but a SQLite
+// table scan is far more costly for a single-connection writer.
+var createMarketIndexStatements = []indexStmt{
+	{indexOrdersArchivedOnAccountName, ordersArchivedTableName, internal.CreateOrdersAccountIndex},
+	{indexCancelsArchivedOnAccountName, cancelsArchivedTableName, internal.CreateOrdersAccountIndex},
+	{indexMatchesOnTakerName, matchesTableName, internal.CreateMatchesTakerIndex},
+	{indexMatchesOnMakerName, matchesTableName, internal.CreateMatchesMakerIndex},
+}
+
+var tableMap = func() map[string]string {
+	m := make(map[string]string, len(createDEXTableStatements)+
+		len(createMarketTableStatements)+len(createAccountTableStatements))
+	for _, tbl := range createDEXTableStatements {
+		m[tbl.name] = tbl.stmt
+	}
+	for _, tbl := range createMarketTableStatements {
+		m[tbl.name] = tbl.stmt
+	}
+	for _, tbl := range createAccountTableStatements {
+		m[tbl.name] = tbl.stmt
+	}
+	return m
+}()
+
+func fullOrderTableName(marketSchema string, active bool) string {
+	if active {
+		return fullTableName(marketSchema, ordersActiveTableName)
+	}
+	return fullTableName(marketSchema, ordersArchivedTableName)
+}
+
+func fullCancelOrderTableName(marketSchema string, active bool) string {
+	if active {
+		return fullTableName(marketSchema, cancelsActiveTableName)
+	}
+	return fullTableName(marketSchema, cancelsArchivedTableName)
+}
+
+func fullMatchesTableName(marketSchema string) string {
+	return fullTableName(marketSchema, matchesTableName)
+}
+
+func fullEpochsTableName(marketSchema string) string {
+	return fullTableName(marketSchema, epochsTableName)
+}
+
+func fullEpochReportsTableName(marketSchema string) string {
+	return fullTableName(marketSchema, epochReportsTableName)
+}
+
+func fullTradingFeesTableName(marketSchema string) string {
+	return fullTableName(marketSchema, tradingFeesTableName)
+}
+
+func fullCandlesTableName(marketSchema string, candleDur uint64) string {
+	const fiveMin = 5 * 60 * 1000
+	const oneHour = 60 * 60 * 1000
+	const aDay = 24 * oneHour
+	var binSize string
+	switch candleDur {
+	case fiveMin:
+		binSize = "5m"
+	case oneHour:
+		binSize = "1h"
+	case aDay:
+		binSize = "24h"
+	default:
+		binSize = "epoch"
+	}
+	return fullTableName(marketSchema, candlesTableName+"_"+binSize)
+}
+
+// createTable creates one of the known tables by name. The table name is
+// prefixed by the schema, if not empty.
+func createTable(db sqlQueryExecutor, schema, tableName string) (bool, error) {
+	createCommand, tableNameFound := tableMap[tableName]
+	if !tableNameFound {
+		return false, fmt.Errorf("table name %q unknown", tableName)
+	}
+	return createTableStmt(db, createCommand, schema, tableName)
+}
+
+// prepareTables ensures that all tables required by the DEX market config,
+// mktConfig, are ready. This also runs any required DB scheme upgrades. The
+// Context allows safely canceling upgrades, which may be long running. Returns
+// a slice of markets that should have orders flushed due to lot size changes.
+func prepareTables(ctx context.Context, db *sql.DB, mktConfig []*dex.MarketInfo) ([]string, error) {
+	created, err := createTable(db, "", marketsTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create markets table: %w", err)
+	}
+	if created { // Fresh install
+		created, err = createTable(db, "", metaTableName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create meta table: %w", err)
+		}
+		if !created {
+			return nil, fmt.Errorf("existing meta table but no markets table: corrupt DB")
+		}
+		_, err = db.Exec(internal.CreateMetaRow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create row for meta table: %w", err)
+		}
+		err = setDBVersion(db, dbVersion) // no upgrades
+		if err != nil {
+			return nil, fmt.Errorf("failed to set db version in meta table: %w", err)
+		}
+		log.Infof("Created new meta table at version %d", dbVersion)
+	}
+	// Prepare the account and registration key counter tables.
+	if err = createAccountTables(db); err != nil {
+		return nil, err
+	}
+	if !created {
+		// Attempt upgrade.
+		if err = upgradeDB(ctx, db); err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, sql.ErrTxDone) {
+				return nil, fmt.Errorf("upgrade DB canceled: %w", err)
+			}
+			return nil, fmt.Errorf("upgrade DB failed: %w", err)
+		}
+	}
+
+	// Verify config of existing markets, creating tables for new markets. This
+	// is done after upgrades since it can create new tables with the current
+	// DB scheme for newly configured markets.
+	log.Infof("Configuring %d markets tables: %v", len(mktConfig), mktConfig)
+	return prepareMarkets(db, mktConfig)
+}
+
+// prepareMarkets ensures that the market-specific tables required by the DEX
+// market config, mktConfig, are ready. See also prepareTables.
+func prepareMarkets(db *sql.DB, mktConfig []*dex.MarketInfo) ([]string, error) {
+	mkts, err := loadMarkets(db, fullTableName("", marketsTableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read markets table: %w", err)
+	}
+	marketMap := make(map[string]*dex.MarketInfo, len(mkts))
+	for _, mkt := range mkts {
+		marketMap[mkt.Name] = mkt
+	}
+
+	var purgeMarkets []string
+	for _, mkt := range mktConfig {
+		existingMkt := marketMap[mkt.Name]
+		if existingMkt == nil {
+			log.Infof("New market specified in config: %s", mkt.Name)
+			err = newMarket(db, fullTableName("", marketsTableName), mkt)
+			if err != nil {
+				return nil, fmt.Errorf("newMarket failed: %w", err)
+			}
+		} else if mkt.LotSize != existingMkt.LotSize {
+			err = updateLotSize(db, mkt.Name, mkt.LotSize)
+			if err != nil {
+				return nil, fmt.Errorf("unable to update lot size for %s: %w", mkt.Name, err)
+			}
+			purgeMarkets = append(purgeMarkets, marketSchema(mkt.Name))
+		}
+
+		err = createMarketTables(db, mkt.Name, existingMkt == nil)
+		if err != nil {
+			return nil, fmt.Errorf("createMarketTables failed: %w", err)
+		}
+	}
+
+	return purgeMarkets, nil
+}
+
+// updateLotSize updates the lot size for a market. Must only be called on an
+// existing market.
+func updateLotSize(db sqlExecutor, mktName string, lotSize uint64) error {
+	stmt := fmt.Sprintf(internal.UpdateLotSize, fullTableName("", marketsTableName))
+	_, err := db.Exec(stmt, mktName, lotSize)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Updated %s lot size to %d.", mktName, lotSize)
+	return nil
+}
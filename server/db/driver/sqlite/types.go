@@ -0,0 +1,201 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/dex/order"
+)
+
+// msTime stores a time.Time as an INTEGER of unix milliseconds. The sqlite3
+// driver would otherwise store a time.Time as TEXT, which does not sort
+// correctly across time zones. Order times have millisecond precision.
+type msTime time.Time
+
+// Value implements the sql/driver.Valuer interface.
+func (t msTime) Value() (driver.Value, error) {
+	return time.Time(t).UnixMilli(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (t *msTime) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*t = msTime(time.UnixMilli(v).UTC())
+		return nil
+	case nil:
+		*t = msTime{}
+		return nil
+	}
+	return fmt.Errorf("cannot convert %T to time", src)
+}
+
+// In a table, an []order.OrderID is stored as a BLOB of the concatenated order
+// IDs.
+type orderIDs []order.OrderID
+
+// Value implements the sql/driver.Valuer interface.
+func (oids orderIDs) Value() (driver.Value, error) {
+	if oids == nil {
+		return nil, nil
+	}
+	b := make([]byte, 0, len(oids)*order.OrderIDSize)
+	for i := range oids {
+		b = append(b, oids[i][:]...)
+	}
+	return b, nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (oids *orderIDs) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case nil:
+		*oids = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot convert %T to order IDs", src)
+	}
+	if len(b)%order.OrderIDSize != 0 {
+		return fmt.Errorf("invalid order IDs length %d", len(b))
+	}
+	*oids = make([]order.OrderID, len(b)/order.OrderIDSize)
+	for i := range *oids {
+		copy((*oids)[i][:], b[i*order.OrderIDSize:])
+	}
+	return nil
+}
+
+// fixedBlobs encodes n byte arrays of the same size as a BLOB of their
+// concatenation.
+func fixedBlobs(n, size int, elem func(i int) []byte) []byte {
+	b := make([]byte, 0, n*size)
+	for i := 0; i < n; i++ {
+		b = append(b, elem(i)...)
+	}
+	return b
+}
+
+// scanFixedBlobs checks that src is a BLOB of concatenated arrays of the given
+// size, returning the number of arrays. A NULL is reported with ok false.
+func scanFixedBlobs(src any, size int, what string) (b []byte, n int, ok bool, err error) {
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case nil:
+		return nil, 0, false, nil
+	default:
+		return nil, 0, false, fmt.Errorf("cannot convert %T to %s", src, what)
+	}
+	if len(b)%size != 0 {
+		return nil, 0, false, fmt.Errorf("invalid %s length %d", what, len(b))
+	}
+	return b, len(b) / size, true, nil
+}
+
+// In a table, a []order.Preimage is stored as a BLOB of the concatenated
+// preimages.
+type preimages []order.Preimage
+
+// Value implements the sql/driver.Valuer interface.
+func (pis preimages) Value() (driver.Value, error) {
+	if pis == nil {
+		return nil, nil
+	}
+	return fixedBlobs(len(pis), order.PreimageSize, func(i int) []byte { return pis[i][:] }), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (pis *preimages) Scan(src any) error {
+	b, n, ok, err := scanFixedBlobs(src, order.PreimageSize, "preimages")
+	if err != nil || !ok {
+		*pis = nil
+		return err
+	}
+	*pis = make([]order.Preimage, n)
+	for i := range *pis {
+		copy((*pis)[i][:], b[i*order.PreimageSize:])
+	}
+	return nil
+}
+
+// In a table, a []order.Commitment is stored as a BLOB of the concatenated
+// commitments.
+type commitments []order.Commitment
+
+// Value implements the sql/driver.Valuer interface.
+func (cs commitments) Value() (driver.Value, error) {
+	if cs == nil {
+		return nil, nil
+	}
+	return fixedBlobs(len(cs), order.CommitmentSize, func(i int) []byte { return cs[i][:] }), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (cs *commitments) Scan(src any) error {
+	b, n, ok, err := scanFixedBlobs(src, order.CommitmentSize, "commitments")
+	if err != nil || !ok {
+		*cs = nil
+		return err
+	}
+	*cs = make([]order.Commitment, n)
+	for i := range *cs {
+		copy((*cs)[i][:], b[i*order.CommitmentSize:])
+	}
+	return nil
+}
+
+// Wrap the CoinID slice to implement custom Scanner and Valuer.
+type dbCoins []order.CoinID
+
+// Value implements the sql/driver.Valuer interface. The coin IDs are encoded as
+// L0|ID0|L1|ID1|... where | is simple concatenation, Ln is the length of the
+// nth coin ID, and IDn is the bytes of the nth coinID.
+func (coins dbCoins) Value() (driver.Value, error) {
+	if len(coins) == 0 {
+		return []byte{}, nil
+	}
+	lenGuess := len(coins[0])
+	b := make([]byte, 0, len(coins)*(lenGuess+1))
+	for _, coin := range coins {
+		b = append(b, byte(len(coin)))
+		b = append(b, coin...)
+	}
+	return b, nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (coins *dbCoins) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok || len(b) == 0 { // an empty BLOB may be returned as NULL
+		*coins = dbCoins{}
+		return nil
+	}
+	c := make(dbCoins, 0, len(b)/(int(b[0])+1))
+	for len(b) > 0 {
+		cLen := int(b[0])
+		if cLen == 0 {
+			return fmt.Errorf("zero-length coin ID indicated")
+		}
+		if len(b) < cLen+1 {
+			return fmt.Errorf("too many bytes indicated")
+		}
+
+		// Deep copy the coin ID (a slice) since the backing buffer may be
+		// reused.
+		bc := make([]byte, cLen)
+		copy(bc, b[1:cLen+1])
+		c = append(c, bc)
+
+		b = b[cLen+1:]
+	}
+
+	*coins = c
+	return nil
+}
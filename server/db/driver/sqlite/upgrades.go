@@ -0,0 +1,142 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/server/db/driver/sqlite/internal"
+)
+
+// dbVersion is the version of the table scheme. The SQLite scheme starts with
+// the equivalent of the pg driver's version 7 scheme at version 0.
+const dbVersion = 2
+
+// The number of upgrades defined MUST be equal to dbVersion. The upgrade at
+// index i upgrades the DB from version i to i+1.
+var upgrades = []func(db *sql.Tx) error{
+	// v1 upgrade adds the preimages and missed_commits columns to the epochs
+	// tables.
+	v1Upgrade,
+
+	// v2 upgrade adds the aSwapConf and bSwapConf columns to the matches
+	// tables.
+	v2Upgrade,
+}
+
+// v1Upgrade adds the preimages and missed_commits columns to the epochs table
+// of each market. The columns are NULL for epochs processed before the
+// upgrade.
+func v1Upgrade(tx *sql.Tx) error {
+	mkts, err := loadMarkets(tx, fullTableName("", marketsTableName))
+	if err != nil {
+		return fmt.Errorf("failed to read markets table: %w", err)
+	}
+	for _, mkt := range mkts {
+		epochsTable := fullEpochsTableName(mkt.Name)
+		for _, col := range []string{"preimages", "missed_commits"} {
+			if _, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s BLOB;", epochsTable, col)); err != nil {
+				return fmt.Errorf("failed to add %s column to %s: %w", col, epochsTable, err)
+			}
+		}
+	}
+	return nil
+}
+
+// v2Upgrade adds the aSwapConf and bSwapConf columns to the matches table of
+// each market. The columns are NULL for matches made before the upgrade.
+func v2Upgrade(tx *sql.Tx) error {
+	mkts, err := loadMarkets(tx, fullTableName("", marketsTableName))
+	if err != nil {
+		return fmt.Errorf("failed to read markets table: %w", err)
+	}
+	for _, mkt := range mkts {
+		matchesTable := fullMatchesTableName(mkt.Name)
+		for _, col := range []string{"aSwapConf", "bSwapConf"} {
+			if _, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s INTEGER;", matchesTable, col)); err != nil {
+				return fmt.Errorf("failed to add %s column to %s: %w", col, matchesTable, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DBVersion retrieves the database version from the meta table.
+func DBVersion(db *sql.DB) (ver uint32, err error) {
+	err = db.QueryRow(internal.SelectDBVersion).Scan(&ver)
+	return
+}
+
+func setDBVersion(db sqlExecutor, ver uint32) error {
+	n, err := sqlExec(db, internal.SetDBVersion, ver)
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return fmt.Errorf("set the DB version in %d rows instead of 1", n)
+	}
+	return nil
+}
+
+func upgradeDB(ctx context.Context, db *sql.DB) error {
+	current, err := DBVersion(db)
+	if err != nil {
+		return fmt.Errorf("failed to get DB version: %w", err)
+	}
+
+	if current == dbVersion {
+		log.Infof("DCRDEX database ready at version %d", dbVersion)
+		return nil // all upgraded
+	}
+
+	if current > dbVersion {
+		return fmt.Errorf("current DB version %d is newer than highest recognized version %d",
+			current, dbVersion)
+	}
+
+	runUpgradeTx := func(targetVer uint32, up func(db *sql.Tx) error) (err error) {
+		// Canceling the context automatically rolls back the transaction.
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err == nil || errors.Is(err, sql.ErrTxDone) {
+				return
+			}
+			log.Warnf("Rolling back upgrade to version %d", targetVer-1)
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Errorf("Rollback failed: %v", errRollback)
+			}
+		}()
+
+		if err = up(tx); err != nil {
+			return fmt.Errorf("failed to upgrade to db version %d: %w", targetVer, err)
+		}
+
+		if err = setDBVersion(tx, targetVer); err != nil {
+			return fmt.Errorf("failed to set new DB version %d: %w", targetVer, err)
+		}
+
+		return tx.Commit()
+	}
+
+	log.Infof("Upgrading DB scheme from %d to %d", current, len(upgrades))
+	for i, up := range upgrades[current:] {
+		targetVer := current + uint32(i) + 1
+		log.Debugf("Upgrading DB scheme to %d...", targetVer)
+		if err = runUpgradeTx(targetVer, up); err != nil {
+			if errors.Is(err, sql.ErrTxDone) {
+				return fmt.Errorf("upgrade cancelled (rolled back to version %d)", current+uint32(i))
+			}
+			return err
+		}
+	}
+
+	log.Infof("Upgrades complete. DB is at version %d", dbVersion)
+	return nil
+}
@@ -0,0 +1,98 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/db"
+)
+
+func TestUpgrades(t *testing.T) {
+	cfg := testConfig(t)
+	archie, err := NewArchiver(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewArchiver: %v", err)
+	}
+	if err = archie.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Revert the epochs and matches tables to the version 0 scheme.
+	sqlDB, err := sql.Open("sqlite3", cfg.Path)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	dropColumns := func(table string, cols ...string) {
+		t.Helper()
+		for _, col := range cols {
+			if _, err := sqlDB.Exec("ALTER TABLE " + table + " DROP COLUMN " + col + ";"); err != nil {
+				t.Fatalf("error dropping %s column: %v", col, err)
+			}
+		}
+	}
+	for _, mkt := range cfg.MarketCfg {
+		dropColumns(fullEpochsTableName(mkt.Name), "preimages", "missed_commits")
+		dropColumns(fullMatchesTableName(mkt.Name), "aSwapConf", "bSwapConf")
+	}
+	if err = setDBVersion(sqlDB, 0); err != nil {
+		t.Fatalf("setDBVersion: %v", err)
+	}
+	sqlDB.Close()
+
+	archie, err = NewArchiver(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewArchiver (upgrade): %v", err)
+	}
+	defer archie.Close()
+
+	ver, err := DBVersion(archie.db)
+	if err != nil {
+		t.Fatalf("DBVersion: %v", err)
+	}
+	if ver != dbVersion {
+		t.Fatalf("wrong DB version %d, expected %d", ver, dbVersion)
+	}
+
+	var pi order.Preimage
+	err = archie.InsertEpoch(&db.EpochResults{
+		MktBase:        AssetDCR,
+		MktQuote:       AssetBTC,
+		Idx:            1,
+		Dur:            int64(EpochDuration),
+		OrdersRevealed: []order.OrderID{{0x01}},
+		Preimages:      []order.Preimage{pi},
+	})
+	if err != nil {
+		t.Fatalf("InsertEpoch: %v", err)
+	}
+	proof, err := archie.EpochProof(AssetDCR, AssetBTC, 1, int64(EpochDuration))
+	if err != nil {
+		t.Fatalf("EpochProof: %v", err)
+	}
+	if len(proof.Preimages) != 1 {
+		t.Fatalf("expected 1 preimage, got %d", len(proof.Preimages))
+	}
+
+	maker := newLimitOrder(true, 4_900_000, 1, 0)
+	bookOrder(t, archie, maker)
+	match := newMatch(maker, newLimitOrder(false, 5_000_000, 1, 10), LotSize, order.EpochID{Idx: 2, Dur: EpochDuration})
+	if err = archie.InsertMatch(match); err != nil {
+		t.Fatalf("InsertMatch: %v", err)
+	}
+	mid := db.MarketMatchID{MatchID: match.ID(), Base: AssetDCR, Quote: AssetBTC}
+	if err = archie.SaveSwapConfs(mid, 1, 2); err != nil {
+		t.Fatalf("SaveSwapConfs: %v", err)
+	}
+	_, swapData, err := archie.SwapData(mid)
+	if err != nil {
+		t.Fatalf("SwapData: %v", err)
+	}
+	if swapData.SwapConfA != 1 || swapData.SwapConfB != 2 {
+		t.Fatalf("wrong swap confs %d, %d", swapData.SwapConfA, swapData.SwapConfB)
+	}
+}
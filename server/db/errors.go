@@ -57,6 +57,7 @@ const (
 	ErrAccountUnknown
 	ErrAccountBadFeeInfo
 	ErrUnknownFeeKey
+	ErrUnknownEpoch
 )
 
 func (ae ArchiveError) Error() string {
@@ -84,6 +85,8 @@ func (ae ArchiveError) Error() string {
 		desc = "mismatching fee address or asset"
 	case ErrUnknownFeeKey:
 		desc = "unknown fee key"
+	case ErrUnknownEpoch:
+		desc = "unknown epoch"
 	}
 
 	if ae.Detail == "" {
@@ -172,3 +175,10 @@ func IsErrUnknownFeeKey(err error) bool {
 	var errA ArchiveError
 	return errors.As(err, &errA) && errA.Code == ErrUnknownFeeKey
 }
+
+// IsErrEpochUnknown returns true if the error is of type ArchiveError and has
+// code ErrUnknownEpoch.
+func IsErrEpochUnknown(err error) bool {
+	var errA ArchiveError
+	return errors.As(err, &errA) && errA.Code == ErrUnknownEpoch
+}
@@ -15,7 +15,9 @@ import (
 
 // EpochResults represents the outcome of epoch order processing, including
 // preimage collection, and computation of commitment checksum and shuffle seed.
-// MatchTime is the time at which order matching is executed.
+// MatchTime is the time at which order matching is executed. OrdersRevealed is
+// in shuffled order, and Preimages and MissedCommits correspond to
+// OrdersRevealed and OrdersMissed, respectively.
 type EpochResults struct {
 	MktBase, MktQuote uint32
 	Idx               int64
@@ -24,7 +26,9 @@ type EpochResults struct {
 	CSum              []byte
 	Seed              []byte
 	OrdersRevealed    []order.OrderID
+	Preimages         []order.Preimage
 	OrdersMissed      []order.OrderID
+	MissedCommits     []order.Commitment
 	MatchVolume       uint64
 	QuoteVolume       uint64
 	BookBuys          uint64
@@ -39,6 +43,24 @@ type EpochResults struct {
 	EndRate           uint64
 }
 
+// EpochProof is the stored match proof for an epoch. With it, the commitment
+// checksum, shuffle seed, and shuffled queue order can be independently
+// verified. OrdersRevealed is the queue in the order it was matched, with
+// Preimages in the same order. MissedCommits are the commitments of the
+// OrdersMissed. Epochs processed before preimages were stored with the epoch
+// have nil Preimages and MissedCommits.
+type EpochProof struct {
+	Idx            int64
+	Dur            int64
+	MatchTime      int64
+	CSum           []byte
+	Seed           []byte
+	OrdersRevealed []order.OrderID
+	Preimages      []order.Preimage
+	OrdersMissed   []order.OrderID
+	MissedCommits  []order.Commitment
+}
+
 // OrderStatus is the current status of an order.
 type OrderStatus struct {
 	ID     order.OrderID
@@ -72,12 +94,20 @@ type DEXArchivist interface {
 	// backend error. Use LastErr to get the error.
 	Fatal() <-chan struct{}
 
+	// Ping checks that the database is reachable.
+	Ping() error
+
 	// Close should gracefully shutdown the backend, returning when complete.
 	Close() error
 
 	// InsertEpoch stores the results of a newly-processed epoch.
 	InsertEpoch(ed *EpochResults) error
 
+	// EpochProof retrieves the match proof for a processed epoch. If the
+	// epoch is not stored, an ArchiveError with code ErrUnknownEpoch is
+	// returned.
+	EpochProof(base, quote uint32, epochIdx, epochDur int64) (*EpochProof, error)
+
 	// LastEpochRate gets the EndRate of the last EpochResults inserted for the
 	// market. If the database is empty, no error and a rate of zero are
 	// returned.
@@ -87,12 +117,21 @@ type DEXArchivist interface {
 	LoadEpochStats(uint32, uint32, []*candles.Cache) error
 	LastCandleEndStamp(base, quote uint32, candleDur uint64) (uint64, error)
 	InsertCandles(base, quote uint32, dur uint64, cs []*candles.Candle) error
+	// CandleHistory retrieves up to limit stored candles of the given
+	// duration that ended after the after stamp and no later than the through
+	// stamp, oldest first. Stamps are in milliseconds.
+	CandleHistory(base, quote uint32, candleDur, after, through uint64, limit int) ([]*candles.Candle, error)
 
 	OrderArchiver
 	AccountArchiver
 	KeyIndexer
 	MatchArchiver
 	SwapArchiver
+	TradingFeeArchiver
+	ReportArchiver
+	ArchivePruner
+	HistoryArchiver
+	SettingsArchiver
 }
 
 // OrderArchiver is the interface required for storage and retrieval of all
@@ -266,6 +305,26 @@ type AccountArchiver interface {
 
 	// AccountInfo returns data for an account.
 	AccountInfo(account.AccountID) (*Account, error)
+
+	// BanScore retrieves the account's stored BanScore. If the account has no
+	// ban score, the returned BanScore is nil and the error is nil.
+	BanScore(account.AccountID) (*BanScore, error)
+	// SetBanScore stores the account's BanScore, replacing any stored score.
+	SetBanScore(account.AccountID, *BanScore) error
+}
+
+// BanScore is an account's accumulated conduct violation points and any
+// resulting suspension or ban. The score decays over time, so it is only
+// meaningful with the Stamp of when it was computed.
+type BanScore struct {
+	Score float64
+	// Stamp is when Score was last updated.
+	Stamp time.Time
+	// SuspendedUntil is when a temporary suspension ends. The zero time means
+	// the account has not been suspended.
+	SuspendedUntil time.Time
+	// Banned indicates that the account is permanently banned.
+	Banned bool
 }
 
 // MatchData represents an order pair match, but with just the order IDs instead
@@ -334,6 +393,11 @@ type SwapData struct {
 	RedeemAAckSig    []byte // B's signature of redeem A data
 	RedeemBCoinID    []byte
 	RedeemBTime      int64
+	// SwapConfA and SwapConfB are the number of confirmations required for
+	// contracts A and B, fixed when the match was made. They are zero for
+	// matches made before they were recorded.
+	SwapConfA uint32
+	SwapConfB uint32
 }
 
 // SwapDataFull combines a MatchData, SwapData, and the Base/Quote asset IDs.
@@ -398,6 +462,126 @@ type MatchArchiver interface {
 	MatchStatuses(aid account.AccountID, base, quote uint32, matchIDs []order.MatchID) ([]*MatchStatus, error)
 }
 
+// TradingFee is a trading fee accrued by one party of a match.
+type TradingFee struct {
+	MatchID order.MatchID
+	Account account.AccountID
+	Maker   bool
+	AssetID uint32 // the asset received by the party, in which the fee is denominated
+	Amount  uint64
+	Time    int64 // match completion time, unix ms
+}
+
+// TradingFeeArchiver is the interface required for storage and retrieval of
+// accrued trading fees.
+type TradingFeeArchiver interface {
+	// InsertTradingFees records the trading fees accrued for matches on the
+	// market.
+	InsertTradingFees(base, quote uint32, fees []*TradingFee) error
+	// MarketTradingFees sums the trading fees accrued on the market, by asset.
+	MarketTradingFees(base, quote uint32) (map[uint32]uint64, error)
+	// AccountTradingFees sums the trading fees accrued by the account on all
+	// markets, by asset.
+	AccountTradingFees(aid account.AccountID) (map[uint32]uint64, error)
+}
+
+// MarketStats summarizes the trade matches made on a market in a time range.
+type MarketStats struct {
+	Matches     uint32
+	Volume      uint64 // base asset
+	QuoteVolume uint64
+	// FailedSwaps is the number of matches that were revoked before the swap
+	// completed.
+	FailedSwaps uint32
+	// Fees are the trading fees accrued, by asset ID.
+	Fees map[uint32]uint64
+}
+
+// ReportArchiver is the interface required to retrieve the statistics for
+// exchange reports. Time ranges include start and exclude end.
+type ReportArchiver interface {
+	// MarketStats summarizes the trade matches made on the market in the time
+	// range.
+	MarketStats(base, quote uint32, start, end time.Time) (*MarketStats, error)
+	// NewAccounts counts the accounts created in the time range.
+	NewAccounts(start, end time.Time) (uint32, error)
+}
+
+// PruneResult is the number of archived records deleted from a market by
+// PruneMarket.
+type PruneResult struct {
+	Matches      int64 `json:"matches"`
+	CancelOrders int64 `json:"cancelOrders"`
+	Epochs       int64 `json:"epochs"`
+	EpochReports int64 `json:"epochReports"`
+}
+
+// ArchivePruner is the interface required to delete old archived data.
+type ArchivePruner interface {
+	// PruneMarket deletes the market's inactive matches, archived cancel
+	// orders, and epoch match proofs from before the given time, and the epoch
+	// reports that ended before reportsBefore. Active matches, and the epochs
+	// in which they were made, are never deleted. Neither are the keep most
+	// recent matches and cancel orders of each account, which are used to
+	// score the accounts.
+	PruneMarket(base, quote uint32, before, reportsBefore time.Time, keep int) (*PruneResult, error)
+}
+
+// HistoryFilter selects the archived trade history of a market.
+type HistoryFilter struct {
+	Base, Quote uint32
+	// Account limits the history to the orders and matches of one account if
+	// it is not nil.
+	Account *account.AccountID
+	// Start and End are the time range, including Start and excluding End.
+	// Orders are selected by their server time, and matches by the start of
+	// their epoch.
+	Start, End time.Time
+}
+
+// HistoryArchiver is the interface required to export trade history.
+type HistoryArchiver interface {
+	// MatchHistory streams the trade matches selected by the filter into the
+	// provided function, oldest first.
+	MatchHistory(filter *HistoryFilter, f func(*MatchDataWithCoins) error) (int, error)
+	// OrderHistory streams the orders selected by the filter, active or
+	// archived, into the provided function. The trade orders are streamed
+	// first, oldest first, followed by the cancel orders.
+	OrderHistory(filter *HistoryFilter, f func(order.Order, order.OrderStatus) error) (int, error)
+}
+
+// AuthSettings are the account conduct settings that may be changed while the
+// server is running. Stored settings take precedence over those in the server
+// configuration.
+type AuthSettings struct {
+	CancelThreshold  float64
+	FreeCancels      bool
+	PenaltyThreshold uint32
+	// GraceLimit is nil if the grace limit is derived from the
+	// CancelThreshold.
+	GraceLimit       *uint32
+	SuspensionPeriod time.Duration
+	BanScoreHalfLife time.Duration
+	// Stamp is when the settings were stored.
+	Stamp time.Time
+}
+
+// SettingsArchiver is the interface required to persist settings changed at
+// runtime.
+type SettingsArchiver interface {
+	// AuthSettings retrieves the stored AuthSettings. If none are stored, the
+	// returned settings are nil and the error is nil.
+	AuthSettings() (*AuthSettings, error)
+	// SetAuthSettings stores the AuthSettings, replacing any stored settings.
+	SetAuthSettings(*AuthSettings) error
+	// MarketSwapConfs retrieves the stored per-market swapConf overrides,
+	// keyed by market name and then asset ID.
+	MarketSwapConfs() (map[string]map[uint32]uint32, error)
+	// SetMarketSwapConf stores the swapConf override for the asset on the
+	// named market. A swapConf of zero removes the stored override.
+	SetMarketSwapConf(mktName string, assetID, swapConf uint32) error
+}
+
 // SwapArchiver is the interface required for storage and retrieval of swap
 // counterparty data.
 //
@@ -423,6 +607,10 @@ type SwapArchiver interface {
 	// SwapData retrieves the swap/match status and the current SwapData.
 	SwapData(mid MarketMatchID) (order.MatchStatus, *SwapData, error)
 
+	// SaveSwapConfs records the number of confirmations required for the swap
+	// contracts of party A (the initiator) and party B (the participant).
+	SaveSwapConfs(mid MarketMatchID, swapConfA, swapConfB uint32) error
+
 	// Match acknowledgement message signatures.
 
 	// SaveMatchAckSigA records the match data acknowledgement signature from
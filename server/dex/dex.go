@@ -6,6 +6,7 @@ package dex
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -24,9 +25,12 @@ import (
 	"decred.org/dcrdex/dex/fiatrates"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/dex/txfee"
+	"decred.org/dcrdex/dex/webhook"
 	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/server/apidata"
 	"decred.org/dcrdex/server/asset"
+	"decred.org/dcrdex/server/asset/plugin"
 	"decred.org/dcrdex/server/auth"
 	"decred.org/dcrdex/server/coinlock"
 	"decred.org/dcrdex/server/comms"
@@ -46,9 +50,13 @@ const (
 	PreAPIVersion  = iota
 	BondAPIVersion // when we drop the legacy reg fee proto
 	V1APIVersion
+	WorstRateAPIVersion // market orders may commit to a worst rate
+	// CancelTargetAPIVersion is when cancel orders for unknown targets are
+	// rejected with msgjson.UnknownCancelTargetError.
+	CancelTargetAPIVersion = msgjson.UnknownCancelTargetAPIVersion
 
 	// APIVersion is the current API version.
-	APIVersion = V1APIVersion
+	APIVersion = CancelTargetAPIVersion
 )
 
 // Asset represents an asset in the Config file.
@@ -60,6 +68,9 @@ type Asset struct {
 	MaxFeeRate  uint64 `json:"maxFeeRate"`
 	SwapConf    uint32 `json:"swapConf"`
 	ConfigPath  string `json:"configPath"`
+	// RegFee, RegConfs, and RegXPub configured the legacy registration fee,
+	// which has been replaced by fidelity bonds. They are ignored except to
+	// warn the operator. Use BondAmt and BondConfs instead.
 	RegFee      uint64 `json:"regFee,omitempty"`
 	RegConfs    uint32 `json:"regConfs,omitempty"`
 	RegXPub     string `json:"regXPub,omitempty"`
@@ -67,6 +78,15 @@ type Asset struct {
 	BondConfs   uint32 `json:"bondConfs,omitempty"`
 	Disabled    bool   `json:"disabled"`
 	NodeRelayID string `json:"nodeRelayID,omitempty"`
+	// Plugin is the path of an executable that runs the asset's backend as
+	// an external process, for assets with no backend built into the server.
+	// PluginArgs are passed to the executable.
+	Plugin     string   `json:"plugin,omitempty"`
+	PluginArgs []string `json:"pluginArgs,omitempty"`
+	// FeeOracle configures the fee rate sources of the asset's backend.
+	// Backends that only use their node's estimate ignore it. Not valid for
+	// tokens.
+	FeeOracle *txfee.OracleConfig `json:"feeOracle,omitempty"`
 }
 
 // Market represents the markets specified in the Config file.
@@ -79,6 +99,20 @@ type Market struct {
 	Duration   uint64  `json:"epochDuration"`
 	MBBuffer   float64 `json:"marketBuyBuffer"`
 	Disabled   bool    `json:"disabled"`
+	// MakerFeeRate and TakerFeeRate are optional trading fees accrued by the
+	// maker and taker of each match, in parts per million of the amount
+	// received. The fees are recorded when a match completes, only for the
+	// operator's accounting. They are not deducted from swaps or charged to
+	// accounts, and are not advertised to clients.
+	MakerFeeRate uint64 `json:"makerFeeRate,omitempty"`
+	TakerFeeRate uint64 `json:"takerFeeRate,omitempty"`
+	// BaseSwapConf and QuoteSwapConf optionally override the swapConf of the
+	// base and quote assets for swaps on this market.
+	BaseSwapConf  uint32 `json:"baseSwapConf,omitempty"`
+	QuoteSwapConf uint32 `json:"quoteSwapConf,omitempty"`
+	// DisplayBand optionally bands the quantities of large booked orders
+	// shown to subscribers, in lots.
+	DisplayBand uint64 `json:"displayBand,omitempty"`
 }
 
 // Config is a market and asset configuration file.
@@ -168,6 +202,15 @@ func loadMarketConf(net dex.Network, src io.Reader) ([]*dex.MarketInfo, []*Asset
 			return nil, nil, fmt.Errorf("max fee rate of 0 is invalid for asset %q", assetConf.Symbol)
 		}
 
+		if assetConf.FeeOracle != nil {
+			if isToken, _ := asset.IsToken(assetID); isToken {
+				return nil, nil, fmt.Errorf("fee oracle configured for token %q", assetConf.Symbol)
+			}
+			if err := assetConf.FeeOracle.Validate(); err != nil {
+				return nil, nil, fmt.Errorf("invalid fee oracle for asset %q: %w", assetConf.Symbol, err)
+			}
+		}
+
 		unused[assetID] = assetConf.Symbol
 		assetMap[assetID] = struct{}{}
 		assets = append(assets, assetConf)
@@ -239,11 +282,19 @@ func loadMarketConf(net dex.Network, src io.Reader) ([]*dex.MarketInfo, []*Asset
 			return nil, nil, fmt.Errorf("parcel size cannot be zero")
 		}
 
+		if mktConf.MakerFeeRate > dex.MaxTradingFeeRate || mktConf.TakerFeeRate > dex.MaxTradingFeeRate {
+			return nil, nil, fmt.Errorf("market (%s, %s) trading fee rates (maker %d, taker %d) exceed the maximum of %d",
+				mktConf.Base, mktConf.Quote, mktConf.MakerFeeRate, mktConf.TakerFeeRate, dex.MaxTradingFeeRate)
+		}
+
 		mkt, err := dex.NewMarketInfoFromSymbols(baseConf.Symbol, quoteConf.Symbol,
 			mktConf.LotSize, mktConf.RateStep, mktConf.Duration, mktConf.ParcelSize, mktConf.MBBuffer)
 		if err != nil {
 			return nil, nil, err
 		}
+		mkt.MakerFeeRate, mkt.TakerFeeRate = mktConf.MakerFeeRate, mktConf.TakerFeeRate
+		mkt.BaseSwapConf, mkt.QuoteSwapConf = mktConf.BaseSwapConf, mktConf.QuoteSwapConf
+		mkt.DisplayBand = mktConf.DisplayBand
 		markets = append(markets, mkt)
 	}
 
@@ -260,6 +311,11 @@ func loadMarketConf(net dex.Network, src io.Reader) ([]*dex.MarketInfo, []*Asset
 
 // DBConf groups the database configuration parameters.
 type DBConf struct {
+	// Driver is the DB driver, "pg" or "sqlite". The default is "pg".
+	Driver string
+	// SQLitePath is the DB file for the sqlite driver.
+	SQLitePath string
+	// The remaining fields are for the pg driver.
 	DBName       string
 	User         string
 	Pass         string
@@ -322,9 +378,9 @@ func ValidateConfigFile(cfgPath string, net dex.Network, log dex.Logger) error {
 			return fmt.Errorf("no asset ID found for symbol %q", a.Symbol)
 		}
 
-		minLotSize, minBondSize, found := asset.Minimums(assetID, a.MaxFeeRate)
-		if !found {
-			return fmt.Errorf("no asset registered for %s (%d)", a.Symbol, assetID)
+		minLotSize, minBondSize, err := asset.Minimums(assetID, a.MaxFeeRate)
+		if err != nil {
+			return fmt.Errorf("error getting minimums for %s (%d): %w", a.Symbol, assetID, err)
 		}
 
 		ui, err := asset.UnitInfo(assetID)
@@ -408,21 +464,32 @@ type RPCConfig = comms.RPCConfig
 
 // DexConf is the configuration data required to create a new DEX.
 type DexConf struct {
-	DataDir          string
-	LogBackend       *dex.LoggerMaker
-	Markets          []*dex.MarketInfo
-	Assets           []*Asset
-	Network          dex.Network
-	DBConf           *DBConf
-	BroadcastTimeout time.Duration
-	TxWaitExpiration time.Duration
-	CancelThreshold  float64
-	FreeCancels      bool
-	PenaltyThreshold uint32
-	DEXPrivKey       *secp256k1.PrivateKey
-	CommsCfg         *RPCConfig
-	NoResumeSwaps    bool
-	NodeRelayAddr    string
+	DataDir             string
+	LogBackend          *dex.LoggerMaker
+	Markets             []*dex.MarketInfo
+	Assets              []*Asset
+	Network             dex.Network
+	DBConf              *DBConf
+	BroadcastTimeout    time.Duration
+	TxWaitExpiration    time.Duration
+	CancelThreshold     float64
+	FreeCancels         bool
+	PenaltyThreshold    uint32
+	MinClientAPIVersion uint16
+	DEXPrivKey          *secp256k1.PrivateKey
+	CommsCfg            *RPCConfig
+	NoResumeSwaps       bool
+	NodeRelayAddr       string
+	// ArchiveRetention is how long archived matches, cancel orders, and epoch
+	// data are kept before they are pruned. Zero disables automatic pruning.
+	ArchiveRetention time.Duration
+	// WebhookURLs are the endpoints notified of account registrations, bonds,
+	// and penalties. Requests are signed with WebhookSecret.
+	WebhookURLs   []string
+	WebhookSecret string
+	// BanScore configures the graduated penalties for accumulated conduct
+	// violations. If nil, auth.DefaultBanScoreConfig is used.
+	BanScore *auth.BanScoreConfig
 }
 
 type signer struct {
@@ -463,16 +530,19 @@ type DEX struct {
 	bookRouter  *market.BookRouter
 	subsystems  []subsystem
 	server      *comms.Server
+	reporter    *reporter // nil if there is no data directory
+	pruner      *pruner
+	health      *healthCache
 
 	configRespMtx sync.RWMutex
 	configResp    *configResponse
 }
 
-// configResponse is defined here to leave open the possibility for hot
-// adjustable parameters while storing a pre-encoded config response message. An
-// update method will need to be defined in the future for this purpose.
+// configResponse holds the config response message, pre-encoded for quick
+// responses. Hot adjustable parameters are changed with the set methods, which
+// re-encode the message.
 type configResponse struct {
-	configMsg *msgjson.ConfigResult // constant for now
+	configMsg *msgjson.ConfigResult
 	configEnc json.RawMessage
 }
 
@@ -481,6 +551,7 @@ func newConfigResponse(cfg *DexConf, bondAssets map[string]*msgjson.BondAsset,
 
 	configMsg := &msgjson.ConfigResult{
 		APIVersion:       uint16(APIVersion),
+		MinAPIVersion:    cfg.MinClientAPIVersion,
 		DEXPubKey:        cfg.DEXPrivKey.PubKey().SerializeCompressed(),
 		BroadcastTimeout: uint64(cfg.BroadcastTimeout.Milliseconds()),
 		CancelMax:        cfg.CancelThreshold,
@@ -491,6 +562,7 @@ func newConfigResponse(cfg *DexConf, bondAssets map[string]*msgjson.BondAsset,
 		BinSizes:         candles.BinSizes,
 		PenaltyThreshold: cfg.PenaltyThreshold,
 		MaxScore:         auth.ScoringMatchLimit,
+		FreeCancels:      cfg.FreeCancels,
 	}
 
 	// NOTE/TODO: To include active epoch in the market status objects, we need
@@ -535,6 +607,32 @@ func (cr *configResponse) setMktResume(name string, startEpoch uint64) (epochLen
 	return 0
 }
 
+// setMktSwapConf sets the swapConf override for the asset on the named market,
+// returning a copy of the updated market.
+func (cr *configResponse) setMktSwapConf(name string, assetID, swapConf uint32) *msgjson.Market {
+	for _, mkt := range cr.configMsg.Markets {
+		if mkt.Name == name {
+			if assetID == mkt.Base {
+				mkt.BaseSwapConf = swapConf
+			} else {
+				mkt.QuoteSwapConf = swapConf
+			}
+			cr.remarshal()
+			mktCopy := *mkt
+			return &mktCopy
+		}
+	}
+	log.Errorf("Failed to update swapConf for market %q", name)
+	return nil
+}
+
+func (cr *configResponse) setAuthThresholds(update *msgjson.ConfigUpdate) {
+	cr.configMsg.CancelMax = update.CancelMax
+	cr.configMsg.FreeCancels = update.FreeCancels
+	cr.configMsg.PenaltyThreshold = update.PenaltyThreshold
+	cr.remarshal()
+}
+
 func (cr *configResponse) remarshal() {
 	encResult, err := json.Marshal(cr.configMsg)
 	if err != nil {
@@ -626,6 +724,9 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	// shutdown in sequence with the other subsystems.
 	ctxDB, cancelDB := context.WithCancel(context.Background())
 	var ready bool
+	// plugins are the asset backend plugins started by plugin.Load, which
+	// must be stopped and unregistered if setup fails.
+	var plugins []*plugin.Driver
 	defer func() {
 		if ready {
 			return
@@ -633,11 +734,20 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		for _, ss := range subsystems {
 			ss.stop()
 		}
+		for _, p := range plugins {
+			p.Unload()
+		}
 		// If the DB is running, kill it too.
 		cancelDB()
 	}()
 
+	if cfg.MinClientAPIVersion > APIVersion {
+		return nil, fmt.Errorf("minimum client API version %d exceeds the server's API version %d",
+			cfg.MinClientAPIVersion, APIVersion)
+	}
+
 	// Check each configured asset.
+	assetLogger := cfg.LogBackend.Logger("ASSET")
 	assetIDs := make([]uint32, len(cfg.Assets))
 	var nodeRelayIDs []string
 	for i, assetConf := range cfg.Assets {
@@ -668,20 +778,47 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 			nodeRelayIDs = append(nodeRelayIDs, assetConf.NodeRelayID)
 		}
 
+		// Start the plugin, which registers the asset's driver.
+		if assetConf.Plugin != "" {
+			p, err := plugin.Load(assetID, assetConf.Plugin, assetConf.PluginArgs, cfg.Network,
+				assetLogger.SubLogger(symbol+"-plugin"))
+			if err != nil {
+				return nil, fmt.Errorf("error loading plugin for asset %q: %w", symbol, err)
+			}
+			plugins = append(plugins, p)
+			log.Infof("Loaded plugin %q for asset %q", assetConf.Plugin, symbol)
+		}
+
 		assetIDs[i] = assetID
 	}
 
-	// Create DEXArchivist with the pg DB driver. The fee Addressers require the
-	// archivist for key index storage and retrieval.
-	pgCfg := &pg.Config{
-		Host:         cfg.DBConf.Host,
-		Port:         strconv.Itoa(int(cfg.DBConf.Port)),
-		User:         cfg.DBConf.User,
-		Pass:         cfg.DBConf.Pass,
-		DBName:       cfg.DBConf.DBName,
-		ShowPGConfig: cfg.DBConf.ShowPGConfig,
-		QueryTimeout: 20 * time.Minute,
-		MarketCfg:    cfg.Markets,
+	// Create DEXArchivist with the configured DB driver. The fee Addressers
+	// require the archivist for key index storage and retrieval.
+	var dbDriver string
+	var dbCfg any
+	switch cfg.DBConf.Driver {
+	case "", "pg":
+		dbDriver = "pg"
+		dbCfg = &pg.Config{
+			Host:         cfg.DBConf.Host,
+			Port:         strconv.Itoa(int(cfg.DBConf.Port)),
+			User:         cfg.DBConf.User,
+			Pass:         cfg.DBConf.Pass,
+			DBName:       cfg.DBConf.DBName,
+			ShowPGConfig: cfg.DBConf.ShowPGConfig,
+			QueryTimeout: 20 * time.Minute,
+			MarketCfg:    cfg.Markets,
+		}
+	case "sqlite":
+		dbDriver = "sqlite"
+		// The sqlite driver is only registered in builds that include it.
+		dbCfg = &db.FileConfig{
+			Path:         cfg.DBConf.SQLitePath,
+			QueryTimeout: 20 * time.Minute,
+			MarketCfg:    cfg.Markets,
+		}
+	default:
+		return nil, fmt.Errorf("unknown DB driver %q", cfg.DBConf.Driver)
 	}
 	// After DEX construction, the storage subsystem should be stopped
 	// gracefully with its Close method, and in coordination with other
@@ -696,7 +833,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		case <-running: // DB shutdown now only via dex.Stop=>db.Close
 		}
 	}()
-	storage, err := db.Open(ctxDB, "pg", pgCfg)
+	storage, err := db.Open(ctxDB, dbDriver, dbCfg)
 	if err != nil {
 		return nil, fmt.Errorf("db.Open: %w", err)
 	}
@@ -747,7 +884,6 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	lockableAssets := make(map[uint32]*swap.SwapperAsset, len(cfg.Assets))
 	backedAssets := make(map[uint32]*asset.BackedAsset, len(cfg.Assets))
 	cfgAssets := make([]*msgjson.Asset, 0, len(cfg.Assets))
-	assetLogger := cfg.LogBackend.Logger("ASSET")
 	txDataSources := make(map[uint32]auth.TxDataSource)
 	feeMgr := NewFeeManager()
 	addAsset := func(assetID uint32, assetConf *Asset) error {
@@ -785,6 +921,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 				Logger:     logger,
 				Net:        cfg.Network,
 				RelayAddr:  relayAddrs[assetConf.NodeRelayID],
+				FeeOracle:  assetConf.FeeOracle,
 			}
 			be, err = asset.Setup(cfg)
 			if err != nil {
@@ -797,6 +934,13 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 			return fmt.Errorf("failed to start asset %q: %w", symbol, err)
 		}
 
+		if assetConf.RegFee > 0 || assetConf.RegConfs > 0 || assetConf.RegXPub != "" {
+			log.Warnf("Registration fee settings for %s are no longer supported and will be ignored. "+
+				"Set bondAmt and bondConfs to accept fidelity bonds in %s.", symbol, symbol)
+		}
+		if (assetConf.BondAmt > 0) != (assetConf.BondConfs > 0) {
+			log.Warnf("Bonds will not be accepted using %s. Both bondAmt and bondConfs must be set.", symbol)
+		}
 		if assetConf.BondAmt > 0 && assetConf.BondConfs > 0 {
 			// Make sure we can check on fee transactions.
 			bc, ok := be.(Bonder)
@@ -851,6 +995,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 			MaxFeeRate: assetConf.MaxFeeRate,
 			SwapConf:   uint16(assetConf.SwapConf),
 			UnitInfo:   unitInfo,
+			Dust:       asset.DustPolicy(assetID),
 		})
 
 		txDataSources[assetID] = be.TxData
@@ -918,6 +1063,50 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		cfg.PenaltyThreshold = auth.DefaultPenaltyThreshold
 	}
 
+	// Account conduct settings changed at runtime override the configured
+	// values.
+	authSettings, err := storage.AuthSettings()
+	if err != nil {
+		return nil, fmt.Errorf("error loading stored auth settings: %w", err)
+	}
+	var graceLimit *uint32
+	if authSettings != nil {
+		log.Infof("Using account conduct settings stored %v, overriding the configured values",
+			authSettings.Stamp.Format(time.RFC3339))
+		cfg.CancelThreshold = authSettings.CancelThreshold
+		cfg.FreeCancels = authSettings.FreeCancels
+		cfg.PenaltyThreshold = authSettings.PenaltyThreshold
+		graceLimit = authSettings.GraceLimit
+		banCfg := auth.DefaultBanScoreConfig()
+		if cfg.BanScore != nil {
+			bc := *cfg.BanScore
+			banCfg = &bc
+		}
+		banCfg.SuspensionPeriod = authSettings.SuspensionPeriod
+		banCfg.HalfLife = authSettings.BanScoreHalfLife
+		cfg.BanScore = banCfg
+	}
+
+	// Likewise for the per-market swapConf overrides.
+	storedSwapConfs, err := storage.MarketSwapConfs()
+	if err != nil {
+		return nil, fmt.Errorf("error loading stored swapConf overrides: %w", err)
+	}
+	for _, mktInf := range cfg.Markets {
+		mktConfs, found := storedSwapConfs[mktInf.Name]
+		if !found {
+			continue
+		}
+		if swapConf, found := mktConfs[mktInf.Base]; found {
+			log.Infof("Using stored swapConf %d for %s on market %s", swapConf, dex.BipIDSymbol(mktInf.Base), mktInf.Name)
+			mktInf.BaseSwapConf = swapConf
+		}
+		if swapConf, found := mktConfs[mktInf.Quote]; found {
+			log.Infof("Using stored swapConf %d for %s on market %s", swapConf, dex.BipIDSymbol(mktInf.Quote), mktInf.Name)
+			mktInf.QuoteSwapConf = swapConf
+		}
+	}
+
 	// Client comms RPC server.
 	server, err := comms.NewServer(cfg.CommsCfg)
 	if err != nil {
@@ -926,6 +1115,21 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 
 	dataAPI := apidata.NewDataAPI(storage, server.RegisterHTTP)
 
+	var events auth.EventNotifier
+	if len(cfg.WebhookURLs) > 0 {
+		hooks, err := webhook.NewNotifier(&webhook.Config{
+			URLs:   cfg.WebhookURLs,
+			Secret: cfg.WebhookSecret,
+			Logger: cfg.LogBackend.NewLogger("HOOK", log.Level()),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating webhook notifier: %w", err)
+		}
+		startSubSys("Webhooks", hooks)
+		events = hooks
+		log.Infof("Sending account event notifications to %d webhook(s)", len(cfg.WebhookURLs))
+	}
+
 	authCfg := auth.Config{
 		Storage:          storage,
 		Signer:           signer{cfg.DEXPrivKey},
@@ -937,12 +1141,23 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		MiaUserTimeout:   cfg.BroadcastTimeout,
 		CancelThreshold:  cfg.CancelThreshold,
 		FreeCancels:      cfg.FreeCancels,
+		GraceLimit:       graceLimit,
 		PenaltyThreshold: cfg.PenaltyThreshold,
+		MinAPIVersion:    cfg.MinClientAPIVersion,
+		MaxAPIVersion:    APIVersion,
 		TxDataSources:    txDataSources,
 		Route:            server.Route,
+		Events:           events,
+		BanScore:         cfg.BanScore,
+	}
+	if cfg.BanScore != nil {
+		if err := cfg.BanScore.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid ban score settings: %w", err)
+		}
 	}
 
 	authMgr := auth.NewAuthManager(&authCfg)
+	server.OnRateLimited(authMgr.RateLimited)
 	log.Infof("Cancellation rate threshold %f, new user grace period %d cancels",
 		cfg.CancelThreshold, authMgr.GraceLimit())
 	log.Infof("MIA user order unbook timeout %v", cfg.BroadcastTimeout)
@@ -950,6 +1165,9 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		log.Infof("Cancellations are NOT COUNTED (the cancellation rate threshold is ignored).")
 	}
 	log.Infof("Penalty threshold is %v", cfg.PenaltyThreshold)
+	if cfg.MinClientAPIVersion > 0 {
+		log.Infof("Refusing connections from clients with API version < %d", cfg.MinClientAPIVersion)
+	}
 
 	// Create a swapDone dispatcher for the Swapper.
 	swapDone := func(ord order.Order, match *order.Match, fail bool) {
@@ -961,9 +1179,25 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		markets[name].SwapDone(ord, match, fail)
 	}
 
+	// Per-market swapConf overrides.
+	swapConfs := make(map[[2]uint32]map[uint32]uint32)
+	for _, mktInf := range cfg.Markets {
+		confs := make(map[uint32]uint32, 2)
+		if mktInf.BaseSwapConf > 0 {
+			confs[mktInf.Base] = mktInf.BaseSwapConf
+		}
+		if mktInf.QuoteSwapConf > 0 {
+			confs[mktInf.Quote] = mktInf.QuoteSwapConf
+		}
+		if len(confs) > 0 {
+			swapConfs[[2]uint32{mktInf.Base, mktInf.Quote}] = confs
+		}
+	}
+
 	// Create the swapper.
 	swapperCfg := &swap.Config{
 		Assets:           lockableAssets,
+		SwapConfs:        swapConfs,
 		Storage:          storage,
 		AuthManager:      authMgr,
 		BroadcastTimeout: cfg.BroadcastTimeout,
@@ -980,6 +1214,9 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	if err != nil {
 		return nil, fmt.Errorf("NewSwapper: %w", err)
 	}
+	// While draining, clients with active swaps may still reconnect to finish
+	// them.
+	server.AdmitWhileDraining(swapper.UserSwapping)
 
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -1026,7 +1263,11 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		// Calculate a minimum market rate that avoids dust.
 		// quote_dust = base_lot * min_rate / rate_encoding_factor
 		// => min_rate = quote_dust * rate_encoding_factor * base_lot
-		quoteMinLotSize, _, _ := asset.Minimums(mktInf.Quote, q.Asset.MaxFeeRate)
+		quoteMinLotSize, _, err := asset.Minimums(mktInf.Quote, q.Asset.MaxFeeRate)
+		if err != nil {
+			return nil, fmt.Errorf("error getting minimums for %s to configure market %s: %w",
+				dex.BipIDSymbol(mktInf.Quote), mktInf.Name, err)
+		}
 		minRate := calc.MinimumMarketRate(mktInf.LotSize, quoteMinLotSize)
 
 		mkt, err := market.NewMarket(&market.Config{
@@ -1086,6 +1327,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		startEpochIdx := 1 + now/int64(mkt.EpochDuration())
 		mkt.SetStartEpochIdx(startEpochIdx)
 		bookSources[name] = mkt
+		mktSwapConfs := swapConfs[[2]uint32{mkt.Base(), mkt.Quote()}]
 		cfgMarkets = append(cfgMarkets, &msgjson.Market{
 			Name:            name,
 			Base:            mkt.Base(),
@@ -1095,6 +1337,9 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 			EpochLen:        mkt.EpochDuration(),
 			MarketBuyBuffer: mkt.MarketBuyBuffer(),
 			ParcelSize:      mkt.ParcelSize(),
+			BaseSwapConf:    mktSwapConfs[mkt.Base()],
+			QuoteSwapConf:   mktSwapConfs[mkt.Quote()],
+			DisplayBand:     mkt.DisplayBand(),
 			MarketStatus: msgjson.MarketStatus{
 				StartEpoch: uint64(startEpochIdx),
 			},
@@ -1124,6 +1369,24 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	})
 	startSubSys("OrderRouter", orderRouter)
 
+	// Daily reports are written to the data directory.
+	var rptr *reporter
+	if cfg.DataDir != "" {
+		rptr, err = newReporter(filepath.Join(cfg.DataDir, "reports"), storage, cfg.Markets)
+		if err != nil {
+			return nil, err
+		}
+		startSubSys("Reporter", rptr)
+	}
+
+	archivePruner, err := newPruner(storage, cfg.Markets, cfg.ArchiveRetention)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ArchiveRetention > 0 {
+		startSubSys("Pruner", archivePruner)
+	}
+
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -1144,14 +1407,20 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		bookRouter:  bookRouter,
 		subsystems:  subsystems,
 		server:      server,
+		reporter:    rptr,
+		pruner:      archivePruner,
 		configResp:  cfgResp,
 	}
+	dexMgr.health = newHealthCache(dexMgr.HealthReport)
 
 	server.RegisterHTTP(msgjson.ConfigRoute, dexMgr.handleDEXConfig)
 	server.RegisterHTTP(msgjson.HealthRoute, dexMgr.handleHealthFlag)
 
 	mux := server.Mux()
 
+	// Health check for load balancers and monitoring. Not rate limited.
+	mux.Get("/healthz", dexMgr.health.handleHealthz)
+
 	// Data API endpoints.
 	mux.Route("/api", func(rr chi.Router) {
 		if log.Level() == dex.LevelTrace {
@@ -1163,7 +1432,9 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		rr.Get("/spots", server.NewRouteHandler(msgjson.SpotsRoute))
 		rr.With(candleParamsParser).Get("/candles/{baseSymbol}/{quoteSymbol}/{binSize}", server.NewRouteHandler(msgjson.CandlesRoute))
 		rr.With(candleParamsParser).Get("/candles/{baseSymbol}/{quoteSymbol}/{binSize}/{count}", server.NewRouteHandler(msgjson.CandlesRoute))
+		rr.With(candleHistoryParamsParser).Get("/candlehistory/{baseSymbol}/{quoteSymbol}/{binSize}", server.NewRouteHandler(msgjson.CandleHistoryRoute))
 		rr.With(orderBookParamsParser).Get("/orderbook/{baseSymbol}/{quoteSymbol}", server.NewRouteHandler(msgjson.OrderBookRoute))
+		rr.With(epochProofParamsParser).Get("/epochproof/{baseSymbol}/{quoteSymbol}/{epoch}", server.NewRouteHandler(msgjson.EpochProofRoute))
 	})
 
 	startSubSys("Comms Server", server)
@@ -1363,11 +1634,111 @@ func (dm *DEX) ResumeMarket(name string, asSoonAs time.Time) (startEpoch int64,
 	return
 }
 
-// AccountInfo returns data for an account.
-func (dm *DEX) AccountInfo(aid account.AccountID) (*db.Account, error) {
-	// TODO: consider asking the auth manager for account info, including tier.
-	// connected, tier := dm.authMgr.AcctStatus(aid)
-	return dm.storage.AccountInfo(aid)
+// SetMarketSwapConf sets the number of confirmations required for swaps of
+// the asset on the named market. A swapConf of zero removes the market's
+// override, reverting to the asset's configured swapConf. The override is
+// stored so that it persists across restarts, taking precedence over the
+// configured value, and is broadcast to connected clients. The new requirement
+// applies to matches made after the change. Each match keeps the requirement in
+// effect when it was made, including when it is restored on startup.
+func (dm *DEX) SetMarketSwapConf(name string, assetID, swapConf uint32) error {
+	name = strings.ToLower(name)
+	mkt := dm.markets[name]
+	if mkt == nil {
+		return fmt.Errorf("unknown market %s", name)
+	}
+	if assetID != mkt.Base() && assetID != mkt.Quote() {
+		return fmt.Errorf("asset %d is not traded on market %s", assetID, name)
+	}
+
+	if err := dm.storage.SetMarketSwapConf(name, assetID, swapConf); err != nil {
+		return fmt.Errorf("error storing swapConf: %w", err)
+	}
+
+	dm.swapper.SetMarketSwapConf(mkt.Base(), mkt.Quote(), assetID, swapConf)
+
+	dm.configRespMtx.Lock()
+	mktCfg := dm.configResp.setMktSwapConf(name, assetID, swapConf)
+	dm.configRespMtx.Unlock()
+	if mktCfg == nil {
+		return nil
+	}
+
+	t := dm.authMgr.Thresholds()
+	update := &msgjson.ConfigUpdate{
+		CancelMax:        t.CancelThreshold,
+		FreeCancels:      t.FreeCancels,
+		PenaltyThreshold: t.PenaltyThreshold,
+		Markets:          []*msgjson.Market{mktCfg},
+	}
+	note, err := msgjson.NewNotification(msgjson.ConfigUpdateRoute, update)
+	if err != nil {
+		log.Errorf("Failed to create config update notification: %v", err)
+		return nil
+	}
+	dm.server.Broadcast(note)
+	return nil
+}
+
+// AuthThresholds returns the current account conduct settings.
+func (dm *DEX) AuthThresholds() *auth.Thresholds {
+	return dm.authMgr.Thresholds()
+}
+
+// SetAuthThresholds changes the account conduct settings. The settings are
+// stored so that they persist across restarts, taking precedence over the
+// configured values, and are broadcast to connected clients.
+func (dm *DEX) SetAuthThresholds(t *auth.Thresholds) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	err := dm.storage.SetAuthSettings(&db.AuthSettings{
+		CancelThreshold:  t.CancelThreshold,
+		FreeCancels:      t.FreeCancels,
+		PenaltyThreshold: t.PenaltyThreshold,
+		GraceLimit:       t.GraceLimit,
+		SuspensionPeriod: t.SuspensionPeriod,
+		BanScoreHalfLife: t.BanScoreHalfLife,
+		Stamp:            time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("error storing auth settings: %w", err)
+	}
+	if err = dm.authMgr.SetThresholds(t); err != nil {
+		return err
+	}
+
+	update := &msgjson.ConfigUpdate{
+		CancelMax:        t.CancelThreshold,
+		FreeCancels:      t.FreeCancels,
+		PenaltyThreshold: t.PenaltyThreshold,
+	}
+	dm.configRespMtx.Lock()
+	dm.configResp.setAuthThresholds(update)
+	dm.configRespMtx.Unlock()
+
+	note, err := msgjson.NewNotification(msgjson.ConfigUpdateRoute, update)
+	if err != nil {
+		log.Errorf("Failed to create config update notification: %v", err)
+		return nil
+	}
+	dm.server.Broadcast(note)
+	return nil
+}
+
+// AccountInfo returns data for an account, including active bonds and tier.
+func (dm *DEX) AccountInfo(aid account.AccountID) (*auth.AccountInfo, error) {
+	return dm.authMgr.AccountInfo(aid)
+}
+
+// BanScore returns the account's ban score and standing.
+func (dm *DEX) BanScore(aid account.AccountID) (*auth.BanScoreInfo, error) {
+	return dm.authMgr.BanScoreInfo(aid)
+}
+
+// ClearBanScore resets the account's ban score, lifting any suspension or ban.
+func (dm *DEX) ClearBanScore(aid account.AccountID) error {
+	return dm.authMgr.ClearBanScore(aid)
 }
 
 // ForgiveMatchFail forgives a user for a specific match failure, potentially
@@ -1380,6 +1751,12 @@ func (dm *DEX) CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]by
 	return dm.authMgr.CreatePrepaidBonds(n, strength, durSecs)
 }
 
+// AccountReputation details the account's reputation and the recent conduct
+// from which it is computed.
+func (dm *DEX) AccountReputation(aid account.AccountID) (*msgjson.ReputationReport, error) {
+	return dm.authMgr.ReputationReport(aid)
+}
+
 func (dm *DEX) AccountMatchOutcomesN(aid account.AccountID, n int) ([]*auth.MatchOutcome, error) {
 	return dm.authMgr.AccountMatchOutcomesN(aid, n)
 }
@@ -1398,6 +1775,18 @@ func (dm *DEX) NotifyAll(msg *msgjson.Message) {
 	dm.server.Broadcast(msg)
 }
 
+// MarketTradingFees sums the trading fees accrued on the market with base and
+// quote, by asset.
+func (dm *DEX) MarketTradingFees(base, quote uint32) (map[uint32]uint64, error) {
+	return dm.storage.MarketTradingFees(base, quote)
+}
+
+// AccountTradingFees sums the trading fees accrued by the account on all
+// markets, by asset.
+func (dm *DEX) AccountTradingFees(aid account.AccountID) (map[uint32]uint64, error) {
+	return dm.storage.AccountTradingFees(aid)
+}
+
 // BookOrders returns booked orders for market with base and quote.
 func (dm *DEX) BookOrders(base, quote uint32) ([]*order.LimitOrder, error) {
 	return dm.storage.BookOrders(base, quote)
@@ -1515,12 +1904,75 @@ func (dm *DEX) MarketMatches(base, quote uint32) ([]*MatchData, error) {
 	return matchDatas, nil
 }
 
+// HistoryFilter is an alias for the archivist's trade history filter.
+type HistoryFilter = db.HistoryFilter
+
+// MatchHistory streams the matches selected by the filter, oldest first.
+func (dm *DEX) MatchHistory(filter *HistoryFilter, f func(*MatchData) error) (int, error) {
+	baseAsset := dm.assets[filter.Base]
+	if baseAsset == nil {
+		return 0, fmt.Errorf("asset %d not found", filter.Base)
+	}
+	quoteAsset := dm.assets[filter.Quote]
+	if quoteAsset == nil {
+		return 0, fmt.Errorf("asset %d not found", filter.Quote)
+	}
+	fDB := func(md *db.MatchDataWithCoins) error {
+		return f(convertMatchData(baseAsset.Backend, quoteAsset.Backend, md))
+	}
+	return dm.storage.MatchHistory(filter, fDB)
+}
+
+// OrderHistory streams the trade orders and then the cancel orders selected
+// by the filter, each oldest first.
+func (dm *DEX) OrderHistory(filter *HistoryFilter, f func(order.Order, order.OrderStatus) error) (int, error) {
+	return dm.storage.OrderHistory(filter, f)
+}
+
 // EnableDataAPI can be called via admin API to enable or disable the HTTP data
 // API endpoints.
 func (dm *DEX) EnableDataAPI(yes bool) {
 	dm.server.EnableDataAPI(yes)
 }
 
+// PruneArchive can be called via admin API to delete archived matches, cancel
+// orders, and epoch data older than the retention period. If retention is
+// zero, the configured ArchiveRetention is used.
+func (dm *DEX) PruneArchive(retention time.Duration) (*PruneReport, error) {
+	if retention == 0 {
+		retention = dm.pruner.retention
+		if retention == 0 {
+			return nil, errors.New("no retention period specified or configured")
+		}
+	}
+	return dm.pruner.prune(retention)
+}
+
+// ReloadTLS can be called via admin API to reload the comms server's TLS
+// certificate from disk without disconnecting clients.
+func (dm *DEX) ReloadTLS() error {
+	return dm.server.ReloadTLS()
+}
+
+// Drain can be called via admin API to stop accepting new websocket
+// connections ahead of a restart. Refused clients are told to retry at the
+// websocket URL redirectAddr, if set. Connected clients are unaffected, and
+// clients with active swaps may reconnect.
+func (dm *DEX) Drain(redirectAddr string) *comms.DrainStatus {
+	return dm.server.Drain(redirectAddr)
+}
+
+// StopDraining can be called via admin API to accept new websocket connections
+// again.
+func (dm *DEX) StopDraining() *comms.DrainStatus {
+	return dm.server.StopDraining()
+}
+
+// DrainStatus reports the comms server's draining mode and connection counts.
+func (dm *DEX) DrainStatus() *comms.DrainStatus {
+	return dm.server.DrainStatus()
+}
+
 // candlesParamsParser is middleware for the /candles routes. Parses the
 // *msgjson.CandlesRequest from the URL parameters.
 func candleParamsParser(next http.Handler) http.Handler {
@@ -1558,6 +2010,53 @@ func candleParamsParser(next http.Handler) http.Handler {
 	})
 }
 
+// candleHistoryParamsParser is middleware for the /candlehistory route. Parses
+// the *msgjson.CandleHistoryRequest from the URL parameters and the optional
+// start, end, and count query parameters.
+func candleHistoryParamsParser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		baseID, quoteID, errMsg := parseBaseQuoteIDs(r)
+		if errMsg != "" {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+
+		binSize := chi.URLParam(r, "binSize")
+		if _, err := time.ParseDuration(binSize); err != nil {
+			http.Error(w, "bin size unparseable", http.StatusBadRequest)
+			return
+		}
+
+		req := &msgjson.CandleHistoryRequest{
+			BaseID:  baseID,
+			QuoteID: quoteID,
+			BinSize: binSize,
+		}
+		query := r.URL.Query()
+		var err error
+		if s := query.Get("start"); s != "" {
+			if req.Start, err = strconv.ParseUint(s, 10, 64); err != nil {
+				http.Error(w, "start unparseable", http.StatusBadRequest)
+				return
+			}
+		}
+		if s := query.Get("end"); s != "" {
+			if req.End, err = strconv.ParseUint(s, 10, 64); err != nil {
+				http.Error(w, "end unparseable", http.StatusBadRequest)
+				return
+			}
+		}
+		if s := query.Get("count"); s != "" {
+			if req.Count, err = strconv.Atoi(s); err != nil {
+				http.Error(w, "count unparseable", http.StatusBadRequest)
+				return
+			}
+		}
+		ctx := context.WithValue(r.Context(), comms.CtxThing, req)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // orderBookParamsParser is middleware for the /orderbook route. Parses the
 // *msgjson.OrderBookSubscription from the URL parameters.
 func orderBookParamsParser(next http.Handler) http.Handler {
@@ -1575,6 +2074,29 @@ func orderBookParamsParser(next http.Handler) http.Handler {
 	})
 }
 
+// epochProofParamsParser is middleware for the /epochproof route. Parses the
+// *msgjson.EpochProofRequest from the URL parameters.
+func epochProofParamsParser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		baseID, quoteID, errMsg := parseBaseQuoteIDs(r)
+		if errMsg != "" {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		epoch, err := strconv.ParseUint(chi.URLParam(r, "epoch"), 10, 64)
+		if err != nil {
+			http.Error(w, "epoch unparseable", http.StatusBadRequest)
+			return
+		}
+		ctx := context.WithValue(r.Context(), comms.CtxThing, &msgjson.EpochProofRequest{
+			BaseID:  baseID,
+			QuoteID: quoteID,
+			Epoch:   epoch,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // parseBaseQuoteIDs parses the "baseSymbol" and "quoteSymbol" URL parameters
 // from the request.
 func parseBaseQuoteIDs(r *http.Request) (baseID, quoteID uint32, errMsg string) {
@@ -0,0 +1,132 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dex
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthCacheDuration is how long a HealthReport is served by the /healthz
+// endpoint before the DEX is checked again. The endpoint is not rate limited,
+// so this bounds the DB and asset backend requests it can generate.
+const healthCacheDuration = 5 * time.Second
+
+// Component statuses reported for an unhealthy component. The endpoint is
+// public, so the details of an error are logged rather than reported.
+const (
+	statusError      = "error"
+	statusNotSynced  = "not synced"
+	statusNotRunning = "not running"
+)
+
+// ComponentHealth is the health of one component of the DEX.
+type ComponentHealth struct {
+	OK     bool   `json:"ok"`
+	Status string `json:"status,omitempty"`
+}
+
+// HealthReport is the breakdown of the DEX's health served by the /healthz
+// endpoint. The DEX is healthy if the DB is reachable and has not reported an
+// error, every asset backend is synced, and every market is running.
+type HealthReport struct {
+	Healthy bool                        `json:"healthy"`
+	DB      *ComponentHealth            `json:"db"`
+	Assets  map[string]*ComponentHealth `json:"assets"`  // by symbol
+	Markets map[string]*ComponentHealth `json:"markets"` // by market name
+}
+
+// HealthReport checks the DB, asset backends, and markets.
+func (dm *DEX) HealthReport() *HealthReport {
+	report := &HealthReport{
+		Healthy: true,
+		DB:      &ComponentHealth{OK: true},
+		Assets:  make(map[string]*ComponentHealth, len(dm.assets)),
+		Markets: make(map[string]*ComponentHealth, len(dm.markets)),
+	}
+	err := dm.storage.LastErr()
+	if err == nil {
+		err = dm.storage.Ping()
+	}
+	if err != nil {
+		log.Errorf("Health check: DB error: %v", err)
+		report.Healthy = false
+		report.DB = &ComponentHealth{Status: statusError}
+	}
+	for _, a := range dm.assets {
+		h := &ComponentHealth{OK: true}
+		synced, err := a.Backend.Synced()
+		switch {
+		case err != nil:
+			log.Errorf("Health check: %s backend error: %v", a.Symbol, err)
+			h = &ComponentHealth{Status: statusError}
+		case !synced:
+			h = &ComponentHealth{Status: statusNotSynced}
+		}
+		report.Healthy = report.Healthy && h.OK
+		report.Assets[a.Symbol] = h
+	}
+	for name, mkt := range dm.markets {
+		h := &ComponentHealth{OK: true}
+		if !mkt.Running() {
+			h = &ComponentHealth{Status: statusNotRunning}
+		}
+		report.Healthy = report.Healthy && h.OK
+		report.Markets[name] = h
+	}
+	return report
+}
+
+// healthCache serves the /healthz endpoint, regenerating the encoded
+// HealthReport at most once every healthCacheDuration.
+type healthCache struct {
+	check func() *HealthReport
+
+	mtx     sync.Mutex
+	stamp   time.Time
+	healthy bool
+	enc     []byte
+}
+
+func newHealthCache(check func() *HealthReport) *healthCache {
+	return &healthCache{check: check}
+}
+
+// report returns the encoded HealthReport and whether the DEX is healthy,
+// checking again if the cached report is stale.
+func (hc *healthCache) report() ([]byte, bool, error) {
+	hc.mtx.Lock()
+	defer hc.mtx.Unlock()
+	if hc.enc != nil && time.Since(hc.stamp) < healthCacheDuration {
+		return hc.enc, hc.healthy, nil
+	}
+	report := hc.check()
+	b, err := json.Marshal(report)
+	if err != nil {
+		return nil, false, err
+	}
+	hc.enc, hc.healthy, hc.stamp = b, report.Healthy, time.Now()
+	return b, report.Healthy, nil
+}
+
+// handleHealthz is the handler for the /healthz endpoint. The HealthReport is
+// sent with status 200 if the DEX is healthy and status 503 if it is degraded.
+func (hc *healthCache) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	b, healthy, err := hc.report()
+	if err != nil {
+		log.Errorf("Error encoding health report: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	code := http.StatusOK
+	if !healthy {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	w.Write(b)
+}
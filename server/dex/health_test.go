@@ -0,0 +1,70 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dex
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleHealthz(t *testing.T) {
+	var checks int
+	report := &HealthReport{
+		Healthy: true,
+		DB:      &ComponentHealth{OK: true},
+		Assets:  map[string]*ComponentHealth{"btc": {OK: true}},
+		Markets: map[string]*ComponentHealth{"dcr_btc": {OK: true}},
+	}
+	hc := newHealthCache(func() *HealthReport {
+		checks++
+		return report
+	})
+
+	get := func() (*httptest.ResponseRecorder, *HealthReport) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		hc.handleHealthz(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		resp := new(HealthReport)
+		if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+			t.Fatalf("error decoding health report: %v", err)
+		}
+		return w, resp
+	}
+
+	w, resp := get()
+	if w.Code != http.StatusOK {
+		t.Fatalf("wrong status code for healthy DEX. wanted %d, got %d", http.StatusOK, w.Code)
+	}
+	if !resp.Healthy || !resp.Assets["btc"].OK {
+		t.Fatalf("wrong health report %+v", resp)
+	}
+
+	// The report is cached.
+	report = &HealthReport{
+		DB:      &ComponentHealth{Status: statusError},
+		Assets:  map[string]*ComponentHealth{"btc": {OK: true}},
+		Markets: map[string]*ComponentHealth{"dcr_btc": {OK: true}},
+	}
+	if w, _ = get(); w.Code != http.StatusOK {
+		t.Fatalf("cached report not used. got status code %d", w.Code)
+	}
+	if checks != 1 {
+		t.Fatalf("expected 1 check, got %d", checks)
+	}
+
+	// A stale report is regenerated.
+	hc.stamp = hc.stamp.Add(-healthCacheDuration)
+	w, resp = get()
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("wrong status code for degraded DEX. wanted %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if resp.Healthy || resp.DB.OK || resp.DB.Status != statusError {
+		t.Fatalf("wrong health report %+v", resp)
+	}
+	if checks != 2 {
+		t.Fatalf("expected 2 checks, got %d", checks)
+	}
+}
@@ -0,0 +1,127 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/candles"
+	"decred.org/dcrdex/server/db"
+)
+
+// MinArchiveRetention is the shortest retention period for which archived data
+// may be pruned. Recent epoch reports build the partial candles that are not
+// yet stored.
+const MinArchiveRetention = 30 * 24 * time.Hour
+
+// pruneKeepPerAccount is how many of each account's most recent matches and
+// cancel orders are kept regardless of age. The auth manager scores an account
+// on its last auth.ScoringMatchLimit match outcomes and on its cancellation
+// rate over its last 100 finished orders, so pruning must not change the score
+// of an account that has not traded for longer than the retention period.
+const pruneKeepPerAccount = 100
+
+// PruneReport summarizes the archived data deleted by a prune.
+type PruneReport struct {
+	// Before is the time before which data was pruned, in unix ms.
+	Before  int64                      `json:"before"`
+	Markets map[string]*db.PruneResult `json:"markets"`
+}
+
+// pruneArchiver is the part of the DEXArchivist used by the pruner.
+type pruneArchiver interface {
+	db.ArchivePruner
+	LastCandleEndStamp(base, quote uint32, candleDur uint64) (uint64, error)
+}
+
+// pruner deletes archived matches, cancel orders, and epoch data that are older
+// than a retention period.
+type pruner struct {
+	storage   pruneArchiver
+	markets   []*dex.MarketInfo
+	retention time.Duration // zero if automatic pruning is disabled
+	binSizes  []uint64      // stored candle durations, ms
+
+	mtx sync.Mutex // one prune at a time
+}
+
+func newPruner(storage pruneArchiver, markets []*dex.MarketInfo, retention time.Duration) (*pruner, error) {
+	if retention != 0 && retention < MinArchiveRetention {
+		return nil, fmt.Errorf("archive retention %v is less than the minimum %v", retention, MinArchiveRetention)
+	}
+	binSizes := make([]uint64, 0, len(candles.BinSizes))
+	for _, s := range candles.BinSizes {
+		dur, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing bin size %q: %w", s, err)
+		}
+		binSizes = append(binSizes, uint64(dur.Milliseconds()))
+	}
+	return &pruner{
+		storage:   storage,
+		markets:   markets,
+		retention: retention,
+		binSizes:  binSizes,
+	}, nil
+}
+
+// Run prunes the archive on startup and once a day thereafter. Run satisfies
+// dex.Runner.
+func (p *pruner) Run(ctx context.Context) {
+	for {
+		if _, err := p.prune(p.retention); err != nil {
+			log.Errorf("Failed to prune archived data: %v", err)
+		}
+		select {
+		case <-time.After(24 * time.Hour):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// prune deletes data older than the retention period from every market. Epoch
+// reports are only deleted once they have been rolled up into the stored
+// candles of every bin size.
+func (p *pruner) prune(retention time.Duration) (*PruneReport, error) {
+	if retention < MinArchiveRetention {
+		return nil, fmt.Errorf("retention %v is less than the minimum %v", retention, MinArchiveRetention)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	before := time.Now().Add(-retention)
+	report := &PruneReport{
+		Before:  before.UnixMilli(),
+		Markets: make(map[string]*db.PruneResult, len(p.markets)),
+	}
+	var errs []error
+	for _, mkt := range p.markets {
+		reportsBefore := before
+		for _, binSize := range p.binSizes {
+			lastEnd, err := p.storage.LastCandleEndStamp(mkt.Base, mkt.Quote, binSize)
+			if err != nil {
+				return nil, fmt.Errorf("error retrieving last %s candle end stamp: %w", mkt.Name, err)
+			}
+			if stored := time.UnixMilli(int64(lastEnd)); stored.Before(reportsBefore) {
+				reportsBefore = stored
+			}
+		}
+		res, err := p.storage.PruneMarket(mkt.Base, mkt.Quote, before, reportsBefore, pruneKeepPerAccount)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error pruning market %s: %w", mkt.Name, err))
+			continue
+		}
+		report.Markets[mkt.Name] = res
+		log.Infof("Pruned %d matches, %d cancel orders, %d epochs, and %d epoch reports older than %v from market %s",
+			res.Matches, res.CancelOrders, res.Epochs, res.EpochReports, before.UTC().Format(time.RFC3339), mkt.Name)
+	}
+	return report, errors.Join(errs...)
+}
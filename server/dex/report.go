@@ -0,0 +1,217 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/db"
+)
+
+// reportDateLayout is the layout of the date of a DailyReport, which is also
+// the name of the report's file without the extension.
+const reportDateLayout = "2006-01-02"
+
+// DailyReport is the end-of-day report for the exchange. Days are UTC.
+type DailyReport struct {
+	Date      string `json:"date"`
+	Start     int64  `json:"start"` // unix ms
+	End       int64  `json:"end"`   // unix ms, exclusive
+	Generated int64  `json:"generated"`
+	// NewRegistrations is the number of accounts created during the day.
+	NewRegistrations uint32          `json:"newRegistrations"`
+	Markets          []*MarketReport `json:"markets"`
+}
+
+// MarketReport is the section of a DailyReport for one market. Matches are
+// attributed to the day of their epoch, and the outcome of a swap is as of the
+// time the report was generated, so matches made near the end of the day may
+// still be active.
+type MarketReport struct {
+	Market      string `json:"market"`
+	Matches     uint32 `json:"matches"`
+	Volume      uint64 `json:"volume"` // base asset
+	QuoteVolume uint64 `json:"quoteVolume"`
+	FailedSwaps uint32 `json:"failedSwaps"`
+	// FailedSwapRate is FailedSwaps / Matches.
+	FailedSwapRate float64 `json:"failedSwapRate"`
+	// Fees are the trading fees accrued, keyed by asset symbol.
+	Fees map[string]uint64 `json:"fees"`
+}
+
+// reporter generates a DailyReport for each day and writes it to a file in the
+// reports directory.
+type reporter struct {
+	dir     string
+	storage db.ReportArchiver
+	markets []*dex.MarketInfo
+}
+
+func newReporter(dir string, storage db.ReportArchiver, markets []*dex.MarketInfo) (*reporter, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create reports directory: %w", err)
+	}
+	return &reporter{
+		dir:     dir,
+		storage: storage,
+		markets: markets,
+	}, nil
+}
+
+// Run generates the report for each day shortly after the day ends. The report
+// for the previous day is generated on startup if it does not exist. Run
+// satisfies dex.Runner.
+func (r *reporter) Run(ctx context.Context) {
+	today := startOfDay(time.Now())
+	if _, err := r.report(today.AddDate(0, 0, -1)); err != nil {
+		log.Errorf("Failed to generate daily report: %v", err)
+	}
+
+	for {
+		// Give late database writes for the day a moment to complete.
+		next := today.AddDate(0, 0, 1).Add(time.Minute)
+		select {
+		case <-time.After(time.Until(next)):
+		case <-ctx.Done():
+			return
+		}
+		if _, err := r.generate(today); err != nil {
+			log.Errorf("Failed to generate daily report: %v", err)
+		}
+		today = startOfDay(time.Now())
+	}
+}
+
+// startOfDay is the start of the UTC day that includes t.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func (r *reporter) path(day time.Time) string {
+	return filepath.Join(r.dir, day.Format(reportDateLayout)+".json")
+}
+
+// report reads the report for the day, generating it if it does not exist.
+func (r *reporter) report(day time.Time) (*DailyReport, error) {
+	b, err := os.ReadFile(r.path(day))
+	if errors.Is(err, os.ErrNotExist) {
+		return r.generate(day)
+	}
+	if err != nil {
+		return nil, err
+	}
+	report := new(DailyReport)
+	if err = json.Unmarshal(b, report); err != nil {
+		return nil, fmt.Errorf("error decoding report for %s: %w", day.Format(reportDateLayout), err)
+	}
+	return report, nil
+}
+
+// generate generates the report for the day and writes it to file, replacing
+// any existing report.
+func (r *reporter) generate(day time.Time) (*DailyReport, error) {
+	start := startOfDay(day)
+	end := start.AddDate(0, 0, 1)
+	date := start.Format(reportDateLayout)
+
+	newAccts, err := r.storage.NewAccounts(start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error counting new accounts for %s: %w", date, err)
+	}
+	report := &DailyReport{
+		Date:             date,
+		Start:            start.UnixMilli(),
+		End:              end.UnixMilli(),
+		Generated:        time.Now().UnixMilli(),
+		NewRegistrations: newAccts,
+		Markets:          make([]*MarketReport, 0, len(r.markets)),
+	}
+
+	for _, mkt := range r.markets {
+		stats, err := r.storage.MarketStats(mkt.Base, mkt.Quote, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving %s stats for %s: %w", mkt.Name, date, err)
+		}
+		mr := &MarketReport{
+			Market:      mkt.Name,
+			Matches:     stats.Matches,
+			Volume:      stats.Volume,
+			QuoteVolume: stats.QuoteVolume,
+			FailedSwaps: stats.FailedSwaps,
+			Fees:        make(map[string]uint64, len(stats.Fees)),
+		}
+		if stats.Matches > 0 {
+			mr.FailedSwapRate = float64(stats.FailedSwaps) / float64(stats.Matches)
+		}
+		for assetID, amt := range stats.Fees {
+			mr.Fees[dex.BipIDSymbol(assetID)] = amt
+		}
+		report.Markets = append(report.Markets, mr)
+	}
+	sort.Slice(report.Markets, func(i, j int) bool { return report.Markets[i].Market < report.Markets[j].Market })
+
+	b, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding report for %s: %w", date, err)
+	}
+	if err = os.WriteFile(r.path(start), b, 0600); err != nil {
+		return nil, fmt.Errorf("error writing report for %s: %w", date, err)
+	}
+	log.Infof("Generated daily report for %s", date)
+	return report, nil
+}
+
+// reportDates lists the dates of the existing reports, in ascending order.
+func (r *reporter) reportDates() ([]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		date, isJSON := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !isJSON {
+			continue
+		}
+		if _, err := time.Parse(reportDateLayout, date); err == nil {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// DailyReportDates lists the dates (YYYY-MM-DD) of the available daily reports.
+func (dm *DEX) DailyReportDates() ([]string, error) {
+	if dm.reporter == nil {
+		return nil, errors.New("daily reports are disabled")
+	}
+	return dm.reporter.reportDates()
+}
+
+// DailyReport retrieves the report for the date (YYYY-MM-DD). The report is
+// generated if it does not exist, but only for days that have ended.
+func (dm *DEX) DailyReport(date string) (*DailyReport, error) {
+	if dm.reporter == nil {
+		return nil, errors.New("daily reports are disabled")
+	}
+	day, err := time.Parse(reportDateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	if !day.Before(startOfDay(time.Now())) {
+		return nil, fmt.Errorf("day %s has not ended", date)
+	}
+	return dm.reporter.report(day)
+}
@@ -139,14 +139,19 @@ type BookSource interface {
 	OrderFeed() <-chan *updateSignal
 	Base() uint32
 	Quote() uint32
+	// DisplayBandSize is the size of the bands into which the quantities of
+	// large booked orders are rounded down for display, in atoms of the base
+	// asset. Zero disables banding.
+	DisplayBandSize() uint64
 }
 
 // subscribers is a manager for a map of subscribers and a sequence counter. The
 // sequence counter should be incremented whenever the DEX accepts, books,
 // removes, or modifies an order. The client is responsible for tracking the
 // sequence ID to ensure all order updates are received. If an update appears to
-// be missing, the client should re-subscribe to the market to synchronize the
-// order book from scratch.
+// be missing, the client may request the missed updates via the book_resync
+// route, or re-subscribe to the market to synchronize the order book from
+// scratch.
 type subscribers struct {
 	mtx   sync.RWMutex
 	conns map[uint64]comms.Link
@@ -189,20 +194,52 @@ func (s *subscribers) lastSeq() uint64 {
 	return s.seq
 }
 
+// bookHistoryLen is the number of recent sequenced book update notifications
+// retained by a msgBook for the book_resync route. A client that is further
+// behind than this is sent a full order book snapshot instead.
+const bookHistoryLen = 1024
+
+// seqMsg is a sequenced book update notification.
+type seqMsg struct {
+	seq uint64
+	msg *msgjson.Message
+}
+
 // msgBook is a local copy of the order book information. The orders are saved
 // as msgjson.BookOrderNote structures.
 type msgBook struct {
 	name string
-	// mtx guards orders and epochIdx
+	// mtx guards orders, history, and epochIdx
 	mtx           sync.RWMutex
 	running       bool
 	orders        map[order.OrderID]*msgjson.BookOrderNote
+	history       []*seqMsg
 	recentMatches [][3]int64
 	epochIdx      int64
 	subs          *subscribers
 	source        BookSource
 	baseID        uint32
 	quoteID       uint32
+	band          uint64 // display band size, zero for exact quantities
+}
+
+// displayQty is the quantity of a limit order shown to subscribers. If the
+// market bands its book, a quantity larger than one band is rounded down to a
+// multiple of the band size, so the exact size of large orders is not
+// revealed.
+func (book *msgBook) displayQty(qty uint64) uint64 {
+	if book.band == 0 || qty <= book.band {
+		return qty
+	}
+	return qty - qty%book.band
+}
+
+// limitOrderNote converts the limit order to a *msgjson.BookOrderNote with the
+// displayed quantity.
+func (book *msgBook) limitOrderNote(lo *order.LimitOrder) *msgjson.BookOrderNote {
+	note := limitOrderToMsgOrder(lo, book.name)
+	note.Quantity = book.displayQty(note.Quantity)
+	return note
 }
 
 func (book *msgBook) setEpoch(idx int64) {
@@ -221,6 +258,38 @@ func (book *msgBook) addRecentMatches(matches [][3]int64) {
 	}
 }
 
+// recordUpdate stores a sequenced book update notification in the book's
+// history, dropping the oldest entry if the history is full.
+func (book *msgBook) recordUpdate(seq uint64, msg *msgjson.Message) {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+	book.history = append(book.history, &seqMsg{seq: seq, msg: msg})
+	if len(book.history) > bookHistoryLen {
+		book.history = book.history[len(book.history)-bookHistoryLen:]
+	}
+}
+
+// updatesSince returns the recorded book update notifications with a sequence
+// number greater than seq. If the history does not extend back far enough to
+// fill the gap, ok will be false.
+func (book *msgBook) updatesSince(seq uint64) (msgs []*msgjson.Message, ok bool) {
+	book.mtx.RLock()
+	defer book.mtx.RUnlock()
+	if !book.running {
+		return nil, false
+	}
+	if len(book.history) == 0 || book.history[0].seq > seq+1 {
+		// Nothing to send is only ok if the requester is up-to-date.
+		return nil, seq >= book.subs.lastSeq()
+	}
+	for _, sm := range book.history {
+		if sm.seq > seq {
+			msgs = append(msgs, sm.msg)
+		}
+	}
+	return msgs, true
+}
+
 func (book *msgBook) epoch() int64 {
 	book.mtx.RLock()
 	defer book.mtx.RUnlock()
@@ -231,7 +300,7 @@ func (book *msgBook) epoch() int64 {
 // is already found, it is inserted, but an error is logged since update should
 // be used in that case.
 func (book *msgBook) insert(lo *order.LimitOrder) *msgjson.BookOrderNote {
-	msgOrder := limitOrderToMsgOrder(lo, book.name)
+	msgOrder := book.limitOrderNote(lo)
 	book.mtx.Lock()
 	defer book.mtx.Unlock()
 	if _, found := book.orders[lo.ID()]; found {
@@ -247,7 +316,7 @@ func (book *msgBook) insert(lo *order.LimitOrder) *msgjson.BookOrderNote {
 // order's filled amount changes. If the order is not found, it is inserted, but
 // an error is logged since insert should be used in that case.
 func (book *msgBook) update(lo *order.LimitOrder) *msgjson.BookOrderNote {
-	msgOrder := limitOrderToMsgOrder(lo, book.name)
+	msgOrder := book.limitOrderNote(lo)
 	book.mtx.Lock()
 	defer book.mtx.Unlock()
 	if _, found := book.orders[lo.ID()]; !found {
@@ -274,7 +343,7 @@ func (book *msgBook) addBulkOrders(epoch int64, orderSets ...[]*order.LimitOrder
 	book.epochIdx = epoch
 	for _, set := range orderSets {
 		for _, lo := range set {
-			book.orders[lo.ID()] = limitOrderToMsgOrder(lo, book.name)
+			book.orders[lo.ID()] = book.limitOrderNote(lo)
 		}
 	}
 }
@@ -315,11 +384,13 @@ func NewBookRouter(sources map[string]BookSource, feeSource FeeSource, route fun
 			source:  src,
 			baseID:  src.Base(),
 			quoteID: src.Quote(),
+			band:    src.DisplayBandSize(),
 		}
 		router.books[mkt] = book
 	}
 	route(msgjson.OrderBookRoute, router.handleOrderBook)
 	route(msgjson.UnsubOrderBookRoute, router.handleUnsubOrderBook)
+	route(msgjson.BookResyncRoute, router.handleBookResync)
 	route(msgjson.FeeRateRoute, router.handleFeeRate)
 	route(msgjson.PriceFeedRoute, router.handlePriceFeeder)
 
@@ -350,6 +421,7 @@ func (r *BookRouter) runBook(ctx context.Context, book *msgBook) {
 		book.mtx.Lock()
 		book.running = false
 		book.orders = make(map[order.OrderID]*msgjson.BookOrderNote)
+		book.history = nil
 		book.mtx.Unlock()
 		log.Infof("Book router terminating for market %q", book.name)
 	}()
@@ -371,6 +443,7 @@ out:
 			// Prepare the book/unbook/epoch note.
 			var note any
 			var route string
+			var seq uint64 // only set for book updates
 			var spot *msgjson.Spot
 			switch sigData := u.data.(type) {
 			case sigDataNewEpoch:
@@ -388,7 +461,8 @@ out:
 					panic("non-limit order received with bookAction")
 				}
 				n := book.insert(lo)
-				n.Seq = subs.nextSeq()
+				seq = subs.nextSeq()
+				n.Seq = seq
 				note = n
 
 			case sigDataUnbookedOrder:
@@ -399,8 +473,9 @@ out:
 				}
 				book.remove(lo)
 				oid := sigData.order.ID()
+				seq = subs.nextSeq()
 				note = &msgjson.UnbookOrderNote{
-					Seq:      subs.nextSeq(),
+					Seq:      seq,
 					MarketID: book.name,
 					OrderID:  oid[:],
 				}
@@ -414,9 +489,10 @@ out:
 				bookNote := book.update(lo)
 				n := &msgjson.UpdateRemainingNote{
 					OrderNote: bookNote.OrderNote,
-					Remaining: lo.Remaining(),
+					Remaining: bookNote.Quantity,
 				}
-				n.Seq = subs.nextSeq()
+				seq = subs.nextSeq()
+				n.Seq = seq
 				note = n
 
 			case sigDataEpochReport:
@@ -458,7 +534,7 @@ out:
 				epochNote := new(msgjson.EpochOrderNote)
 				switch o := sigData.order.(type) {
 				case *order.LimitOrder:
-					epochNote.BookOrderNote = *limitOrderToMsgOrder(o, book.name)
+					epochNote.BookOrderNote = *book.limitOrderNote(o)
 					epochNote.OrderType = msgjson.LimitOrderNum
 				case *order.MarketOrder:
 					epochNote.BookOrderNote = *marketOrderToMsgOrder(o, book.name)
@@ -469,7 +545,8 @@ out:
 					epochNote.TargetID = o.TargetOrderID[:]
 				}
 
-				epochNote.Seq = subs.nextSeq()
+				seq = subs.nextSeq()
+				epochNote.Seq = seq
 				epochNote.MarketID = book.name
 				epochNote.Epoch = uint64(sigData.epochIdx)
 				c := sigData.order.Commitment()
@@ -510,7 +587,8 @@ out:
 				}
 				// Only set Seq if there is a book update.
 				if !sigData.persistBook {
-					susp.Seq = subs.nextSeq() // book purge
+					seq = subs.nextSeq() // book purge
+					susp.Seq = seq
 					book.mtx.Lock()
 					book.orders = make(map[order.OrderID]*msgjson.BookOrderNote)
 					book.mtx.Unlock()
@@ -536,7 +614,15 @@ out:
 				continue
 			}
 
-			r.sendNote(route, subs, note)
+			msg, err := msgjson.NewNotification(route, note)
+			if err != nil {
+				log.Errorf("error creating notification-type Message: %v", err)
+				continue
+			}
+			if seq > 0 {
+				book.recordUpdate(seq, msg)
+			}
+			r.sendMsg(subs, msg)
 
 			if spot != nil {
 				r.sendNote(msgjson.PriceUpdateRoute, r.priceFeeders, spot)
@@ -679,6 +765,53 @@ func (r *BookRouter) handleUnsubOrderBook(conn comms.Link, msg *msgjson.Message)
 	return nil
 }
 
+// handleBookResync is the handler for the non-authenticated 'book_resync'
+// route. A subscriber that detects a gap in the sequence of book update
+// notifications sends the last sequence number that it applied, and the
+// missed notifications are returned in order. If the missed updates are no
+// longer available, a full order book snapshot is returned instead.
+func (r *BookRouter) handleBookResync(conn comms.Link, msg *msgjson.Message) *msgjson.Error {
+	resync := new(msgjson.BookResync)
+	err := msg.Unmarshal(&resync)
+	if err != nil || resync == nil {
+		return &msgjson.Error{
+			Code:    msgjson.RPCParseError,
+			Message: "error parsing book_resync request",
+		}
+	}
+	book := r.books[resync.MarketID]
+	if book == nil {
+		return &msgjson.Error{
+			Code:    msgjson.UnknownMarket,
+			Message: "unknown market: " + resync.MarketID,
+		}
+	}
+
+	res := &msgjson.BookResyncResult{MarketID: book.name}
+	if updates, ok := book.updatesSince(resync.Seq); ok {
+		res.Updates = updates
+	} else {
+		res.Snapshot = r.msgOrderBook(book)
+		if res.Snapshot == nil {
+			return msgjson.NewError(msgjson.MarketNotRunningError, "market not running")
+		}
+	}
+
+	resp, err := msgjson.NewResponse(msg.ID, res, nil)
+	if err != nil {
+		log.Errorf("error encoding 'book_resync' response: %v", err)
+		return &msgjson.Error{
+			Code:    msgjson.RPCInternal,
+			Message: "encoding error",
+		}
+	}
+	err = conn.Send(resp)
+	if err != nil {
+		log.Debugf("error sending book_resync response: %v", err)
+	}
+	return nil
+}
+
 // handleFeeRate handles a fee_rate request.
 func (r *BookRouter) handleFeeRate(conn comms.Link, msg *msgjson.Message) *msgjson.Error {
 	var assetID uint32
@@ -727,10 +860,13 @@ func (r *BookRouter) sendNote(route string, subs *subscribers, note any) {
 	msg, err := msgjson.NewNotification(route, note)
 	if err != nil {
 		log.Errorf("error creating notification-type Message: %v", err)
-		// Do I need to do some kind of resync here?
 		return
 	}
+	r.sendMsg(subs, msg)
+}
 
+// sendMsg sends the message to the specified subscribers.
+func (r *BookRouter) sendMsg(subs *subscribers, msg *msgjson.Message) {
 	// Marshal and send the bytes to avoid multiple marshals when sending.
 	b, err := json.Marshal(msg)
 	if err != nil {
@@ -186,6 +186,7 @@ type Storage interface {
 	LastEpochRate(base, quote uint32) (uint64, error)
 	MarketMatches(base, quote uint32) ([]*db.MatchDataWithCoins, error)
 	InsertMatch(match *order.Match) error
+	InsertTradingFees(base, quote uint32, fees []*db.TradingFee) error
 }
 
 // NewMarket creates a new Market for the provided base and quote assets, with
@@ -1087,6 +1088,12 @@ orders:
 // processReadyEpoch) are removed from the settling map regardless of any amount
 // still setting for such orders.
 func (m *Market) SwapDone(ord order.Order, match *order.Match, fail bool) {
+	// The taker's redemption completes the match. Only completed matches
+	// accrue trading fees.
+	if !fail && match.Status == order.MatchComplete {
+		m.recordTradingFees(match, time.Now())
+	}
+
 	oid := ord.ID()
 	m.bookMtx.Lock()
 	defer m.bookMtx.Unlock()
@@ -1738,6 +1745,76 @@ func (m *Market) ParcelSize() uint32 {
 	return m.marketInfo.ParcelSize
 }
 
+// DisplayBand is the size, in lots, of the bands into which the displayed
+// quantities of large booked orders are rounded down. Zero if banding is
+// disabled.
+func (m *Market) DisplayBand() uint64 {
+	return m.marketInfo.DisplayBand
+}
+
+// DisplayBandSize is the size of the display bands in atoms of the base asset.
+// DisplayBandSize is part of the BookSource interface.
+func (m *Market) DisplayBandSize() uint64 {
+	return m.marketInfo.DisplayBand * m.marketInfo.LotSize
+}
+
+// tradingFees computes the trading fees accrued by the maker and taker of a
+// completed match. Each party's fee is denominated in the asset they receive.
+// Cancel order matches do not accrue fees.
+func (m *Market) tradingFees(match *order.Match, completeTime time.Time) []*db.TradingFee {
+	mi := m.marketInfo
+	if mi.MakerFeeRate == 0 && mi.TakerFeeRate == 0 {
+		return nil
+	}
+	t := match.Taker.Trade()
+	if t == nil {
+		return nil
+	}
+	stamp := completeTime.UnixMilli()
+	mid := match.ID()
+	baseQty, quoteQty := match.Quantity, calc.BaseToQuote(match.Rate, match.Quantity)
+	takerAsset, takerQty := mi.Base, baseQty
+	makerAsset, makerQty := mi.Quote, quoteQty
+	if t.Sell {
+		takerAsset, takerQty = mi.Quote, quoteQty
+		makerAsset, makerQty = mi.Base, baseQty
+	}
+	var fees []*db.TradingFee
+	if fee := mi.TradingFee(makerQty, true); fee > 0 {
+		fees = append(fees, &db.TradingFee{
+			MatchID: mid,
+			Account: match.Maker.User(),
+			Maker:   true,
+			AssetID: makerAsset,
+			Amount:  fee,
+			Time:    stamp,
+		})
+	}
+	if fee := mi.TradingFee(takerQty, false); fee > 0 {
+		fees = append(fees, &db.TradingFee{
+			MatchID: mid,
+			Account: match.Taker.User(),
+			AssetID: takerAsset,
+			Amount:  fee,
+			Time:    stamp,
+		})
+	}
+	return fees
+}
+
+// recordTradingFees stores the trading fees accrued by a completed match.
+func (m *Market) recordTradingFees(match *order.Match, completeTime time.Time) {
+	fees := m.tradingFees(match, completeTime)
+	if len(fees) == 0 {
+		return
+	}
+	m.lazy(func() {
+		if err := m.storage.InsertTradingFees(m.Base(), m.Quote(), fees); err != nil {
+			log.Errorf("Failed to record %d trading fees for match %v: %v", len(fees), match.ID(), err)
+		}
+	})
+}
+
 // Parcels calculates the total parcels for the market with the specified
 // settling quantity. Parcels is used as part of order validation for global
 // parcel limits. Parcels is not called for the market for which the order is
@@ -2419,13 +2496,19 @@ func (m *Market) processReadyEpoch(epoch *readyEpoch, notifyChan chan<- *updateS
 
 	// Store data in epochs table, including matchTime so that cancel execution
 	// times can be obtained from the DB for cancellation rate computation.
+	// The preimages and missed order commitments are stored as well so that
+	// the match proof can be verified after the orders are pruned.
 	oidsRevealed := make([]order.OrderID, 0, len(ordersRevealed))
-	for _, or := range ordersRevealed {
+	preimages := make([]order.Preimage, 0, len(ordersRevealed))
+	for _, or := range ordersRevealed { // shuffled by Match
 		oidsRevealed = append(oidsRevealed, or.Order.ID())
+		preimages = append(preimages, or.Preimage)
 	}
 	oidsMissed := make([]order.OrderID, 0, len(misses))
+	missedCommits := make([]order.Commitment, 0, len(misses))
 	for _, om := range misses {
 		oidsMissed = append(oidsMissed, om.ID())
+		missedCommits = append(missedCommits, om.Commitment())
 	}
 
 	// If there were no matches, we need to persist that last rate from the last
@@ -2448,7 +2531,9 @@ func (m *Market) processReadyEpoch(epoch *readyEpoch, notifyChan chan<- *updateS
 		CSum:           cSum,
 		Seed:           seed,
 		OrdersRevealed: oidsRevealed,
+		Preimages:      preimages,
 		OrdersMissed:   oidsMissed,
+		MissedCommits:  missedCommits,
 		MatchVolume:    stats.MatchVolume,
 		QuoteVolume:    stats.QuoteVolume,
 		BookBuys:       stats.BookBuys,
@@ -2527,10 +2612,6 @@ func (m *Market) processReadyEpoch(epoch *readyEpoch, notifyChan chan<- *updateS
 	}
 
 	// Signal the match_proof to the orderbook subscribers.
-	preimages := make([]order.Preimage, len(ordersRevealed))
-	for i := range ordersRevealed {
-		preimages[i] = ordersRevealed[i].Preimage
-	}
 	sig := &updateSignal{
 		action: matchProofAction,
 		data: sigDataMatchProof{
@@ -168,6 +168,9 @@ func (ta *TArchivist) UpdateOrderStatus(order.Order, order.OrderStatus) error
 // SwapArchiver for Swapper
 func (ta *TArchivist) ActiveSwaps() ([]*db.SwapDataFull, error) { return nil, nil }
 func (ta *TArchivist) InsertMatch(match *order.Match) error     { return nil }
+func (ta *TArchivist) InsertTradingFees(base, quote uint32, fees []*db.TradingFee) error {
+	return nil
+}
 func (ta *TArchivist) MatchByID(mid order.MatchID, base, quote uint32) (*db.MatchData, error) {
 	return nil, nil
 }
@@ -190,6 +193,9 @@ func (ta *TArchivist) MatchStatuses(aid account.AccountID, base, quote uint32, m
 func (ta *TArchivist) SwapData(mid db.MarketMatchID) (order.MatchStatus, *db.SwapData, error) {
 	return 0, nil, nil
 }
+func (ta *TArchivist) SaveSwapConfs(mid db.MarketMatchID, swapConfA, swapConfB uint32) error {
+	return nil
+}
 func (ta *TArchivist) SaveMatchAckSigA(mid db.MarketMatchID, sig []byte) error { return nil }
 func (ta *TArchivist) SaveMatchAckSigB(mid db.MarketMatchID, sig []byte) error { return nil }
 
@@ -2102,3 +2108,64 @@ func TestMarket_AccountPending(t *testing.T) {
 	checkPending("with-epoch-market-buy-matic", maticAddr, assetMATIC.ID, totalQty, totalBuyLots, redeems)
 	checkPending("with-epoch-market-buy-eth", ethAddr, assetETH.ID, totalSellLots*dcrLotSize, totalSellLots, int(totalBuyLots))
 }
+
+func TestTradingFees(t *testing.T) {
+	mi := &dex.MarketInfo{
+		Base:         mkt1.Base,
+		Quote:        mkt1.Quote,
+		LotSize:      mkt1.LotSize,
+		MakerFeeRate: 1_000, // 0.1%
+		TakerFeeRate: 2_000, // 0.2%
+	}
+	mkt := &Market{marketInfo: mi}
+
+	const rate = 5e7
+	maker := makeLO(buyer1, rate, 2, order.StandingTiF)
+	taker := makeLO(seller1, rate, 2, order.ImmediateTiF)
+	qty := 2 * mkt1.LotSize
+	match := &order.Match{
+		Taker:    taker,
+		Maker:    maker,
+		Quantity: qty,
+		Rate:     rate,
+	}
+
+	completeTime := time.Now()
+	fees := mkt.tradingFees(match, completeTime)
+	if len(fees) != 2 {
+		t.Fatalf("expected 2 fees, got %d", len(fees))
+	}
+	makerFee, takerFee := fees[0], fees[1]
+	if !makerFee.Maker || makerFee.Account != maker.User() || makerFee.AssetID != mi.Base {
+		t.Fatalf("wrong maker fee %+v", makerFee)
+	}
+	if makerFee.Amount != qty/1_000 {
+		t.Fatalf("wrong maker fee amount. wanted %d, got %d", qty/1_000, makerFee.Amount)
+	}
+	if takerFee.Maker || takerFee.Account != taker.User() || takerFee.AssetID != mi.Quote {
+		t.Fatalf("wrong taker fee %+v", takerFee)
+	}
+	if wantFee := calc.BaseToQuote(rate, qty) / 500; takerFee.Amount != wantFee {
+		t.Fatalf("wrong taker fee amount. wanted %d, got %d", wantFee, takerFee.Amount)
+	}
+	if makerFee.Time != completeTime.UnixMilli() {
+		t.Fatalf("wrong fee time")
+	}
+
+	// Cancel matches accrue no fees.
+	cancelMatch := &order.Match{
+		Taker:    makeCO(seller1, maker.ID()),
+		Maker:    maker,
+		Quantity: maker.Quantity,
+		Rate:     maker.Rate,
+	}
+	if fees := mkt.tradingFees(cancelMatch, completeTime); len(fees) != 0 {
+		t.Fatalf("expected no fees for a cancel match, got %d", len(fees))
+	}
+
+	// No fees are computed without a fee schedule.
+	mi.MakerFeeRate, mi.TakerFeeRate = 0, 0
+	if fees := mkt.tradingFees(match, completeTime); len(fees) != 0 {
+		t.Fatalf("expected no fees, got %d", len(fees))
+	}
+}
@@ -38,6 +38,8 @@ type AuthManager interface {
 	RecordCancel(user account.AccountID, oid, target order.OrderID, epochGap int32, t time.Time)
 	RecordCompletedOrder(user account.AccountID, oid order.OrderID, t time.Time)
 	UserReputation(user account.AccountID) (tier int64, score, maxScore int32, err error)
+	Standing(user account.AccountID) (standing account.Standing, maxLots uint64)
+	UserAPIVersion(user account.AccountID) (ver uint16, ok bool)
 }
 
 const (
@@ -234,6 +236,10 @@ func (r *OrderRouter) handleLimit(user account.AccountID, msg *msgjson.Message)
 	if _, tier := r.auth.AcctStatus(user); tier < 1 {
 		return msgjson.NewError(msgjson.AccountClosedError, "account %v with tier %d may not submit trade orders", user, tier)
 	}
+	standing, maxLots := r.auth.Standing(user)
+	if standing >= account.StandingSuspended {
+		return msgjson.NewError(msgjson.AccountClosedError, "account %v is %v and may not submit trade orders", user, standing)
+	}
 
 	tunnel, assets, sell, rpcErr := r.extractMarketDetails(&limit.Prefix, &limit.Trade)
 	if rpcErr != nil {
@@ -257,8 +263,9 @@ func (r *OrderRouter) handleLimit(user account.AccountID, msg *msgjson.Message)
 		return msgjson.NewError(msgjson.OrderParameterError, "rate = 0 not allowed")
 	}
 	if rateStep := tunnel.RateStep(); limit.Rate%rateStep != 0 {
-		return msgjson.NewError(msgjson.OrderParameterError, "rate (%d) not a multiple of ratestep (%d)",
-			limit.Rate, rateStep)
+		return msgjson.NewErrorWithData(msgjson.OrderParameterError,
+			msgjson.NewOrderStepHint(msgjson.StepFieldRate, limit.Rate, rateStep),
+			"rate (%d) not a multiple of ratestep (%d)", limit.Rate, rateStep)
 	}
 
 	// Check time-in-force
@@ -277,6 +284,9 @@ func (r *OrderRouter) handleLimit(user account.AccountID, msg *msgjson.Message)
 	if rpcErr != nil {
 		return rpcErr
 	}
+	if rpcErr = checkLotCap(limit.Quantity/lotSize, maxLots); rpcErr != nil {
+		return rpcErr
+	}
 
 	// Commitment
 	if len(limit.Commit) != order.CommitmentSize {
@@ -343,6 +353,10 @@ func (r *OrderRouter) handleMarket(user account.AccountID, msg *msgjson.Message)
 	if _, tier := r.auth.AcctStatus(user); tier < 1 {
 		return msgjson.NewError(msgjson.AccountClosedError, "account %v with tier %d may not submit trade orders", user, tier)
 	}
+	standing, maxLots := r.auth.Standing(user)
+	if standing >= account.StandingSuspended {
+		return msgjson.NewError(msgjson.AccountClosedError, "account %v is %v and may not submit trade orders", user, standing)
+	}
 
 	tunnel, assets, sell, rpcErr := r.extractMarketDetails(&market.Prefix, &market.Trade)
 	if rpcErr != nil {
@@ -359,6 +373,13 @@ func (r *OrderRouter) handleMarket(user account.AccountID, msg *msgjson.Message)
 		return msgjson.NewError(msgjson.OrderParameterError, "wrong order type set for market order")
 	}
 
+	// The optional worst rate must obey the rate step interval.
+	if rateStep := tunnel.RateStep(); market.WorstRate%rateStep != 0 {
+		return msgjson.NewErrorWithData(msgjson.OrderParameterError,
+			msgjson.NewOrderStepHint(msgjson.StepFieldRate, market.WorstRate, rateStep),
+			"worst rate (%d) not a multiple of ratestep (%d)", market.WorstRate, rateStep)
+	}
+
 	// Passing sell as the checkLot parameter causes the lot size check to be
 	// ignored for market buy orders.
 	lotSize := tunnel.LotSize()
@@ -366,6 +387,13 @@ func (r *OrderRouter) handleMarket(user account.AccountID, msg *msgjson.Message)
 	if rpcErr != nil {
 		return rpcErr
 	}
+	baseQty := market.Quantity
+	if !sell {
+		baseQty = calc.QuoteToBase(safeMidGap(tunnel), market.Quantity)
+	}
+	if rpcErr = checkLotCap(baseQty/lotSize, maxLots); rpcErr != nil {
+		return rpcErr
+	}
 
 	// Commitment.
 	if len(market.Commit) != order.CommitmentSize {
@@ -397,6 +425,7 @@ func (r *OrderRouter) handleMarket(user account.AccountID, msg *msgjson.Message)
 			Quantity: market.Quantity,
 			Address:  market.Address,
 		},
+		WorstRate: market.WorstRate,
 	}
 
 	// Send the order to the epoch queue.
@@ -811,7 +840,12 @@ func (r *OrderRouter) handleCancel(user account.AccountID, msg *msgjson.Message)
 	copy(targetID[:], cancel.TargetID)
 
 	if !tunnel.Cancelable(targetID) {
-		return msgjson.NewError(msgjson.OrderParameterError, "target order not known: %v", targetID)
+		// Older clients expect an OrderParameterError.
+		code := msgjson.OrderParameterError
+		if ver, _ := r.auth.UserAPIVersion(user); ver >= msgjson.UnknownCancelTargetAPIVersion {
+			code = msgjson.UnknownCancelTargetError
+		}
+		return msgjson.NewError(code, "target order not known: %v", targetID)
 	}
 
 	// Check that OrderType is set correctly
@@ -999,7 +1033,9 @@ func (r *OrderRouter) checkPrefixTrade(assets *assetSet, lotSize uint64, prefix
 		return msgjson.NewError(msgjson.OrderParameterError, "zero quantity not allowed")
 	}
 	if checkLot && trade.Quantity%lotSize != 0 {
-		return msgjson.NewError(msgjson.OrderParameterError, "order quantity not a multiple of lot size")
+		return msgjson.NewErrorWithData(msgjson.OrderParameterError,
+			msgjson.NewOrderStepHint(msgjson.StepFieldQty, trade.Quantity, lotSize),
+			"order quantity not a multiple of lot size")
 	}
 	// Validate UTXOs
 	// Check that all required arrays are of equal length.
@@ -1052,6 +1088,16 @@ func fmtCoinIDs(assetID uint32, coinIDs []order.CoinID) string {
 	return fmt.Sprint(out)
 }
 
+// checkLotCap checks the order's lots against the order size limit of an
+// account with a capped standing. A maxLots of zero is no limit.
+func checkLotCap(lots, maxLots uint64) *msgjson.Error {
+	if maxLots > 0 && lots > maxLots {
+		return msgjson.NewError(msgjson.OrderQuantityTooHigh,
+			"order of %d lots exceeds this account's limit of %d lots", lots, maxLots)
+	}
+	return nil
+}
+
 func safeMidGap(tunnel MarketTunnel) uint64 {
 	midGap := tunnel.MidGap()
 	if midGap == 0 {
@@ -136,6 +136,9 @@ type TAuth struct {
 	suspensions        map[account.AccountID]bool
 	canceledOrder      order.OrderID
 	cancelOrder        order.OrderID
+	standing           account.Standing
+	maxLots            uint64
+	apiVersion         uint16
 	rep                struct {
 		tier            int64
 		score, maxScore int32
@@ -262,6 +265,12 @@ func (a *TAuth) AcctStatus(user account.AccountID) (connected bool, tier int64)
 	return true, 1
 }
 func (a *TAuth) RecordCompletedOrder(account.AccountID, order.OrderID, time.Time) {}
+func (a *TAuth) Standing(user account.AccountID) (account.Standing, uint64) {
+	return a.standing, a.maxLots
+}
+func (a *TAuth) UserAPIVersion(account.AccountID) (uint16, bool) {
+	return a.apiVersion, true
+}
 func (a *TAuth) RecordCancel(aid account.AccountID, coid, oid order.OrderID, epochGap int32, t time.Time) {
 	a.cancelOrder = coid
 	a.canceledOrder = oid
@@ -880,6 +889,16 @@ func TestLimit(t *testing.T) {
 		t.Errorf("Got force %v, expected %v (immediate)", epochOrder.Force, order.ImmediateTiF)
 	}
 
+	// A capped account may not exceed its lot limit, and a suspended account
+	// may not order at all.
+	oRig.auth.standing, oRig.auth.maxLots = account.StandingCapped, lots-1
+	ensureErr("capped order too large", sendLimit(), msgjson.OrderQuantityTooHigh)
+	oRig.auth.maxLots = lots
+	ensureSuccess("capped order")
+	oRig.auth.standing = account.StandingSuspended
+	ensureErr("suspended account", sendLimit(), msgjson.AccountClosedError)
+	oRig.auth.standing, oRig.auth.maxLots = account.StandingGood, 0
+
 	// Test an invalid payload.
 	msg := new(msgjson.Message)
 	msg.Payload = []byte(`?`)
@@ -910,7 +929,12 @@ func TestLimit(t *testing.T) {
 
 	// non-step-multiple rate
 	limit.Rate = rate + (btcRateStep / 2)
-	ensureErr("non-step-multiple", sendLimit(), msgjson.OrderParameterError)
+	rpcErr = sendLimit()
+	ensureErr("non-step-multiple", rpcErr, msgjson.OrderParameterError)
+	if hint, found := rpcErr.OrderStepHint(); !found || hint.Field != msgjson.StepFieldRate ||
+		hint.Lower != rate || hint.Upper != rate+btcRateStep {
+		t.Fatalf("wrong rate step hint %+v", hint)
+	}
 	limit.Rate = rate
 
 	// Time-in-force incorrectly marked
@@ -1214,6 +1238,16 @@ func TestMarketStartProcessStop(t *testing.T) {
 		t.Fatalf("failed to duplicate ID")
 	}
 
+	// The worst rate must be a multiple of the rate step.
+	mkt.WorstRate = midGap*2 + btcRateStep/2
+	ensureErr("worst rate not a multiple of rate step", sendMarket(), msgjson.OrderParameterError)
+	mkt.WorstRate = midGap * 2
+	ensureSuccess("market buy with worst rate")
+	if worstRate := oRecord.order.(*order.MarketOrder).WorstRate; worstRate != mkt.WorstRate {
+		t.Fatalf("worst rate %d not passed to the epoch order, got %d", mkt.WorstRate, worstRate)
+	}
+	mkt.WorstRate = 0
+
 	// Fund with an account-based asset.
 	mkt.Quote = assetETH.ID
 
@@ -1269,7 +1303,9 @@ func TestCancel(t *testing.T) {
 
 	// Unknown order.
 	oRig.market.cancelable = false
-	ensureErr("non cancelable", sendCancel(), msgjson.OrderParameterError)
+	ensureErr("non cancelable, old client", sendCancel(), msgjson.OrderParameterError)
+	oRig.auth.apiVersion = msgjson.UnknownCancelTargetAPIVersion
+	ensureErr("non cancelable", sendCancel(), msgjson.UnknownCancelTargetError)
 	oRig.market.cancelable = true
 
 	// Wrong order type marked for cancel order
@@ -1482,6 +1518,7 @@ type TBookSource struct {
 	feed  chan *updateSignal
 	base  uint32
 	quote uint32
+	band  uint64
 }
 
 func (s *TBookSource) Base() uint32 {
@@ -1492,6 +1529,10 @@ func (s *TBookSource) Quote() uint32 {
 	return s.quote
 }
 
+func (s *TBookSource) DisplayBandSize() uint64 {
+	return s.band
+}
+
 func tNewBookSource(base, quote uint32) *TBookSource {
 	return &TBookSource{
 		feed:  make(chan *updateSignal, 16),
@@ -2144,6 +2185,101 @@ func TestBadMessages(t *testing.T) {
 	checkErr("bad payload", rpcErr, msgjson.NotSubscribedError)
 }
 
+func TestBookResync(t *testing.T) {
+	router := rig.router
+	link, sub := newSubscriber(mkt3)
+	if err := router.handleOrderBook(link, sub); err != nil {
+		t.Fatalf("handleOrderBook: %v", err)
+	}
+	link.getSend() // the book
+
+	// Send a few epoch orders and record the sequence numbers.
+	var seqs []uint64
+	for i := 0; i < 3; i++ {
+		rig.source3.feed <- &updateSignal{
+			action: epochAction,
+			data: sigDataEpochOrder{
+				order:    makeMO(buyer3, randLots(10)),
+				epochIdx: 12345678,
+			},
+		}
+		seqs = append(seqs, getEpochNoteFromLink(t, link).Seq)
+	}
+
+	resync := func(seq uint64) *msgjson.BookResyncResult {
+		t.Helper()
+		req, _ := msgjson.NewRequest(1, msgjson.BookResyncRoute, &msgjson.BookResync{
+			MarketID: mktName3,
+			Seq:      seq,
+		})
+		if msgErr := router.handleBookResync(link, req); msgErr != nil {
+			t.Fatalf("handleBookResync error: %v", msgErr)
+		}
+		respMsg := link.getSend()
+		if respMsg == nil {
+			t.Fatalf("no book_resync response")
+		}
+		res := new(msgjson.BookResyncResult)
+		if err := respMsg.UnmarshalResult(res); err != nil {
+			t.Fatalf("error unmarshaling book_resync result: %v", err)
+		}
+		return res
+	}
+
+	// Missed the last two updates.
+	res := resync(seqs[0])
+	if res.Snapshot != nil {
+		t.Fatalf("unexpected snapshot")
+	}
+	if len(res.Updates) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(res.Updates))
+	}
+	for i, msg := range res.Updates {
+		if msg.Route != msgjson.EpochOrderRoute {
+			t.Fatalf("wrong route %q", msg.Route)
+		}
+		note := new(msgjson.EpochOrderNote)
+		if err := msg.Unmarshal(note); err != nil {
+			t.Fatalf("error unmarshaling epoch note: %v", err)
+		}
+		if note.Seq != seqs[i+1] {
+			t.Fatalf("wrong seq. wanted %d, got %d", seqs[i+1], note.Seq)
+		}
+	}
+
+	// Already up-to-date.
+	res = resync(seqs[2])
+	if res.Snapshot != nil || len(res.Updates) != 0 {
+		t.Fatalf("expected no updates and no snapshot for an up-to-date request")
+	}
+
+	// Too far behind. Drop the history up through the second update.
+	book := router.books[mktName3]
+	book.mtx.Lock()
+	for len(book.history) > 0 && book.history[0].seq <= seqs[1] {
+		book.history = book.history[1:]
+	}
+	book.mtx.Unlock()
+	res = resync(seqs[0])
+	if res.Snapshot == nil {
+		t.Fatalf("expected a snapshot")
+	}
+	if len(res.Updates) != 0 {
+		t.Fatalf("expected no updates with a snapshot, got %d", len(res.Updates))
+	}
+	if res.Snapshot.Seq != seqs[2] {
+		t.Fatalf("wrong snapshot seq. wanted %d, got %d", seqs[2], res.Snapshot.Seq)
+	}
+
+	// Unknown market.
+	req, _ := msgjson.NewRequest(1, msgjson.BookResyncRoute, &msgjson.BookResync{
+		MarketID: "sdgo_ptn",
+	})
+	if msgErr := router.handleBookResync(link, req); msgErr == nil || msgErr.Code != msgjson.UnknownMarket {
+		t.Fatalf("expected an UnknownMarket error, got %v", msgErr)
+	}
+}
+
 func TestPriceFeed(t *testing.T) {
 	mktID := "abc_123"
 	rig.router.spots[mktID] = &msgjson.Spot{Vol24: 54321}
@@ -2289,3 +2425,41 @@ func TestParcelLimits(t *testing.T) {
 	lo.Quantity += lotSize
 	ensureErr()
 }
+
+func TestDisplayBand(t *testing.T) {
+	lotSize := buyer1.Market.LotSize
+	band := 10 * lotSize
+	book := &msgBook{
+		name:   "dcr_btc",
+		orders: make(map[order.OrderID]*msgjson.BookOrderNote),
+		band:   band,
+	}
+
+	tests := []struct {
+		name        string
+		lots        uint64
+		wantDisplay uint64
+	}{
+		{"below band", 4, 4 * lotSize},
+		{"one band", 10, band},
+		{"rounded down", 25, 2 * band},
+		{"multiple of band", 30, 3 * band},
+	}
+	for _, tt := range tests {
+		lo := makeLO(buyer1, mkRate1(0.8, 1.0), tt.lots, order.StandingTiF)
+		note := book.insert(lo)
+		if note.Quantity != tt.wantDisplay {
+			t.Fatalf("%s: displayed quantity %d, expected %d", tt.name, note.Quantity, tt.wantDisplay)
+		}
+		if lo.Quantity != tt.lots*lotSize {
+			t.Fatalf("%s: order quantity modified", tt.name)
+		}
+	}
+
+	// Without a band, quantities are exact.
+	book.band = 0
+	lo := makeLO(buyer1, mkRate1(0.8, 1.0), 25, order.StandingTiF)
+	if note := book.insert(lo); note.Quantity != lo.Quantity {
+		t.Fatalf("displayed quantity %d, expected %d", note.Quantity, lo.Quantity)
+	}
+}
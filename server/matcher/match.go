@@ -410,12 +410,13 @@ func matchMarketSellOrder(book Booker, ord *order.MarketOrder) (matchSet *order.
 	}
 
 	// A market sell order is a special case of a limit order with time-in-force
-	// immediate and no minimum rate (a rate of 0).
+	// immediate and a minimum rate of the order's worst rate, which is zero
+	// when there is no limit.
 	limOrd := &order.LimitOrder{
 		P:     ord.P,
 		T:     *ord.T.Copy(),
 		Force: order.ImmediateTiF,
-		Rate:  0,
+		Rate:  ord.WorstRate,
 	}
 	matchSet = matchLimitOrder(book, limOrd)
 	if matchSet == nil {
@@ -448,6 +449,11 @@ func matchMarketBuyOrder(book Booker, ord *order.MarketOrder) (matchSet *order.M
 			return
 		}
 
+		// Stop at the buyer's worst acceptable rate, if set.
+		if ord.WorstRate > 0 && best.Rate > ord.WorstRate {
+			return
+		}
+
 		// Convert the market buy order's quantity into base asset:
 		//   quoteAmt = rate * baseAmt
 		amtRemainingBase := QuoteToBase(best.Rate, amtRemaining)
@@ -520,7 +526,9 @@ func OrdersMatch(a, b order.Order) bool {
 	case order.MarketOrderType:
 		switch bType {
 		case order.LimitOrderType:
-			return true // market-limit
+			if aRate == 0 {
+				return true // market-limit with no worst rate
+			}
 		case order.MarketOrderType:
 			fallthrough // no two market orders
 		default:
@@ -531,7 +539,9 @@ func OrdersMatch(a, b order.Order) bool {
 		case order.LimitOrderType:
 			// limit-limit: must check rates
 		case order.MarketOrderType:
-			return true // limit-market
+			if bRate == 0 {
+				return true // limit-market with no worst rate
+			}
 		default:
 			return false // cancel or unknown
 		}
@@ -539,7 +549,8 @@ func OrdersMatch(a, b order.Order) bool {
 		return false
 	}
 
-	// For limit-limit orders, check that the rates overlap.
+	// For limit-limit orders, and market orders with a worst rate, check that
+	// the rates overlap.
 	cmp := func(buyRate, sellRate uint64) bool { return sellRate <= buyRate }
 	if bSell {
 		// a is buy, b is sell
@@ -560,6 +571,7 @@ func orderData(o order.Order) (orderType order.OrderType, sell bool, amount, rat
 	case *order.MarketOrder:
 		sell = ot.Sell
 		amount = ot.Quantity
+		rate = ot.WorstRate
 	}
 
 	return
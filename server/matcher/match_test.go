@@ -1556,6 +1556,69 @@ func TestMatch_marketBuysOnly(t *testing.T) {
 		})
 	}
 }
+
+func TestMatch_marketWorstRate(t *testing.T) {
+	startLogger()
+
+	// A market buy stops at its worst rate, leaving the remainder unfilled.
+	buy := newMarketBuyOrder(quoteAmt(99), 0).Order.(*order.MarketOrder)
+	buy.WorstRate = 4600000
+	matchSet := matchMarketBuyOrder(newBooker(), buy)
+	if matchSet == nil {
+		t.Fatalf("no match for market buy with worst rate")
+	}
+	if matchSet.Total != 3*LotSize {
+		t.Fatalf("market buy matched %d, expected %d", matchSet.Total, 3*LotSize)
+	}
+	for _, rate := range matchSet.Rates {
+		if rate > buy.WorstRate {
+			t.Fatalf("market buy matched at %d, above the worst rate %d", rate, buy.WorstRate)
+		}
+	}
+	if buy.Remaining() == 0 {
+		t.Fatalf("market buy remainder not left unfilled")
+	}
+
+	// Nothing matches if the best sell is already worse.
+	buy = newMarketBuyOrder(quoteAmt(1), 0).Order.(*order.MarketOrder)
+	buy.WorstRate = 4500000
+	if matchSet = matchMarketBuyOrder(newBooker(), buy); matchSet != nil {
+		t.Fatalf("market buy matched above its worst rate")
+	}
+
+	// A market sell stops at its worst rate.
+	sell := newMarketSellOrder(99, 0).Order.(*order.MarketOrder)
+	sell.WorstRate = 4300000
+	matchSet = matchMarketSellOrder(newBooker(), sell)
+	if matchSet == nil {
+		t.Fatalf("no match for market sell with worst rate")
+	}
+	if matchSet.Total != 7*LotSize {
+		t.Fatalf("market sell matched %d, expected %d", matchSet.Total, 7*LotSize)
+	}
+	for _, rate := range matchSet.Rates {
+		if rate < sell.WorstRate {
+			t.Fatalf("market sell matched at %d, below the worst rate %d", rate, sell.WorstRate)
+		}
+	}
+
+	sell = newMarketSellOrder(1, 0).Order.(*order.MarketOrder)
+	sell.WorstRate = 4550000
+	if matchSet = matchMarketSellOrder(newBooker(), sell); matchSet != nil {
+		t.Fatalf("market sell matched below its worst rate")
+	}
+
+	// The worst rate is considered by OrdersMatch.
+	if OrdersMatch(sell, bookBuyOrders[len(bookBuyOrders)-1]) {
+		t.Fatalf("market sell with worst rate above the buy rate matched")
+	}
+	sell.WorstRate = 4500000
+	if !OrdersMatch(bookBuyOrders[len(bookBuyOrders)-1], sell) {
+		t.Fatalf("market sell with worst rate at the buy rate did not match")
+	}
+	resetMakers()
+}
+
 func Test_shuffleQueue(t *testing.T) {
 	// Setup the match package's logger.
 	startLogger()
@@ -82,6 +82,9 @@ type swapStatus struct {
 	// The asset to which the user broadcasts their swap transaction.
 	swapAsset   uint32
 	redeemAsset uint32
+	// swapConf is the number of confirmations required for the swap, fixed
+	// when the match is made.
+	swapConf uint32
 
 	swapSearching   uint32 // atomic
 	redeemSearching uint32 // atomic
@@ -246,6 +249,11 @@ type Swapper struct {
 	// latencyQ is a queue for coin waiters to deal with network latency.
 	latencyQ *wait.TaperingTickerQueue
 
+	// swapConfs are the per-market swapConf overrides, keyed by the market's
+	// [base, quote] asset IDs, then the swap asset ID.
+	swapConfMtx sync.RWMutex
+	swapConfs   map[[2]uint32]map[uint32]uint32
+
 	// handlerMtx should be read-locked for the duration of the comms route
 	// handlers (handleInit and handleRedeem) and Negotiate. This blocks
 	// shutdown until any coin waiters are registered with latencyQ. It should
@@ -285,6 +293,10 @@ type Config struct {
 	// SwapDone registers a match with the DEX manager (or other consumer) for a
 	// given order as being finished.
 	SwapDone func(oid order.Order, match *order.Match, fail bool)
+	// SwapConfs optionally overrides the SwapConf of the Assets for particular
+	// markets. The map is keyed by the market's [base, quote] asset IDs, then
+	// the swap asset ID.
+	SwapConfs map[[2]uint32]map[uint32]uint32
 }
 
 // NewSwapper is a constructor for a Swapper.
@@ -302,6 +314,23 @@ func NewSwapper(cfg *Config) (*Swapper, error) {
 		}
 	}
 
+	swapConfs := make(map[[2]uint32]map[uint32]uint32, len(cfg.SwapConfs))
+	for mkt, confs := range cfg.SwapConfs {
+		mktConfs := make(map[uint32]uint32, len(confs))
+		for assetID, swapConf := range confs {
+			if assetID != mkt[0] && assetID != mkt[1] {
+				return nil, fmt.Errorf("swapConf for asset %d not in market %d-%d", assetID, mkt[0], mkt[1])
+			}
+			if cfg.Assets[assetID] == nil {
+				return nil, fmt.Errorf("swapConf for unknown asset %d", assetID)
+			}
+			if swapConf > 0 {
+				mktConfs[assetID] = swapConf
+			}
+		}
+		swapConfs[mkt] = mktConfs
+	}
+
 	authMgr := cfg.AuthManager
 	swapper := &Swapper{
 		coins:            cfg.Assets,
@@ -316,6 +345,7 @@ func NewSwapper(cfg *Config) (*Swapper, error) {
 		txWaitExpiration: cfg.TxWaitExpiration,
 		lockTimeTaker:    cfg.LockTimeTaker,
 		lockTimeMaker:    cfg.LockTimeMaker,
+		swapConfs:        swapConfs,
 	}
 
 	// Ensure txWaitExpiration is not greater than broadcast timeout setting.
@@ -432,6 +462,13 @@ func (s *Swapper) deleteMatch(mt *matchTracker) {
 	}
 }
 
+// UserSwapping reports whether the user is a party to any active swaps.
+func (s *Swapper) UserSwapping(user account.AccountID) bool {
+	s.matchMtx.RLock()
+	defer s.matchMtx.RUnlock()
+	return len(s.userMatches[user]) > 0
+}
+
 // UnsettledQuantity sums up the settling quantity per market for a user. Part
 // of the market.MatchSwapper interface.
 func (s *Swapper) UnsettledQuantity(user account.AccountID) map[[2]uint32]uint64 {
@@ -577,6 +614,7 @@ func (s *Swapper) restoreActiveSwaps(allowPartial bool) error {
 	// methods as needed.
 
 	type swapStatusData struct {
+		Base, Quote     uint32 // the market
 		SwapAsset       uint32 // from market schema and takerSell bool
 		RedeemAsset     uint32
 		SwapTime        int64  // {a,b}ContractTime
@@ -584,12 +622,19 @@ func (s *Swapper) restoreActiveSwaps(allowPartial bool) error {
 		ContractScript  []byte // {a,b}Contract
 		RedeemTime      int64  // {a,b}RedeemTime
 		RedeemCoinIn    []byte // {a,b}aRedeemCoinID
+		SwapConf        uint32 // {a,b}SwapConf, zero if not recorded
 		// SwapConfirmTime is not stored in the DB, so use time.Now() if the
 		// contract has reached SwapConf.
 	}
 
 	translateSwapStatus := func(ss *swapStatus, ssd *swapStatusData, cpSwapCoin []byte) error {
 		ss.swapAsset, ss.redeemAsset = ssd.SwapAsset, ssd.RedeemAsset
+		// Use the requirement in effect when the match was made. Matches
+		// recorded without one get the current requirement.
+		ss.swapConf = ssd.SwapConf
+		if ss.swapConf == 0 {
+			ss.swapConf = s.swapConf(ssd.Base, ssd.Quote, ssd.SwapAsset)
+		}
 
 		swapCoin := ssd.ContractCoinOut
 		if len(swapCoin) > 0 {
@@ -605,7 +650,7 @@ func (s *Swapper) restoreActiveSwaps(allowPartial bool) error {
 			swapConfs, err := swap.Confirmations(context.Background())
 			if err != nil {
 				log.Warnf("No swap confirmed time for %v: %v", swap, err)
-			} else if swapConfs >= int64(swapAsset.SwapConf) {
+			} else if swapConfs >= int64(ss.swapConf) {
 				// We don't record the time at which we saw the block that got
 				// the swap to SwapConf, so give the user extra time.
 				ss.swapConfirmed = time.Now().UTC()
@@ -712,6 +757,8 @@ func (s *Swapper) restoreActiveSwaps(allowPartial bool) error {
 		}
 
 		makerStatus := &swapStatusData{
+			Base:            sd.Base,
+			Quote:           sd.Quote,
 			SwapAsset:       makerSwapAsset,
 			RedeemAsset:     makerRedeemAsset,
 			SwapTime:        sd.SwapData.ContractATime,
@@ -719,8 +766,11 @@ func (s *Swapper) restoreActiveSwaps(allowPartial bool) error {
 			ContractScript:  sd.SwapData.ContractA,
 			RedeemTime:      sd.SwapData.RedeemATime,
 			RedeemCoinIn:    sd.SwapData.RedeemACoinID,
+			SwapConf:        sd.SwapData.SwapConfA,
 		}
 		takerStatus := &swapStatusData{
+			Base:            sd.Base,
+			Quote:           sd.Quote,
 			SwapAsset:       makerRedeemAsset,
 			RedeemAsset:     makerSwapAsset,
 			SwapTime:        sd.SwapData.ContractBTime,
@@ -728,6 +778,7 @@ func (s *Swapper) restoreActiveSwaps(allowPartial bool) error {
 			ContractScript:  sd.SwapData.ContractB,
 			RedeemTime:      sd.SwapData.RedeemBTime,
 			RedeemCoinIn:    sd.SwapData.RedeemBCoinID,
+			SwapConf:        sd.SwapData.SwapConfB,
 		}
 
 		if err := translateSwapStatus(mt.makerStatus, makerStatus, takerStatus.ContractCoinOut); err != nil {
@@ -961,7 +1012,7 @@ func (s *Swapper) tryConfirmSwap(ctx context.Context, status *swapStatus, confTi
 		return true
 	}
 
-	swapConf := s.coins[status.swapAsset].SwapConf // swapStatus exists, therefore swapAsset is in the map
+	swapConf := status.swapConf
 	if confs >= int64(swapConf) {
 		log.Debugf("Swap %v (%s) has reached %d confirmations (%d required)",
 			status.swap, dex.BipIDSymbol(status.swapAsset), confs, swapConf)
@@ -2364,9 +2415,41 @@ func matchNotifications(match *matchTracker) (makerMsg *msgjson.Match, takerMsg
 		}
 }
 
+// swapConf returns the number of confirmations required for swaps of the asset
+// on the market, which is the market's override if set, or the asset's
+// configured SwapConf. The asset must be in the coins map.
+func (s *Swapper) swapConf(base, quote, assetID uint32) uint32 {
+	s.swapConfMtx.RLock()
+	swapConf := s.swapConfs[[2]uint32{base, quote}][assetID]
+	s.swapConfMtx.RUnlock()
+	if swapConf > 0 {
+		return swapConf
+	}
+	return s.coins[assetID].SwapConf
+}
+
+// SetMarketSwapConf sets the number of confirmations required for swaps of the
+// asset on the market with the given base and quote assets. A swapConf of zero
+// removes the override. Only matches made after the change are affected.
+func (s *Swapper) SetMarketSwapConf(base, quote, assetID, swapConf uint32) {
+	mkt := [2]uint32{base, quote}
+	s.swapConfMtx.Lock()
+	defer s.swapConfMtx.Unlock()
+	if swapConf == 0 {
+		delete(s.swapConfs[mkt], assetID)
+		return
+	}
+	mktConfs := s.swapConfs[mkt]
+	if mktConfs == nil {
+		mktConfs = make(map[uint32]uint32, 1)
+		s.swapConfs[mkt] = mktConfs
+	}
+	mktConfs[assetID] = swapConf
+}
+
 // readMatches translates a slice of raw matches from the market manager into
 // a slice of matchTrackers.
-func readMatches(matchSets []*order.MatchSet) []*matchTracker {
+func (s *Swapper) readMatches(matchSets []*order.MatchSet) []*matchTracker {
 	// The initial capacity guess here is a minimum, but will avoid a few
 	// reallocs.
 	nowMs := unixMsNow()
@@ -2391,10 +2474,12 @@ func readMatches(matchSets []*order.MatchSet) []*matchTracker {
 				makerStatus: &swapStatus{
 					swapAsset:   makerSwapAsset,
 					redeemAsset: takerSwapAsset,
+					swapConf:    s.swapConf(base, quote, makerSwapAsset),
 				},
 				takerStatus: &swapStatus{
 					swapAsset:   takerSwapAsset,
 					redeemAsset: makerSwapAsset,
+					swapConf:    s.swapConf(base, quote, takerSwapAsset),
 				},
 			})
 		}
@@ -2444,7 +2529,7 @@ func (s *Swapper) Negotiate(matchSets []*order.MatchSet) {
 	s.LockOrdersCoins(swapOrders)
 
 	// Set up the matchTrackers, which includes a slice of Matches.
-	matches := readMatches(matchSets)
+	matches := s.readMatches(matchSets)
 
 	// Record the matches. If any DB updates fail, no swaps proceed. We could
 	// let the others proceed, but that could seem selective trickery to the
@@ -2466,6 +2551,16 @@ func (s *Swapper) Negotiate(matchSets []*order.MatchSet) {
 			// abortAll()
 			return
 		}
+		if match.Taker.Type() == order.CancelOrderType {
+			continue
+		}
+		// Record the swapConfs so that a restored swap keeps the
+		// requirement in effect now, even if the market's override changes.
+		err := s.storage.SaveSwapConfs(db.MatchID(match.Match), match.makerStatus.swapConf, match.takerStatus.swapConf)
+		if err != nil {
+			log.Errorf("SaveSwapConfs (match id=%v) failed: %v", match.ID(), err)
+			return
+		}
 	}
 
 	userMatches := make(map[account.AccountID][]*messageAcker)
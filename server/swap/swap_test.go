@@ -299,6 +299,9 @@ type TStorage struct {
 	fatalMtx sync.RWMutex
 	fatal    chan struct{}
 	fatalErr error
+
+	swapConfsMtx sync.Mutex
+	swapConfs    map[order.MatchID][2]uint32
 }
 
 func (ts *TStorage) LastErr() error {
@@ -332,6 +335,15 @@ func (ts *TStorage) InsertMatch(match *order.Match) error     { return nil }
 func (ts *TStorage) SwapData(mid db.MarketMatchID) (order.MatchStatus, *db.SwapData, error) {
 	return 0, nil, nil
 }
+func (ts *TStorage) SaveSwapConfs(mid db.MarketMatchID, swapConfA, swapConfB uint32) error {
+	ts.swapConfsMtx.Lock()
+	defer ts.swapConfsMtx.Unlock()
+	if ts.swapConfs == nil {
+		ts.swapConfs = make(map[order.MatchID][2]uint32)
+	}
+	ts.swapConfs[mid.MatchID] = [2]uint32{swapConfA, swapConfB}
+	return nil
+}
 func (ts *TStorage) SaveMatchAckSigA(mid db.MarketMatchID, sig []byte) error { return nil }
 func (ts *TStorage) SaveMatchAckSigB(mid db.MarketMatchID, sig []byte) error { return nil }
 
@@ -2220,6 +2232,43 @@ func TestBadParams(t *testing.T) {
 	ensureNilErr(rig.checkServerResponseFail(user, msgjson.AckCountError))
 }
 
+func TestMarketSwapConf(t *testing.T) {
+	set := tPerfectLimitLimit(uint64(1e8), uint64(1e8), true)
+	matchInfo := set.matchInfos[0]
+	rig, cleanup := tNewTestRig(matchInfo)
+	defer cleanup()
+
+	const abcSwapConf = 5
+	rig.swapper.SetMarketSwapConf(ABCID, XYZID, ABCID, abcSwapConf)
+	rig.swapper.Negotiate([]*order.MatchSet{set.matchSet})
+	match := rig.getTracker()
+	// Maker is selling, so the maker swaps ABC.
+	if match.makerStatus.swapConf != abcSwapConf {
+		t.Fatalf("wrong maker swapConf. wanted %d, got %d", abcSwapConf, match.makerStatus.swapConf)
+	}
+	if match.takerStatus.swapConf != rig.xyz.SwapConf {
+		t.Fatalf("wrong taker swapConf. wanted %d, got %d", rig.xyz.SwapConf, match.takerStatus.swapConf)
+	}
+	// The swapConfs are stored with the match.
+	rig.storage.swapConfsMtx.Lock()
+	swapConfs := rig.storage.swapConfs[match.ID()]
+	rig.storage.swapConfsMtx.Unlock()
+	if swapConfs != [2]uint32{abcSwapConf, rig.xyz.SwapConf} {
+		t.Fatalf("wrong stored swapConfs %v", swapConfs)
+	}
+
+	// Other markets are unaffected.
+	if swapConf := rig.swapper.swapConf(XYZID, ABCID, ABCID); swapConf != rig.abc.SwapConf {
+		t.Fatalf("override applied to wrong market. wanted %d, got %d", rig.abc.SwapConf, swapConf)
+	}
+
+	// Removing the override reverts to the asset's swapConf.
+	rig.swapper.SetMarketSwapConf(ABCID, XYZID, ABCID, 0)
+	if swapConf := rig.swapper.swapConf(ABCID, XYZID, ABCID); swapConf != rig.abc.SwapConf {
+		t.Fatalf("override not removed. wanted %d, got %d", rig.abc.SwapConf, swapConf)
+	}
+}
+
 func TestCancel(t *testing.T) {
 	set := tCancelPair()
 	matchInfo := set.matchInfos[0]